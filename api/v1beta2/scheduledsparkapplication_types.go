@@ -32,8 +32,23 @@ type ScheduledSparkApplicationSpec struct {
 	// INSERT ADDITIONAL SPEC FIELDS - desired state of cluster
 	// Important: Run "make generate" to regenerate code after modifying this file
 
-	// Schedule is a cron schedule on which the application should run.
+	// Schedule is a cron schedule on which the application should run. Besides standard cron expressions, the
+	// macros `@firstbusinessday`, `@lastbusinessday` and `@lastdayofmonth`, optionally followed by a time of the
+	// day, e.g. `@lastbusinessday 18:30`, run the application once a month on the given day.
 	Schedule string `json:"schedule"`
+	// AdditionalSchedules are further schedules in the same format as Schedule. A run is started whenever any of
+	// the schedules is due.
+	// +optional
+	AdditionalSchedules []string `json:"additionalSchedules,omitempty"`
+	// TimeZone is the IANA name of the time zone the schedules are evaluated in, e.g. `Europe/Berlin`.
+	// Defaults to the time zone of the controller.
+	// +optional
+	TimeZone *string `json:"timeZone,omitempty"`
+	// ExclusionCalendar is the name of a ConfigMap listing windows during which no run is started, e.g. maintenance
+	// windows or holidays. Every line of every value is either a date, e.g. `2024-12-25`, excluding the whole day,
+	// or an interval `<start>/<end>` whose ends are dates or RFC 3339 times. An end date is included in the interval.
+	// +optional
+	ExclusionCalendar *string `json:"exclusionCalendar,omitempty"`
 	// Template is a template from which SparkApplication instances can be created.
 	Template SparkApplicationSpec `json:"template"`
 	// Suspend is a flag telling the controller to suspend subsequent runs of the application if set to true.
@@ -19,7 +19,9 @@ package v1beta2
 import (
 	corev1 "k8s.io/api/core/v1"
 	networkingv1 "k8s.io/api/networking/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
 )
 
 // EDIT THIS FILE!  THIS IS SCAFFOLDING FOR YOU TO OWN!
@@ -31,14 +33,18 @@ func init() {
 
 // SparkApplicationSpec defines the desired state of SparkApplication
 // It carries every pieces of information a spark-submit command takes and recognizes.
+// +kubebuilder:validation:XValidation:rule="has(self.templateRef) || (has(self.type) && has(self.sparkVersion) && has(self.driver) && has(self.executor))",message="type, sparkVersion, driver and executor are required unless templateRef is set"
 type SparkApplicationSpec struct {
 	// INSERT ADDITIONAL SPEC FIELDS - desired state of cluster
 	// Important: Run "make generate" to regenerate code after modifying this file
 
-	// Type tells the type of the Spark application.
+	// Type tells the type of the Spark application. Required unless set by the template referenced by TemplateRef.
 	// +kubebuilder:validation:Enum={Java,Python,Scala,R}
+	// +optional
 	Type SparkApplicationType `json:"type"`
-	// SparkVersion is the version of Spark the application uses.
+	// SparkVersion is the version of Spark the application uses. Required unless set by the template referenced
+	// by TemplateRef.
+	// +optional
 	SparkVersion string `json:"sparkVersion"`
 	// Mode is the deployment mode of the Spark application.
 	// +kubebuilder:validation:Enum={cluster,client}
@@ -47,6 +53,14 @@ type SparkApplicationSpec struct {
 	// It maps to the command-line flag "--proxy-user" in spark-submit.
 	// +optional
 	ProxyUser *string `json:"proxyUser,omitempty"`
+	// SubmissionEngine is the engine submitting the application. `SparkSubmit` runs spark-submit in the operator,
+	// `Job` runs spark-submit in a Job in the namespace of the application, retaining its logs in the pod of the Job,
+	// while `Native` creates the driver pod, its Service and ConfigMap directly without starting a JVM. The `Job` and
+	// `Native` engines only support the cluster mode, and the `Native` engine only supports dependencies the driver
+	// can fetch itself, i.e. no local files to upload. Defaults to the default submission engine of the operator.
+	// +kubebuilder:validation:Enum={SparkSubmit,Job,Native}
+	// +optional
+	SubmissionEngine *SubmissionEngine `json:"submissionEngine,omitempty"`
 	// Image is the container image for the driver, executor, and init-container. Any custom container images for the
 	// driver, executor, or init-container takes precedence over this.
 	// +optional
@@ -62,7 +76,15 @@ type SparkApplicationSpec struct {
 	// +optional
 	MainClass *string `json:"mainClass,omitempty"`
 	// MainFile is the path to a bundled JAR, Python, or R file of the application.
-	MainApplicationFile *string `json:"mainApplicationFile"`
+	// A file embedded in a ConfigMap can be referenced in the form of `configmap://<configmap-name>/<key>`,
+	// in which case the ConfigMap is mounted into the driver pod.
+	// Mutually exclusive with MainApplicationSource.
+	// +optional
+	MainApplicationFile *string `json:"mainApplicationFile,omitempty"`
+	// MainApplicationSource specifies the source code of the main application inline, as an alternative to
+	// MainApplicationFile for short Python or R scripts.
+	// +optional
+	MainApplicationSource *MainApplicationSource `json:"mainApplicationSource,omitempty"`
 	// Arguments is a list of arguments to be passed to the application.
 	// +optional
 	Arguments []string `json:"arguments,omitempty"`
@@ -83,12 +105,28 @@ type SparkApplicationSpec struct {
 	// The controller will add environment variable HADOOP_CONF_DIR to the path where the ConfigMap is mounted to.
 	// +optional
 	HadoopConfigMap *string `json:"hadoopConfigMap,omitempty"`
+	// MountConfProperties specifies whether the SparkConf and HadoopConf properties are written into a ConfigMap
+	// owned by the application and mounted on both the driver and executors at /etc/spark-operator/conf, as the
+	// files spark.properties and hadoop.properties. The ConfigMap is readable by anyone allowed to read ConfigMaps
+	// in the namespace, so credentials should not be passed as properties when this is enabled.
+	// Defaults to false.
+	// +optional
+	MountConfProperties *bool `json:"mountConfProperties,omitempty"`
+	// Kerberos configures Kerberos authentication of the driver and executors.
+	// +optional
+	Kerberos *KerberosSpec `json:"kerberos,omitempty"`
 	// Volumes is the list of Kubernetes volumes that can be mounted by the driver and/or executors.
 	// +optional
 	Volumes []corev1.Volume `json:"volumes,omitempty"`
-	// Driver is the driver specification.
+	// LocalDirPolicy configures the volumes the operator provisions as Spark local directories (`spark.local.dir`)
+	// used for shuffle and spill data. It cannot be combined with volumes whose names start with `spark-local-dir-`.
+	// +optional
+	LocalDirPolicy *LocalDirPolicy `json:"localDirPolicy,omitempty"`
+	// Driver is the driver specification. Required unless set by the template referenced by TemplateRef.
+	// +optional
 	Driver DriverSpec `json:"driver"`
-	// Executor is the executor specification.
+	// Executor is the executor specification. Required unless set by the template referenced by TemplateRef.
+	// +optional
 	Executor ExecutorSpec `json:"executor"`
 	// Deps captures all possible types of dependencies of a Spark application.
 	// +optional
@@ -126,8 +164,10 @@ type SparkApplicationSpec struct {
 	// TimeToLiveSeconds defines the Time-To-Live (TTL) duration in seconds for this SparkApplication
 	// after its termination.
 	// The SparkApplication object will be garbage collected if the current time is more than the
-	// TimeToLiveSeconds since its termination.
+	// TimeToLiveSeconds since its termination, together with the driver pod, services, ConfigMaps
+	// and ingresses created for it.
 	// +optional
+	// +kubebuilder:validation:Minimum=0
 	TimeToLiveSeconds *int64 `json:"timeToLiveSeconds,omitempty"`
 	// BatchSchedulerOptions provides fine-grained control on how to batch scheduling.
 	// +optional
@@ -142,6 +182,49 @@ type SparkApplicationSpec struct {
 	// scheduler backend since Spark 3.0.
 	// +optional
 	DynamicAllocation *DynamicAllocation `json:"dynamicAllocation,omitempty"`
+	// TemplateRef references a SparkApplicationTemplate in the same namespace whose spec is used as the base
+	// of this application. Fields set in this application override the fields set in the template.
+	// +optional
+	TemplateRef *SparkApplicationTemplateReference `json:"templateRef,omitempty"`
+	// NetworkPorts configures the ports the driver and executors listen on, which take precedence over the
+	// corresponding properties in SparkConf.
+	// +optional
+	NetworkPorts *NetworkPorts `json:"networkPorts,omitempty"`
+	// ImagePrefetch configures pulling the executor image onto the candidate nodes of the executors
+	// before the application is submitted. Requires the operator to be started with image prefetch enabled.
+	// +optional
+	ImagePrefetch *ImagePrefetchSpec `json:"imagePrefetch,omitempty"`
+	// SecretRotation configures restarting the executors in batches whenever the contents of the Secrets
+	// mounted into them change, so that long-running applications pick up rotated credentials. The driver is
+	// not restarted, so the authentication secret generated by the operator, which the driver and executors
+	// must share, is only rotated for new runs.
+	// +optional
+	SecretRotation *SecretRotationSpec `json:"secretRotation,omitempty"`
+	// PostRunActions are executed by the operator in order once the application reaches a terminal state,
+	// before it is deleted when TimeToLiveSeconds expires. The result of each action is recorded in the status.
+	// +optional
+	// +listType=map
+	// +listMapKey=name
+	PostRunActions []PostRunAction `json:"postRunActions,omitempty"`
+	// WaitFor holds the submission of the application until the Kubernetes resources it depends on are ready,
+	// e.g. a ConfigMap rendered by another controller or a Deployment of a service the application connects to.
+	// +optional
+	WaitFor *WaitForSpec `json:"waitFor,omitempty"`
+	// SLA defines service level objectives of the application. The operator emits a Warning event and increments
+	// a metric whenever one of them is violated, so that late applications can be alerted on.
+	// +optional
+	SLA *SLASpec `json:"sla,omitempty"`
+	// Outputs are the outputs the application produces. The operator verifies that they exist once the application
+	// completes and records them in the status, so that downstream pipelines can discover them from the application.
+	// +optional
+	// +listType=map
+	// +listMapKey=name
+	Outputs []Output `json:"outputs,omitempty"`
+	// DisableDefaults lists the kinds of defaults the operator injects into the driver and executor pods, e.g.
+	// node selectors or sidecars configured for the cluster, which are not injected into the pods of this application.
+	// +optional
+	// +listType=set
+	DisableDefaults []InjectedDefault `json:"disableDefaults,omitempty"`
 }
 
 // SparkApplicationStatus defines the observed state of SparkApplication
@@ -165,12 +248,64 @@ type SparkApplicationStatus struct {
 	AppState ApplicationState `json:"applicationState,omitempty"`
 	// ExecutorState records the state of executors by executor Pod names.
 	ExecutorState map[string]ExecutorState `json:"executorState,omitempty"`
+	// ExecutorPlacement records the node and zone of executors by executor Pod names. It is only recorded if
+	// executor placement tracking is enabled on the operator.
+	// +optional
+	ExecutorPlacement map[string]ExecutorPlacement `json:"executorPlacement,omitempty"`
 	// ExecutionAttempts is the total number of attempts to run a submitted application to completion.
 	// Incremented upon each attempted run of the application and reset upon invalidation.
 	ExecutionAttempts int32 `json:"executionAttempts,omitempty"`
 	// SubmissionAttempts is the total number of attempts to submit an application to run.
 	// Incremented upon each attempted submission of the application and reset upon invalidation and rerun.
 	SubmissionAttempts int32 `json:"submissionAttempts,omitempty"`
+	// SparkSubmitCommand is the spark-submit command of the last submission attempt, with the values of configuration
+	// properties whose key or value matches `spark.redaction.regex` and a matching `--proxy-user` redacted.
+	// +optional
+	SparkSubmitCommand string `json:"sparkSubmitCommand,omitempty"`
+	// ConfigHash is the hash of the normalized configuration of the last submission, which changes whenever the
+	// spec or the effective Spark configuration of the application changes between runs.
+	// +optional
+	ConfigHash string `json:"configHash,omitempty"`
+	// ObservedGeneration is the generation of the SparkApplication observed by the controller.
+	// +optional
+	ObservedGeneration int64 `json:"observedGeneration,omitempty"`
+	// Conditions are the latest observations of the state of the application.
+	// +optional
+	// +listType=map
+	// +listMapKey=type
+	Conditions []metav1.Condition `json:"conditions,omitempty"`
+	// SecretRotation tracks the rollout of rotated Secrets to the executors.
+	// +optional
+	SecretRotation *SecretRotationStatus `json:"secretRotation,omitempty"`
+	// PostRunActions records the results of the post-run actions executed after the current run terminated.
+	// +optional
+	// +listType=map
+	// +listMapKey=name
+	PostRunActions []PostRunActionStatus `json:"postRunActions,omitempty"`
+	// SLAViolations records the violations of the SLA of the application. Violations of MaxPendingDuration and
+	// MaxRunDuration are cleared upon rerun and invalidation.
+	// +optional
+	// +listType=map
+	// +listMapKey=type
+	SLAViolations []SLAViolation `json:"slaViolations,omitempty"`
+	// DriverProgress records the progress of the current run reported by the driver. It is only recorded if the
+	// operator is configured to scrape the drivers.
+	// +optional
+	DriverProgress *DriverProgress `json:"driverProgress,omitempty"`
+	// Outputs records the outputs of the current run verified once it completed.
+	// +optional
+	// +listType=map
+	// +listMapKey=name
+	Outputs []OutputStatus `json:"outputs,omitempty"`
+	// InjectedDefaults records the kinds of operator defaults applied to and suppressed from the driver pod of the
+	// current run.
+	// +optional
+	InjectedDefaults *InjectedDefaultsStatus `json:"injectedDefaults,omitempty"`
+	// ExecutorQuotaShortfall records the executors of the current run missing because of an exhausted
+	// ResourceQuota. It is only recorded for applications with an executor quota failure policy if the operator is
+	// configured to detect executor quota failures.
+	// +optional
+	ExecutorQuotaShortfall *ExecutorQuotaShortfall `json:"executorQuotaShortfall,omitempty"`
 }
 
 // +kubebuilder:object:root=true
@@ -222,6 +357,16 @@ const (
 	DeployModeInClusterClient DeployMode = "in-cluster-client"
 )
 
+// SubmissionEngine describes the engine submitting a Spark application.
+type SubmissionEngine string
+
+// Different engines submitting Spark applications.
+const (
+	SubmissionEngineSparkSubmit SubmissionEngine = "SparkSubmit"
+	SubmissionEngineJob         SubmissionEngine = "Job"
+	SubmissionEngineNative      SubmissionEngine = "Native"
+)
+
 // RestartPolicy is the policy of if and in which conditions the controller should restart a terminated application.
 // This completely defines actions to be taken on any kind of Failures during an application run.
 type RestartPolicy struct {
@@ -250,6 +395,31 @@ type RestartPolicy struct {
 	// +kubebuilder:validation:Minimum=1
 	// +optional
 	OnFailureRetryInterval *int64 `json:"onFailureRetryInterval,omitempty"`
+
+	// Backoff configures an exponential backoff between retries of failed submissions and failed runs. It replaces
+	// the linear backoff derived from OnSubmissionFailureRetryInterval and OnFailureRetryInterval if set.
+	// +optional
+	Backoff *RetryBackoff `json:"backoff,omitempty"`
+}
+
+// RetryBackoff configures an exponential backoff between retries. The n-th retry waits
+// InitialIntervalSeconds * Multiplier^(n-1) seconds, capped at MaxIntervalSeconds.
+type RetryBackoff struct {
+	// InitialIntervalSeconds is the interval in seconds before the first retry.
+	// +kubebuilder:validation:Minimum=1
+	InitialIntervalSeconds int64 `json:"initialIntervalSeconds"`
+	// Multiplier is the factor the interval grows by with every retry, e.g. `1.5`. Defaults to `2`.
+	// +optional
+	Multiplier *string `json:"multiplier,omitempty"`
+	// MaxIntervalSeconds caps the interval in seconds between two retries.
+	// +kubebuilder:validation:Minimum=1
+	// +optional
+	MaxIntervalSeconds *int64 `json:"maxIntervalSeconds,omitempty"`
+	// MaxDurationSeconds bounds the total time in seconds spent waiting between retries. No further retry is
+	// attempted once the next retry would exceed it.
+	// +kubebuilder:validation:Minimum=1
+	// +optional
+	MaxDurationSeconds *int64 `json:"maxDurationSeconds,omitempty"`
 }
 
 type RestartPolicyType string
@@ -260,18 +430,34 @@ const (
 	RestartPolicyAlways    RestartPolicyType = "Always"
 )
 
+// MainApplicationSource specifies the source code of the main application.
+type MainApplicationSource struct {
+	// Inline is the source code of the main application. The operator writes it into a ConfigMap owned by the
+	// SparkApplication, which is mounted into the driver pod and passed to spark-submit as the main application file.
+	// +optional
+	Inline *string `json:"inline,omitempty"`
+}
+
 // BatchSchedulerConfiguration used to configure how to batch scheduling Spark Application
 type BatchSchedulerConfiguration struct {
 	// Queue stands for the resource queue which the application belongs to, it's being used in Volcano batch scheduler.
 	// +optional
 	Queue *string `json:"queue,omitempty"`
 	// PriorityClassName stands for the name of k8s PriorityClass resource, it's being used in Volcano batch scheduler.
+	// Defaults to the PriorityClass of the driver.
 	// +optional
 	PriorityClassName *string `json:"priorityClassName,omitempty"`
 	// Resources stands for the resource list custom request for. Usually it is used to define the lower-bound limit.
-	// If specified, volcano scheduler will consider it as the resources requested.
+	// If specified, volcano scheduler and kube-schedulers with the coscheduling plugin will consider it as the
+	// resources requested. Otherwise, volcano scheduler considers the requests of the driver in cluster mode and of
+	// the initial executors, including the memory overhead added by Spark.
 	// +optional
 	Resources corev1.ResourceList `json:"resources,omitempty"`
+	// TTLSecondsAfterFinished is the number of seconds the Volcano PodGroup of the application is kept after the
+	// application terminates. Defaults to deleting the PodGroup as soon as the application terminates.
+	// +kubebuilder:validation:Minimum=0
+	// +optional
+	TTLSecondsAfterFinished *int32 `json:"ttlSecondsAfterFinished,omitempty"`
 }
 
 // SparkUIConfiguration is for driver UI specific configuration parameters.
@@ -300,6 +486,47 @@ type SparkUIConfiguration struct {
 	// TlsHosts is useful If we need to declare SSL certificates to the ingress object
 	// +optional
 	IngressTLS []networkingv1.IngressTLS `json:"ingressTLS,omitempty"`
+	// Auth configures authentication in front of the web UI.
+	// +optional
+	Auth *SparkUIAuth `json:"auth,omitempty"`
+}
+
+// SparkUIAuth configures authentication of the web UI. Exactly one of BasicAuth and OAuth2Proxy must be set.
+type SparkUIAuth struct {
+	// BasicAuth enables HTTP basic authentication on the ingresses of the driver, which must be served by ingress-nginx.
+	// +optional
+	BasicAuth *SparkUIBasicAuth `json:"basicAuth,omitempty"`
+	// OAuth2Proxy runs oauth2-proxy as a sidecar of the driver in front of the web UI, and routes the web UI
+	// service to it. Requires the webhook to be enabled.
+	// +optional
+	OAuth2Proxy *SparkUIOAuth2Proxy `json:"oauth2Proxy,omitempty"`
+}
+
+// SparkUIBasicAuth configures HTTP basic authentication of the web UI.
+type SparkUIBasicAuth struct {
+	// SecretName is the name of a Secret holding the `username` and `password` of the user allowed to access the
+	// web UI. The operator creates the htpasswd Secret read by ingress-nginx from it.
+	SecretName string `json:"secretName"`
+	// Realm is the realm shown in the authentication prompt. Defaults to "Spark UI".
+	// +optional
+	Realm *string `json:"realm,omitempty"`
+}
+
+// SparkUIOAuth2Proxy configures the oauth2-proxy sidecar authenticating users of the web UI.
+type SparkUIOAuth2Proxy struct {
+	// Image is the container image of oauth2-proxy. Defaults to quay.io/oauth2-proxy/oauth2-proxy:v7.7.1.
+	// +optional
+	Image *string `json:"image,omitempty"`
+	// Port is the port oauth2-proxy listens on. Defaults to 4180.
+	// +optional
+	// +kubebuilder:validation:Minimum=1
+	// +kubebuilder:validation:Maximum=65535
+	Port *int32 `json:"port,omitempty"`
+	// SecretName is the name of a Secret holding the `client-id`, `client-secret` and `cookie-secret` of oauth2-proxy.
+	SecretName string `json:"secretName"`
+	// Args are additional arguments of oauth2-proxy, e.g. `--provider=oidc` and `--oidc-issuer-url`.
+	// +optional
+	Args []string `json:"args,omitempty"`
 }
 
 // DriverIngressConfiguration is for driver ingress specific configuration parameters.
@@ -344,6 +571,32 @@ const (
 	ApplicationStateSucceeding       ApplicationStateType = "SUCCEEDING"
 	ApplicationStateFailing          ApplicationStateType = "FAILING"
 	ApplicationStateUnknown          ApplicationStateType = "UNKNOWN"
+	// ApplicationStateWaitingForDependencies is the state of applications held before submission until the Secrets,
+	// ConfigMaps and service accounts they reference exist.
+	ApplicationStateWaitingForDependencies ApplicationStateType = "WAITING_FOR_DEPENDENCIES"
+)
+
+// SparkApplicationConditionType is the type of a condition of a SparkApplication.
+type SparkApplicationConditionType string
+
+// Different condition types a SparkApplication may have. Their names follow the conditions of batch Jobs, so that
+// tools waiting on Kubernetes workloads, e.g. `kubectl wait --for=condition=Complete` or Argo Workflows resource
+// templates, can track SparkApplications without application-specific success and failure conditions.
+const (
+	// SparkApplicationConditionSubmitted is true once the application was submitted successfully.
+	SparkApplicationConditionSubmitted SparkApplicationConditionType = "Submitted"
+	// SparkApplicationConditionRunning is true while the driver of the application is running.
+	SparkApplicationConditionRunning SparkApplicationConditionType = "Running"
+	// SparkApplicationConditionComplete is true once the application completed successfully.
+	SparkApplicationConditionComplete SparkApplicationConditionType = "Complete"
+	// SparkApplicationConditionFailed is true once the application failed and will not be retried.
+	SparkApplicationConditionFailed SparkApplicationConditionType = "Failed"
+	// SparkApplicationConditionDependenciesReady tells whether the resources listed in `spec.waitFor` are ready.
+	// It is only set if the application waits for any resources.
+	SparkApplicationConditionDependenciesReady SparkApplicationConditionType = "DependenciesReady"
+	// SparkApplicationConditionDriverPodAdmitted tells whether a server-side dry run of creating the driver pod passed
+	// admission, e.g. PodSecurity or policy engines. It is only set if driver pod validation is enabled in the operator.
+	SparkApplicationConditionDriverPodAdmitted SparkApplicationConditionType = "DriverPodAdmitted"
 )
 
 // ApplicationState tells the current state of the application and an error message in case of failures.
@@ -376,6 +629,16 @@ const (
 	ExecutorStateUnknown   ExecutorState = "UNKNOWN"
 )
 
+// ExecutorPlacement tells the failure domain an executor is placed in.
+type ExecutorPlacement struct {
+	// NodeName is the name of the node the executor Pod is scheduled to.
+	// +optional
+	NodeName string `json:"nodeName,omitempty"`
+	// Zone is the zone of the node, as given by its `topology.kubernetes.io/zone` label.
+	// +optional
+	Zone string `json:"zone,omitempty"`
+}
+
 // Dependencies specifies all possible types of dependencies of a Spark application.
 type Dependencies struct {
 	// Jars is a list of JAR files the Spark application depends on.
@@ -404,6 +667,23 @@ type Dependencies struct {
 	// Archives is a list of archives to be extracted into the working directory of each executor.
 	// +optional
 	Archives []string `json:"archives,omitempty"`
+	// OCIArtifacts configures how dependencies and the main application file referenced in the form of
+	// `oci://<registry>/<repository>:<tag>/<file>` are pulled into the driver and executor pods.
+	// +optional
+	OCIArtifacts *OCIArtifactsSpec `json:"ociArtifacts,omitempty"`
+}
+
+// OCIArtifactsSpec configures pulling OCI artifacts. Each artifact is pulled by an init container into a volume
+// shared with the Spark container, and the referenced files are passed to spark-submit as local files.
+type OCIArtifactsSpec struct {
+	// RegistrySecret is the name of a Secret of type `kubernetes.io/dockerconfigjson` holding the credentials
+	// used to pull the artifacts. Artifacts are pulled anonymously if not set.
+	// +optional
+	RegistrySecret *string `json:"registrySecret,omitempty"`
+	// Image is the container image used to pull the artifacts. The image must have `oras` as its entrypoint.
+	// Defaults to `ghcr.io/oras-project/oras:v1.2.0`.
+	// +optional
+	Image *string `json:"image,omitempty"`
 }
 
 // SparkPodSpec defines common things that can be customized for a Spark driver or executor pod.
@@ -417,6 +697,11 @@ type SparkPodSpec struct {
 	// +kubebuilder:validation:Type:=object
 	// +kubebuilder:pruning:PreserveUnknownFields
 	Template *corev1.PodTemplateSpec `json:"template,omitempty"`
+	// TemplateConfigMap references the key of a ConfigMap in the namespace of the application holding a pod
+	// template, i.e. a Pod manifest, which is used like Template. The ConfigMap is read upon every submission.
+	// Must not be set together with Template. Spark version >= 3.0.0 is required.
+	// +optional
+	TemplateConfigMap *NameKey `json:"templateConfigMap,omitempty"`
 	// Cores maps to `spark.driver.cores` or `spark.executor.cores` for the driver and executors, respectively.
 	// +optional
 	// +kubebuilder:validation:Minimum=1
@@ -433,6 +718,13 @@ type SparkPodSpec struct {
 	// GPU specifies GPU requirement for the pod.
 	// +optional
 	GPU *GPUSpec `json:"gpu,omitempty"`
+	// EphemeralStorage is the amount of local ephemeral storage requested by the main Spark container, e.g., for
+	// shuffle and spill data, so that the pod is scheduled on a node with enough disk and not evicted under disk pressure.
+	// +optional
+	EphemeralStorage *string `json:"ephemeralStorage,omitempty"`
+	// EphemeralStorageLimit is the limit of the local ephemeral storage of the main Spark container.
+	// +optional
+	EphemeralStorageLimit *string `json:"ephemeralStorageLimit,omitempty"`
 	// Image is the container image to use. Overrides Spec.Image if set.
 	// +optional
 	Image *string `json:"image,omitempty"`
@@ -474,16 +766,41 @@ type SparkPodSpec struct {
 	// PodSecurityContext specifies the PodSecurityContext to apply.
 	// +optional
 	PodSecurityContext *corev1.PodSecurityContext `json:"podSecurityContext,omitempty"`
-	// SecurityContext specifies the container's SecurityContext to apply.
+	// SecurityContext specifies the container's SecurityContext to apply. It also applies to the init and sidecar
+	// containers the operator injects, e.g. for Kerberos or OCI artifacts, unless they set their own.
 	// +optional
 	SecurityContext *corev1.SecurityContext `json:"securityContext,omitempty"`
+	// LivenessProbe is the liveness probe of the main Spark container, e.g. to restart a stalled streaming driver.
+	// +optional
+	LivenessProbe *corev1.Probe `json:"livenessProbe,omitempty"`
+	// ReadinessProbe is the readiness probe of the main Spark container.
+	// +optional
+	ReadinessProbe *corev1.Probe `json:"readinessProbe,omitempty"`
+	// StartupProbe is the startup probe of the main Spark container, which holds off the other probes until the
+	// JVM is up.
+	// +optional
+	StartupProbe *corev1.Probe `json:"startupProbe,omitempty"`
+	// Lifecycle specifies the postStart and preStop hooks of the main Spark container, e.g. to flush logs and
+	// metrics or to deregister from a service mesh before the container terminates.
+	// +optional
+	Lifecycle *corev1.Lifecycle `json:"lifecycle,omitempty"`
 	// SchedulerName specifies the scheduler that will be used for scheduling
 	// +optional
 	SchedulerName *string `json:"schedulerName,omitempty"`
+	// RuntimeClassName is the name of the RuntimeClass used to run the pod, e.g. to run it under gVisor or Kata
+	// Containers for isolation.
+	// +optional
+	RuntimeClassName *string `json:"runtimeClassName,omitempty"`
 	// Sidecars is a list of sidecar containers that run along side the main Spark container.
 	// +optional
 	Sidecars []corev1.Container `json:"sidecars,omitempty"`
-	// InitContainers is a list of init-containers that run to completion before the main Spark container.
+	// NativeSidecars specifies whether to run the Sidecars as Kubernetes native sidecar containers, i.e. init
+	// containers with restart policy `Always`, which are terminated once the main Spark container has exited, so
+	// that they do not keep the pod running. Requires Kubernetes 1.29 or higher.
+	// +optional
+	NativeSidecars *bool `json:"nativeSidecars,omitempty"`
+	// InitContainers is a list of init-containers that run to completion before the main Spark container,
+	// e.g., to prefetch data before each executor starts. They are added to the pod template if one is set.
 	// +optional
 	InitContainers []corev1.Container `json:"initContainers,omitempty"`
 	// HostNetwork indicates whether to request host networking for the pod or not.
@@ -496,6 +813,15 @@ type SparkPodSpec struct {
 	// DnsConfig dns settings for the pod, following the Kubernetes specifications.
 	// +optional
 	DNSConfig *corev1.PodDNSConfig `json:"dnsConfig,omitempty"`
+	// DNSPolicy is the DNS policy of the pod, following the Kubernetes specifications. It takes precedence over
+	// the policy set for hostNetwork pods. The `None` policy requires dnsConfig to specify nameservers.
+	// +kubebuilder:validation:Enum={ClusterFirstWithHostNet,ClusterFirst,Default,None}
+	// +optional
+	DNSPolicy *corev1.DNSPolicy `json:"dnsPolicy,omitempty"`
+	// DNSSearchDomains are DNS search domains appended to the searches of DnsConfig, e.g., the domain of the
+	// driver headless service to speed up the resolution of the driver by the executors.
+	// +optional
+	DNSSearchDomains []string `json:"dnsSearchDomains,omitempty"`
 	// Termination grace period seconds for the pod
 	// +optional
 	TerminationGracePeriodSeconds *int64 `json:"terminationGracePeriodSeconds,omitempty"`
@@ -508,6 +834,10 @@ type SparkPodSpec struct {
 	// ShareProcessNamespace settings for the pod, following the Kubernetes specifications.
 	// +optional
 	ShareProcessNamespace *bool `json:"shareProcessNamespace,omitempty"`
+	// SchedulingGates is an opaque list of values that if specified will block scheduling the pod.
+	// The gates are expected to be removed by an external controller once the pod is allowed to be scheduled.
+	// +optional
+	SchedulingGates []corev1.PodSchedulingGate `json:"schedulingGates,omitempty"`
 }
 
 // DriverSpec is specification of the driver.
@@ -528,9 +858,6 @@ type DriverSpec struct {
 	// GC settings or other logging.
 	// +optional
 	JavaOptions *string `json:"javaOptions,omitempty"`
-	// Lifecycle for running preStop or postStart commands
-	// +optional
-	Lifecycle *corev1.Lifecycle `json:"lifecycle,omitempty"`
 	// KubernetesMaster is the URL of the Kubernetes master used by the driver to manage executor pods and
 	// other Kubernetes resources. Default to https://kubernetes.default.svc.
 	// +optional
@@ -543,12 +870,24 @@ type DriverSpec struct {
 	// executors to connect to the driver.
 	// +optional
 	ServiceLabels map[string]string `json:"serviceLabels,omitempty"`
+	// ServicePublishNotReadyAddresses sets publishNotReadyAddresses on the Kubernetes headless service used by
+	// executors to connect to the driver, so that the driver address is resolvable before the driver is ready,
+	// e.g., while it restarts.
+	// +optional
+	ServicePublishNotReadyAddresses *bool `json:"servicePublishNotReadyAddresses,omitempty"`
 	// Ports settings for the pods, following the Kubernetes specifications.
 	// +optional
 	Ports []Port `json:"ports,omitempty"`
-	// PriorityClassName is the name of the PriorityClass for the driver pod.
+	// PriorityClassName is the name of the PriorityClass for the driver pod. It is also the PriorityClass of the
+	// pod group created by batch schedulers unless batchSchedulerOptions.priorityClassName is set.
 	// +optional
 	PriorityClassName *string `json:"priorityClassName,omitempty"`
+	// TerminateSidecars specifies whether to delete the driver pod once the Spark driver container has terminated
+	// while sidecar containers, e.g. istio-proxy or log shippers, keep it running. The application completes based
+	// on the state of the Spark driver container either way. Logs of the driver container can no longer be read from
+	// the pod once it is deleted.
+	// +optional
+	TerminateSidecars *bool `json:"terminateSidecars,omitempty"`
 }
 
 // ExecutorSpec is specification of the executor.
@@ -566,9 +905,6 @@ type ExecutorSpec struct {
 	// GC settings or other logging.
 	// +optional
 	JavaOptions *string `json:"javaOptions,omitempty"`
-	// Lifecycle for running preStop or postStart commands
-	// +optional
-	Lifecycle *corev1.Lifecycle `json:"lifecycle,omitempty"`
 	// DeleteOnTermination specify whether executor pods should be deleted in case of failure or normal termination.
 	// Maps to `spark.kubernetes.executor.deleteOnTermination` that is available since Spark 3.0.
 	// +optional
@@ -579,8 +915,143 @@ type ExecutorSpec struct {
 	// PriorityClassName is the name of the PriorityClass for the executor pod.
 	// +optional
 	PriorityClassName *string `json:"priorityClassName,omitempty"`
+	// TopologyPolicy controls how executors of the application are placed across topology domains.
+	// `zone-affinity` prefers co-locating executors of the same application in the same zone to reduce
+	// cross-zone shuffle traffic.
+	// +kubebuilder:validation:Enum={zone-affinity}
+	// +optional
+	TopologyPolicy *TopologyPolicy `json:"topologyPolicy,omitempty"`
+	// PinToDriverZone requires executors to be scheduled into the same zone as the driver pod.
+	// Only takes effect when TopologyPolicy is set to `zone-affinity`.
+	// +optional
+	PinToDriverZone *bool `json:"pinToDriverZone,omitempty"`
+	// MaxPerNode bounds the number of executors of the application scheduled onto the same node. A value of 1
+	// is enforced with a required pod anti-affinity, larger values with a topology spread constraint across nodes
+	// whose maximum skew is MaxPerNode.
+	// +optional
+	// +kubebuilder:validation:Minimum=1
+	MaxPerNode *int32 `json:"maxPerNode,omitempty"`
+	// DisruptionBudget configures a PodDisruptionBudget limiting the number of executors voluntarily disrupted
+	// at once, e.g. by node consolidation. Requires the operator to be started with Karpenter disruption
+	// protection enabled.
+	// +optional
+	DisruptionBudget *ExecutorDisruptionBudget `json:"disruptionBudget,omitempty"`
+	// LaunchRateLimit paces the creation of executor pods by the driver, so that applications requesting many
+	// executors do not flood the API server. Properties set in SparkConf take precedence.
+	// +optional
+	LaunchRateLimit *ExecutorLaunchRateLimit `json:"launchRateLimit,omitempty"`
+	// QuotaFailurePolicy configures how the application handles executor pods the driver fails to create because
+	// a ResourceQuota of the namespace is exhausted. Left to the default behavior of the Spark version if unset.
+	// +optional
+	QuotaFailurePolicy *ExecutorQuotaFailurePolicy `json:"quotaFailurePolicy,omitempty"`
 }
 
+// ExecutorDisruptionBudget contains configuration options for the PodDisruptionBudget of the executors.
+type ExecutorDisruptionBudget struct {
+	// MaxUnavailable is the maximum number or percentage of executors that can be unavailable after an eviction.
+	// +optional
+	MaxUnavailable *intstr.IntOrString `json:"maxUnavailable,omitempty"`
+}
+
+// ExecutorLaunchRateLimit contains configuration options for pacing the creation of executor pods.
+type ExecutorLaunchRateLimit struct {
+	// BatchSize is the number of executor pods created in each round of executor allocation.
+	// Maps to `spark.kubernetes.allocation.batch.size`.
+	// +optional
+	// +kubebuilder:validation:Minimum=1
+	BatchSize *int32 `json:"batchSize,omitempty"`
+	// BatchDelay is the time to wait between rounds of executor allocation, e.g. `2s`.
+	// Maps to `spark.kubernetes.allocation.batch.delay`.
+	// +optional
+	BatchDelay *string `json:"batchDelay,omitempty"`
+}
+
+// ExecutorQuotaFailurePolicy contains configuration options for handling executor pods rejected by a ResourceQuota.
+type ExecutorQuotaFailurePolicy struct {
+	// Action is the action taken when executors cannot be created because of a ResourceQuota.
+	// `Continue` schedules tasks on the executors created so far right away, `Wait` holds off scheduling tasks until
+	// all requested executors registered, and `Fail` fails the application once the operator detects the shortfall.
+	// +kubebuilder:validation:Enum={Continue,Wait,Fail}
+	Action ExecutorQuotaFailureAction `json:"action"`
+	// WaitTimeout is the maximum time to wait for all requested executors with the `Wait` action before scheduling
+	// tasks on the executors created so far, e.g. `10m`. Waits indefinitely if unset.
+	// Maps to `spark.scheduler.maxRegisteredResourcesWaitingTime`.
+	// +optional
+	WaitTimeout *string `json:"waitTimeout,omitempty"`
+}
+
+// ExecutorQuotaFailureAction is the action taken when executors cannot be created because of a ResourceQuota.
+type ExecutorQuotaFailureAction string
+
+// Different actions taken when executors cannot be created because of a ResourceQuota.
+const (
+	ExecutorQuotaFailureActionContinue ExecutorQuotaFailureAction = "Continue"
+	ExecutorQuotaFailureActionWait     ExecutorQuotaFailureAction = "Wait"
+	ExecutorQuotaFailureActionFail     ExecutorQuotaFailureAction = "Fail"
+)
+
+// ExecutorQuotaShortfall records executors of a SparkApplication missing because of an exhausted ResourceQuota.
+type ExecutorQuotaShortfall struct {
+	// RequestedExecutors is the number of executors the application requires.
+	RequestedExecutors int32 `json:"requestedExecutors"`
+	// ActiveExecutors is the number of pending and running executors of the application.
+	ActiveExecutors int32 `json:"activeExecutors"`
+	// ResourceQuota is the name of the exhausted ResourceQuota.
+	// +optional
+	ResourceQuota string `json:"resourceQuota,omitempty"`
+	// Message describes the shortfall.
+	// +optional
+	Message string `json:"message,omitempty"`
+	// DetectedTime is the time the shortfall was first detected.
+	// +optional
+	DetectedTime metav1.Time `json:"detectedTime,omitempty"`
+}
+
+// TopologyPolicy describes how executors are placed across topology domains.
+type TopologyPolicy string
+
+// Different types of executor topology policies.
+const (
+	TopologyPolicyZoneAffinity TopologyPolicy = "zone-affinity"
+)
+
+// LocalDirPolicy describes how the volumes backing the Spark local directories are provisioned.
+type LocalDirPolicy struct {
+	// Type is the type of the volumes backing the local directories.
+	// +kubebuilder:validation:Enum={EmptyDir,Memory,HostPath,PersistentVolumeClaim}
+	Type LocalDirType `json:"type"`
+	// Count is the number of local directories, each backed by a separate volume. Ignored for type HostPath,
+	// for which one local directory is provisioned for each of the host paths. Defaults to 1.
+	// +optional
+	// +kubebuilder:validation:Minimum=1
+	Count *int32 `json:"count,omitempty"`
+	// SizeLimit is the size limit of each volume of type EmptyDir or Memory, and the requested size of
+	// each volume of type PersistentVolumeClaim.
+	// +optional
+	SizeLimit *resource.Quantity `json:"sizeLimit,omitempty"`
+	// HostPaths is the pool of directories on the host backing the local directories for type HostPath.
+	// +optional
+	HostPaths []string `json:"hostPaths,omitempty"`
+	// StorageClassName is the storage class of the on-demand PersistentVolumeClaims for type PersistentVolumeClaim.
+	// +optional
+	StorageClassName *string `json:"storageClassName,omitempty"`
+	// Driver controls whether the local directories are also provisioned for the driver. Defaults to false,
+	// i.e. only the executors get the local directories.
+	// +optional
+	Driver *bool `json:"driver,omitempty"`
+}
+
+// LocalDirType is the type of the volumes backing the Spark local directories.
+type LocalDirType string
+
+// Different types of local directory volumes.
+const (
+	LocalDirTypeEmptyDir              LocalDirType = "EmptyDir"
+	LocalDirTypeMemory                LocalDirType = "Memory"
+	LocalDirTypeHostPath              LocalDirType = "HostPath"
+	LocalDirTypePersistentVolumeClaim LocalDirType = "PersistentVolumeClaim"
+)
+
 // NamePath is a pair of a name and a path to which the named objects should be mounted to.
 type NamePath struct {
 	Name string `json:"name"`
@@ -614,6 +1085,23 @@ type DriverInfo struct {
 	PodName             string `json:"podName,omitempty"`
 }
 
+// DriverProgress records the progress of an application scraped from the REST API and the metrics of its driver,
+// which tells whether slow progress is bound by the scheduling of executors or by their computation.
+type DriverProgress struct {
+	// ActiveStages is the number of active stages.
+	ActiveStages int32 `json:"activeStages"`
+	// PendingStages is the number of stages waiting for their parent stages or resources.
+	PendingStages int32 `json:"pendingStages"`
+	// ActiveExecutors is the number of executors registered with the driver.
+	ActiveExecutors int32 `json:"activeExecutors"`
+	// TargetExecutors is the number of executors dynamic allocation currently requests.
+	// It is only recorded if dynamic allocation is enabled.
+	// +optional
+	TargetExecutors *int32 `json:"targetExecutors,omitempty"`
+	// UpdateTime is the time the progress was scraped.
+	UpdateTime metav1.Time `json:"updateTime"`
+}
+
 // SecretInfo captures information of a secret.
 type SecretInfo struct {
 	Name string     `json:"name"`
@@ -651,6 +1139,23 @@ type MonitoringSpec struct {
 	// Prometheus is for configuring the Prometheus JMX exporter.
 	// +optional
 	Prometheus *PrometheusSpec `json:"prometheus,omitempty"`
+	// DriverMetricsService configures a ClusterIP Service exposing the Prometheus JMX exporter port of the driver,
+	// for scraping solutions that discover targets through Services rather than pod annotations. The Service is
+	// created on submission and deleted once the application terminates. It requires Prometheus to be configured
+	// and ExposeDriverMetrics to be true.
+	// +optional
+	DriverMetricsService *MetricsServiceSpec `json:"driverMetricsService,omitempty"`
+}
+
+// MetricsServiceSpec defines the Service exposing the metrics port of the driver.
+type MetricsServiceSpec struct {
+	// Labels are added to the Service in addition to the stable labels set by the operator, which are the
+	// app name label and the metrics service label.
+	// +optional
+	Labels map[string]string `json:"labels,omitempty"`
+	// Annotations are added to the Service.
+	// +optional
+	Annotations map[string]string `json:"annotations,omitempty"`
 }
 
 // PrometheusSpec defines the Prometheus specification when Prometheus is to be used for
@@ -686,6 +1191,294 @@ type GPUSpec struct {
 	Quantity int64 `json:"quantity"`
 }
 
+// KerberosSpec configures Kerberos authentication of a SparkApplication. The keytab and the Kerberos configuration
+// are mounted into the driver and executor pods, an init container obtains the initial ticket, and optionally a
+// sidecar container renews the ticket periodically for long-running applications.
+type KerberosSpec struct {
+	// Principal is the Kerberos principal the application authenticates as.
+	Principal string `json:"principal"`
+	// KeytabSecret is the name of the Secret holding the keytab of the principal.
+	KeytabSecret string `json:"keytabSecret"`
+	// KeytabSecretKey is the key of the keytab in the Secret. Defaults to `krb5.keytab`.
+	// +optional
+	KeytabSecretKey *string `json:"keytabSecretKey,omitempty"`
+	// Krb5ConfigMap is the name of the ConfigMap holding the Kerberos configuration under the key `krb5.conf`.
+	Krb5ConfigMap string `json:"krb5ConfigMap"`
+	// RenewalInterval is the interval at which the ticket is renewed by a native sidecar container, e.g. `8h`.
+	// No sidecar is injected if not set. Requires Kubernetes 1.29 or higher.
+	// +optional
+	RenewalInterval *string `json:"renewalInterval,omitempty"`
+	// Image is the container image used to run `kinit`. Defaults to the image of the Spark container.
+	// +optional
+	Image *string `json:"image,omitempty"`
+}
+
+// NetworkPorts contains the ports the driver and executors listen on.
+type NetworkPorts struct {
+	// DriverPort is the port of the driver RPC endpoint. Maps to `spark.driver.port`.
+	// +optional
+	// +kubebuilder:validation:Minimum=1
+	// +kubebuilder:validation:Maximum=65535
+	DriverPort *int32 `json:"driverPort,omitempty"`
+	// DriverBlockManagerPort is the port of the block manager of the driver. Defaults to BlockManagerPort.
+	// Maps to `spark.driver.blockManager.port`.
+	// +optional
+	// +kubebuilder:validation:Minimum=1
+	// +kubebuilder:validation:Maximum=65535
+	DriverBlockManagerPort *int32 `json:"driverBlockManagerPort,omitempty"`
+	// BlockManagerPort is the port of the block managers of the driver and executors.
+	// Maps to `spark.blockManager.port`.
+	// +optional
+	// +kubebuilder:validation:Minimum=1
+	// +kubebuilder:validation:Maximum=65535
+	BlockManagerPort *int32 `json:"blockManagerPort,omitempty"`
+	// UIPort is the port of the Spark web UI. Maps to `spark.ui.port`.
+	// +optional
+	// +kubebuilder:validation:Minimum=1
+	// +kubebuilder:validation:Maximum=65535
+	UIPort *int32 `json:"uiPort,omitempty"`
+}
+
+// ImagePrefetchSpec contains configuration options for prefetching the executor image.
+type ImagePrefetchSpec struct {
+	// TimeoutSeconds is the maximum time in seconds to wait for the executor image to be pulled onto
+	// the candidate nodes before the application is submitted anyway. Defaults to 300.
+	// +optional
+	// +kubebuilder:validation:Minimum=0
+	TimeoutSeconds *int32 `json:"timeoutSeconds,omitempty"`
+	// PriorityClassName is the name of the priority class of the prefetch pods, which should be a low
+	// priority so that the prefetch pods never preempt other workloads.
+	// +optional
+	PriorityClassName *string `json:"priorityClassName,omitempty"`
+}
+
+// SecretRotationSpec contains configuration options for rolling out rotated Secrets to the executors.
+// The Secrets listed in `spec.executor.secrets` are watched for changes. The driver keeps the Secrets it
+// started with, so rotated credentials must remain valid against the driver, e.g. certificates issued
+// by the same CA.
+type SecretRotationSpec struct {
+	// BatchSize is the maximum number of executors restarted at once. Defaults to 1.
+	// +optional
+	// +kubebuilder:validation:Minimum=1
+	BatchSize *int32 `json:"batchSize,omitempty"`
+	// BatchIntervalSeconds is the minimum interval in seconds between restarting two batches of executors.
+	// Defaults to 60.
+	// +optional
+	// +kubebuilder:validation:Minimum=0
+	BatchIntervalSeconds *int64 `json:"batchIntervalSeconds,omitempty"`
+}
+
+// SecretRotationStatus tracks the rollout of rotated Secrets to the executors.
+type SecretRotationStatus struct {
+	// SecretHash is the hash of the contents of the Secrets mounted into the executors being rolled out. The
+	// webhook records it on the executors it mutates, and executors recording another hash are restarted.
+	SecretHash string `json:"secretHash,omitempty"`
+	// RolloutStartTime is the time the rollout of the current Secret contents started. It is unset once all
+	// executors run with the current contents.
+	// +optional
+	RolloutStartTime *metav1.Time `json:"rolloutStartTime,omitempty"`
+	// LastBatchTime is the time the last batch of executors was restarted.
+	// +optional
+	LastBatchTime *metav1.Time `json:"lastBatchTime,omitempty"`
+}
+
+// PostRunAction is an action executed by the operator once the application reaches a terminal state.
+// Exactly one of Webhook, ConfigMapSummary and DriverLogs must be set.
+type PostRunAction struct {
+	// Name identifies the action in the status of the application.
+	Name string `json:"name"`
+	// Webhook POSTs the SparkApplication as JSON to an HTTP endpoint.
+	// +optional
+	Webhook *WebhookPostRunAction `json:"webhook,omitempty"`
+	// ConfigMapSummary creates a ConfigMap summarizing the run in the namespace of the application.
+	// +optional
+	ConfigMapSummary *ConfigMapSummaryPostRunAction `json:"configMapSummary,omitempty"`
+	// DriverLogs copies the logs of the driver to the application archive of the operator.
+	// +optional
+	DriverLogs *DriverLogsPostRunAction `json:"driverLogs,omitempty"`
+}
+
+// WebhookPostRunAction calls an HTTP endpoint with the SparkApplication.
+type WebhookPostRunAction struct {
+	// URL is the endpoint the SparkApplication is POSTed to. It must start with one of the URL prefixes the operator
+	// is configured to allow, see the `--post-run-webhook-url-prefixes` flag of the controller.
+	URL string `json:"url"`
+	// TimeoutSeconds is the timeout of the call. Defaults to 10 seconds.
+	// +optional
+	// +kubebuilder:validation:Minimum=1
+	TimeoutSeconds *int32 `json:"timeoutSeconds,omitempty"`
+}
+
+// ConfigMapSummaryPostRunAction creates a ConfigMap summarizing the run of the application. The ConfigMap is not
+// owned by the application, so it outlives the application once its TTL expires.
+type ConfigMapSummaryPostRunAction struct {
+	// ConfigMapName is the name of the ConfigMap. Defaults to <application name>-summary.
+	// +optional
+	ConfigMapName *string `json:"configMapName,omitempty"`
+}
+
+// DriverLogsPostRunAction copies the logs of the driver to the application archive of the operator.
+type DriverLogsPostRunAction struct {
+	// TailLines limits the copy to the given number of lines from the end of the logs.
+	// +optional
+	// +kubebuilder:validation:Minimum=1
+	TailLines *int64 `json:"tailLines,omitempty"`
+}
+
+// Output is an output of an application. Exactly one of URI and ConfigMapName must be set.
+type Output struct {
+	// Name identifies the output in the status of the application.
+	Name string `json:"name"`
+	// URI is the URI of an object or of a prefix of objects in an object store, e.g. `s3://bucket/path/to/table`.
+	// Query parameters configure the bucket, e.g. `s3://bucket/path?region=us-west-1`.
+	// +optional
+	URI *string `json:"uri,omitempty"`
+	// ConfigMapName is the name of a ConfigMap in the namespace of the application.
+	// +optional
+	ConfigMapName *string `json:"configMapName,omitempty"`
+}
+
+// OutputState is the state of an output.
+type OutputState string
+
+const (
+	OutputStateAvailable OutputState = "Available"
+	OutputStateMissing   OutputState = "Missing"
+)
+
+// OutputStatus records the result of the verification of an output.
+type OutputStatus struct {
+	// Name is the name of the output.
+	Name string `json:"name"`
+	// Location is the URI or the ConfigMap name of the output.
+	Location string `json:"location"`
+	// State is the state of the output.
+	State OutputState `json:"state"`
+	// Message describes why the output is missing, if it is.
+	// +optional
+	Message string `json:"message,omitempty"`
+	// VerificationTime is the time the output was verified.
+	// +optional
+	VerificationTime metav1.Time `json:"verificationTime,omitempty"`
+}
+
+// InjectedDefault is a kind of defaults the operator injects into Spark pods.
+// +kubebuilder:validation:Enum={metadata,nodeSelector,tolerations,sidecars}
+type InjectedDefault string
+
+const (
+	// InjectedDefaultMetadata are the default labels and annotations of Spark pods.
+	InjectedDefaultMetadata InjectedDefault = "metadata"
+	// InjectedDefaultNodeSelector is the default node selector of Spark pods.
+	InjectedDefaultNodeSelector InjectedDefault = "nodeSelector"
+	// InjectedDefaultTolerations are the default tolerations of Spark pods.
+	InjectedDefaultTolerations InjectedDefault = "tolerations"
+	// InjectedDefaultSidecars are the sidecar containers, init containers and volumes injected into Spark pods.
+	InjectedDefaultSidecars InjectedDefault = "sidecars"
+)
+
+// InjectedDefaultsStatus records the kinds of operator defaults applied to and suppressed from a pod.
+type InjectedDefaultsStatus struct {
+	// Applied lists the kinds of defaults injected into the pod.
+	// +optional
+	Applied []InjectedDefault `json:"applied,omitempty"`
+	// Suppressed lists the kinds of defaults configured for the pod but not injected because the application
+	// disables them.
+	// +optional
+	Suppressed []InjectedDefault `json:"suppressed,omitempty"`
+}
+
+// PostRunActionState is the state of a post-run action.
+type PostRunActionState string
+
+const (
+	PostRunActionStateSucceeded PostRunActionState = "Succeeded"
+	PostRunActionStateFailed    PostRunActionState = "Failed"
+)
+
+// PostRunActionStatus records the result of a post-run action.
+type PostRunActionStatus struct {
+	// Name is the name of the action.
+	Name string `json:"name"`
+	// State is the state of the action.
+	State PostRunActionState `json:"state"`
+	// Message describes the failure of the action, if any.
+	// +optional
+	Message string `json:"message,omitempty"`
+	// CompletionTime is the time the action completed.
+	// +optional
+	CompletionTime metav1.Time `json:"completionTime,omitempty"`
+}
+
+// WaitForSpec contains the resources the application waits for before it is submitted.
+type WaitForSpec struct {
+	// Resources are the resources in the namespace of the application that must be ready.
+	// +listType=atomic
+	Resources []ResourceDependency `json:"resources"`
+	// TimeoutSeconds is the maximum time in seconds to wait for the resources to be ready, after which the
+	// application fails without being submitted. Defaults to 600.
+	// +optional
+	// +kubebuilder:validation:Minimum=1
+	TimeoutSeconds *int32 `json:"timeoutSeconds,omitempty"`
+}
+
+// ResourceDependency references a resource in the namespace of the application. The operator must be allowed
+// to get resources of the kind, which out of the box are ConfigMaps, Secrets, Services, Deployments,
+// StatefulSets and Jobs.
+type ResourceDependency struct {
+	// APIVersion is the API version of the resource, e.g. `apps/v1`. Defaults to `v1`.
+	// +optional
+	APIVersion string `json:"apiVersion,omitempty"`
+	// Kind is the kind of the resource, e.g. `Deployment`.
+	Kind string `json:"kind"`
+	// Name is the name of the resource.
+	Name string `json:"name"`
+	// Condition is the type of the status condition that must be `True` for the resource to be ready, e.g.
+	// `Available` for Deployments or `Complete` for Jobs. A condition of type `Ready` is also satisfied by
+	// workloads without such condition, e.g. StatefulSets, once all their replicas are ready.
+	// The resource is ready as soon as it exists if unset.
+	// +optional
+	Condition *string `json:"condition,omitempty"`
+}
+
+// SLASpec defines service level objectives of a SparkApplication.
+type SLASpec struct {
+	// MaxPendingDuration is the maximum time the application may wait for its driver to run, measured from the
+	// creation of the application or the end of its previous run, e.g. `15m`.
+	// +optional
+	MaxPendingDuration *string `json:"maxPendingDuration,omitempty"`
+	// MaxRunDuration is the maximum time the driver of the application may run, e.g. `2h`.
+	// +optional
+	MaxRunDuration *string `json:"maxRunDuration,omitempty"`
+	// ExpectedCompletionBy is a cron expression, the application is expected to complete successfully by the first
+	// time matching it after the creation of the application, e.g. `0 6 * * *` for 6 AM. The expression may be
+	// prefixed with `CRON_TZ=<time zone>`, and is otherwise interpreted in the time zone of the operator.
+	// +optional
+	ExpectedCompletionBy *string `json:"expectedCompletionBy,omitempty"`
+}
+
+// SLAViolationType is the type of an SLA violation.
+type SLAViolationType string
+
+// Different types of SLA violations.
+const (
+	SLAViolationMaxPendingDuration   SLAViolationType = "MaxPendingDuration"
+	SLAViolationMaxRunDuration       SLAViolationType = "MaxRunDuration"
+	SLAViolationExpectedCompletionBy SLAViolationType = "ExpectedCompletionBy"
+)
+
+// SLAViolation records a violation of the SLA of a SparkApplication.
+type SLAViolation struct {
+	// Type is the type of the violation.
+	Type SLAViolationType `json:"type"`
+	// Message describes the violation.
+	// +optional
+	Message string `json:"message,omitempty"`
+	// Time is the time the violation was detected.
+	// +optional
+	Time metav1.Time `json:"time,omitempty"`
+}
+
 // DynamicAllocation contains configuration options for dynamic allocation.
 type DynamicAllocation struct {
 	// Enabled controls whether dynamic allocation is enabled or not.
@@ -704,4 +1497,16 @@ type DynamicAllocation struct {
 	// shuffle data if shuffle tracking is enabled (true by default if dynamic allocation is enabled).
 	// +optional
 	ShuffleTrackingTimeout *int64 `json:"shuffleTrackingTimeout,omitempty"`
+	// ExecutorIdleTimeout is the duration after which executors idle for that long are removed, e.g. `60s`.
+	// Defaults to `60s` if dynamic allocation is enabled.
+	// +optional
+	ExecutorIdleTimeout *string `json:"executorIdleTimeout,omitempty"`
+	// CachedExecutorIdleTimeout is the duration after which executors holding cached data blocks and idle for that
+	// long are removed, e.g. `30m`. Executors holding cached data are never removed if not set.
+	// +optional
+	CachedExecutorIdleTimeout *string `json:"cachedExecutorIdleTimeout,omitempty"`
+	// SchedulerBacklogTimeout is the duration after which new executors are requested if there have been pending
+	// tasks backlogged for that long, e.g. `1s`. Defaults to `1s` if dynamic allocation is enabled.
+	// +optional
+	SchedulerBacklogTimeout *string `json:"schedulerBacklogTimeout,omitempty"`
 }
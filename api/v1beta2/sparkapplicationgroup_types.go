@@ -0,0 +1,118 @@
+/*
+Copyright 2024 The Kubeflow authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1beta2
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func init() {
+	SchemeBuilder.Register(&SparkApplicationGroup{}, &SparkApplicationGroupList{})
+}
+
+// SparkApplicationGroupSpec defines the desired state of SparkApplicationGroup.
+type SparkApplicationGroupSpec struct {
+	// Labels are the labels shared by all member SparkApplications of the group.
+	// +optional
+	Labels map[string]string `json:"labels,omitempty"`
+	// Applications are the member SparkApplications of the group.
+	// +kubebuilder:validation:MinItems=1
+	Applications []SparkApplicationGroupMember `json:"applications"`
+	// Suspend is a flag telling the controller to stop the running member applications and not to start
+	// any member application until it is set to false again. Stopped member applications are started over
+	// once the group is resumed, while the terminated ones are not run again.
+	// +optional
+	Suspend *bool `json:"suspend,omitempty"`
+	// Kill is a flag telling the controller to stop all running member applications. A killed group cannot
+	// be resumed.
+	// +optional
+	Kill *bool `json:"kill,omitempty"`
+}
+
+// SparkApplicationGroupMember defines a member SparkApplication of a SparkApplicationGroup.
+type SparkApplicationGroupMember struct {
+	// Name is the name of the member within the group. The member SparkApplication is named
+	// after the group and the member, i.e. `<group>-<member>`.
+	Name string `json:"name"`
+	// Template is the spec of the member SparkApplication.
+	// +kubebuilder:validation:Schemaless
+	// +kubebuilder:validation:Type=object
+	// +kubebuilder:pruning:PreserveUnknownFields
+	Template SparkApplicationSpec `json:"template"`
+}
+
+// SparkApplicationGroupState is the aggregated state of the members of a SparkApplicationGroup.
+type SparkApplicationGroupState string
+
+const (
+	SparkApplicationGroupStatePending      SparkApplicationGroupState = "Pending"
+	SparkApplicationGroupStateRunning      SparkApplicationGroupState = "Running"
+	SparkApplicationGroupStateAllCompleted SparkApplicationGroupState = "AllCompleted"
+	SparkApplicationGroupStateAnyFailed    SparkApplicationGroupState = "AnyFailed"
+	SparkApplicationGroupStateSuspended    SparkApplicationGroupState = "Suspended"
+	SparkApplicationGroupStateKilled       SparkApplicationGroupState = "Killed"
+)
+
+// SparkApplicationGroupStatus defines the observed state of SparkApplicationGroup.
+type SparkApplicationGroupStatus struct {
+	// State is the aggregated state of the member applications.
+	State SparkApplicationGroupState `json:"state,omitempty"`
+	// Members are the states of the member applications.
+	Members []SparkApplicationGroupMemberStatus `json:"members,omitempty"`
+	// Completed is the number of member applications that completed successfully.
+	Completed int32 `json:"completed,omitempty"`
+	// Failed is the number of member applications that failed.
+	Failed int32 `json:"failed,omitempty"`
+}
+
+// SparkApplicationGroupMemberStatus is the state of a member SparkApplication of a SparkApplicationGroup.
+type SparkApplicationGroupMemberStatus struct {
+	// Name is the name of the member within the group.
+	Name string `json:"name"`
+	// ApplicationName is the name of the member SparkApplication.
+	ApplicationName string `json:"applicationName"`
+	// State is the state of the member SparkApplication.
+	State ApplicationStateType `json:"state,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+// +kubebuilder:metadata:annotations="api-approved.kubernetes.io=https://github.com/kubeflow/spark-operator/pull/1298"
+// +kubebuilder:resource:scope=Namespaced,shortName=sparkappgroup,singular=sparkapplicationgroup
+// +kubebuilder:subresource:status
+// +kubebuilder:printcolumn:JSONPath=.status.state,name=Status,type=string
+// +kubebuilder:printcolumn:JSONPath=.status.completed,name=Completed,type=integer
+// +kubebuilder:printcolumn:JSONPath=.status.failed,name=Failed,type=integer
+// +kubebuilder:printcolumn:JSONPath=.metadata.creationTimestamp,name=Age,type=date
+
+// SparkApplicationGroup is the Schema for the sparkapplicationgroups API. It manages a set of
+// SparkApplications deployed, observed and stopped as a unit.
+type SparkApplicationGroup struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata"`
+
+	Spec   SparkApplicationGroupSpec   `json:"spec"`
+	Status SparkApplicationGroupStatus `json:"status,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+// SparkApplicationGroupList contains a list of SparkApplicationGroup.
+type SparkApplicationGroupList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []SparkApplicationGroup `json:"items"`
+}
@@ -0,0 +1,64 @@
+/*
+Copyright 2024 The Kubeflow authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1beta2
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func init() {
+	SchemeBuilder.Register(&SparkApplicationTemplate{}, &SparkApplicationTemplateList{})
+}
+
+// SparkApplicationTemplateSpec defines the desired state of SparkApplicationTemplate.
+type SparkApplicationTemplateSpec struct {
+	// Template carries the common SparkApplication spec fields shared by the applications referencing this template.
+	// Fields set in a referencing application take precedence over the fields set here. Maps are merged key by key,
+	// while lists and scalar values set in the application replace those in the template.
+	// +kubebuilder:validation:Schemaless
+	// +kubebuilder:validation:Type=object
+	// +kubebuilder:pruning:PreserveUnknownFields
+	Template SparkApplicationSpec `json:"template"`
+}
+
+// +kubebuilder:object:root=true
+// +kubebuilder:metadata:annotations="api-approved.kubernetes.io=https://github.com/kubeflow/spark-operator/pull/1298"
+// +kubebuilder:resource:scope=Namespaced,shortName=sparkapptemplate,singular=sparkapplicationtemplate
+// +kubebuilder:printcolumn:JSONPath=.metadata.creationTimestamp,name=Age,type=date
+
+// SparkApplicationTemplate is the Schema for the sparkapplicationtemplates API.
+type SparkApplicationTemplate struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata"`
+
+	Spec SparkApplicationTemplateSpec `json:"spec"`
+}
+
+// +kubebuilder:object:root=true
+
+// SparkApplicationTemplateList contains a list of SparkApplicationTemplate.
+type SparkApplicationTemplateList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []SparkApplicationTemplate `json:"items"`
+}
+
+// SparkApplicationTemplateReference references a SparkApplicationTemplate in the same namespace.
+type SparkApplicationTemplateReference struct {
+	// Name is the name of the SparkApplicationTemplate.
+	Name string `json:"name"`
+}
@@ -0,0 +1,138 @@
+/*
+Copyright 2024 The Kubeflow authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1beta2
+
+import (
+	corev1 "k8s.io/api/core/v1"
+	networkingv1 "k8s.io/api/networking/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func init() {
+	SchemeBuilder.Register(&SparkSQLGateway{}, &SparkSQLGatewayList{})
+}
+
+// SparkSQLGatewaySpec defines the desired state of SparkSQLGateway.
+type SparkSQLGatewaySpec struct {
+	// Image is the container image of the Spark Thrift server and its executors.
+	Image string `json:"image"`
+	// ImagePullPolicy is the image pull policy of the Spark Thrift server and its executors.
+	// +optional
+	ImagePullPolicy *corev1.PullPolicy `json:"imagePullPolicy,omitempty"`
+	// ImagePullSecrets is the list of image-pull secrets.
+	// +optional
+	ImagePullSecrets []string `json:"imagePullSecrets,omitempty"`
+	// Replicas is the number of Spark Thrift server replicas. Each replica is an independent Spark driver.
+	// +kubebuilder:validation:Minimum=0
+	// +kubebuilder:default=1
+	// +optional
+	Replicas *int32 `json:"replicas,omitempty"`
+	// ServiceAccount is the name of the Kubernetes service account used by the Thrift server to manage executors.
+	// +optional
+	ServiceAccount *string `json:"serviceAccount,omitempty"`
+	// Resources are the compute resources of the Thrift server container.
+	// +optional
+	Resources *corev1.ResourceRequirements `json:"resources,omitempty"`
+	// SparkConf carries user-specified Spark configuration properties passed to the Thrift server.
+	// +optional
+	SparkConf map[string]string `json:"sparkConf,omitempty"`
+	// DynamicAllocation configures dynamic allocation of the executors of each Thrift server replica.
+	// +optional
+	DynamicAllocation *DynamicAllocation `json:"dynamicAllocation,omitempty"`
+	// Port is the port the Thrift server listens on.
+	// +kubebuilder:default=10000
+	// +optional
+	Port *int32 `json:"port,omitempty"`
+	// ServiceType is the type of the Service exposing the Thrift server.
+	// +kubebuilder:default=ClusterIP
+	// +optional
+	ServiceType *corev1.ServiceType `json:"serviceType,omitempty"`
+	// AuthSecretName is the name of a Secret holding a `hive-site.xml` with the HiveServer2 authentication
+	// configuration, which is mounted into the Spark configuration directory of the Thrift server.
+	// +optional
+	AuthSecretName *string `json:"authSecretName,omitempty"`
+	// Ingress configures an Ingress exposing the Thrift server over HTTP. If set, the Thrift server is
+	// started in HTTP transport mode.
+	// +optional
+	Ingress *SparkSQLGatewayIngress `json:"ingress,omitempty"`
+	// NodeSelector is the Kubernetes node selector of the Thrift server pods.
+	// +optional
+	NodeSelector map[string]string `json:"nodeSelector,omitempty"`
+	// Tolerations specifies the tolerations of the Thrift server pods.
+	// +optional
+	Tolerations []corev1.Toleration `json:"tolerations,omitempty"`
+}
+
+// SparkSQLGatewayIngress configures the Ingress of a SparkSQLGateway.
+type SparkSQLGatewayIngress struct {
+	// Host is the host of the Ingress rule.
+	Host string `json:"host"`
+	// IngressClassName is the name of the IngressClass of the Ingress.
+	// +optional
+	IngressClassName *string `json:"ingressClassName,omitempty"`
+	// Annotations are the annotations added to the Ingress.
+	// +optional
+	Annotations map[string]string `json:"annotations,omitempty"`
+	// TLS is the TLS configuration of the Ingress.
+	// +optional
+	TLS []networkingv1.IngressTLS `json:"tls,omitempty"`
+}
+
+// SparkSQLGatewayStatus defines the observed state of SparkSQLGateway.
+type SparkSQLGatewayStatus struct {
+	// Replicas is the number of Thrift server replicas.
+	Replicas int32 `json:"replicas,omitempty"`
+	// ReadyReplicas is the number of ready Thrift server replicas.
+	ReadyReplicas int32 `json:"readyReplicas,omitempty"`
+	// ServiceName is the name of the Service exposing the Thrift server.
+	ServiceName string `json:"serviceName,omitempty"`
+	// Endpoint is the in-cluster endpoint of the Thrift server.
+	Endpoint string `json:"endpoint,omitempty"`
+	// IngressName is the name of the Ingress exposing the Thrift server, if any.
+	IngressName string `json:"ingressName,omitempty"`
+	// Selector is the label selector of the Thrift server pods, used by the scale subresource.
+	Selector string `json:"selector,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+// +kubebuilder:metadata:annotations="api-approved.kubernetes.io=https://github.com/kubeflow/spark-operator/pull/1298"
+// +kubebuilder:resource:scope=Namespaced,shortName=sparksqlgw,singular=sparksqlgateway
+// +kubebuilder:subresource:status
+// +kubebuilder:subresource:scale:specpath=.spec.replicas,statuspath=.status.replicas,selectorpath=.status.selector
+// +kubebuilder:printcolumn:JSONPath=.spec.replicas,name=Replicas,type=integer
+// +kubebuilder:printcolumn:JSONPath=.status.readyReplicas,name=Ready,type=integer
+// +kubebuilder:printcolumn:JSONPath=.status.endpoint,name=Endpoint,type=string
+// +kubebuilder:printcolumn:JSONPath=.metadata.creationTimestamp,name=Age,type=date
+
+// SparkSQLGateway is the Schema for the sparksqlgateways API. It runs a long-lived Spark Thrift server
+// compatible with HiveServer2 clients such as JDBC/ODBC BI tools.
+type SparkSQLGateway struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata"`
+
+	Spec   SparkSQLGatewaySpec   `json:"spec"`
+	Status SparkSQLGatewayStatus `json:"status,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+// SparkSQLGatewayList contains a list of SparkSQLGateway.
+type SparkSQLGatewayList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []SparkSQLGateway `json:"items"`
+}
@@ -23,7 +23,9 @@ package v1beta2
 import (
 	"k8s.io/api/core/v1"
 	networkingv1 "k8s.io/api/networking/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	runtime "k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/intstr"
 )
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
@@ -61,6 +63,11 @@ func (in *BatchSchedulerConfiguration) DeepCopyInto(out *BatchSchedulerConfigura
 			(*out)[key] = val.DeepCopy()
 		}
 	}
+	if in.TTLSecondsAfterFinished != nil {
+		in, out := &in.TTLSecondsAfterFinished, &out.TTLSecondsAfterFinished
+		*out = new(int32)
+		**out = **in
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new BatchSchedulerConfiguration.
@@ -73,6 +80,26 @@ func (in *BatchSchedulerConfiguration) DeepCopy() *BatchSchedulerConfiguration {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ConfigMapSummaryPostRunAction) DeepCopyInto(out *ConfigMapSummaryPostRunAction) {
+	*out = *in
+	if in.ConfigMapName != nil {
+		in, out := &in.ConfigMapName, &out.ConfigMapName
+		*out = new(string)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ConfigMapSummaryPostRunAction.
+func (in *ConfigMapSummaryPostRunAction) DeepCopy() *ConfigMapSummaryPostRunAction {
+	if in == nil {
+		return nil
+	}
+	out := new(ConfigMapSummaryPostRunAction)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *Dependencies) DeepCopyInto(out *Dependencies) {
 	*out = *in
@@ -111,6 +138,11 @@ func (in *Dependencies) DeepCopyInto(out *Dependencies) {
 		*out = make([]string, len(*in))
 		copy(*out, *in)
 	}
+	if in.OCIArtifacts != nil {
+		in, out := &in.OCIArtifacts, &out.OCIArtifacts
+		*out = new(OCIArtifactsSpec)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Dependencies.
@@ -196,6 +228,47 @@ func (in *DriverIngressConfiguration) DeepCopy() *DriverIngressConfiguration {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DriverLogsPostRunAction) DeepCopyInto(out *DriverLogsPostRunAction) {
+	*out = *in
+	if in.TailLines != nil {
+		in, out := &in.TailLines, &out.TailLines
+		*out = new(int64)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DriverLogsPostRunAction.
+func (in *DriverLogsPostRunAction) DeepCopy() *DriverLogsPostRunAction {
+	if in == nil {
+		return nil
+	}
+	out := new(DriverLogsPostRunAction)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DriverProgress) DeepCopyInto(out *DriverProgress) {
+	*out = *in
+	if in.TargetExecutors != nil {
+		in, out := &in.TargetExecutors, &out.TargetExecutors
+		*out = new(int32)
+		**out = **in
+	}
+	in.UpdateTime.DeepCopyInto(&out.UpdateTime)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DriverProgress.
+func (in *DriverProgress) DeepCopy() *DriverProgress {
+	if in == nil {
+		return nil
+	}
+	out := new(DriverProgress)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *DriverSpec) DeepCopyInto(out *DriverSpec) {
 	*out = *in
@@ -215,11 +288,6 @@ func (in *DriverSpec) DeepCopyInto(out *DriverSpec) {
 		*out = new(string)
 		**out = **in
 	}
-	if in.Lifecycle != nil {
-		in, out := &in.Lifecycle, &out.Lifecycle
-		*out = new(v1.Lifecycle)
-		(*in).DeepCopyInto(*out)
-	}
 	if in.KubernetesMaster != nil {
 		in, out := &in.KubernetesMaster, &out.KubernetesMaster
 		*out = new(string)
@@ -239,6 +307,11 @@ func (in *DriverSpec) DeepCopyInto(out *DriverSpec) {
 			(*out)[key] = val
 		}
 	}
+	if in.ServicePublishNotReadyAddresses != nil {
+		in, out := &in.ServicePublishNotReadyAddresses, &out.ServicePublishNotReadyAddresses
+		*out = new(bool)
+		**out = **in
+	}
 	if in.Ports != nil {
 		in, out := &in.Ports, &out.Ports
 		*out = make([]Port, len(*in))
@@ -249,6 +322,11 @@ func (in *DriverSpec) DeepCopyInto(out *DriverSpec) {
 		*out = new(string)
 		**out = **in
 	}
+	if in.TerminateSidecars != nil {
+		in, out := &in.TerminateSidecars, &out.TerminateSidecars
+		*out = new(bool)
+		**out = **in
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DriverSpec.
@@ -284,6 +362,21 @@ func (in *DynamicAllocation) DeepCopyInto(out *DynamicAllocation) {
 		*out = new(int64)
 		**out = **in
 	}
+	if in.ExecutorIdleTimeout != nil {
+		in, out := &in.ExecutorIdleTimeout, &out.ExecutorIdleTimeout
+		*out = new(string)
+		**out = **in
+	}
+	if in.CachedExecutorIdleTimeout != nil {
+		in, out := &in.CachedExecutorIdleTimeout, &out.CachedExecutorIdleTimeout
+		*out = new(string)
+		**out = **in
+	}
+	if in.SchedulerBacklogTimeout != nil {
+		in, out := &in.SchedulerBacklogTimeout, &out.SchedulerBacklogTimeout
+		*out = new(string)
+		**out = **in
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DynamicAllocation.
@@ -296,6 +389,102 @@ func (in *DynamicAllocation) DeepCopy() *DynamicAllocation {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ExecutorDisruptionBudget) DeepCopyInto(out *ExecutorDisruptionBudget) {
+	*out = *in
+	if in.MaxUnavailable != nil {
+		in, out := &in.MaxUnavailable, &out.MaxUnavailable
+		*out = new(intstr.IntOrString)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ExecutorDisruptionBudget.
+func (in *ExecutorDisruptionBudget) DeepCopy() *ExecutorDisruptionBudget {
+	if in == nil {
+		return nil
+	}
+	out := new(ExecutorDisruptionBudget)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ExecutorLaunchRateLimit) DeepCopyInto(out *ExecutorLaunchRateLimit) {
+	*out = *in
+	if in.BatchSize != nil {
+		in, out := &in.BatchSize, &out.BatchSize
+		*out = new(int32)
+		**out = **in
+	}
+	if in.BatchDelay != nil {
+		in, out := &in.BatchDelay, &out.BatchDelay
+		*out = new(string)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ExecutorLaunchRateLimit.
+func (in *ExecutorLaunchRateLimit) DeepCopy() *ExecutorLaunchRateLimit {
+	if in == nil {
+		return nil
+	}
+	out := new(ExecutorLaunchRateLimit)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ExecutorPlacement) DeepCopyInto(out *ExecutorPlacement) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ExecutorPlacement.
+func (in *ExecutorPlacement) DeepCopy() *ExecutorPlacement {
+	if in == nil {
+		return nil
+	}
+	out := new(ExecutorPlacement)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ExecutorQuotaFailurePolicy) DeepCopyInto(out *ExecutorQuotaFailurePolicy) {
+	*out = *in
+	if in.WaitTimeout != nil {
+		in, out := &in.WaitTimeout, &out.WaitTimeout
+		*out = new(string)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ExecutorQuotaFailurePolicy.
+func (in *ExecutorQuotaFailurePolicy) DeepCopy() *ExecutorQuotaFailurePolicy {
+	if in == nil {
+		return nil
+	}
+	out := new(ExecutorQuotaFailurePolicy)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ExecutorQuotaShortfall) DeepCopyInto(out *ExecutorQuotaShortfall) {
+	*out = *in
+	in.DetectedTime.DeepCopyInto(&out.DetectedTime)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ExecutorQuotaShortfall.
+func (in *ExecutorQuotaShortfall) DeepCopy() *ExecutorQuotaShortfall {
+	if in == nil {
+		return nil
+	}
+	out := new(ExecutorQuotaShortfall)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ExecutorSpec) DeepCopyInto(out *ExecutorSpec) {
 	*out = *in
@@ -315,11 +504,6 @@ func (in *ExecutorSpec) DeepCopyInto(out *ExecutorSpec) {
 		*out = new(string)
 		**out = **in
 	}
-	if in.Lifecycle != nil {
-		in, out := &in.Lifecycle, &out.Lifecycle
-		*out = new(v1.Lifecycle)
-		(*in).DeepCopyInto(*out)
-	}
 	if in.DeleteOnTermination != nil {
 		in, out := &in.DeleteOnTermination, &out.DeleteOnTermination
 		*out = new(bool)
@@ -335,6 +519,36 @@ func (in *ExecutorSpec) DeepCopyInto(out *ExecutorSpec) {
 		*out = new(string)
 		**out = **in
 	}
+	if in.TopologyPolicy != nil {
+		in, out := &in.TopologyPolicy, &out.TopologyPolicy
+		*out = new(TopologyPolicy)
+		**out = **in
+	}
+	if in.PinToDriverZone != nil {
+		in, out := &in.PinToDriverZone, &out.PinToDriverZone
+		*out = new(bool)
+		**out = **in
+	}
+	if in.MaxPerNode != nil {
+		in, out := &in.MaxPerNode, &out.MaxPerNode
+		*out = new(int32)
+		**out = **in
+	}
+	if in.DisruptionBudget != nil {
+		in, out := &in.DisruptionBudget, &out.DisruptionBudget
+		*out = new(ExecutorDisruptionBudget)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.LaunchRateLimit != nil {
+		in, out := &in.LaunchRateLimit, &out.LaunchRateLimit
+		*out = new(ExecutorLaunchRateLimit)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.QuotaFailurePolicy != nil {
+		in, out := &in.QuotaFailurePolicy, &out.QuotaFailurePolicy
+		*out = new(ExecutorQuotaFailurePolicy)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ExecutorSpec.
@@ -363,203 +577,847 @@ func (in *GPUSpec) DeepCopy() *GPUSpec {
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *MonitoringSpec) DeepCopyInto(out *MonitoringSpec) {
+func (in *ImagePrefetchSpec) DeepCopyInto(out *ImagePrefetchSpec) {
 	*out = *in
-	if in.MetricsProperties != nil {
-		in, out := &in.MetricsProperties, &out.MetricsProperties
-		*out = new(string)
+	if in.TimeoutSeconds != nil {
+		in, out := &in.TimeoutSeconds, &out.TimeoutSeconds
+		*out = new(int32)
 		**out = **in
 	}
-	if in.MetricsPropertiesFile != nil {
-		in, out := &in.MetricsPropertiesFile, &out.MetricsPropertiesFile
+	if in.PriorityClassName != nil {
+		in, out := &in.PriorityClassName, &out.PriorityClassName
 		*out = new(string)
 		**out = **in
 	}
-	if in.Prometheus != nil {
-		in, out := &in.Prometheus, &out.Prometheus
-		*out = new(PrometheusSpec)
-		(*in).DeepCopyInto(*out)
-	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MonitoringSpec.
-func (in *MonitoringSpec) DeepCopy() *MonitoringSpec {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ImagePrefetchSpec.
+func (in *ImagePrefetchSpec) DeepCopy() *ImagePrefetchSpec {
 	if in == nil {
 		return nil
 	}
-	out := new(MonitoringSpec)
+	out := new(ImagePrefetchSpec)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *NameKey) DeepCopyInto(out *NameKey) {
+func (in *InjectedDefaultsStatus) DeepCopyInto(out *InjectedDefaultsStatus) {
 	*out = *in
-}
-
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NameKey.
-func (in *NameKey) DeepCopy() *NameKey {
-	if in == nil {
-		return nil
+	if in.Applied != nil {
+		in, out := &in.Applied, &out.Applied
+		*out = make([]InjectedDefault, len(*in))
+		copy(*out, *in)
 	}
-	out := new(NameKey)
-	in.DeepCopyInto(out)
-	return out
-}
-
-// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *NamePath) DeepCopyInto(out *NamePath) {
-	*out = *in
-}
-
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NamePath.
-func (in *NamePath) DeepCopy() *NamePath {
-	if in == nil {
-		return nil
+	if in.Suppressed != nil {
+		in, out := &in.Suppressed, &out.Suppressed
+		*out = make([]InjectedDefault, len(*in))
+		copy(*out, *in)
 	}
-	out := new(NamePath)
-	in.DeepCopyInto(out)
-	return out
-}
-
-// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *Port) DeepCopyInto(out *Port) {
-	*out = *in
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Port.
-func (in *Port) DeepCopy() *Port {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new InjectedDefaultsStatus.
+func (in *InjectedDefaultsStatus) DeepCopy() *InjectedDefaultsStatus {
 	if in == nil {
 		return nil
 	}
-	out := new(Port)
+	out := new(InjectedDefaultsStatus)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *PrometheusSpec) DeepCopyInto(out *PrometheusSpec) {
+func (in *KerberosSpec) DeepCopyInto(out *KerberosSpec) {
 	*out = *in
-	if in.Port != nil {
-		in, out := &in.Port, &out.Port
-		*out = new(int32)
-		**out = **in
-	}
-	if in.PortName != nil {
-		in, out := &in.PortName, &out.PortName
+	if in.KeytabSecretKey != nil {
+		in, out := &in.KeytabSecretKey, &out.KeytabSecretKey
 		*out = new(string)
 		**out = **in
 	}
-	if in.ConfigFile != nil {
-		in, out := &in.ConfigFile, &out.ConfigFile
+	if in.RenewalInterval != nil {
+		in, out := &in.RenewalInterval, &out.RenewalInterval
 		*out = new(string)
 		**out = **in
 	}
-	if in.Configuration != nil {
-		in, out := &in.Configuration, &out.Configuration
+	if in.Image != nil {
+		in, out := &in.Image, &out.Image
 		*out = new(string)
 		**out = **in
 	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PrometheusSpec.
-func (in *PrometheusSpec) DeepCopy() *PrometheusSpec {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new KerberosSpec.
+func (in *KerberosSpec) DeepCopy() *KerberosSpec {
 	if in == nil {
 		return nil
 	}
-	out := new(PrometheusSpec)
+	out := new(KerberosSpec)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *RestartPolicy) DeepCopyInto(out *RestartPolicy) {
+func (in *LocalDirPolicy) DeepCopyInto(out *LocalDirPolicy) {
 	*out = *in
-	if in.OnSubmissionFailureRetries != nil {
-		in, out := &in.OnSubmissionFailureRetries, &out.OnSubmissionFailureRetries
+	if in.Count != nil {
+		in, out := &in.Count, &out.Count
 		*out = new(int32)
 		**out = **in
 	}
-	if in.OnFailureRetries != nil {
-		in, out := &in.OnFailureRetries, &out.OnFailureRetries
-		*out = new(int32)
-		**out = **in
+	if in.SizeLimit != nil {
+		in, out := &in.SizeLimit, &out.SizeLimit
+		x := (*in).DeepCopy()
+		*out = &x
 	}
-	if in.OnSubmissionFailureRetryInterval != nil {
-		in, out := &in.OnSubmissionFailureRetryInterval, &out.OnSubmissionFailureRetryInterval
-		*out = new(int64)
+	if in.HostPaths != nil {
+		in, out := &in.HostPaths, &out.HostPaths
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.StorageClassName != nil {
+		in, out := &in.StorageClassName, &out.StorageClassName
+		*out = new(string)
 		**out = **in
 	}
-	if in.OnFailureRetryInterval != nil {
-		in, out := &in.OnFailureRetryInterval, &out.OnFailureRetryInterval
-		*out = new(int64)
+	if in.Driver != nil {
+		in, out := &in.Driver, &out.Driver
+		*out = new(bool)
 		**out = **in
 	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RestartPolicy.
-func (in *RestartPolicy) DeepCopy() *RestartPolicy {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new LocalDirPolicy.
+func (in *LocalDirPolicy) DeepCopy() *LocalDirPolicy {
 	if in == nil {
 		return nil
 	}
-	out := new(RestartPolicy)
+	out := new(LocalDirPolicy)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *ScheduledSparkApplication) DeepCopyInto(out *ScheduledSparkApplication) {
+func (in *MainApplicationSource) DeepCopyInto(out *MainApplicationSource) {
 	*out = *in
-	out.TypeMeta = in.TypeMeta
-	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
-	in.Spec.DeepCopyInto(&out.Spec)
-	in.Status.DeepCopyInto(&out.Status)
+	if in.Inline != nil {
+		in, out := &in.Inline, &out.Inline
+		*out = new(string)
+		**out = **in
+	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ScheduledSparkApplication.
-func (in *ScheduledSparkApplication) DeepCopy() *ScheduledSparkApplication {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MainApplicationSource.
+func (in *MainApplicationSource) DeepCopy() *MainApplicationSource {
 	if in == nil {
 		return nil
 	}
-	out := new(ScheduledSparkApplication)
+	out := new(MainApplicationSource)
 	in.DeepCopyInto(out)
 	return out
 }
 
-// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
-func (in *ScheduledSparkApplication) DeepCopyObject() runtime.Object {
-	if c := in.DeepCopy(); c != nil {
-		return c
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *MetricsServiceSpec) DeepCopyInto(out *MetricsServiceSpec) {
+	*out = *in
+	if in.Labels != nil {
+		in, out := &in.Labels, &out.Labels
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.Annotations != nil {
+		in, out := &in.Annotations, &out.Annotations
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MetricsServiceSpec.
+func (in *MetricsServiceSpec) DeepCopy() *MetricsServiceSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(MetricsServiceSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *MonitoringSpec) DeepCopyInto(out *MonitoringSpec) {
+	*out = *in
+	if in.MetricsProperties != nil {
+		in, out := &in.MetricsProperties, &out.MetricsProperties
+		*out = new(string)
+		**out = **in
+	}
+	if in.MetricsPropertiesFile != nil {
+		in, out := &in.MetricsPropertiesFile, &out.MetricsPropertiesFile
+		*out = new(string)
+		**out = **in
+	}
+	if in.Prometheus != nil {
+		in, out := &in.Prometheus, &out.Prometheus
+		*out = new(PrometheusSpec)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.DriverMetricsService != nil {
+		in, out := &in.DriverMetricsService, &out.DriverMetricsService
+		*out = new(MetricsServiceSpec)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MonitoringSpec.
+func (in *MonitoringSpec) DeepCopy() *MonitoringSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(MonitoringSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *NameKey) DeepCopyInto(out *NameKey) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NameKey.
+func (in *NameKey) DeepCopy() *NameKey {
+	if in == nil {
+		return nil
+	}
+	out := new(NameKey)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *NamePath) DeepCopyInto(out *NamePath) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NamePath.
+func (in *NamePath) DeepCopy() *NamePath {
+	if in == nil {
+		return nil
+	}
+	out := new(NamePath)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *NetworkPorts) DeepCopyInto(out *NetworkPorts) {
+	*out = *in
+	if in.DriverPort != nil {
+		in, out := &in.DriverPort, &out.DriverPort
+		*out = new(int32)
+		**out = **in
+	}
+	if in.DriverBlockManagerPort != nil {
+		in, out := &in.DriverBlockManagerPort, &out.DriverBlockManagerPort
+		*out = new(int32)
+		**out = **in
+	}
+	if in.BlockManagerPort != nil {
+		in, out := &in.BlockManagerPort, &out.BlockManagerPort
+		*out = new(int32)
+		**out = **in
+	}
+	if in.UIPort != nil {
+		in, out := &in.UIPort, &out.UIPort
+		*out = new(int32)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NetworkPorts.
+func (in *NetworkPorts) DeepCopy() *NetworkPorts {
+	if in == nil {
+		return nil
+	}
+	out := new(NetworkPorts)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *OCIArtifactsSpec) DeepCopyInto(out *OCIArtifactsSpec) {
+	*out = *in
+	if in.RegistrySecret != nil {
+		in, out := &in.RegistrySecret, &out.RegistrySecret
+		*out = new(string)
+		**out = **in
+	}
+	if in.Image != nil {
+		in, out := &in.Image, &out.Image
+		*out = new(string)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OCIArtifactsSpec.
+func (in *OCIArtifactsSpec) DeepCopy() *OCIArtifactsSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(OCIArtifactsSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *Output) DeepCopyInto(out *Output) {
+	*out = *in
+	if in.URI != nil {
+		in, out := &in.URI, &out.URI
+		*out = new(string)
+		**out = **in
+	}
+	if in.ConfigMapName != nil {
+		in, out := &in.ConfigMapName, &out.ConfigMapName
+		*out = new(string)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Output.
+func (in *Output) DeepCopy() *Output {
+	if in == nil {
+		return nil
+	}
+	out := new(Output)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *OutputStatus) DeepCopyInto(out *OutputStatus) {
+	*out = *in
+	in.VerificationTime.DeepCopyInto(&out.VerificationTime)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OutputStatus.
+func (in *OutputStatus) DeepCopy() *OutputStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(OutputStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *Port) DeepCopyInto(out *Port) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Port.
+func (in *Port) DeepCopy() *Port {
+	if in == nil {
+		return nil
+	}
+	out := new(Port)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PostRunAction) DeepCopyInto(out *PostRunAction) {
+	*out = *in
+	if in.Webhook != nil {
+		in, out := &in.Webhook, &out.Webhook
+		*out = new(WebhookPostRunAction)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.ConfigMapSummary != nil {
+		in, out := &in.ConfigMapSummary, &out.ConfigMapSummary
+		*out = new(ConfigMapSummaryPostRunAction)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.DriverLogs != nil {
+		in, out := &in.DriverLogs, &out.DriverLogs
+		*out = new(DriverLogsPostRunAction)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PostRunAction.
+func (in *PostRunAction) DeepCopy() *PostRunAction {
+	if in == nil {
+		return nil
+	}
+	out := new(PostRunAction)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PostRunActionStatus) DeepCopyInto(out *PostRunActionStatus) {
+	*out = *in
+	in.CompletionTime.DeepCopyInto(&out.CompletionTime)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PostRunActionStatus.
+func (in *PostRunActionStatus) DeepCopy() *PostRunActionStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(PostRunActionStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PrometheusSpec) DeepCopyInto(out *PrometheusSpec) {
+	*out = *in
+	if in.Port != nil {
+		in, out := &in.Port, &out.Port
+		*out = new(int32)
+		**out = **in
+	}
+	if in.PortName != nil {
+		in, out := &in.PortName, &out.PortName
+		*out = new(string)
+		**out = **in
+	}
+	if in.ConfigFile != nil {
+		in, out := &in.ConfigFile, &out.ConfigFile
+		*out = new(string)
+		**out = **in
+	}
+	if in.Configuration != nil {
+		in, out := &in.Configuration, &out.Configuration
+		*out = new(string)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PrometheusSpec.
+func (in *PrometheusSpec) DeepCopy() *PrometheusSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(PrometheusSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ResourceDependency) DeepCopyInto(out *ResourceDependency) {
+	*out = *in
+	if in.Condition != nil {
+		in, out := &in.Condition, &out.Condition
+		*out = new(string)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ResourceDependency.
+func (in *ResourceDependency) DeepCopy() *ResourceDependency {
+	if in == nil {
+		return nil
+	}
+	out := new(ResourceDependency)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RestartPolicy) DeepCopyInto(out *RestartPolicy) {
+	*out = *in
+	if in.OnSubmissionFailureRetries != nil {
+		in, out := &in.OnSubmissionFailureRetries, &out.OnSubmissionFailureRetries
+		*out = new(int32)
+		**out = **in
+	}
+	if in.OnFailureRetries != nil {
+		in, out := &in.OnFailureRetries, &out.OnFailureRetries
+		*out = new(int32)
+		**out = **in
+	}
+	if in.OnSubmissionFailureRetryInterval != nil {
+		in, out := &in.OnSubmissionFailureRetryInterval, &out.OnSubmissionFailureRetryInterval
+		*out = new(int64)
+		**out = **in
+	}
+	if in.OnFailureRetryInterval != nil {
+		in, out := &in.OnFailureRetryInterval, &out.OnFailureRetryInterval
+		*out = new(int64)
+		**out = **in
+	}
+	if in.Backoff != nil {
+		in, out := &in.Backoff, &out.Backoff
+		*out = new(RetryBackoff)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RestartPolicy.
+func (in *RestartPolicy) DeepCopy() *RestartPolicy {
+	if in == nil {
+		return nil
+	}
+	out := new(RestartPolicy)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RetryBackoff) DeepCopyInto(out *RetryBackoff) {
+	*out = *in
+	if in.Multiplier != nil {
+		in, out := &in.Multiplier, &out.Multiplier
+		*out = new(string)
+		**out = **in
+	}
+	if in.MaxIntervalSeconds != nil {
+		in, out := &in.MaxIntervalSeconds, &out.MaxIntervalSeconds
+		*out = new(int64)
+		**out = **in
+	}
+	if in.MaxDurationSeconds != nil {
+		in, out := &in.MaxDurationSeconds, &out.MaxDurationSeconds
+		*out = new(int64)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RetryBackoff.
+func (in *RetryBackoff) DeepCopy() *RetryBackoff {
+	if in == nil {
+		return nil
+	}
+	out := new(RetryBackoff)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SLASpec) DeepCopyInto(out *SLASpec) {
+	*out = *in
+	if in.MaxPendingDuration != nil {
+		in, out := &in.MaxPendingDuration, &out.MaxPendingDuration
+		*out = new(string)
+		**out = **in
+	}
+	if in.MaxRunDuration != nil {
+		in, out := &in.MaxRunDuration, &out.MaxRunDuration
+		*out = new(string)
+		**out = **in
+	}
+	if in.ExpectedCompletionBy != nil {
+		in, out := &in.ExpectedCompletionBy, &out.ExpectedCompletionBy
+		*out = new(string)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SLASpec.
+func (in *SLASpec) DeepCopy() *SLASpec {
+	if in == nil {
+		return nil
+	}
+	out := new(SLASpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SLAViolation) DeepCopyInto(out *SLAViolation) {
+	*out = *in
+	in.Time.DeepCopyInto(&out.Time)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SLAViolation.
+func (in *SLAViolation) DeepCopy() *SLAViolation {
+	if in == nil {
+		return nil
+	}
+	out := new(SLAViolation)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ScheduledSparkApplication) DeepCopyInto(out *ScheduledSparkApplication) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ScheduledSparkApplication.
+func (in *ScheduledSparkApplication) DeepCopy() *ScheduledSparkApplication {
+	if in == nil {
+		return nil
+	}
+	out := new(ScheduledSparkApplication)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *ScheduledSparkApplication) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ScheduledSparkApplicationList) DeepCopyInto(out *ScheduledSparkApplicationList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]ScheduledSparkApplication, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ScheduledSparkApplicationList.
+func (in *ScheduledSparkApplicationList) DeepCopy() *ScheduledSparkApplicationList {
+	if in == nil {
+		return nil
+	}
+	out := new(ScheduledSparkApplicationList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *ScheduledSparkApplicationList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ScheduledSparkApplicationSpec) DeepCopyInto(out *ScheduledSparkApplicationSpec) {
+	*out = *in
+	if in.AdditionalSchedules != nil {
+		in, out := &in.AdditionalSchedules, &out.AdditionalSchedules
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.TimeZone != nil {
+		in, out := &in.TimeZone, &out.TimeZone
+		*out = new(string)
+		**out = **in
+	}
+	if in.ExclusionCalendar != nil {
+		in, out := &in.ExclusionCalendar, &out.ExclusionCalendar
+		*out = new(string)
+		**out = **in
+	}
+	in.Template.DeepCopyInto(&out.Template)
+	if in.Suspend != nil {
+		in, out := &in.Suspend, &out.Suspend
+		*out = new(bool)
+		**out = **in
+	}
+	if in.SuccessfulRunHistoryLimit != nil {
+		in, out := &in.SuccessfulRunHistoryLimit, &out.SuccessfulRunHistoryLimit
+		*out = new(int32)
+		**out = **in
+	}
+	if in.FailedRunHistoryLimit != nil {
+		in, out := &in.FailedRunHistoryLimit, &out.FailedRunHistoryLimit
+		*out = new(int32)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ScheduledSparkApplicationSpec.
+func (in *ScheduledSparkApplicationSpec) DeepCopy() *ScheduledSparkApplicationSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(ScheduledSparkApplicationSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ScheduledSparkApplicationStatus) DeepCopyInto(out *ScheduledSparkApplicationStatus) {
+	*out = *in
+	in.LastRun.DeepCopyInto(&out.LastRun)
+	in.NextRun.DeepCopyInto(&out.NextRun)
+	if in.PastSuccessfulRunNames != nil {
+		in, out := &in.PastSuccessfulRunNames, &out.PastSuccessfulRunNames
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.PastFailedRunNames != nil {
+		in, out := &in.PastFailedRunNames, &out.PastFailedRunNames
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ScheduledSparkApplicationStatus.
+func (in *ScheduledSparkApplicationStatus) DeepCopy() *ScheduledSparkApplicationStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(ScheduledSparkApplicationStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SecretInfo) DeepCopyInto(out *SecretInfo) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SecretInfo.
+func (in *SecretInfo) DeepCopy() *SecretInfo {
+	if in == nil {
+		return nil
+	}
+	out := new(SecretInfo)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SecretRotationSpec) DeepCopyInto(out *SecretRotationSpec) {
+	*out = *in
+	if in.BatchSize != nil {
+		in, out := &in.BatchSize, &out.BatchSize
+		*out = new(int32)
+		**out = **in
+	}
+	if in.BatchIntervalSeconds != nil {
+		in, out := &in.BatchIntervalSeconds, &out.BatchIntervalSeconds
+		*out = new(int64)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SecretRotationSpec.
+func (in *SecretRotationSpec) DeepCopy() *SecretRotationSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(SecretRotationSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SecretRotationStatus) DeepCopyInto(out *SecretRotationStatus) {
+	*out = *in
+	if in.RolloutStartTime != nil {
+		in, out := &in.RolloutStartTime, &out.RolloutStartTime
+		*out = (*in).DeepCopy()
+	}
+	if in.LastBatchTime != nil {
+		in, out := &in.LastBatchTime, &out.LastBatchTime
+		*out = (*in).DeepCopy()
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SecretRotationStatus.
+func (in *SecretRotationStatus) DeepCopy() *SecretRotationStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(SecretRotationStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SparkApplication) DeepCopyInto(out *SparkApplication) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SparkApplication.
+func (in *SparkApplication) DeepCopy() *SparkApplication {
+	if in == nil {
+		return nil
+	}
+	out := new(SparkApplication)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *SparkApplication) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SparkApplicationGroup) DeepCopyInto(out *SparkApplicationGroup) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SparkApplicationGroup.
+func (in *SparkApplicationGroup) DeepCopy() *SparkApplicationGroup {
+	if in == nil {
+		return nil
+	}
+	out := new(SparkApplicationGroup)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *SparkApplicationGroup) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
 	}
 	return nil
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *ScheduledSparkApplicationList) DeepCopyInto(out *ScheduledSparkApplicationList) {
+func (in *SparkApplicationGroupList) DeepCopyInto(out *SparkApplicationGroupList) {
 	*out = *in
 	out.TypeMeta = in.TypeMeta
 	in.ListMeta.DeepCopyInto(&out.ListMeta)
 	if in.Items != nil {
 		in, out := &in.Items, &out.Items
-		*out = make([]ScheduledSparkApplication, len(*in))
+		*out = make([]SparkApplicationGroup, len(*in))
 		for i := range *in {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ScheduledSparkApplicationList.
-func (in *ScheduledSparkApplicationList) DeepCopy() *ScheduledSparkApplicationList {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SparkApplicationGroupList.
+func (in *SparkApplicationGroupList) DeepCopy() *SparkApplicationGroupList {
 	if in == nil {
 		return nil
 	}
-	out := new(ScheduledSparkApplicationList)
+	out := new(SparkApplicationGroupList)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
-func (in *ScheduledSparkApplicationList) DeepCopyObject() runtime.Object {
+func (in *SparkApplicationGroupList) DeepCopyObject() runtime.Object {
 	if c := in.DeepCopy(); c != nil {
 		return c
 	}
@@ -567,105 +1425,95 @@ func (in *ScheduledSparkApplicationList) DeepCopyObject() runtime.Object {
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *ScheduledSparkApplicationSpec) DeepCopyInto(out *ScheduledSparkApplicationSpec) {
+func (in *SparkApplicationGroupMember) DeepCopyInto(out *SparkApplicationGroupMember) {
 	*out = *in
 	in.Template.DeepCopyInto(&out.Template)
-	if in.Suspend != nil {
-		in, out := &in.Suspend, &out.Suspend
-		*out = new(bool)
-		**out = **in
-	}
-	if in.SuccessfulRunHistoryLimit != nil {
-		in, out := &in.SuccessfulRunHistoryLimit, &out.SuccessfulRunHistoryLimit
-		*out = new(int32)
-		**out = **in
-	}
-	if in.FailedRunHistoryLimit != nil {
-		in, out := &in.FailedRunHistoryLimit, &out.FailedRunHistoryLimit
-		*out = new(int32)
-		**out = **in
-	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ScheduledSparkApplicationSpec.
-func (in *ScheduledSparkApplicationSpec) DeepCopy() *ScheduledSparkApplicationSpec {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SparkApplicationGroupMember.
+func (in *SparkApplicationGroupMember) DeepCopy() *SparkApplicationGroupMember {
 	if in == nil {
 		return nil
 	}
-	out := new(ScheduledSparkApplicationSpec)
+	out := new(SparkApplicationGroupMember)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *ScheduledSparkApplicationStatus) DeepCopyInto(out *ScheduledSparkApplicationStatus) {
+func (in *SparkApplicationGroupMemberStatus) DeepCopyInto(out *SparkApplicationGroupMemberStatus) {
 	*out = *in
-	in.LastRun.DeepCopyInto(&out.LastRun)
-	in.NextRun.DeepCopyInto(&out.NextRun)
-	if in.PastSuccessfulRunNames != nil {
-		in, out := &in.PastSuccessfulRunNames, &out.PastSuccessfulRunNames
-		*out = make([]string, len(*in))
-		copy(*out, *in)
-	}
-	if in.PastFailedRunNames != nil {
-		in, out := &in.PastFailedRunNames, &out.PastFailedRunNames
-		*out = make([]string, len(*in))
-		copy(*out, *in)
-	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ScheduledSparkApplicationStatus.
-func (in *ScheduledSparkApplicationStatus) DeepCopy() *ScheduledSparkApplicationStatus {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SparkApplicationGroupMemberStatus.
+func (in *SparkApplicationGroupMemberStatus) DeepCopy() *SparkApplicationGroupMemberStatus {
 	if in == nil {
 		return nil
 	}
-	out := new(ScheduledSparkApplicationStatus)
+	out := new(SparkApplicationGroupMemberStatus)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *SecretInfo) DeepCopyInto(out *SecretInfo) {
+func (in *SparkApplicationGroupSpec) DeepCopyInto(out *SparkApplicationGroupSpec) {
 	*out = *in
+	if in.Labels != nil {
+		in, out := &in.Labels, &out.Labels
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.Applications != nil {
+		in, out := &in.Applications, &out.Applications
+		*out = make([]SparkApplicationGroupMember, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.Suspend != nil {
+		in, out := &in.Suspend, &out.Suspend
+		*out = new(bool)
+		**out = **in
+	}
+	if in.Kill != nil {
+		in, out := &in.Kill, &out.Kill
+		*out = new(bool)
+		**out = **in
+	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SecretInfo.
-func (in *SecretInfo) DeepCopy() *SecretInfo {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SparkApplicationGroupSpec.
+func (in *SparkApplicationGroupSpec) DeepCopy() *SparkApplicationGroupSpec {
 	if in == nil {
 		return nil
 	}
-	out := new(SecretInfo)
+	out := new(SparkApplicationGroupSpec)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *SparkApplication) DeepCopyInto(out *SparkApplication) {
+func (in *SparkApplicationGroupStatus) DeepCopyInto(out *SparkApplicationGroupStatus) {
 	*out = *in
-	out.TypeMeta = in.TypeMeta
-	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
-	in.Spec.DeepCopyInto(&out.Spec)
-	in.Status.DeepCopyInto(&out.Status)
+	if in.Members != nil {
+		in, out := &in.Members, &out.Members
+		*out = make([]SparkApplicationGroupMemberStatus, len(*in))
+		copy(*out, *in)
+	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SparkApplication.
-func (in *SparkApplication) DeepCopy() *SparkApplication {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SparkApplicationGroupStatus.
+func (in *SparkApplicationGroupStatus) DeepCopy() *SparkApplicationGroupStatus {
 	if in == nil {
 		return nil
 	}
-	out := new(SparkApplication)
+	out := new(SparkApplicationGroupStatus)
 	in.DeepCopyInto(out)
 	return out
 }
 
-// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
-func (in *SparkApplication) DeepCopyObject() runtime.Object {
-	if c := in.DeepCopy(); c != nil {
-		return c
-	}
-	return nil
-}
-
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *SparkApplicationList) DeepCopyInto(out *SparkApplicationList) {
 	*out = *in
@@ -706,6 +1554,11 @@ func (in *SparkApplicationSpec) DeepCopyInto(out *SparkApplicationSpec) {
 		*out = new(string)
 		**out = **in
 	}
+	if in.SubmissionEngine != nil {
+		in, out := &in.SubmissionEngine, &out.SubmissionEngine
+		*out = new(SubmissionEngine)
+		**out = **in
+	}
 	if in.Image != nil {
 		in, out := &in.Image, &out.Image
 		*out = new(string)
@@ -731,6 +1584,11 @@ func (in *SparkApplicationSpec) DeepCopyInto(out *SparkApplicationSpec) {
 		*out = new(string)
 		**out = **in
 	}
+	if in.MainApplicationSource != nil {
+		in, out := &in.MainApplicationSource, &out.MainApplicationSource
+		*out = new(MainApplicationSource)
+		(*in).DeepCopyInto(*out)
+	}
 	if in.Arguments != nil {
 		in, out := &in.Arguments, &out.Arguments
 		*out = make([]string, len(*in))
@@ -760,6 +1618,16 @@ func (in *SparkApplicationSpec) DeepCopyInto(out *SparkApplicationSpec) {
 		*out = new(string)
 		**out = **in
 	}
+	if in.MountConfProperties != nil {
+		in, out := &in.MountConfProperties, &out.MountConfProperties
+		*out = new(bool)
+		**out = **in
+	}
+	if in.Kerberos != nil {
+		in, out := &in.Kerberos, &out.Kerberos
+		*out = new(KerberosSpec)
+		(*in).DeepCopyInto(*out)
+	}
 	if in.Volumes != nil {
 		in, out := &in.Volumes, &out.Volumes
 		*out = make([]v1.Volume, len(*in))
@@ -767,6 +1635,11 @@ func (in *SparkApplicationSpec) DeepCopyInto(out *SparkApplicationSpec) {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
+	if in.LocalDirPolicy != nil {
+		in, out := &in.LocalDirPolicy, &out.LocalDirPolicy
+		*out = new(LocalDirPolicy)
+		(*in).DeepCopyInto(*out)
+	}
 	in.Driver.DeepCopyInto(&out.Driver)
 	in.Executor.DeepCopyInto(&out.Executor)
 	in.Deps.DeepCopyInto(&out.Deps)
@@ -835,6 +1708,55 @@ func (in *SparkApplicationSpec) DeepCopyInto(out *SparkApplicationSpec) {
 		*out = new(DynamicAllocation)
 		(*in).DeepCopyInto(*out)
 	}
+	if in.TemplateRef != nil {
+		in, out := &in.TemplateRef, &out.TemplateRef
+		*out = new(SparkApplicationTemplateReference)
+		**out = **in
+	}
+	if in.NetworkPorts != nil {
+		in, out := &in.NetworkPorts, &out.NetworkPorts
+		*out = new(NetworkPorts)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.ImagePrefetch != nil {
+		in, out := &in.ImagePrefetch, &out.ImagePrefetch
+		*out = new(ImagePrefetchSpec)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.SecretRotation != nil {
+		in, out := &in.SecretRotation, &out.SecretRotation
+		*out = new(SecretRotationSpec)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.PostRunActions != nil {
+		in, out := &in.PostRunActions, &out.PostRunActions
+		*out = make([]PostRunAction, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.WaitFor != nil {
+		in, out := &in.WaitFor, &out.WaitFor
+		*out = new(WaitForSpec)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.SLA != nil {
+		in, out := &in.SLA, &out.SLA
+		*out = new(SLASpec)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Outputs != nil {
+		in, out := &in.Outputs, &out.Outputs
+		*out = make([]Output, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.DisableDefaults != nil {
+		in, out := &in.DisableDefaults, &out.DisableDefaults
+		*out = make([]InjectedDefault, len(*in))
+		copy(*out, *in)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SparkApplicationSpec.
@@ -861,6 +1783,61 @@ func (in *SparkApplicationStatus) DeepCopyInto(out *SparkApplicationStatus) {
 			(*out)[key] = val
 		}
 	}
+	if in.ExecutorPlacement != nil {
+		in, out := &in.ExecutorPlacement, &out.ExecutorPlacement
+		*out = make(map[string]ExecutorPlacement, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.Conditions != nil {
+		in, out := &in.Conditions, &out.Conditions
+		*out = make([]metav1.Condition, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.SecretRotation != nil {
+		in, out := &in.SecretRotation, &out.SecretRotation
+		*out = new(SecretRotationStatus)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.PostRunActions != nil {
+		in, out := &in.PostRunActions, &out.PostRunActions
+		*out = make([]PostRunActionStatus, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.SLAViolations != nil {
+		in, out := &in.SLAViolations, &out.SLAViolations
+		*out = make([]SLAViolation, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.DriverProgress != nil {
+		in, out := &in.DriverProgress, &out.DriverProgress
+		*out = new(DriverProgress)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Outputs != nil {
+		in, out := &in.Outputs, &out.Outputs
+		*out = make([]OutputStatus, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.InjectedDefaults != nil {
+		in, out := &in.InjectedDefaults, &out.InjectedDefaults
+		*out = new(InjectedDefaultsStatus)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.ExecutorQuotaShortfall != nil {
+		in, out := &in.ExecutorQuotaShortfall, &out.ExecutorQuotaShortfall
+		*out = new(ExecutorQuotaShortfall)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SparkApplicationStatus.
@@ -873,6 +1850,95 @@ func (in *SparkApplicationStatus) DeepCopy() *SparkApplicationStatus {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SparkApplicationTemplate) DeepCopyInto(out *SparkApplicationTemplate) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SparkApplicationTemplate.
+func (in *SparkApplicationTemplate) DeepCopy() *SparkApplicationTemplate {
+	if in == nil {
+		return nil
+	}
+	out := new(SparkApplicationTemplate)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *SparkApplicationTemplate) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SparkApplicationTemplateList) DeepCopyInto(out *SparkApplicationTemplateList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]SparkApplicationTemplate, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SparkApplicationTemplateList.
+func (in *SparkApplicationTemplateList) DeepCopy() *SparkApplicationTemplateList {
+	if in == nil {
+		return nil
+	}
+	out := new(SparkApplicationTemplateList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *SparkApplicationTemplateList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SparkApplicationTemplateReference) DeepCopyInto(out *SparkApplicationTemplateReference) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SparkApplicationTemplateReference.
+func (in *SparkApplicationTemplateReference) DeepCopy() *SparkApplicationTemplateReference {
+	if in == nil {
+		return nil
+	}
+	out := new(SparkApplicationTemplateReference)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SparkApplicationTemplateSpec) DeepCopyInto(out *SparkApplicationTemplateSpec) {
+	*out = *in
+	in.Template.DeepCopyInto(&out.Template)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SparkApplicationTemplateSpec.
+func (in *SparkApplicationTemplateSpec) DeepCopy() *SparkApplicationTemplateSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(SparkApplicationTemplateSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *SparkPodSpec) DeepCopyInto(out *SparkPodSpec) {
 	*out = *in
@@ -881,6 +1947,11 @@ func (in *SparkPodSpec) DeepCopyInto(out *SparkPodSpec) {
 		*out = new(v1.PodTemplateSpec)
 		(*in).DeepCopyInto(*out)
 	}
+	if in.TemplateConfigMap != nil {
+		in, out := &in.TemplateConfigMap, &out.TemplateConfigMap
+		*out = new(NameKey)
+		**out = **in
+	}
 	if in.Cores != nil {
 		in, out := &in.Cores, &out.Cores
 		*out = new(int32)
@@ -906,6 +1977,16 @@ func (in *SparkPodSpec) DeepCopyInto(out *SparkPodSpec) {
 		*out = new(GPUSpec)
 		**out = **in
 	}
+	if in.EphemeralStorage != nil {
+		in, out := &in.EphemeralStorage, &out.EphemeralStorage
+		*out = new(string)
+		**out = **in
+	}
+	if in.EphemeralStorageLimit != nil {
+		in, out := &in.EphemeralStorageLimit, &out.EphemeralStorageLimit
+		*out = new(string)
+		**out = **in
+	}
 	if in.Image != nil {
 		in, out := &in.Image, &out.Image
 		*out = new(string)
@@ -992,11 +2073,36 @@ func (in *SparkPodSpec) DeepCopyInto(out *SparkPodSpec) {
 		*out = new(v1.SecurityContext)
 		(*in).DeepCopyInto(*out)
 	}
+	if in.LivenessProbe != nil {
+		in, out := &in.LivenessProbe, &out.LivenessProbe
+		*out = new(v1.Probe)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.ReadinessProbe != nil {
+		in, out := &in.ReadinessProbe, &out.ReadinessProbe
+		*out = new(v1.Probe)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.StartupProbe != nil {
+		in, out := &in.StartupProbe, &out.StartupProbe
+		*out = new(v1.Probe)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Lifecycle != nil {
+		in, out := &in.Lifecycle, &out.Lifecycle
+		*out = new(v1.Lifecycle)
+		(*in).DeepCopyInto(*out)
+	}
 	if in.SchedulerName != nil {
 		in, out := &in.SchedulerName, &out.SchedulerName
 		*out = new(string)
 		**out = **in
 	}
+	if in.RuntimeClassName != nil {
+		in, out := &in.RuntimeClassName, &out.RuntimeClassName
+		*out = new(string)
+		**out = **in
+	}
 	if in.Sidecars != nil {
 		in, out := &in.Sidecars, &out.Sidecars
 		*out = make([]v1.Container, len(*in))
@@ -1004,6 +2110,11 @@ func (in *SparkPodSpec) DeepCopyInto(out *SparkPodSpec) {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
+	if in.NativeSidecars != nil {
+		in, out := &in.NativeSidecars, &out.NativeSidecars
+		*out = new(bool)
+		**out = **in
+	}
 	if in.InitContainers != nil {
 		in, out := &in.InitContainers, &out.InitContainers
 		*out = make([]v1.Container, len(*in))
@@ -1028,6 +2139,16 @@ func (in *SparkPodSpec) DeepCopyInto(out *SparkPodSpec) {
 		*out = new(v1.PodDNSConfig)
 		(*in).DeepCopyInto(*out)
 	}
+	if in.DNSPolicy != nil {
+		in, out := &in.DNSPolicy, &out.DNSPolicy
+		*out = new(v1.DNSPolicy)
+		**out = **in
+	}
+	if in.DNSSearchDomains != nil {
+		in, out := &in.DNSSearchDomains, &out.DNSSearchDomains
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
 	if in.TerminationGracePeriodSeconds != nil {
 		in, out := &in.TerminationGracePeriodSeconds, &out.TerminationGracePeriodSeconds
 		*out = new(int64)
@@ -1050,6 +2171,11 @@ func (in *SparkPodSpec) DeepCopyInto(out *SparkPodSpec) {
 		*out = new(bool)
 		**out = **in
 	}
+	if in.SchedulingGates != nil {
+		in, out := &in.SchedulingGates, &out.SchedulingGates
+		*out = make([]v1.PodSchedulingGate, len(*in))
+		copy(*out, *in)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SparkPodSpec.
@@ -1062,6 +2188,245 @@ func (in *SparkPodSpec) DeepCopy() *SparkPodSpec {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SparkSQLGateway) DeepCopyInto(out *SparkSQLGateway) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	out.Status = in.Status
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SparkSQLGateway.
+func (in *SparkSQLGateway) DeepCopy() *SparkSQLGateway {
+	if in == nil {
+		return nil
+	}
+	out := new(SparkSQLGateway)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *SparkSQLGateway) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SparkSQLGatewayIngress) DeepCopyInto(out *SparkSQLGatewayIngress) {
+	*out = *in
+	if in.IngressClassName != nil {
+		in, out := &in.IngressClassName, &out.IngressClassName
+		*out = new(string)
+		**out = **in
+	}
+	if in.Annotations != nil {
+		in, out := &in.Annotations, &out.Annotations
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.TLS != nil {
+		in, out := &in.TLS, &out.TLS
+		*out = make([]networkingv1.IngressTLS, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SparkSQLGatewayIngress.
+func (in *SparkSQLGatewayIngress) DeepCopy() *SparkSQLGatewayIngress {
+	if in == nil {
+		return nil
+	}
+	out := new(SparkSQLGatewayIngress)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SparkSQLGatewayList) DeepCopyInto(out *SparkSQLGatewayList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]SparkSQLGateway, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SparkSQLGatewayList.
+func (in *SparkSQLGatewayList) DeepCopy() *SparkSQLGatewayList {
+	if in == nil {
+		return nil
+	}
+	out := new(SparkSQLGatewayList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *SparkSQLGatewayList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SparkSQLGatewaySpec) DeepCopyInto(out *SparkSQLGatewaySpec) {
+	*out = *in
+	if in.ImagePullPolicy != nil {
+		in, out := &in.ImagePullPolicy, &out.ImagePullPolicy
+		*out = new(v1.PullPolicy)
+		**out = **in
+	}
+	if in.ImagePullSecrets != nil {
+		in, out := &in.ImagePullSecrets, &out.ImagePullSecrets
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Replicas != nil {
+		in, out := &in.Replicas, &out.Replicas
+		*out = new(int32)
+		**out = **in
+	}
+	if in.ServiceAccount != nil {
+		in, out := &in.ServiceAccount, &out.ServiceAccount
+		*out = new(string)
+		**out = **in
+	}
+	if in.Resources != nil {
+		in, out := &in.Resources, &out.Resources
+		*out = new(v1.ResourceRequirements)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.SparkConf != nil {
+		in, out := &in.SparkConf, &out.SparkConf
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.DynamicAllocation != nil {
+		in, out := &in.DynamicAllocation, &out.DynamicAllocation
+		*out = new(DynamicAllocation)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Port != nil {
+		in, out := &in.Port, &out.Port
+		*out = new(int32)
+		**out = **in
+	}
+	if in.ServiceType != nil {
+		in, out := &in.ServiceType, &out.ServiceType
+		*out = new(v1.ServiceType)
+		**out = **in
+	}
+	if in.AuthSecretName != nil {
+		in, out := &in.AuthSecretName, &out.AuthSecretName
+		*out = new(string)
+		**out = **in
+	}
+	if in.Ingress != nil {
+		in, out := &in.Ingress, &out.Ingress
+		*out = new(SparkSQLGatewayIngress)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.NodeSelector != nil {
+		in, out := &in.NodeSelector, &out.NodeSelector
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.Tolerations != nil {
+		in, out := &in.Tolerations, &out.Tolerations
+		*out = make([]v1.Toleration, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SparkSQLGatewaySpec.
+func (in *SparkSQLGatewaySpec) DeepCopy() *SparkSQLGatewaySpec {
+	if in == nil {
+		return nil
+	}
+	out := new(SparkSQLGatewaySpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SparkSQLGatewayStatus) DeepCopyInto(out *SparkSQLGatewayStatus) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SparkSQLGatewayStatus.
+func (in *SparkSQLGatewayStatus) DeepCopy() *SparkSQLGatewayStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(SparkSQLGatewayStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SparkUIAuth) DeepCopyInto(out *SparkUIAuth) {
+	*out = *in
+	if in.BasicAuth != nil {
+		in, out := &in.BasicAuth, &out.BasicAuth
+		*out = new(SparkUIBasicAuth)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.OAuth2Proxy != nil {
+		in, out := &in.OAuth2Proxy, &out.OAuth2Proxy
+		*out = new(SparkUIOAuth2Proxy)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SparkUIAuth.
+func (in *SparkUIAuth) DeepCopy() *SparkUIAuth {
+	if in == nil {
+		return nil
+	}
+	out := new(SparkUIAuth)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SparkUIBasicAuth) DeepCopyInto(out *SparkUIBasicAuth) {
+	*out = *in
+	if in.Realm != nil {
+		in, out := &in.Realm, &out.Realm
+		*out = new(string)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SparkUIBasicAuth.
+func (in *SparkUIBasicAuth) DeepCopy() *SparkUIBasicAuth {
+	if in == nil {
+		return nil
+	}
+	out := new(SparkUIBasicAuth)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *SparkUIConfiguration) DeepCopyInto(out *SparkUIConfiguration) {
 	*out = *in
@@ -1108,6 +2473,11 @@ func (in *SparkUIConfiguration) DeepCopyInto(out *SparkUIConfiguration) {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
+	if in.Auth != nil {
+		in, out := &in.Auth, &out.Auth
+		*out = new(SparkUIAuth)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SparkUIConfiguration.
@@ -1119,3 +2489,80 @@ func (in *SparkUIConfiguration) DeepCopy() *SparkUIConfiguration {
 	in.DeepCopyInto(out)
 	return out
 }
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SparkUIOAuth2Proxy) DeepCopyInto(out *SparkUIOAuth2Proxy) {
+	*out = *in
+	if in.Image != nil {
+		in, out := &in.Image, &out.Image
+		*out = new(string)
+		**out = **in
+	}
+	if in.Port != nil {
+		in, out := &in.Port, &out.Port
+		*out = new(int32)
+		**out = **in
+	}
+	if in.Args != nil {
+		in, out := &in.Args, &out.Args
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SparkUIOAuth2Proxy.
+func (in *SparkUIOAuth2Proxy) DeepCopy() *SparkUIOAuth2Proxy {
+	if in == nil {
+		return nil
+	}
+	out := new(SparkUIOAuth2Proxy)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *WaitForSpec) DeepCopyInto(out *WaitForSpec) {
+	*out = *in
+	if in.Resources != nil {
+		in, out := &in.Resources, &out.Resources
+		*out = make([]ResourceDependency, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.TimeoutSeconds != nil {
+		in, out := &in.TimeoutSeconds, &out.TimeoutSeconds
+		*out = new(int32)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new WaitForSpec.
+func (in *WaitForSpec) DeepCopy() *WaitForSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(WaitForSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *WebhookPostRunAction) DeepCopyInto(out *WebhookPostRunAction) {
+	*out = *in
+	if in.TimeoutSeconds != nil {
+		in, out := &in.TimeoutSeconds, &out.TimeoutSeconds
+		*out = new(int32)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new WebhookPostRunAction.
+func (in *WebhookPostRunAction) DeepCopy() *WebhookPostRunAction {
+	if in == nil {
+		return nil
+	}
+	out := new(WebhookPostRunAction)
+	in.DeepCopyInto(out)
+	return out
+}
@@ -173,9 +173,30 @@ Create the role policy rules for the controller in every Spark job namespace
   verbs:
   - get
   - create
+  - update
   - delete
   - list
   - watch
+- apiGroups:
+  - apps
+  resources:
+  - deployments
+  - statefulsets
+  verbs:
+  - get
+- apiGroups:
+  - batch
+  resources:
+  - jobs
+  verbs:
+  - get
+{{- if .Values.controller.submitterJob.enable }}
+  - list
+  - watch
+  - create
+  - delete
+  - deletecollection
+{{- end }}
 - apiGroups:
   - sparkoperator.k8s.io
   resources:
@@ -200,6 +221,209 @@ Create the role policy rules for the controller in every Spark job namespace
   - get
   - update
   - patch
+{{- if .Values.controller.resourceReservation.enable }}
+- apiGroups:
+  - ""
+  resources:
+  - resourcequotas
+  verbs:
+  - get
+  - list
+  - watch
+  - create
+  - update
+  - delete
+{{- end }}
+{{- if or .Values.controller.query.enable .Values.controller.executorQuotaFailureDetection.enable }}
+- apiGroups:
+  - ""
+  resources:
+  - resourcequotas
+  verbs:
+  - list
+{{- end }}
+{{- if .Values.controller.karpenterDisruptionProtection.enable }}
+- apiGroups:
+  - policy
+  resources:
+  - poddisruptionbudgets
+  verbs:
+  - get
+  - create
+  - update
+{{- end }}
+{{- with .Values.controller.impersonation.serviceAccount }}
+- apiGroups:
+  - ""
+  resources:
+  - serviceaccounts
+  resourceNames:
+  - {{ . }}
+  verbs:
+  - impersonate
+- apiGroups:
+  - ""
+  resources:
+  - serviceaccounts/token
+  resourceNames:
+  - {{ . }}
+  verbs:
+  - create
+{{- end }}
+{{- if .Values.controller.imagePrefetch.enable }}
+- apiGroups:
+  - apps
+  resources:
+  - daemonsets
+  verbs:
+  - get
+  - create
+  - delete
+{{- end }}
+{{- if .Values.controller.sparkAuthSecret.enable }}
+- apiGroups:
+  - ""
+  resources:
+  - secrets
+  verbs:
+  - get
+  - list
+  - watch
+  - create
+{{- end }}
+{{- if .Values.controller.uiIngress.enable }}
+- apiGroups:
+  - ""
+  resources:
+  - secrets
+  verbs:
+  - get
+  - create
+  - update
+{{- end }}
+{{- if .Values.controller.secretRotation.enable }}
+- apiGroups:
+  - ""
+  resources:
+  - secrets
+  verbs:
+  - get
+  - update
+{{- end }}
+{{- if .Values.controller.namespaceTerminationHandling.enable }}
+- apiGroups:
+  - ""
+  resources:
+  - namespaces
+  verbs:
+  - get
+  - list
+  - watch
+{{- end }}
+{{- if .Values.controller.waitingForDependencies.enable }}
+- apiGroups:
+  - ""
+  resources:
+  - secrets
+  verbs:
+  - get
+- apiGroups:
+  - ""
+  resources:
+  - serviceaccounts
+  verbs:
+  - get
+  - list
+  - watch
+{{- end }}
+{{- if .Values.controller.archive.url }}
+- apiGroups:
+  - ""
+  resources:
+  - pods/log
+  verbs:
+  - get
+{{- end }}
+{{- if .Values.controller.driverPVCRBAC.enable }}
+- apiGroups:
+  - ""
+  resources:
+  - persistentvolumeclaims
+  verbs:
+  - deletecollection
+- apiGroups:
+  - rbac.authorization.k8s.io
+  resources:
+  - roles
+  - rolebindings
+  verbs:
+  - get
+  - list
+  - watch
+  - create
+  - update
+{{- end }}
+{{- if .Values.controller.applicationGroup.enable }}
+- apiGroups:
+  - sparkoperator.k8s.io
+  resources:
+  - sparkapplicationgroups
+  verbs:
+  - get
+  - list
+  - watch
+  - update
+  - patch
+- apiGroups:
+  - sparkoperator.k8s.io
+  resources:
+  - sparkapplicationgroups/status
+  - sparkapplicationgroups/finalizers
+  verbs:
+  - get
+  - update
+  - patch
+{{- end }}
+{{- if .Values.controller.sqlGateway.enable }}
+- apiGroups:
+  - apps
+  resources:
+  - deployments
+  verbs:
+  - get
+  - list
+  - watch
+  - create
+  - update
+  - patch
+  - delete
+- apiGroups:
+  - networking.k8s.io
+  resources:
+  - ingresses
+  verbs:
+  - update
+  - patch
+- apiGroups:
+  - sparkoperator.k8s.io
+  resources:
+  - sparksqlgateways
+  verbs:
+  - get
+  - list
+  - watch
+  - update
+  - patch
+- apiGroups:
+  - sparkoperator.k8s.io
+  resources:
+  - sparksqlgateways/status
+  - sparksqlgateways/finalizers
+  verbs:
+  - get
+  - update
+  - patch
+{{- end }}
 {{- if .Values.controller.batchScheduler.enable }}
 {{/* required for the `volcano` batch scheduler */}}
 - apiGroups:
@@ -210,5 +434,17 @@ Create the role policy rules for the controller in every Spark job namespace
   - podgroups
   verbs:
   - "*"
+{{- if .Values.controller.batchScheduler.kubeSchedulerNames }}
+{{/* required for the kube-schedulers running the coscheduling plugin */}}
+- apiGroups:
+  - scheduling.x-k8s.io
+  resources:
+  - podgroups
+  verbs:
+  - get
+  - create
+  - update
+  - delete
+{{- end }}
 {{- end }}
 {{- end -}}
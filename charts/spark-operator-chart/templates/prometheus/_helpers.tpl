@@ -20,3 +20,25 @@ Create the name of pod monitor
 {{- define "spark-operator.prometheus.podMonitorName" -}}
 {{- include "spark-operator.fullname" . }}-podmonitor
 {{- end -}}
+
+{{/*
+Create the name of service monitor
+*/}}
+{{- define "spark-operator.prometheus.serviceMonitorName" -}}
+{{- include "spark-operator.fullname" . }}-servicemonitor
+{{- end -}}
+
+{{/*
+Create the name of the metrics service
+*/}}
+{{- define "spark-operator.prometheus.metricsServiceName" -}}
+{{- include "spark-operator.fullname" . }}-metrics
+{{- end -}}
+
+{{/*
+Selector labels of the metrics service
+*/}}
+{{- define "spark-operator.prometheus.metricsServiceSelectorLabels" -}}
+{{ include "spark-operator.selectorLabels" . }}
+app.kubernetes.io/component: metrics
+{{- end -}}
@@ -119,6 +119,60 @@ Create the name of the lease resource to be used by leader election
 {{ include "spark-operator.webhook.name" . }}-lock
 {{- end -}}
 
+{{/*
+Create the namespace selector of the webhooks from `webhook.namespaceSelector`, restricted to the Spark job namespaces if any
+*/}}
+{{- define "spark-operator.webhook.namespaceSelector" -}}
+{{- $matchExpressions := .Values.webhook.namespaceSelector.matchExpressions | default list }}
+{{- with .Values.spark.jobNamespaces }}
+{{- if not (has "" .) }}
+{{- $matchExpressions = append $matchExpressions (dict "key" "kubernetes.io/metadata.name" "operator" "In" "values" .) }}
+{{- end }}
+{{- end }}
+{{- with .Values.webhook.namespaceSelector.matchLabels }}
+matchLabels:
+  {{- toYaml . | nindent 2 }}
+{{- end }}
+{{- with $matchExpressions }}
+matchExpressions:
+{{- toYaml . | nindent 0 }}
+{{- end }}
+{{- end -}}
+
+{{/*
+Create the object selector of the pod webhook from `webhook.objectSelector`, restricted to pods launched by the operator
+*/}}
+{{- define "spark-operator.webhook.objectSelector" -}}
+{{- $matchLabels := merge (dict "sparkoperator.k8s.io/launched-by-spark-operator" "true") (.Values.webhook.objectSelector.matchLabels | default dict) }}
+matchLabels:
+  {{- toYaml $matchLabels | nindent 2 }}
+{{- with .Values.webhook.objectSelector.matchExpressions }}
+matchExpressions:
+{{- toYaml . | nindent 0 }}
+{{- end }}
+{{- end -}}
+
+{{/*
+Create the name of the config map holding the Spark pod defaults of the webhook
+*/}}
+{{- define "spark-operator.webhook.podDefaultsName" -}}
+{{ include "spark-operator.webhook.name" . }}-pod-defaults
+{{- end -}}
+
+{{/*
+Create the name of the config map holding the SparkApplication defaults of the webhook
+*/}}
+{{- define "spark-operator.webhook.applicationDefaultsName" -}}
+{{ include "spark-operator.webhook.name" . }}-application-defaults
+{{- end -}}
+
+{{/*
+Create the name of the config map holding the Spark conf policy of the webhook
+*/}}
+{{- define "spark-operator.webhook.sparkConfPolicyName" -}}
+{{ include "spark-operator.webhook.name" . }}-spark-conf-policy
+{{- end -}}
+
 {{/*
 Create the name of the pod disruption budget to be used by webhook
 */}}
@@ -163,4 +217,12 @@ Create the role policy rules for the webhook in every Spark job namespace
   - update
   - patch
   - delete
+- apiGroups:
+  - sparkoperator.k8s.io
+  resources:
+  - sparkapplicationtemplates
+  verbs:
+  - get
+  - list
+  - watch
 {{- end -}}
\ No newline at end of file
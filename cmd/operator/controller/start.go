@@ -17,10 +17,13 @@ limitations under the License.
 package controller
 
 import (
+	"context"
 	"crypto/tls"
 	"flag"
+	"fmt"
 	"os"
 	"slices"
+	"strings"
 	"time"
 
 	// Import all Kubernetes client auth plugins (e.g. Azure, GCP, OIDC, etc.)
@@ -32,12 +35,15 @@ import (
 	"go.uber.org/zap"
 	"go.uber.org/zap/zapcore"
 	"golang.org/x/time/rate"
+	batchv1 "k8s.io/api/batch/v1"
 	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/labels"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/selection"
 	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
 	"k8s.io/client-go/kubernetes"
 	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	toolscache "k8s.io/client-go/tools/cache"
 	"k8s.io/utils/clock"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/cache"
@@ -52,14 +58,22 @@ import (
 	sparkoperator "github.com/kubeflow/spark-operator"
 	"github.com/kubeflow/spark-operator/api/v1beta1"
 	"github.com/kubeflow/spark-operator/api/v1beta2"
+	"github.com/kubeflow/spark-operator/internal/chaos"
+	"github.com/kubeflow/spark-operator/internal/controller/recommendation"
 	"github.com/kubeflow/spark-operator/internal/controller/scheduledsparkapplication"
 	"github.com/kubeflow/spark-operator/internal/controller/sparkapplication"
+	"github.com/kubeflow/spark-operator/internal/controller/sparkapplicationgroup"
+	"github.com/kubeflow/spark-operator/internal/controller/sparksqlgateway"
 	"github.com/kubeflow/spark-operator/internal/metrics"
+	"github.com/kubeflow/spark-operator/internal/query"
 	"github.com/kubeflow/spark-operator/internal/scheduler"
 	"github.com/kubeflow/spark-operator/internal/scheduler/kubescheduler"
 	"github.com/kubeflow/spark-operator/internal/scheduler/volcano"
 	"github.com/kubeflow/spark-operator/internal/scheduler/yunikorn"
+	"github.com/kubeflow/spark-operator/internal/webhook"
+	"github.com/kubeflow/spark-operator/pkg/archive"
 	"github.com/kubeflow/spark-operator/pkg/common"
+	"github.com/kubeflow/spark-operator/pkg/statestore"
 	"github.com/kubeflow/spark-operator/pkg/util"
 	// +kubebuilder:scaffold:imports
 )
@@ -69,14 +83,42 @@ var (
 	logger = ctrl.Log.WithName("")
 )
 
+// Names of the controllers that can be selected with --controllers.
+const (
+	sparkApplicationController          = "sparkapplication"
+	scheduledSparkApplicationController = "scheduledsparkapplication"
+	recommendationController            = "recommendation"
+	sparkApplicationGroupController     = "sparkapplicationgroup"
+	sparkSQLGatewayController           = "sparksqlgateway"
+)
+
+var knownControllers = []string{
+	sparkApplicationController,
+	scheduledSparkApplicationController,
+	recommendationController,
+	sparkApplicationGroupController,
+	sparkSQLGatewayController,
+}
+
 var (
 	namespaces []string
 
 	// Controller
+	controllers              []string
 	controllerThreads        int
 	cacheSyncTimeout         time.Duration
 	maxTrackedExecutorPerApp int
 
+	// Submission
+	maxConcurrentSubmissionsPerNamespace int
+
+	// State store
+	stateStoreURL string
+
+	// Cache
+	cacheListPageSize                int64
+	cacheTerminatedApplicationMaxAge time.Duration
+
 	//WorkQueue
 	workqueueRateLimiterBucketQPS  int
 	workqueueRateLimiterBucketSize int
@@ -102,6 +144,94 @@ var (
 
 	driverPodCreationGracePeriod time.Duration
 
+	// Executor pod deletion
+	executorDeletionBatchSize     int
+	executorDeletionBatchInterval time.Duration
+
+	// Executors on unreachable nodes
+	unreachableExecutorGracePeriod  time.Duration
+	forceDeleteUnreachableExecutors bool
+
+	// Driver RBAC
+	enableDriverPVCRBAC bool
+
+	// Driver pod validation
+	enableDriverPodValidation bool
+
+	// Executor placement tracking
+	enableExecutorPlacementTracking bool
+
+	// Submission engine
+	defaultSubmissionEngine string
+	enableSubmitterJob      bool
+	submitterJobImage       string
+	submitterJobTimeout     time.Duration
+	sparkHomes              map[string]string
+
+	// Executor launch pacing
+	maxExecutorAllocationBatchSize int
+
+	// Driver progress
+	driverProgressScrapeInterval time.Duration
+
+	// Impersonation
+	impersonateServiceAccount string
+
+	// Prometheus ConfigMap garbage collection
+	prometheusConfigMapGCInterval time.Duration
+
+	// Karpenter disruption protection
+	enableKarpenterDisruptionProtection bool
+
+	// Owner references of driver resources
+	disableOwnerReferences bool
+
+	// Resource reservation for dynamic allocation scale-ups
+	enableResourceReservation bool
+
+	// Executor quota failure detection
+	enableExecutorQuotaFailureDetection bool
+
+	// Executor image prefetch
+	enableImagePrefetch     bool
+	imagePrefetchPauseImage string
+
+	// Spark internal authentication and encryption
+	enableSparkAuthSecret bool
+
+	enableSecretRotation bool
+
+	enableNamespaceTerminationHandling bool
+
+	enableWaitingForDependencies bool
+
+	// Archival of terminated SparkApplications
+	archiveURL string
+
+	// Resource recommendation
+	enableResourceRecommendation bool
+	memoryIncreaseFactor         float64
+	recommendationHistoryLimit   int
+	recommendationHeadroom       float64
+	recommendationPrometheusURL  string
+
+	// SparkApplication groups
+	enableApplicationGroup bool
+
+	// Spark SQL gateway
+	enableSQLGateway bool
+
+	// Extension hooks
+	preSubmissionHookURLs  []string
+	postCompletionHookURLs []string
+	hookTimeout            time.Duration
+	hookFailurePolicy      string
+
+	applicationDefaultsFile string
+	sparkConfPolicyFile     string
+
+	postRunWebhookURLPrefixes []string
+
 	// Metrics
 	enableMetrics                 bool
 	metricsBindAddress            string
@@ -112,6 +242,7 @@ var (
 
 	healthProbeBindAddress string
 	pprofBindAddress       string
+	queryBindAddress       string
 	secureMetrics          bool
 	enableHTTP2            bool
 	development            bool
@@ -140,9 +271,20 @@ func NewStartCommand() *cobra.Command {
 		},
 	}
 
+	command.Flags().StringSliceVar(&controllers, "controllers", []string{"*"}, "A list of controllers to run. `*` runs all controllers, `foo` runs the controller named `foo` "+
+		"and `-foo` disables it. Controllers of optional features additionally require their features to be enabled. "+
+		fmt.Sprintf("Available controllers: %s.", strings.Join(knownControllers, ", ")))
 	command.Flags().IntVar(&controllerThreads, "controller-threads", 10, "Number of worker threads used by the SparkApplication controller.")
+	command.Flags().IntVar(&maxConcurrentSubmissionsPerNamespace, "max-concurrent-submissions-per-namespace", 0, "Maximum number of spark-submit processes running concurrently "+
+		"for the SparkApplications of a namespace, so that a single namespace cannot monopolize the submission throughput. Unlimited if set to 0.")
+	command.Flags().StringVar(&stateStoreURL, "state-store-url", "", "URL of the store persisting the bookkeeping of the submissions in flight and queued across restarts, "+
+		"either configmap://<namespace>/<name> or a bucket URL, e.g. s3://bucket?region=us-west-1&prefix=state/. Not persisted if empty.")
 	command.Flags().StringSliceVar(&namespaces, "namespaces", []string{}, "The Kubernetes namespace to manage. Will manage custom resource objects of the managed CRD types for the whole cluster if unset or contains empty string.")
 	command.Flags().DurationVar(&cacheSyncTimeout, "cache-sync-timeout", 30*time.Second, "Informer cache sync timeout.")
+	command.Flags().Int64Var(&cacheListPageSize, "cache-list-page-size", 0, "Number of objects listed per request during the initial sync of the informer caches. "+
+		"Lists are served from etcd in pages if set, instead of in a single response from the watch cache. Pagination is disabled if set to 0.")
+	command.Flags().DurationVar(&cacheTerminatedApplicationMaxAge, "cache-terminated-application-max-age", 0, "SparkApplications terminated longer ago than this are not cached, "+
+		"so they are neither reconciled nor cleaned up by the operator. All SparkApplications are cached if set to 0.")
 	command.Flags().IntVar(&maxTrackedExecutorPerApp, "max-tracked-executor-per-app", 1000, "The maximum number of tracked executors per SparkApplication.")
 
 	command.Flags().IntVar(&workqueueRateLimiterBucketQPS, "workqueue-ratelimiter-bucket-qps", 10, "QPS of the bucket rate of the workqueue.")
@@ -159,14 +301,113 @@ func NewStartCommand() *cobra.Command {
 
 	command.Flags().BoolVar(&enableLeaderElection, "leader-election", false, "Enable leader election for controller manager. "+
 		"Enabling this will ensure there is only one active controller manager.")
-	command.Flags().StringVar(&leaderElectionLockName, "leader-election-lock-name", "spark-operator-lock", "Name of the ConfigMap for leader election.")
-	command.Flags().StringVar(&leaderElectionLockNamespace, "leader-election-lock-namespace", "spark-operator", "Namespace in which to create the ConfigMap for leader election.")
-	command.Flags().DurationVar(&leaderElectionLeaseDuration, "leader-election-lease-duration", 15*time.Second, "Leader election lease duration.")
-	command.Flags().DurationVar(&leaderElectionRenewDeadline, "leader-election-renew-deadline", 14*time.Second, "Leader election renew deadline.")
-	command.Flags().DurationVar(&leaderElectionRetryPeriod, "leader-election-retry-period", 4*time.Second, "Leader election retry period.")
+	command.Flags().StringVar(&leaderElectionLockName, "leader-election-lock-name", "spark-operator-lock", "Name of the Lease for leader election.")
+	command.Flags().StringVar(&leaderElectionLockNamespace, "leader-election-lock-namespace", "spark-operator", "Namespace in which to create the Lease for leader election.")
+	command.Flags().DurationVar(&leaderElectionLeaseDuration, "leader-election-lease-duration", 15*time.Second, "Duration that non-leader candidates wait after observing a leadership renewal before attempting to acquire leadership.")
+	command.Flags().DurationVar(&leaderElectionRenewDeadline, "leader-election-renew-deadline", 14*time.Second, "Duration that the acting leader retries refreshing leadership before giving up, must be shorter than the lease duration.")
+	command.Flags().DurationVar(&leaderElectionRetryPeriod, "leader-election-retry-period", 4*time.Second, "Duration the leader election clients wait between tries of actions, must be shorter than the renew deadline.")
 
 	command.Flags().DurationVar(&driverPodCreationGracePeriod, "driver-pod-creation-grace-period", 10*time.Second, "Grace period after a successful spark-submit when driver pod not found errors will be retried. Useful if the driver pod can take some time to be created.")
 
+	command.Flags().IntVar(&executorDeletionBatchSize, "executor-deletion-batch-size", 0, "Number of executor pods deleted in parallel per batch when tearing down an application. "+
+		"Executor pods are left to be garbage collected along with the driver pod if set to 0.")
+	command.Flags().DurationVar(&executorDeletionBatchInterval, "executor-deletion-batch-interval", time.Second, "Interval between two batches of executor pod deletions.")
+
+	command.Flags().DurationVar(&unreachableExecutorGracePeriod, "unreachable-executor-grace-period", 0, "Grace period after which executors on nodes not being ready are treated as failed. "+
+		"Executors on nodes which do not exist are treated as failed once the grace period has passed since they were scheduled. "+
+		"Executors on unreachable nodes are counted as running indefinitely if set to 0. Requires nodes to be cached.")
+	command.Flags().BoolVar(&forceDeleteUnreachableExecutors, "force-delete-unreachable-executors", false, "Force delete executor pods treated as failed because of unreachable nodes "+
+		"so that Spark can request replacements.")
+
+	command.Flags().BoolVar(&enableDriverPVCRBAC, "enable-driver-pvc-rbac", false, "Grant the driver service account access to persistent volume claims "+
+		"for SparkApplications with dynamic allocation, shuffle tracking and PVC reuse enabled. "+
+		"Requires the controller to be granted access to roles, rolebindings and persistentvolumeclaims, which the default role does not include.")
+
+	command.Flags().BoolVar(&enableDriverPodValidation, "enable-driver-pod-validation", false, "Create the driver pod with a server-side dry run before submission, "+
+		"so that SparkApplications whose driver pod is rejected by admission, e.g. PodSecurity, fail without retries.")
+
+	command.Flags().BoolVar(&enableExecutorPlacementTracking, "enable-executor-placement-tracking", false, "Record the node and zone of executors in the status of SparkApplications "+
+		"and export the number of running executors per zone. Requires nodes to be cached metadata-only.")
+
+	command.Flags().StringVar(&defaultSubmissionEngine, "default-submission-engine", string(v1beta2.SubmissionEngineSparkSubmit), "Engine submitting SparkApplications "+
+		"not specifying one, either `SparkSubmit` running spark-submit, `Job` running spark-submit in a Job or `Native` creating the driver resources directly without a JVM.")
+	command.Flags().BoolVar(&enableSubmitterJob, "enable-submitter-job", false, "Enable the `Job` submission engine running spark-submit in a Job, "+
+		"which requires the controller to be granted access to jobs. SparkApplications using it fail to be submitted if disabled.")
+	command.Flags().StringVar(&submitterJobImage, "submitter-job-image", "", "Image of the Jobs running spark-submit for the `Job` submission engine. "+
+		"Defaults to the image of each SparkApplication if empty.")
+	command.Flags().DurationVar(&submitterJobTimeout, "submitter-job-timeout", 5*time.Minute, "Maximum time a Job running spark-submit may take to complete.")
+	command.Flags().StringToStringVar(&sparkHomes, "spark-homes", map[string]string{}, "Spark distributions in the operator image running spark-submit "+
+		"for the `SparkSubmit` submission engine, keyed by Spark version prefix, e.g. `3.4=/opt/spark-3.4,3.5=/opt/spark-3.5`. "+
+		"SparkApplications whose sparkVersion matches none of them use the distribution in $SPARK_HOME.")
+
+	command.Flags().IntVar(&maxExecutorAllocationBatchSize, "max-executor-allocation-batch-size", 0, "Maximum number of executor pods the driver of "+
+		"every SparkApplication creates in each round of executor allocation. Unlimited if set to 0.")
+
+	command.Flags().DurationVar(&driverProgressScrapeInterval, "driver-progress-scrape-interval", 0, "Interval at which the active and pending stages and "+
+		"the executor counts of running SparkApplications are scraped from the REST API of their drivers into their status. Scraping is disabled if set to 0.")
+
+	command.Flags().StringVar(&impersonateServiceAccount, "impersonate-service-account", "", "Name of the service account in the namespace of each SparkApplication "+
+		"to impersonate when creating driver resources. Impersonation is disabled if empty.")
+
+	command.Flags().DurationVar(&prometheusConfigMapGCInterval, "prometheus-configmap-gc-interval", 0, "Interval of deleting the Prometheus ConfigMaps "+
+		"of SparkApplications that no longer exist, e.g. after they lost their owner references in a restore. Disabled if 0.")
+
+	command.Flags().BoolVar(&enableKarpenterDisruptionProtection, "enable-karpenter-disruption-protection", false, "Annotate driver pods with `karpenter.sh/do-not-disrupt`, "+
+		"create PodDisruptionBudgets for executors of SparkApplications configuring them, and decommission executors on nodes Karpenter disrupts.")
+
+	command.Flags().BoolVar(&disableOwnerReferences, "disable-owner-references", false, "Only label rather than owner-reference the services, ingresses and ConfigMaps "+
+		"created for SparkApplications, and clean them up explicitly when the SparkApplications are deleted.")
+
+	command.Flags().BoolVar(&enableResourceReservation, "enable-resource-reservation", false, "Reserve the resources of the executors SparkApplications may scale up to "+
+		"with dynamic allocation with a ResourceQuota, which shrinks as executors start. Requires the resource quota enforcement of the webhook, which enforces the reservations.")
+
+	command.Flags().BoolVar(&enableExecutorQuotaFailureDetection, "enable-executor-quota-failure-detection", false, "Detect running SparkApplications with an executor "+
+		"quota failure policy missing executors because of an exhausted ResourceQuota, record the shortfall in their status and fail those whose policy says so.")
+
+	command.Flags().BoolVar(&enableImagePrefetch, "enable-image-prefetch", false, "Pull the executor image onto the candidate nodes of the executors with a DaemonSet "+
+		"before submitting SparkApplications with image prefetch configured.")
+	command.Flags().StringVar(&imagePrefetchPauseImage, "image-prefetch-pause-image", "registry.k8s.io/pause:3.10", "Image of the main container of the image prefetch pods.")
+
+	command.Flags().BoolVar(&enableSparkAuthSecret, "enable-spark-auth-secret", false, "Generate a per-application authentication secret and enable authentication and encryption "+
+		"of Spark internal connections for SparkApplications not setting `spark.authenticate` themselves.")
+
+	command.Flags().BoolVar(&enableSecretRotation, "enable-secret-rotation", false, "Restart the executors of running SparkApplications with secret rotation configured "+
+		"in batches whenever the Secrets mounted into them change, and regenerate their authentication secrets for every run. "+
+		"Requires the webhook, which records the hash of the Secrets on the executors.")
+
+	command.Flags().BoolVar(&enableNamespaceTerminationHandling, "enable-namespace-termination-handling", false, "Watch namespaces and fail the SparkApplications in namespaces "+
+		"being deleted right away, without retrying or recreating any of their resources while the namespaces are finalized.")
+
+	command.Flags().BoolVar(&enableWaitingForDependencies, "enable-waiting-for-dependencies", false, "Hold SparkApplications referencing Secrets, ConfigMaps "+
+		"or service accounts which do not exist in the WAITING_FOR_DEPENDENCIES state and submit them once they do, instead of failing their submission. "+
+		"ConfigMaps and service accounts are watched, while missing Secrets are polled for every 10 seconds since only Secrets created by the operator are cached.")
+
+	command.Flags().StringVar(&archiveURL, "archive-url", "", "URL of the bucket where terminated SparkApplications are archived before being deleted once their TTL expires, "+
+		"along with the configuration snapshot of every submitted run, e.g. s3://bucket?region=us-west-1&prefix=archive/ or gs://bucket?prefix=archive/. Archival is disabled if empty.")
+
+	command.Flags().BoolVar(&enableResourceRecommendation, "enable-resource-recommendation", false, "Enable driver and executor core and memory recommendations for SparkApplications "+
+		"derived from the history of their runs, i.e. their durations, OOM kills and, if a Prometheus URL is set, their peak usage.")
+	command.Flags().Float64Var(&memoryIncreaseFactor, "resource-recommendation-memory-increase-factor", 1.5, "Factor by which the memory is increased in recommendations for pods killed because of running out of memory.")
+	command.Flags().IntVar(&recommendationHistoryLimit, "resource-recommendation-history-limit", 10, "Number of runs of a SparkApplication or ScheduledSparkApplication kept in the run history recommendations are derived from.")
+	command.Flags().Float64Var(&recommendationHeadroom, "resource-recommendation-headroom", 1.2, "Factor applied to the peak usage of runs to size the cores and memory of the driver and executors.")
+	command.Flags().StringVar(&recommendationPrometheusURL, "resource-recommendation-prometheus-url", "", "URL of the Prometheus server the peak usage of runs is queried from, "+
+		"using the cAdvisor container metrics. Only memory increases after OOM kills are recommended if empty.")
+
+	command.Flags().BoolVar(&enableApplicationGroup, "enable-application-group", false, "Enable the controller managing SparkApplicationGroup resources.")
+	command.Flags().BoolVar(&enableSQLGateway, "enable-sql-gateway", false, "Enable the controller managing SparkSQLGateway resources.")
+
+	command.Flags().StringSliceVar(&preSubmissionHookURLs, "pre-submission-hook-urls", []string{}, "URLs of the external HTTP hooks called with the SparkApplication payload before submission.")
+	command.Flags().StringSliceVar(&postCompletionHookURLs, "post-completion-hook-urls", []string{}, "URLs of the external HTTP hooks called with the SparkApplication payload after completion. "+
+		"The calls are best-effort: each hook is called at most once and failed calls are not retried.")
+	command.Flags().DurationVar(&hookTimeout, "hook-timeout", 10*time.Second, "Timeout of a single external hook call.")
+	command.Flags().StringVar(&hookFailurePolicy, "hook-failure-policy", string(sparkapplication.HookFailurePolicyIgnore), "Failure policy of the pre-submission hooks, can be one of `Ignore` and `Fail`.")
+	command.Flags().StringVar(&applicationDefaultsFile, "application-defaults-file", "", "Path to the YAML file holding the application defaults of the webhook, "+
+		"applied to the specs returned by pre-submission hooks.")
+	command.Flags().StringVar(&sparkConfPolicyFile, "spark-conf-policy-file", "", "Path to the YAML file holding the Spark conf policy of the webhook, "+
+		"enforced on the specs returned by pre-submission hooks.")
+	command.Flags().StringSliceVar(&postRunWebhookURLPrefixes, "post-run-webhook-url-prefixes", []string{}, "URL prefixes, e.g. `https://hooks.example.com/spark/`, "+
+		"the webhook post-run actions of SparkApplications may call. Webhook post-run actions are refused if empty.")
+
 	command.Flags().BoolVar(&enableMetrics, "enable-metrics", false, "Enable metrics.")
 	command.Flags().StringVar(&metricsBindAddress, "metrics-bind-address", "0", "The address the metric endpoint binds to. "+
 		"Use the port :8080. If not set, it will be 0 in order to disable the metrics server")
@@ -181,6 +422,10 @@ func NewStartCommand() *cobra.Command {
 
 	command.Flags().StringVar(&pprofBindAddress, "pprof-bind-address", "0", "The address the pprof endpoint binds to. "+
 		"If not set, it will be 0 in order to disable the pprof server")
+	command.Flags().StringVar(&queryBindAddress, "query-bind-address", "0", "The address the REST API for indexed queries of SparkApplications, tenant reports and schemas of the CRDs binds to. "+
+		"If not set, it will be 0 in order to disable the query server")
+
+	chaos.AddFlags(command.Flags())
 
 	flagSet := flag.NewFlagSet("controller", flag.ExitOnError)
 	ctrl.RegisterFlags(flagSet)
@@ -193,6 +438,11 @@ func NewStartCommand() *cobra.Command {
 func start() {
 	setupLog()
 
+	if err := validateControllers(); err != nil {
+		logger.Error(err, "Invalid controllers")
+		os.Exit(1)
+	}
+
 	// Create the client rest config. Use kubeConfig if given, otherwise assume in-cluster.
 	cfg, err := ctrl.GetConfig()
 	if err != nil {
@@ -201,35 +451,7 @@ func start() {
 	}
 
 	// Create the manager.
-	tlsOptions := newTLSOptions()
-	mgr, err := ctrl.NewManager(cfg, ctrl.Options{
-		Scheme: scheme,
-		Cache:  newCacheOptions(),
-		Metrics: metricsserver.Options{
-			BindAddress:   metricsBindAddress,
-			SecureServing: secureMetrics,
-			TLSOpts:       tlsOptions,
-		},
-		WebhookServer: ctrlwebhook.NewServer(ctrlwebhook.Options{
-			TLSOpts: tlsOptions,
-		}),
-		HealthProbeBindAddress:  healthProbeBindAddress,
-		PprofBindAddress:        pprofBindAddress,
-		LeaderElection:          enableLeaderElection,
-		LeaderElectionID:        leaderElectionLockName,
-		LeaderElectionNamespace: leaderElectionLockNamespace,
-		// LeaderElectionReleaseOnCancel defines if the leader should step down voluntarily
-		// when the Manager ends. This requires the binary to immediately end when the
-		// Manager is stopped, otherwise, this setting is unsafe. Setting this significantly
-		// speeds up voluntary leader transitions as the new leader don't have to wait
-		// LeaseDuration time first.
-		//
-		// In the default scaffold provided, the program ends immediately after
-		// the manager stops, so would be fine to enable this option. However,
-		// if you are doing or is intended to do any operation such as perform cleanups
-		// after the manager stops then its usage might be unsafe.
-		// LeaderElectionReleaseOnCancel: true,
-	})
+	mgr, err := ctrl.NewManager(cfg, newManagerOptions())
 	if err != nil {
 		logger.Error(err, "failed to create manager")
 		os.Exit(1)
@@ -249,12 +471,18 @@ func start() {
 	var registry *scheduler.Registry
 	if enableBatchScheduler {
 		registry = scheduler.GetRegistry()
-		_ = registry.Register(common.VolcanoSchedulerName, volcano.Factory)
-		_ = registry.Register(yunikorn.SchedulerName, yunikorn.Factory)
+		_ = registry.Register(common.VolcanoSchedulerName, volcano.Factory, &volcano.Config{RestConfig: mgr.GetConfig()})
+		_ = registry.Register(yunikorn.SchedulerName, yunikorn.Factory, nil)
 
 		// Register kube-schedulers.
 		for _, name := range kubeSchedulerNames {
-			_ = registry.Register(name, kubescheduler.Factory)
+			_ = registry.Register(name, kubescheduler.Factory, &kubescheduler.Config{SchedulerName: name, Client: mgr.GetClient()})
+		}
+
+		// Register scheduler plugins compiled into the operator.
+		if err := registry.RegisterPlugins(mgr.GetClient(), mgr.GetConfig()); err != nil {
+			logger.Error(err, "Failed to register scheduler plugins")
+			os.Exit(1)
 		}
 
 		schedulerNames := registry.GetRegisteredSchedulerNames()
@@ -264,28 +492,134 @@ func start() {
 		}
 	}
 
-	// Setup controller for SparkApplication.
-	if err = sparkapplication.NewReconciler(
-		mgr,
-		mgr.GetScheme(),
-		mgr.GetClient(),
-		mgr.GetEventRecorderFor("spark-application-controller"),
-		registry,
-		newSparkApplicationReconcilerOptions(),
-	).SetupWithManager(mgr, newControllerOptions()); err != nil {
-		logger.Error(err, "Failed to create controller", "controller", "SparkApplication")
+	switch v1beta2.SubmissionEngine(defaultSubmissionEngine) {
+	case v1beta2.SubmissionEngineSparkSubmit, v1beta2.SubmissionEngineJob, v1beta2.SubmissionEngineNative:
+	default:
+		logger.Error(nil, "Invalid default submission engine", "engine", defaultSubmissionEngine)
+		os.Exit(1)
+	}
+	if v1beta2.SubmissionEngine(defaultSubmissionEngine) == v1beta2.SubmissionEngineJob && !enableSubmitterJob {
+		logger.Error(nil, "Default submission engine requires the submitter Job to be enabled", "engine", defaultSubmissionEngine)
 		os.Exit(1)
 	}
 
+	sparkApplicationReconcilerOptions := newSparkApplicationReconcilerOptions()
+	if applicationDefaultsFile != "" {
+		sparkApplicationReconcilerOptions.ApplicationDefaults, err = webhook.LoadApplicationDefaults(applicationDefaultsFile)
+		if err != nil {
+			logger.Error(err, "Failed to load application defaults")
+			os.Exit(1)
+		}
+	}
+	if sparkConfPolicyFile != "" {
+		sparkApplicationReconcilerOptions.SparkConfPolicy, err = webhook.LoadSparkConfPolicy(sparkConfPolicyFile)
+		if err != nil {
+			logger.Error(err, "Failed to load Spark conf policy")
+			os.Exit(1)
+		}
+	}
+	if archiveURL != "" {
+		applicationArchive, err := archive.Open(context.Background(), archiveURL)
+		if err != nil {
+			logger.Error(err, "Failed to open archive for SparkApplications")
+			os.Exit(1)
+		}
+		defer applicationArchive.Close()
+		sparkApplicationReconcilerOptions.Archive = applicationArchive
+	}
+
+	if stateStoreURL != "" {
+		stateStore, err := statestore.Open(context.Background(), stateStoreURL, mgr.GetClient(), mgr.GetAPIReader())
+		if err != nil {
+			logger.Error(err, "Failed to open state store")
+			os.Exit(1)
+		}
+		defer stateStore.Close()
+		sparkApplicationReconcilerOptions.StateStore = stateStore
+	}
+
+	// Setup controller for SparkApplication.
+	if isControllerEnabled(sparkApplicationController) {
+		if err = sparkapplication.NewReconciler(
+			mgr,
+			mgr.GetScheme(),
+			mgr.GetClient(),
+			mgr.GetEventRecorderFor("spark-application-controller"),
+			registry,
+			sparkApplicationReconcilerOptions,
+		).SetupWithManager(mgr, newControllerOptions()); err != nil {
+			logger.Error(err, "Failed to create controller", "controller", "SparkApplication")
+			os.Exit(1)
+		}
+	}
+
 	// Setup controller for ScheduledSparkApplication.
-	if err = scheduledsparkapplication.NewReconciler(
-		mgr.GetScheme(),
-		mgr.GetClient(),
-		mgr.GetEventRecorderFor("scheduled-spark-application-controller"),
-		clock.RealClock{},
-		newScheduledSparkApplicationReconcilerOptions(),
-	).SetupWithManager(mgr, newControllerOptions()); err != nil {
-		logger.Error(err, "Failed to create controller", "controller", "ScheduledSparkApplication")
+	if isControllerEnabled(scheduledSparkApplicationController) {
+		if err = scheduledsparkapplication.NewReconciler(
+			mgr.GetScheme(),
+			mgr.GetClient(),
+			mgr.GetEventRecorderFor("scheduled-spark-application-controller"),
+			clock.RealClock{},
+			newScheduledSparkApplicationReconcilerOptions(),
+		).SetupWithManager(mgr, newControllerOptions()); err != nil {
+			logger.Error(err, "Failed to create controller", "controller", "ScheduledSparkApplication")
+			os.Exit(1)
+		}
+	}
+
+	// Setup controller for resource recommendations.
+	if enableResourceRecommendation && isControllerEnabled(recommendationController) {
+		if err = recommendation.NewReconciler(
+			mgr.GetClient(),
+			mgr.GetEventRecorderFor("spark-application-recommendation-controller"),
+			newRecommendationReconcilerOptions(),
+		).SetupWithManager(mgr, newControllerOptions()); err != nil {
+			logger.Error(err, "Failed to create controller", "controller", "Recommendation")
+			os.Exit(1)
+		}
+	}
+
+	// Setup controller for SparkApplicationGroup.
+	if enableApplicationGroup && isControllerEnabled(sparkApplicationGroupController) {
+		if err = sparkapplicationgroup.NewReconciler(
+			mgr.GetScheme(),
+			mgr.GetClient(),
+			mgr.GetEventRecorderFor("spark-application-group-controller"),
+			newSparkApplicationGroupReconcilerOptions(),
+		).SetupWithManager(mgr, newControllerOptions()); err != nil {
+			logger.Error(err, "Failed to create controller", "controller", "SparkApplicationGroup")
+			os.Exit(1)
+		}
+	}
+
+	// Setup controller for SparkSQLGateway.
+	if enableSQLGateway && isControllerEnabled(sparkSQLGatewayController) {
+		if err = sparksqlgateway.NewReconciler(
+			mgr.GetScheme(),
+			mgr.GetClient(),
+			mgr.GetEventRecorderFor("spark-sql-gateway-controller"),
+			newSparkSQLGatewayReconcilerOptions(),
+		).SetupWithManager(mgr, newControllerOptions()); err != nil {
+			logger.Error(err, "Failed to create controller", "controller", "SparkSQLGateway")
+			os.Exit(1)
+		}
+	}
+
+	// Setup indexed queries of SparkApplications.
+	if queryBindAddress != "0" {
+		if err := query.SetupIndexes(context.Background(), mgr.GetFieldIndexer()); err != nil {
+			logger.Error(err, "Failed to set up indexes of SparkApplications")
+			os.Exit(1)
+		}
+		if err := mgr.Add(query.NewServer(queryBindAddress, mgr.GetCache(), mgr.GetAPIReader())); err != nil {
+			logger.Error(err, "Failed to set up query server")
+			os.Exit(1)
+		}
+	}
+
+	// Setup fault injection for binaries built with the chaos build tag.
+	if err := chaos.Setup(mgr); err != nil {
+		logger.Error(err, "Failed to set up fault injection")
 		os.Exit(1)
 	}
 
@@ -331,6 +665,42 @@ func setupLog() {
 	)
 }
 
+// newManagerOptions returns the options of the manager, including the configuration of leader election.
+func newManagerOptions() ctrl.Options {
+	tlsOptions := newTLSOptions()
+	return ctrl.Options{
+		Scheme: scheme,
+		Cache:  newCacheOptions(),
+		Metrics: metricsserver.Options{
+			BindAddress:   metricsBindAddress,
+			SecureServing: secureMetrics,
+			TLSOpts:       tlsOptions,
+		},
+		WebhookServer: ctrlwebhook.NewServer(ctrlwebhook.Options{
+			TLSOpts: tlsOptions,
+		}),
+		HealthProbeBindAddress:  healthProbeBindAddress,
+		PprofBindAddress:        pprofBindAddress,
+		LeaderElection:          enableLeaderElection,
+		LeaderElectionID:        leaderElectionLockName,
+		LeaderElectionNamespace: leaderElectionLockNamespace,
+		LeaseDuration:           &leaderElectionLeaseDuration,
+		RenewDeadline:           &leaderElectionRenewDeadline,
+		RetryPeriod:             &leaderElectionRetryPeriod,
+		// LeaderElectionReleaseOnCancel defines if the leader should step down voluntarily
+		// when the Manager ends. This requires the binary to immediately end when the
+		// Manager is stopped, otherwise, this setting is unsafe. Setting this significantly
+		// speeds up voluntary leader transitions as the new leader don't have to wait
+		// LeaseDuration time first.
+		//
+		// In the default scaffold provided, the program ends immediately after
+		// the manager stops, so would be fine to enable this option. However,
+		// if you are doing or is intended to do any operation such as perform cleanups
+		// after the manager stops then its usage might be unsafe.
+		// LeaderElectionReleaseOnCancel: true,
+	}
+}
+
 func newTLSOptions() []func(c *tls.Config) {
 	// if the enable-http2 flag is false (the default), http/2 should be disabled
 	// due to its vulnerabilities. More specifically, disabling http/2 will
@@ -368,6 +738,9 @@ func newCacheOptions() cache.Options {
 					common.LabelLaunchedBySparkOperator: "true",
 				}),
 			},
+			&corev1.Secret{}: {
+				Label: sparkAppNameExistsSelector(),
+			},
 			&corev1.ConfigMap{}:             {},
 			&corev1.PersistentVolumeClaim{}: {},
 			&corev1.Service{}:               {},
@@ -375,9 +748,71 @@ func newCacheOptions() cache.Options {
 		},
 	}
 
+	// Only submitter Jobs are cached, and only if the Job submission engine is enabled.
+	if enableSubmitterJob {
+		options.ByObject[&batchv1.Job{}] = cache.ByObject{
+			Label: labels.SelectorFromSet(labels.Set{
+				common.LabelSubmitterJob: "true",
+			}),
+		}
+	}
+
+	if cacheListPageSize > 0 || cacheTerminatedApplicationMaxAge > 0 {
+		var filter func(runtime.Object) bool
+		if cacheTerminatedApplicationMaxAge > 0 {
+			filter = util.NewTerminatedSparkApplicationFilter(cacheTerminatedApplicationMaxAge)
+		}
+		options.NewInformer = func(lw toolscache.ListerWatcher, obj runtime.Object, resync time.Duration, indexers toolscache.Indexers) toolscache.SharedIndexInformer {
+			return toolscache.NewSharedIndexInformer(&util.ListWatch{
+				ListerWatcher: lw,
+				PageSize:      cacheListPageSize,
+				Filter:        filter,
+			}, obj, resync, indexers)
+		}
+	}
+
 	return options
 }
 
+// sparkAppNameExistsSelector returns a selector matching the objects created by the operator for SparkApplications.
+func sparkAppNameExistsSelector() labels.Selector {
+	requirement, err := labels.NewRequirement(common.LabelSparkAppName, selection.Exists, nil)
+	if err != nil {
+		panic(err)
+	}
+	return labels.NewSelector().Add(*requirement)
+}
+
+// validateControllers checks that --controllers only refers to known controllers.
+func validateControllers() error {
+	for _, c := range controllers {
+		if c == "*" {
+			continue
+		}
+		if !slices.Contains(knownControllers, strings.TrimPrefix(c, "-")) {
+			return fmt.Errorf("unknown controller %q, must be one of %s", c, strings.Join(knownControllers, ", "))
+		}
+	}
+	return nil
+}
+
+// isControllerEnabled returns whether the controller of the given name is enabled by --controllers. The first entry
+// naming the controller, or disabling it with a `-` prefix, takes precedence over `*`.
+func isControllerEnabled(name string) bool {
+	enabled := false
+	for _, c := range controllers {
+		switch c {
+		case name:
+			return true
+		case "-" + name:
+			return false
+		case "*":
+			enabled = true
+		}
+	}
+	return enabled
+}
+
 // newControllerOptions creates and returns a controller.Options instance configured with the given options.
 func newControllerOptions() controller.Options {
 	options := controller.Options{
@@ -391,11 +826,14 @@ func newControllerOptions() controller.Options {
 func newSparkApplicationReconcilerOptions() sparkapplication.Options {
 	var sparkApplicationMetrics *metrics.SparkApplicationMetrics
 	var sparkExecutorMetrics *metrics.SparkExecutorMetrics
+	var sparkSubmissionMetrics *metrics.SparkSubmissionMetrics
 	if enableMetrics {
 		sparkApplicationMetrics = metrics.NewSparkApplicationMetrics(metricsPrefix, metricsLabels, metricsJobStartLatencyBuckets)
 		sparkApplicationMetrics.Register()
 		sparkExecutorMetrics = metrics.NewSparkExecutorMetrics(metricsPrefix, metricsLabels)
 		sparkExecutorMetrics.Register()
+		sparkSubmissionMetrics = metrics.NewSparkSubmissionMetrics(metricsPrefix)
+		sparkSubmissionMetrics.Register()
 	}
 	options := sparkapplication.Options{
 		Namespaces:                   namespaces,
@@ -406,10 +844,81 @@ func newSparkApplicationReconcilerOptions() sparkapplication.Options {
 		DriverPodCreationGracePeriod: driverPodCreationGracePeriod,
 		SparkApplicationMetrics:      sparkApplicationMetrics,
 		SparkExecutorMetrics:         sparkExecutorMetrics,
+		SparkSubmissionMetrics:       sparkSubmissionMetrics,
 		MaxTrackedExecutorPerApp:     maxTrackedExecutorPerApp,
+		Submission: sparkapplication.SubmissionOptions{
+			DefaultEngine:             v1beta2.SubmissionEngine(defaultSubmissionEngine),
+			EnableSubmitterJob:        enableSubmitterJob,
+			SubmitterJobImage:         submitterJobImage,
+			SubmitterJobTimeout:       submitterJobTimeout,
+			SparkHomes:                sparkHomes,
+			MaxConcurrentPerNamespace: maxConcurrentSubmissionsPerNamespace,
+			ImpersonateServiceAccount: impersonateServiceAccount,
+			EnableDriverPodValidation: enableDriverPodValidation,
+		},
+		Executor: sparkapplication.ExecutorOptions{
+			DeletionBatchSize:                   executorDeletionBatchSize,
+			DeletionBatchInterval:               executorDeletionBatchInterval,
+			UnreachableGracePeriod:              unreachableExecutorGracePeriod,
+			ForceDeleteUnreachable:              forceDeleteUnreachableExecutors,
+			EnablePlacementTracking:             enableExecutorPlacementTracking,
+			MaxAllocationBatchSize:              maxExecutorAllocationBatchSize,
+			EnableSecretRotation:                enableSecretRotation,
+			EnableKarpenterDisruptionProtection: enableKarpenterDisruptionProtection,
+		},
+		Scheduling: sparkapplication.SchedulingOptions{
+			EnableWaitingForDependencies:        enableWaitingForDependencies,
+			EnableResourceReservation:           enableResourceReservation,
+			EnableExecutorQuotaFailureDetection: enableExecutorQuotaFailureDetection,
+			EnableImagePrefetch:                 enableImagePrefetch,
+			ImagePrefetchPauseImage:             imagePrefetchPauseImage,
+		},
+		EnableDriverPVCRBAC:                enableDriverPVCRBAC,
+		DriverProgressScrapeInterval:       driverProgressScrapeInterval,
+		PrometheusConfigMapGCInterval:      prometheusConfigMapGCInterval,
+		DisableOwnerReferences:             disableOwnerReferences,
+		EnableSparkAuthSecret:              enableSparkAuthSecret,
+		EnableNamespaceTerminationHandling: enableNamespaceTerminationHandling,
+		PostRunWebhookURLPrefixes:          postRunWebhookURLPrefixes,
 	}
-	if enableBatchScheduler {
-		options.KubeSchedulerNames = kubeSchedulerNames
+	for _, url := range preSubmissionHookURLs {
+		options.PreSubmissionHooks = append(options.PreSubmissionHooks, newHook(url))
+	}
+	for _, url := range postCompletionHookURLs {
+		options.PostCompletionHooks = append(options.PostCompletionHooks, newHook(url))
+	}
+	return options
+}
+
+func newHook(url string) sparkapplication.Hook {
+	return sparkapplication.Hook{
+		URL:           url,
+		Timeout:       hookTimeout,
+		FailurePolicy: sparkapplication.HookFailurePolicy(hookFailurePolicy),
+	}
+}
+
+func newRecommendationReconcilerOptions() recommendation.Options {
+	options := recommendation.Options{
+		Namespaces:           namespaces,
+		MemoryIncreaseFactor: memoryIncreaseFactor,
+		HistoryLimit:         recommendationHistoryLimit,
+		Headroom:             recommendationHeadroom,
+		PrometheusURL:        recommendationPrometheusURL,
+	}
+	return options
+}
+
+func newSparkApplicationGroupReconcilerOptions() sparkapplicationgroup.Options {
+	options := sparkapplicationgroup.Options{
+		Namespaces: namespaces,
+	}
+	return options
+}
+
+func newSparkSQLGatewayReconcilerOptions() sparksqlgateway.Options {
+	options := sparksqlgateway.Options{
+		Namespaces: namespaces,
 	}
 	return options
 }
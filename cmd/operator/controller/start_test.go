@@ -0,0 +1,122 @@
+/*
+Copyright 2024 The Kubeflow authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	batchv1 "k8s.io/api/batch/v1"
+	"sigs.k8s.io/controller-runtime/pkg/cache"
+)
+
+func TestIsControllerEnabled(t *testing.T) {
+	defer func(old []string) { controllers = old }(controllers)
+
+	testCases := []struct {
+		controllers []string
+		enabled     []string
+		disabled    []string
+	}{
+		{
+			controllers: []string{"*"},
+			enabled:     knownControllers,
+		},
+		{
+			controllers: []string{"sparkapplication"},
+			enabled:     []string{sparkApplicationController},
+			disabled:    []string{scheduledSparkApplicationController, sparkSQLGatewayController},
+		},
+		{
+			controllers: []string{"*", "-sparkapplication"},
+			enabled:     []string{scheduledSparkApplicationController, sparkSQLGatewayController},
+			disabled:    []string{sparkApplicationController},
+		},
+		{
+			controllers: []string{"sparkapplication", "-sparkapplication"},
+			enabled:     []string{sparkApplicationController},
+		},
+		{
+			controllers: []string{},
+			disabled:    knownControllers,
+		},
+	}
+
+	for _, tc := range testCases {
+		controllers = tc.controllers
+		for _, name := range tc.enabled {
+			assert.True(t, isControllerEnabled(name), "%s with --controllers=%v", name, tc.controllers)
+		}
+		for _, name := range tc.disabled {
+			assert.False(t, isControllerEnabled(name), "%s with --controllers=%v", name, tc.controllers)
+		}
+	}
+}
+
+func TestValidateControllers(t *testing.T) {
+	defer func(old []string) { controllers = old }(controllers)
+
+	controllers = []string{"*", "-recommendation", "sparkapplicationgroup"}
+	assert.NoError(t, validateControllers())
+
+	controllers = []string{"sparkapplications"}
+	assert.ErrorContains(t, validateControllers(), `unknown controller "sparkapplications"`)
+
+	controllers = []string{"-*"}
+	assert.Error(t, validateControllers())
+}
+
+func TestNewManagerOptionsAppliesLeaderElectionFlags(t *testing.T) {
+	command := NewStartCommand()
+	require.NoError(t, command.Flags().Parse([]string{
+		"--leader-election=true",
+		"--leader-election-lock-name=test-lock",
+		"--leader-election-lock-namespace=test-namespace",
+		"--leader-election-lease-duration=60s",
+		"--leader-election-renew-deadline=40s",
+		"--leader-election-retry-period=10s",
+	}))
+
+	options := newManagerOptions()
+	assert.True(t, options.LeaderElection)
+	assert.Equal(t, "test-lock", options.LeaderElectionID)
+	assert.Equal(t, "test-namespace", options.LeaderElectionNamespace)
+	assert.Equal(t, 60*time.Second, *options.LeaseDuration)
+	assert.Equal(t, 40*time.Second, *options.RenewDeadline)
+	assert.Equal(t, 10*time.Second, *options.RetryPeriod)
+}
+
+func TestNewCacheOptionsOnlyCachesJobsIfSubmitterJobEnabled(t *testing.T) {
+	defer func(old bool) { enableSubmitterJob = old }(enableSubmitterJob)
+
+	hasJob := func(options cache.Options) bool {
+		for obj := range options.ByObject {
+			if _, ok := obj.(*batchv1.Job); ok {
+				return true
+			}
+		}
+		return false
+	}
+
+	enableSubmitterJob = false
+	assert.False(t, hasJob(newCacheOptions()))
+
+	enableSubmitterJob = true
+	assert.True(t, hasJob(newCacheOptions()))
+}
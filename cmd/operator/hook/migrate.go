@@ -0,0 +1,135 @@
+/*
+Copyright 2024 The Kubeflow authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package hook
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/spf13/cobra"
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/client-go/util/retry"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/kubeflow/spark-operator/pkg/schema"
+)
+
+func NewMigrateStorageVersionCommand() *cobra.Command {
+	command := &cobra.Command{
+		Use:   "migrate-storage-version",
+		Short: "Migrate the stored objects to the storage version of the CRDs",
+		Long: `Rewrite all stored objects of the CRDs of the operator, e.g., SparkApplications and
+ScheduledSparkApplications, in the current storage version after a CRD upgrade, and then prune the old versions
+from status.storedVersions of the CRDs so that they can be removed from the CRDs in later upgrades.`,
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			return migrateStorageVersion(cmd.Context())
+		},
+	}
+	return command
+}
+
+func migrateStorageVersion(ctx context.Context) error {
+	_, c, err := newClient()
+	if err != nil {
+		return err
+	}
+	crds, err := schema.LoadCRDs()
+	if err != nil {
+		return err
+	}
+
+	for _, crd := range crds {
+		installed := &apiextensionsv1.CustomResourceDefinition{}
+		if err := c.Get(ctx, client.ObjectKey{Name: crd.Name}, installed); err != nil {
+			if errors.IsNotFound(err) {
+				continue
+			}
+			return fmt.Errorf("failed to get CRD %s: %v", crd.Name, err)
+		}
+		if err := migrateCRD(ctx, c, installed); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// migrateCRD rewrites all objects of the given CRD in its storage version and then sets the storage version as the
+// only stored version of the CRD.
+func migrateCRD(ctx context.Context, c client.Client, crd *apiextensionsv1.CustomResourceDefinition) error {
+	storageVersion := getStorageVersion(crd)
+	if storageVersion == "" {
+		return fmt.Errorf("CRD %s has no storage version", crd.Name)
+	}
+	if len(crd.Status.StoredVersions) == 1 && crd.Status.StoredVersions[0] == storageVersion {
+		fmt.Printf("CRD %s is already stored in version %s only\n", crd.Name, storageVersion)
+		return nil
+	}
+
+	list := &unstructured.UnstructuredList{}
+	list.SetAPIVersion(crd.Spec.Group + "/" + storageVersion)
+	list.SetKind(crd.Spec.Names.ListKind)
+	if err := c.List(ctx, list); err != nil {
+		return fmt.Errorf("failed to list %s: %v", crd.Spec.Names.Plural, err)
+	}
+	for i := range list.Items {
+		if err := rewriteObject(ctx, c, &list.Items[i]); err != nil {
+			return fmt.Errorf("failed to migrate %s %s/%s: %v", crd.Spec.Names.Kind, list.Items[i].GetNamespace(), list.Items[i].GetName(), err)
+		}
+	}
+	fmt.Printf("Migrated %d %s to version %s\n", len(list.Items), crd.Spec.Names.Plural, storageVersion)
+
+	return retry.RetryOnConflict(retry.DefaultRetry, func() error {
+		latest := &apiextensionsv1.CustomResourceDefinition{}
+		if err := c.Get(ctx, client.ObjectKey{Name: crd.Name}, latest); err != nil {
+			return err
+		}
+		if getStorageVersion(latest) != storageVersion {
+			return fmt.Errorf("storage version of CRD %s changed during migration", crd.Name)
+		}
+		latest.Status.StoredVersions = []string{storageVersion}
+		if err := c.Status().Update(ctx, latest); err != nil {
+			return err
+		}
+		fmt.Printf("Pruned stored versions of CRD %s to %s\n", crd.Name, storageVersion)
+		return nil
+	})
+}
+
+// rewriteObject writes the given object back unchanged, which makes the API server store it in the storage version.
+func rewriteObject(ctx context.Context, c client.Client, obj *unstructured.Unstructured) error {
+	return retry.RetryOnConflict(retry.DefaultRetry, func() error {
+		err := c.Update(ctx, obj)
+		if errors.IsConflict(err) {
+			if err := c.Get(ctx, client.ObjectKeyFromObject(obj), obj); err != nil {
+				return client.IgnoreNotFound(err)
+			}
+			return err
+		}
+		return client.IgnoreNotFound(err)
+	})
+}
+
+func getStorageVersion(crd *apiextensionsv1.CustomResourceDefinition) string {
+	for _, version := range crd.Spec.Versions {
+		if version.Storage {
+			return version.Name
+		}
+	}
+	return ""
+}
@@ -0,0 +1,246 @@
+/*
+Copyright 2024 The Kubeflow authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package hook
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/spf13/cobra"
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/util/sets"
+	utilversion "k8s.io/apimachinery/pkg/util/version"
+	"k8s.io/client-go/discovery"
+	"k8s.io/client-go/kubernetes"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/kubeflow/spark-operator/pkg/schema"
+)
+
+var (
+	minKubernetesVersion string
+	crdsDir              string
+	validateObjects      bool
+)
+
+// webhookResources are the resources of the admission webhook API the operator depends on.
+var webhookResources = []string{"mutatingwebhookconfigurations", "validatingwebhookconfigurations"}
+
+func NewPreflightCommand() *cobra.Command {
+	command := &cobra.Command{
+		Use:   "preflight",
+		Short: "Check that the cluster is compatible with the CRDs before an upgrade",
+		Long: `Check the Kubernetes version, the availability of the admission webhook API, the storage versions of
+the installed CRDs and the compatibility of the stored objects with the CRDs bundled in the operator, or the
+CRDs in --crds-dir if set, e.g., as a pre-upgrade hook of the Helm chart.`,
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			return preflight(cmd.Context())
+		},
+	}
+
+	command.Flags().StringVar(&minKubernetesVersion, "min-kubernetes-version", "1.16", "The minimum Kubernetes version required.")
+	command.Flags().StringVar(&crdsDir, "crds-dir", "", "Directory of the CRDs to check against. If not set, the CRDs bundled in the operator are used.")
+	command.Flags().BoolVar(&validateObjects, "validate-objects", true, "Whether to validate the stored objects against the CRDs.")
+	return command
+}
+
+func preflight(ctx context.Context) error {
+	cfg, c, err := newClient()
+	if err != nil {
+		return err
+	}
+	clientset, err := kubernetes.NewForConfig(cfg)
+	if err != nil {
+		return fmt.Errorf("failed to create clientset: %v", err)
+	}
+	crds, err := loadTargetCRDs(crdsDir)
+	if err != nil {
+		return err
+	}
+
+	var problems []string
+	if err := checkKubernetesVersion(clientset.Discovery(), minKubernetesVersion); err != nil {
+		problems = append(problems, err.Error())
+	}
+	if err := checkWebhookAPI(clientset.Discovery()); err != nil {
+		problems = append(problems, err.Error())
+	}
+	crdProblems, err := checkCRDs(ctx, c, crds, validateObjects)
+	if err != nil {
+		return err
+	}
+	problems = append(problems, crdProblems...)
+	return report(problems)
+}
+
+func loadTargetCRDs(dir string) ([]*apiextensionsv1.CustomResourceDefinition, error) {
+	if dir == "" {
+		return schema.LoadCRDs()
+	}
+	crds, err := schema.LoadCRDsFromDir(dir)
+	if err != nil {
+		return nil, err
+	}
+	if len(crds) == 0 {
+		return nil, fmt.Errorf("no CRDs found in %s", dir)
+	}
+	return crds, nil
+}
+
+func report(problems []string) error {
+	for _, problem := range problems {
+		fmt.Printf("FAIL: %s\n", problem)
+	}
+	if len(problems) > 0 {
+		return fmt.Errorf("%d preflight check(s) failed", len(problems))
+	}
+	fmt.Println("All preflight checks passed")
+	return nil
+}
+
+// checkKubernetesVersion checks that the version of the Kubernetes API server is at least minVersion.
+func checkKubernetesVersion(client discovery.ServerVersionInterface, minVersion string) error {
+	info, err := client.ServerVersion()
+	if err != nil {
+		return fmt.Errorf("failed to get Kubernetes version: %v", err)
+	}
+	return compareKubernetesVersion(info.GitVersion, minVersion)
+}
+
+func compareKubernetesVersion(serverVersion string, minVersion string) error {
+	actual, err := utilversion.ParseGeneric(serverVersion)
+	if err != nil {
+		return fmt.Errorf("failed to parse Kubernetes version %q: %v", serverVersion, err)
+	}
+	required, err := utilversion.ParseGeneric(minVersion)
+	if err != nil {
+		return fmt.Errorf("failed to parse minimum Kubernetes version %q: %v", minVersion, err)
+	}
+	if !actual.AtLeast(required) {
+		return fmt.Errorf("Kubernetes version %s is older than the minimum version %s", serverVersion, minVersion)
+	}
+	return nil
+}
+
+// checkWebhookAPI checks that the admissionregistration.k8s.io/v1 API used by the webhook is served.
+func checkWebhookAPI(client discovery.ServerResourcesInterface) error {
+	groupVersion := "admissionregistration.k8s.io/v1"
+	list, err := client.ServerResourcesForGroupVersion(groupVersion)
+	if err != nil {
+		return fmt.Errorf("admission webhook API %s is not available: %v", groupVersion, err)
+	}
+	served := sets.New[string]()
+	for _, resource := range list.APIResources {
+		served.Insert(resource.Name)
+	}
+	for _, resource := range webhookResources {
+		if !served.Has(resource) {
+			return fmt.Errorf("admission webhook API %s does not serve %s", groupVersion, resource)
+		}
+	}
+	return nil
+}
+
+// checkCRDs checks that replacing the installed CRDs with the given ones keeps the stored objects readable.
+func checkCRDs(ctx context.Context, c client.Client, crds []*apiextensionsv1.CustomResourceDefinition, validateObjects bool) ([]string, error) {
+	var problems []string
+	for _, crd := range crds {
+		installed := &apiextensionsv1.CustomResourceDefinition{}
+		if err := c.Get(ctx, client.ObjectKey{Name: crd.Name}, installed); err != nil {
+			if errors.IsNotFound(err) {
+				continue
+			}
+			return nil, fmt.Errorf("failed to get CRD %s: %v", crd.Name, err)
+		}
+
+		problems = append(problems, checkStoredVersions(installed, crd)...)
+		if validateObjects {
+			objectProblems, err := checkStoredObjects(ctx, c, installed, crd)
+			if err != nil {
+				return nil, err
+			}
+			problems = append(problems, objectProblems...)
+		}
+	}
+	return problems, nil
+}
+
+// checkStoredVersions checks that every version objects of the installed CRD may be stored in is still served by
+// the given CRD, otherwise those objects would no longer be readable.
+func checkStoredVersions(installed *apiextensionsv1.CustomResourceDefinition, crd *apiextensionsv1.CustomResourceDefinition) []string {
+	served := sets.New[string]()
+	for _, version := range crd.Spec.Versions {
+		if version.Served {
+			served.Insert(version.Name)
+		}
+	}
+
+	var problems []string
+	for _, version := range installed.Status.StoredVersions {
+		if !served.Has(version) {
+			problems = append(problems, fmt.Sprintf("CRD %s has objects stored in version %s which is not served by the new CRD", crd.Name, version))
+		}
+	}
+	return problems
+}
+
+// checkStoredObjects checks that the objects of the installed CRD are valid against the schemas of the given CRD
+// and have no fields the given CRD would prune.
+func checkStoredObjects(ctx context.Context, c client.Client, installed *apiextensionsv1.CustomResourceDefinition, crd *apiextensionsv1.CustomResourceDefinition) ([]string, error) {
+	servedByInstalled := sets.New[string]()
+	for _, version := range installed.Spec.Versions {
+		if version.Served {
+			servedByInstalled.Insert(version.Name)
+		}
+	}
+
+	var problems []string
+	for _, version := range crd.Spec.Versions {
+		if !version.Served || version.Schema == nil || !servedByInstalled.Has(version.Name) {
+			continue
+		}
+
+		list := &unstructured.UnstructuredList{}
+		list.SetAPIVersion(crd.Spec.Group + "/" + version.Name)
+		list.SetKind(crd.Spec.Names.ListKind)
+		if err := c.List(ctx, list); err != nil {
+			return nil, fmt.Errorf("failed to list %s in version %s: %v", crd.Spec.Names.Plural, version.Name, err)
+		}
+
+		for i := range list.Items {
+			obj := &list.Items[i]
+			key := fmt.Sprintf("%s %s/%s (%s)", crd.Spec.Names.Kind, obj.GetNamespace(), obj.GetName(), version.Name)
+			errs, err := schema.ValidateWithSchema(version.Schema.OpenAPIV3Schema, obj)
+			if err != nil {
+				return nil, err
+			}
+			for _, e := range errs {
+				problems = append(problems, fmt.Sprintf("%s is invalid: %v", key, e))
+			}
+			fields, err := schema.UnknownFields(version.Schema.OpenAPIV3Schema, obj)
+			if err != nil {
+				return nil, err
+			}
+			for _, field := range fields {
+				problems = append(problems, fmt.Sprintf("%s has field %s which would be pruned", key, field))
+			}
+		}
+	}
+	return problems, nil
+}
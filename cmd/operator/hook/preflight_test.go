@@ -0,0 +1,71 @@
+/*
+Copyright 2024 The Kubeflow authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package hook
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/version"
+	fakediscovery "k8s.io/client-go/discovery/fake"
+	kubetesting "k8s.io/client-go/testing"
+)
+
+func TestCheckKubernetesVersion(t *testing.T) {
+	client := &fakediscovery.FakeDiscovery{Fake: &kubetesting.Fake{}, FakedServerVersion: &version.Info{GitVersion: "v1.30.2-eks-1552ad0"}}
+	assert.NoError(t, checkKubernetesVersion(client, "1.16"))
+	assert.NoError(t, checkKubernetesVersion(client, "1.30"))
+	assert.Error(t, checkKubernetesVersion(client, "1.31"))
+	assert.Error(t, checkKubernetesVersion(client, "invalid"))
+}
+
+func TestCheckWebhookAPI(t *testing.T) {
+	client := &fakediscovery.FakeDiscovery{Fake: &kubetesting.Fake{}}
+	assert.Error(t, checkWebhookAPI(client))
+
+	client.Resources = []*metav1.APIResourceList{{
+		GroupVersion: "admissionregistration.k8s.io/v1",
+		APIResources: []metav1.APIResource{{Name: "mutatingwebhookconfigurations"}},
+	}}
+	assert.Error(t, checkWebhookAPI(client))
+
+	client.Resources[0].APIResources = append(client.Resources[0].APIResources, metav1.APIResource{Name: "validatingwebhookconfigurations"})
+	assert.NoError(t, checkWebhookAPI(client))
+}
+
+func TestCheckStoredVersions(t *testing.T) {
+	installed := &apiextensionsv1.CustomResourceDefinition{
+		Status: apiextensionsv1.CustomResourceDefinitionStatus{StoredVersions: []string{"v1beta1", "v1beta2"}},
+	}
+	crd := &apiextensionsv1.CustomResourceDefinition{
+		ObjectMeta: metav1.ObjectMeta{Name: "sparkapplications.sparkoperator.k8s.io"},
+		Spec: apiextensionsv1.CustomResourceDefinitionSpec{
+			Versions: []apiextensionsv1.CustomResourceDefinitionVersion{
+				{Name: "v1beta1", Served: false},
+				{Name: "v1beta2", Served: true, Storage: true},
+			},
+		},
+	}
+	problems := checkStoredVersions(installed, crd)
+	assert.Len(t, problems, 1)
+	assert.Contains(t, problems[0], "v1beta1")
+
+	crd.Spec.Versions[0].Served = true
+	assert.Empty(t, checkStoredVersions(installed, crd))
+}
@@ -0,0 +1,107 @@
+/*
+Copyright 2024 The Kubeflow authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package hook
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/spf13/cobra"
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+var (
+	rollbackCRDsDir string
+	dryRun          bool
+)
+
+func NewRollbackCRDsCommand() *cobra.Command {
+	command := &cobra.Command{
+		Use:   "rollback-crds",
+		Short: "Roll back the installed CRDs to a previous version",
+		Long: `Replace the installed CRDs with the CRDs in --crds-dir, e.g., the crds directory of a previous version
+of the Helm chart. The rollback is refused if objects are stored in a version the previous CRDs do not serve,
+or if stored objects are invalid against or have fields pruned by the previous CRDs.`,
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			return rollbackCRDs(cmd.Context())
+		},
+	}
+
+	command.Flags().StringVar(&rollbackCRDsDir, "crds-dir", "", "Directory of the CRDs to roll back to.")
+	command.Flags().BoolVar(&dryRun, "dry-run", false, "Only check and print the CRDs that would be rolled back.")
+	_ = command.MarkFlagRequired("crds-dir")
+	return command
+}
+
+func rollbackCRDs(ctx context.Context) error {
+	_, c, err := newClient()
+	if err != nil {
+		return err
+	}
+	crds, err := loadTargetCRDs(rollbackCRDsDir)
+	if err != nil {
+		return err
+	}
+
+	problems, err := checkCRDs(ctx, c, crds, true)
+	if err != nil {
+		return err
+	}
+	if err := report(problems); err != nil {
+		return fmt.Errorf("refusing to roll back CRDs: %v", err)
+	}
+
+	var opts []client.UpdateOption
+	var createOpts []client.CreateOption
+	if dryRun {
+		opts = append(opts, client.DryRunAll)
+		createOpts = append(createOpts, client.DryRunAll)
+	}
+	for _, crd := range crds {
+		installed := &apiextensionsv1.CustomResourceDefinition{}
+		if err := c.Get(ctx, client.ObjectKey{Name: crd.Name}, installed); err != nil {
+			if !errors.IsNotFound(err) {
+				return fmt.Errorf("failed to get CRD %s: %v", crd.Name, err)
+			}
+			if err := c.Create(ctx, crd, createOpts...); err != nil {
+				return fmt.Errorf("failed to create CRD %s: %v", crd.Name, err)
+			}
+			fmt.Printf("Created CRD %s%s\n", crd.Name, dryRunSuffix())
+			continue
+		}
+
+		installed.Spec = crd.Spec
+		for key, value := range crd.Annotations {
+			metav1.SetMetaDataAnnotation(&installed.ObjectMeta, key, value)
+		}
+		if err := c.Update(ctx, installed, opts...); err != nil {
+			return fmt.Errorf("failed to update CRD %s: %v", crd.Name, err)
+		}
+		fmt.Printf("Rolled back CRD %s%s\n", crd.Name, dryRunSuffix())
+	}
+	return nil
+}
+
+func dryRunSuffix() string {
+	if dryRun {
+		return " (dry run)"
+	}
+	return ""
+}
@@ -53,6 +53,7 @@ import (
 	"github.com/kubeflow/spark-operator/api/v1beta2"
 	"github.com/kubeflow/spark-operator/internal/controller/mutatingwebhookconfiguration"
 	"github.com/kubeflow/spark-operator/internal/controller/validatingwebhookconfiguration"
+	"github.com/kubeflow/spark-operator/internal/metrics"
 	"github.com/kubeflow/spark-operator/internal/webhook"
 	"github.com/kubeflow/spark-operator/pkg/certificate"
 	"github.com/kubeflow/spark-operator/pkg/common"
@@ -65,8 +66,12 @@ var (
 	logger = ctrl.Log.WithName("")
 )
 
+// certificateSyncInterval is how often the webhook secret is synced to rotate certificates before they expire.
+const certificateSyncInterval = 10 * time.Minute
+
 var (
 	namespaces          []string
+	namespaceSelector   string
 	labelSelectorFilter string
 
 	// Controller
@@ -75,6 +80,11 @@ var (
 
 	// Webhook
 	enableResourceQuotaEnforcement bool
+	podDefaultsFile                string
+	applicationDefaultsFile        string
+	sparkConfPolicyFile            string
+	webhookDryRun                  bool
+	annotateMutations              bool
 	webhookCertDir                 string
 	webhookCertName                string
 	webhookKeyName                 string
@@ -85,6 +95,14 @@ var (
 	webhookSecretNamespace         string
 	webhookServiceName             string
 	webhookServiceNamespace        string
+	webhookFailurePolicy           string
+	webhookSideEffects             string
+	webhookMatchPolicy             string
+	webhookNamespaceSelector       string
+	webhookObjectSelector          string
+	webhookCertValidity            time.Duration
+	webhookCertRenewBefore         time.Duration
+	webhookRegistrationMaxDelay    time.Duration
 
 	// Leader election
 	enableLeaderElection        bool
@@ -131,6 +149,8 @@ func NewStartCommand() *cobra.Command {
 
 	command.Flags().IntVar(&controllerThreads, "controller-threads", 10, "Number of worker threads used by the SparkApplication controller.")
 	command.Flags().StringSliceVar(&namespaces, "namespaces", []string{}, "The Kubernetes namespace to manage. Will manage custom resource objects of the managed CRD types for the whole cluster if unset or contains empty string.")
+	command.Flags().StringVar(&namespaceSelector, "namespace-selector", "", "Label selector of the namespaces in which Spark pods are mutated, e.g. `spark-jobs=enabled`. "+
+		"Combined with --namespaces if both are set.")
 	command.Flags().StringVar(&labelSelectorFilter, "label-selector-filter", "", "A comma-separated list of key=value, or key labels to filter resources during watch and list based on the specified labels.")
 	command.Flags().DurationVar(&cacheSyncTimeout, "cache-sync-timeout", 30*time.Second, "Informer cache sync timeout.")
 
@@ -145,15 +165,43 @@ func NewStartCommand() *cobra.Command {
 	command.Flags().StringVar(&webhookSecretNamespace, "webhook-secret-namespace", "spark-operator", "The namespace of the secret that contains the webhook server's TLS certificate and key.")
 	command.Flags().StringVar(&webhookServiceName, "webhook-svc-name", "spark-webhook", "The name of the Service for the webhook server.")
 	command.Flags().StringVar(&webhookServiceNamespace, "webhook-svc-namespace", "spark-webhook", "The name of the Service for the webhook server.")
-	command.Flags().BoolVar(&enableResourceQuotaEnforcement, "enable-resource-quota-enforcement", false, "Whether to enable ResourceQuota enforcement for SparkApplication resources. Requires the webhook to be enabled.")
+	command.Flags().StringVar(&webhookFailurePolicy, "webhook-failure-policy", "", "Failure policy enforced on the webhooks, can be one of `Ignore` and `Fail`. "+
+		"Keeps the failure policy the webhooks are registered with if unset.")
+	command.Flags().StringVar(&webhookSideEffects, "webhook-side-effects", "", "Side effects enforced on the webhooks, can be one of `None` and `NoneOnDryRun`. "+
+		"Keeps the side effects the webhooks are registered with if unset.")
+	command.Flags().StringVar(&webhookMatchPolicy, "webhook-match-policy", "", "Match policy enforced on the webhooks, can be one of `Exact` and `Equivalent`. "+
+		"Keeps the match policy the webhooks are registered with if unset.")
+	command.Flags().StringVar(&webhookNamespaceSelector, "webhook-namespace-selector", "", "Label selector enforced as the namespace selector of the webhooks, e.g. `spark-jobs=enabled`. "+
+		"Keeps the namespace selector the webhooks are registered with if unset.")
+	command.Flags().StringVar(&webhookObjectSelector, "webhook-object-selector", "", "Label selector enforced as the object selector of the pod webhook, e.g. `spark-role in (driver,executor)`. "+
+		"Pods not launched by the operator are never sent to the webhook. Keeps the object selector the webhook is registered with if unset.")
+	command.Flags().DurationVar(&webhookCertValidity, "webhook-cert-validity", certificate.DefaultValidity, "Validity of the self-signed webhook server certificate.")
+	command.Flags().DurationVar(&webhookCertRenewBefore, "webhook-cert-renew-before", certificate.DefaultRenewBefore, "How long before their expiry the webhook certificates are rotated. "+
+		"A rotated CA certificate is added to the CA bundle of the webhook configurations along with the previous one, "+
+		"and the server certificate is only switched to one signed by it once the webhook configurations trust it.")
+	command.Flags().DurationVar(&webhookRegistrationMaxDelay, "webhook-registration-max-delay", 5*time.Minute, "The maximum delay between retries of syncing the webhook configurations, "+
+		"which are re-synced periodically and recreated if deleted.")
+	command.Flags().BoolVar(&webhookDryRun, "webhook-dry-run", false, "Whether to run the pod webhook in dry-run mode, in which Spark pods are not mutated. "+
+		"The patch that would have been applied is logged and recorded in the `sparkoperator.k8s.io/dry-run-patch` annotation on the pod instead.")
+	command.Flags().BoolVar(&annotateMutations, "annotate-mutations", false, "Whether to annotate mutated Spark pods with the fields the pod webhook changed "+
+		"in the `sparkoperator.k8s.io/mutations` annotation.")
+	command.Flags().BoolVar(&enableResourceQuotaEnforcement, "enable-resource-quota-enforcement", false, "Whether to enable ResourceQuota enforcement for SparkApplication resources, "+
+		"which also denies Spark pods using resources reserved for the executors of other SparkApplications. Requires the webhook to be enabled.")
+	command.Flags().StringVar(&podDefaultsFile, "pod-defaults-file", "", "Path to a YAML file holding the labels, annotations, node selectors and tolerations "+
+		"added to driver and executor pods by default, e.g. those needed by cluster-autoscaler or Karpenter to provision nodes from scale-from-zero node pools, "+
+		"and the sidecars injected into the driver and executor pods matching their selectors.")
+	command.Flags().StringVar(&applicationDefaultsFile, "application-defaults-file", "", "Path to a YAML file holding the defaults applied to the fields SparkApplications leave unset, "+
+		"e.g. the image, driver service account, restart policy, monitoring settings and Spark configuration properties.")
+	command.Flags().StringVar(&sparkConfPolicyFile, "spark-conf-policy-file", "", "Path to a YAML file holding the Spark configuration properties "+
+		"SparkApplications are forbidden to set and those enforced on every SparkApplication, overriding the values set by users.")
 
 	command.Flags().BoolVar(&enableLeaderElection, "leader-election", false, "Enable leader election for controller manager. "+
 		"Enabling this will ensure there is only one active controller manager.")
-	command.Flags().StringVar(&leaderElectionLockName, "leader-election-lock-name", "spark-operator-lock", "Name of the ConfigMap for leader election.")
-	command.Flags().StringVar(&leaderElectionLockNamespace, "leader-election-lock-namespace", "spark-operator", "Namespace in which to create the ConfigMap for leader election.")
-	command.Flags().DurationVar(&leaderElectionLeaseDuration, "leader-election-lease-duration", 15*time.Second, "Leader election lease duration.")
-	command.Flags().DurationVar(&leaderElectionRenewDeadline, "leader-election-renew-deadline", 14*time.Second, "Leader election renew deadline.")
-	command.Flags().DurationVar(&leaderElectionRetryPeriod, "leader-election-retry-period", 4*time.Second, "Leader election retry period.")
+	command.Flags().StringVar(&leaderElectionLockName, "leader-election-lock-name", "spark-operator-lock", "Name of the Lease for leader election.")
+	command.Flags().StringVar(&leaderElectionLockNamespace, "leader-election-lock-namespace", "spark-operator", "Namespace in which to create the Lease for leader election.")
+	command.Flags().DurationVar(&leaderElectionLeaseDuration, "leader-election-lease-duration", 15*time.Second, "Duration that non-leader candidates wait after observing a leadership renewal before attempting to acquire leadership.")
+	command.Flags().DurationVar(&leaderElectionRenewDeadline, "leader-election-renew-deadline", 14*time.Second, "Duration that the acting leader retries refreshing leadership before giving up, must be shorter than the lease duration.")
+	command.Flags().DurationVar(&leaderElectionRetryPeriod, "leader-election-retry-period", 4*time.Second, "Duration the leader election clients wait between tries of actions, must be shorter than the renew deadline.")
 
 	command.Flags().BoolVar(&enableMetrics, "enable-metrics", false, "Enable metrics.")
 	command.Flags().StringVar(&metricsBindAddress, "metrics-bind-address", "0", "The address the metric endpoint binds to. "+
@@ -185,38 +233,7 @@ func start() {
 	}
 
 	// Create the manager.
-	tlsOptions := newTLSOptions()
-	mgr, err := ctrl.NewManager(cfg, ctrl.Options{
-		Scheme: scheme,
-		Cache:  newCacheOptions(),
-		Metrics: metricsserver.Options{
-			BindAddress:   metricsBindAddress,
-			SecureServing: secureMetrics,
-			TLSOpts:       tlsOptions,
-		},
-		WebhookServer: ctrlwebhook.NewServer(ctrlwebhook.Options{
-			Port:     webhookPort,
-			CertDir:  webhookCertDir,
-			CertName: webhookCertName,
-			KeyName:  webhookKeyName,
-			TLSOpts:  tlsOptions,
-		}),
-		HealthProbeBindAddress:  healthProbeBindAddress,
-		LeaderElection:          enableLeaderElection,
-		LeaderElectionID:        leaderElectionLockName,
-		LeaderElectionNamespace: leaderElectionLockNamespace,
-		// LeaderElectionReleaseOnCancel defines if the leader should step down voluntarily
-		// when the Manager ends. This requires the binary to immediately end when the
-		// Manager is stopped, otherwise, this setting is unsafe. Setting this significantly
-		// speeds up voluntary leader transitions as the new leader don't have to wait
-		// LeaseDuration time first.
-		//
-		// In the default scaffold provided, the program ends immediately after
-		// the manager stops, so would be fine to enable this option. However,
-		// if you are doing or is intended to do any operation such as perform cleanups
-		// after the manager stops then its usage might be unsafe.
-		// LeaderElectionReleaseOnCancel: true,
-	})
+	mgr, err := ctrl.NewManager(cfg, newManagerOptions())
 	if err != nil {
 		logger.Error(err, "Failed to create manager")
 		os.Exit(1)
@@ -228,10 +245,17 @@ func start() {
 		os.Exit(1)
 	}
 
+	if webhookCertRenewBefore <= 0 || webhookCertRenewBefore >= webhookCertValidity {
+		logger.Error(nil, "Webhook certificate renewal period must be positive and shorter than its validity", "validity", webhookCertValidity, "renewBefore", webhookCertRenewBefore)
+		os.Exit(1)
+	}
+
 	certProvider := certificate.NewProvider(
 		client,
 		webhookServiceName,
 		webhookServiceNamespace,
+		webhookCertValidity,
+		webhookCertRenewBefore,
 	)
 
 	if err := wait.ExponentialBackoff(
@@ -262,11 +286,34 @@ func start() {
 		os.Exit(1)
 	}
 
+	certRotator := certificate.NewRotator(
+		certProvider,
+		webhookSecretName,
+		webhookSecretNamespace,
+		mutatingWebhookName,
+		validatingWebhookName,
+		webhookCertDir,
+		webhookCertName,
+		webhookKeyName,
+		certificateSyncInterval,
+	)
+	if err := mgr.Add(certRotator); err != nil {
+		logger.Error(err, "Failed to add certificate rotator")
+		os.Exit(1)
+	}
+
+	admissionPolicy, err := webhook.NewAdmissionPolicy(webhookFailurePolicy, webhookSideEffects, webhookMatchPolicy, webhookNamespaceSelector, webhookObjectSelector)
+	if err != nil {
+		logger.Error(err, "Invalid webhook admission policy")
+		os.Exit(1)
+	}
+
 	if err := mutatingwebhookconfiguration.NewReconciler(
 		mgr.GetClient(),
 		certProvider,
 		mutatingWebhookName,
-	).SetupWithManager(mgr, controller.Options{}); err != nil {
+		admissionPolicy,
+	).SetupWithManager(mgr, newWebhookConfigurationControllerOptions()); err != nil {
 		logger.Error(err, "Failed to create controller", "controller", "MutatingWebhookConfiguration")
 		os.Exit(1)
 	}
@@ -275,15 +322,34 @@ func start() {
 		mgr.GetClient(),
 		certProvider,
 		validatingWebhookName,
-	).SetupWithManager(mgr, controller.Options{}); err != nil {
+		admissionPolicy,
+	).SetupWithManager(mgr, newWebhookConfigurationControllerOptions()); err != nil {
 		logger.Error(err, "Failed to create controller", "controller", "ValidatingWebhookConfiguration")
 		os.Exit(1)
 	}
 
+	var applicationDefaults *webhook.ApplicationDefaults
+	if applicationDefaultsFile != "" {
+		applicationDefaults, err = webhook.LoadApplicationDefaults(applicationDefaultsFile)
+		if err != nil {
+			logger.Error(err, "Failed to load application defaults")
+			os.Exit(1)
+		}
+	}
+
+	var sparkConfPolicy *webhook.SparkConfPolicy
+	if sparkConfPolicyFile != "" {
+		sparkConfPolicy, err = webhook.LoadSparkConfPolicy(sparkConfPolicyFile)
+		if err != nil {
+			logger.Error(err, "Failed to load Spark conf policy")
+			os.Exit(1)
+		}
+	}
+
 	if err := ctrl.NewWebhookManagedBy(mgr).
 		For(&v1beta2.SparkApplication{}).
-		WithDefaulter(webhook.NewSparkApplicationDefaulter()).
-		WithValidator(webhook.NewSparkApplicationValidator(mgr.GetClient(), enableResourceQuotaEnforcement)).
+		WithDefaulter(webhook.NewSparkApplicationDefaulter(mgr.GetClient(), applicationDefaults, sparkConfPolicy)).
+		WithValidator(webhook.NewSparkApplicationValidator(mgr.GetClient(), enableResourceQuotaEnforcement, sparkConfPolicy)).
 		Complete(); err != nil {
 		logger.Error(err, "Failed to create mutating webhook for Spark application")
 		os.Exit(1)
@@ -298,9 +364,24 @@ func start() {
 		os.Exit(1)
 	}
 
+	var podDefaults *webhook.PodDefaults
+	if podDefaultsFile != "" {
+		podDefaults, err = webhook.LoadPodDefaults(podDefaultsFile)
+		if err != nil {
+			logger.Error(err, "Failed to load pod defaults")
+			os.Exit(1)
+		}
+	}
+
+	sparkJobNamespaceSelector, err := labels.Parse(namespaceSelector)
+	if err != nil {
+		logger.Error(err, "Invalid namespace selector")
+		os.Exit(1)
+	}
+
 	if err := ctrl.NewWebhookManagedBy(mgr).
 		For(&corev1.Pod{}).
-		WithDefaulter(webhook.NewSparkPodDefaulter(mgr.GetClient(), namespaces)).
+		WithDefaulter(webhook.NewSparkPodDefaulter(mgr.GetClient(), namespaces, sparkJobNamespaceSelector, podDefaults, webhookDryRun, annotateMutations, enableResourceQuotaEnforcement)).
 		Complete(); err != nil {
 		logger.Error(err, "Failed to create mutating webhook for Spark pod")
 		os.Exit(1)
@@ -318,6 +399,15 @@ func start() {
 		os.Exit(1)
 	}
 
+	if err := mgr.AddReadyzCheck("certificate", certRotator.CheckServingCert); err != nil {
+		logger.Error(err, "Failed to set up certificate ready check")
+		os.Exit(1)
+	}
+
+	if enableMetrics {
+		metrics.NewWebhookCertificateMetrics(metricsPrefix, certProvider, certRotator).Register()
+	}
+
 	logger.Info("Starting manager")
 	if err := mgr.Start(ctrl.SetupSignalHandler()); err != nil {
 		logger.Error(err, "Failed to start manager")
@@ -348,6 +438,45 @@ func setupLog() {
 	)
 }
 
+// newManagerOptions returns the options of the manager, including the configuration of leader election.
+func newManagerOptions() ctrl.Options {
+	tlsOptions := newTLSOptions()
+	return ctrl.Options{
+		Scheme: scheme,
+		Cache:  newCacheOptions(),
+		Metrics: metricsserver.Options{
+			BindAddress:   metricsBindAddress,
+			SecureServing: secureMetrics,
+			TLSOpts:       tlsOptions,
+		},
+		WebhookServer: ctrlwebhook.NewServer(ctrlwebhook.Options{
+			Port:     webhookPort,
+			CertDir:  webhookCertDir,
+			CertName: webhookCertName,
+			KeyName:  webhookKeyName,
+			TLSOpts:  tlsOptions,
+		}),
+		HealthProbeBindAddress:  healthProbeBindAddress,
+		LeaderElection:          enableLeaderElection,
+		LeaderElectionID:        leaderElectionLockName,
+		LeaderElectionNamespace: leaderElectionLockNamespace,
+		LeaseDuration:           &leaderElectionLeaseDuration,
+		RenewDeadline:           &leaderElectionRenewDeadline,
+		RetryPeriod:             &leaderElectionRetryPeriod,
+		// LeaderElectionReleaseOnCancel defines if the leader should step down voluntarily
+		// when the Manager ends. This requires the binary to immediately end when the
+		// Manager is stopped, otherwise, this setting is unsafe. Setting this significantly
+		// speeds up voluntary leader transitions as the new leader don't have to wait
+		// LeaseDuration time first.
+		//
+		// In the default scaffold provided, the program ends immediately after
+		// the manager stops, so would be fine to enable this option. However,
+		// if you are doing or is intended to do any operation such as perform cleanups
+		// after the manager stops then its usage might be unsafe.
+		// LeaderElectionReleaseOnCancel: true,
+	}
+}
+
 func newTLSOptions() []func(c *tls.Config) {
 	// if the enable-http2 flag is false (the default), http/2 should be disabled
 	// due to its vulnerabilities. More specifically, disabling http/2 will
@@ -367,6 +496,14 @@ func newTLSOptions() []func(c *tls.Config) {
 	return tlsOpts
 }
 
+// newWebhookConfigurationControllerOptions creates and returns a controller.Options instance for the controllers
+// syncing the webhook configurations, whose failed syncs are retried with an exponential backoff.
+func newWebhookConfigurationControllerOptions() controller.Options {
+	return controller.Options{
+		RateLimiter: util.NewRateLimiter[ctrl.Request](10, 100, webhookRegistrationMaxDelay),
+	}
+}
+
 // newCacheOptions creates and returns a cache.Options instance configured with default namespaces and object caching settings.
 func newCacheOptions() cache.Options {
 	defaultNamespaces := make(map[string]cache.Config)
@@ -384,6 +521,7 @@ func newCacheOptions() cache.Options {
 		},
 		&v1beta2.SparkApplication{}:          {},
 		&v1beta2.ScheduledSparkApplication{}: {},
+		&v1beta2.SparkApplicationTemplate{}:  {},
 		&admissionregistrationv1.MutatingWebhookConfiguration{}: {
 			Field: fields.SelectorFromSet(fields.Set{
 				"metadata.name": mutatingWebhookName,
@@ -400,6 +538,10 @@ func newCacheOptions() cache.Options {
 		byObject[&corev1.ResourceQuota{}] = cache.ByObject{}
 	}
 
+	if namespaceSelector != "" {
+		byObject[&corev1.Namespace{}] = cache.ByObject{}
+	}
+
 	options := cache.Options{
 		Scheme:            scheme,
 		DefaultNamespaces: defaultNamespaces,
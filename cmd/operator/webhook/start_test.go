@@ -0,0 +1,55 @@
+/*
+Copyright 2024 The Kubeflow authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package webhook
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewManagerOptionsAppliesLeaderElectionFlags(t *testing.T) {
+	command := NewStartCommand()
+	require.NoError(t, command.Flags().Parse([]string{
+		"--leader-election=true",
+		"--leader-election-lock-name=test-lock",
+		"--leader-election-lock-namespace=test-namespace",
+		"--leader-election-lease-duration=60s",
+		"--leader-election-renew-deadline=40s",
+		"--leader-election-retry-period=10s",
+	}))
+
+	options := newManagerOptions()
+	assert.True(t, options.LeaderElection)
+	assert.Equal(t, "test-lock", options.LeaderElectionID)
+	assert.Equal(t, "test-namespace", options.LeaderElectionNamespace)
+	assert.Equal(t, 60*time.Second, *options.LeaseDuration)
+	assert.Equal(t, 40*time.Second, *options.RenewDeadline)
+	assert.Equal(t, 10*time.Second, *options.RetryPeriod)
+}
+
+func TestNewManagerOptionsDefaultsLeaderElectionTimings(t *testing.T) {
+	command := NewStartCommand()
+	require.NoError(t, command.Flags().Parse(nil))
+
+	options := newManagerOptions()
+	assert.Equal(t, 15*time.Second, *options.LeaseDuration)
+	assert.Equal(t, 14*time.Second, *options.RenewDeadline)
+	assert.Equal(t, 4*time.Second, *options.RetryPeriod)
+}
@@ -0,0 +1,122 @@
+/*
+Copyright 2024 The Kubeflow authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package app
+
+import (
+	"context"
+	"fmt"
+	"os"
+
+	"github.com/olekukonko/tablewriter"
+	"github.com/spf13/cobra"
+
+	"github.com/kubeflow/spark-operator/pkg/archive"
+)
+
+var diffRunsCmd = &cobra.Command{
+	Use:   "diff-runs <name> [<run-id> <run-id>]",
+	Short: "Show configuration changes between two runs of a SparkApplication",
+	Long: `Show what changed in the spec and the effective Spark configuration between two submitted runs of a
+SparkApplication, from the configuration snapshots archived in object storage by the operator. Lists the runs
+having a configuration snapshot if no run IDs are given.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		if len(args) != 1 && len(args) != 3 {
+			fmt.Fprintln(os.Stderr, "must specify a SparkApplication name and either zero or two run IDs")
+			return
+		}
+		if ArchiveURL == "" {
+			fmt.Fprintln(os.Stderr, "must specify the archive URL")
+			return
+		}
+
+		if err := doDiffRuns(cmd.Context(), args[0], args[1:]); err != nil {
+			fmt.Fprintf(os.Stderr, "failed to diff runs of SparkApplication %s: %v\n", args[0], err)
+		}
+	},
+}
+
+func init() {
+	diffRunsCmd.Flags().StringVarP(&ArchiveURL, "archive-url", "a", "",
+		"the URL of the bucket the configuration snapshots are archived in, e.g. s3://bucket?region=us-west-1&prefix=archive/")
+}
+
+func doDiffRuns(ctx context.Context, name string, runIDs []string) error {
+	a, err := archive.Open(ctx, ArchiveURL)
+	if err != nil {
+		return err
+	}
+	defer a.Close()
+
+	if len(runIDs) == 0 {
+		return printSnapshots(ctx, a, name)
+	}
+
+	from, err := a.GetSnapshot(ctx, Namespace, name, runIDs[0])
+	if err != nil {
+		return err
+	}
+	to, err := a.GetSnapshot(ctx, Namespace, name, runIDs[1])
+	if err != nil {
+		return err
+	}
+
+	if from.Hash == to.Hash {
+		fmt.Println("No configuration changes")
+		return nil
+	}
+
+	changes, err := archive.Diff(from, to)
+	if err != nil {
+		return err
+	}
+	for _, change := range changes {
+		switch change.Type {
+		case archive.ChangeTypeAdded:
+			fmt.Printf("%s %s: %s\n", change.Type, change.Path, change.New)
+		case archive.ChangeTypeRemoved:
+			fmt.Printf("%s %s: %s\n", change.Type, change.Path, change.Old)
+		default:
+			fmt.Printf("%s %s: %s -> %s\n", change.Type, change.Path, change.Old, change.New)
+		}
+	}
+
+	return nil
+}
+
+func printSnapshots(ctx context.Context, a *archive.Archive, name string) error {
+	records, err := a.ListSnapshots(ctx, Namespace, name)
+	if err != nil {
+		return err
+	}
+
+	table := tablewriter.NewWriter(os.Stdout)
+	table.SetHeader([]string{"Run ID", "Config Hash", "Submission Age"})
+	for _, record := range records {
+		snapshot, err := a.GetSnapshot(ctx, Namespace, name, record.RunID)
+		if err != nil {
+			return err
+		}
+		table.Append([]string{
+			record.RunID,
+			snapshot.Hash[:12],
+			getSinceTime(snapshot.SubmissionTime),
+		})
+	}
+	table.Render()
+
+	return nil
+}
@@ -0,0 +1,120 @@
+/*
+Copyright 2024 The Kubeflow authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package app
+
+import (
+	"context"
+	"fmt"
+	"os"
+
+	"github.com/olekukonko/tablewriter"
+	"github.com/spf13/cobra"
+	"sigs.k8s.io/yaml"
+
+	"github.com/kubeflow/spark-operator/pkg/archive"
+)
+
+var ArchiveURL string
+var RunID string
+
+var historyCmd = &cobra.Command{
+	Use:   "history [name]",
+	Short: "Query archived runs of SparkApplications",
+	Long: `Query runs of SparkApplications archived in object storage by the operator. Lists the archived runs
+in a given namespace, optionally of a given SparkApplication, or prints a single archived run if a run ID is given.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		if len(args) > 1 {
+			fmt.Fprintln(os.Stderr, "must specify at most one SparkApplication name")
+			return
+		}
+		if ArchiveURL == "" {
+			fmt.Fprintln(os.Stderr, "must specify the archive URL")
+			return
+		}
+
+		name := ""
+		if len(args) == 1 {
+			name = args[0]
+		}
+		if RunID != "" && name == "" {
+			fmt.Fprintln(os.Stderr, "must specify a SparkApplication name together with a run ID")
+			return
+		}
+
+		if err := doHistory(cmd.Context(), name); err != nil {
+			fmt.Fprintf(os.Stderr, "failed to query archived SparkApplications: %v\n", err)
+		}
+	},
+}
+
+func init() {
+	historyCmd.Flags().StringVarP(&ArchiveURL, "archive-url", "a", "",
+		"the URL of the bucket the SparkApplications are archived in, e.g. s3://bucket?region=us-west-1&prefix=archive/")
+	historyCmd.Flags().StringVar(&RunID, "run-id", "",
+		"the ID of the archived run to print")
+}
+
+func doHistory(ctx context.Context, name string) error {
+	a, err := archive.Open(ctx, ArchiveURL)
+	if err != nil {
+		return err
+	}
+	defer a.Close()
+
+	if RunID != "" {
+		return printArchivedRun(ctx, a, name)
+	}
+
+	records, err := a.List(ctx, Namespace, name)
+	if err != nil {
+		return err
+	}
+
+	table := tablewriter.NewWriter(os.Stdout)
+	table.SetHeader([]string{"Name", "Run ID", "State", "Submission Age", "Termination Age"})
+	for _, record := range records {
+		app, err := a.Get(ctx, record.Key)
+		if err != nil {
+			return err
+		}
+		table.Append([]string{
+			record.Name,
+			record.RunID,
+			string(app.Status.AppState.State),
+			getSinceTime(app.Status.LastSubmissionAttemptTime),
+			getSinceTime(app.Status.TerminationTime),
+		})
+	}
+	table.Render()
+
+	return nil
+}
+
+func printArchivedRun(ctx context.Context, a *archive.Archive, name string) error {
+	app, err := a.Get(ctx, archive.GetKey(Namespace, name, RunID))
+	if err != nil {
+		return err
+	}
+
+	data, err := yaml.Marshal(app)
+	if err != nil {
+		return fmt.Errorf("failed to marshal SparkApplication %s: %v", name, err)
+	}
+	fmt.Print(string(data))
+
+	return nil
+}
@@ -18,21 +18,42 @@ package app
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
+	"net/http"
+	"net/url"
 	"os"
+	"strings"
 
 	"github.com/olekukonko/tablewriter"
 	"github.com/spf13/cobra"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
 
+	"github.com/kubeflow/spark-operator/api/v1beta2"
+	"github.com/kubeflow/spark-operator/internal/query"
 	crdclientset "github.com/kubeflow/spark-operator/pkg/client/clientset/versioned"
+	"github.com/kubeflow/spark-operator/pkg/common"
 )
 
+var AllNamespaces bool
+var StateFilter string
+var QueueFilter string
+var ScheduledAppFilter string
+var QueryURL string
+
 var listCmd = &cobra.Command{
 	Use:   "list",
 	Short: "List SparkApplication objects",
 	Long:  `List SparkApplication objects in a given namespaces.`,
-	Run: func(_ *cobra.Command, args []string) {
+	Run: func(cmd *cobra.Command, args []string) {
+		if QueryURL != "" {
+			if err := doQuery(cmd.Context()); err != nil {
+				fmt.Fprintf(os.Stderr, "failed to query SparkApplications: %v\n", err)
+			}
+			return
+		}
+
 		crdClientset, err := getSparkApplicationClient()
 		if err != nil {
 			fmt.Fprintf(os.Stderr, "failed to get SparkApplication client: %v\n", err)
@@ -45,23 +66,109 @@ var listCmd = &cobra.Command{
 	},
 }
 
+func init() {
+	listCmd.Flags().BoolVarP(&AllNamespaces, "all-namespaces", "A", false,
+		"whether to list SparkApplications across all namespaces")
+	listCmd.Flags().StringVar(&StateFilter, "state", "",
+		"only list SparkApplications in the given state, e.g. RUNNING")
+	listCmd.Flags().StringVar(&QueueFilter, "queue", "",
+		"only list SparkApplications in the given batch scheduler queue")
+	listCmd.Flags().StringVar(&ScheduledAppFilter, "scheduled-app", "",
+		"only list SparkApplications created by the given ScheduledSparkApplication")
+	listCmd.Flags().StringVar(&QueryURL, "query-url", "",
+		"the URL of the query API of the operator, e.g. http://localhost:8090. If set, the operator is queried "+
+			"using its indexes instead of listing and filtering the SparkApplications on the client side")
+}
+
 func doList(crdClientset crdclientset.Interface) error {
-	apps, err := crdClientset.SparkoperatorV1beta2().SparkApplications(Namespace).List(context.TODO(), metav1.ListOptions{})
+	opts := metav1.ListOptions{}
+	if ScheduledAppFilter != "" {
+		opts.LabelSelector = labels.SelectorFromSet(labels.Set{common.LabelScheduledSparkAppName: ScheduledAppFilter}).String()
+	}
+	apps, err := crdClientset.SparkoperatorV1beta2().SparkApplications(getListNamespace()).List(context.TODO(), opts)
 	if err != nil {
 		return err
 	}
 
-	table := tablewriter.NewWriter(os.Stdout)
-	table.SetHeader([]string{"Name", "State", "Submission Age", "Termination Age"})
+	var filtered []v1beta2.SparkApplication
 	for _, app := range apps.Items {
-		table.Append([]string{
+		if StateFilter != "" && !strings.EqualFold(string(app.Status.AppState.State), StateFilter) {
+			continue
+		}
+		if QueueFilter != "" && (app.Spec.BatchSchedulerOptions == nil || app.Spec.BatchSchedulerOptions.Queue == nil ||
+			*app.Spec.BatchSchedulerOptions.Queue != QueueFilter) {
+			continue
+		}
+		filtered = append(filtered, app)
+	}
+	printApplications(filtered)
+
+	return nil
+}
+
+func doQuery(ctx context.Context) error {
+	params := url.Values{}
+	if namespace := getListNamespace(); namespace != "" {
+		params.Set(query.ParamNamespace, namespace)
+	}
+	if StateFilter != "" {
+		params.Set(query.ParamState, strings.ToUpper(StateFilter))
+	}
+	if QueueFilter != "" {
+		params.Set(query.ParamQueue, QueueFilter)
+	}
+	if ScheduledAppFilter != "" {
+		params.Set(query.ParamScheduledApp, ScheduledAppFilter)
+	}
+
+	requestURL := strings.TrimSuffix(QueryURL, "/") + query.SparkApplicationsPath + "?" + params.Encode()
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, requestURL, nil)
+	if err != nil {
+		return err
+	}
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("unexpected response status %s", resp.Status)
+	}
+
+	apps := &v1beta2.SparkApplicationList{}
+	if err := json.NewDecoder(resp.Body).Decode(apps); err != nil {
+		return fmt.Errorf("failed to decode response: %v", err)
+	}
+	printApplications(apps.Items)
+
+	return nil
+}
+
+func getListNamespace() string {
+	if AllNamespaces {
+		return metav1.NamespaceAll
+	}
+	return Namespace
+}
+
+func printApplications(apps []v1beta2.SparkApplication) {
+	table := tablewriter.NewWriter(os.Stdout)
+	header := []string{"Name", "State", "Submission Age", "Termination Age"}
+	if AllNamespaces {
+		header = append([]string{"Namespace"}, header...)
+	}
+	table.SetHeader(header)
+	for _, app := range apps {
+		row := []string{
 			app.Name,
 			string(app.Status.AppState.State),
 			getSinceTime(app.Status.LastSubmissionAttemptTime),
 			getSinceTime(app.Status.TerminationTime),
-		})
+		}
+		if AllNamespaces {
+			row = append([]string{app.Namespace}, row...)
+		}
+		table.Append(row)
 	}
 	table.Render()
-
-	return nil
 }
@@ -0,0 +1,81 @@
+/*
+Copyright 2024 The Kubeflow authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package app
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+
+	"github.com/olekukonko/tablewriter"
+	"github.com/spf13/cobra"
+
+	"github.com/kubeflow/spark-operator/api/v1beta2"
+	"github.com/kubeflow/spark-operator/pkg/schema"
+)
+
+var SchemaVersion string
+
+var schemaCmd = &cobra.Command{
+	Use:   "schema [kind]",
+	Short: "Print the schemas of the Spark operator",
+	Long: `Print the OpenAPI schema of a kind of the Spark operator, e.g. SparkApplication, for use in IDEs and
+validation pipelines. Lists the available schemas if no kind is given.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		if len(args) > 1 {
+			fmt.Fprintln(os.Stderr, "must specify at most one kind")
+			return
+		}
+
+		if err := doSchema(args); err != nil {
+			fmt.Fprintf(os.Stderr, "failed to print schema: %v\n", err)
+		}
+	},
+}
+
+func init() {
+	schemaCmd.Flags().StringVar(&SchemaVersion, "version", v1beta2.SchemeGroupVersion.Version,
+		"the API version of the schema to print")
+}
+
+func doSchema(args []string) error {
+	if len(args) == 0 {
+		schemas, err := schema.List()
+		if err != nil {
+			return err
+		}
+
+		table := tablewriter.NewWriter(os.Stdout)
+		table.SetHeader([]string{"Group", "Version", "Kind", "Storage"})
+		for _, s := range schemas {
+			table.Append([]string{s.Group, s.Version, s.Kind, fmt.Sprintf("%t", s.Storage)})
+		}
+		table.Render()
+		return nil
+	}
+
+	s, err := schema.Get(v1beta2.SchemeGroupVersion.WithKind(args[0]).GroupKind().WithVersion(SchemaVersion))
+	if err != nil {
+		return err
+	}
+	data, err := json.MarshalIndent(s.OpenAPIV3Schema, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal schema: %v", err)
+	}
+	fmt.Println(string(data))
+	return nil
+}
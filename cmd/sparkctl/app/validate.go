@@ -0,0 +1,94 @@
+/*
+Copyright 2024 The Kubeflow authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package app
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/spf13/cobra"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/util/yaml"
+
+	"github.com/kubeflow/spark-operator/pkg/schema"
+)
+
+var validateCmd = &cobra.Command{
+	Use:   "validate <yaml file>...",
+	Short: "Validate manifests against the schemas of the Spark operator",
+	Long: `Validate SparkApplication and other Spark operator manifests offline against the OpenAPI schemas of the
+CustomResourceDefinitions embedded in sparkctl, which match the operator version sparkctl is released with.
+Exits with a non-zero code if any manifest is invalid.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		if len(args) == 0 {
+			fmt.Fprintln(os.Stderr, "must specify at least one YAML file")
+			os.Exit(1)
+		}
+
+		valid := true
+		for _, file := range args {
+			ok, err := doValidate(file)
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "failed to validate %s: %v\n", file, err)
+			}
+			valid = valid && ok && err == nil
+		}
+		if !valid {
+			os.Exit(1)
+		}
+	},
+}
+
+func doValidate(file string) (bool, error) {
+	f, err := os.Open(file)
+	if err != nil {
+		return false, err
+	}
+	defer f.Close()
+
+	valid := true
+	decoder := yaml.NewYAMLOrJSONDecoder(f, bufferSize)
+	for {
+		obj := &unstructured.Unstructured{}
+		if err := decoder.Decode(&obj.Object); err != nil {
+			if errors.Is(err, io.EOF) {
+				break
+			}
+			return false, err
+		}
+		if len(obj.Object) == 0 {
+			continue
+		}
+
+		errs, err := schema.Validate(obj)
+		if err != nil {
+			return false, err
+		}
+		if len(errs) == 0 {
+			fmt.Printf("%s: %s %s is valid\n", file, obj.GetKind(), obj.GetName())
+			continue
+		}
+		valid = false
+		fmt.Printf("%s: %s %s is invalid:\n", file, obj.GetKind(), obj.GetName())
+		for _, e := range errs {
+			fmt.Printf("  %s\n", e.Error())
+		}
+	}
+	return valid, nil
+}
@@ -0,0 +1,54 @@
+//go:build !chaos
+
+/*
+Copyright 2024 The Kubeflow authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package chaos
+
+import (
+	"context"
+
+	"github.com/spf13/pflag"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/manager"
+)
+
+// Enabled tells whether the binary is built with fault injection.
+const Enabled = false
+
+// AddFlags adds the flags configuring the injected faults. No flags are added without fault injection.
+func AddFlags(_ *pflag.FlagSet) {}
+
+// Setup sets up fault injection with the manager. It is a no-op without fault injection.
+func Setup(_ manager.Manager) error {
+	return nil
+}
+
+// DelaySubmission delays the submission of a SparkApplication. It is a no-op without fault injection.
+func DelaySubmission(_ context.Context) error {
+	return nil
+}
+
+// DropPodEvent tells whether a Spark pod event should be dropped. It is always false without fault injection.
+func DropPodEvent() bool {
+	return false
+}
+
+// InjectStatusUpdateConflict returns a conflict error if a status update of the object should fail.
+// It always returns nil without fault injection.
+func InjectStatusUpdateConflict(_ client.Object) error {
+	return nil
+}
@@ -0,0 +1,162 @@
+//go:build chaos
+
+/*
+Copyright 2024 The Kubeflow authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package chaos
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"math/rand/v2"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/spf13/pflag"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/manager"
+
+	"github.com/kubeflow/spark-operator/api/v1beta2"
+)
+
+// Enabled tells whether the binary is built with fault injection.
+const Enabled = true
+
+var (
+	logger = logf.Log.WithName("chaos")
+
+	mu          sync.RWMutex
+	config      Config
+	bindAddress string
+)
+
+// AddFlags adds the flags configuring the injected faults.
+func AddFlags(flagSet *pflag.FlagSet) {
+	flagSet.DurationVar(&config.SubmissionDelay, "chaos-submission-delay", 0, "Delay every submission of a SparkApplication by the given duration.")
+	flagSet.Float64Var(&config.PodEventDropRate, "chaos-pod-event-drop-rate", 0, "Ratio in [0, 1] of Spark pod events dropped before they are enqueued.")
+	flagSet.Float64Var(&config.StatusUpdateConflictRate, "chaos-status-update-conflict-rate", 0, "Ratio in [0, 1] of SparkApplication status updates failed with a conflict.")
+	flagSet.StringVar(&bindAddress, "chaos-bind-address", "0", "The address the endpoint to read and change the injected faults at runtime binds to. "+
+		"If not set, it will be 0 in order to disable the endpoint")
+}
+
+// Setup validates the configured faults and adds the endpoint to change them at runtime to the manager.
+func Setup(mgr manager.Manager) error {
+	if err := GetConfig().Validate(); err != nil {
+		return err
+	}
+	logger.Info("Fault injection is enabled", "config", GetConfig())
+
+	if bindAddress == "0" {
+		return nil
+	}
+	return mgr.Add(manager.RunnableFunc(func(ctx context.Context) error {
+		mux := http.NewServeMux()
+		mux.HandleFunc(EndpointPath, handleConfig)
+		server := &http.Server{
+			Addr:              bindAddress,
+			Handler:           mux,
+			ReadHeaderTimeout: 10 * time.Second,
+		}
+		go func() {
+			<-ctx.Done()
+			_ = server.Close()
+		}()
+		if err := server.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			return err
+		}
+		return nil
+	}))
+}
+
+// GetConfig returns the current fault injection configuration.
+func GetConfig() Config {
+	mu.RLock()
+	defer mu.RUnlock()
+	return config
+}
+
+// SetConfig replaces the fault injection configuration.
+func SetConfig(c Config) error {
+	if err := c.Validate(); err != nil {
+		return err
+	}
+	mu.Lock()
+	defer mu.Unlock()
+	config = c
+	return nil
+}
+
+// DelaySubmission delays the submission of a SparkApplication by the configured duration.
+func DelaySubmission(ctx context.Context) error {
+	delay := GetConfig().SubmissionDelay
+	if delay <= 0 {
+		return nil
+	}
+	logger.Info("Delaying submission", "delay", delay)
+	select {
+	case <-time.After(delay):
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// DropPodEvent tells whether a Spark pod event should be dropped according to the configured rate.
+func DropPodEvent() bool {
+	if rand.Float64() < GetConfig().PodEventDropRate {
+		logger.Info("Dropping Spark pod event")
+		return true
+	}
+	return false
+}
+
+// InjectStatusUpdateConflict returns a conflict error according to the configured rate.
+func InjectStatusUpdateConflict(obj client.Object) error {
+	if rand.Float64() < GetConfig().StatusUpdateConflictRate {
+		logger.Info("Injecting conflict into status update", "name", obj.GetName(), "namespace", obj.GetNamespace())
+		resource := schema.GroupResource{Group: v1beta2.GroupVersion.Group, Resource: "sparkapplications"}
+		return apierrors.NewConflict(resource, obj.GetName(), errors.New("conflict injected by fault injection"))
+	}
+	return nil
+}
+
+func handleConfig(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case http.MethodGet:
+	case http.MethodPut:
+		c := Config{}
+		if err := json.NewDecoder(r.Body).Decode(&c); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		if err := SetConfig(c); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		logger.Info("Updated fault injection configuration", "config", c)
+	default:
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(GetConfig())
+}
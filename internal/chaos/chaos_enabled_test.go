@@ -0,0 +1,63 @@
+//go:build chaos
+
+/*
+Copyright 2024 The Kubeflow authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package chaos
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+
+	"github.com/kubeflow/spark-operator/api/v1beta2"
+)
+
+func TestFaultInjection(t *testing.T) {
+	defer func() { _ = SetConfig(Config{}) }()
+	app := &v1beta2.SparkApplication{}
+
+	require.NoError(t, SetConfig(Config{}))
+	assert.False(t, DropPodEvent())
+	assert.NoError(t, InjectStatusUpdateConflict(app))
+	assert.NoError(t, DelaySubmission(context.Background()))
+
+	require.NoError(t, SetConfig(Config{PodEventDropRate: 1, StatusUpdateConflictRate: 1}))
+	assert.True(t, DropPodEvent())
+	assert.True(t, apierrors.IsConflict(InjectStatusUpdateConflict(app)))
+}
+
+func TestHandleConfig(t *testing.T) {
+	defer func() { _ = SetConfig(Config{}) }()
+
+	recorder := httptest.NewRecorder()
+	body := strings.NewReader(`{"submissionDelay": 5000000000, "podEventDropRate": 0.25}`)
+	handleConfig(recorder, httptest.NewRequest(http.MethodPut, EndpointPath, body))
+	require.Equal(t, http.StatusOK, recorder.Code)
+	assert.Equal(t, 0.25, GetConfig().PodEventDropRate)
+
+	recorder = httptest.NewRecorder()
+	body = strings.NewReader(`{"podEventDropRate": 2}`)
+	handleConfig(recorder, httptest.NewRequest(http.MethodPut, EndpointPath, body))
+	assert.Equal(t, http.StatusBadRequest, recorder.Code)
+	assert.Equal(t, 0.25, GetConfig().PodEventDropRate)
+}
@@ -0,0 +1,51 @@
+/*
+Copyright 2024 The Kubeflow authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package chaos
+
+import (
+	"fmt"
+	"time"
+)
+
+const (
+	// EndpointPath is the path of the HTTP endpoint to read and change the fault injection configuration.
+	EndpointPath = "/chaos"
+)
+
+// Config configures the faults injected into the controller.
+type Config struct {
+	// SubmissionDelay delays every submission of a SparkApplication by the given duration.
+	SubmissionDelay time.Duration `json:"submissionDelay,omitempty"`
+	// PodEventDropRate is the ratio in [0, 1] of Spark pod events dropped before they are enqueued.
+	PodEventDropRate float64 `json:"podEventDropRate,omitempty"`
+	// StatusUpdateConflictRate is the ratio in [0, 1] of SparkApplication status updates failed with a conflict.
+	StatusUpdateConflictRate float64 `json:"statusUpdateConflictRate,omitempty"`
+}
+
+// Validate checks whether the configuration is valid.
+func (c Config) Validate() error {
+	if c.SubmissionDelay < 0 {
+		return fmt.Errorf("submission delay must not be negative")
+	}
+	if c.PodEventDropRate < 0 || c.PodEventDropRate > 1 {
+		return fmt.Errorf("pod event drop rate must be between 0 and 1")
+	}
+	if c.StatusUpdateConflictRate < 0 || c.StatusUpdateConflictRate > 1 {
+		return fmt.Errorf("status update conflict rate must be between 0 and 1")
+	}
+	return nil
+}
@@ -0,0 +1,71 @@
+/*
+Copyright 2024 The Kubeflow authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package chaos
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestConfigValidate(t *testing.T) {
+	testCases := []struct {
+		name    string
+		config  Config
+		wantErr bool
+	}{
+		{
+			name:   "empty config",
+			config: Config{},
+		},
+		{
+			name: "valid config",
+			config: Config{
+				SubmissionDelay:          10 * time.Second,
+				PodEventDropRate:         0.5,
+				StatusUpdateConflictRate: 1,
+			},
+		},
+		{
+			name:    "negative submission delay",
+			config:  Config{SubmissionDelay: -time.Second},
+			wantErr: true,
+		},
+		{
+			name:    "pod event drop rate out of range",
+			config:  Config{PodEventDropRate: 1.5},
+			wantErr: true,
+		},
+		{
+			name:    "status update conflict rate out of range",
+			config:  Config{StatusUpdateConflictRate: -0.1},
+			wantErr: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			err := tc.config.Validate()
+			if tc.wantErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
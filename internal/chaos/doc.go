@@ -0,0 +1,22 @@
+/*
+Copyright 2024 The Kubeflow authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package chaos implements a fault injection layer for resilience testing of the controller state machine.
+//
+// Faults are only injected in binaries built with the `chaos` build tag, e.g. `make build-operator-chaos`.
+// Such binaries accept flags configuring the faults and optionally serve an HTTP endpoint to read and change
+// them at runtime. In regular builds, all the hooks of this package are no-ops and no flags are added.
+package chaos
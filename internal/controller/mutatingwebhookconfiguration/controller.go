@@ -19,15 +19,22 @@ package mutatingwebhookconfiguration
 import (
 	"context"
 	"fmt"
+	"time"
 
 	admissionregistrationv1 "k8s.io/api/admissionregistration/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/types"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/builder"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/controller"
+	"sigs.k8s.io/controller-runtime/pkg/event"
 	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+	"sigs.k8s.io/controller-runtime/pkg/source"
 
+	"github.com/kubeflow/spark-operator/internal/webhook"
 	"github.com/kubeflow/spark-operator/pkg/certificate"
 )
 
@@ -35,26 +42,44 @@ var (
 	logger = ctrl.Log.WithName("")
 )
 
+// resyncPeriod is how often the webhook configuration is re-synced, so that changes made by other cluster tools are
+// reverted. Changes of the CA bundle of the certificate provider are synced as soon as they happen.
+const resyncPeriod = 10 * time.Minute
+
 // Reconciler reconciles a webhook configuration object.
 type Reconciler struct {
 	client       client.Client
 	certProvider *certificate.Provider
 	name         string
+	policy       webhook.AdmissionPolicy
+	// lastApplied is the last webhook configuration synced, from which the webhook configuration is recreated
+	// if it gets deleted.
+	lastApplied *admissionregistrationv1.MutatingWebhookConfiguration
 }
 
 // MutatingWebhookConfigurationReconciler implements reconcile.Reconciler.
 var _ reconcile.Reconciler = &Reconciler{}
 
 // NewReconciler creates a new MutatingWebhookConfigurationReconciler instance.
-func NewReconciler(client client.Client, certProvider *certificate.Provider, name string) *Reconciler {
+func NewReconciler(client client.Client, certProvider *certificate.Provider, name string, policy webhook.AdmissionPolicy) *Reconciler {
 	return &Reconciler{
 		client:       client,
 		certProvider: certProvider,
 		name:         name,
+		policy:       policy,
 	}
 }
 
 func (r *Reconciler) SetupWithManager(mgr ctrl.Manager, options controller.Options) error {
+	// A change of the CA bundle, e.g. by a CA rotation, is synced right away rather than on the next resync.
+	caBundleEvents := make(chan event.GenericEvent, 1)
+	r.certProvider.OnCABundleChange(func() {
+		select {
+		case caBundleEvents <- event.GenericEvent{Object: &admissionregistrationv1.MutatingWebhookConfiguration{ObjectMeta: metav1.ObjectMeta{Name: r.name}}}:
+		default:
+		}
+	})
+
 	return ctrl.NewControllerManagedBy(mgr).
 		Named("mutating-webhook-configuration-controller").
 		Watches(
@@ -64,36 +89,91 @@ func (r *Reconciler) SetupWithManager(mgr ctrl.Manager, options controller.Optio
 				NewEventFilter(r.name),
 			),
 		).
+		WatchesRawSource(source.Channel(caBundleEvents, NewEventHandler())).
 		WithOptions(options).
 		Complete(r)
 }
 
 func (r *Reconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
-	logger.Info("Updating CA bundle of MutatingWebhookConfiguration", "name", req.Name)
+	logger.V(1).Info("Syncing MutatingWebhookConfiguration", "name", req.Name)
 	if err := r.updateMutatingWebhookConfiguration(ctx, req.NamespacedName); err != nil {
 		return ctrl.Result{Requeue: true}, err
 	}
-	return ctrl.Result{}, nil
+	return ctrl.Result{RequeueAfter: resyncPeriod}, nil
 }
 
 func (r *Reconciler) updateMutatingWebhookConfiguration(ctx context.Context, key types.NamespacedName) error {
-	webhook := &admissionregistrationv1.MutatingWebhookConfiguration{}
-	if err := r.client.Get(ctx, key, webhook); err != nil {
-		return fmt.Errorf("failed to get mutating webhook configuration %v: %v", key, err)
+	webhookConfig := &admissionregistrationv1.MutatingWebhookConfiguration{}
+	if err := r.client.Get(ctx, key, webhookConfig); err != nil {
+		if !errors.IsNotFound(err) || r.lastApplied == nil {
+			return fmt.Errorf("failed to get mutating webhook configuration %v: %v", key, err)
+		}
+		return r.recreateMutatingWebhookConfiguration(ctx, key)
 	}
 
-	caBundle, err := r.certProvider.CACert()
-	if err != nil {
-		return fmt.Errorf("failed to get CA certificate: %v", err)
+	newWebhook := webhookConfig.DeepCopy()
+	if err := r.syncWebhooks(newWebhook); err != nil {
+		return err
 	}
-
-	newWebhook := webhook.DeepCopy()
-	for i := range newWebhook.Webhooks {
-		newWebhook.Webhooks[i].ClientConfig.CABundle = caBundle
+	if equality.Semantic.DeepEqual(webhookConfig, newWebhook) {
+		r.lastApplied = newWebhook
+		return nil
 	}
+	logger.Info("Updating MutatingWebhookConfiguration", "name", key.Name)
 	if err := r.client.Update(ctx, newWebhook); err != nil {
 		return fmt.Errorf("failed to update mutating webhook configuration %v: %v", key, err)
 	}
+	r.lastApplied = newWebhook
+
+	return nil
+}
+
+// recreateMutatingWebhookConfiguration recreates the deleted webhook configuration from the last one synced.
+func (r *Reconciler) recreateMutatingWebhookConfiguration(ctx context.Context, key types.NamespacedName) error {
+	webhookConfig := &admissionregistrationv1.MutatingWebhookConfiguration{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        r.lastApplied.Name,
+			Labels:      r.lastApplied.Labels,
+			Annotations: r.lastApplied.Annotations,
+		},
+		Webhooks: r.lastApplied.Webhooks,
+	}
+	webhookConfig = webhookConfig.DeepCopy()
+	if err := r.syncWebhooks(webhookConfig); err != nil {
+		return err
+	}
+
+	logger.Info("Recreating deleted MutatingWebhookConfiguration", "name", key.Name)
+	if err := r.client.Create(ctx, webhookConfig); err != nil && !errors.IsAlreadyExists(err) {
+		return fmt.Errorf("failed to recreate mutating webhook configuration %v: %v", key, err)
+	}
+	return nil
+}
 
+// syncWebhooks sets the current CA bundle and the admission policy on the webhooks of the webhook configuration.
+func (r *Reconciler) syncWebhooks(webhookConfig *admissionregistrationv1.MutatingWebhookConfiguration) error {
+	caBundle, err := r.certProvider.CABundle()
+	if err != nil {
+		return fmt.Errorf("failed to get CA certificate: %v", err)
+	}
+
+	for i := range webhookConfig.Webhooks {
+		webhookConfig.Webhooks[i].ClientConfig.CABundle = caBundle
+		if r.policy.FailurePolicy != nil {
+			webhookConfig.Webhooks[i].FailurePolicy = r.policy.FailurePolicy
+		}
+		if r.policy.SideEffects != nil {
+			webhookConfig.Webhooks[i].SideEffects = r.policy.SideEffects
+		}
+		if r.policy.MatchPolicy != nil {
+			webhookConfig.Webhooks[i].MatchPolicy = r.policy.MatchPolicy
+		}
+		if r.policy.NamespaceSelector != nil {
+			webhookConfig.Webhooks[i].NamespaceSelector = r.policy.NamespaceSelector.DeepCopy()
+		}
+		if r.policy.ObjectSelector != nil && webhook.MatchesPods(webhookConfig.Webhooks[i].Rules) {
+			webhookConfig.Webhooks[i].ObjectSelector = r.policy.ObjectSelector.DeepCopy()
+		}
+	}
 	return nil
 }
@@ -46,8 +46,8 @@ func (f *EventFilter) Update(e event.UpdateEvent) bool {
 }
 
 // Delete implements predicate.Predicate.
-func (f *EventFilter) Delete(event.DeleteEvent) bool {
-	return false
+func (f *EventFilter) Delete(e event.DeleteEvent) bool {
+	return e.Object.GetName() == f.name
 }
 
 // Generic implements predicate.Predicate.
@@ -0,0 +1,394 @@
+/*
+Copyright 2024 The Kubeflow authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package recommendation
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/builder"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	"github.com/kubeflow/spark-operator/api/v1beta2"
+	"github.com/kubeflow/spark-operator/pkg/common"
+	"github.com/kubeflow/spark-operator/pkg/util"
+)
+
+var (
+	logger = ctrl.Log.WithName("")
+)
+
+// Options defines the options of the recommendation controller.
+type Options struct {
+	Namespaces []string
+
+	// MemoryIncreaseFactor is the factor by which the memory is increased when a pod was killed
+	// because it ran out of memory.
+	MemoryIncreaseFactor float64
+
+	// HistoryLimit is the number of runs of an application kept in its run history.
+	HistoryLimit int
+
+	// Headroom is the factor applied to the peak usage of runs to size the driver and executors.
+	Headroom float64
+
+	// PrometheusURL is the URL of the Prometheus server the peak usage of runs is queried from. Resources
+	// are only recommended after OOM kills if empty.
+	PrometheusURL string
+}
+
+// Reconciler analyzes terminated SparkApplications and publishes driver and executor core and memory
+// recommendations as annotations and events, both on the SparkApplication and on the
+// ScheduledSparkApplication it was created from, if any.
+//
+// Every terminated run is appended to a run history recording its duration, OOM kills and the peak
+// usage of its driver and executors queried from Prometheus. The history is kept on the
+// ScheduledSparkApplication, so that it spans its runs, or else on the SparkApplication, so that it
+// spans its resubmissions. Memory is raised after an OOM kill in the last run and, once enough
+// completed runs were measured, cores and memory are sized to fit the peak usage of the history with
+// some headroom.
+type Reconciler struct {
+	client   client.Client
+	recorder record.EventRecorder
+	options  Options
+	usage    usageSource
+}
+
+// Reconciler implements reconcile.Reconciler.
+var _ reconcile.Reconciler = &Reconciler{}
+
+// NewReconciler creates a new Reconciler instance.
+func NewReconciler(client client.Client, recorder record.EventRecorder, options Options) *Reconciler {
+	r := &Reconciler{
+		client:   client,
+		recorder: recorder,
+		options:  options,
+	}
+	if options.PrometheusURL != "" {
+		r.usage = &prometheusUsageSource{url: options.PrometheusURL}
+	}
+	return r
+}
+
+// SetupWithManager sets up the controller with the Manager.
+func (r *Reconciler) SetupWithManager(mgr ctrl.Manager, options controller.Options) error {
+	namespaces := make(map[string]bool)
+	for _, ns := range r.options.Namespaces {
+		namespaces[ns] = true
+	}
+
+	return ctrl.NewControllerManagedBy(mgr).
+		Named("spark-application-recommendation-controller").
+		For(
+			&v1beta2.SparkApplication{},
+			builder.WithPredicates(predicate.NewPredicateFuncs(func(object client.Object) bool {
+				app, ok := object.(*v1beta2.SparkApplication)
+				if !ok {
+					return false
+				}
+				if len(namespaces) > 0 && !namespaces[app.Namespace] && !namespaces[""] {
+					return false
+				}
+				return util.IsTerminated(app) && app.Annotations[common.AnnotationRecommendationSubmissionID] != app.Status.SubmissionID
+			})),
+		).
+		WithOptions(options).
+		Complete(r)
+}
+
+// +kubebuilder:rbac:groups=,resources=pods,verbs=get;list;watch
+// +kubebuilder:rbac:groups=sparkoperator.k8s.io,resources=sparkapplications,verbs=get;list;watch;update;patch
+// +kubebuilder:rbac:groups=sparkoperator.k8s.io,resources=scheduledsparkapplications,verbs=get;list;watch;update;patch
+
+// Reconcile records the last run of a terminated SparkApplication in its run history and derives
+// resource recommendations from the history.
+func (r *Reconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	app := &v1beta2.SparkApplication{}
+	if err := r.client.Get(ctx, req.NamespacedName, app); err != nil {
+		if errors.IsNotFound(err) {
+			return ctrl.Result{}, nil
+		}
+		return ctrl.Result{}, err
+	}
+
+	if !util.IsTerminated(app) || app.Annotations[common.AnnotationRecommendationSubmissionID] == app.Status.SubmissionID {
+		return ctrl.Result{}, nil
+	}
+
+	record, err := r.recordRun(ctx, app)
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+
+	scheduledApp, err := r.getScheduledSparkApplication(ctx, app)
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+
+	historyOwner := client.Object(app)
+	if scheduledApp != nil {
+		historyOwner = scheduledApp
+	}
+	history, err := parseHistory(historyOwner.GetAnnotations())
+	if err != nil {
+		logger.Error(err, "Discarding run history", "name", historyOwner.GetName(), "namespace", historyOwner.GetNamespace())
+	}
+	history = appendHistory(history, record, r.options.HistoryLimit)
+	recommendations := r.recommend(app, history)
+
+	// The ScheduledSparkApplication is annotated first, so that its history is not missed if annotating the
+	// SparkApplication with the submission ID fails.
+	if scheduledApp != nil {
+		if err := r.annotateScheduledSparkApplication(ctx, app, scheduledApp, history, recommendations); err != nil {
+			return ctrl.Result{}, err
+		}
+	}
+
+	patch := client.MergeFrom(app.DeepCopy())
+	if app.Annotations == nil {
+		app.Annotations = make(map[string]string)
+	}
+	setRecommendations(app.Annotations, recommendations)
+	if scheduledApp == nil {
+		value, err := formatHistory(history)
+		if err != nil {
+			return ctrl.Result{}, err
+		}
+		app.Annotations[common.AnnotationRecommendationHistory] = value
+	}
+	app.Annotations[common.AnnotationRecommendationSubmissionID] = app.Status.SubmissionID
+	if err := r.client.Patch(ctx, app, patch); err != nil {
+		return ctrl.Result{}, fmt.Errorf("failed to annotate SparkApplication with recommendations: %v", err)
+	}
+
+	if len(recommendations) == 0 {
+		return ctrl.Result{}, nil
+	}
+
+	logger.Info("Recommending resources for SparkApplication", "name", app.Name, "namespace", app.Namespace, "recommendations", recommendations)
+	r.recorder.Eventf(app, corev1.EventTypeNormal, common.EventSparkApplicationResourceRecommendation,
+		"Recommended resources for SparkApplication %s from %d runs: %s", app.Name, len(history), formatRecommendations(recommendations))
+	return ctrl.Result{}, nil
+}
+
+// recordRun records the duration, OOM kills and, if a usage source is configured, the peak usage of the driver
+// and executors of the last run of the SparkApplication.
+func (r *Reconciler) recordRun(ctx context.Context, app *v1beta2.SparkApplication) (runRecord, error) {
+	record := runRecord{
+		SubmissionID: app.Status.SubmissionID,
+		State:        app.Status.AppState.State,
+	}
+	start := app.Status.LastSubmissionAttemptTime.Time
+	end := app.Status.TerminationTime.Time
+	if !start.IsZero() && end.After(start) {
+		record.DurationSeconds = int64(math.Ceil(end.Sub(start).Seconds()))
+	}
+
+	driverPod := &corev1.Pod{}
+	key := types.NamespacedName{Namespace: app.Namespace, Name: util.GetDriverPodName(app)}
+	if err := r.client.Get(ctx, key, driverPod); err != nil {
+		if !errors.IsNotFound(err) {
+			return record, fmt.Errorf("failed to get driver pod %s: %v", key, err)
+		}
+	} else {
+		record.Driver.OOMKilled = isOOMKilled(util.GetDriverContainerTerminatedState(driverPod))
+	}
+
+	executorPods := &corev1.PodList{}
+	if err := r.client.List(ctx, executorPods, client.InNamespace(app.Namespace), client.MatchingLabels{
+		common.LabelSparkAppName: app.Name,
+		common.LabelSparkRole:    common.SparkRoleExecutor,
+	}); err != nil {
+		return record, fmt.Errorf("failed to list executor pods: %v", err)
+	}
+	for i := range executorPods.Items {
+		if isOOMKilled(util.GetExecutorContainerTerminatedState(&executorPods.Items[i])) {
+			record.Executor.OOMKilled = true
+			break
+		}
+	}
+
+	if r.usage == nil || record.DurationSeconds == 0 {
+		return record, nil
+	}
+
+	// Usage that cannot be measured is left out of the history rather than blocking the recommendations.
+	var err error
+	record.Driver.PeakMemoryMiB, record.Driver.PeakCores, err = r.usage.peakUsage(ctx, app.Namespace, []string{util.GetDriverPodName(app)}, common.SparkDriverContainerName, start, end)
+	if err != nil {
+		logger.Error(err, "Failed to measure driver usage", "name", app.Name, "namespace", app.Namespace)
+	}
+	// Executor pods are usually deleted by the time the run terminates, so their names are taken from the status.
+	var executors []string
+	for name := range app.Status.ExecutorState {
+		executors = append(executors, name)
+	}
+	if len(executors) > 0 {
+		sort.Strings(executors)
+		record.Executor.PeakMemoryMiB, record.Executor.PeakCores, err = r.usage.peakUsage(ctx, app.Namespace, executors, common.Spark3DefaultExecutorContainerName, start, end)
+		if err != nil {
+			logger.Error(err, "Failed to measure executor usage", "name", app.Name, "namespace", app.Namespace)
+		}
+	}
+	return record, nil
+}
+
+// recommend returns the recommended resources keyed by annotation from the run history, latest run last.
+func (r *Reconciler) recommend(app *v1beta2.SparkApplication, history []runRecord) map[string]string {
+	recommendations := make(map[string]string)
+
+	driverUsages := make([]roleUsage, 0, len(history))
+	executorUsages := make([]roleUsage, 0, len(history))
+	for _, record := range history {
+		driverUsages = append(driverUsages, measuredUsage(record, record.Driver))
+		executorUsages = append(executorUsages, measuredUsage(record, record.Executor))
+	}
+
+	if memory, ok := recommendMemory(app, &app.Spec.Driver.SparkPodSpec, driverUsages, r.options.MemoryIncreaseFactor, r.options.Headroom); ok {
+		recommendations[common.AnnotationRecommendedDriverMemory] = memory
+	}
+	if cores, ok := recommendCores(&app.Spec.Driver.SparkPodSpec, history, driverUsages, r.options.Headroom); ok {
+		recommendations[common.AnnotationRecommendedDriverCores] = cores
+	}
+	if memory, ok := recommendMemory(app, &app.Spec.Executor.SparkPodSpec, executorUsages, r.options.MemoryIncreaseFactor, r.options.Headroom); ok {
+		recommendations[common.AnnotationRecommendedExecutorMemory] = memory
+	}
+	if cores, ok := recommendCores(&app.Spec.Executor.SparkPodSpec, history, executorUsages, r.options.Headroom); ok {
+		recommendations[common.AnnotationRecommendedExecutorCores] = cores
+	}
+	return recommendations
+}
+
+// getScheduledSparkApplication returns the ScheduledSparkApplication the SparkApplication was created from, or nil
+// if there is none.
+func (r *Reconciler) getScheduledSparkApplication(ctx context.Context, app *v1beta2.SparkApplication) (*v1beta2.ScheduledSparkApplication, error) {
+	name, ok := app.Labels[common.LabelScheduledSparkAppName]
+	if !ok {
+		return nil, nil
+	}
+
+	scheduledApp := &v1beta2.ScheduledSparkApplication{}
+	if err := r.client.Get(ctx, types.NamespacedName{Namespace: app.Namespace, Name: name}, scheduledApp); err != nil {
+		if errors.IsNotFound(err) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to get ScheduledSparkApplication %s/%s: %v", app.Namespace, name, err)
+	}
+	return scheduledApp, nil
+}
+
+// annotateScheduledSparkApplication records the run history and propagates the recommendations to the
+// ScheduledSparkApplication the SparkApplication was created from, so that they apply to subsequent runs.
+func (r *Reconciler) annotateScheduledSparkApplication(ctx context.Context, app *v1beta2.SparkApplication, scheduledApp *v1beta2.ScheduledSparkApplication, history []runRecord, recommendations map[string]string) error {
+	value, err := formatHistory(history)
+	if err != nil {
+		return err
+	}
+
+	patch := client.MergeFrom(scheduledApp.DeepCopy())
+	if scheduledApp.Annotations == nil {
+		scheduledApp.Annotations = make(map[string]string)
+	}
+	setRecommendations(scheduledApp.Annotations, recommendations)
+	scheduledApp.Annotations[common.AnnotationRecommendationHistory] = value
+	if err := r.client.Patch(ctx, scheduledApp, patch); err != nil {
+		return fmt.Errorf("failed to annotate ScheduledSparkApplication with recommendations: %v", err)
+	}
+
+	if len(recommendations) > 0 {
+		r.recorder.Eventf(scheduledApp, corev1.EventTypeNormal, common.EventSparkApplicationResourceRecommendation,
+			"Recommended resources from run %s and %d earlier runs: %s", app.Name, len(history)-1, formatRecommendations(recommendations))
+	}
+	return nil
+}
+
+// recommendationAnnotations are the annotations recording recommended resources.
+var recommendationAnnotations = []string{
+	common.AnnotationRecommendedDriverCores,
+	common.AnnotationRecommendedDriverMemory,
+	common.AnnotationRecommendedExecutorCores,
+	common.AnnotationRecommendedExecutorMemory,
+}
+
+// setRecommendations replaces the recommendations in the annotations, so that recommendations of earlier runs
+// which no longer apply are removed.
+func setRecommendations(annotations map[string]string, recommendations map[string]string) {
+	for _, key := range recommendationAnnotations {
+		delete(annotations, key)
+		if value, ok := recommendations[key]; ok {
+			annotations[key] = value
+		}
+	}
+}
+
+func isOOMKilled(state *corev1.ContainerStateTerminated) bool {
+	return state != nil && state.Reason == "OOMKilled"
+}
+
+var memoryPattern = regexp.MustCompile(`^([0-9]+)([kmgt]?)b?$`)
+
+var memoryUnitsInMiB = map[string]float64{
+	"":  1.0 / (1024 * 1024),
+	"k": 1.0 / 1024,
+	"m": 1,
+	"g": 1024,
+	"t": 1024 * 1024,
+}
+
+// parseMemoryMiB parses a Java-style memory string (e.g. 512m, 2g) and returns it in MiB, rounded up.
+func parseMemoryMiB(memory string) (int64, error) {
+	matches := memoryPattern.FindStringSubmatch(strings.ToLower(memory))
+	if matches == nil {
+		return 0, fmt.Errorf("could not parse memory %q", memory)
+	}
+
+	value, err := strconv.ParseFloat(matches[1], 64)
+	if err != nil {
+		return 0, err
+	}
+	return int64(math.Ceil(value * memoryUnitsInMiB[matches[2]])), nil
+}
+
+func formatRecommendations(recommendations map[string]string) string {
+	var items []string
+	if cores, ok := recommendations[common.AnnotationRecommendedDriverCores]; ok {
+		items = append(items, fmt.Sprintf("driver cores %s", cores))
+	}
+	if memory, ok := recommendations[common.AnnotationRecommendedDriverMemory]; ok {
+		items = append(items, fmt.Sprintf("driver memory %s", memory))
+	}
+	if cores, ok := recommendations[common.AnnotationRecommendedExecutorCores]; ok {
+		items = append(items, fmt.Sprintf("executor cores %s", cores))
+	}
+	if memory, ok := recommendations[common.AnnotationRecommendedExecutorMemory]; ok {
+		items = append(items, fmt.Sprintf("executor memory %s", memory))
+	}
+	return strings.Join(items, ", ")
+}
@@ -0,0 +1,483 @@
+/*
+Copyright 2024 The Kubeflow authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package recommendation
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/tools/record"
+	"k8s.io/utils/ptr"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	"github.com/kubeflow/spark-operator/api/v1beta2"
+	"github.com/kubeflow/spark-operator/pkg/common"
+)
+
+func TestParseMemoryMiB(t *testing.T) {
+	testCases := []struct {
+		memory   string
+		expected int64
+	}{
+		{memory: "512m", expected: 512},
+		{memory: "1g", expected: 1024},
+		{memory: "2048k", expected: 2},
+		{memory: "1G", expected: 1024},
+		{memory: "1gb", expected: 1024},
+	}
+
+	for _, tc := range testCases {
+		memory, err := parseMemoryMiB(tc.memory)
+		assert.NoError(t, err)
+		assert.Equal(t, tc.expected, memory, tc.memory)
+	}
+
+	_, err := parseMemoryMiB("1.5g")
+	assert.Error(t, err)
+}
+
+func newTestPod(name string, role string, container string, reason string) *corev1.Pod {
+	return &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: "default",
+			Labels: map[string]string{
+				common.LabelSparkAppName: "test-app",
+				common.LabelSparkRole:    role,
+			},
+		},
+		Status: corev1.PodStatus{
+			Phase: corev1.PodFailed,
+			ContainerStatuses: []corev1.ContainerStatus{{
+				Name: container,
+				State: corev1.ContainerState{
+					Terminated: &corev1.ContainerStateTerminated{ExitCode: 137, Reason: reason},
+				},
+			}},
+		},
+	}
+}
+
+func TestReconcile(t *testing.T) {
+	testCases := []struct {
+		name     string
+		pods     []client.Object
+		expected map[string]string
+	}{
+		{
+			name: "raises the memory of OOM killed roles",
+			pods: []client.Object{
+				newTestPod("test-app-driver", common.SparkRoleDriver, common.SparkDriverContainerName, "OOMKilled"),
+				newTestPod("test-app-exec-1", common.SparkRoleExecutor, common.Spark3DefaultExecutorContainerName, "OOMKilled"),
+			},
+			expected: map[string]string{
+				common.AnnotationRecommendedDriverMemory:   "768m",
+				common.AnnotationRecommendedExecutorMemory: "3072m",
+			},
+		},
+		{
+			name: "does not recommend anything for other failures",
+			pods: []client.Object{
+				newTestPod("test-app-driver", common.SparkRoleDriver, common.SparkDriverContainerName, "Error"),
+				newTestPod("test-app-exec-1", common.SparkRoleExecutor, common.Spark3DefaultExecutorContainerName, "Error"),
+			},
+			expected: map[string]string{},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			app := &v1beta2.SparkApplication{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "test-app",
+					Namespace: "default",
+					// Recommendations of earlier runs are replaced.
+					Annotations: map[string]string{common.AnnotationRecommendedDriverCores: "8"},
+				},
+				Spec: v1beta2.SparkApplicationSpec{
+					Driver:   v1beta2.DriverSpec{SparkPodSpec: v1beta2.SparkPodSpec{Memory: ptr.To("512m")}},
+					Executor: v1beta2.ExecutorSpec{SparkPodSpec: v1beta2.SparkPodSpec{Memory: ptr.To("2g")}},
+				},
+				Status: v1beta2.SparkApplicationStatus{
+					SubmissionID: "submission-1",
+					AppState:     v1beta2.ApplicationState{State: v1beta2.ApplicationStateFailed},
+				},
+			}
+
+			scheme := runtime.NewScheme()
+			require.NoError(t, clientgoscheme.AddToScheme(scheme))
+			require.NoError(t, v1beta2.AddToScheme(scheme))
+			c := fake.NewClientBuilder().WithScheme(scheme).WithObjects(append(tc.pods, app)...).Build()
+			r := NewReconciler(c, record.NewFakeRecorder(10), Options{MemoryIncreaseFactor: 1.5})
+
+			key := types.NamespacedName{Namespace: "default", Name: "test-app"}
+			_, err := r.Reconcile(context.Background(), ctrl.Request{NamespacedName: key})
+			require.NoError(t, err)
+
+			require.NoError(t, c.Get(context.Background(), key, app))
+			assert.Equal(t, "submission-1", app.Annotations[common.AnnotationRecommendationSubmissionID])
+			for _, annotation := range recommendationAnnotations {
+				assert.Equal(t, tc.expected[annotation], app.Annotations[annotation], annotation)
+			}
+
+			history, err := parseHistory(app.Annotations)
+			require.NoError(t, err)
+			require.Len(t, history, 1)
+			assert.Equal(t, "submission-1", history[0].SubmissionID)
+			assert.Equal(t, v1beta2.ApplicationStateFailed, history[0].State)
+		})
+	}
+}
+
+// fakeUsageSource returns the peak usage of containers by container name.
+type fakeUsageSource struct {
+	memoryMiB map[string]int64
+	cores     map[string]float64
+	pods      map[string][]string
+}
+
+func (s *fakeUsageSource) peakUsage(_ context.Context, _ string, pods []string, container string, _, _ time.Time) (int64, float64, error) {
+	s.pods[container] = pods
+	return s.memoryMiB[container], s.cores[container], nil
+}
+
+func TestReconcileRecordsHistoryOnScheduledSparkApplication(t *testing.T) {
+	earlierHistory, err := formatHistory([]runRecord{
+		{
+			SubmissionID:    "submission-1",
+			State:           v1beta2.ApplicationStateCompleted,
+			DurationSeconds: 100,
+			Driver:          roleUsage{PeakMemoryMiB: 1000, PeakCores: 1.9},
+			Executor:        roleUsage{PeakMemoryMiB: 2000, PeakCores: 0.5},
+		},
+		{
+			SubmissionID:    "submission-2",
+			State:           v1beta2.ApplicationStateCompleted,
+			DurationSeconds: 100,
+			Driver:          roleUsage{PeakMemoryMiB: 900, PeakCores: 1.5},
+			Executor:        roleUsage{PeakMemoryMiB: 2100, PeakCores: 0.6},
+		},
+	})
+	require.NoError(t, err)
+	scheduledApp := &v1beta2.ScheduledSparkApplication{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        "test-scheduled-app",
+			Namespace:   "default",
+			Annotations: map[string]string{common.AnnotationRecommendationHistory: earlierHistory},
+		},
+	}
+
+	start := metav1.NewTime(time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC))
+	app := &v1beta2.SparkApplication{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "test-app",
+			Namespace: "default",
+			Labels:    map[string]string{common.LabelScheduledSparkAppName: "test-scheduled-app"},
+		},
+		Spec: v1beta2.SparkApplicationSpec{
+			Driver:   v1beta2.DriverSpec{SparkPodSpec: v1beta2.SparkPodSpec{Cores: ptr.To[int32](2), Memory: ptr.To("512m")}},
+			Executor: v1beta2.ExecutorSpec{SparkPodSpec: v1beta2.SparkPodSpec{Cores: ptr.To[int32](4), Memory: ptr.To("4g")}},
+		},
+		Status: v1beta2.SparkApplicationStatus{
+			SubmissionID:              "submission-3",
+			AppState:                  v1beta2.ApplicationState{State: v1beta2.ApplicationStateCompleted},
+			LastSubmissionAttemptTime: start,
+			TerminationTime:           metav1.NewTime(start.Add(110 * time.Second)),
+			ExecutorState: map[string]v1beta2.ExecutorState{
+				"test-app-exec-2": v1beta2.ExecutorStateCompleted,
+				"test-app-exec-1": v1beta2.ExecutorStateCompleted,
+			},
+		},
+	}
+
+	scheme := runtime.NewScheme()
+	require.NoError(t, clientgoscheme.AddToScheme(scheme))
+	require.NoError(t, v1beta2.AddToScheme(scheme))
+	c := fake.NewClientBuilder().WithScheme(scheme).WithObjects(app, scheduledApp).Build()
+	r := NewReconciler(c, record.NewFakeRecorder(10), Options{MemoryIncreaseFactor: 1.5, HistoryLimit: 10, Headroom: 1.2})
+	usage := &fakeUsageSource{
+		memoryMiB: map[string]int64{common.SparkDriverContainerName: 1200, common.Spark3DefaultExecutorContainerName: 2200},
+		cores:     map[string]float64{common.SparkDriverContainerName: 2, common.Spark3DefaultExecutorContainerName: 0.8},
+		pods:      map[string][]string{},
+	}
+	r.usage = usage
+
+	key := types.NamespacedName{Namespace: "default", Name: "test-app"}
+	_, err = r.Reconcile(context.Background(), ctrl.Request{NamespacedName: key})
+	require.NoError(t, err)
+
+	assert.Equal(t, []string{"test-app-driver"}, usage.pods[common.SparkDriverContainerName])
+	assert.Equal(t, []string{"test-app-exec-1", "test-app-exec-2"}, usage.pods[common.Spark3DefaultExecutorContainerName])
+
+	expected := map[string]string{
+		// The driver uses all its cores and more memory than requested.
+		common.AnnotationRecommendedDriverCores:  "3",
+		common.AnnotationRecommendedDriverMemory: "1056m",
+		// The executors use less than a core and about half of their memory.
+		common.AnnotationRecommendedExecutorCores:  "1",
+		common.AnnotationRecommendedExecutorMemory: "2256m",
+	}
+
+	require.NoError(t, c.Get(context.Background(), key, app))
+	assert.Equal(t, "submission-3", app.Annotations[common.AnnotationRecommendationSubmissionID])
+	assert.NotContains(t, app.Annotations, common.AnnotationRecommendationHistory)
+	for annotation, value := range expected {
+		assert.Equal(t, value, app.Annotations[annotation], annotation)
+	}
+
+	require.NoError(t, c.Get(context.Background(), types.NamespacedName{Namespace: "default", Name: "test-scheduled-app"}, scheduledApp))
+	for annotation, value := range expected {
+		assert.Equal(t, value, scheduledApp.Annotations[annotation], annotation)
+	}
+	history, err := parseHistory(scheduledApp.Annotations)
+	require.NoError(t, err)
+	require.Len(t, history, 3)
+	assert.Equal(t, runRecord{
+		SubmissionID:    "submission-3",
+		State:           v1beta2.ApplicationStateCompleted,
+		DurationSeconds: 110,
+		Driver:          roleUsage{PeakMemoryMiB: 1200, PeakCores: 2},
+		Executor:        roleUsage{PeakMemoryMiB: 2200, PeakCores: 0.8},
+	}, history[2])
+}
+
+func TestAppendHistory(t *testing.T) {
+	history := []runRecord{{SubmissionID: "1"}, {SubmissionID: "2"}, {SubmissionID: "3"}}
+
+	updated := appendHistory(history, runRecord{SubmissionID: "2", DurationSeconds: 10}, 3)
+	assert.Equal(t, []runRecord{{SubmissionID: "1"}, {SubmissionID: "3"}, {SubmissionID: "2", DurationSeconds: 10}}, updated)
+
+	updated = appendHistory(history, runRecord{SubmissionID: "4"}, 3)
+	assert.Equal(t, []runRecord{{SubmissionID: "2"}, {SubmissionID: "3"}, {SubmissionID: "4"}}, updated)
+}
+
+func measuredMemory(peaks ...int64) []roleUsage {
+	usages := make([]roleUsage, 0, len(peaks))
+	for _, peak := range peaks {
+		usages = append(usages, roleUsage{PeakMemoryMiB: peak})
+	}
+	return usages
+}
+
+func TestRecommendMemory(t *testing.T) {
+	testCases := []struct {
+		name     string
+		appType  v1beta2.SparkApplicationType
+		spec     v1beta2.SparkPodSpec
+		usages   []roleUsage
+		expected string
+	}{
+		{
+			name:     "raises memory after an OOM kill in the last run",
+			spec:     v1beta2.SparkPodSpec{Memory: ptr.To("512m")},
+			usages:   append(measuredMemory(400, 400, 400), roleUsage{OOMKilled: true}),
+			expected: "768m",
+		},
+		{
+			name:     "raises the default memory after an OOM kill",
+			usages:   []roleUsage{{OOMKilled: true}},
+			expected: "1536m",
+		},
+		{
+			name:   "does not size memory from too few measured runs",
+			spec:   v1beta2.SparkPodSpec{Memory: ptr.To("4g")},
+			usages: append(measuredMemory(1000, 1000), roleUsage{}),
+		},
+		{
+			name:     "sizes memory to the peak usage with the headroom and overhead",
+			spec:     v1beta2.SparkPodSpec{Memory: ptr.To("4g")},
+			usages:   measuredMemory(2000, 2200, 2100),
+			expected: "2256m",
+		},
+		{
+			name:     "uses the overhead factor of non-JVM applications",
+			appType:  v1beta2.SparkApplicationTypePython,
+			spec:     v1beta2.SparkPodSpec{Memory: ptr.To("4g")},
+			usages:   measuredMemory(3000, 3000, 3000),
+			expected: "2572m",
+		},
+		{
+			name:     "uses the memory overhead",
+			spec:     v1beta2.SparkPodSpec{Memory: ptr.To("4g"), MemoryOverhead: ptr.To("1g")},
+			usages:   measuredMemory(2000, 2000, 2000),
+			expected: "1376m",
+		},
+		{
+			name:   "does not lower memory after an OOM kill in an earlier run",
+			spec:   v1beta2.SparkPodSpec{Memory: ptr.To("4g")},
+			usages: append([]roleUsage{{OOMKilled: true}}, measuredMemory(2000, 2200, 2100)...),
+		},
+		{
+			name:   "does not recommend memory close to the current memory",
+			spec:   v1beta2.SparkPodSpec{Memory: ptr.To("2g")},
+			usages: measuredMemory(2100, 2100, 2100),
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			app := &v1beta2.SparkApplication{Spec: v1beta2.SparkApplicationSpec{Type: tc.appType}}
+			memory, ok := recommendMemory(app, &tc.spec, tc.usages, 1.5, 1.2)
+			assert.Equal(t, tc.expected != "", ok)
+			assert.Equal(t, tc.expected, memory)
+		})
+	}
+}
+
+func TestRecommendCores(t *testing.T) {
+	completed := func(durations ...int64) []runRecord {
+		history := make([]runRecord, 0, len(durations))
+		for _, duration := range durations {
+			history = append(history, runRecord{State: v1beta2.ApplicationStateCompleted, DurationSeconds: duration})
+		}
+		return history
+	}
+	measured := func(peaks ...float64) []roleUsage {
+		usages := make([]roleUsage, 0, len(peaks))
+		for _, peak := range peaks {
+			usages = append(usages, roleUsage{PeakCores: peak})
+		}
+		return usages
+	}
+
+	testCases := []struct {
+		name     string
+		spec     v1beta2.SparkPodSpec
+		history  []runRecord
+		usages   []roleUsage
+		expected string
+	}{
+		{
+			name:     "raises the cores of saturated pods",
+			spec:     v1beta2.SparkPodSpec{Cores: ptr.To[int32](2)},
+			history:  completed(100, 100, 100),
+			usages:   measured(1.9, 2, 1.5),
+			expected: "3",
+		},
+		{
+			name:     "lowers the cores of idle pods",
+			spec:     v1beta2.SparkPodSpec{Cores: ptr.To[int32](4)},
+			history:  completed(100, 100, 100),
+			usages:   measured(0.5, 0.8, 0.6),
+			expected: "1",
+		},
+		{
+			name:    "does not lower the cores if the last run was slower",
+			spec:    v1beta2.SparkPodSpec{Cores: ptr.To[int32](4)},
+			history: completed(100, 100, 200),
+			usages:  measured(0.5, 0.8, 0.6),
+		},
+		{
+			name:     "raises the cores even if the last run was slower",
+			spec:     v1beta2.SparkPodSpec{Cores: ptr.To[int32](2)},
+			history:  completed(100, 100, 200),
+			usages:   measured(1.9, 2, 1.5),
+			expected: "3",
+		},
+		{
+			name:    "does not size cores from too few measured runs",
+			spec:    v1beta2.SparkPodSpec{Cores: ptr.To[int32](4)},
+			history: completed(100, 100, 100),
+			usages:  append(measured(0.5, 0.5), roleUsage{}),
+		},
+		{
+			name:    "does not recommend the default cores",
+			history: completed(100, 100, 100),
+			usages:  measured(0.5, 0.8, 0.7),
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			cores, ok := recommendCores(&tc.spec, tc.history, tc.usages, 1.2)
+			assert.Equal(t, tc.expected != "", ok)
+			assert.Equal(t, tc.expected, cores)
+		})
+	}
+}
+
+func TestRecommendFromFailedAndOOMKilledRuns(t *testing.T) {
+	r := NewReconciler(nil, nil, Options{MemoryIncreaseFactor: 1.5, Headroom: 1.2})
+	app := &v1beta2.SparkApplication{
+		Spec: v1beta2.SparkApplicationSpec{
+			Driver:   v1beta2.DriverSpec{SparkPodSpec: v1beta2.SparkPodSpec{Memory: ptr.To("4g"), Cores: ptr.To[int32](4)}},
+			Executor: v1beta2.ExecutorSpec{SparkPodSpec: v1beta2.SparkPodSpec{Memory: ptr.To("4g"), Cores: ptr.To[int32](4)}},
+		},
+	}
+	measured := roleUsage{PeakMemoryMiB: 2000, PeakCores: 0.5}
+	completed := runRecord{State: v1beta2.ApplicationStateCompleted, DurationSeconds: 100, Driver: measured, Executor: measured}
+
+	t.Run("usage of failed runs is not measured", func(t *testing.T) {
+		failed := completed
+		failed.State = v1beta2.ApplicationStateFailed
+		failed.Driver = roleUsage{PeakMemoryMiB: 3900, PeakCores: 3.9}
+
+		// Two completed runs are too few to size the driver and executors from.
+		assert.Empty(t, r.recommend(app, []runRecord{completed, failed, completed}))
+
+		recommendations := r.recommend(app, []runRecord{completed, failed, completed, completed})
+		assert.Equal(t, map[string]string{
+			common.AnnotationRecommendedDriverMemory:   "2016m",
+			common.AnnotationRecommendedDriverCores:    "1",
+			common.AnnotationRecommendedExecutorMemory: "2016m",
+			common.AnnotationRecommendedExecutorCores:  "1",
+		}, recommendations)
+	})
+
+	t.Run("usage of OOM killed roles is not measured", func(t *testing.T) {
+		oomKilled := completed
+		oomKilled.Executor = roleUsage{OOMKilled: true, PeakMemoryMiB: 4500, PeakCores: 3.9}
+
+		recommendations := r.recommend(app, []runRecord{completed, oomKilled, completed})
+		assert.Equal(t, map[string]string{
+			common.AnnotationRecommendedDriverMemory: "2016m",
+			common.AnnotationRecommendedDriverCores:  "1",
+		}, recommendations)
+
+		// Memory is not lowered after an OOM kill, but cores are sized from the completed runs.
+		recommendations = r.recommend(app, []runRecord{completed, oomKilled, completed, completed})
+		assert.Equal(t, "1", recommendations[common.AnnotationRecommendedExecutorCores])
+		assert.NotContains(t, recommendations, common.AnnotationRecommendedExecutorMemory)
+	})
+}
+
+func TestIsSlowingDown(t *testing.T) {
+	record := func(state v1beta2.ApplicationStateType, duration int64) runRecord {
+		return runRecord{State: state, DurationSeconds: duration}
+	}
+	completed := v1beta2.ApplicationStateCompleted
+	failed := v1beta2.ApplicationStateFailed
+
+	assert.False(t, isSlowingDown(nil))
+	assert.False(t, isSlowingDown([]runRecord{record(completed, 100)}))
+	assert.False(t, isSlowingDown([]runRecord{record(completed, 100), record(completed, 120)}))
+	assert.True(t, isSlowingDown([]runRecord{record(completed, 100), record(completed, 121)}))
+	assert.True(t, isSlowingDown([]runRecord{record(completed, 100), record(completed, 300), record(completed, 50), record(completed, 181)}))
+	// Failed runs are not taken into account.
+	assert.False(t, isSlowingDown([]runRecord{record(completed, 100), record(failed, 10), record(completed, 110)}))
+	assert.False(t, isSlowingDown([]runRecord{record(completed, 100), record(failed, 200)}))
+}
@@ -0,0 +1,236 @@
+/*
+Copyright 2024 The Kubeflow authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package recommendation
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"sort"
+	"strconv"
+
+	"github.com/kubeflow/spark-operator/api/v1beta2"
+	"github.com/kubeflow/spark-operator/pkg/common"
+)
+
+const (
+	// minMeasuredRuns is the number of runs with measured usage needed to recommend resources from usage.
+	minMeasuredRuns = 3
+
+	// memoryTolerance is the relative difference between the recommended and the current memory below which
+	// no memory is recommended.
+	memoryTolerance = 0.1
+
+	// slowdownFactor is the factor by which the duration of the last run must exceed the median duration of
+	// the earlier completed runs for cores not to be recommended to be lowered.
+	slowdownFactor = 1.2
+
+	// defaultMemoryMiB is the memory of the driver and executors if not specified, as in Spark.
+	defaultMemoryMiB = 1024
+)
+
+// runRecord records a terminated run of a SparkApplication.
+type runRecord struct {
+	SubmissionID    string                       `json:"submissionID"`
+	State           v1beta2.ApplicationStateType `json:"state"`
+	DurationSeconds int64                        `json:"durationSeconds,omitempty"`
+	Driver          roleUsage                    `json:"driver"`
+	Executor        roleUsage                    `json:"executor"`
+}
+
+// roleUsage records the usage of the driver or executors of a run. The peak usage is zero if it was not measured.
+type roleUsage struct {
+	OOMKilled     bool    `json:"oomKilled,omitempty"`
+	PeakMemoryMiB int64   `json:"peakMemoryMiB,omitempty"`
+	PeakCores     float64 `json:"peakCores,omitempty"`
+}
+
+// parseHistory parses the run history recorded in the given annotations, oldest run first.
+func parseHistory(annotations map[string]string) ([]runRecord, error) {
+	value, ok := annotations[common.AnnotationRecommendationHistory]
+	if !ok {
+		return nil, nil
+	}
+	var history []runRecord
+	if err := json.Unmarshal([]byte(value), &history); err != nil {
+		return nil, fmt.Errorf("failed to parse run history: %v", err)
+	}
+	return history, nil
+}
+
+// appendHistory appends the record to the history, replacing an earlier record of the same run, and keeps at most
+// limit records.
+func appendHistory(history []runRecord, record runRecord, limit int) []runRecord {
+	updated := make([]runRecord, 0, len(history)+1)
+	for _, r := range history {
+		if r.SubmissionID != record.SubmissionID {
+			updated = append(updated, r)
+		}
+	}
+	updated = append(updated, record)
+	if limit > 0 && len(updated) > limit {
+		updated = updated[len(updated)-limit:]
+	}
+	return updated
+}
+
+// formatHistory formats the run history as the value of its annotation.
+func formatHistory(history []runRecord) (string, error) {
+	value, err := json.Marshal(history)
+	if err != nil {
+		return "", fmt.Errorf("failed to format run history: %v", err)
+	}
+	return string(value), nil
+}
+
+// measuredUsage returns the usage of a role in the run that recommendations are derived from. The usage of runs
+// that did not complete, or of roles that ran out of memory, was cut short by the failure and is not a measure of
+// what the role needs, so only their OOM kills are taken into account.
+func measuredUsage(record runRecord, usage roleUsage) roleUsage {
+	if record.State != v1beta2.ApplicationStateCompleted || usage.OOMKilled {
+		return roleUsage{OOMKilled: usage.OOMKilled}
+	}
+	return usage
+}
+
+// recommendMemory recommends the memory of a role from its usage in the history, latest run last. The memory is
+// increased by the increase factor if the role ran out of memory in the latest run. Otherwise, once enough runs
+// were measured, it is sized so that the container fits the peak usage with the headroom, but never lowered if
+// the role ran out of memory in any run of the history.
+func recommendMemory(app *v1beta2.SparkApplication, spec *v1beta2.SparkPodSpec, usages []roleUsage, increaseFactor float64, headroom float64) (string, bool) {
+	if len(usages) == 0 {
+		return "", false
+	}
+
+	currentMiB := int64(defaultMemoryMiB)
+	if spec.Memory != nil {
+		var err error
+		if currentMiB, err = parseMemoryMiB(*spec.Memory); err != nil {
+			return "", false
+		}
+	}
+
+	if usages[len(usages)-1].OOMKilled {
+		return fmt.Sprintf("%dm", int64(math.Ceil(float64(currentMiB)*increaseFactor))), true
+	}
+
+	var peakMiB int64
+	var measured int
+	var oomKilled bool
+	for _, usage := range usages {
+		oomKilled = oomKilled || usage.OOMKilled
+		if usage.PeakMemoryMiB > 0 {
+			measured++
+			peakMiB = max(peakMiB, usage.PeakMemoryMiB)
+		}
+	}
+	if measured < minMeasuredRuns {
+		return "", false
+	}
+
+	memoryMiB, ok := memoryForContainer(app, spec, int64(math.Ceil(float64(peakMiB)*headroom)))
+	if !ok || (oomKilled && memoryMiB < currentMiB) {
+		return "", false
+	}
+	if math.Abs(float64(memoryMiB-currentMiB)) <= float64(currentMiB)*memoryTolerance {
+		return "", false
+	}
+	return fmt.Sprintf("%dm", memoryMiB), true
+}
+
+// memoryForContainer returns the memory in MiB for which the container memory, i.e. the memory plus the memory
+// overhead as computed by Spark, is the given container memory.
+func memoryForContainer(app *v1beta2.SparkApplication, spec *v1beta2.SparkPodSpec, containerMiB int64) (int64, bool) {
+	var memoryMiB int64
+	if spec.MemoryOverhead != nil {
+		overheadMiB, err := parseMemoryMiB(*spec.MemoryOverhead)
+		if err != nil {
+			return 0, false
+		}
+		memoryMiB = containerMiB - overheadMiB
+	} else {
+		factor := common.DefaultJVMMemoryOverheadFactor
+		if app.Spec.Type == v1beta2.SparkApplicationTypePython || app.Spec.Type == v1beta2.SparkApplicationTypeR {
+			factor = common.DefaultNonJVMMemoryOverheadFactor
+		}
+		if app.Spec.MemoryOverheadFactor != nil {
+			value, err := strconv.ParseFloat(*app.Spec.MemoryOverheadFactor, 64)
+			if err != nil {
+				return 0, false
+			}
+			factor = value
+		}
+		// The overhead is the larger of the memory times the factor and the minimum overhead.
+		memoryMiB = min(int64(math.Ceil(float64(containerMiB)/(1+factor))), containerMiB-common.MinMemoryOverhead/(1<<20))
+	}
+	return memoryMiB, memoryMiB > 0
+}
+
+// recommendCores recommends the cores of a role from its usage in the history, latest run last, once enough runs
+// were measured, so that the peak usage fits the cores with the headroom. Cores are not recommended to be lowered
+// if the latest run was slower than the earlier ones.
+func recommendCores(spec *v1beta2.SparkPodSpec, history []runRecord, usages []roleUsage, headroom float64) (string, bool) {
+	var peakCores float64
+	var measured int
+	for _, usage := range usages {
+		if usage.PeakCores > 0 {
+			measured++
+			peakCores = math.Max(peakCores, usage.PeakCores)
+		}
+	}
+	if measured < minMeasuredRuns {
+		return "", false
+	}
+
+	currentCores := int32(1)
+	if spec.Cores != nil {
+		currentCores = *spec.Cores
+	}
+	cores := max(int32(math.Ceil(peakCores*headroom)), 1)
+	if cores == currentCores || (cores < currentCores && isSlowingDown(history)) {
+		return "", false
+	}
+	return strconv.Itoa(int(cores)), true
+}
+
+// isSlowingDown returns whether the latest run completed and took longer than the median duration of the earlier
+// completed runs by more than the slowdown factor.
+func isSlowingDown(history []runRecord) bool {
+	if len(history) == 0 {
+		return false
+	}
+	latest := history[len(history)-1]
+	if latest.State != v1beta2.ApplicationStateCompleted || latest.DurationSeconds == 0 {
+		return false
+	}
+
+	var durations []int64
+	for _, record := range history[:len(history)-1] {
+		if record.State == v1beta2.ApplicationStateCompleted && record.DurationSeconds > 0 {
+			durations = append(durations, record.DurationSeconds)
+		}
+	}
+	if len(durations) == 0 {
+		return false
+	}
+	sort.Slice(durations, func(i, j int) bool { return durations[i] < durations[j] })
+	median := float64(durations[len(durations)/2])
+	if len(durations)%2 == 0 {
+		median = float64(durations[len(durations)/2-1]+durations[len(durations)/2]) / 2
+	}
+	return float64(latest.DurationSeconds) > median*slowdownFactor
+}
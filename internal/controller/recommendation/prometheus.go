@@ -0,0 +1,152 @@
+/*
+Copyright 2024 The Kubeflow authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package recommendation
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	// prometheusQueryTimeout is the timeout of each query of the peak usage of a run.
+	prometheusQueryTimeout = 30 * time.Second
+
+	// executorPodNameSuffix is the suffix Spark appends to the pod name prefix of the run to name its executors.
+	executorPodNameSuffix = `-exec-[0-9]+`
+
+	// peakMemoryQuery queries the peak working set in bytes of the containers of the given pods over a window.
+	peakMemoryQuery = `max(max_over_time(container_memory_working_set_bytes{namespace=%q,pod=~%q,container=%q}[%ds]))`
+
+	// peakCoresQuery queries the peak CPU usage in cores of the containers of the given pods over a window.
+	peakCoresQuery = `max(max_over_time(rate(container_cpu_usage_seconds_total{namespace=%q,pod=~%q,container=%q}[1m])[%ds:15s]))`
+)
+
+var (
+	// prometheusClient is the HTTP client querying Prometheus.
+	prometheusClient = &http.Client{Timeout: prometheusQueryTimeout}
+
+	// executorPodNameRegex matches executor pod names, capturing their prefix.
+	executorPodNameRegex = regexp.MustCompile(`^(.+)` + executorPodNameSuffix + `$`)
+)
+
+// usageSource measures the peak usage of containers.
+type usageSource interface {
+	// peakUsage returns the peak memory in MiB and the peak CPU usage in cores of the containers with the given
+	// name of the given pods between start and end, or zero if no usage was measured.
+	peakUsage(ctx context.Context, namespace string, pods []string, container string, start, end time.Time) (int64, float64, error)
+}
+
+// prometheusUsageSource measures the peak usage of containers from the cAdvisor metrics scraped by Prometheus.
+type prometheusUsageSource struct {
+	url string
+}
+
+// prometheusResponse is the response of the instant query API of Prometheus.
+type prometheusResponse struct {
+	Status string `json:"status"`
+	Error  string `json:"error"`
+	Data   struct {
+		Result []struct {
+			Value []interface{} `json:"value"`
+		} `json:"result"`
+	} `json:"data"`
+}
+
+func (s *prometheusUsageSource) peakUsage(ctx context.Context, namespace string, pods []string, container string, start, end time.Time) (int64, float64, error) {
+	podPattern := podNamePattern(pods)
+	window := int64(math.Ceil(end.Sub(start).Seconds()))
+
+	memoryBytes, err := s.query(ctx, fmt.Sprintf(peakMemoryQuery, namespace, podPattern, container, window), end)
+	if err != nil {
+		return 0, 0, err
+	}
+	cores, err := s.query(ctx, fmt.Sprintf(peakCoresQuery, namespace, podPattern, container, window), end)
+	if err != nil {
+		return 0, 0, err
+	}
+	return int64(math.Ceil(memoryBytes / (1 << 20))), cores, nil
+}
+
+// podNamePattern returns the regex matching the given pod names. Executor pods are matched by the pod name prefix of
+// their run rather than one by one, so that the query does not grow with the number of executors.
+func podNamePattern(pods []string) string {
+	var alternatives []string
+	seen := make(map[string]bool)
+	for _, pod := range pods {
+		alternative := regexp.QuoteMeta(pod)
+		if match := executorPodNameRegex.FindStringSubmatch(pod); match != nil {
+			alternative = regexp.QuoteMeta(match[1]) + executorPodNameSuffix
+		}
+		if !seen[alternative] {
+			seen[alternative] = true
+			alternatives = append(alternatives, alternative)
+		}
+	}
+	return strings.Join(alternatives, "|")
+}
+
+// query runs an instant query returning a single sample and returns its value, or zero if there is none. The query is
+// posted as a form rather than in the URL, which proxies in front of Prometheus may limit to a few KB.
+func (s *prometheusUsageSource) query(ctx context.Context, query string, at time.Time) (float64, error) {
+	params := url.Values{}
+	params.Set("query", query)
+	params.Set("time", strconv.FormatInt(at.Unix(), 10))
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, strings.TrimSuffix(s.url, "/")+"/api/v1/query", strings.NewReader(params.Encode()))
+	if err != nil {
+		return 0, fmt.Errorf("failed to create Prometheus query: %v", err)
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+
+	resp, err := prometheusClient.Do(req)
+	if err != nil {
+		return 0, fmt.Errorf("failed to query Prometheus: %v", err)
+	}
+	defer resp.Body.Close()
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return 0, fmt.Errorf("failed to read Prometheus response: %v", err)
+	}
+
+	var result prometheusResponse
+	if err := json.Unmarshal(body, &result); err != nil {
+		return 0, fmt.Errorf("failed to parse Prometheus response with status %d: %v", resp.StatusCode, err)
+	}
+	if result.Status != "success" {
+		return 0, fmt.Errorf("failed to query Prometheus: %s", result.Error)
+	}
+	if len(result.Data.Result) == 0 || len(result.Data.Result[0].Value) != 2 {
+		return 0, nil
+	}
+	value, ok := result.Data.Result[0].Value[1].(string)
+	if !ok {
+		return 0, fmt.Errorf("unexpected Prometheus sample value %v", result.Data.Result[0].Value[1])
+	}
+	sample, err := strconv.ParseFloat(value, 64)
+	if err != nil || math.IsNaN(sample) {
+		return 0, fmt.Errorf("unexpected Prometheus sample value %q", value)
+	}
+	return sample, nil
+}
@@ -0,0 +1,129 @@
+/*
+Copyright 2024 The Kubeflow authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package recommendation
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPrometheusUsageSource(t *testing.T) {
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	end := start.Add(90 * time.Second)
+
+	var queries []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/api/v1/query", r.URL.Path)
+		assert.Equal(t, http.MethodPost, r.Method)
+		assert.Equal(t, fmt.Sprint(end.Unix()), r.PostFormValue("time"))
+		query := r.PostFormValue("query")
+		queries = append(queries, query)
+
+		value := "1.5"
+		if strings.Contains(query, "container_memory_working_set_bytes") {
+			value = "2147483648"
+		}
+		fmt.Fprintf(w, `{"status":"success","data":{"resultType":"vector","result":[{"metric":{},"value":[%d,"%s"]}]}}`, end.Unix(), value)
+	}))
+	defer server.Close()
+
+	source := &prometheusUsageSource{url: server.URL + "/"}
+	memoryMiB, cores, err := source.peakUsage(context.Background(), "default", []string{"test.app-exec-1", "test.app-exec-2"}, "spark-kubernetes-executor", start, end)
+	require.NoError(t, err)
+	assert.Equal(t, int64(2048), memoryMiB)
+	assert.Equal(t, 1.5, cores)
+	assert.Equal(t, []string{
+		`max(max_over_time(container_memory_working_set_bytes{namespace="default",pod=~"test\\.app-exec-[0-9]+",container="spark-kubernetes-executor"}[90s]))`,
+		`max(max_over_time(rate(container_cpu_usage_seconds_total{namespace="default",pod=~"test\\.app-exec-[0-9]+",container="spark-kubernetes-executor"}[1m])[90s:15s]))`,
+	}, queries)
+}
+
+func TestPodNamePattern(t *testing.T) {
+	testCases := []struct {
+		name     string
+		pods     []string
+		expected string
+	}{
+		{
+			name:     "driver pod",
+			pods:     []string{"test.app-driver"},
+			expected: `test\.app-driver`,
+		},
+		{
+			name:     "executor pods of a run",
+			pods:     []string{"test-app-1a2b3c-exec-1", "test-app-1a2b3c-exec-2", "test-app-1a2b3c-exec-10"},
+			expected: `test-app-1a2b3c-exec-[0-9]+`,
+		},
+		{
+			name:     "executor pods with different prefixes",
+			pods:     []string{"test-app-1a2b3c-exec-1", "test-app-4d5e6f-exec-1"},
+			expected: `test-app-1a2b3c-exec-[0-9]+|test-app-4d5e6f-exec-[0-9]+`,
+		},
+		{
+			name:     "pod not named like an executor",
+			pods:     []string{"test-app-exec-1", "custom-executor"},
+			expected: `test-app-exec-[0-9]+|custom-executor`,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, tc.expected, podNamePattern(tc.pods))
+		})
+	}
+}
+
+func TestPodNamePatternDoesNotGrowWithExecutors(t *testing.T) {
+	pods := make([]string, 0, 5000)
+	for id := 1; id <= 5000; id++ {
+		pods = append(pods, fmt.Sprintf("test-app-1a2b3c-exec-%d", id))
+	}
+	assert.Equal(t, `test-app-1a2b3c-exec-[0-9]+`, podNamePattern(pods))
+}
+
+func TestPrometheusUsageSourceWithoutSamples(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		fmt.Fprint(w, `{"status":"success","data":{"resultType":"vector","result":[]}}`)
+	}))
+	defer server.Close()
+
+	source := &prometheusUsageSource{url: server.URL}
+	memoryMiB, cores, err := source.peakUsage(context.Background(), "default", []string{"test-app-driver"}, "spark-kubernetes-driver", time.Now().Add(-time.Minute), time.Now())
+	require.NoError(t, err)
+	assert.Zero(t, memoryMiB)
+	assert.Zero(t, cores)
+}
+
+func TestPrometheusUsageSourceQueryError(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusBadRequest)
+		fmt.Fprint(w, `{"status":"error","errorType":"bad_data","error":"parse error"}`)
+	}))
+	defer server.Close()
+
+	source := &prometheusUsageSource{url: server.URL}
+	_, _, err := source.peakUsage(context.Background(), "default", []string{"test-app-driver"}, "spark-kubernetes-driver", time.Now().Add(-time.Minute), time.Now())
+	assert.ErrorContains(t, err, "parse error")
+}
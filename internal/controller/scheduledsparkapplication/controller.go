@@ -23,9 +23,10 @@ import (
 	"sort"
 	"time"
 
-	"github.com/robfig/cron/v3"
+	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/apimachinery/pkg/labels"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/types"
@@ -40,6 +41,7 @@ import (
 
 	"github.com/kubeflow/spark-operator/api/v1beta2"
 	"github.com/kubeflow/spark-operator/pkg/common"
+	"github.com/kubeflow/spark-operator/pkg/naming"
 	"github.com/kubeflow/spark-operator/pkg/util"
 )
 
@@ -81,6 +83,7 @@ func NewReconciler(
 // +kubebuilder:rbac:groups=sparkoperator.k8s.io,resources=scheduledsparkapplications,verbs=get;list;watch;create;update;patch;delete
 // +kubebuilder:rbac:groups=sparkoperator.k8s.io,resources=scheduledsparkapplications/status,verbs=get;update;patch
 // +kubebuilder:rbac:groups=sparkoperator.k8s.io,resources=scheduledsparkapplications/finalizers,verbs=update
+// +kubebuilder:rbac:groups=,resources=configmaps,verbs=get;list;watch
 
 // Reconcile is part of the main kubernetes reconciliation loop which aims to
 // move the current state of the cluster closer to the desired state.
@@ -107,9 +110,15 @@ func (r *Reconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Resu
 		return ctrl.Result{}, nil
 	}
 
-	schedule, parseErr := cron.ParseStandard(scheduledApp.Spec.Schedule)
+	calendar, err := r.getExclusionCalendar(ctx, scheduledApp)
+	if err != nil {
+		logger.Error(err, "Failed to get exclusion calendar of ScheduledSparkApplication", "name", scheduledApp.Name, "namespace", scheduledApp.Namespace)
+		return ctrl.Result{Requeue: true}, err
+	}
+
+	schedule, parseErr := util.ParseSchedule(&scheduledApp.Spec, calendar)
 	if parseErr != nil {
-		logger.Error(err, "Failed to parse schedule of ScheduledSparkApplication", "name", scheduledApp.Name, "namespace", scheduledApp.Namespace, "schedule", scheduledApp.Spec.Schedule)
+		logger.Error(parseErr, "Failed to parse schedule of ScheduledSparkApplication", "name", scheduledApp.Name, "namespace", scheduledApp.Namespace, "schedule", scheduledApp.Spec.Schedule)
 		scheduledApp.Status.ScheduleState = v1beta2.ScheduleStateFailedValidation
 		scheduledApp.Status.Reason = parseErr.Error()
 		if updateErr := r.updateScheduledSparkApplicationStatus(ctx, scheduledApp); updateErr != nil {
@@ -146,6 +155,16 @@ func (r *Reconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Resu
 			return ctrl.Result{RequeueAfter: nextRunTime.Time.Sub(now)}, nil
 		}
 
+		// The exclusion calendar may have changed since the next run was computed.
+		if util.IsExcluded(schedule, nextRunTime.Time) {
+			logger.Info("Skipping run of ScheduledSparkApplication in exclusion window", "name", scheduledApp.Name, "namespace", scheduledApp.Namespace, "runTime", nextRunTime.Time)
+			scheduledApp.Status.NextRun = metav1.NewTime(schedule.Next(now))
+			if err := r.updateScheduledSparkApplicationStatus(ctx, scheduledApp); err != nil {
+				return ctrl.Result{Requeue: true}, err
+			}
+			return ctrl.Result{RequeueAfter: schedule.Next(now).Sub(now)}, nil
+		}
+
 		ok, err := r.shouldStartNextRun(scheduledApp)
 		if err != nil {
 			return ctrl.Result{Requeue: true}, err
@@ -155,7 +174,7 @@ func (r *Reconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Resu
 		}
 
 		logger.Info("Next run of ScheduledSparkApplication is due", "name", scheduledApp.Name, "namespace", scheduledApp.Namespace)
-		app, err := r.startNextRun(scheduledApp, now)
+		app, err := r.startNextRun(scheduledApp, nextRunTime.Time)
 		if err != nil {
 			logger.Error(err, "Failed to start next run for ScheduledSparkApplication", "name", scheduledApp.Name, "namespace", scheduledApp.Namespace)
 			return ctrl.Result{RequeueAfter: schedule.Next(now).Sub(now)}, err
@@ -200,19 +219,34 @@ func (r *Reconciler) getScheduledSparkApplication(ctx context.Context, key types
 	return app, nil
 }
 
+// getExclusionCalendar returns the data of the exclusion calendar ConfigMap of the ScheduledSparkApplication, or nil
+// if it has none.
+func (r *Reconciler) getExclusionCalendar(ctx context.Context, scheduledApp *v1beta2.ScheduledSparkApplication) (map[string]string, error) {
+	if scheduledApp.Spec.ExclusionCalendar == nil {
+		return nil, nil
+	}
+
+	configMap := &corev1.ConfigMap{}
+	key := types.NamespacedName{Namespace: scheduledApp.Namespace, Name: *scheduledApp.Spec.ExclusionCalendar}
+	if err := r.client.Get(ctx, key, configMap); err != nil {
+		return nil, fmt.Errorf("failed to get exclusion calendar %s: %v", key, err)
+	}
+	return configMap.Data, nil
+}
+
 func (r *Reconciler) createSparkApplication(
 	scheduledApp *v1beta2.ScheduledSparkApplication,
 	t time.Time,
 ) (*v1beta2.SparkApplication, error) {
-	labels := map[string]string{
-		common.LabelScheduledSparkAppName: scheduledApp.Name,
-	}
+	labels := map[string]string{}
 	for key, value := range scheduledApp.Labels {
 		labels[key] = value
 	}
+	labels[common.LabelScheduledSparkAppName] = scheduledApp.Name
+	labels[common.LabelScheduledRunTime] = t.UTC().Format(common.ScheduledRunTimeFormat)
 	app := &v1beta2.SparkApplication{
 		ObjectMeta: metav1.ObjectMeta{
-			Name:      fmt.Sprintf("%s-%d", scheduledApp.Name, t.UnixNano()),
+			Name:      naming.ScheduledRunName(scheduledApp, t),
 			Namespace: scheduledApp.Namespace,
 			Labels:    labels,
 			OwnerReferences: []metav1.OwnerReference{{
@@ -225,12 +259,53 @@ func (r *Reconciler) createSparkApplication(
 		},
 		Spec: scheduledApp.Spec.Template,
 	}
+	if app.Spec.TemplateRef != nil {
+		return r.createSparkApplicationFromRawTemplate(scheduledApp, app)
+	}
 	if err := r.client.Create(context.TODO(), app); err != nil {
 		return nil, err
 	}
 	return app, nil
 }
 
+// createSparkApplicationFromRawTemplate creates the given SparkApplication with the spec of the ScheduledSparkApplication
+// template as stored in the API server. The webhook only overrides the referenced SparkApplicationTemplate with the
+// fields set in the submitted spec, while the typed spec sets every field without omitempty, e.g. the type and the
+// Spark version, which would override those of the SparkApplicationTemplate with empty values.
+func (r *Reconciler) createSparkApplicationFromRawTemplate(
+	scheduledApp *v1beta2.ScheduledSparkApplication,
+	app *v1beta2.SparkApplication,
+) (*v1beta2.SparkApplication, error) {
+	rawScheduledApp := &unstructured.Unstructured{}
+	rawScheduledApp.SetGroupVersionKind(v1beta2.SchemeGroupVersion.WithKind(reflect.TypeOf(v1beta2.ScheduledSparkApplication{}).Name()))
+	key := types.NamespacedName{Namespace: scheduledApp.Namespace, Name: scheduledApp.Name}
+	if err := r.client.Get(context.TODO(), key, rawScheduledApp); err != nil {
+		return nil, fmt.Errorf("failed to get ScheduledSparkApplication %s: %v", key, err)
+	}
+	template, found, err := unstructured.NestedMap(rawScheduledApp.Object, "spec", "template")
+	if err != nil || !found {
+		return nil, fmt.Errorf("failed to get template of ScheduledSparkApplication %s: %v", key, err)
+	}
+
+	object, err := runtime.DefaultUnstructuredConverter.ToUnstructured(app)
+	if err != nil {
+		return nil, err
+	}
+	rawApp := &unstructured.Unstructured{Object: object}
+	rawApp.SetGroupVersionKind(v1beta2.SchemeGroupVersion.WithKind(reflect.TypeOf(v1beta2.SparkApplication{}).Name()))
+	rawApp.Object["spec"] = template
+	delete(rawApp.Object, "status")
+	if err := r.client.Create(context.TODO(), rawApp); err != nil {
+		return nil, err
+	}
+
+	created := &v1beta2.SparkApplication{}
+	if err := runtime.DefaultUnstructuredConverter.FromUnstructured(rawApp.Object, created); err != nil {
+		return nil, err
+	}
+	return created, nil
+}
+
 // shouldStartNextRun checks if the next run should be started.
 func (r *Reconciler) shouldStartNextRun(scheduledApp *v1beta2.ScheduledSparkApplication) (bool, error) {
 	apps, err := r.listSparkApplications(scheduledApp)
@@ -258,12 +333,25 @@ func (r *Reconciler) shouldStartNextRun(scheduledApp *v1beta2.ScheduledSparkAppl
 	return false, nil
 }
 
-func (r *Reconciler) startNextRun(scheduledApp *v1beta2.ScheduledSparkApplication, now time.Time) (*v1beta2.SparkApplication, error) {
-	app, err := r.createSparkApplication(scheduledApp, now)
-	if err != nil {
+func (r *Reconciler) startNextRun(scheduledApp *v1beta2.ScheduledSparkApplication, runTime time.Time) (*v1beta2.SparkApplication, error) {
+	app, err := r.createSparkApplication(scheduledApp, runTime)
+	if err == nil {
+		return app, nil
+	}
+	if !errors.IsAlreadyExists(err) {
 		return nil, err
 	}
-	return app, nil
+
+	// The run may already have been created by a previous reconciliation that failed to update the status.
+	existing := &v1beta2.SparkApplication{}
+	key := types.NamespacedName{Namespace: scheduledApp.Namespace, Name: naming.ScheduledRunName(scheduledApp, runTime)}
+	if err := r.client.Get(context.TODO(), key, existing); err != nil {
+		return nil, err
+	}
+	if existing.Labels[common.LabelScheduledSparkAppName] != scheduledApp.Name {
+		return nil, fmt.Errorf("SparkApplication %s already exists and does not belong to ScheduledSparkApplication %s", key.Name, scheduledApp.Name)
+	}
+	return existing, nil
 }
 
 func (r *Reconciler) hasLastRunFinished(app *v1beta2.SparkApplication) bool {
@@ -358,9 +446,15 @@ func (r *Reconciler) listSparkApplications(app *v1beta2.ScheduledSparkApplicatio
 	return apps, nil
 }
 
-// sortSparkApplicationsInPlace sorts the given slice of SparkApplication in place by the decreasing order of creation timestamp.
+// sortSparkApplicationsInPlace sorts the given slice of SparkApplication in place by the decreasing order of scheduled
+// run time, falling back to creation timestamp for runs created before the run time label was introduced.
 func sortSparkApplicationsInPlace(apps []*v1beta2.SparkApplication) {
-	sort.Slice(apps, func(i, j int) bool {
+	sort.SliceStable(apps, func(i, j int) bool {
+		ti, iok := apps[i].Labels[common.LabelScheduledRunTime]
+		tj, jok := apps[j].Labels[common.LabelScheduledRunTime]
+		if iok && jok && ti != tj {
+			return ti > tj
+		}
 		return apps[i].CreationTimestamp.After(apps[j].CreationTimestamp.Time)
 	})
 }
@@ -18,17 +18,21 @@ package scheduledsparkapplication
 
 import (
 	"context"
+	"time"
 
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
 
 	"k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/utils/clock"
+	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/reconcile"
 
 	"github.com/kubeflow/spark-operator/api/v1beta2"
+	"github.com/kubeflow/spark-operator/pkg/common"
 	"github.com/kubeflow/spark-operator/pkg/util"
 )
 
@@ -90,4 +94,83 @@ var _ = Describe("ScheduledSparkApplication Controller", func() {
 			// Example: If you expect a certain status condition after reconciliation, verify it here.
 		})
 	})
+
+	Context("When starting a run of a ScheduledSparkApplication referencing a SparkApplicationTemplate", func() {
+		ctx := context.Background()
+		key := types.NamespacedName{
+			Name:      "test-template-ref",
+			Namespace: "default",
+		}
+
+		// newRawScheduledApp returns a ScheduledSparkApplication with the given template, as created by kubectl.
+		newRawScheduledApp := func(template map[string]any) *unstructured.Unstructured {
+			scheduledApp := &unstructured.Unstructured{Object: map[string]any{
+				"spec": map[string]any{
+					"schedule":          "@every 1m",
+					"concurrencyPolicy": string(v1beta2.ConcurrencyAllow),
+					"template":          template,
+				},
+			}}
+			scheduledApp.SetGroupVersionKind(v1beta2.SchemeGroupVersion.WithKind("ScheduledSparkApplication"))
+			scheduledApp.SetName(key.Name)
+			scheduledApp.SetNamespace(key.Namespace)
+			return scheduledApp
+		}
+
+		AfterEach(func() {
+			scheduledApp := &v1beta2.ScheduledSparkApplication{}
+			if err := k8sClient.Get(ctx, key, scheduledApp); err == nil {
+				By("Deleting the created ScheduledSparkApplication")
+				Expect(k8sClient.Delete(ctx, scheduledApp)).To(Succeed())
+			}
+			Expect(k8sClient.DeleteAllOf(ctx, &v1beta2.SparkApplication{}, client.InNamespace(key.Namespace),
+				client.MatchingLabels{common.LabelScheduledSparkAppName: key.Name})).To(Succeed())
+		})
+
+		It("Should only set the fields of the template in the spec of the run", func() {
+			By("Creating a ScheduledSparkApplication leaving the required fields to the SparkApplicationTemplate")
+			Expect(k8sClient.Create(ctx, newRawScheduledApp(map[string]any{
+				"templateRef": map[string]any{"name": "spark-pi"},
+				"arguments":   []any{"100"},
+			}))).To(Succeed())
+
+			By("Making the next run due")
+			scheduledApp := &v1beta2.ScheduledSparkApplication{}
+			Expect(k8sClient.Get(ctx, key, scheduledApp)).To(Succeed())
+			scheduledApp.Status.ScheduleState = v1beta2.ScheduleStateScheduled
+			scheduledApp.Status.NextRun = metav1.NewTime(time.Now().Add(-time.Minute))
+			Expect(k8sClient.Status().Update(ctx, scheduledApp)).To(Succeed())
+
+			By("Reconciling the ScheduledSparkApplication")
+			reconciler := NewReconciler(k8sClient.Scheme(), k8sClient, nil, clock.RealClock{}, Options{Namespaces: []string{key.Namespace}})
+			_, err := reconciler.Reconcile(ctx, reconcile.Request{NamespacedName: key})
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(k8sClient.Get(ctx, key, scheduledApp)).To(Succeed())
+			Expect(scheduledApp.Status.LastRunName).NotTo(BeEmpty())
+
+			// Fields left unset must not be sent to the webhook, which would override the template with them.
+			run := &unstructured.Unstructured{}
+			run.SetGroupVersionKind(v1beta2.SchemeGroupVersion.WithKind("SparkApplication"))
+			Expect(k8sClient.Get(ctx, types.NamespacedName{Namespace: key.Namespace, Name: scheduledApp.Status.LastRunName}, run)).To(Succeed())
+			spec, _, err := unstructured.NestedMap(run.Object, "spec")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(spec).To(HaveKeyWithValue("templateRef", map[string]any{"name": "spark-pi"}))
+			Expect(spec).To(HaveKeyWithValue("arguments", []any{"100"}))
+			Expect(spec).NotTo(HaveKey("type"))
+			Expect(spec).NotTo(HaveKey("sparkVersion"))
+			Expect(spec).NotTo(HaveKey("driver"))
+			Expect(spec).NotTo(HaveKey("executor"))
+			Expect(run.GetLabels()).To(HaveKeyWithValue(common.LabelScheduledSparkAppName, key.Name))
+			Expect(run.GetOwnerReferences()).To(HaveLen(1))
+		})
+
+		It("Should require the type, Spark version, driver and executor without a template reference", func() {
+			err := k8sClient.Create(ctx, newRawScheduledApp(map[string]any{
+				"arguments": []any{"100"},
+			}))
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("type, sparkVersion, driver and executor are required unless templateRef is set"))
+		})
+	})
 })
@@ -0,0 +1,104 @@
+/*
+Copyright 2024 The Kubeflow authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package sparkapplication
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/base64"
+	"fmt"
+	"path/filepath"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/kubeflow/spark-operator/api/v1beta2"
+	"github.com/kubeflow/spark-operator/pkg/common"
+	"github.com/kubeflow/spark-operator/pkg/util"
+)
+
+// sparkAuthSecretLength is the length in bytes of the generated authentication secret.
+const sparkAuthSecretLength = 32
+
+// configSparkAuthSecret makes sure a Secret holding a random authentication secret exists for the given
+// SparkApplication, and configures the driver and executors to mount it and to authenticate and encrypt
+// their internal connections with it. The Secret is owned by the SparkApplication and reused across runs, unless
+// secret rotation is configured, in which case a new secret is generated for every run.
+func (r *Reconciler) configSparkAuthSecret(ctx context.Context, app *v1beta2.SparkApplication) error {
+	name := util.GetSparkAuthSecretName(app)
+	secret := &corev1.Secret{}
+	if err := r.client.Get(ctx, client.ObjectKey{Namespace: app.Namespace, Name: name}, secret); err != nil {
+		if !errors.IsNotFound(err) {
+			return fmt.Errorf("failed to get secret %s: %v", name, err)
+		}
+
+		value, err := generateSparkAuthSecret()
+		if err != nil {
+			return err
+		}
+		secret = &corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:            name,
+				Namespace:       app.Namespace,
+				Labels:          map[string]string{common.LabelSparkAppName: app.Name},
+				OwnerReferences: []metav1.OwnerReference{util.GetOwnerReference(app)},
+			},
+			Type: corev1.SecretTypeOpaque,
+			Data: map[string][]byte{
+				common.SparkAuthSecretKey: []byte(value),
+			},
+		}
+		if err := r.client.Create(ctx, secret); err != nil && !errors.IsAlreadyExists(err) {
+			return fmt.Errorf("failed to create secret %s: %v", name, err)
+		}
+		appLogger(app).Info("Created authentication secret for SparkApplication", "secret", name)
+	} else if r.options.Executor.EnableSecretRotation && app.Spec.SecretRotation != nil {
+		// The driver cannot switch to a new secret while running, so rotate the secret for every new run instead.
+		value, err := generateSparkAuthSecret()
+		if err != nil {
+			return err
+		}
+		secret.Data = map[string][]byte{common.SparkAuthSecretKey: []byte(value)}
+		if err := r.client.Update(ctx, secret); err != nil {
+			return fmt.Errorf("failed to update secret %s: %v", name, err)
+		}
+		appLogger(app).Info("Rotated authentication secret for SparkApplication", "secret", name)
+	}
+
+	if app.Spec.SparkConf == nil {
+		app.Spec.SparkConf = make(map[string]string)
+	}
+	app.Spec.SparkConf[common.SparkAuthenticate] = "true"
+	app.Spec.SparkConf[common.SparkAuthenticateSecretFile] = filepath.Join(common.SparkAuthSecretMountPath, common.SparkAuthSecretKey)
+	app.Spec.SparkConf[fmt.Sprintf(common.SparkKubernetesDriverSecretsTemplate, name)] = common.SparkAuthSecretMountPath
+	app.Spec.SparkConf[fmt.Sprintf(common.SparkKubernetesExecutorSecretsTemplate, name)] = common.SparkAuthSecretMountPath
+	if _, ok := app.Spec.SparkConf[common.SparkNetworkCryptoEnabled]; !ok {
+		app.Spec.SparkConf[common.SparkNetworkCryptoEnabled] = "true"
+	}
+	return nil
+}
+
+// generateSparkAuthSecret generates a random base64-encoded authentication secret.
+func generateSparkAuthSecret() (string, error) {
+	buf := make([]byte, sparkAuthSecretLength)
+	if _, err := rand.Read(buf); err != nil {
+		return "", fmt.Errorf("failed to generate authentication secret: %v", err)
+	}
+	return base64.StdEncoding.EncodeToString(buf), nil
+}
@@ -0,0 +1,40 @@
+/*
+Copyright 2024 The Kubeflow authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package sparkapplication
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/kubeflow/spark-operator/api/v1beta2"
+	"github.com/kubeflow/spark-operator/pkg/naming"
+	"github.com/kubeflow/spark-operator/pkg/util"
+)
+
+// createOrUpdateConfPropertiesConfigMap writes the SparkConf and HadoopConf properties of the app into the ConfigMap
+// that is mounted into the driver and executor pods, creating the ConfigMap if it does not exist yet.
+func (r *Reconciler) createOrUpdateConfPropertiesConfigMap(ctx context.Context, app *v1beta2.SparkApplication) error {
+	if !util.ShouldMountConfProperties(app) {
+		return nil
+	}
+
+	configMapName := naming.ConfPropertiesConfigMapName(app)
+	if err := r.createOrUpdateAppConfigMap(ctx, app, configMapName, util.GetConfPropertiesConfigMapData(app)); err != nil {
+		return fmt.Errorf("failed to create or update ConfigMap %s for conf properties: %v", configMapName, err)
+	}
+	return nil
+}
@@ -20,34 +20,38 @@ import (
 	"context"
 	"fmt"
 	"os"
-	"strconv"
+	"sync"
 	"time"
 
-	"github.com/golang/glog"
-	"github.com/google/uuid"
+	"github.com/go-logr/logr"
+	batchv1 "k8s.io/api/batch/v1"
 	corev1 "k8s.io/api/core/v1"
 	extensionsv1beta1 "k8s.io/api/extensions/v1beta1"
 	networkingv1 "k8s.io/api/networking/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
 	"k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/types"
+	corev1client "k8s.io/client-go/kubernetes/typed/core/v1"
 	"k8s.io/client-go/tools/record"
 	"k8s.io/client-go/util/retry"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/builder"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/controller"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
 	"sigs.k8s.io/controller-runtime/pkg/log"
 	"sigs.k8s.io/controller-runtime/pkg/reconcile"
 
 	"github.com/kubeflow/spark-operator/api/v1beta2"
+	"github.com/kubeflow/spark-operator/internal/chaos"
 	"github.com/kubeflow/spark-operator/internal/metrics"
 	"github.com/kubeflow/spark-operator/internal/scheduler"
-	"github.com/kubeflow/spark-operator/internal/scheduler/kubescheduler"
-	"github.com/kubeflow/spark-operator/internal/scheduler/volcano"
-	"github.com/kubeflow/spark-operator/internal/scheduler/yunikorn"
+	"github.com/kubeflow/spark-operator/internal/webhook"
+	"github.com/kubeflow/spark-operator/pkg/archive"
 	"github.com/kubeflow/spark-operator/pkg/common"
+	"github.com/kubeflow/spark-operator/pkg/statestore"
 	"github.com/kubeflow/spark-operator/pkg/util"
 )
 
@@ -55,6 +59,12 @@ var (
 	logger = log.Log.WithName("")
 )
 
+// appLogger returns the logger for the given SparkApplication, whose log lines carry the name and the namespace of the
+// application and the correlation ID of its current run, which the webhook also logs for the pods of the run.
+func appLogger(app *v1beta2.SparkApplication) logr.Logger {
+	return logger.WithValues("name", app.Name, "namespace", app.Namespace, "correlationID", util.GetCorrelationID(app))
+}
+
 // Options defines the options of the controller.
 type Options struct {
 	Namespaces            []string
@@ -65,12 +75,65 @@ type Options struct {
 
 	DriverPodCreationGracePeriod time.Duration
 
-	KubeSchedulerNames []string
-
 	SparkApplicationMetrics *metrics.SparkApplicationMetrics
 	SparkExecutorMetrics    *metrics.SparkExecutorMetrics
+	SparkSubmissionMetrics  *metrics.SparkSubmissionMetrics
 
 	MaxTrackedExecutorPerApp int
+
+	PreSubmissionHooks  []Hook
+	PostCompletionHooks []Hook
+
+	// ApplicationDefaults and SparkConfPolicy are applied to the specs returned by pre-submission hooks, which are
+	// defaulted and validated like the webhook does on admission. Both are optional.
+	ApplicationDefaults *webhook.ApplicationDefaults
+	SparkConfPolicy     *webhook.SparkConfPolicy
+
+	// Submission configures how SparkApplications are submitted, see submit.go.
+	Submission SubmissionOptions
+
+	// Executor configures the lifecycle of the executors of SparkApplications, see executor.go.
+	Executor ExecutorOptions
+
+	// Scheduling configures the features holding SparkApplications back until they can be scheduled, see scheduling.go.
+	Scheduling SchedulingOptions
+
+	// EnableDriverPVCRBAC enables granting the driver service account access to persistent volume claims
+	// when dynamic allocation with shuffle tracking and PVC reuse are enabled. The permissions this requires
+	// are not part of the generated controller role, see config/rbac/driver-pvc-rbac.
+	EnableDriverPVCRBAC bool
+
+	// PrometheusConfigMapGCInterval is the interval of deleting the Prometheus ConfigMaps of SparkApplications that
+	// no longer exist. Such ConfigMaps are not deleted if set to 0.
+	PrometheusConfigMapGCInterval time.Duration
+
+	// DriverProgressScrapeInterval is the interval at which the progress of running SparkApplications is scraped
+	// from their drivers into their status. Scraping is disabled if set to 0.
+	DriverProgressScrapeInterval time.Duration
+
+	// DisableOwnerReferences disables setting owner references on the services, ingresses and ConfigMaps created
+	// for SparkApplications, which are then only labeled and explicitly cleaned up when the SparkApplications are
+	// deleted, e.g., for GitOps tools pruning resources with owner references of other controllers.
+	DisableOwnerReferences bool
+
+	// EnableNamespaceTerminationHandling enables failing the SparkApplications in namespaces being deleted right away,
+	// without retrying or recreating any of their resources while the namespaces are finalized.
+	EnableNamespaceTerminationHandling bool
+
+	// EnableSparkAuthSecret enables generating a per-application authentication secret and turning on
+	// authentication and encryption of Spark internal connections.
+	EnableSparkAuthSecret bool
+
+	// Archive stores terminated SparkApplications in object storage before they are deleted once their TTL expires,
+	// as well as the configuration snapshot of every submitted run.
+	Archive *archive.Archive
+
+	// PostRunWebhookURLPrefixes are the URL prefixes the webhook post-run actions of SparkApplications may call.
+	// Webhook post-run actions are refused if empty, as their URLs are set by the authors of SparkApplications.
+	PostRunWebhookURLPrefixes []string
+
+	// StateStore persists the bookkeeping of the submissions in flight and queued across restarts. Not persisted if nil.
+	StateStore statestore.Store
 }
 
 // Reconciler reconciles a SparkApplication object.
@@ -81,6 +144,16 @@ type Reconciler struct {
 	recorder record.EventRecorder
 	options  Options
 	registry *scheduler.Registry
+
+	submissionLimiter *submissionLimiter
+
+	// impersonatingClients caches the clients impersonating service accounts by namespace.
+	impersonatingClients sync.Map
+
+	// podsClient reads the logs of driver pods, see getPodsClient.
+	podsClient     corev1client.PodsGetter
+	podsClientErr  error
+	podsClientOnce sync.Once
 }
 
 // Reconciler implements reconcile.Reconciler.
@@ -102,15 +175,26 @@ func NewReconciler(
 		recorder: recorder,
 		registry: registry,
 		options:  options,
+
+		submissionLimiter: newSubmissionLimiter(options.Submission.MaxConcurrentPerNamespace, options.SparkSubmissionMetrics, options.StateStore),
 	}
 }
 
 // +kubebuilder:rbac:groups=,resources=pods,verbs=get;list;watch;create;update;patch;delete;deletecollection
-// +kubebuilder:rbac:groups=,resources=configmaps,verbs=get;list;create;update;patch;delete
-// +kubebuilder:rbac:groups=,resources=services,verbs=get;create;delete
-// +kubebuilder:rbac:groups=,resources=nodes,verbs=get
+// +kubebuilder:rbac:groups=,resources=pods/log,verbs=get
+// +kubebuilder:rbac:groups=,resources=configmaps,verbs=get;list;watch;create;update;patch;delete
+// +kubebuilder:rbac:groups=,resources=secrets,verbs=get;list;watch;create;update
+// +kubebuilder:rbac:groups=,resources=services,verbs=get;list;watch;create;update;patch;delete
+// +kubebuilder:rbac:groups=,resources=nodes,verbs=get;list;watch
+// +kubebuilder:rbac:groups=,resources=serviceaccounts,verbs=get;list;watch;impersonate
+// +kubebuilder:rbac:groups=,resources=serviceaccounts/token,verbs=create
+// +kubebuilder:rbac:groups=,resources=namespaces,verbs=get;list;watch
 // +kubebuilder:rbac:groups=,resources=events,verbs=create;update;patch
-// +kubebuilder:rbac:groups=,resources=resourcequotas,verbs=get;list;watch
+// +kubebuilder:rbac:groups=,resources=resourcequotas,verbs=get;list;watch;create;update;delete
+// +kubebuilder:rbac:groups=apps,resources=daemonsets,verbs=get;create;delete
+// +kubebuilder:rbac:groups=apps,resources=deployments;statefulsets,verbs=get
+// +kubebuilder:rbac:groups=batch,resources=jobs,verbs=get;list;watch;create;delete;deletecollection
+// +kubebuilder:rbac:groups=policy,resources=poddisruptionbudgets,verbs=get;create;update
 // +kubebuilder:rbac:groups=extensions,resources=ingresses,verbs=get;list;watch;create;update;patch;delete
 // +kubebuilder:rbac:groups=networking.k8s.io,resources=ingresses,verbs=get;list;watch;create;update;patch;delete
 // +kubebuilder:rbac:groups=apiextensions.k8s.io,resources=customresourcedefinitions,verbs=get
@@ -170,20 +254,55 @@ func (r *Reconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Resu
 	app, err := r.getSparkApplication(ctx, key)
 	if err != nil {
 		if errors.IsNotFound(err) {
+			r.submissionLimiter.forget(ctx, key)
 			return ctrl.Result{}, nil
 		}
 		return ctrl.Result{Requeue: true}, err
 	}
-	logger.Info("Reconciling SparkApplication", "name", app.Name, "namespace", app.Namespace, "state", app.Status.AppState.State)
-	defer logger.Info("Finished reconciling SparkApplication", "name", app.Name, "namespace", app.Namespace)
+	appLogger(app).Info("Reconciling SparkApplication", "state", app.Status.AppState.State)
+	defer appLogger(app).Info("Finished reconciling SparkApplication")
 
 	// Check if the spark application is being deleted
 	if !app.DeletionTimestamp.IsZero() {
 		return r.handleSparkApplicationDeletion(ctx, req)
 	}
-	switch app.Status.AppState.State {
+	if r.options.EnableNamespaceTerminationHandling {
+		terminating, err := r.isNamespaceTerminating(ctx, app.Namespace)
+		if err != nil {
+			return ctrl.Result{Requeue: true}, err
+		}
+		if terminating {
+			return r.reconcileSparkApplicationInTerminatingNamespace(ctx, req)
+		}
+	}
+	if r.options.DisableOwnerReferences {
+		if err := r.addFinalizer(ctx, app); err != nil {
+			appLogger(app).Error(err, "Failed to add finalizer to SparkApplication")
+			return ctrl.Result{Requeue: true}, err
+		}
+	}
+
+	var slaRequeueAfter time.Duration
+	if app.Spec.SLA != nil {
+		if slaRequeueAfter, err = r.checkSLA(ctx, app); err != nil {
+			appLogger(app).Error(err, "Failed to check SLA")
+		}
+	}
+	result, err := r.reconcileSparkApplicationState(ctx, req, app.Status.AppState.State)
+	if err == nil && slaRequeueAfter > 0 && !result.Requeue &&
+		(result.RequeueAfter == 0 || slaRequeueAfter < result.RequeueAfter) {
+		result.RequeueAfter = slaRequeueAfter
+	}
+	return result, err
+}
+
+// reconcileSparkApplicationState reconciles the SparkApplication according to its application state.
+func (r *Reconciler) reconcileSparkApplicationState(ctx context.Context, req ctrl.Request, state v1beta2.ApplicationStateType) (ctrl.Result, error) {
+	switch state {
 	case v1beta2.ApplicationStateNew:
 		return r.reconcileNewSparkApplication(ctx, req)
+	case v1beta2.ApplicationStateWaitingForDependencies:
+		return r.reconcileWaitingForDependenciesSparkApplication(ctx, req)
 	case v1beta2.ApplicationStateSubmitted:
 		return r.reconcileSubmittedSparkApplication(ctx, req)
 	case v1beta2.ApplicationStateFailedSubmission:
@@ -210,7 +329,7 @@ func (r *Reconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Resu
 
 // SetupWithManager sets up the controller with the Manager.
 func (r *Reconciler) SetupWithManager(mgr ctrl.Manager, options controller.Options) error {
-	return ctrl.NewControllerManagedBy(mgr).
+	b := ctrl.NewControllerManagedBy(mgr).
 		Named("spark-application-controller").
 		Watches(
 			&corev1.Pod{},
@@ -219,7 +338,7 @@ func (r *Reconciler) SetupWithManager(mgr ctrl.Manager, options controller.Optio
 		).
 		Watches(
 			&v1beta2.SparkApplication{},
-			NewSparkApplicationEventHandler(r.options.SparkApplicationMetrics),
+			NewSparkApplicationEventHandler(r.options.SparkApplicationMetrics, r.options.SparkSubmissionMetrics),
 			builder.WithPredicates(
 				NewSparkApplicationEventFilter(
 					mgr.GetClient(),
@@ -227,9 +346,38 @@ func (r *Reconciler) SetupWithManager(mgr ctrl.Manager, options controller.Optio
 					r.options.Namespaces,
 				),
 			),
-		).
-		WithOptions(options).
-		Complete(r)
+		)
+
+	if r.options.Submission.EnableSubmitterJob {
+		b = b.Watches(&batchv1.Job{}, newSubmitterJobEventHandler())
+	}
+
+	if r.options.Executor.EnableKarpenterDisruptionProtection {
+		if err := mgr.GetFieldIndexer().IndexField(context.Background(), &corev1.Pod{}, podNodeNameField, indexPodByNodeName); err != nil {
+			return fmt.Errorf("failed to index pods by node name: %v", err)
+		}
+		b = b.Watches(&corev1.Node{}, newKarpenterNodeEventHandler(mgr.GetClient()))
+	}
+
+	// Secrets are not watched, as the cache only holds those created by the operator and a metadata-only watch
+	// would share its label selector. Missing Secrets are polled for instead.
+	if r.options.Scheduling.EnableWaitingForDependencies {
+		b = b.Watches(&corev1.ConfigMap{}, newReferencedObjectEventHandler(mgr.GetClient())).
+			Watches(&corev1.ServiceAccount{}, newReferencedObjectEventHandler(mgr.GetClient()))
+	}
+
+	if r.options.EnableNamespaceTerminationHandling {
+		b = b.Watches(&corev1.Namespace{}, newNamespaceEventHandler(mgr.GetClient()))
+	}
+
+	if r.options.PrometheusConfigMapGCInterval > 0 {
+		collector := newPrometheusConfigMapCollector(mgr.GetClient(), mgr.GetAPIReader(), r.options.Namespaces, r.options.PrometheusConfigMapGCInterval)
+		if err := mgr.Add(collector); err != nil {
+			return fmt.Errorf("failed to add Prometheus ConfigMap collector: %v", err)
+		}
+	}
+
+	return b.WithOptions(options).Complete(r)
 }
 
 func (r *Reconciler) handleSparkApplicationDeletion(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
@@ -242,39 +390,63 @@ func (r *Reconciler) handleSparkApplicationDeletion(ctx context.Context, req ctr
 		return ctrl.Result{Requeue: true}, err
 	}
 
-	if err := r.deleteSparkResources(ctx, app); err != nil {
-		logger.Error(err, "Failed to delete resources associated with SparkApplication", "name", app.Name, "namespace", app.Namespace)
+	deleting, err := r.deleteSparkResources(ctx, app)
+	if err != nil {
+		appLogger(app).Error(err, "Failed to delete resources associated with SparkApplication")
 		return ctrl.Result{Requeue: true}, err
 	}
+	if deleting {
+		return r.executorDeletionResult(), nil
+	}
+
+	if controllerutil.ContainsFinalizer(app, common.SparkApplicationFinalizerName) {
+		if err := r.deletePrometheusConfigMap(ctx, app); err != nil {
+			appLogger(app).Error(err, "Failed to delete Prometheus ConfigMap of SparkApplication")
+			return ctrl.Result{Requeue: true}, err
+		}
+		if err := r.deleteResourceReservation(ctx, app); err != nil {
+			appLogger(app).Error(err, "Failed to delete resource reservation of SparkApplication")
+			return ctrl.Result{Requeue: true}, err
+		}
+		if err := r.deleteDriverMetricsService(ctx, app); err != nil {
+			appLogger(app).Error(err, "Failed to delete driver metrics service of SparkApplication")
+			return ctrl.Result{Requeue: true}, err
+		}
+		if err := r.removeFinalizer(ctx, app); err != nil {
+			appLogger(app).Error(err, "Failed to remove finalizer from SparkApplication")
+			return ctrl.Result{Requeue: true}, err
+		}
+	}
 	return ctrl.Result{}, nil
 }
 
+// reconcileNewSparkApplication submits the new SparkApplication once its dependencies are ready. The submission is
+// not retried upon conflicting status updates, only the status update is.
 func (r *Reconciler) reconcileNewSparkApplication(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
 	key := req.NamespacedName
-	retryErr := retry.RetryOnConflict(
-		retry.DefaultRetry,
-		func() error {
-			old, err := r.getSparkApplication(ctx, key)
-			if err != nil {
-				return err
-			}
-			if old.Status.AppState.State != v1beta2.ApplicationStateNew {
-				return nil
-			}
-			app := old.DeepCopy()
+	old, err := r.getSparkApplication(ctx, key)
+	if err != nil {
+		return ctrl.Result{Requeue: true}, err
+	}
+	if old.Status.AppState.State != v1beta2.ApplicationStateNew {
+		return ctrl.Result{}, nil
+	}
+	app := old.DeepCopy()
 
-			_ = r.submitSparkApplication(app)
-			if err := r.updateSparkApplicationStatus(ctx, app); err != nil {
-				return err
-			}
-			return nil
-		},
-	)
-	if retryErr != nil {
-		logger.Error(retryErr, "Failed to reconcile SparkApplication", "name", key.Name, "namespace", key.Namespace)
-		return ctrl.Result{Requeue: true}, retryErr
+	if ready, result := r.checkSubmissionPrerequisites(ctx, app); !ready {
+		if equality.Semantic.DeepEqual(old.Status, app.Status) {
+			return result, nil
+		}
+		return r.finishReconcile(ctx, old, app, result)
 	}
-	return ctrl.Result{}, nil
+
+	if !r.submissionLimiter.tryAcquire(ctx, key) {
+		return ctrl.Result{RequeueAfter: submissionThrottleInterval}, nil
+	}
+	defer r.submissionLimiter.release(ctx, key)
+
+	_ = r.submitSparkApplication(ctx, app)
+	return r.finishReconcile(ctx, old, app, ctrl.Result{})
 }
 
 func (r *Reconciler) reconcileSubmittedSparkApplication(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
@@ -291,10 +463,28 @@ func (r *Reconciler) reconcileSubmittedSparkApplication(ctx context.Context, req
 			}
 			app := old.DeepCopy()
 
+			// The driver pod is created once the submitter Job finishes, which triggers another reconciliation.
+			if r.getSubmissionEngine(app) == v1beta2.SubmissionEngineJob && r.options.Submission.EnableSubmitterJob {
+				done, err := r.reconcileSubmitterJob(ctx, app)
+				if err != nil {
+					return err
+				}
+				if !done {
+					if equality.Semantic.DeepEqual(old.Status, app.Status) {
+						return nil
+					}
+					return r.updateSparkApplicationStatus(ctx, old, app)
+				}
+			}
+
+			if err := r.configDriverService(ctx, app); err != nil {
+				appLogger(app).Error(err, "Failed to configure driver service")
+			}
+
 			if err := r.updateSparkApplicationState(ctx, app); err != nil {
 				return err
 			}
-			if err := r.updateSparkApplicationStatus(ctx, app); err != nil {
+			if err := r.updateSparkApplicationStatus(ctx, old, app); err != nil {
 				return err
 			}
 			return nil
@@ -307,62 +497,66 @@ func (r *Reconciler) reconcileSubmittedSparkApplication(ctx context.Context, req
 	return ctrl.Result{}, nil
 }
 
+// reconcileFailedSubmissionSparkApplication resubmits the SparkApplication once its next retry is due, or fails it if
+// it should not be retried. The submission is not retried upon conflicting status updates, only the status update is.
 func (r *Reconciler) reconcileFailedSubmissionSparkApplication(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
 	key := req.NamespacedName
+	old, err := r.getSparkApplication(ctx, key)
+	if err != nil {
+		return ctrl.Result{Requeue: true}, err
+	}
+	if old.Status.AppState.State != v1beta2.ApplicationStateFailedSubmission {
+		return ctrl.Result{}, nil
+	}
+	app := old.DeepCopy()
 
 	var result ctrl.Result
-
-	retryErr := retry.RetryOnConflict(
-		retry.DefaultRetry,
-		func() error {
-			old, err := r.getSparkApplication(ctx, key)
-			if err != nil {
-				return err
-			}
-			if old.Status.AppState.State != v1beta2.ApplicationStateFailedSubmission {
-				return nil
-			}
-			app := old.DeepCopy()
-
-			if util.ShouldRetry(app) {
-				timeUntilNextRetryDue, err := util.TimeUntilNextRetryDue(app)
+	if util.ShouldRetry(app) {
+		timeUntilNextRetryDue, err := util.TimeUntilNextRetryDue(app)
+		if err != nil {
+			logger.Error(err, "Failed to reconcile SparkApplication", "name", key.Name, "namespace", key.Namespace)
+			return ctrl.Result{}, err
+		}
+		if timeUntilNextRetryDue <= 0 {
+			if !r.validateSparkResourceDeletion(ctx, app) {
+				deleting, err := r.deleteSparkResources(ctx, app)
 				if err != nil {
-					return err
+					appLogger(app).Error(err, "failed to delete resources associated with SparkApplication")
+				} else if deleting {
+					return r.executorDeletionResult(), nil
 				}
-				if timeUntilNextRetryDue <= 0 {
-					if r.validateSparkResourceDeletion(ctx, app) {
-						_ = r.submitSparkApplication(app)
-					} else {
-						if err := r.deleteSparkResources(ctx, app); err != nil {
-							logger.Error(err, "failed to delete resources associated with SparkApplication", "name", app.Name, "namespace", app.Namespace)
-						}
-						return fmt.Errorf("resources associated with SparkApplication name: %s namespace: %s, needed to be deleted", app.Name, app.Namespace)
-					}
-				} else {
-					// If we're waiting before retrying then reconcile will not modify anything, so we need to requeue.
-					result.RequeueAfter = timeUntilNextRetryDue
-				}
-			} else {
-				app.Status.AppState.State = v1beta2.ApplicationStateFailed
-				app.Status.TerminationTime = metav1.Now()
-				r.recordSparkApplicationEvent(app)
+				err = fmt.Errorf("resources associated with SparkApplication name: %s namespace: %s, needed to be deleted", app.Name, app.Namespace)
+				logger.Error(err, "Failed to reconcile SparkApplication", "name", key.Name, "namespace", key.Namespace)
+				return ctrl.Result{}, err
 			}
-
-			if err := r.updateSparkApplicationStatus(ctx, app); err != nil {
-				return err
+			if !r.submissionLimiter.tryAcquire(ctx, key) {
+				return ctrl.Result{RequeueAfter: submissionThrottleInterval}, nil
 			}
-			return nil
-		},
-	)
-	if retryErr != nil {
-		logger.Error(retryErr, "Failed to reconcile SparkApplication", "name", key.Name, "namespace", key.Namespace)
-		return result, retryErr
+			defer r.submissionLimiter.release(ctx, key)
+			_ = r.submitSparkApplication(ctx, app)
+		} else {
+			// If we're waiting before retrying then reconcile will not modify anything, so we need to requeue.
+			result.RequeueAfter = timeUntilNextRetryDue
+		}
+	} else {
+		app.Status.AppState.State = v1beta2.ApplicationStateFailed
+		app.Status.TerminationTime = metav1.Now()
+		r.recordSparkApplicationEvent(app)
+	}
+
+	result, err = r.finishReconcile(ctx, old, app, result)
+	if err != nil {
+		return result, err
+	}
+	if util.IsTerminated(app) {
+		r.runPostCompletionHooks(ctx, app)
 	}
 	return result, nil
 }
 
 func (r *Reconciler) reconcileRunningSparkApplication(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
 	key := req.NamespacedName
+	var rotationRequeueAfter, quotaRequeueAfter time.Duration
 	retryErr := retry.RetryOnConflict(
 		retry.DefaultRetry,
 		func() error {
@@ -375,11 +569,51 @@ func (r *Reconciler) reconcileRunningSparkApplication(ctx context.Context, req c
 			}
 			app := old.DeepCopy()
 
+			if err := r.configDriverService(ctx, app); err != nil {
+				appLogger(app).Error(err, "Failed to configure driver service")
+			}
+
+			if err := r.reconcileDriverNetworking(ctx, app); err != nil {
+				appLogger(app).Error(err, "Failed to reconcile driver services and ingresses")
+			}
+
+			if util.PrometheusMonitoringEnabled(app) {
+				if err := r.reconcilePrometheusConfigMap(ctx, app); err != nil {
+					appLogger(app).Error(err, "Failed to reconcile Prometheus ConfigMap")
+				}
+			}
+
+			if r.options.Executor.EnableSecretRotation {
+				var err error
+				if rotationRequeueAfter, err = r.rotateExecutorSecrets(ctx, app); err != nil {
+					appLogger(app).Error(err, "Failed to rotate executor secrets")
+				}
+			}
+
 			if err := r.updateSparkApplicationState(ctx, app); err != nil {
 				return err
 			}
 
-			if err := r.updateSparkApplicationStatus(ctx, app); err != nil {
+			if r.options.Scheduling.EnableResourceReservation && app.Status.AppState.State == v1beta2.ApplicationStateRunning {
+				if err := r.updateResourceReservation(ctx, app); err != nil {
+					appLogger(app).Error(err, "Failed to update resource reservation")
+				}
+			}
+
+			if r.shouldCheckExecutorQuota(app) && app.Status.AppState.State == v1beta2.ApplicationStateRunning {
+				quotaRequeueAfter = executorQuotaCheckInterval
+				if err := r.checkExecutorQuota(ctx, app); err != nil {
+					appLogger(app).Error(err, "Failed to check executors against resource quotas")
+				}
+			}
+
+			if r.options.DriverProgressScrapeInterval > 0 && app.Status.AppState.State == v1beta2.ApplicationStateRunning {
+				if err := r.scrapeDriverProgress(ctx, app); err != nil {
+					appLogger(app).Error(err, "Failed to scrape driver progress")
+				}
+			}
+
+			if err := r.updateSparkApplicationStatus(ctx, old, app); err != nil {
 				return err
 			}
 
@@ -390,45 +624,51 @@ func (r *Reconciler) reconcileRunningSparkApplication(ctx context.Context, req c
 		logger.Error(retryErr, "Failed to reconcile SparkApplication", "name", key.Name, "namespace", key.Namespace)
 		return ctrl.Result{}, retryErr
 	}
+	// Periodically re-evaluate the executors, as nodes becoming unreachable do not trigger further pod events
+	// once the grace period expires, and neither do changes to the Secrets of executors with secret rotation,
+	// executors rejected by resource quotas or the progress reported by the driver.
+	requeueAfter := r.options.Executor.UnreachableGracePeriod
+	for _, after := range []time.Duration{rotationRequeueAfter, quotaRequeueAfter, r.options.DriverProgressScrapeInterval} {
+		if after > 0 && (requeueAfter <= 0 || after < requeueAfter) {
+			requeueAfter = after
+		}
+	}
+	if requeueAfter > 0 {
+		return ctrl.Result{RequeueAfter: requeueAfter}, nil
+	}
 	return ctrl.Result{}, nil
 }
 
+// reconcilePendingRerunSparkApplication resubmits the SparkApplication once the resources of its previous run are
+// deleted. The submission is not retried upon conflicting status updates, only the status update is.
 func (r *Reconciler) reconcilePendingRerunSparkApplication(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
 	key := req.NamespacedName
-	retryErr := retry.RetryOnConflict(
-		retry.DefaultRetry,
-		func() error {
-			old, err := r.getSparkApplication(ctx, key)
-			if err != nil {
-				return err
-			}
-			if old.Status.AppState.State != v1beta2.ApplicationStatePendingRerun {
-				return nil
-			}
-			app := old.DeepCopy()
+	old, err := r.getSparkApplication(ctx, key)
+	if err != nil {
+		return ctrl.Result{Requeue: true}, err
+	}
+	if old.Status.AppState.State != v1beta2.ApplicationStatePendingRerun {
+		return ctrl.Result{}, nil
+	}
+	app := old.DeepCopy()
 
-			logger.Info("Pending rerun SparkApplication", "name", app.Name, "namespace", app.Namespace, "state", app.Status.AppState.State)
-			if r.validateSparkResourceDeletion(ctx, app) {
-				logger.Info("Successfully deleted resources associated with SparkApplication", "name", app.Name, "namespace", app.Namespace, "state", app.Status.AppState.State)
-				r.recordSparkApplicationEvent(app)
-				r.resetSparkApplicationStatus(app)
-				_ = r.submitSparkApplication(app)
-			}
-			if err := r.updateSparkApplicationStatus(ctx, app); err != nil {
-				return err
-			}
-			return nil
-		},
-	)
-	if retryErr != nil {
-		logger.Error(retryErr, "Failed to reconcile SparkApplication", "name", key.Name, "namespace", key.Namespace)
-		return ctrl.Result{}, retryErr
+	appLogger(app).Info("Pending rerun SparkApplication", "state", app.Status.AppState.State)
+	if r.validateSparkResourceDeletion(ctx, app) {
+		if !r.submissionLimiter.tryAcquire(ctx, key) {
+			return ctrl.Result{RequeueAfter: submissionThrottleInterval}, nil
+		}
+		defer r.submissionLimiter.release(ctx, key)
+		appLogger(app).Info("Successfully deleted resources associated with SparkApplication", "state", app.Status.AppState.State)
+		r.recordSparkApplicationEvent(app)
+		r.resetSparkApplicationStatus(app)
+		_ = r.submitSparkApplication(ctx, app)
 	}
-	return ctrl.Result{}, nil
+	return r.finishReconcile(ctx, old, app, ctrl.Result{})
 }
 
 func (r *Reconciler) reconcileInvalidatingSparkApplication(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
 	key := req.NamespacedName
+	deleting := false
 	retryErr := retry.RetryOnConflict(
 		retry.DefaultRetry,
 		func() error {
@@ -442,13 +682,13 @@ func (r *Reconciler) reconcileInvalidatingSparkApplication(ctx context.Context,
 			app := old.DeepCopy()
 
 			// Invalidate the current run and enqueue the SparkApplication for re-execution.
-			if err := r.deleteSparkResources(ctx, app); err != nil {
-				logger.Error(err, "Failed to delete resources associated with SparkApplication", "name", app.Name, "namespace", app.Namespace)
-			} else {
+			if deleting, err = r.deleteSparkResources(ctx, app); err != nil {
+				appLogger(app).Error(err, "Failed to delete resources associated with SparkApplication")
+			} else if !deleting {
 				r.resetSparkApplicationStatus(app)
 				app.Status.AppState.State = v1beta2.ApplicationStatePendingRerun
 			}
-			if err := r.updateSparkApplicationStatus(ctx, app); err != nil {
+			if err := r.updateSparkApplicationStatus(ctx, old, app); err != nil {
 				return err
 			}
 			return nil
@@ -458,11 +698,15 @@ func (r *Reconciler) reconcileInvalidatingSparkApplication(ctx context.Context,
 		logger.Error(retryErr, "Failed to reconcile SparkApplication", "name", key.Name, "namespace", key.Namespace)
 		return ctrl.Result{}, retryErr
 	}
+	if deleting {
+		return r.executorDeletionResult(), nil
+	}
 	return ctrl.Result{}, nil
 }
 
 func (r *Reconciler) reconcileSucceedingSparkApplication(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
 	key := req.NamespacedName
+	deleting := false
 	retryErr := retry.RetryOnConflict(
 		retry.DefaultRetry,
 		func() error {
@@ -476,17 +720,23 @@ func (r *Reconciler) reconcileSucceedingSparkApplication(ctx context.Context, re
 			app := old.DeepCopy()
 
 			if util.ShouldRetry(app) {
-				if err := r.deleteSparkResources(ctx, app); err != nil {
-					logger.Error(err, "failed to delete spark resources", "name", app.Name, "namespace", app.Namespace)
+				if deleting, err = r.deleteSparkResources(ctx, app); err != nil {
+					appLogger(app).Error(err, "failed to delete spark resources")
 					return err
 				}
+				if deleting {
+					return nil
+				}
 				app.Status.AppState.State = v1beta2.ApplicationStatePendingRerun
 			} else {
 				app.Status.AppState.State = v1beta2.ApplicationStateCompleted
 			}
-			if err := r.updateSparkApplicationStatus(ctx, app); err != nil {
+			if err := r.updateSparkApplicationStatus(ctx, old, app); err != nil {
 				return err
 			}
+			if util.IsTerminated(app) {
+				r.runPostCompletionHooks(ctx, app)
+			}
 			return nil
 		},
 	)
@@ -494,6 +744,9 @@ func (r *Reconciler) reconcileSucceedingSparkApplication(ctx context.Context, re
 		logger.Error(retryErr, "Failed to reconcile SparkApplication", "name", key.Name, "namespace", key.Namespace)
 		return ctrl.Result{}, retryErr
 	}
+	if deleting {
+		return r.executorDeletionResult(), nil
+	}
 	return ctrl.Result{}, nil
 }
 
@@ -520,10 +773,15 @@ func (r *Reconciler) reconcileFailingSparkApplication(ctx context.Context, req c
 					return err
 				}
 				if timeUntilNextRetryDue <= 0 {
-					if err := r.deleteSparkResources(ctx, app); err != nil {
-						logger.Error(err, "failed to delete spark resources", "name", app.Name, "namespace", app.Namespace)
+					deleting, err := r.deleteSparkResources(ctx, app)
+					if err != nil {
+						appLogger(app).Error(err, "failed to delete spark resources")
 						return err
 					}
+					if deleting {
+						result = r.executorDeletionResult()
+						return nil
+					}
 					app.Status.AppState.State = v1beta2.ApplicationStatePendingRerun
 				} else {
 					// If we're waiting before retrying then reconcile will not modify anything, so we need to requeue.
@@ -532,9 +790,12 @@ func (r *Reconciler) reconcileFailingSparkApplication(ctx context.Context, req c
 			} else {
 				app.Status.AppState.State = v1beta2.ApplicationStateFailed
 			}
-			if err := r.updateSparkApplicationStatus(ctx, app); err != nil {
+			if err := r.updateSparkApplicationStatus(ctx, old, app); err != nil {
 				return err
 			}
+			if util.IsTerminated(app) {
+				r.runPostCompletionHooks(ctx, app)
+			}
 			return nil
 		},
 	)
@@ -565,8 +826,34 @@ func (r *Reconciler) reconcileTerminatedSparkApplication(ctx context.Context, re
 		return ctrl.Result{}, nil
 	}
 
+	// Outputs are verified before post-run actions are executed, so that the actions see the verified outputs.
+	if shouldVerifyOutputs(app) {
+		r.verifyOutputs(ctx, app)
+		if err := r.updateSparkApplicationStatus(ctx, old, app); err != nil {
+			return ctrl.Result{Requeue: true}, err
+		}
+		return ctrl.Result{Requeue: true}, nil
+	}
+
+	// Post-run actions are executed before anything else but the verification of outputs, in particular before the
+	// application is deleted once its TTL expires.
+	if actions := getPendingPostRunActions(app); len(actions) > 0 {
+		r.runPostRunActions(ctx, app, actions)
+		if err := r.updateSparkApplicationStatus(ctx, old, app); err != nil {
+			return ctrl.Result{Requeue: true}, err
+		}
+		return ctrl.Result{Requeue: true}, nil
+	}
+
 	if util.IsExpired(app) {
-		logger.Info("Deleting expired SparkApplication", "name", app.Name, "namespace", app.Namespace, "state", app.Status.AppState.State)
+		if r.options.Archive != nil {
+			if err := r.options.Archive.Put(ctx, app); err != nil {
+				appLogger(app).Error(err, "Failed to archive expired SparkApplication")
+				return ctrl.Result{Requeue: true}, err
+			}
+			appLogger(app).Info("Archived expired SparkApplication", "runID", archive.GetRunID(app))
+		}
+		appLogger(app).Info("Deleting expired SparkApplication", "state", app.Status.AppState.State)
 		if err := r.client.Delete(ctx, app); err != nil {
 			return ctrl.Result{Requeue: true}, err
 		}
@@ -577,7 +864,7 @@ func (r *Reconciler) reconcileTerminatedSparkApplication(ctx context.Context, re
 		return ctrl.Result{Requeue: true}, err
 	}
 
-	if err := r.updateSparkApplicationStatus(ctx, app); err != nil {
+	if err := r.updateSparkApplicationStatus(ctx, old, app); err != nil {
 		return ctrl.Result{Requeue: true}, err
 	}
 
@@ -586,13 +873,19 @@ func (r *Reconciler) reconcileTerminatedSparkApplication(ctx context.Context, re
 		return ctrl.Result{Requeue: true}, err
 	}
 
-	// If termination time or TTL is not set, will not requeue this application.
+	// Requeue the application for the deletion of its batch scheduler PodGroup once the PodGroup expires.
+	now := time.Now()
+	var podGroupRequeueAfter time.Duration
+	if expiry, ok := util.GetPodGroupExpiry(app); ok && expiry.After(now) {
+		podGroupRequeueAfter = expiry.Sub(now)
+	}
+
+	// If termination time or TTL is not set, will not requeue this application for deletion.
 	if app.Status.TerminationTime.IsZero() || app.Spec.TimeToLiveSeconds == nil || *app.Spec.TimeToLiveSeconds <= 0 {
-		return ctrl.Result{}, nil
+		return ctrl.Result{RequeueAfter: podGroupRequeueAfter}, nil
 	}
 
 	// Otherwise, requeue the application for subsequent deletion.
-	now := time.Now()
 	ttl := time.Duration(*app.Spec.TimeToLiveSeconds) * time.Second
 	survival := now.Sub(app.Status.TerminationTime.Time)
 
@@ -600,7 +893,10 @@ func (r *Reconciler) reconcileTerminatedSparkApplication(ctx context.Context, re
 	if survival >= ttl {
 		return ctrl.Result{Requeue: true}, nil
 	}
-	// Otherwise, requeue the application after (TTL - survival) seconds.
+	// Otherwise, requeue the application after (TTL - survival) seconds, or earlier if its PodGroup expires first.
+	if podGroupRequeueAfter > 0 && podGroupRequeueAfter < ttl-survival {
+		return ctrl.Result{RequeueAfter: podGroupRequeueAfter}, nil
+	}
 	return ctrl.Result{RequeueAfter: ttl - survival}, nil
 }
 
@@ -621,7 +917,7 @@ func (r *Reconciler) reconcileUnknownSparkApplication(ctx context.Context, req c
 			if err := r.updateSparkApplicationState(ctx, app); err != nil {
 				return err
 			}
-			if err := r.updateSparkApplicationStatus(ctx, app); err != nil {
+			if err := r.updateSparkApplicationStatus(ctx, old, app); err != nil {
 				return err
 			}
 			return nil
@@ -643,124 +939,6 @@ func (r *Reconciler) getSparkApplication(ctx context.Context, key types.Namespac
 	return app, nil
 }
 
-// submitSparkApplication creates a new submission for the given SparkApplication and submits it using spark-submit.
-func (r *Reconciler) submitSparkApplication(app *v1beta2.SparkApplication) (submitErr error) {
-	logger.Info("Submitting SparkApplication", "name", app.Name, "namespace", app.Namespace, "state", app.Status.AppState.State)
-
-	// SubmissionID must be set before creating any resources to ensure all the resources are labeled.
-	app.Status.SubmissionID = uuid.New().String()
-	app.Status.DriverInfo.PodName = util.GetDriverPodName(app)
-	app.Status.LastSubmissionAttemptTime = metav1.Now()
-	app.Status.SubmissionAttempts = app.Status.SubmissionAttempts + 1
-
-	defer func() {
-		if submitErr == nil {
-			app.Status.AppState = v1beta2.ApplicationState{
-				State: v1beta2.ApplicationStateSubmitted,
-			}
-			app.Status.ExecutionAttempts = app.Status.ExecutionAttempts + 1
-		} else {
-			logger.Info("Failed to submit SparkApplication", "name", app.Name, "namespace", app.Namespace, "state", app.Status.AppState.State, "error", submitErr)
-			app.Status.AppState = v1beta2.ApplicationState{
-				State:        v1beta2.ApplicationStateFailedSubmission,
-				ErrorMessage: submitErr.Error(),
-			}
-		}
-		r.recordSparkApplicationEvent(app)
-	}()
-
-	if util.PrometheusMonitoringEnabled(app) {
-		logger.Info("Configure Prometheus monitoring for SparkApplication", "name", app.Name, "namespace", app.Namespace)
-		if err := configPrometheusMonitoring(app, r.client); err != nil {
-			return fmt.Errorf("failed to configure Prometheus monitoring: %v", err)
-		}
-	}
-
-	// Use batch scheduler to perform scheduling task before submitting (before build command arguments).
-	if needScheduling, scheduler := r.shouldDoBatchScheduling(app); needScheduling {
-		logger.Info("Do batch scheduling for SparkApplication", "name", app.Name, "namespace", app.Namespace)
-		if err := scheduler.Schedule(app); err != nil {
-			return fmt.Errorf("failed to process batch scheduler: %v", err)
-		}
-	}
-
-	// Create web UI service for spark applications if enabled.
-	if r.options.EnableUIService {
-		service, err := r.createWebUIService(app)
-		if err != nil {
-			return fmt.Errorf("failed to create web UI service: %v", err)
-		}
-		app.Status.DriverInfo.WebUIServiceName = service.serviceName
-		app.Status.DriverInfo.WebUIPort = service.servicePort
-		app.Status.DriverInfo.WebUIAddress = fmt.Sprintf("%s:%d", service.serviceIP, app.Status.DriverInfo.WebUIPort)
-		logger.Info("Created web UI service for SparkApplication", "name", app.Name, "namespace", app.Namespace)
-
-		// Create UI Ingress if ingress-format is set.
-		if r.options.IngressURLFormat != "" {
-			// We are going to want to use an ingress url.
-			ingressURL, err := getDriverIngressURL(r.options.IngressURLFormat, app.Name, app.Namespace)
-			if err != nil {
-				return fmt.Errorf("failed to get ingress url: %v", err)
-			}
-			// need to ensure the spark.ui variables are configured correctly if a subPath is used.
-			if ingressURL.Path != "" {
-				if app.Spec.SparkConf == nil {
-					app.Spec.SparkConf = make(map[string]string)
-				}
-				app.Spec.SparkConf[common.SparkUIProxyBase] = ingressURL.Path
-				app.Spec.SparkConf[common.SparkUIProxyRedirectURI] = "/"
-			}
-			ingress, err := r.createWebUIIngress(app, *service, ingressURL, r.options.IngressClassName)
-			if err != nil {
-				return fmt.Errorf("failed to create web UI ingress: %v", err)
-			}
-			app.Status.DriverInfo.WebUIIngressAddress = ingress.ingressURL.String()
-			app.Status.DriverInfo.WebUIIngressName = ingress.ingressName
-			logger.Info("Created web UI ingress for SparkApplication", "name", app.Name, "namespace", app.Namespace)
-		}
-	}
-
-	for _, driverIngressConfiguration := range app.Spec.DriverIngressOptions {
-		logger.Info("Creating driver ingress service for SparkApplication", "name", app.Name, "namespace", app.Namespace)
-		service, err := r.createDriverIngressServiceFromConfiguration(app, &driverIngressConfiguration)
-		if err != nil {
-			return fmt.Errorf("failed to create driver ingress service for SparkApplication: %v", err)
-		}
-		// Create ingress if ingress-format is set.
-		if driverIngressConfiguration.IngressURLFormat != "" {
-			// We are going to want to use an ingress url.
-			ingressURL, err := getDriverIngressURL(driverIngressConfiguration.IngressURLFormat, app.Name, app.Namespace)
-			if err != nil {
-				return fmt.Errorf("failed to get driver ingress url: %v", err)
-			}
-			ingress, err := r.createDriverIngress(app, &driverIngressConfiguration, *service, ingressURL, r.options.IngressClassName)
-			if err != nil {
-				return fmt.Errorf("failed to create driver ingress: %v", err)
-			}
-			logger.V(1).Info("Created driver ingress for SparkApplication", "name", app.Name, "namespace", app.Namespace, "ingressName", ingress.ingressName, "ingressURL", ingress.ingressURL)
-		}
-	}
-
-	defer func() {
-		if err := r.cleanUpPodTemplateFiles(app); err != nil {
-			logger.Error(fmt.Errorf("failed to clean up pod template files: %v", err), "name", app.Name, "namespace", app.Namespace)
-		}
-	}()
-
-	sparkSubmitArgs, err := buildSparkSubmitArgs(app)
-	if err != nil {
-		return fmt.Errorf("failed to build spark-submit arguments: %v", err)
-	}
-
-	// Try submitting the application by running spark-submit.
-	logger.Info("Running spark-submit for SparkApplication", "name", app.Name, "namespace", app.Namespace, "arguments", sparkSubmitArgs)
-	if err := runSparkSubmit(newSubmission(sparkSubmitArgs, app)); err != nil {
-		r.recordSparkApplicationEvent(app)
-		return fmt.Errorf("failed to run spark-submit: %v", err)
-	}
-	return nil
-}
-
 // updateDriverState finds the driver pod of the application
 // and updates the driver state based on the current phase of the pod.
 func (r *Reconciler) updateDriverState(ctx context.Context, app *v1beta2.SparkApplication) error {
@@ -785,6 +963,7 @@ func (r *Reconciler) updateDriverState(ctx context.Context, app *v1beta2.SparkAp
 	}
 
 	app.Status.SparkApplicationID = util.GetSparkApplicationID(driverPod)
+	app.Status.InjectedDefaults = util.GetInjectedDefaults(driverPod)
 	driverState := util.GetDriverState(driverPod)
 	if util.IsDriverTerminated(driverState) {
 		if app.Status.TerminationTime.IsZero() {
@@ -799,6 +978,11 @@ func (r *Reconciler) updateDriverState(ctx context.Context, app *v1beta2.SparkAp
 				app.Status.AppState.ErrorMessage = "driver container status missing"
 			}
 		}
+		if driverPod.Status.Phase == corev1.PodRunning && util.ShouldTerminateDriverSidecars(app) {
+			if err := r.terminateDriverSidecars(ctx, app, driverPod); err != nil {
+				appLogger(app).Error(err, "Failed to terminate sidecars of driver pod", "pod", driverPod.Name)
+			}
+		}
 	}
 
 	newState := util.DriverStateToApplicationState(driverState)
@@ -811,94 +995,23 @@ func (r *Reconciler) updateDriverState(ctx context.Context, app *v1beta2.SparkAp
 	return nil
 }
 
-// updateExecutorState lists the executor pods of the application
-// and updates the executor state based on the current phase of the pods.
-func (r *Reconciler) updateExecutorState(ctx context.Context, app *v1beta2.SparkApplication) error {
-	podList, err := r.getExecutorPods(ctx, app)
-	if err != nil {
+// terminateDriverSidecars deletes the driver pod, whose sidecar containers keep it running after the driver container
+// has terminated. The deletion is conditioned on the UID of the pod so that a pod of a later run is never deleted.
+func (r *Reconciler) terminateDriverSidecars(ctx context.Context, app *v1beta2.SparkApplication, driverPod *corev1.Pod) error {
+	appLogger(app).Info("Deleting driver pod to terminate its sidecars", "pod", driverPod.Name)
+	if err := r.client.Delete(ctx, driverPod, client.Preconditions{UID: &driverPod.UID}); err != nil && !errors.IsNotFound(err) {
 		return err
 	}
-	pods := podList.Items
-
-	executorStateMap := make(map[string]v1beta2.ExecutorState)
-	var executorApplicationID string
-	for _, pod := range pods {
-		if util.IsExecutorPod(&pod) {
-			// If the executor number is higher than the `MaxTrackedExecutorPerApp` we want to stop persisting executors
-			if executorID, _ := strconv.Atoi(util.GetSparkExecutorID(&pod)); executorID > r.options.MaxTrackedExecutorPerApp {
-				continue
-			}
-			newState := util.GetExecutorState(&pod)
-			oldState, exists := app.Status.ExecutorState[pod.Name]
-			// Only record an executor event if the executor state is new or it has changed.
-			if !exists || newState != oldState {
-				if newState == v1beta2.ExecutorStateFailed {
-					execContainerState := util.GetExecutorContainerTerminatedState(&pod)
-					if execContainerState != nil {
-						r.recordExecutorEvent(app, newState, pod.Name, execContainerState.ExitCode, execContainerState.Reason)
-					} else {
-						// If we can't find the container state,
-						// we need to set the exitCode and the Reason to unambiguous values.
-						r.recordExecutorEvent(app, newState, pod.Name, -1, "Unknown (Container not Found)")
-					}
-				} else {
-					r.recordExecutorEvent(app, newState, pod.Name)
-				}
-			}
-			executorStateMap[pod.Name] = newState
-
-			if executorApplicationID == "" {
-				executorApplicationID = util.GetSparkApplicationID(&pod)
-			}
-		}
-	}
-
-	// ApplicationID label can be different on driver/executors. Prefer executor ApplicationID if set.
-	// Refer https://issues.apache.org/jira/projects/SPARK/issues/SPARK-25922 for details.
-	if executorApplicationID != "" {
-		app.Status.SparkApplicationID = executorApplicationID
-	}
-
-	if app.Status.ExecutorState == nil {
-		app.Status.ExecutorState = make(map[string]v1beta2.ExecutorState)
-	}
-	for name, state := range executorStateMap {
-		app.Status.ExecutorState[name] = state
-	}
-
-	// Handle missing/deleted executors.
-	for name, oldStatus := range app.Status.ExecutorState {
-		_, exists := executorStateMap[name]
-		if !util.IsExecutorTerminated(oldStatus) && !exists {
-			if !util.IsDriverRunning(app) {
-				// If ApplicationState is COMPLETED, in other words, the driver pod has been completed
-				// successfully. The executor pods terminate and are cleaned up, so we could not found
-				// the executor pod, under this circumstances, we assume the executor pod are completed.
-				if app.Status.AppState.State == v1beta2.ApplicationStateCompleted {
-					app.Status.ExecutorState[name] = v1beta2.ExecutorStateCompleted
-				} else {
-					glog.Infof("Executor pod %s not found, assuming it was deleted.", name)
-					app.Status.ExecutorState[name] = v1beta2.ExecutorStateFailed
-				}
-			} else {
-				app.Status.ExecutorState[name] = v1beta2.ExecutorStateUnknown
-			}
-		}
-	}
-
+	r.recorder.Eventf(
+		app,
+		corev1.EventTypeNormal,
+		common.EventSparkDriverSidecarsTerminated,
+		"Driver %s terminated with sidecars still running, deleted the driver pod",
+		driverPod.Name,
+	)
 	return nil
 }
 
-func (r *Reconciler) getExecutorPods(ctx context.Context, app *v1beta2.SparkApplication) (*corev1.PodList, error) {
-	matchLabels := util.GetResourceLabels(app)
-	matchLabels[common.LabelSparkRole] = common.SparkRoleExecutor
-	pods := &corev1.PodList{}
-	if err := r.client.List(ctx, pods, client.InNamespace(app.Namespace), client.MatchingLabels(matchLabels)); err != nil {
-		return nil, fmt.Errorf("failed to get pods for SparkApplication %s/%s: %v", app.Namespace, app.Name, err)
-	}
-	return pods, nil
-}
-
 func (r *Reconciler) getDriverPod(ctx context.Context, app *v1beta2.SparkApplication) (*corev1.Pod, error) {
 	pod := &corev1.Pod{}
 	var err error
@@ -927,29 +1040,112 @@ func (r *Reconciler) updateSparkApplicationState(ctx context.Context, app *v1bet
 	return nil
 }
 
-// updateSparkApplicationStatus updates the status of the SparkApplication.
-func (r *Reconciler) updateSparkApplicationStatus(ctx context.Context, app *v1beta2.SparkApplication) error {
-	if err := r.client.Status().Update(ctx, app); err != nil {
+// reconcileDriverNetworking creates the web UI service and ingress and the driver ingress services and ingresses of
+// the app or, if they already exist, brings them back to their desired state, e.g. after manual edits or a partially
+// failed submission. The web UI service and ingress are recorded in the status of the app.
+func (r *Reconciler) reconcileDriverNetworking(ctx context.Context, app *v1beta2.SparkApplication) error {
+	// The ingresses of the driver reference the htpasswd secret if basic authentication of the web UI is enabled.
+	if basicAuth := util.GetWebUIBasicAuth(app); basicAuth != nil {
+		if err := r.syncWebUIBasicAuthSecret(ctx, app, basicAuth); err != nil {
+			return fmt.Errorf("failed to sync web UI authentication secret: %v", err)
+		}
+	}
+
+	// Create web UI service for spark applications if enabled.
+	if r.options.EnableUIService {
+		service, err := r.createWebUIService(ctx, app)
+		if err != nil {
+			return fmt.Errorf("failed to create web UI service: %v", err)
+		}
+		app.Status.DriverInfo.WebUIServiceName = service.serviceName
+		app.Status.DriverInfo.WebUIPort = service.servicePort
+		app.Status.DriverInfo.WebUIAddress = fmt.Sprintf("%s:%d", service.serviceIP, app.Status.DriverInfo.WebUIPort)
+
+		// Create UI Ingress if ingress-format is set.
+		if r.options.IngressURLFormat != "" {
+			// We are going to want to use an ingress url.
+			ingressURL, err := getDriverIngressURL(r.options.IngressURLFormat, app.Name, app.Namespace)
+			if err != nil {
+				return fmt.Errorf("failed to get ingress url: %v", err)
+			}
+			ingress, err := r.createWebUIIngress(ctx, app, *service, ingressURL, r.options.IngressClassName)
+			if err != nil {
+				return fmt.Errorf("failed to create web UI ingress: %v", err)
+			}
+			app.Status.DriverInfo.WebUIIngressAddress = ingress.ingressURL.String()
+			app.Status.DriverInfo.WebUIIngressName = ingress.ingressName
+		}
+	}
+
+	for _, driverIngressConfiguration := range app.Spec.DriverIngressOptions {
+		service, err := r.createDriverIngressServiceFromConfiguration(ctx, app, &driverIngressConfiguration)
+		if err != nil {
+			return fmt.Errorf("failed to create driver ingress service for SparkApplication: %v", err)
+		}
+		// Create ingress if ingress-format is set.
+		if driverIngressConfiguration.IngressURLFormat != "" {
+			// We are going to want to use an ingress url.
+			ingressURL, err := getDriverIngressURL(driverIngressConfiguration.IngressURLFormat, app.Name, app.Namespace)
+			if err != nil {
+				return fmt.Errorf("failed to get driver ingress url: %v", err)
+			}
+			if _, err := r.createDriverIngress(ctx, app, &driverIngressConfiguration, *service, ingressURL, r.options.IngressClassName); err != nil {
+				return fmt.Errorf("failed to create driver ingress: %v", err)
+			}
+		}
+	}
+	return nil
+}
+
+// updateSparkApplicationStatus updates the status of the SparkApplication with a JSON merge patch of the changes
+// made to the status since old was read, which is much smaller than a full update of applications with many
+// executors. The patch does not carry the resource version, so that it does not conflict with concurrent changes
+// of the application, e.g. executor pod events, but only overwrites the status fields changed since old was read.
+func (r *Reconciler) updateSparkApplicationStatus(ctx context.Context, old, app *v1beta2.SparkApplication) error {
+	util.UpdateConditions(app)
+	if err := chaos.InjectStatusUpdateConflict(app); err != nil {
+		return err
+	}
+	if err := r.client.Status().Patch(ctx, app, client.MergeFrom(old)); err != nil {
 		return err
 	}
 	return nil
 }
 
-// Delete the resources associated with the spark application.
-func (r *Reconciler) deleteSparkResources(ctx context.Context, app *v1beta2.SparkApplication) error {
+// finishReconcile updates the status of the SparkApplication and returns the given result. Only the status update is
+// retried upon conflicts, so that the work the status results from, e.g. a submission, is not redone.
+func (r *Reconciler) finishReconcile(ctx context.Context, old, app *v1beta2.SparkApplication, result ctrl.Result) (ctrl.Result, error) {
+	err := retry.RetryOnConflict(retry.DefaultRetry, func() error {
+		return r.updateSparkApplicationStatus(ctx, old, app)
+	})
+	if err != nil {
+		logger.Error(err, "Failed to reconcile SparkApplication", "name", app.Name, "namespace", app.Namespace)
+		return ctrl.Result{Requeue: true}, err
+	}
+	return result, nil
+}
+
+// Delete the resources associated with the spark application. Returns whether executor pods remain to be deleted in
+// later batches, in which case the other resources are not deleted yet, see executorDeletionResult.
+func (r *Reconciler) deleteSparkResources(ctx context.Context, app *v1beta2.SparkApplication) (bool, error) {
+	deleting, err := r.deleteExecutorPods(ctx, app)
+	if err != nil || deleting {
+		return deleting, err
+	}
+
 	if err := r.deleteDriverPod(ctx, app); err != nil {
-		return err
+		return false, err
 	}
 
 	if err := r.deleteWebUIService(ctx, app); err != nil {
-		return err
+		return false, err
 	}
 
 	if err := r.deleteWebUIIngress(ctx, app); err != nil {
-		return err
+		return false, err
 	}
 
-	return nil
+	return false, nil
 }
 
 func (r *Reconciler) deleteDriverPod(ctx context.Context, app *v1beta2.SparkApplication) error {
@@ -1128,6 +1324,15 @@ func (r *Reconciler) recordSparkApplicationEvent(app *v1beta2.SparkApplication)
 			"SparkApplication %s is pending rerun",
 			app.Name,
 		)
+	case v1beta2.ApplicationStateWaitingForDependencies:
+		r.recorder.Eventf(
+			app,
+			corev1.EventTypeWarning,
+			common.EventSparkApplicationWaitingForDependencies,
+			"SparkApplication %s is waiting for dependencies: %s",
+			app.Name,
+			app.Status.AppState.ErrorMessage,
+		)
 	}
 }
 
@@ -1172,6 +1377,10 @@ func (r *Reconciler) resetSparkApplicationStatus(app *v1beta2.SparkApplication)
 		status.TerminationTime = metav1.Time{}
 		status.AppState.ErrorMessage = ""
 		status.ExecutorState = nil
+		status.ExecutorPlacement = nil
+		status.DriverProgress = nil
+		status.ExecutorQuotaShortfall = nil
+		clearRunSLAViolations(app)
 	case v1beta2.ApplicationStatePendingRerun:
 		status.SparkApplicationID = ""
 		status.SubmissionAttempts = 0
@@ -1179,6 +1388,10 @@ func (r *Reconciler) resetSparkApplicationStatus(app *v1beta2.SparkApplication)
 		status.DriverInfo = v1beta2.DriverInfo{}
 		status.AppState.ErrorMessage = ""
 		status.ExecutorState = nil
+		status.ExecutorPlacement = nil
+		status.DriverProgress = nil
+		status.ExecutorQuotaShortfall = nil
+		clearRunSLAViolations(app)
 	}
 }
 
@@ -1198,31 +1411,9 @@ func (r *Reconciler) shouldDoBatchScheduling(app *v1beta2.SparkApplication) (boo
 		return false, nil
 	}
 
-	var err error
-	var scheduler scheduler.Interface
-
-	switch schedulerName {
-	case common.VolcanoSchedulerName:
-		config := &volcano.Config{
-			RestConfig: r.manager.GetConfig(),
-		}
-		scheduler, err = r.registry.GetScheduler(schedulerName, config)
-	case yunikorn.SchedulerName:
-		scheduler, err = r.registry.GetScheduler(schedulerName, nil)
-	}
-
-	for _, name := range r.options.KubeSchedulerNames {
-		if schedulerName == name {
-			config := &kubescheduler.Config{
-				SchedulerName: name,
-				Client:        r.manager.GetClient(),
-			}
-			scheduler, err = r.registry.GetScheduler(name, config)
-		}
-	}
-
-	if err != nil || scheduler == nil {
-		logger.Error(err, "Failed to get scheduler for SparkApplication", "name", app.Name, "namespace", app.Namespace, "scheduler", schedulerName)
+	scheduler, err := r.registry.GetScheduler(schedulerName)
+	if err != nil {
+		appLogger(app).Error(err, "Failed to get scheduler for SparkApplication", "scheduler", schedulerName)
 		return false, nil
 	}
 	return scheduler.ShouldSchedule(app), scheduler
@@ -1235,12 +1426,19 @@ func (r *Reconciler) cleanUpOnTermination(_, newApp *v1beta2.SparkApplication) e
 			return err
 		}
 	}
+	if err := r.deleteResourceReservation(context.TODO(), newApp); err != nil {
+		return err
+	}
+	if err := r.deleteDriverMetricsService(context.TODO(), newApp); err != nil {
+		return err
+	}
 	return nil
 }
 
-// cleanUpPodTemplateFiles cleans up the driver and executor pod template files.
+// cleanUpPodTemplateFiles cleans up the driver and executor pod template files, as well as the token file of the
+// impersonated service account.
 func (r *Reconciler) cleanUpPodTemplateFiles(app *v1beta2.SparkApplication) error {
-	if app.Spec.Driver.Template == nil && app.Spec.Executor.Template == nil {
+	if app.Spec.Driver.Template == nil && app.Spec.Executor.Template == nil && r.options.Submission.ImpersonateServiceAccount == "" {
 		return nil
 	}
 	path := fmt.Sprintf("/tmp/spark/%s", app.Status.SubmissionID)
@@ -18,22 +18,46 @@ package sparkapplication_test
 
 import (
 	"context"
+	"encoding/base64"
+	"encoding/json"
 	"fmt"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync/atomic"
 	"time"
 
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
 
+	"golang.org/x/crypto/bcrypt"
+	batchv1 "k8s.io/api/batch/v1"
 	corev1 "k8s.io/api/core/v1"
+	networkingv1 "k8s.io/api/networking/v1"
+	rbacv1 "k8s.io/api/rbac/v1"
 	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/client-go/tools/record"
+	"k8s.io/utils/ptr"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/interceptor"
+	"sigs.k8s.io/controller-runtime/pkg/controller"
+	metricsserver "sigs.k8s.io/controller-runtime/pkg/metrics/server"
 	"sigs.k8s.io/controller-runtime/pkg/reconcile"
 
 	"github.com/kubeflow/spark-operator/api/v1beta2"
 	"github.com/kubeflow/spark-operator/internal/controller/sparkapplication"
+	"github.com/kubeflow/spark-operator/internal/webhook"
 	"github.com/kubeflow/spark-operator/pkg/common"
+	"github.com/kubeflow/spark-operator/pkg/naming"
 	"github.com/kubeflow/spark-operator/pkg/util"
 )
 
@@ -550,6 +574,3049 @@ var _ = Describe("SparkApplication Controller", func() {
 			Expect(app.Status.ExecutorState).To(HaveLen(1))
 		})
 	})
+
+	Context("When reconciling a running SparkApplication with scheduled executor pods", func() {
+		ctx := context.Background()
+		appName := "test-placement"
+		appNamespace := "default"
+		nodeName := "test-placement-node"
+		missingNodeName := "test-placement-missing-node"
+		key := types.NamespacedName{
+			Name:      appName,
+			Namespace: appNamespace,
+		}
+
+		BeforeEach(func() {
+			By("Creating a node in a zone")
+			node := &corev1.Node{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:   nodeName,
+					Labels: map[string]string{corev1.LabelTopologyZone: "zone-a"},
+				},
+			}
+			Expect(k8sClient.Create(ctx, node)).To(Succeed())
+
+			By("Creating a test SparkApplication")
+			app := &v1beta2.SparkApplication{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      appName,
+					Namespace: appNamespace,
+				},
+				Spec: v1beta2.SparkApplicationSpec{
+					MainApplicationFile: util.StringPtr("local:///dummy.jar"),
+				},
+			}
+			v1beta2.SetSparkApplicationDefaults(app)
+			Expect(k8sClient.Create(ctx, app)).To(Succeed())
+
+			driverPod := createDriverPod(appName, appNamespace)
+			Expect(k8sClient.Create(ctx, driverPod)).To(Succeed())
+			driverPod.Status.Phase = corev1.PodRunning
+			Expect(k8sClient.Status().Update(ctx, driverPod)).To(Succeed())
+
+			app.Status.DriverInfo.PodName = driverPod.Name
+			app.Status.AppState.State = v1beta2.ApplicationStateRunning
+			Expect(k8sClient.Status().Update(ctx, app)).To(Succeed())
+
+			By("Creating executor pods scheduled to an existing and a missing node")
+			for id, scheduledTo := range map[int]string{1: nodeName, 2: missingNodeName} {
+				executorPod := createExecutorPod(appName, appNamespace, id)
+				executorPod.Spec.NodeName = scheduledTo
+				Expect(k8sClient.Create(ctx, executorPod)).To(Succeed())
+				executorPod.Status.Phase = corev1.PodRunning
+				Expect(k8sClient.Status().Update(ctx, executorPod)).To(Succeed())
+			}
+		})
+
+		AfterEach(func() {
+			app := &v1beta2.SparkApplication{}
+			Expect(k8sClient.Get(ctx, key, app)).To(Succeed())
+
+			By("Deleting the created test SparkApplication")
+			Expect(k8sClient.Delete(ctx, app)).To(Succeed())
+
+			By("Deleting the driver and executor pods, which are bound to nodes without kubelets")
+			Expect(k8sClient.Delete(ctx, &corev1.Pod{ObjectMeta: metav1.ObjectMeta{
+				Name:      getDriverNamespacedName(appName, appNamespace).Name,
+				Namespace: appNamespace,
+			}})).To(Succeed())
+			for _, id := range []int{1, 2} {
+				Expect(k8sClient.Delete(ctx, &corev1.Pod{ObjectMeta: metav1.ObjectMeta{
+					Name:      getExecutorNamespacedName(appName, appNamespace, id).Name,
+					Namespace: appNamespace,
+				}}, client.GracePeriodSeconds(0))).To(Succeed())
+			}
+
+			By("Deleting the node")
+			Expect(k8sClient.Delete(ctx, &corev1.Node{ObjectMeta: metav1.ObjectMeta{Name: nodeName}})).To(Succeed())
+		})
+
+		It("Should not record the placement of the executors by default", func() {
+			By("Reconciling the running SparkApplication")
+			reconciler := sparkapplication.NewReconciler(
+				nil,
+				k8sClient.Scheme(),
+				k8sClient,
+				record.NewFakeRecorder(3),
+				nil,
+				sparkapplication.Options{Namespaces: []string{appNamespace}, MaxTrackedExecutorPerApp: 10},
+			)
+			_, err := reconciler.Reconcile(ctx, reconcile.Request{NamespacedName: key})
+			Expect(err).NotTo(HaveOccurred())
+
+			app := &v1beta2.SparkApplication{}
+			Expect(k8sClient.Get(ctx, key, app)).To(Succeed())
+			Expect(app.Status.ExecutorState).To(HaveLen(2))
+			Expect(app.Status.ExecutorPlacement).To(BeEmpty())
+		})
+
+		It("Should record the node and zone of the executors if enabled", func() {
+			By("Reconciling the running SparkApplication")
+			reconciler := sparkapplication.NewReconciler(
+				nil,
+				k8sClient.Scheme(),
+				k8sClient,
+				record.NewFakeRecorder(3),
+				nil,
+				sparkapplication.Options{
+					Namespaces:               []string{appNamespace},
+					MaxTrackedExecutorPerApp: 10,
+					Executor:                 sparkapplication.ExecutorOptions{EnablePlacementTracking: true},
+				},
+			)
+			_, err := reconciler.Reconcile(ctx, reconcile.Request{NamespacedName: key})
+			Expect(err).NotTo(HaveOccurred())
+
+			app := &v1beta2.SparkApplication{}
+			Expect(k8sClient.Get(ctx, key, app)).To(Succeed())
+			Expect(app.Status.ExecutorPlacement).To(Equal(map[string]v1beta2.ExecutorPlacement{
+				getExecutorNamespacedName(appName, appNamespace, 1).Name: {NodeName: nodeName, Zone: "zone-a"},
+				getExecutorNamespacedName(appName, appNamespace, 2).Name: {NodeName: missingNodeName},
+			}))
+		})
+
+		It("Should record the placement of at most the maximum number of tracked executors", func() {
+			By("Reconciling the running SparkApplication")
+			reconciler := sparkapplication.NewReconciler(
+				nil,
+				k8sClient.Scheme(),
+				k8sClient,
+				record.NewFakeRecorder(3),
+				nil,
+				sparkapplication.Options{
+					Namespaces:               []string{appNamespace},
+					MaxTrackedExecutorPerApp: 1,
+					Executor:                 sparkapplication.ExecutorOptions{EnablePlacementTracking: true},
+				},
+			)
+			_, err := reconciler.Reconcile(ctx, reconcile.Request{NamespacedName: key})
+			Expect(err).NotTo(HaveOccurred())
+
+			app := &v1beta2.SparkApplication{}
+			Expect(k8sClient.Get(ctx, key, app)).To(Succeed())
+			Expect(app.Status.ExecutorPlacement).To(HaveLen(1))
+		})
+	})
+
+	Context("When reconciling a running SparkApplication with executors on unreachable nodes", func() {
+		ctx := context.Background()
+		appName := "test-unreachable"
+		appNamespace := "default"
+		recentlyNotReadyNodeName := "test-unreachable-recent-node"
+		longNotReadyNodeName := "test-unreachable-long-node"
+		missingNodeName := "test-unreachable-missing-node"
+		gracePeriod := 5 * time.Minute
+		key := types.NamespacedName{
+			Name:      appName,
+			Namespace: appNamespace,
+		}
+
+		// Executors by ID, with the node they are scheduled to and the time they were scheduled.
+		executorNodes := map[int]string{
+			1: recentlyNotReadyNodeName,
+			2: longNotReadyNodeName,
+			3: missingNodeName,
+			4: missingNodeName,
+		}
+		executorScheduledAgo := map[int]time.Duration{
+			1: time.Hour,
+			2: time.Hour,
+			3: time.Minute,
+			4: time.Hour,
+		}
+
+		BeforeEach(func() {
+			By("Creating nodes which are not ready")
+			for name, notReadyFor := range map[string]time.Duration{recentlyNotReadyNodeName: time.Minute, longNotReadyNodeName: time.Hour} {
+				node := &corev1.Node{ObjectMeta: metav1.ObjectMeta{Name: name}}
+				Expect(k8sClient.Create(ctx, node)).To(Succeed())
+				node.Status.Conditions = []corev1.NodeCondition{
+					{
+						Type:               corev1.NodeReady,
+						Status:             corev1.ConditionUnknown,
+						LastTransitionTime: metav1.NewTime(time.Now().Add(-notReadyFor)),
+					},
+				}
+				Expect(k8sClient.Status().Update(ctx, node)).To(Succeed())
+			}
+
+			By("Creating a test SparkApplication")
+			app := &v1beta2.SparkApplication{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      appName,
+					Namespace: appNamespace,
+				},
+				Spec: v1beta2.SparkApplicationSpec{
+					MainApplicationFile: util.StringPtr("local:///dummy.jar"),
+				},
+			}
+			v1beta2.SetSparkApplicationDefaults(app)
+			Expect(k8sClient.Create(ctx, app)).To(Succeed())
+
+			driverPod := createDriverPod(appName, appNamespace)
+			Expect(k8sClient.Create(ctx, driverPod)).To(Succeed())
+			driverPod.Status.Phase = corev1.PodRunning
+			Expect(k8sClient.Status().Update(ctx, driverPod)).To(Succeed())
+
+			app.Status.DriverInfo.PodName = driverPod.Name
+			app.Status.AppState.State = v1beta2.ApplicationStateRunning
+			Expect(k8sClient.Status().Update(ctx, app)).To(Succeed())
+
+			By("Creating running executor pods which are not ready")
+			for id, nodeName := range executorNodes {
+				executorPod := createExecutorPod(appName, appNamespace, id)
+				executorPod.Spec.NodeName = nodeName
+				Expect(k8sClient.Create(ctx, executorPod)).To(Succeed())
+				executorPod.Status.Phase = corev1.PodRunning
+				executorPod.Status.Conditions = []corev1.PodCondition{
+					{
+						Type:               corev1.PodScheduled,
+						Status:             corev1.ConditionTrue,
+						LastTransitionTime: metav1.NewTime(time.Now().Add(-executorScheduledAgo[id])),
+					},
+					{
+						Type:   corev1.PodReady,
+						Status: corev1.ConditionFalse,
+					},
+				}
+				Expect(k8sClient.Status().Update(ctx, executorPod)).To(Succeed())
+			}
+		})
+
+		AfterEach(func() {
+			app := &v1beta2.SparkApplication{}
+			Expect(k8sClient.Get(ctx, key, app)).To(Succeed())
+
+			By("Deleting the created test SparkApplication")
+			Expect(k8sClient.Delete(ctx, app)).To(Succeed())
+
+			By("Deleting the driver and executor pods, which are bound to nodes without kubelets")
+			Expect(k8sClient.Delete(ctx, &corev1.Pod{ObjectMeta: metav1.ObjectMeta{
+				Name:      getDriverNamespacedName(appName, appNamespace).Name,
+				Namespace: appNamespace,
+			}})).To(Succeed())
+			for id := range executorNodes {
+				Expect(client.IgnoreNotFound(k8sClient.Delete(ctx, &corev1.Pod{ObjectMeta: metav1.ObjectMeta{
+					Name:      getExecutorNamespacedName(appName, appNamespace, id).Name,
+					Namespace: appNamespace,
+				}}, client.GracePeriodSeconds(0)))).To(Succeed())
+			}
+
+			By("Deleting the nodes")
+			for _, name := range []string{recentlyNotReadyNodeName, longNotReadyNodeName} {
+				Expect(k8sClient.Delete(ctx, &corev1.Node{ObjectMeta: metav1.ObjectMeta{Name: name}})).To(Succeed())
+			}
+		})
+
+		It("Should treat executors as failed only once the grace period has passed", func() {
+			By("Reconciling the running SparkApplication")
+			reconciler := sparkapplication.NewReconciler(
+				nil,
+				k8sClient.Scheme(),
+				k8sClient,
+				record.NewFakeRecorder(10),
+				nil,
+				sparkapplication.Options{
+					Namespaces:               []string{appNamespace},
+					MaxTrackedExecutorPerApp: 10,
+					Executor:                 sparkapplication.ExecutorOptions{UnreachableGracePeriod: gracePeriod},
+				},
+			)
+			_, err := reconciler.Reconcile(ctx, reconcile.Request{NamespacedName: key})
+			Expect(err).NotTo(HaveOccurred())
+
+			app := &v1beta2.SparkApplication{}
+			Expect(k8sClient.Get(ctx, key, app)).To(Succeed())
+			Expect(app.Status.ExecutorState).To(Equal(map[string]v1beta2.ExecutorState{
+				getExecutorNamespacedName(appName, appNamespace, 1).Name: v1beta2.ExecutorStateRunning,
+				getExecutorNamespacedName(appName, appNamespace, 2).Name: v1beta2.ExecutorStateFailed,
+				getExecutorNamespacedName(appName, appNamespace, 3).Name: v1beta2.ExecutorStateRunning,
+				getExecutorNamespacedName(appName, appNamespace, 4).Name: v1beta2.ExecutorStateFailed,
+			}))
+
+			By("Checking that the executor pods are kept")
+			for id := range executorNodes {
+				Expect(k8sClient.Get(ctx, getExecutorNamespacedName(appName, appNamespace, id), &corev1.Pod{})).To(Succeed())
+			}
+		})
+
+		It("Should force delete the executor pods treated as failed if enabled", func() {
+			By("Reconciling the running SparkApplication")
+			reconciler := sparkapplication.NewReconciler(
+				nil,
+				k8sClient.Scheme(),
+				k8sClient,
+				record.NewFakeRecorder(10),
+				nil,
+				sparkapplication.Options{
+					Namespaces:               []string{appNamespace},
+					MaxTrackedExecutorPerApp: 10,
+					Executor: sparkapplication.ExecutorOptions{
+						UnreachableGracePeriod: gracePeriod,
+						ForceDeleteUnreachable: true,
+					},
+				},
+			)
+			_, err := reconciler.Reconcile(ctx, reconcile.Request{NamespacedName: key})
+			Expect(err).NotTo(HaveOccurred())
+
+			By("Checking that only the executor pods treated as failed are deleted")
+			for _, id := range []int{1, 3} {
+				Expect(k8sClient.Get(ctx, getExecutorNamespacedName(appName, appNamespace, id), &corev1.Pod{})).To(Succeed())
+			}
+			for _, id := range []int{2, 4} {
+				err := k8sClient.Get(ctx, getExecutorNamespacedName(appName, appNamespace, id), &corev1.Pod{})
+				Expect(errors.IsNotFound(err)).To(BeTrue())
+			}
+		})
+
+		It("Should count the executors on unreachable nodes as running if no grace period is set", func() {
+			By("Reconciling the running SparkApplication")
+			reconciler := sparkapplication.NewReconciler(
+				nil,
+				k8sClient.Scheme(),
+				k8sClient,
+				record.NewFakeRecorder(10),
+				nil,
+				sparkapplication.Options{Namespaces: []string{appNamespace}, MaxTrackedExecutorPerApp: 10},
+			)
+			_, err := reconciler.Reconcile(ctx, reconcile.Request{NamespacedName: key})
+			Expect(err).NotTo(HaveOccurred())
+
+			app := &v1beta2.SparkApplication{}
+			Expect(k8sClient.Get(ctx, key, app)).To(Succeed())
+			Expect(app.Status.ExecutorState).To(HaveLen(len(executorNodes)))
+			for _, state := range app.Status.ExecutorState {
+				Expect(state).To(Equal(v1beta2.ExecutorStateRunning))
+			}
+		})
+	})
+
+	Context("When reconciling an invalidating SparkApplication with executor pods", func() {
+		ctx := context.Background()
+		appName := "test-executor-deletion"
+		appNamespace := "default"
+		key := types.NamespacedName{
+			Name:      appName,
+			Namespace: appNamespace,
+		}
+
+		BeforeEach(func() {
+			By("Creating a test SparkApplication")
+			app := &v1beta2.SparkApplication{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      appName,
+					Namespace: appNamespace,
+				},
+				Spec: v1beta2.SparkApplicationSpec{
+					MainApplicationFile: util.StringPtr("local:///dummy.jar"),
+				},
+			}
+			v1beta2.SetSparkApplicationDefaults(app)
+			Expect(k8sClient.Create(ctx, app)).To(Succeed())
+
+			app.Status.AppState.State = v1beta2.ApplicationStateInvalidating
+			Expect(k8sClient.Status().Update(ctx, app)).To(Succeed())
+
+			By("Creating the executor pods")
+			for id := 1; id <= 3; id++ {
+				Expect(k8sClient.Create(ctx, createExecutorPod(appName, appNamespace, id))).To(Succeed())
+			}
+		})
+
+		AfterEach(func() {
+			app := &v1beta2.SparkApplication{}
+			Expect(k8sClient.Get(ctx, key, app)).To(Succeed())
+
+			By("Deleting the created test SparkApplication")
+			Expect(k8sClient.Delete(ctx, app)).To(Succeed())
+
+			By("Deleting the remaining executor pods")
+			for id := 1; id <= 3; id++ {
+				pod := &corev1.Pod{}
+				pod.Name = getExecutorNamespacedName(appName, appNamespace, id).Name
+				pod.Namespace = appNamespace
+				Expect(client.IgnoreNotFound(k8sClient.Delete(ctx, pod))).To(Succeed())
+			}
+		})
+
+		listExecutorPods := func() []corev1.Pod {
+			pods := &corev1.PodList{}
+			Expect(k8sClient.List(ctx, pods, client.InNamespace(appNamespace), client.MatchingLabels{
+				common.LabelSparkAppName: appName,
+				common.LabelSparkRole:    common.SparkRoleExecutor,
+			})).To(Succeed())
+			return pods.Items
+		}
+
+		It("Should leave the executor pods to the garbage collector if executor deletion batching is disabled", func() {
+			By("Reconciling the invalidating SparkApplication")
+			reconciler := sparkapplication.NewReconciler(
+				nil,
+				k8sClient.Scheme(),
+				k8sClient,
+				record.NewFakeRecorder(3),
+				nil,
+				sparkapplication.Options{Namespaces: []string{appNamespace}},
+			)
+			_, err := reconciler.Reconcile(ctx, reconcile.Request{NamespacedName: key})
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(listExecutorPods()).To(HaveLen(3))
+		})
+
+		It("Should delete at most one batch of executor pods per reconcile", func() {
+			reconciler := sparkapplication.NewReconciler(
+				nil,
+				k8sClient.Scheme(),
+				k8sClient,
+				record.NewFakeRecorder(3),
+				nil,
+				sparkapplication.Options{
+					Namespaces: []string{appNamespace},
+					Executor:   sparkapplication.ExecutorOptions{DeletionBatchSize: 2, DeletionBatchInterval: time.Hour},
+				},
+			)
+
+			By("Reconciling the invalidating SparkApplication")
+			result, err := reconciler.Reconcile(ctx, reconcile.Request{NamespacedName: key})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(result.RequeueAfter).To(Equal(time.Hour))
+
+			Expect(listExecutorPods()).To(HaveLen(1))
+			app := &v1beta2.SparkApplication{}
+			Expect(k8sClient.Get(ctx, key, app)).To(Succeed())
+			Expect(app.Status.AppState.State).To(Equal(v1beta2.ApplicationStateInvalidating))
+
+			By("Reconciling the invalidating SparkApplication after the batch interval")
+			result, err = reconciler.Reconcile(ctx, reconcile.Request{NamespacedName: key})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(result).To(Equal(ctrl.Result{}))
+
+			Expect(listExecutorPods()).To(BeEmpty())
+			Expect(k8sClient.Get(ctx, key, app)).To(Succeed())
+			Expect(app.Status.AppState.State).To(Equal(v1beta2.ApplicationStatePendingRerun))
+		})
+	})
+
+	Context("When reconciling a running SparkApplication whose driver service and ingress drifted", func() {
+		ctx := context.Background()
+		appName := "test-networking-drift"
+		appNamespace := "default"
+		key := types.NamespacedName{
+			Name:      appName,
+			Namespace: appNamespace,
+		}
+		serviceKey := types.NamespacedName{
+			Name:      appName + "-ui-svc",
+			Namespace: appNamespace,
+		}
+		ingressKey := types.NamespacedName{
+			Name:      appName + "-ui-ingress",
+			Namespace: appNamespace,
+		}
+		ingressCapabilities := util.IngressCapabilities
+
+		newService := func(app *v1beta2.SparkApplication) *corev1.Service {
+			return &corev1.Service{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:            serviceKey.Name,
+					Namespace:       serviceKey.Namespace,
+					Labels:          map[string]string{common.LabelSparkAppName: appName, "other-controller": "true"},
+					Annotations:     map[string]string{"edited": "true"},
+					OwnerReferences: []metav1.OwnerReference{util.GetOwnerReference(app)},
+				},
+				Spec: corev1.ServiceSpec{
+					Type:     corev1.ServiceTypeNodePort,
+					Ports:    []corev1.ServicePort{{Name: common.DefaultSparkWebUIPortName, Port: 8080, Protocol: corev1.ProtocolTCP}},
+					Selector: map[string]string{"app": "edited"},
+				},
+			}
+		}
+
+		newIngress := func(app *v1beta2.SparkApplication) *networkingv1.Ingress {
+			return &networkingv1.Ingress{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:            ingressKey.Name,
+					Namespace:       ingressKey.Namespace,
+					Annotations:     map[string]string{"other-controller": "true"},
+					OwnerReferences: []metav1.OwnerReference{util.GetOwnerReference(app)},
+				},
+				Spec: networkingv1.IngressSpec{
+					DefaultBackend: &networkingv1.IngressBackend{
+						Service: &networkingv1.IngressServiceBackend{
+							Name: "edited-svc",
+							Port: networkingv1.ServiceBackendPort{Number: 8080},
+						},
+					},
+				},
+			}
+		}
+
+		BeforeEach(func() {
+			util.IngressCapabilities = util.Capabilities{"networking.k8s.io/v1": true}
+
+			By("Creating a test SparkApplication")
+			app := &v1beta2.SparkApplication{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      appName,
+					Namespace: appNamespace,
+				},
+				Spec: v1beta2.SparkApplicationSpec{
+					MainApplicationFile: util.StringPtr("local:///dummy.jar"),
+					SparkUIOptions: &v1beta2.SparkUIConfiguration{
+						ServiceType: ptr.To(corev1.ServiceTypeNodePort),
+					},
+				},
+			}
+			v1beta2.SetSparkApplicationDefaults(app)
+			Expect(k8sClient.Create(ctx, app)).To(Succeed())
+
+			driverPod := createDriverPod(appName, appNamespace)
+			Expect(k8sClient.Create(ctx, driverPod)).To(Succeed())
+			driverPod.Status.Phase = corev1.PodRunning
+			Expect(k8sClient.Status().Update(ctx, driverPod)).To(Succeed())
+
+			app.Status.DriverInfo.PodName = driverPod.Name
+			app.Status.AppState.State = v1beta2.ApplicationStateRunning
+			Expect(k8sClient.Status().Update(ctx, app)).To(Succeed())
+		})
+
+		AfterEach(func() {
+			util.IngressCapabilities = ingressCapabilities
+
+			app := &v1beta2.SparkApplication{}
+			Expect(k8sClient.Get(ctx, key, app)).To(Succeed())
+
+			By("Deleting the created test SparkApplication")
+			Expect(k8sClient.Delete(ctx, app)).To(Succeed())
+
+			By("Deleting the driver pod")
+			driverPod := &corev1.Pod{}
+			Expect(k8sClient.Get(ctx, getDriverNamespacedName(appName, appNamespace), driverPod)).To(Succeed())
+			Expect(k8sClient.Delete(ctx, driverPod)).To(Succeed())
+
+			By("Deleting the web UI service and ingress")
+			service := &corev1.Service{}
+			service.Name, service.Namespace = serviceKey.Name, serviceKey.Namespace
+			Expect(client.IgnoreNotFound(k8sClient.Delete(ctx, service))).To(Succeed())
+			ingress := &networkingv1.Ingress{}
+			ingress.Name, ingress.Namespace = ingressKey.Name, ingressKey.Namespace
+			Expect(client.IgnoreNotFound(k8sClient.Delete(ctx, ingress))).To(Succeed())
+		})
+
+		newReconciler := func() *sparkapplication.Reconciler {
+			return sparkapplication.NewReconciler(
+				nil,
+				k8sClient.Scheme(),
+				k8sClient,
+				record.NewFakeRecorder(10),
+				nil,
+				sparkapplication.Options{
+					Namespaces:       []string{appNamespace},
+					EnableUIService:  true,
+					IngressURLFormat: "{{$appName}}.example.com",
+				},
+			)
+		}
+
+		It("Should bring the service and ingress back to their desired state", func() {
+			app := &v1beta2.SparkApplication{}
+			Expect(k8sClient.Get(ctx, key, app)).To(Succeed())
+
+			By("Creating the drifted web UI service and ingress")
+			Expect(k8sClient.Create(ctx, newService(app))).To(Succeed())
+			Expect(k8sClient.Create(ctx, newIngress(app))).To(Succeed())
+			drifted := &corev1.Service{}
+			Expect(k8sClient.Get(ctx, serviceKey, drifted)).To(Succeed())
+
+			By("Reconciling the running SparkApplication")
+			_, err := newReconciler().Reconcile(ctx, reconcile.Request{NamespacedName: key})
+			Expect(err).NotTo(HaveOccurred())
+
+			service := &corev1.Service{}
+			Expect(k8sClient.Get(ctx, serviceKey, service)).To(Succeed())
+			Expect(service.Spec.Selector).To(Equal(map[string]string{
+				common.LabelSparkAppName: appName,
+				common.LabelSparkRole:    common.SparkRoleDriver,
+			}))
+			Expect(service.Spec.Ports).To(HaveLen(1))
+			Expect(service.Spec.Ports[0].Port).To(Equal(int32(4040)))
+			By("Keeping the cluster IP and node port allocated by the API server")
+			Expect(service.Spec.ClusterIP).To(Equal(drifted.Spec.ClusterIP))
+			Expect(service.Spec.Ports[0].NodePort).To(Equal(drifted.Spec.Ports[0].NodePort))
+			By("Keeping the labels and annotations added by others")
+			Expect(service.Labels).To(HaveKeyWithValue("other-controller", "true"))
+			Expect(service.Annotations).To(HaveKeyWithValue("edited", "true"))
+
+			ingress := &networkingv1.Ingress{}
+			Expect(k8sClient.Get(ctx, ingressKey, ingress)).To(Succeed())
+			Expect(ingress.Spec.DefaultBackend).To(BeNil())
+			Expect(ingress.Spec.Rules).To(HaveLen(1))
+			Expect(ingress.Spec.Rules[0].Host).To(Equal(appName + ".example.com"))
+			Expect(ingress.Spec.Rules[0].HTTP.Paths[0].Backend.Service.Name).To(Equal(serviceKey.Name))
+			Expect(ingress.Annotations).To(HaveKeyWithValue("other-controller", "true"))
+		})
+
+		It("Should leave a service and ingress not owned by the SparkApplication alone", func() {
+			app := &v1beta2.SparkApplication{}
+			Expect(k8sClient.Get(ctx, key, app)).To(Succeed())
+
+			By("Creating a web UI service and ingress owned by something else")
+			otherOwner := metav1.OwnerReference{APIVersion: "v1", Kind: "ConfigMap", Name: "other", UID: "other-uid"}
+			unownedService := newService(app)
+			unownedService.OwnerReferences = []metav1.OwnerReference{otherOwner}
+			Expect(k8sClient.Create(ctx, unownedService)).To(Succeed())
+			unownedIngress := newIngress(app)
+			unownedIngress.OwnerReferences = nil
+			Expect(k8sClient.Create(ctx, unownedIngress)).To(Succeed())
+
+			By("Reconciling the running SparkApplication")
+			_, err := newReconciler().Reconcile(ctx, reconcile.Request{NamespacedName: key})
+			Expect(err).NotTo(HaveOccurred())
+
+			service := &corev1.Service{}
+			Expect(k8sClient.Get(ctx, serviceKey, service)).To(Succeed())
+			Expect(service.Spec.Selector).To(Equal(map[string]string{"app": "edited"}))
+			ingress := &networkingv1.Ingress{}
+			Expect(k8sClient.Get(ctx, ingressKey, ingress)).To(Succeed())
+			Expect(ingress.Spec.DefaultBackend.Service.Name).To(Equal("edited-svc"))
+		})
+	})
+
+	Context("When the status of a SparkApplication is patched", func() {
+		ctx := context.Background()
+		appName := "test-status-patch"
+		appNamespace := "default"
+		key := types.NamespacedName{
+			Name:      appName,
+			Namespace: appNamespace,
+		}
+
+		// newClient returns a client calling the given functions instead of the API server.
+		newClient := func(funcs interceptor.Funcs) client.Client {
+			withWatch, err := client.NewWithWatch(cfg, client.Options{Scheme: k8sClient.Scheme()})
+			Expect(err).NotTo(HaveOccurred())
+			return interceptor.NewClient(withWatch, funcs)
+		}
+
+		BeforeEach(func() {
+			By("Creating a test SparkApplication")
+			app := &v1beta2.SparkApplication{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      appName,
+					Namespace: appNamespace,
+				},
+				Spec: v1beta2.SparkApplicationSpec{
+					MainApplicationFile: util.StringPtr("local:///dummy.jar"),
+				},
+			}
+			v1beta2.SetSparkApplicationDefaults(app)
+			Expect(k8sClient.Create(ctx, app)).To(Succeed())
+
+			GinkgoT().Setenv(common.EnvKubernetesServiceHost, "10.0.0.1")
+			GinkgoT().Setenv(common.EnvKubernetesServicePort, "443")
+		})
+
+		AfterEach(func() {
+			app := &v1beta2.SparkApplication{}
+			Expect(k8sClient.Get(ctx, key, app)).To(Succeed())
+
+			By("Deleting the created test SparkApplication")
+			Expect(k8sClient.Delete(ctx, app)).To(Succeed())
+
+			By("Deleting the driver pod")
+			driverPod := &corev1.Pod{}
+			if err := k8sClient.Get(ctx, getDriverNamespacedName(appName, appNamespace), driverPod); err == nil {
+				Expect(k8sClient.Delete(ctx, driverPod)).To(Succeed())
+			}
+		})
+
+		It("Should not conflict with changes made to the SparkApplication since the reconcile read it", func() {
+			By("Running the SparkApplication with a completed driver pod")
+			driverPod := createDriverPod(appName, appNamespace)
+			Expect(k8sClient.Create(ctx, driverPod)).To(Succeed())
+			driverPod.Status.Phase = corev1.PodSucceeded
+			Expect(k8sClient.Status().Update(ctx, driverPod)).To(Succeed())
+
+			app := &v1beta2.SparkApplication{}
+			Expect(k8sClient.Get(ctx, key, app)).To(Succeed())
+			app.Status.DriverInfo.PodName = driverPod.Name
+			app.Status.AppState.State = v1beta2.ApplicationStateRunning
+			Expect(k8sClient.Status().Update(ctx, app)).To(Succeed())
+
+			By("Labeling the SparkApplication after the reconcile read it")
+			patches := 0
+			c := newClient(interceptor.Funcs{
+				SubResourcePatch: func(ctx context.Context, c client.Client, subResourceName string, obj client.Object, patch client.Patch, opts ...client.SubResourcePatchOption) error {
+					patches++
+					data, err := patch.Data(obj)
+					Expect(err).NotTo(HaveOccurred())
+					Expect(string(data)).NotTo(ContainSubstring("resourceVersion"))
+
+					labeled := &v1beta2.SparkApplication{}
+					Expect(c.Get(ctx, key, labeled)).To(Succeed())
+					labeled.Labels = map[string]string{"patched": strconv.Itoa(patches)}
+					Expect(c.Update(ctx, labeled)).To(Succeed())
+					return c.SubResource(subResourceName).Patch(ctx, obj, patch, opts...)
+				},
+			})
+
+			By("Reconciling the running SparkApplication")
+			reconciler := sparkapplication.NewReconciler(
+				nil,
+				k8sClient.Scheme(),
+				c,
+				record.NewFakeRecorder(3),
+				nil,
+				sparkapplication.Options{Namespaces: []string{appNamespace}},
+			)
+			_, err := reconciler.Reconcile(ctx, reconcile.Request{NamespacedName: key})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(patches).To(Equal(1))
+
+			Expect(k8sClient.Get(ctx, key, app)).To(Succeed())
+			Expect(app.Labels).To(HaveKeyWithValue("patched", "1"))
+			Expect(app.Status.AppState.State).To(Equal(v1beta2.ApplicationStateSucceeding))
+		})
+
+		It("Should retry a conflicting status patch without submitting the SparkApplication again", func() {
+			var hookCalls atomic.Int32
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				hookCalls.Add(1)
+				w.WriteHeader(http.StatusOK)
+			}))
+			DeferCleanup(server.Close)
+
+			By("Failing the first status patch with a conflict")
+			patches := 0
+			c := newClient(interceptor.Funcs{
+				SubResourcePatch: func(ctx context.Context, c client.Client, subResourceName string, obj client.Object, patch client.Patch, opts ...client.SubResourcePatchOption) error {
+					patches++
+					if patches == 1 {
+						return errors.NewConflict(v1beta2.Resource("sparkapplications"), appName, fmt.Errorf("injected conflict"))
+					}
+					return c.SubResource(subResourceName).Patch(ctx, obj, patch, opts...)
+				},
+			})
+
+			By("Reconciling the new SparkApplication")
+			reconciler := sparkapplication.NewReconciler(
+				nil,
+				k8sClient.Scheme(),
+				c,
+				record.NewFakeRecorder(10),
+				nil,
+				sparkapplication.Options{
+					Namespaces:         []string{appNamespace},
+					PreSubmissionHooks: []sparkapplication.Hook{{URL: server.URL}},
+				},
+			)
+			_, err := reconciler.Reconcile(ctx, reconcile.Request{NamespacedName: key})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(patches).To(Equal(2))
+			Expect(hookCalls.Load()).To(Equal(int32(1)))
+
+			app := &v1beta2.SparkApplication{}
+			Expect(k8sClient.Get(ctx, key, app)).To(Succeed())
+			Expect(app.Status.SubmissionAttempts).To(Equal(int32(1)))
+			Expect(app.Status.AppState.State).NotTo(Equal(v1beta2.ApplicationStateNew))
+		})
+	})
+
+	Context("When submitting a SparkApplication with service account impersonation", func() {
+		ctx := context.Background()
+		appName := "test-impersonation"
+		appNamespace := "default"
+		serviceAccountName := "spark-submitter"
+		key := types.NamespacedName{
+			Name:      appName,
+			Namespace: appNamespace,
+		}
+
+		BeforeEach(func() {
+			By("Creating the impersonated service account")
+			serviceAccount := &corev1.ServiceAccount{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      serviceAccountName,
+					Namespace: appNamespace,
+				},
+			}
+			Expect(k8sClient.Create(ctx, serviceAccount)).To(Succeed())
+
+			By("Creating a test SparkApplication with Prometheus monitoring")
+			app := &v1beta2.SparkApplication{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      appName,
+					Namespace: appNamespace,
+				},
+				Spec: v1beta2.SparkApplicationSpec{
+					MainApplicationFile: util.StringPtr("local:///dummy.jar"),
+					Monitoring: &v1beta2.MonitoringSpec{
+						ExposeDriverMetrics: true,
+						Prometheus: &v1beta2.PrometheusSpec{
+							JmxExporterJar: "/prometheus/jmx_prometheus_javaagent.jar",
+						},
+					},
+				},
+			}
+			v1beta2.SetSparkApplicationDefaults(app)
+			Expect(k8sClient.Create(ctx, app)).To(Succeed())
+		})
+
+		AfterEach(func() {
+			app := &v1beta2.SparkApplication{}
+			Expect(k8sClient.Get(ctx, key, app)).To(Succeed())
+
+			By("Deleting the created test SparkApplication")
+			Expect(k8sClient.Delete(ctx, app)).To(Succeed())
+
+			By("Deleting the created Prometheus ConfigMap")
+			configMap := &corev1.ConfigMap{}
+			configMapKey := types.NamespacedName{Name: util.GetPrometheusConfigMapName(app), Namespace: appNamespace}
+			if err := k8sClient.Get(ctx, configMapKey, configMap); err == nil {
+				Expect(k8sClient.Delete(ctx, configMap)).To(Succeed())
+			}
+
+			By("Deleting the RBAC resources of the impersonated service account")
+			role := &rbacv1.Role{}
+			if err := k8sClient.Get(ctx, types.NamespacedName{Name: serviceAccountName, Namespace: appNamespace}, role); err == nil {
+				Expect(k8sClient.Delete(ctx, role)).To(Succeed())
+			}
+			roleBinding := &rbacv1.RoleBinding{}
+			if err := k8sClient.Get(ctx, types.NamespacedName{Name: serviceAccountName, Namespace: appNamespace}, roleBinding); err == nil {
+				Expect(k8sClient.Delete(ctx, roleBinding)).To(Succeed())
+			}
+
+			By("Deleting the impersonated service account")
+			serviceAccount := &corev1.ServiceAccount{}
+			Expect(k8sClient.Get(ctx, types.NamespacedName{Name: serviceAccountName, Namespace: appNamespace}, serviceAccount)).To(Succeed())
+			Expect(k8sClient.Delete(ctx, serviceAccount)).To(Succeed())
+		})
+
+		newReconciler := func() *sparkapplication.Reconciler {
+			mgr, err := ctrl.NewManager(cfg, ctrl.Options{
+				Scheme:  k8sClient.Scheme(),
+				Metrics: metricsserver.Options{BindAddress: "0"},
+			})
+			Expect(err).NotTo(HaveOccurred())
+			return sparkapplication.NewReconciler(
+				mgr,
+				k8sClient.Scheme(),
+				k8sClient,
+				record.NewFakeRecorder(3),
+				nil,
+				sparkapplication.Options{Namespaces: []string{appNamespace}, Submission: sparkapplication.SubmissionOptions{ImpersonateServiceAccount: serviceAccountName}},
+			)
+		}
+
+		It("Should create driver resources on behalf of the impersonated service account", func() {
+			By("Reconciling the new SparkApplication")
+			_, err := newReconciler().Reconcile(ctx, reconcile.Request{NamespacedName: key})
+			Expect(err).NotTo(HaveOccurred())
+
+			By("Checking that the submission was denied to the impersonated service account")
+			app := &v1beta2.SparkApplication{}
+			Expect(k8sClient.Get(ctx, key, app)).To(Succeed())
+			Expect(app.Status.AppState.State).To(Equal(v1beta2.ApplicationStateFailedSubmission))
+			Expect(app.Status.AppState.ErrorMessage).To(ContainSubstring(
+				fmt.Sprintf("system:serviceaccount:%s:%s", appNamespace, serviceAccountName)))
+
+			configMap := &corev1.ConfigMap{}
+			configMapKey := types.NamespacedName{Name: util.GetPrometheusConfigMapName(app), Namespace: appNamespace}
+			Expect(errors.IsNotFound(k8sClient.Get(ctx, configMapKey, configMap))).To(BeTrue())
+		})
+
+		It("Should authenticate spark-submit with a token of the impersonated service account", func() {
+			By("Granting the impersonated service account access to ConfigMaps")
+			role := &rbacv1.Role{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      serviceAccountName,
+					Namespace: appNamespace,
+				},
+				Rules: []rbacv1.PolicyRule{
+					{
+						APIGroups: []string{""},
+						Resources: []string{"configmaps"},
+						Verbs:     []string{"get", "create", "update"},
+					},
+				},
+			}
+			Expect(k8sClient.Create(ctx, role)).To(Succeed())
+			roleBinding := &rbacv1.RoleBinding{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      serviceAccountName,
+					Namespace: appNamespace,
+				},
+				Subjects: []rbacv1.Subject{
+					{
+						Kind:      rbacv1.ServiceAccountKind,
+						Name:      serviceAccountName,
+						Namespace: appNamespace,
+					},
+				},
+				RoleRef: rbacv1.RoleRef{
+					APIGroup: rbacv1.GroupName,
+					Kind:     "Role",
+					Name:     serviceAccountName,
+				},
+			}
+			Expect(k8sClient.Create(ctx, roleBinding)).To(Succeed())
+
+			By("Reconciling the new SparkApplication")
+			GinkgoT().Setenv("KUBERNETES_SERVICE_HOST", "localhost")
+			GinkgoT().Setenv("KUBERNETES_SERVICE_PORT", "443")
+			_, err := newReconciler().Reconcile(ctx, reconcile.Request{NamespacedName: key})
+			Expect(err).NotTo(HaveOccurred())
+
+			By("Checking that the driver resources were created and spark-submit was run with the token")
+			app := &v1beta2.SparkApplication{}
+			Expect(k8sClient.Get(ctx, key, app)).To(Succeed())
+			configMap := &corev1.ConfigMap{}
+			configMapKey := types.NamespacedName{Name: util.GetPrometheusConfigMapName(app), Namespace: appNamespace}
+			Expect(k8sClient.Get(ctx, configMapKey, configMap)).To(Succeed())
+			Expect(app.Status.AppState.ErrorMessage).To(ContainSubstring("failed to run spark-submit"))
+			Expect(app.Status.SparkSubmitCommand).To(ContainSubstring(common.SparkKubernetesAuthenticateSubmissionOAuthTokenFile))
+
+			By("Checking that the token file was cleaned up")
+			_, err = os.Stat(fmt.Sprintf("/tmp/spark/%s", app.Status.SubmissionID))
+			Expect(os.IsNotExist(err)).To(BeTrue())
+		})
+	})
+	Context("When reconciling a running SparkApplication whose Prometheus ConfigMap was modified", func() {
+		ctx := context.Background()
+		appName := "test-prometheus-configmap"
+		appNamespace := "default"
+		key := types.NamespacedName{
+			Name:      appName,
+			Namespace: appNamespace,
+		}
+
+		BeforeEach(func() {
+			By("Creating a test SparkApplication with Prometheus monitoring")
+			app := &v1beta2.SparkApplication{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      appName,
+					Namespace: appNamespace,
+				},
+				Spec: v1beta2.SparkApplicationSpec{
+					MainApplicationFile: util.StringPtr("local:///dummy.jar"),
+					Monitoring: &v1beta2.MonitoringSpec{
+						ExposeDriverMetrics: true,
+						Prometheus: &v1beta2.PrometheusSpec{
+							JmxExporterJar: "/prometheus/jmx_prometheus_javaagent.jar",
+						},
+					},
+				},
+			}
+			v1beta2.SetSparkApplicationDefaults(app)
+			Expect(k8sClient.Create(ctx, app)).To(Succeed())
+
+			driverPod := createDriverPod(appName, appNamespace)
+			Expect(k8sClient.Create(ctx, driverPod)).To(Succeed())
+			driverPod.Status.Phase = corev1.PodRunning
+			Expect(k8sClient.Status().Update(ctx, driverPod)).To(Succeed())
+
+			app.Status.DriverInfo.PodName = driverPod.Name
+			app.Status.AppState.State = v1beta2.ApplicationStateRunning
+			app.Status.ObservedGeneration = app.Generation
+			Expect(k8sClient.Status().Update(ctx, app)).To(Succeed())
+
+			By("Creating a modified Prometheus ConfigMap without labels and owner references")
+			configMap := &corev1.ConfigMap{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      util.GetPrometheusConfigMapName(app),
+					Namespace: appNamespace,
+				},
+				Data: map[string]string{common.PrometheusConfigKey: "stale"},
+			}
+			Expect(k8sClient.Create(ctx, configMap)).To(Succeed())
+		})
+
+		AfterEach(func() {
+			app := &v1beta2.SparkApplication{}
+			Expect(k8sClient.Get(ctx, key, app)).To(Succeed())
+
+			By("Deleting the Prometheus ConfigMap")
+			configMap := &corev1.ConfigMap{}
+			Expect(k8sClient.Get(ctx, types.NamespacedName{Name: util.GetPrometheusConfigMapName(app), Namespace: appNamespace}, configMap)).To(Succeed())
+			Expect(k8sClient.Delete(ctx, configMap)).To(Succeed())
+
+			By("Deleting the created test SparkApplication")
+			Expect(k8sClient.Delete(ctx, app)).To(Succeed())
+
+			By("Deleting the driver pod")
+			driverPod := &corev1.Pod{}
+			Expect(k8sClient.Get(ctx, getDriverNamespacedName(appName, appNamespace), driverPod)).To(Succeed())
+			Expect(k8sClient.Delete(ctx, driverPod)).To(Succeed())
+		})
+
+		It("Should restore the data, label and owner reference of the Prometheus ConfigMap", func() {
+			By("Reconciling the running SparkApplication")
+			reconciler := sparkapplication.NewReconciler(
+				nil,
+				k8sClient.Scheme(),
+				k8sClient,
+				record.NewFakeRecorder(3),
+				nil,
+				sparkapplication.Options{Namespaces: []string{appNamespace}},
+			)
+			_, err := reconciler.Reconcile(ctx, reconcile.Request{NamespacedName: key})
+			Expect(err).NotTo(HaveOccurred())
+
+			app := &v1beta2.SparkApplication{}
+			Expect(k8sClient.Get(ctx, key, app)).To(Succeed())
+			configMap := &corev1.ConfigMap{}
+			Expect(k8sClient.Get(ctx, types.NamespacedName{Name: util.GetPrometheusConfigMapName(app), Namespace: appNamespace}, configMap)).To(Succeed())
+			Expect(configMap.Data).To(HaveKeyWithValue(common.PrometheusConfigKey, common.DefaultPrometheusConfiguration))
+			Expect(configMap.Data).To(HaveKeyWithValue(common.MetricsPropertiesKey, common.DefaultMetricsProperties))
+			Expect(configMap.Labels).To(HaveKeyWithValue(common.LabelSparkAppName, appName))
+			Expect(configMap.OwnerReferences).To(HaveLen(1))
+			Expect(configMap.OwnerReferences[0].UID).To(Equal(app.UID))
+		})
+	})
+
+	Context("When collecting the Prometheus ConfigMaps of deleted SparkApplications", func() {
+		ctx := context.Background()
+		appName := "test-prometheus-configmap-gc"
+		appNamespace := "prometheus-configmap-gc"
+		key := types.NamespacedName{
+			Name:      appName,
+			Namespace: appNamespace,
+		}
+
+		// newPrometheusConfigMap returns a Prometheus ConfigMap of the SparkApplication with the given name.
+		newPrometheusConfigMap := func(appName string, namespace string) *corev1.ConfigMap {
+			app := &v1beta2.SparkApplication{ObjectMeta: metav1.ObjectMeta{Name: appName}}
+			return &corev1.ConfigMap{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      util.GetPrometheusConfigMapName(app),
+					Namespace: namespace,
+					Labels:    map[string]string{common.LabelSparkAppName: appName},
+				},
+				Data: map[string]string{common.PrometheusConfigKey: "stale"},
+			}
+		}
+
+		owned := newPrometheusConfigMap(appName, appNamespace)
+		orphaned := newPrometheusConfigMap("deleted-app", appNamespace)
+		unrelated := newPrometheusConfigMap("deleted-app", appNamespace)
+		unrelated.Name = "deleted-app-settings"
+		otherNamespace := newPrometheusConfigMap("deleted-app", "default")
+
+		BeforeEach(func() {
+			By("Creating the namespace of the test SparkApplication")
+			namespace := &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: appNamespace}}
+			if err := k8sClient.Create(ctx, namespace); err != nil && !errors.IsAlreadyExists(err) {
+				Expect(err).NotTo(HaveOccurred())
+			}
+
+			By("Creating a completed test SparkApplication")
+			app := &v1beta2.SparkApplication{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      appName,
+					Namespace: appNamespace,
+				},
+				Spec: v1beta2.SparkApplicationSpec{
+					MainApplicationFile: util.StringPtr("local:///dummy.jar"),
+				},
+			}
+			v1beta2.SetSparkApplicationDefaults(app)
+			Expect(k8sClient.Create(ctx, app)).To(Succeed())
+			app.Status.AppState.State = v1beta2.ApplicationStateCompleted
+			Expect(k8sClient.Status().Update(ctx, app)).To(Succeed())
+
+			By("Creating the Prometheus ConfigMaps")
+			for _, configMap := range []*corev1.ConfigMap{owned, orphaned, unrelated, otherNamespace} {
+				Expect(k8sClient.Create(ctx, configMap.DeepCopy())).To(Succeed())
+			}
+		})
+
+		AfterEach(func() {
+			By("Deleting the created test SparkApplication")
+			app := &v1beta2.SparkApplication{}
+			Expect(k8sClient.Get(ctx, key, app)).To(Succeed())
+			app.Finalizers = nil
+			Expect(k8sClient.Update(ctx, app)).To(Succeed())
+			Expect(k8sClient.Delete(ctx, app)).To(Succeed())
+
+			By("Deleting the remaining Prometheus ConfigMaps")
+			for _, configMap := range []*corev1.ConfigMap{owned, orphaned, unrelated, otherNamespace} {
+				Expect(client.IgnoreNotFound(k8sClient.Delete(ctx, configMap.DeepCopy()))).To(Succeed())
+			}
+		})
+
+		It("Should delete the Prometheus ConfigMaps of deleted SparkApplications in the watched namespaces", func() {
+			By("Starting a manager running the collector")
+			mgr, err := ctrl.NewManager(cfg, ctrl.Options{
+				Scheme:  k8sClient.Scheme(),
+				Metrics: metricsserver.Options{BindAddress: "0"},
+			})
+			Expect(err).NotTo(HaveOccurred())
+			reconciler := sparkapplication.NewReconciler(
+				mgr,
+				k8sClient.Scheme(),
+				mgr.GetClient(),
+				record.NewFakeRecorder(100),
+				nil,
+				sparkapplication.Options{Namespaces: []string{appNamespace}, PrometheusConfigMapGCInterval: 100 * time.Millisecond},
+			)
+			Expect(reconciler.SetupWithManager(mgr, controller.Options{SkipNameValidation: ptr.To(true)})).To(Succeed())
+			mgrCtx, cancel := context.WithCancel(ctx)
+			defer cancel()
+			go func() {
+				defer GinkgoRecover()
+				Expect(mgr.Start(mgrCtx)).To(Succeed())
+			}()
+
+			By("Waiting for the orphaned Prometheus ConfigMap to be deleted")
+			Eventually(func() bool {
+				err := k8sClient.Get(ctx, client.ObjectKeyFromObject(orphaned), &corev1.ConfigMap{})
+				return errors.IsNotFound(err)
+			}).WithTimeout(10 * time.Second).Should(BeTrue())
+
+			By("Checking that the other ConfigMaps were kept")
+			for _, configMap := range []*corev1.ConfigMap{owned, unrelated, otherNamespace} {
+				Expect(k8sClient.Get(ctx, client.ObjectKeyFromObject(configMap), &corev1.ConfigMap{})).To(Succeed())
+			}
+		})
+	})
+	Context("When submitting a SparkApplication with the native submission engine", func() {
+		ctx := context.Background()
+		appName := "test-native-submission"
+		appNamespace := "default"
+		key := types.NamespacedName{
+			Name:      appName,
+			Namespace: appNamespace,
+		}
+
+		BeforeEach(func() {
+			By("Creating a test SparkApplication")
+			app := &v1beta2.SparkApplication{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      appName,
+					Namespace: appNamespace,
+				},
+				Spec: v1beta2.SparkApplicationSpec{
+					Image:               util.StringPtr("spark:3.5.3"),
+					MainClass:           util.StringPtr("org.apache.spark.examples.SparkPi"),
+					MainApplicationFile: util.StringPtr("local:///opt/spark/examples/jars/spark-examples.jar"),
+					Arguments:           []string{"1000"},
+				},
+			}
+			v1beta2.SetSparkApplicationDefaults(app)
+			Expect(k8sClient.Create(ctx, app)).To(Succeed())
+		})
+
+		AfterEach(func() {
+			app := &v1beta2.SparkApplication{}
+			Expect(k8sClient.Get(ctx, key, app)).To(Succeed())
+
+			By("Deleting the created test SparkApplication")
+			Expect(k8sClient.Delete(ctx, app)).To(Succeed())
+
+			By("Deleting the driver resources")
+			driverPod := &corev1.Pod{}
+			Expect(k8sClient.Get(ctx, getDriverNamespacedName(appName, appNamespace), driverPod)).To(Succeed())
+			selector := client.MatchingLabels{common.LabelSparkApplicationSelector: driverPod.Labels[common.LabelSparkApplicationSelector]}
+			Expect(k8sClient.DeleteAllOf(ctx, &corev1.Service{}, client.InNamespace(appNamespace), selector)).To(Succeed())
+			Expect(k8sClient.DeleteAllOf(ctx, &corev1.ConfigMap{}, client.InNamespace(appNamespace), selector)).To(Succeed())
+			Expect(k8sClient.Delete(ctx, driverPod)).To(Succeed())
+		})
+
+		It("Should create the driver pod and its Service and ConfigMap owned by it", func() {
+			By("Reconciling the new SparkApplication")
+			GinkgoT().Setenv(common.EnvKubernetesServiceHost, "10.0.0.1")
+			GinkgoT().Setenv(common.EnvKubernetesServicePort, "443")
+			reconciler := sparkapplication.NewReconciler(
+				nil,
+				k8sClient.Scheme(),
+				k8sClient,
+				record.NewFakeRecorder(3),
+				nil,
+				sparkapplication.Options{Namespaces: []string{appNamespace}, Submission: sparkapplication.SubmissionOptions{DefaultEngine: v1beta2.SubmissionEngineNative}},
+			)
+			_, err := reconciler.Reconcile(ctx, reconcile.Request{NamespacedName: key})
+			Expect(err).NotTo(HaveOccurred())
+
+			app := &v1beta2.SparkApplication{}
+			Expect(k8sClient.Get(ctx, key, app)).To(Succeed())
+			Expect(app.Status.AppState.State).To(Equal(v1beta2.ApplicationStateSubmitted))
+
+			By("Checking the driver resources")
+			driverPod := &corev1.Pod{}
+			Expect(k8sClient.Get(ctx, getDriverNamespacedName(appName, appNamespace), driverPod)).To(Succeed())
+			appID := driverPod.Labels[common.LabelSparkApplicationSelector]
+			Expect(appID).NotTo(BeEmpty())
+
+			selector := client.MatchingLabels{common.LabelSparkApplicationSelector: appID}
+			services := &corev1.ServiceList{}
+			Expect(k8sClient.List(ctx, services, client.InNamespace(appNamespace), selector)).To(Succeed())
+			Expect(services.Items).To(HaveLen(1))
+			configMaps := &corev1.ConfigMapList{}
+			Expect(k8sClient.List(ctx, configMaps, client.InNamespace(appNamespace), selector)).To(Succeed())
+			Expect(configMaps.Items).To(HaveLen(1))
+			for _, obj := range []client.Object{&services.Items[0], &configMaps.Items[0]} {
+				Expect(obj.GetOwnerReferences()).To(HaveLen(1))
+				Expect(obj.GetOwnerReferences()[0].Kind).To(Equal("Pod"))
+				Expect(obj.GetOwnerReferences()[0].UID).To(Equal(driverPod.UID))
+			}
+
+			By("Submitting the SparkApplication again")
+			app.Status.AppState.State = v1beta2.ApplicationStateNew
+			Expect(k8sClient.Status().Update(ctx, app)).To(Succeed())
+			_, err = reconciler.Reconcile(ctx, reconcile.Request{NamespacedName: key})
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(k8sClient.Get(ctx, key, app)).To(Succeed())
+			Expect(app.Status.AppState.State).To(Equal(v1beta2.ApplicationStateFailedSubmission))
+			Expect(app.Status.AppState.ErrorMessage).To(ContainSubstring("driver pod already exist"))
+		})
+	})
+	Context("When reconciling a running SparkApplication with driver progress scraping", func() {
+		ctx := context.Background()
+		appName := "test-driver-progress"
+		appNamespace := "default"
+		key := types.NamespacedName{
+			Name:      appName,
+			Namespace: appNamespace,
+		}
+
+		var server *httptest.Server
+		var requests atomic.Int32
+
+		BeforeEach(func() {
+			By("Starting a server serving the REST API and metrics of the driver")
+			requests.Store(0)
+			mux := http.NewServeMux()
+			mux.HandleFunc("/api/v1/applications/spark-123/stages", func(w http.ResponseWriter, r *http.Request) {
+				requests.Add(1)
+				switch r.URL.Query().Get("status") {
+				case "active":
+					fmt.Fprint(w, `[{"stageId": 3}, {"stageId": 4}]`)
+				case "pending":
+					fmt.Fprint(w, `[{"stageId": 5}]`)
+				default:
+					w.WriteHeader(http.StatusBadRequest)
+				}
+			})
+			mux.HandleFunc("/api/v1/applications/spark-123/executors", func(w http.ResponseWriter, _ *http.Request) {
+				fmt.Fprint(w, `[{"id": "driver", "isActive": true}, {"id": "1", "isActive": true}, {"id": "2", "isActive": false}]`)
+			})
+			mux.HandleFunc("/metrics/json/", func(w http.ResponseWriter, _ *http.Request) {
+				fmt.Fprint(w, `{"gauges": {"spark-123.driver.ExecutorAllocationManager.executors.numberTargetExecutors": {"value": 8}}}`)
+			})
+			server = httptest.NewServer(mux)
+			host, port, err := net.SplitHostPort(server.Listener.Addr().String())
+			Expect(err).NotTo(HaveOccurred())
+			uiPort, err := strconv.Atoi(port)
+			Expect(err).NotTo(HaveOccurred())
+
+			By("Creating a test SparkApplication")
+			app := &v1beta2.SparkApplication{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      appName,
+					Namespace: appNamespace,
+				},
+				Spec: v1beta2.SparkApplicationSpec{
+					MainApplicationFile: util.StringPtr("local:///dummy.jar"),
+					NetworkPorts:        &v1beta2.NetworkPorts{UIPort: ptr.To(int32(uiPort))},
+				},
+			}
+			v1beta2.SetSparkApplicationDefaults(app)
+			Expect(k8sClient.Create(ctx, app)).To(Succeed())
+
+			driverPod := createDriverPod(appName, appNamespace)
+			driverPod.Labels[common.LabelSparkApplicationSelector] = "spark-123"
+			Expect(k8sClient.Create(ctx, driverPod)).To(Succeed())
+			driverPod.Status.Phase = corev1.PodRunning
+			driverPod.Status.PodIP = host
+			Expect(k8sClient.Status().Update(ctx, driverPod)).To(Succeed())
+
+			app.Status.DriverInfo.PodName = driverPod.Name
+			app.Status.AppState.State = v1beta2.ApplicationStateRunning
+			app.Status.ObservedGeneration = app.Generation
+			Expect(k8sClient.Status().Update(ctx, app)).To(Succeed())
+		})
+
+		AfterEach(func() {
+			server.Close()
+
+			app := &v1beta2.SparkApplication{}
+			Expect(k8sClient.Get(ctx, key, app)).To(Succeed())
+
+			By("Deleting the created test SparkApplication")
+			Expect(k8sClient.Delete(ctx, app)).To(Succeed())
+
+			By("Deleting the driver pod")
+			driverPod := &corev1.Pod{}
+			Expect(k8sClient.Get(ctx, getDriverNamespacedName(appName, appNamespace), driverPod)).To(Succeed())
+			Expect(k8sClient.Delete(ctx, driverPod)).To(Succeed())
+		})
+
+		It("Should record the progress scraped from the driver at most once per scrape interval", func() {
+			reconciler := sparkapplication.NewReconciler(
+				nil,
+				k8sClient.Scheme(),
+				k8sClient,
+				record.NewFakeRecorder(3),
+				nil,
+				sparkapplication.Options{Namespaces: []string{appNamespace}, DriverProgressScrapeInterval: time.Minute},
+			)
+
+			By("Reconciling the running SparkApplication")
+			result, err := reconciler.Reconcile(ctx, reconcile.Request{NamespacedName: key})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(result.RequeueAfter).To(Equal(time.Minute))
+
+			app := &v1beta2.SparkApplication{}
+			Expect(k8sClient.Get(ctx, key, app)).To(Succeed())
+			Expect(app.Status.SparkApplicationID).To(Equal("spark-123"))
+			Expect(app.Status.DriverProgress).NotTo(BeNil())
+			Expect(app.Status.DriverProgress.ActiveStages).To(Equal(int32(2)))
+			Expect(app.Status.DriverProgress.PendingStages).To(Equal(int32(1)))
+			Expect(app.Status.DriverProgress.ActiveExecutors).To(Equal(int32(1)))
+			Expect(app.Status.DriverProgress.TargetExecutors).To(Equal(ptr.To[int32](8)))
+			scraped := requests.Load()
+			Expect(scraped).To(BeNumerically(">", 0))
+
+			By("Reconciling the running SparkApplication again within the scrape interval")
+			_, err = reconciler.Reconcile(ctx, reconcile.Request{NamespacedName: key})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(requests.Load()).To(Equal(scraped))
+		})
+	})
+	Context("When calling the hooks of a SparkApplication", func() {
+		ctx := context.Background()
+		appName := "test-hooks"
+		appNamespace := "default"
+		key := types.NamespacedName{
+			Name:      appName,
+			Namespace: appNamespace,
+		}
+
+		// hookRequest is the payload the hooks are called with.
+		type hookRequest struct {
+			Phase            sparkapplication.HookPhase `json:"phase"`
+			SparkApplication *v1beta2.SparkApplication  `json:"sparkApplication"`
+		}
+
+		// newHookServer returns a server recording the hook requests to the returned channel and replying with the
+		// given status. If key is not empty, the server replies with the application with the given Spark property.
+		newHookServer := func(status int, key string, value string) (*httptest.Server, <-chan *hookRequest) {
+			requests := make(chan *hookRequest, 10)
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				defer GinkgoRecover()
+				request := &hookRequest{}
+				Expect(json.NewDecoder(r.Body).Decode(request)).To(Succeed())
+				requests <- request
+
+				w.WriteHeader(status)
+				if key == "" {
+					return
+				}
+				app := request.SparkApplication.DeepCopy()
+				if app.Spec.SparkConf == nil {
+					app.Spec.SparkConf = map[string]string{}
+				}
+				app.Spec.SparkConf[key] = value
+				Expect(json.NewEncoder(w).Encode(map[string]any{"sparkApplication": app})).To(Succeed())
+			}))
+			DeferCleanup(server.Close)
+			return server, requests
+		}
+
+		newReconciler := func(options sparkapplication.Options) *sparkapplication.Reconciler {
+			options.Namespaces = []string{appNamespace}
+			return sparkapplication.NewReconciler(
+				nil,
+				k8sClient.Scheme(),
+				k8sClient,
+				record.NewFakeRecorder(3),
+				nil,
+				options,
+			)
+		}
+
+		BeforeEach(func() {
+			By("Creating a test SparkApplication")
+			app := &v1beta2.SparkApplication{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      appName,
+					Namespace: appNamespace,
+				},
+				Spec: v1beta2.SparkApplicationSpec{
+					MainApplicationFile: util.StringPtr("local:///dummy.jar"),
+				},
+			}
+			v1beta2.SetSparkApplicationDefaults(app)
+			Expect(k8sClient.Create(ctx, app)).To(Succeed())
+
+			GinkgoT().Setenv(common.EnvKubernetesServiceHost, "10.0.0.1")
+			GinkgoT().Setenv(common.EnvKubernetesServicePort, "443")
+		})
+
+		AfterEach(func() {
+			app := &v1beta2.SparkApplication{}
+			Expect(k8sClient.Get(ctx, key, app)).To(Succeed())
+
+			By("Deleting the created test SparkApplication")
+			Expect(k8sClient.Delete(ctx, app)).To(Succeed())
+		})
+
+		It("Should submit the specs returned by the pre-submission hooks in order", func() {
+			first, _ := newHookServer(http.StatusOK, "spark.first", "1")
+			second, requests := newHookServer(http.StatusOK, "spark.second", "2")
+			reconciler := newReconciler(sparkapplication.Options{
+				PreSubmissionHooks: []sparkapplication.Hook{{URL: first.URL}, {URL: second.URL}},
+			})
+
+			By("Reconciling the new SparkApplication")
+			_, err := reconciler.Reconcile(ctx, reconcile.Request{NamespacedName: key})
+			Expect(err).NotTo(HaveOccurred())
+
+			// The second hook is called with the spec returned by the first one.
+			request := <-requests
+			Expect(request.Phase).To(Equal(sparkapplication.HookPhasePreSubmission))
+			Expect(request.SparkApplication.Spec.SparkConf).To(HaveKeyWithValue("spark.first", "1"))
+
+			app := &v1beta2.SparkApplication{}
+			Expect(k8sClient.Get(ctx, key, app)).To(Succeed())
+			Expect(app.Status.SparkSubmitCommand).To(ContainSubstring("spark.first=1"))
+			Expect(app.Status.SparkSubmitCommand).To(ContainSubstring("spark.second=2"))
+		})
+
+		It("Should fail the submission if a pre-submission hook with the Fail policy fails", func() {
+			failing, _ := newHookServer(http.StatusBadRequest, "", "")
+			next, requests := newHookServer(http.StatusOK, "spark.next", "1")
+			reconciler := newReconciler(sparkapplication.Options{
+				PreSubmissionHooks: []sparkapplication.Hook{
+					{URL: failing.URL, FailurePolicy: sparkapplication.HookFailurePolicyFail},
+					{URL: next.URL},
+				},
+			})
+
+			By("Reconciling the new SparkApplication")
+			_, err := reconciler.Reconcile(ctx, reconcile.Request{NamespacedName: key})
+			Expect(err).NotTo(HaveOccurred())
+
+			app := &v1beta2.SparkApplication{}
+			Expect(k8sClient.Get(ctx, key, app)).To(Succeed())
+			Expect(app.Status.AppState.State).To(Equal(v1beta2.ApplicationStateFailedSubmission))
+			Expect(app.Status.AppState.ErrorMessage).To(ContainSubstring("failed to run pre-submission hooks"))
+			Expect(requests).To(BeEmpty())
+		})
+
+		It("Should ignore failed pre-submission hooks with the Ignore policy", func() {
+			failing, _ := newHookServer(http.StatusBadRequest, "", "")
+			next, _ := newHookServer(http.StatusOK, "spark.next", "1")
+			reconciler := newReconciler(sparkapplication.Options{
+				PreSubmissionHooks: []sparkapplication.Hook{
+					{URL: failing.URL, FailurePolicy: sparkapplication.HookFailurePolicyIgnore},
+					{URL: next.URL},
+				},
+			})
+
+			By("Reconciling the new SparkApplication")
+			_, err := reconciler.Reconcile(ctx, reconcile.Request{NamespacedName: key})
+			Expect(err).NotTo(HaveOccurred())
+
+			app := &v1beta2.SparkApplication{}
+			Expect(k8sClient.Get(ctx, key, app)).To(Succeed())
+			Expect(app.Status.AppState.ErrorMessage).NotTo(ContainSubstring("pre-submission hooks"))
+			Expect(app.Status.SparkSubmitCommand).To(ContainSubstring("spark.next=1"))
+		})
+
+		It("Should enforce the Spark conf policy on the specs returned by the pre-submission hooks", func() {
+			server, _ := newHookServer(http.StatusOK, "spark.eventLog.enabled", "false")
+			reconciler := newReconciler(sparkapplication.Options{
+				PreSubmissionHooks: []sparkapplication.Hook{{URL: server.URL}},
+				SparkConfPolicy:    &webhook.SparkConfPolicy{Enforced: map[string]string{"spark.eventLog.enabled": "true"}},
+			})
+
+			By("Reconciling the new SparkApplication")
+			_, err := reconciler.Reconcile(ctx, reconcile.Request{NamespacedName: key})
+			Expect(err).NotTo(HaveOccurred())
+
+			app := &v1beta2.SparkApplication{}
+			Expect(k8sClient.Get(ctx, key, app)).To(Succeed())
+			Expect(app.Status.SparkSubmitCommand).To(ContainSubstring("spark.eventLog.enabled=true"))
+			Expect(app.Status.SparkSubmitCommand).NotTo(ContainSubstring("spark.eventLog.enabled=false"))
+		})
+
+		It("Should fail the submission if a pre-submission hook returns an invalid spec", func() {
+			server, _ := newHookServer(http.StatusOK, "spark.authenticate", "false")
+			reconciler := newReconciler(sparkapplication.Options{
+				PreSubmissionHooks: []sparkapplication.Hook{{URL: server.URL, FailurePolicy: sparkapplication.HookFailurePolicyIgnore}},
+				SparkConfPolicy:    &webhook.SparkConfPolicy{Forbidden: []string{"spark.authenticate"}},
+			})
+
+			By("Reconciling the new SparkApplication")
+			_, err := reconciler.Reconcile(ctx, reconcile.Request{NamespacedName: key})
+			Expect(err).NotTo(HaveOccurred())
+
+			app := &v1beta2.SparkApplication{}
+			Expect(k8sClient.Get(ctx, key, app)).To(Succeed())
+			Expect(app.Status.AppState.State).To(Equal(v1beta2.ApplicationStateFailedSubmission))
+			Expect(app.Status.AppState.ErrorMessage).To(ContainSubstring("invalid spec returned by pre-submission hook " + server.URL))
+			Expect(app.Status.AppState.ErrorMessage).To(ContainSubstring("spark.authenticate"))
+		})
+
+		It("Should call the post-completion hooks once the SparkApplication completed", func() {
+			failing, _ := newHookServer(http.StatusInternalServerError, "", "")
+			server, requests := newHookServer(http.StatusOK, "spark.ignored", "1")
+			recorder := record.NewFakeRecorder(10)
+			reconciler := sparkapplication.NewReconciler(
+				nil,
+				k8sClient.Scheme(),
+				k8sClient,
+				recorder,
+				nil,
+				sparkapplication.Options{
+					Namespaces:          []string{appNamespace},
+					PostCompletionHooks: []sparkapplication.Hook{{URL: failing.URL}, {URL: server.URL}},
+				},
+			)
+
+			By("Setting the SparkApplication state to Succeeding")
+			app := &v1beta2.SparkApplication{}
+			Expect(k8sClient.Get(ctx, key, app)).To(Succeed())
+			app.Status.AppState.State = v1beta2.ApplicationStateSucceeding
+			Expect(k8sClient.Status().Update(ctx, app)).To(Succeed())
+
+			By("Reconciling the succeeding SparkApplication")
+			_, err := reconciler.Reconcile(ctx, reconcile.Request{NamespacedName: key})
+			Expect(err).NotTo(HaveOccurred())
+
+			request := <-requests
+			Expect(request.Phase).To(Equal(sparkapplication.HookPhasePostCompletion))
+			Expect(request.SparkApplication.Status.AppState.State).To(Equal(v1beta2.ApplicationStateCompleted))
+
+			// Specs returned by post-completion hooks are not applied.
+			Expect(k8sClient.Get(ctx, key, app)).To(Succeed())
+			Expect(app.Status.AppState.State).To(Equal(v1beta2.ApplicationStateCompleted))
+			Expect(app.Spec.SparkConf).To(BeEmpty())
+
+			// The failed hook is not retried but recorded as a warning event.
+			Eventually(recorder.Events).Should(Receive(ContainSubstring(common.EventSparkApplicationPostCompletionHookFailed)))
+
+			By("Reconciling the completed SparkApplication")
+			_, err = reconciler.Reconcile(ctx, reconcile.Request{NamespacedName: key})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(requests).To(BeEmpty())
+		})
+	})
+	Context("When reconciling a running SparkApplication with basic authentication of the web UI", func() {
+		ctx := context.Background()
+		appName := "test-web-ui-auth"
+		appNamespace := "default"
+		key := types.NamespacedName{
+			Name:      appName,
+			Namespace: appNamespace,
+		}
+		credentialsKey := types.NamespacedName{
+			Name:      "ui-credentials",
+			Namespace: appNamespace,
+		}
+		authSecretKey := types.NamespacedName{
+			Name:      naming.UIAuthSecretName(&v1beta2.SparkApplication{ObjectMeta: metav1.ObjectMeta{Name: appName}}),
+			Namespace: appNamespace,
+		}
+
+		// setCredentials creates or updates the Secret holding the credentials of the web UI.
+		setCredentials := func(username string, password string) {
+			credentials := &corev1.Secret{}
+			err := k8sClient.Get(ctx, credentialsKey, credentials)
+			if errors.IsNotFound(err) {
+				credentials.Name = credentialsKey.Name
+				credentials.Namespace = credentialsKey.Namespace
+			} else {
+				Expect(err).NotTo(HaveOccurred())
+			}
+			credentials.Data = map[string][]byte{
+				"username": []byte(username),
+				"password": []byte(password),
+			}
+			if credentials.ResourceVersion == "" {
+				Expect(k8sClient.Create(ctx, credentials)).To(Succeed())
+			} else {
+				Expect(k8sClient.Update(ctx, credentials)).To(Succeed())
+			}
+		}
+
+		// matchesCredentials returns whether the htpasswd file holds the given user with the given password.
+		matchesCredentials := func(htpasswd []byte, username string, password string) bool {
+			user, hash, ok := strings.Cut(strings.TrimSpace(string(htpasswd)), ":")
+			return ok && user == username && bcrypt.CompareHashAndPassword([]byte(hash), []byte(password)) == nil
+		}
+
+		reconcileApp := func() {
+			mgr, err := ctrl.NewManager(cfg, ctrl.Options{
+				Scheme:  k8sClient.Scheme(),
+				Metrics: metricsserver.Options{BindAddress: "0"},
+			})
+			Expect(err).NotTo(HaveOccurred())
+			reconciler := sparkapplication.NewReconciler(
+				mgr,
+				k8sClient.Scheme(),
+				k8sClient,
+				record.NewFakeRecorder(3),
+				nil,
+				sparkapplication.Options{Namespaces: []string{appNamespace}},
+			)
+			_, err = reconciler.Reconcile(ctx, reconcile.Request{NamespacedName: key})
+			Expect(err).NotTo(HaveOccurred())
+		}
+
+		BeforeEach(func() {
+			By("Creating a test SparkApplication")
+			app := &v1beta2.SparkApplication{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      appName,
+					Namespace: appNamespace,
+				},
+				Spec: v1beta2.SparkApplicationSpec{
+					MainApplicationFile: util.StringPtr("local:///dummy.jar"),
+					SparkUIOptions: &v1beta2.SparkUIConfiguration{
+						Auth: &v1beta2.SparkUIAuth{
+							BasicAuth: &v1beta2.SparkUIBasicAuth{SecretName: credentialsKey.Name},
+						},
+					},
+				},
+			}
+			v1beta2.SetSparkApplicationDefaults(app)
+			Expect(k8sClient.Create(ctx, app)).To(Succeed())
+
+			driverPod := createDriverPod(appName, appNamespace)
+			Expect(k8sClient.Create(ctx, driverPod)).To(Succeed())
+			driverPod.Status.Phase = corev1.PodRunning
+			Expect(k8sClient.Status().Update(ctx, driverPod)).To(Succeed())
+
+			app.Status.DriverInfo.PodName = driverPod.Name
+			app.Status.AppState.State = v1beta2.ApplicationStateRunning
+			app.Status.ObservedGeneration = app.Generation
+			Expect(k8sClient.Status().Update(ctx, app)).To(Succeed())
+		})
+
+		AfterEach(func() {
+			app := &v1beta2.SparkApplication{}
+			Expect(k8sClient.Get(ctx, key, app)).To(Succeed())
+
+			By("Deleting the created test SparkApplication")
+			Expect(k8sClient.Delete(ctx, app)).To(Succeed())
+
+			By("Deleting the driver pod")
+			driverPod := &corev1.Pod{}
+			Expect(k8sClient.Get(ctx, getDriverNamespacedName(appName, appNamespace), driverPod)).To(Succeed())
+			Expect(k8sClient.Delete(ctx, driverPod)).To(Succeed())
+
+			By("Deleting the secrets")
+			for _, secretKey := range []types.NamespacedName{credentialsKey, authSecretKey} {
+				secret := &corev1.Secret{ObjectMeta: metav1.ObjectMeta{Name: secretKey.Name, Namespace: secretKey.Namespace}}
+				Expect(client.IgnoreNotFound(k8sClient.Delete(ctx, secret))).To(Succeed())
+			}
+		})
+
+		It("Should create the htpasswd secret from the credentials and regenerate it once they change", func() {
+			setCredentials("admin", "secret")
+
+			By("Reconciling the running SparkApplication")
+			reconcileApp()
+			secret := &corev1.Secret{}
+			Expect(k8sClient.Get(ctx, authSecretKey, secret)).To(Succeed())
+			htpasswd := secret.Data[common.SparkUIAuthSecretKey]
+			Expect(string(htpasswd)).To(HavePrefix("admin:$2"))
+			Expect(matchesCredentials(htpasswd, "admin", "secret")).To(BeTrue())
+
+			By("Reconciling the running SparkApplication with unchanged credentials")
+			reconcileApp()
+			Expect(k8sClient.Get(ctx, authSecretKey, secret)).To(Succeed())
+			Expect(secret.Data[common.SparkUIAuthSecretKey]).To(Equal(htpasswd))
+
+			By("Reconciling the running SparkApplication with a rotated password")
+			setCredentials("admin", "rotated")
+			reconcileApp()
+			Expect(k8sClient.Get(ctx, authSecretKey, secret)).To(Succeed())
+			Expect(matchesCredentials(secret.Data[common.SparkUIAuthSecretKey], "admin", "rotated")).To(BeTrue())
+		})
+
+		It("Should not create the htpasswd secret from invalid credentials", func() {
+			setCredentials("ad:min", "secret")
+
+			By("Reconciling the running SparkApplication")
+			reconcileApp()
+			Expect(errors.IsNotFound(k8sClient.Get(ctx, authSecretKey, &corev1.Secret{}))).To(BeTrue())
+		})
+
+		It("Should not overwrite an htpasswd secret not owned by the SparkApplication", func() {
+			setCredentials("admin", "secret")
+			existing := &corev1.Secret{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      authSecretKey.Name,
+					Namespace: authSecretKey.Namespace,
+				},
+				Data: map[string][]byte{common.SparkUIAuthSecretKey: []byte("someone:else")},
+			}
+			Expect(k8sClient.Create(ctx, existing)).To(Succeed())
+
+			By("Reconciling the running SparkApplication")
+			reconcileApp()
+			secret := &corev1.Secret{}
+			Expect(k8sClient.Get(ctx, authSecretKey, secret)).To(Succeed())
+			Expect(secret.Data[common.SparkUIAuthSecretKey]).To(Equal([]byte("someone:else")))
+		})
+	})
+	Context("When submitting a SparkApplication with the Job submission engine", func() {
+		ctx := context.Background()
+		appName := "test-submitter-job"
+		appNamespace := "default"
+		key := types.NamespacedName{
+			Name:      appName,
+			Namespace: appNamespace,
+		}
+		jobSelector := client.MatchingLabels{common.LabelSparkAppName: appName, common.LabelSubmitterJob: "true"}
+
+		BeforeEach(func() {
+			By("Creating a test SparkApplication")
+			app := &v1beta2.SparkApplication{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      appName,
+					Namespace: appNamespace,
+				},
+				Spec: v1beta2.SparkApplicationSpec{
+					Image:               util.StringPtr("spark:3.5.3"),
+					MainApplicationFile: util.StringPtr("local:///dummy.jar"),
+					SubmissionEngine:    ptr.To(v1beta2.SubmissionEngineJob),
+				},
+			}
+			app.Spec.Driver.Template = &corev1.PodTemplateSpec{
+				ObjectMeta: metav1.ObjectMeta{Labels: map[string]string{"team": "data"}},
+			}
+			v1beta2.SetSparkApplicationDefaults(app)
+			Expect(k8sClient.Create(ctx, app)).To(Succeed())
+
+			By("Creating the submitter Job of a previous submission")
+			previous := &batchv1.Job{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "previous-submitter-job",
+					Namespace: appNamespace,
+					Labels:    jobSelector,
+				},
+				Spec: batchv1.JobSpec{
+					Template: corev1.PodTemplateSpec{
+						Spec: corev1.PodSpec{
+							RestartPolicy: corev1.RestartPolicyNever,
+							Containers:    []corev1.Container{{Name: "spark-submit", Image: "spark:3.5.3"}},
+						},
+					},
+				},
+			}
+			Expect(k8sClient.Create(ctx, previous)).To(Succeed())
+
+			GinkgoT().Setenv(common.EnvKubernetesServiceHost, "10.0.0.1")
+			GinkgoT().Setenv(common.EnvKubernetesServicePort, "443")
+		})
+
+		AfterEach(func() {
+			app := &v1beta2.SparkApplication{}
+			Expect(k8sClient.Get(ctx, key, app)).To(Succeed())
+
+			By("Deleting the created test SparkApplication")
+			Expect(k8sClient.Delete(ctx, app)).To(Succeed())
+
+			By("Deleting the submitter Jobs and their ConfigMaps")
+			Expect(k8sClient.DeleteAllOf(ctx, &batchv1.Job{}, client.InNamespace(appNamespace), jobSelector,
+				client.PropagationPolicy(metav1.DeletePropagationBackground))).To(Succeed())
+			Expect(k8sClient.DeleteAllOf(ctx, &corev1.ConfigMap{}, client.InNamespace(appNamespace), jobSelector)).To(Succeed())
+		})
+
+		It("Should create a submitter Job and delete the Jobs of previous submissions", func() {
+			By("Reconciling the new SparkApplication")
+			reconciler := sparkapplication.NewReconciler(
+				nil,
+				k8sClient.Scheme(),
+				k8sClient,
+				record.NewFakeRecorder(3),
+				nil,
+				sparkapplication.Options{Namespaces: []string{appNamespace}, Submission: sparkapplication.SubmissionOptions{EnableSubmitterJob: true}},
+			)
+			_, err := reconciler.Reconcile(ctx, reconcile.Request{NamespacedName: key})
+			Expect(err).NotTo(HaveOccurred())
+
+			app := &v1beta2.SparkApplication{}
+			Expect(k8sClient.Get(ctx, key, app)).To(Succeed())
+			Expect(app.Status.AppState.State).To(Equal(v1beta2.ApplicationStateSubmitted))
+
+			By("Checking the submitter Job")
+			jobs := &batchv1.JobList{}
+			Expect(k8sClient.List(ctx, jobs, client.InNamespace(appNamespace), jobSelector)).To(Succeed())
+			Expect(jobs.Items).To(HaveLen(1))
+			job := jobs.Items[0]
+			Expect(job.Name).To(Equal(naming.SubmitterJobName(app)))
+			Expect(job.Labels).To(HaveKeyWithValue(common.LabelSubmissionID, app.Status.SubmissionID))
+			Expect(*job.Spec.BackoffLimit).To(Equal(int32(0)))
+			Expect(*job.Spec.ActiveDeadlineSeconds).To(Equal(int64(300)))
+			Expect(job.OwnerReferences).To(HaveLen(1))
+			Expect(job.OwnerReferences[0].UID).To(Equal(app.UID))
+
+			podSpec := job.Spec.Template.Spec
+			Expect(podSpec.ServiceAccountName).To(Equal(util.GetDriverServiceAccountName(app)))
+			Expect(podSpec.Containers).To(HaveLen(1))
+			Expect(podSpec.Containers[0].Image).To(Equal("spark:3.5.3"))
+			Expect(podSpec.Containers[0].Args).To(ContainElement("local:///dummy.jar"))
+
+			By("Checking that the submission files are mounted from a ConfigMap owned by the Job")
+			submissionDir := fmt.Sprintf("/tmp/spark/%s", app.Status.SubmissionID)
+			Expect(podSpec.Containers[0].VolumeMounts).To(HaveLen(1))
+			Expect(podSpec.Containers[0].VolumeMounts[0].MountPath).To(Equal(submissionDir))
+			Expect(podSpec.Containers[0].Args).To(ContainElement(
+				fmt.Sprintf("%s=%s/driver-pod-template.yaml", common.SparkKubernetesDriverPodTemplateFile, submissionDir)))
+			configMap := &corev1.ConfigMap{}
+			Expect(k8sClient.Get(ctx, types.NamespacedName{Name: job.Name, Namespace: appNamespace}, configMap)).To(Succeed())
+			Expect(configMap.Data).To(HaveKeyWithValue("driver-pod-template.yaml", ContainSubstring("team: data")))
+			Expect(configMap.OwnerReferences).To(HaveLen(1))
+			Expect(configMap.OwnerReferences[0].UID).To(Equal(job.UID))
+		})
+
+		It("Should fail the submission if there is no image to run the submitter Job", func() {
+			By("Removing the image of the SparkApplication")
+			app := &v1beta2.SparkApplication{}
+			Expect(k8sClient.Get(ctx, key, app)).To(Succeed())
+			app.Spec.Image = nil
+			Expect(k8sClient.Update(ctx, app)).To(Succeed())
+
+			By("Reconciling the new SparkApplication")
+			reconciler := sparkapplication.NewReconciler(
+				nil,
+				k8sClient.Scheme(),
+				k8sClient,
+				record.NewFakeRecorder(3),
+				nil,
+				sparkapplication.Options{Namespaces: []string{appNamespace}, Submission: sparkapplication.SubmissionOptions{EnableSubmitterJob: true}},
+			)
+			_, err := reconciler.Reconcile(ctx, reconcile.Request{NamespacedName: key})
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(k8sClient.Get(ctx, key, app)).To(Succeed())
+			Expect(app.Status.AppState.State).To(Equal(v1beta2.ApplicationStateFailedSubmission))
+			Expect(app.Status.AppState.ErrorMessage).To(ContainSubstring("no image to run the submitter job"))
+		})
+
+		It("Should fail the submission if the Job submission engine is not enabled", func() {
+			By("Reconciling the new SparkApplication")
+			reconciler := sparkapplication.NewReconciler(
+				nil,
+				k8sClient.Scheme(),
+				k8sClient,
+				record.NewFakeRecorder(3),
+				nil,
+				sparkapplication.Options{Namespaces: []string{appNamespace}},
+			)
+			_, err := reconciler.Reconcile(ctx, reconcile.Request{NamespacedName: key})
+			Expect(err).NotTo(HaveOccurred())
+
+			app := &v1beta2.SparkApplication{}
+			Expect(k8sClient.Get(ctx, key, app)).To(Succeed())
+			Expect(app.Status.AppState.State).To(Equal(v1beta2.ApplicationStateFailedSubmission))
+			Expect(app.Status.AppState.ErrorMessage).To(ContainSubstring("submission engine Job is not enabled"))
+
+			By("Checking that no submitter Job was created")
+			jobs := &batchv1.JobList{}
+			Expect(k8sClient.List(ctx, jobs, client.InNamespace(appNamespace), jobSelector)).To(Succeed())
+			Expect(jobs.Items).To(HaveLen(1))
+			Expect(jobs.Items[0].Name).To(Equal("previous-submitter-job"))
+		})
+	})
+
+	Context("When reconciling a SparkApplication submitted with the Job submission engine", func() {
+		ctx := context.Background()
+		appName := "test-submitted-job"
+		appNamespace := "default"
+		submissionID := "test-submission-id"
+		key := types.NamespacedName{
+			Name:      appName,
+			Namespace: appNamespace,
+		}
+		jobKey := types.NamespacedName{
+			Name: naming.SubmitterJobName(&v1beta2.SparkApplication{
+				ObjectMeta: metav1.ObjectMeta{Name: appName},
+				Status:     v1beta2.SparkApplicationStatus{SubmissionID: submissionID},
+			}),
+			Namespace: appNamespace,
+		}
+
+		// createSubmitterJob creates the submitter Job of the app with the given finished condition, if any.
+		createSubmitterJob := func(conditionType batchv1.JobConditionType, reason string) {
+			job := &batchv1.Job{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      jobKey.Name,
+					Namespace: jobKey.Namespace,
+					Labels: map[string]string{
+						common.LabelSparkAppName: appName,
+						common.LabelSubmissionID: submissionID,
+						common.LabelSubmitterJob: "true",
+					},
+				},
+				Spec: batchv1.JobSpec{
+					Template: corev1.PodTemplateSpec{
+						Spec: corev1.PodSpec{
+							RestartPolicy: corev1.RestartPolicyNever,
+							Containers:    []corev1.Container{{Name: "spark-submit", Image: "spark:3.5.3"}},
+						},
+					},
+				},
+			}
+			Expect(k8sClient.Create(ctx, job)).To(Succeed())
+			if conditionType == "" {
+				return
+			}
+
+			now := metav1.Now()
+			job.Status.StartTime = &now
+			switch conditionType {
+			case batchv1.JobComplete:
+				job.Status.Succeeded = 1
+				job.Status.CompletionTime = &now
+				job.Status.Conditions = []batchv1.JobCondition{
+					{Type: batchv1.JobSuccessCriteriaMet, Status: corev1.ConditionTrue},
+					{Type: batchv1.JobComplete, Status: corev1.ConditionTrue},
+				}
+			case batchv1.JobFailed:
+				job.Status.Failed = 1
+				job.Status.Conditions = []batchv1.JobCondition{
+					{Type: batchv1.JobFailureTarget, Status: corev1.ConditionTrue, Reason: reason, Message: reason},
+					{Type: batchv1.JobFailed, Status: corev1.ConditionTrue, Reason: reason, Message: reason},
+				}
+			}
+			Expect(k8sClient.Status().Update(ctx, job)).To(Succeed())
+		}
+
+		reconcileApp := func() *v1beta2.SparkApplication {
+			mgr, err := ctrl.NewManager(cfg, ctrl.Options{
+				Scheme:  k8sClient.Scheme(),
+				Metrics: metricsserver.Options{BindAddress: "0"},
+			})
+			Expect(err).NotTo(HaveOccurred())
+			reconciler := sparkapplication.NewReconciler(
+				mgr,
+				k8sClient.Scheme(),
+				k8sClient,
+				record.NewFakeRecorder(3),
+				nil,
+				sparkapplication.Options{Namespaces: []string{appNamespace}, Submission: sparkapplication.SubmissionOptions{EnableSubmitterJob: true}},
+			)
+			_, err = reconciler.Reconcile(ctx, reconcile.Request{NamespacedName: key})
+			Expect(err).NotTo(HaveOccurred())
+
+			app := &v1beta2.SparkApplication{}
+			Expect(k8sClient.Get(ctx, key, app)).To(Succeed())
+			return app
+		}
+
+		BeforeEach(func() {
+			By("Creating a test SparkApplication submitted with the Job submission engine")
+			app := &v1beta2.SparkApplication{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      appName,
+					Namespace: appNamespace,
+				},
+				Spec: v1beta2.SparkApplicationSpec{
+					Image:               util.StringPtr("spark:3.5.3"),
+					MainApplicationFile: util.StringPtr("local:///dummy.jar"),
+					SubmissionEngine:    ptr.To(v1beta2.SubmissionEngineJob),
+				},
+			}
+			v1beta2.SetSparkApplicationDefaults(app)
+			Expect(k8sClient.Create(ctx, app)).To(Succeed())
+
+			app.Status.AppState.State = v1beta2.ApplicationStateSubmitted
+			app.Status.SubmissionID = submissionID
+			app.Status.DriverInfo.PodName = getDriverNamespacedName(appName, appNamespace).Name
+			app.Status.LastSubmissionAttemptTime = metav1.Now()
+			app.Status.ExecutionAttempts = 1
+			app.Status.ObservedGeneration = app.Generation
+			Expect(k8sClient.Status().Update(ctx, app)).To(Succeed())
+		})
+
+		AfterEach(func() {
+			app := &v1beta2.SparkApplication{}
+			Expect(k8sClient.Get(ctx, key, app)).To(Succeed())
+
+			By("Deleting the created test SparkApplication")
+			Expect(k8sClient.Delete(ctx, app)).To(Succeed())
+
+			By("Deleting the submitter Job and the pods")
+			job := &batchv1.Job{ObjectMeta: metav1.ObjectMeta{Name: jobKey.Name, Namespace: jobKey.Namespace}}
+			Expect(client.IgnoreNotFound(k8sClient.Delete(ctx, job, client.PropagationPolicy(metav1.DeletePropagationBackground)))).To(Succeed())
+			for _, name := range []string{getDriverNamespacedName(appName, appNamespace).Name, "test-submitter-pod"} {
+				pod := &corev1.Pod{ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: appNamespace}}
+				Expect(client.IgnoreNotFound(k8sClient.Delete(ctx, pod))).To(Succeed())
+			}
+		})
+
+		It("Should keep waiting for an active submitter Job without a driver pod", func() {
+			createSubmitterJob("", "")
+
+			app := reconcileApp()
+			Expect(app.Status.AppState.State).To(Equal(v1beta2.ApplicationStateSubmitted))
+			Expect(app.Status.ExecutionAttempts).To(Equal(int32(1)))
+		})
+
+		It("Should update the driver state once the submitter Job completed", func() {
+			createSubmitterJob(batchv1.JobComplete, "")
+			driverPod := createDriverPod(appName, appNamespace)
+			Expect(k8sClient.Create(ctx, driverPod)).To(Succeed())
+			driverPod.Status.Phase = corev1.PodRunning
+			Expect(k8sClient.Status().Update(ctx, driverPod)).To(Succeed())
+
+			app := reconcileApp()
+			Expect(app.Status.AppState.State).To(Equal(v1beta2.ApplicationStateRunning))
+			Expect(app.Status.ExecutionAttempts).To(Equal(int32(1)))
+		})
+
+		It("Should fail the submission with the error of spark-submit once the submitter Job failed", func() {
+			createSubmitterJob(batchv1.JobFailed, "BackoffLimitExceeded")
+			submitterPod := &corev1.Pod{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "test-submitter-pod",
+					Namespace: appNamespace,
+					Labels:    map[string]string{batchv1.JobNameLabel: jobKey.Name},
+				},
+				Spec: corev1.PodSpec{
+					RestartPolicy: corev1.RestartPolicyNever,
+					Containers:    []corev1.Container{{Name: "spark-submit", Image: "spark:3.5.3"}},
+				},
+			}
+			Expect(k8sClient.Create(ctx, submitterPod)).To(Succeed())
+			submitterPod.Status.Phase = corev1.PodFailed
+			submitterPod.Status.ContainerStatuses = []corev1.ContainerStatus{{
+				Name:  "spark-submit",
+				Image: "spark:3.5.3",
+				State: corev1.ContainerState{
+					Terminated: &corev1.ContainerStateTerminated{ExitCode: 1, Message: "Exception in thread main"},
+				},
+			}}
+			Expect(k8sClient.Status().Update(ctx, submitterPod)).To(Succeed())
+
+			app := reconcileApp()
+			Expect(app.Status.AppState.State).To(Equal(v1beta2.ApplicationStateFailedSubmission))
+			Expect(app.Status.AppState.ErrorMessage).To(ContainSubstring("Exception in thread main"))
+			Expect(app.Status.ExecutionAttempts).To(Equal(int32(0)))
+		})
+
+		It("Should fail the submission with the reason of the submitter Job without pods", func() {
+			createSubmitterJob(batchv1.JobFailed, "DeadlineExceeded")
+
+			app := reconcileApp()
+			Expect(app.Status.AppState.State).To(Equal(v1beta2.ApplicationStateFailedSubmission))
+			Expect(app.Status.AppState.ErrorMessage).To(ContainSubstring("DeadlineExceeded"))
+			Expect(app.Status.ExecutionAttempts).To(Equal(int32(0)))
+		})
+	})
+	Context("When running the post-run actions of a terminated SparkApplication", func() {
+		ctx := context.Background()
+		appName := "test-post-run-actions"
+		appNamespace := "default"
+		key := types.NamespacedName{
+			Name:      appName,
+			Namespace: appNamespace,
+		}
+		summaryKey := types.NamespacedName{
+			Name:      naming.PostRunSummaryName(&v1beta2.SparkApplication{ObjectMeta: metav1.ObjectMeta{Name: appName}}),
+			Namespace: appNamespace,
+		}
+		foreignKey := types.NamespacedName{
+			Name:      "test-foreign-summary",
+			Namespace: appNamespace,
+		}
+
+		// createApp creates a failed test SparkApplication with the given post-run actions.
+		createApp := func(actions ...v1beta2.PostRunAction) {
+			app := &v1beta2.SparkApplication{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      appName,
+					Namespace: appNamespace,
+				},
+				Spec: v1beta2.SparkApplicationSpec{
+					MainApplicationFile: util.StringPtr("local:///dummy.jar"),
+					PostRunActions:      actions,
+				},
+			}
+			v1beta2.SetSparkApplicationDefaults(app)
+			Expect(k8sClient.Create(ctx, app)).To(Succeed())
+
+			app.Status.AppState = v1beta2.ApplicationState{
+				State:        v1beta2.ApplicationStateFailed,
+				ErrorMessage: "driver container failed",
+			}
+			app.Status.SubmissionID = "test-submission-id"
+			app.Status.TerminationTime = metav1.Now()
+			app.Status.ObservedGeneration = app.Generation
+			Expect(k8sClient.Status().Update(ctx, app)).To(Succeed())
+		}
+
+		reconcileApp := func(options sparkapplication.Options) *v1beta2.SparkApplication {
+			options.Namespaces = []string{appNamespace}
+			reconciler := sparkapplication.NewReconciler(
+				nil,
+				k8sClient.Scheme(),
+				k8sClient,
+				record.NewFakeRecorder(100),
+				nil,
+				options,
+			)
+			_, err := reconciler.Reconcile(ctx, reconcile.Request{NamespacedName: key})
+			Expect(err).NotTo(HaveOccurred())
+
+			app := &v1beta2.SparkApplication{}
+			Expect(k8sClient.Get(ctx, key, app)).To(Succeed())
+			return app
+		}
+
+		AfterEach(func() {
+			app := &v1beta2.SparkApplication{}
+			Expect(k8sClient.Get(ctx, key, app)).To(Succeed())
+
+			By("Deleting the created test SparkApplication")
+			Expect(k8sClient.Delete(ctx, app)).To(Succeed())
+
+			By("Deleting the summary ConfigMaps")
+			for _, configMapKey := range []types.NamespacedName{summaryKey, foreignKey} {
+				configMap := &corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Name: configMapKey.Name, Namespace: configMapKey.Namespace}}
+				Expect(client.IgnoreNotFound(k8sClient.Delete(ctx, configMap))).To(Succeed())
+			}
+		})
+
+		It("Should only call the webhooks allowed by the operator without following redirects", func() {
+			requests := make(chan string, 10)
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				requests <- r.URL.Path
+			}))
+			defer server.Close()
+			redirecting := httptest.NewServer(http.RedirectHandler(server.URL, http.StatusTemporaryRedirect))
+			defer redirecting.Close()
+
+			createApp(
+				v1beta2.PostRunAction{Name: "allowed", Webhook: &v1beta2.WebhookPostRunAction{URL: server.URL + "/done"}},
+				v1beta2.PostRunAction{Name: "not-allowed", Webhook: &v1beta2.WebhookPostRunAction{URL: "http://169.254.169.254/latest/meta-data/"}},
+				v1beta2.PostRunAction{Name: "redirected", Webhook: &v1beta2.WebhookPostRunAction{URL: redirecting.URL + "/done"}},
+			)
+
+			By("Reconciling the failed SparkApplication")
+			app := reconcileApp(sparkapplication.Options{PostRunWebhookURLPrefixes: []string{server.URL + "/", redirecting.URL + "/"}})
+			Expect(app.Status.PostRunActions).To(HaveLen(3))
+			Expect(app.Status.PostRunActions[0].State).To(Equal(v1beta2.PostRunActionStateSucceeded))
+			Expect(app.Status.PostRunActions[1].State).To(Equal(v1beta2.PostRunActionStateFailed))
+			Expect(app.Status.PostRunActions[1].Message).To(ContainSubstring("is not allowed by the operator"))
+			Expect(app.Status.PostRunActions[2].State).To(Equal(v1beta2.PostRunActionStateFailed))
+			Expect(app.Status.PostRunActions[2].Message).To(ContainSubstring("returned status 307"))
+
+			// Only the allowed webhook reached the server, the redirect was not followed.
+			Expect(requests).To(HaveLen(1))
+			Expect(<-requests).To(Equal("/done"))
+		})
+
+		It("Should create the summary ConfigMap of the run and replace it with the one of a later run", func() {
+			createApp(v1beta2.PostRunAction{Name: "summary", ConfigMapSummary: &v1beta2.ConfigMapSummaryPostRunAction{}})
+
+			By("Reconciling the failed SparkApplication")
+			app := reconcileApp(sparkapplication.Options{})
+			Expect(app.Status.PostRunActions).To(HaveLen(1))
+			Expect(app.Status.PostRunActions[0].State).To(Equal(v1beta2.PostRunActionStateSucceeded))
+
+			configMap := &corev1.ConfigMap{}
+			Expect(k8sClient.Get(ctx, summaryKey, configMap)).To(Succeed())
+			Expect(configMap.Labels).To(HaveKeyWithValue(common.LabelSparkAppName, appName))
+			Expect(configMap.Data).To(HaveKeyWithValue("state", string(v1beta2.ApplicationStateFailed)))
+			Expect(configMap.Data).To(HaveKeyWithValue("errorMessage", "driver container failed"))
+
+			By("Completing a later run of the SparkApplication")
+			app.Status.SubmissionID = "next-submission-id"
+			app.Status.AppState = v1beta2.ApplicationState{State: v1beta2.ApplicationStateCompleted}
+			app.Status.PostRunActions = nil
+			Expect(k8sClient.Status().Update(ctx, app)).To(Succeed())
+
+			By("Reconciling the completed SparkApplication")
+			reconcileApp(sparkapplication.Options{})
+			Expect(k8sClient.Get(ctx, summaryKey, configMap)).To(Succeed())
+			Expect(configMap.Data).To(HaveKeyWithValue("submissionID", "next-submission-id"))
+			Expect(configMap.Data).To(HaveKeyWithValue("state", string(v1beta2.ApplicationStateCompleted)))
+			Expect(configMap.Data).NotTo(HaveKey("errorMessage"))
+		})
+
+		It("Should not overwrite a ConfigMap not created for the SparkApplication with its summary", func() {
+			foreign := &corev1.ConfigMap{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      foreignKey.Name,
+					Namespace: foreignKey.Namespace,
+					Labels:    map[string]string{common.LabelSparkAppName: "other-app"},
+				},
+				Data: map[string]string{"ca.crt": "certificate"},
+			}
+			Expect(k8sClient.Create(ctx, foreign)).To(Succeed())
+			createApp(v1beta2.PostRunAction{
+				Name:             "summary",
+				ConfigMapSummary: &v1beta2.ConfigMapSummaryPostRunAction{ConfigMapName: util.StringPtr(foreignKey.Name)},
+			})
+
+			By("Reconciling the failed SparkApplication")
+			app := reconcileApp(sparkapplication.Options{})
+			Expect(app.Status.PostRunActions).To(HaveLen(1))
+			Expect(app.Status.PostRunActions[0].State).To(Equal(v1beta2.PostRunActionStateFailed))
+			Expect(app.Status.PostRunActions[0].Message).To(ContainSubstring("was not created for SparkApplication"))
+
+			configMap := &corev1.ConfigMap{}
+			Expect(k8sClient.Get(ctx, foreignKey, configMap)).To(Succeed())
+			Expect(configMap.Labels).To(Equal(foreign.Labels))
+			Expect(configMap.Data).To(Equal(foreign.Data))
+		})
+	})
+
+	Context("When reconciling a SparkApplication with resource reservation", func() {
+		ctx := context.Background()
+		appName := "test-resource-reservation"
+		appNamespace := "default"
+		key := types.NamespacedName{
+			Name:      appName,
+			Namespace: appNamespace,
+		}
+		reservationKey := types.NamespacedName{
+			Name:      naming.ResourceReservationName(&v1beta2.SparkApplication{ObjectMeta: metav1.ObjectMeta{Name: appName}}),
+			Namespace: appNamespace,
+		}
+
+		getReservation := func() (string, string) {
+			quota := &corev1.ResourceQuota{}
+			Expect(k8sClient.Get(ctx, reservationKey, quota)).To(Succeed())
+			Expect(quota.Labels).To(HaveKeyWithValue(common.LabelResourceReservation, "true"))
+			cpu := quota.Spec.Hard.Name(corev1.ResourceRequestsCPU, resource.DecimalSI)
+			memory := quota.Spec.Hard.Name(corev1.ResourceRequestsMemory, resource.BinarySI)
+			return cpu.String(), memory.String()
+		}
+
+		BeforeEach(func() {
+			By("Creating a test SparkApplication")
+			app := &v1beta2.SparkApplication{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      appName,
+					Namespace: appNamespace,
+				},
+				Spec: v1beta2.SparkApplicationSpec{
+					Image:               util.StringPtr("spark:3.5.3"),
+					MainClass:           util.StringPtr("org.apache.spark.examples.SparkPi"),
+					MainApplicationFile: util.StringPtr("local:///opt/spark/examples/jars/spark-examples.jar"),
+					Executor: v1beta2.ExecutorSpec{
+						SparkPodSpec: v1beta2.SparkPodSpec{
+							Cores:  ptr.To[int32](1),
+							Memory: util.StringPtr("1Gi"),
+						},
+					},
+					DynamicAllocation: &v1beta2.DynamicAllocation{
+						Enabled:      true,
+						MinExecutors: ptr.To[int32](1),
+						MaxExecutors: ptr.To[int32](4),
+					},
+				},
+			}
+			v1beta2.SetSparkApplicationDefaults(app)
+			Expect(k8sClient.Create(ctx, app)).To(Succeed())
+		})
+
+		AfterEach(func() {
+			app := &v1beta2.SparkApplication{}
+			if err := k8sClient.Get(ctx, key, app); err == nil {
+				By("Deleting the created test SparkApplication")
+				app.Finalizers = nil
+				Expect(k8sClient.Update(ctx, app)).To(Succeed())
+				Expect(k8sClient.Delete(ctx, app)).To(Succeed())
+			}
+
+			By("Deleting the resources of the SparkApplication")
+			driverPod := &corev1.Pod{}
+			if err := k8sClient.Get(ctx, getDriverNamespacedName(appName, appNamespace), driverPod); err == nil {
+				if appID := driverPod.Labels[common.LabelSparkApplicationSelector]; appID != "" {
+					selector := client.MatchingLabels{common.LabelSparkApplicationSelector: appID}
+					Expect(k8sClient.DeleteAllOf(ctx, &corev1.Service{}, client.InNamespace(appNamespace), selector)).To(Succeed())
+					Expect(k8sClient.DeleteAllOf(ctx, &corev1.ConfigMap{}, client.InNamespace(appNamespace), selector)).To(Succeed())
+				}
+			}
+			selector := client.MatchingLabels{common.LabelSparkAppName: appName}
+			Expect(k8sClient.DeleteAllOf(ctx, &corev1.Pod{}, client.InNamespace(appNamespace), selector)).To(Succeed())
+			Expect(k8sClient.DeleteAllOf(ctx, &corev1.ResourceQuota{}, client.InNamespace(appNamespace), selector)).To(Succeed())
+		})
+
+		It("Should reserve all executors the SparkApplication may scale up to upon submission", func() {
+			By("Reconciling the new SparkApplication")
+			GinkgoT().Setenv(common.EnvKubernetesServiceHost, "10.0.0.1")
+			GinkgoT().Setenv(common.EnvKubernetesServicePort, "443")
+			reconciler := sparkapplication.NewReconciler(
+				nil,
+				k8sClient.Scheme(),
+				k8sClient,
+				record.NewFakeRecorder(100),
+				nil,
+				sparkapplication.Options{
+					Namespaces: []string{appNamespace},
+					Submission: sparkapplication.SubmissionOptions{DefaultEngine: v1beta2.SubmissionEngineNative},
+					Scheduling: sparkapplication.SchedulingOptions{EnableResourceReservation: true},
+				},
+			)
+			_, err := reconciler.Reconcile(ctx, reconcile.Request{NamespacedName: key})
+			Expect(err).NotTo(HaveOccurred())
+
+			app := &v1beta2.SparkApplication{}
+			Expect(k8sClient.Get(ctx, key, app)).To(Succeed())
+			Expect(app.Status.AppState.State).To(Equal(v1beta2.ApplicationStateSubmitted))
+			cpu, memory := getReservation()
+			Expect(cpu).To(Equal("4"))
+			Expect(memory).To(Equal("4Gi"))
+		})
+
+		It("Should not reserve resources if the SparkApplication cannot scale up", func() {
+			app := &v1beta2.SparkApplication{}
+			Expect(k8sClient.Get(ctx, key, app)).To(Succeed())
+			app.Spec.DynamicAllocation.MinExecutors = ptr.To[int32](4)
+			Expect(k8sClient.Update(ctx, app)).To(Succeed())
+
+			By("Reconciling the new SparkApplication")
+			GinkgoT().Setenv(common.EnvKubernetesServiceHost, "10.0.0.1")
+			GinkgoT().Setenv(common.EnvKubernetesServicePort, "443")
+			reconciler := sparkapplication.NewReconciler(
+				nil,
+				k8sClient.Scheme(),
+				k8sClient,
+				record.NewFakeRecorder(100),
+				nil,
+				sparkapplication.Options{
+					Namespaces: []string{appNamespace},
+					Submission: sparkapplication.SubmissionOptions{DefaultEngine: v1beta2.SubmissionEngineNative},
+					Scheduling: sparkapplication.SchedulingOptions{EnableResourceReservation: true},
+				},
+			)
+			_, err := reconciler.Reconcile(ctx, reconcile.Request{NamespacedName: key})
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(k8sClient.Get(ctx, key, app)).To(Succeed())
+			Expect(app.Status.AppState.State).To(Equal(v1beta2.ApplicationStateSubmitted))
+			err = k8sClient.Get(ctx, reservationKey, &corev1.ResourceQuota{})
+			Expect(errors.IsNotFound(err)).To(BeTrue())
+		})
+
+		It("Should shrink the reservation as executors start and delete it along with the SparkApplication", func() {
+			By("Running the SparkApplication with 3 executors")
+			driverPod := createDriverPod(appName, appNamespace)
+			Expect(k8sClient.Create(ctx, driverPod)).To(Succeed())
+			driverPod.Status.Phase = corev1.PodRunning
+			Expect(k8sClient.Status().Update(ctx, driverPod)).To(Succeed())
+			executorPods := make([]*corev1.Pod, 0, 3)
+			for id := 1; id <= 3; id++ {
+				executorPod := createExecutorPod(appName, appNamespace, id)
+				Expect(k8sClient.Create(ctx, executorPod)).To(Succeed())
+				executorPod.Status.Phase = corev1.PodRunning
+				Expect(k8sClient.Status().Update(ctx, executorPod)).To(Succeed())
+				executorPods = append(executorPods, executorPod)
+			}
+
+			app := &v1beta2.SparkApplication{}
+			Expect(k8sClient.Get(ctx, key, app)).To(Succeed())
+			app.Status.DriverInfo.PodName = driverPod.Name
+			app.Status.AppState.State = v1beta2.ApplicationStateRunning
+			app.Status.ObservedGeneration = app.Generation
+			Expect(k8sClient.Status().Update(ctx, app)).To(Succeed())
+
+			By("Reconciling the running SparkApplication")
+			reconciler := sparkapplication.NewReconciler(
+				nil,
+				k8sClient.Scheme(),
+				k8sClient,
+				record.NewFakeRecorder(100),
+				nil,
+				sparkapplication.Options{
+					Namespaces:             []string{appNamespace},
+					DisableOwnerReferences: true,
+					Scheduling:             sparkapplication.SchedulingOptions{EnableResourceReservation: true},
+				},
+			)
+			_, err := reconciler.Reconcile(ctx, reconcile.Request{NamespacedName: key})
+			Expect(err).NotTo(HaveOccurred())
+			cpu, memory := getReservation()
+			Expect(cpu).To(Equal("1"))
+			Expect(memory).To(Equal("1Gi"))
+
+			By("Reconciling the running SparkApplication after an executor failed")
+			executorPods[2].Status.Phase = corev1.PodFailed
+			Expect(k8sClient.Status().Update(ctx, executorPods[2])).To(Succeed())
+			_, err = reconciler.Reconcile(ctx, reconcile.Request{NamespacedName: key})
+			Expect(err).NotTo(HaveOccurred())
+			cpu, memory = getReservation()
+			Expect(cpu).To(Equal("2"))
+			Expect(memory).To(Equal("2Gi"))
+
+			By("Reconciling the deleted SparkApplication")
+			Expect(k8sClient.Get(ctx, key, app)).To(Succeed())
+			Expect(app.Finalizers).To(ContainElement(common.SparkApplicationFinalizerName))
+			Expect(k8sClient.Delete(ctx, app)).To(Succeed())
+			_, err = reconciler.Reconcile(ctx, reconcile.Request{NamespacedName: key})
+			Expect(err).NotTo(HaveOccurred())
+			err = k8sClient.Get(ctx, reservationKey, &corev1.ResourceQuota{})
+			Expect(errors.IsNotFound(err)).To(BeTrue())
+		})
+	})
+	Context("When submitting a SparkApplication with a generated Spark authentication secret", func() {
+		ctx := context.Background()
+		appName := "test-spark-auth"
+		appNamespace := "default"
+		key := types.NamespacedName{
+			Name:      appName,
+			Namespace: appNamespace,
+		}
+
+		createApp := func(sparkConf map[string]string) *v1beta2.SparkApplication {
+			By("Creating a test SparkApplication")
+			app := &v1beta2.SparkApplication{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      appName,
+					Namespace: appNamespace,
+				},
+				Spec: v1beta2.SparkApplicationSpec{
+					MainApplicationFile: util.StringPtr("local:///dummy.jar"),
+					SparkConf:           sparkConf,
+				},
+			}
+			v1beta2.SetSparkApplicationDefaults(app)
+			Expect(k8sClient.Create(ctx, app)).To(Succeed())
+			return app
+		}
+
+		// submit reconciles the new SparkApplication with a spark-submit that fails printing its arguments,
+		// and returns the failed SparkApplication.
+		submit := func() *v1beta2.SparkApplication {
+			sparkHome := GinkgoT().TempDir()
+			Expect(os.Mkdir(filepath.Join(sparkHome, "bin"), 0o755)).To(Succeed())
+			script := "#!/bin/sh\necho \"$@\" >&2\nexit 1\n"
+			Expect(os.WriteFile(filepath.Join(sparkHome, "bin", "spark-submit"), []byte(script), 0o755)).To(Succeed())
+			GinkgoT().Setenv(common.EnvSparkHome, sparkHome)
+			GinkgoT().Setenv(common.EnvKubernetesServiceHost, "10.0.0.1")
+			GinkgoT().Setenv(common.EnvKubernetesServicePort, "443")
+
+			reconciler := sparkapplication.NewReconciler(
+				nil,
+				k8sClient.Scheme(),
+				k8sClient,
+				record.NewFakeRecorder(100),
+				nil,
+				sparkapplication.Options{Namespaces: []string{appNamespace}, EnableSparkAuthSecret: true},
+			)
+			_, err := reconciler.Reconcile(ctx, reconcile.Request{NamespacedName: key})
+			Expect(err).NotTo(HaveOccurred())
+
+			app := &v1beta2.SparkApplication{}
+			Expect(k8sClient.Get(ctx, key, app)).To(Succeed())
+			Expect(app.Status.AppState.State).To(Equal(v1beta2.ApplicationStateFailedSubmission))
+			return app
+		}
+
+		AfterEach(func() {
+			app := &v1beta2.SparkApplication{}
+			Expect(k8sClient.Get(ctx, key, app)).To(Succeed())
+
+			By("Deleting the authentication secret")
+			secret := &corev1.Secret{}
+			Expect(k8sClient.Get(ctx, types.NamespacedName{Namespace: appNamespace, Name: util.GetSparkAuthSecretName(app)}, secret)).To(Succeed())
+			Expect(k8sClient.Delete(ctx, secret)).To(Succeed())
+
+			By("Deleting the created test SparkApplication")
+			Expect(k8sClient.Delete(ctx, app)).To(Succeed())
+		})
+
+		It("Should mount a secret owned by the SparkApplication and reuse it across runs", func() {
+			createApp(nil)
+			app := submit()
+			name := util.GetSparkAuthSecretName(app)
+			Expect(app.Status.AppState.ErrorMessage).To(And(
+				ContainSubstring(fmt.Sprintf("%s=true", common.SparkAuthenticate)),
+				ContainSubstring(fmt.Sprintf("%s=%s/%s", common.SparkAuthenticateSecretFile, common.SparkAuthSecretMountPath, common.SparkAuthSecretKey)),
+				ContainSubstring(fmt.Sprintf(common.SparkKubernetesDriverSecretsTemplate+"=%s", name, common.SparkAuthSecretMountPath)),
+				ContainSubstring(fmt.Sprintf(common.SparkKubernetesExecutorSecretsTemplate+"=%s", name, common.SparkAuthSecretMountPath)),
+				ContainSubstring(fmt.Sprintf("%s=true", common.SparkNetworkCryptoEnabled)),
+			))
+
+			By("Checking the authentication secret")
+			secret := &corev1.Secret{}
+			Expect(k8sClient.Get(ctx, types.NamespacedName{Namespace: appNamespace, Name: name}, secret)).To(Succeed())
+			Expect(secret.Labels).To(HaveKeyWithValue(common.LabelSparkAppName, appName))
+			Expect(secret.OwnerReferences).To(HaveLen(1))
+			Expect(secret.OwnerReferences[0].UID).To(Equal(app.UID))
+			value, err := base64.StdEncoding.DecodeString(string(secret.Data[common.SparkAuthSecretKey]))
+			Expect(err).NotTo(HaveOccurred())
+			Expect(value).To(HaveLen(32))
+
+			By("Submitting the SparkApplication again")
+			app.Status.AppState.State = v1beta2.ApplicationStateNew
+			Expect(k8sClient.Status().Update(ctx, app)).To(Succeed())
+			submit()
+			resubmitted := &corev1.Secret{}
+			Expect(k8sClient.Get(ctx, types.NamespacedName{Namespace: appNamespace, Name: name}, resubmitted)).To(Succeed())
+			Expect(resubmitted.Data).To(Equal(secret.Data))
+		})
+
+		It("Should keep the network encryption setting of the SparkApplication", func() {
+			createApp(map[string]string{common.SparkNetworkCryptoEnabled: "false"})
+			app := submit()
+			Expect(app.Status.AppState.ErrorMessage).To(And(
+				ContainSubstring(fmt.Sprintf("%s=true", common.SparkAuthenticate)),
+				ContainSubstring(fmt.Sprintf("%s=false", common.SparkNetworkCryptoEnabled)),
+			))
+		})
+	})
+	Context("When submitting a SparkApplication with driver pod validation", func() {
+		ctx := context.Background()
+		appName := "test-driver-pod-validation"
+		var key types.NamespacedName
+
+		createNamespace := func(name string, labels map[string]string) {
+			namespace := &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: name, Labels: labels}}
+			Expect(client.IgnoreAlreadyExists(k8sClient.Create(ctx, namespace))).To(Succeed())
+		}
+
+		// submit creates a SparkApplication and reconciles it with driver pod validation enabled.
+		submit := func(namespace string, image *string) *v1beta2.SparkApplication {
+			By("Creating a test SparkApplication")
+			key = types.NamespacedName{Name: appName, Namespace: namespace}
+			app := &v1beta2.SparkApplication{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      appName,
+					Namespace: namespace,
+				},
+				Spec: v1beta2.SparkApplicationSpec{
+					Image:               image,
+					MainApplicationFile: util.StringPtr("local:///dummy.jar"),
+				},
+			}
+			v1beta2.SetSparkApplicationDefaults(app)
+			Expect(k8sClient.Create(ctx, app)).To(Succeed())
+
+			By("Reconciling the new SparkApplication")
+			reconciler := sparkapplication.NewReconciler(
+				nil,
+				k8sClient.Scheme(),
+				k8sClient,
+				record.NewFakeRecorder(100),
+				nil,
+				sparkapplication.Options{Namespaces: []string{namespace}, Submission: sparkapplication.SubmissionOptions{EnableDriverPodValidation: true}},
+			)
+			_, err := reconciler.Reconcile(ctx, reconcile.Request{NamespacedName: key})
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(k8sClient.Get(ctx, key, app)).To(Succeed())
+			pods := &corev1.PodList{}
+			Expect(k8sClient.List(ctx, pods, client.InNamespace(namespace), client.MatchingLabels{common.LabelSparkAppName: appName})).To(Succeed())
+			Expect(pods.Items).To(BeEmpty())
+			return app
+		}
+
+		AfterEach(func() {
+			app := &v1beta2.SparkApplication{}
+			Expect(k8sClient.Get(ctx, key, app)).To(Succeed())
+
+			By("Deleting the created test SparkApplication")
+			Expect(k8sClient.Delete(ctx, app)).To(Succeed())
+		})
+
+		It("Should go on with the submission if the driver pod is admitted", func() {
+			app := submit("default", util.StringPtr("spark:3.5.3"))
+			Expect(app.Status.AppState.State).To(Equal(v1beta2.ApplicationStateFailedSubmission))
+			condition := meta.FindStatusCondition(app.Status.Conditions, string(v1beta2.SparkApplicationConditionDriverPodAdmitted))
+			Expect(condition).NotTo(BeNil())
+			Expect(condition.Status).To(Equal(metav1.ConditionTrue))
+		})
+
+		It("Should fail the SparkApplication without retries if the driver pod is invalid", func() {
+			app := submit("default", nil)
+			Expect(app.Status.AppState.State).To(Equal(v1beta2.ApplicationStateFailed))
+			Expect(app.Status.AppState.ErrorMessage).To(ContainSubstring("driver pod rejected by admission"))
+			condition := meta.FindStatusCondition(app.Status.Conditions, string(v1beta2.SparkApplicationConditionDriverPodAdmitted))
+			Expect(condition).NotTo(BeNil())
+			Expect(condition.Status).To(Equal(metav1.ConditionFalse))
+			Expect(condition.Message).To(ContainSubstring("image"))
+		})
+
+		It("Should fail the SparkApplication without retries if the driver pod is forbidden by PodSecurity", func() {
+			namespace := "driver-pod-validation-restricted"
+			createNamespace(namespace, map[string]string{"pod-security.kubernetes.io/enforce": "restricted"})
+			app := submit(namespace, util.StringPtr("spark:3.5.3"))
+			Expect(app.Status.AppState.State).To(Equal(v1beta2.ApplicationStateFailed))
+			condition := meta.FindStatusCondition(app.Status.Conditions, string(v1beta2.SparkApplicationConditionDriverPodAdmitted))
+			Expect(condition).NotTo(BeNil())
+			Expect(condition.Status).To(Equal(metav1.ConditionFalse))
+			Expect(condition.Message).To(ContainSubstring("PodSecurity"))
+		})
+
+		It("Should retry the submission if the driver pod exceeds a quota", func() {
+			namespace := "driver-pod-validation-quota"
+			createNamespace(namespace, nil)
+			quota := &corev1.ResourceQuota{
+				ObjectMeta: metav1.ObjectMeta{Name: "pods", Namespace: namespace},
+				Spec:       corev1.ResourceQuotaSpec{Hard: corev1.ResourceList{corev1.ResourcePods: resource.MustParse("0")}},
+			}
+			Expect(k8sClient.Create(ctx, quota)).To(Succeed())
+			DeferCleanup(func() { Expect(k8sClient.Delete(ctx, quota)).To(Succeed()) })
+			// There is no quota controller in the test environment to compute the usage.
+			quota.Status.Hard = quota.Spec.Hard
+			quota.Status.Used = corev1.ResourceList{corev1.ResourcePods: resource.MustParse("0")}
+			Expect(k8sClient.Status().Update(ctx, quota)).To(Succeed())
+
+			app := submit(namespace, util.StringPtr("spark:3.5.3"))
+			Expect(app.Status.AppState.State).To(Equal(v1beta2.ApplicationStateFailedSubmission))
+			Expect(app.Status.AppState.ErrorMessage).To(ContainSubstring("exceeded quota"))
+			Expect(meta.FindStatusCondition(app.Status.Conditions, string(v1beta2.SparkApplicationConditionDriverPodAdmitted))).To(BeNil())
+		})
+	})
+	Context("When reconciling a SparkApplication with owner references disabled", func() {
+		ctx := context.Background()
+		appName := "test-no-owner-references"
+		appNamespace := "default"
+		key := types.NamespacedName{
+			Name:      appName,
+			Namespace: appNamespace,
+		}
+		var configMapKey types.NamespacedName
+
+		BeforeEach(func() {
+			By("Creating a test SparkApplication")
+			app := &v1beta2.SparkApplication{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      appName,
+					Namespace: appNamespace,
+				},
+				Spec: v1beta2.SparkApplicationSpec{
+					MainApplicationFile: util.StringPtr("local:///dummy.jar"),
+					Monitoring: &v1beta2.MonitoringSpec{
+						ExposeDriverMetrics: true,
+						Prometheus: &v1beta2.PrometheusSpec{
+							JmxExporterJar: "/prometheus/jmx_prometheus_javaagent.jar",
+						},
+					},
+				},
+			}
+			v1beta2.SetSparkApplicationDefaults(app)
+			Expect(k8sClient.Create(ctx, app)).To(Succeed())
+			configMapKey = types.NamespacedName{Namespace: appNamespace, Name: util.GetPrometheusConfigMapName(app)}
+		})
+
+		AfterEach(func() {
+			app := &v1beta2.SparkApplication{}
+			if err := k8sClient.Get(ctx, key, app); err == nil {
+				By("Deleting the created test SparkApplication")
+				app.Finalizers = nil
+				Expect(k8sClient.Update(ctx, app)).To(Succeed())
+				Expect(k8sClient.Delete(ctx, app)).To(Succeed())
+			}
+
+			By("Deleting the Prometheus ConfigMap")
+			configMap := &corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Name: configMapKey.Name, Namespace: configMapKey.Namespace}}
+			Expect(client.IgnoreNotFound(k8sClient.Delete(ctx, configMap))).To(Succeed())
+		})
+
+		It("Should label the resources and clean them up when the SparkApplication is deleted", func() {
+			By("Reconciling the new SparkApplication")
+			reconciler := sparkapplication.NewReconciler(
+				nil,
+				k8sClient.Scheme(),
+				k8sClient,
+				record.NewFakeRecorder(100),
+				nil,
+				sparkapplication.Options{Namespaces: []string{appNamespace}, DisableOwnerReferences: true},
+			)
+			_, err := reconciler.Reconcile(ctx, reconcile.Request{NamespacedName: key})
+			Expect(err).NotTo(HaveOccurred())
+
+			app := &v1beta2.SparkApplication{}
+			Expect(k8sClient.Get(ctx, key, app)).To(Succeed())
+			Expect(app.Finalizers).To(ConsistOf(common.SparkApplicationFinalizerName))
+			configMap := &corev1.ConfigMap{}
+			Expect(k8sClient.Get(ctx, configMapKey, configMap)).To(Succeed())
+			Expect(configMap.OwnerReferences).To(BeEmpty())
+			Expect(configMap.Labels).To(HaveKeyWithValue(common.LabelSparkAppName, appName))
+
+			By("Reconciling the deleted SparkApplication")
+			Expect(k8sClient.Delete(ctx, app)).To(Succeed())
+			_, err = reconciler.Reconcile(ctx, reconcile.Request{NamespacedName: key})
+			Expect(err).NotTo(HaveOccurred())
+
+			err = k8sClient.Get(ctx, configMapKey, &corev1.ConfigMap{})
+			Expect(errors.IsNotFound(err)).To(BeTrue())
+			err = k8sClient.Get(ctx, key, &v1beta2.SparkApplication{})
+			Expect(errors.IsNotFound(err)).To(BeTrue())
+		})
+
+		It("Should set owner references on the resources by default", func() {
+			By("Reconciling the new SparkApplication")
+			reconciler := sparkapplication.NewReconciler(
+				nil,
+				k8sClient.Scheme(),
+				k8sClient,
+				record.NewFakeRecorder(100),
+				nil,
+				sparkapplication.Options{Namespaces: []string{appNamespace}},
+			)
+			_, err := reconciler.Reconcile(ctx, reconcile.Request{NamespacedName: key})
+			Expect(err).NotTo(HaveOccurred())
+
+			app := &v1beta2.SparkApplication{}
+			Expect(k8sClient.Get(ctx, key, app)).To(Succeed())
+			Expect(app.Finalizers).To(BeEmpty())
+			configMap := &corev1.ConfigMap{}
+			Expect(k8sClient.Get(ctx, configMapKey, configMap)).To(Succeed())
+			Expect(configMap.OwnerReferences).To(HaveLen(1))
+			Expect(configMap.OwnerReferences[0].UID).To(Equal(app.UID))
+		})
+	})
+	Context("When reconciling a SparkApplication with secret rotation", func() {
+		ctx := context.Background()
+		appName := "test-secret-rotation"
+		appNamespace := "default"
+		key := types.NamespacedName{
+			Name:      appName,
+			Namespace: appNamespace,
+		}
+		secretKey := types.NamespacedName{
+			Name:      "test-secret-rotation-credentials",
+			Namespace: appNamespace,
+		}
+
+		newReconciler := func(options sparkapplication.Options) *sparkapplication.Reconciler {
+			// Secrets are read with the API reader of the manager, which does not need to be started.
+			mgr, err := ctrl.NewManager(cfg, ctrl.Options{
+				Scheme:  k8sClient.Scheme(),
+				Metrics: metricsserver.Options{BindAddress: "0"},
+			})
+			Expect(err).NotTo(HaveOccurred())
+			options.Namespaces = []string{appNamespace}
+			return sparkapplication.NewReconciler(mgr, k8sClient.Scheme(), k8sClient, record.NewFakeRecorder(100), nil, options)
+		}
+
+		listExecutorPods := func() []string {
+			pods := &corev1.PodList{}
+			Expect(k8sClient.List(ctx, pods, client.InNamespace(appNamespace), client.MatchingLabels{
+				common.LabelSparkAppName: appName,
+				common.LabelSparkRole:    common.SparkRoleExecutor,
+			})).To(Succeed())
+			var names []string
+			for _, pod := range pods.Items {
+				names = append(names, pod.Name)
+			}
+			return names
+		}
+
+		BeforeEach(func() {
+			By("Creating the secret mounted into the executors")
+			secret := &corev1.Secret{
+				ObjectMeta: metav1.ObjectMeta{Name: secretKey.Name, Namespace: secretKey.Namespace},
+				Data:       map[string][]byte{"password": []byte("v1")},
+			}
+			Expect(k8sClient.Create(ctx, secret)).To(Succeed())
+
+			By("Creating a test SparkApplication")
+			app := &v1beta2.SparkApplication{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      appName,
+					Namespace: appNamespace,
+				},
+				Spec: v1beta2.SparkApplicationSpec{
+					MainApplicationFile: util.StringPtr("local:///dummy.jar"),
+					Executor: v1beta2.ExecutorSpec{
+						SparkPodSpec: v1beta2.SparkPodSpec{
+							Secrets: []v1beta2.SecretInfo{{Name: secretKey.Name, Path: "/etc/credentials"}},
+						},
+					},
+					SecretRotation: &v1beta2.SecretRotationSpec{
+						BatchSize:            ptr.To[int32](2),
+						BatchIntervalSeconds: ptr.To[int64](3600),
+					},
+				},
+			}
+			v1beta2.SetSparkApplicationDefaults(app)
+			Expect(k8sClient.Create(ctx, app)).To(Succeed())
+		})
+
+		AfterEach(func() {
+			app := &v1beta2.SparkApplication{}
+			Expect(k8sClient.Get(ctx, key, app)).To(Succeed())
+
+			By("Deleting the created test SparkApplication")
+			Expect(k8sClient.Delete(ctx, app)).To(Succeed())
+
+			By("Deleting the pods and secrets of the SparkApplication")
+			selector := client.MatchingLabels{common.LabelSparkAppName: appName}
+			Expect(k8sClient.DeleteAllOf(ctx, &corev1.Pod{}, client.InNamespace(appNamespace), selector)).To(Succeed())
+			Expect(k8sClient.DeleteAllOf(ctx, &corev1.Secret{}, client.InNamespace(appNamespace), selector)).To(Succeed())
+			Expect(k8sClient.Delete(ctx, &corev1.Secret{ObjectMeta: metav1.ObjectMeta{Name: secretKey.Name, Namespace: secretKey.Namespace}})).To(Succeed())
+		})
+
+		It("Should restart the executors created before the secrets changed in batches", func() {
+			By("Running the SparkApplication with 3 executors")
+			driverPod := createDriverPod(appName, appNamespace)
+			Expect(k8sClient.Create(ctx, driverPod)).To(Succeed())
+			driverPod.Status.Phase = corev1.PodRunning
+			Expect(k8sClient.Status().Update(ctx, driverPod)).To(Succeed())
+			for id := 1; id <= 3; id++ {
+				executorPod := createExecutorPod(appName, appNamespace, id)
+				Expect(k8sClient.Create(ctx, executorPod)).To(Succeed())
+				executorPod.Status.Phase = corev1.PodRunning
+				Expect(k8sClient.Status().Update(ctx, executorPod)).To(Succeed())
+			}
+			app := &v1beta2.SparkApplication{}
+			Expect(k8sClient.Get(ctx, key, app)).To(Succeed())
+			app.Status.DriverInfo.PodName = driverPod.Name
+			app.Status.AppState.State = v1beta2.ApplicationStateRunning
+			Expect(k8sClient.Status().Update(ctx, app)).To(Succeed())
+
+			By("Recording the hash of the secrets the executors were created with")
+			reconciler := newReconciler(sparkapplication.Options{Executor: sparkapplication.ExecutorOptions{EnableSecretRotation: true}})
+			result, err := reconciler.Reconcile(ctx, reconcile.Request{NamespacedName: key})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(result.RequeueAfter).To(Equal(time.Hour))
+			Expect(k8sClient.Get(ctx, key, app)).To(Succeed())
+			Expect(app.Status.SecretRotation).NotTo(BeNil())
+			Expect(app.Status.SecretRotation.SecretHash).NotTo(BeEmpty())
+			Expect(app.Status.SecretRotation.RolloutStartTime).To(BeNil())
+			Expect(listExecutorPods()).To(HaveLen(3))
+
+			By("Rotating the secret")
+			secret := &corev1.Secret{}
+			Expect(k8sClient.Get(ctx, secretKey, secret)).To(Succeed())
+			secret.Data["password"] = []byte("v2")
+			Expect(k8sClient.Update(ctx, secret)).To(Succeed())
+			result, err = reconciler.Reconcile(ctx, reconcile.Request{NamespacedName: key})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(result.RequeueAfter).To(Equal(5 * time.Second))
+			Expect(k8sClient.Get(ctx, key, app)).To(Succeed())
+			Expect(app.Status.SecretRotation.RolloutStartTime).NotTo(BeNil())
+			// The new hash is published before any executor is restarted, so that their replacements record it.
+			Expect(listExecutorPods()).To(HaveLen(3))
+
+			By("Restarting the first batch of executors created in the same second the rollout started")
+			pod := &corev1.Pod{}
+			Expect(k8sClient.Get(ctx, types.NamespacedName{Namespace: appNamespace, Name: listExecutorPods()[0]}, pod)).To(Succeed())
+			app.Status.SecretRotation.RolloutStartTime = ptr.To(pod.CreationTimestamp)
+			Expect(k8sClient.Status().Update(ctx, app)).To(Succeed())
+			_, err = reconciler.Reconcile(ctx, reconcile.Request{NamespacedName: key})
+			Expect(err).NotTo(HaveOccurred())
+			remaining := listExecutorPods()
+			Expect(remaining).To(HaveLen(1))
+
+			By("Waiting for the batch interval before restarting the next batch")
+			// The webhook records the published hash on the executors replacing the restarted ones.
+			replacement := createExecutorPod(appName, appNamespace, 4)
+			replacement.Annotations = map[string]string{common.AnnotationSecretHash: app.Status.SecretRotation.SecretHash}
+			Expect(k8sClient.Create(ctx, replacement)).To(Succeed())
+			replacement.Status.Phase = corev1.PodRunning
+			Expect(k8sClient.Status().Update(ctx, replacement)).To(Succeed())
+			_, err = reconciler.Reconcile(ctx, reconcile.Request{NamespacedName: key})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(listExecutorPods()).To(ConsistOf(remaining[0], replacement.Name))
+
+			By("Restarting the next batch once the batch interval elapsed")
+			Expect(k8sClient.Get(ctx, key, app)).To(Succeed())
+			app.Status.SecretRotation.LastBatchTime = ptr.To(metav1.NewTime(time.Now().Add(-2 * time.Hour)))
+			Expect(k8sClient.Status().Update(ctx, app)).To(Succeed())
+			_, err = reconciler.Reconcile(ctx, reconcile.Request{NamespacedName: key})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(listExecutorPods()).To(ConsistOf(replacement.Name))
+
+			By("Completing the rollout once all executors were replaced")
+			_, err = reconciler.Reconcile(ctx, reconcile.Request{NamespacedName: key})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(k8sClient.Get(ctx, key, app)).To(Succeed())
+			Expect(app.Status.SecretRotation.RolloutStartTime).To(BeNil())
+			Expect(listExecutorPods()).To(ConsistOf(replacement.Name))
+		})
+
+		It("Should generate a new authentication secret for every run", func() {
+			reconciler := newReconciler(sparkapplication.Options{EnableSparkAuthSecret: true, Executor: sparkapplication.ExecutorOptions{EnableSecretRotation: true}})
+			app := &v1beta2.SparkApplication{}
+			Expect(k8sClient.Get(ctx, key, app)).To(Succeed())
+			authSecretKey := types.NamespacedName{Namespace: appNamespace, Name: util.GetSparkAuthSecretName(app)}
+
+			By("Submitting the SparkApplication")
+			_, err := reconciler.Reconcile(ctx, reconcile.Request{NamespacedName: key})
+			Expect(err).NotTo(HaveOccurred())
+			first := &corev1.Secret{}
+			Expect(k8sClient.Get(ctx, authSecretKey, first)).To(Succeed())
+
+			By("Submitting the SparkApplication again")
+			Expect(k8sClient.Get(ctx, key, app)).To(Succeed())
+			app.Status.AppState.State = v1beta2.ApplicationStateNew
+			Expect(k8sClient.Status().Update(ctx, app)).To(Succeed())
+			_, err = reconciler.Reconcile(ctx, reconcile.Request{NamespacedName: key})
+			Expect(err).NotTo(HaveOccurred())
+			second := &corev1.Secret{}
+			Expect(k8sClient.Get(ctx, authSecretKey, second)).To(Succeed())
+			Expect(second.Data).NotTo(Equal(first.Data))
+		})
+
+		It("Should never restart the executors for the generated authentication secret", func() {
+			app := &v1beta2.SparkApplication{}
+			Expect(k8sClient.Get(ctx, key, app)).To(Succeed())
+			authSecret := &corev1.Secret{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      util.GetSparkAuthSecretName(app),
+					Namespace: appNamespace,
+					Labels:    map[string]string{common.LabelSparkAppName: appName},
+				},
+				Data: map[string][]byte{common.SparkAuthSecretKey: []byte("v1")},
+			}
+			Expect(k8sClient.Create(ctx, authSecret)).To(Succeed())
+			app.Spec.Executor.Secrets = append(app.Spec.Executor.Secrets, v1beta2.SecretInfo{Name: authSecret.Name, Path: common.SparkAuthSecretMountPath})
+			Expect(k8sClient.Update(ctx, app)).To(Succeed())
+
+			By("Running the SparkApplication with 2 executors")
+			driverPod := createDriverPod(appName, appNamespace)
+			Expect(k8sClient.Create(ctx, driverPod)).To(Succeed())
+			driverPod.Status.Phase = corev1.PodRunning
+			Expect(k8sClient.Status().Update(ctx, driverPod)).To(Succeed())
+			for id := 1; id <= 2; id++ {
+				executorPod := createExecutorPod(appName, appNamespace, id)
+				Expect(k8sClient.Create(ctx, executorPod)).To(Succeed())
+				executorPod.Status.Phase = corev1.PodRunning
+				Expect(k8sClient.Status().Update(ctx, executorPod)).To(Succeed())
+			}
+			app.Status.DriverInfo.PodName = driverPod.Name
+			app.Status.AppState.State = v1beta2.ApplicationStateRunning
+			Expect(k8sClient.Status().Update(ctx, app)).To(Succeed())
+
+			reconciler := newReconciler(sparkapplication.Options{Executor: sparkapplication.ExecutorOptions{EnableSecretRotation: true}})
+			_, err := reconciler.Reconcile(ctx, reconcile.Request{NamespacedName: key})
+			Expect(err).NotTo(HaveOccurred())
+
+			By("Rotating the authentication secret")
+			// Executors restarted with a new authentication secret could not authenticate with the driver.
+			authSecret.Data[common.SparkAuthSecretKey] = []byte("v2")
+			Expect(k8sClient.Update(ctx, authSecret)).To(Succeed())
+			for range 2 {
+				_, err = reconciler.Reconcile(ctx, reconcile.Request{NamespacedName: key})
+				Expect(err).NotTo(HaveOccurred())
+			}
+			Expect(listExecutorPods()).To(HaveLen(2))
+			Expect(k8sClient.Get(ctx, key, app)).To(Succeed())
+			Expect(app.Status.SecretRotation).NotTo(BeNil())
+			Expect(app.Status.SecretRotation.RolloutStartTime).To(BeNil())
+		})
+	})
+	Context("When the namespace of SparkApplications is being terminated", func() {
+		ctx := context.Background()
+		appNamespace := "terminating-namespace"
+		runningKey := types.NamespacedName{Name: "test-running", Namespace: appNamespace}
+		completedKey := types.NamespacedName{Name: "test-completed", Namespace: appNamespace}
+
+		BeforeEach(func() {
+			By("Creating the namespace of the test SparkApplications")
+			namespace := &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: appNamespace}}
+			Expect(k8sClient.Create(ctx, namespace)).To(Succeed())
+
+			By("Creating a running and a completed test SparkApplication")
+			for key, state := range map[types.NamespacedName]v1beta2.ApplicationStateType{
+				runningKey:   v1beta2.ApplicationStateRunning,
+				completedKey: v1beta2.ApplicationStateCompleted,
+			} {
+				app := &v1beta2.SparkApplication{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:      key.Name,
+						Namespace: key.Namespace,
+					},
+					Spec: v1beta2.SparkApplicationSpec{
+						MainApplicationFile: util.StringPtr("local:///dummy.jar"),
+					},
+				}
+				v1beta2.SetSparkApplicationDefaults(app)
+				Expect(k8sClient.Create(ctx, app)).To(Succeed())
+				app.Status.AppState.State = state
+				app.Status.DriverInfo.PodName = getDriverNamespacedName(key.Name, key.Namespace).Name
+				Expect(k8sClient.Status().Update(ctx, app)).To(Succeed())
+			}
+
+			driverPod := createDriverPod(runningKey.Name, appNamespace)
+			Expect(k8sClient.Create(ctx, driverPod)).To(Succeed())
+			driverPod.Status.Phase = corev1.PodRunning
+			Expect(k8sClient.Status().Update(ctx, driverPod)).To(Succeed())
+		})
+
+		AfterEach(func() {
+			By("Deleting the created test SparkApplications")
+			Expect(k8sClient.DeleteAllOf(ctx, &v1beta2.SparkApplication{}, client.InNamespace(appNamespace))).To(Succeed())
+			Expect(k8sClient.DeleteAllOf(ctx, &corev1.Pod{}, client.InNamespace(appNamespace))).To(Succeed())
+		})
+
+		It("Should fail the running SparkApplications without retries", func() {
+			By("Starting a manager running the controller")
+			mgr, err := ctrl.NewManager(cfg, ctrl.Options{
+				Scheme:  k8sClient.Scheme(),
+				Metrics: metricsserver.Options{BindAddress: "0"},
+			})
+			Expect(err).NotTo(HaveOccurred())
+			reconciler := sparkapplication.NewReconciler(
+				mgr,
+				k8sClient.Scheme(),
+				mgr.GetClient(),
+				record.NewFakeRecorder(100),
+				nil,
+				sparkapplication.Options{Namespaces: []string{appNamespace}, EnableNamespaceTerminationHandling: true},
+			)
+			Expect(reconciler.SetupWithManager(mgr, controller.Options{SkipNameValidation: ptr.To(true)})).To(Succeed())
+			mgrCtx, cancel := context.WithCancel(ctx)
+			defer cancel()
+			go func() {
+				defer GinkgoRecover()
+				Expect(mgr.Start(mgrCtx)).To(Succeed())
+			}()
+
+			getState := func(key types.NamespacedName) func() v1beta2.ApplicationStateType {
+				return func() v1beta2.ApplicationStateType {
+					app := &v1beta2.SparkApplication{}
+					Expect(k8sClient.Get(ctx, key, app)).To(Succeed())
+					return app.Status.AppState.State
+				}
+			}
+			Consistently(getState(runningKey)).WithTimeout(time.Second).Should(Equal(v1beta2.ApplicationStateRunning))
+
+			By("Deleting the namespace")
+			// There is no namespace controller in the test environment, so the namespace stays terminating.
+			namespace := &corev1.Namespace{}
+			Expect(k8sClient.Get(ctx, types.NamespacedName{Name: appNamespace}, namespace)).To(Succeed())
+			Expect(k8sClient.Delete(ctx, namespace)).To(Succeed())
+
+			Eventually(getState(runningKey)).WithTimeout(10 * time.Second).Should(Equal(v1beta2.ApplicationStateFailed))
+			app := &v1beta2.SparkApplication{}
+			Expect(k8sClient.Get(ctx, runningKey, app)).To(Succeed())
+			Expect(app.Status.AppState.ErrorMessage).To(Equal(fmt.Sprintf("namespace %s is being terminated", appNamespace)))
+			Expect(app.Status.TerminationTime.IsZero()).To(BeFalse())
+			Expect(getState(completedKey)()).To(Equal(v1beta2.ApplicationStateCompleted))
+
+			By("Checking that the driver pod was left to the namespace deletion")
+			Expect(k8sClient.Get(ctx, getDriverNamespacedName(runningKey.Name, appNamespace), &corev1.Pod{})).To(Succeed())
+		})
+	})
 })
 
 func getDriverNamespacedName(appName string, appNamespace string) types.NamespacedName {
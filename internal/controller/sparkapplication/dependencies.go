@@ -0,0 +1,271 @@
+/*
+Copyright 2024 The Kubeflow authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package sparkapplication
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/util/retry"
+	"k8s.io/client-go/util/workqueue"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+
+	"github.com/kubeflow/spark-operator/api/v1beta2"
+	"github.com/kubeflow/spark-operator/pkg/util"
+)
+
+const (
+	// defaultDependencyTimeout is the default maximum time to wait for the dependencies of an application.
+	defaultDependencyTimeout = 600 * time.Second
+
+	// dependencyPollInterval is the interval at which the readiness of the dependencies and the existence of
+	// referenced Secrets are checked. Keep the --enable-waiting-for-dependencies flag help in sync.
+	dependencyPollInterval = 10 * time.Second
+
+	// dependencyConditionReady is the condition type that is also satisfied by workloads reporting ready replicas.
+	dependencyConditionReady = "Ready"
+)
+
+// checkDependencies returns whether the resources listed in `spec.waitFor` of the SparkApplication are ready, and
+// records the outcome in the DependenciesReady condition. An error is returned if the dependencies did not become
+// ready within the timeout, which is measured from the time the application started waiting for them.
+func (r *Reconciler) checkDependencies(ctx context.Context, app *v1beta2.SparkApplication) (bool, error) {
+	var pending []string
+	for _, dependency := range app.Spec.WaitFor.Resources {
+		ready, reason, err := r.isDependencyReady(ctx, app.Namespace, dependency)
+		if err != nil {
+			appLogger(app).Error(err, "Failed to check dependency", "kind", dependency.Kind, "dependency", dependency.Name)
+		}
+		if !ready {
+			pending = append(pending, fmt.Sprintf("%s %s %s", dependency.Kind, dependency.Name, reason))
+		}
+	}
+
+	if len(pending) == 0 {
+		meta.SetStatusCondition(&app.Status.Conditions, metav1.Condition{
+			Type:               string(v1beta2.SparkApplicationConditionDependenciesReady),
+			Status:             metav1.ConditionTrue,
+			ObservedGeneration: app.Generation,
+			Reason:             "Ready",
+		})
+		return true, nil
+	}
+
+	message := strings.Join(pending, "; ")
+	meta.SetStatusCondition(&app.Status.Conditions, metav1.Condition{
+		Type:               string(v1beta2.SparkApplicationConditionDependenciesReady),
+		Status:             metav1.ConditionFalse,
+		ObservedGeneration: app.Generation,
+		Reason:             "Waiting",
+		Message:            message,
+	})
+
+	timeout := defaultDependencyTimeout
+	if app.Spec.WaitFor.TimeoutSeconds != nil {
+		timeout = time.Duration(*app.Spec.WaitFor.TimeoutSeconds) * time.Second
+	}
+	condition := meta.FindStatusCondition(app.Status.Conditions, string(v1beta2.SparkApplicationConditionDependenciesReady))
+	if time.Since(condition.LastTransitionTime.Time) >= timeout {
+		return false, fmt.Errorf("timed out waiting for dependencies: %s", message)
+	}
+	return false, nil
+}
+
+// isDependencyReady returns whether the given resource is ready, and the reason if it is not.
+func (r *Reconciler) isDependencyReady(ctx context.Context, namespace string, dependency v1beta2.ResourceDependency) (bool, string, error) {
+	apiVersion := dependency.APIVersion
+	if apiVersion == "" {
+		apiVersion = "v1"
+	}
+	gv, err := schema.ParseGroupVersion(apiVersion)
+	if err != nil {
+		return false, "has an invalid API version", fmt.Errorf("failed to parse API version %s: %v", apiVersion, err)
+	}
+
+	obj := &unstructured.Unstructured{}
+	obj.SetGroupVersionKind(gv.WithKind(dependency.Kind))
+	if err := r.manager.GetAPIReader().Get(ctx, client.ObjectKey{Namespace: namespace, Name: dependency.Name}, obj); err != nil {
+		if errors.IsNotFound(err) {
+			return false, "does not exist", nil
+		}
+		return false, "cannot be read", fmt.Errorf("failed to get %s %s: %v", dependency.Kind, dependency.Name, err)
+	}
+
+	if dependency.Condition == nil || *dependency.Condition == "" {
+		return true, "", nil
+	}
+	if isConditionTrue(obj, *dependency.Condition) {
+		return true, "", nil
+	}
+	return false, fmt.Sprintf("is not %s", *dependency.Condition), nil
+}
+
+// isConditionTrue returns whether the status condition of the given type of the object is true. A condition of
+// type Ready is also satisfied by workloads without such condition once all their replicas are ready.
+func isConditionTrue(obj *unstructured.Unstructured, conditionType string) bool {
+	conditions, found, _ := unstructured.NestedSlice(obj.Object, "status", "conditions")
+	if found {
+		for _, c := range conditions {
+			condition, ok := c.(map[string]interface{})
+			if !ok || condition["type"] != conditionType {
+				continue
+			}
+			return condition["status"] == string(metav1.ConditionTrue)
+		}
+	}
+
+	if conditionType != dependencyConditionReady {
+		return false
+	}
+	readyReplicas, found, _ := unstructured.NestedInt64(obj.Object, "status", "readyReplicas")
+	if !found {
+		return false
+	}
+	replicas, found, _ := unstructured.NestedInt64(obj.Object, "spec", "replicas")
+	if !found {
+		replicas = 1
+	}
+	observedGeneration, _, _ := unstructured.NestedInt64(obj.Object, "status", "observedGeneration")
+	return observedGeneration >= obj.GetGeneration() && readyReplicas >= replicas
+}
+
+// reconcileWaitingForDependenciesSparkApplication moves the SparkApplication back to the new state, which submits
+// it, once the Secrets, ConfigMaps and service accounts it references exist.
+func (r *Reconciler) reconcileWaitingForDependenciesSparkApplication(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	key := req.NamespacedName
+	waiting := false
+	retryErr := retry.RetryOnConflict(
+		retry.DefaultRetry,
+		func() error {
+			old, err := r.getSparkApplication(ctx, key)
+			if err != nil {
+				return err
+			}
+			if old.Status.AppState.State != v1beta2.ApplicationStateWaitingForDependencies {
+				return nil
+			}
+			app := old.DeepCopy()
+
+			missing, err := r.getMissingReferencedObjects(ctx, app)
+			if err != nil {
+				return err
+			}
+			if len(missing) > 0 {
+				waiting = true
+				app.Status.AppState.ErrorMessage = getMissingReferencedObjectsMessage(missing)
+				if equality.Semantic.DeepEqual(old.Status, app.Status) {
+					return nil
+				}
+			} else {
+				appLogger(app).Info("Referenced objects of SparkApplication exist, enqueuing it for submission")
+				app.Status.AppState = v1beta2.ApplicationState{State: v1beta2.ApplicationStateNew}
+			}
+			return r.updateSparkApplicationStatus(ctx, old, app)
+		},
+	)
+	if retryErr != nil {
+		logger.Error(retryErr, "Failed to reconcile SparkApplication", "name", key.Name, "namespace", key.Namespace)
+		return ctrl.Result{}, retryErr
+	}
+	// Secrets not created by the operator are not watched, so the referenced objects are also checked periodically.
+	if waiting {
+		return ctrl.Result{RequeueAfter: dependencyPollInterval}, nil
+	}
+	return ctrl.Result{}, nil
+}
+
+// getMissingReferencedObjects returns the Secrets, ConfigMaps and service accounts referenced by the SparkApplication
+// which do not exist.
+func (r *Reconciler) getMissingReferencedObjects(ctx context.Context, app *v1beta2.SparkApplication) ([]corev1.ObjectReference, error) {
+	var missing []corev1.ObjectReference
+	for _, reference := range util.GetReferencedObjects(app) {
+		obj := &metav1.PartialObjectMetadata{}
+		obj.SetGroupVersionKind(corev1.SchemeGroupVersion.WithKind(reference.Kind))
+		if err := r.manager.GetAPIReader().Get(ctx, client.ObjectKey{Namespace: app.Namespace, Name: reference.Name}, obj); err != nil {
+			if errors.IsNotFound(err) {
+				missing = append(missing, reference)
+				continue
+			}
+			return nil, fmt.Errorf("failed to get %s %s: %v", reference.Kind, reference.Name, err)
+		}
+	}
+	return missing, nil
+}
+
+// getMissingReferencedObjectsMessage returns a message listing the given missing objects.
+func getMissingReferencedObjectsMessage(missing []corev1.ObjectReference) string {
+	names := make([]string, 0, len(missing))
+	for _, reference := range missing {
+		names = append(names, fmt.Sprintf("%s %s", reference.Kind, reference.Name))
+	}
+	return fmt.Sprintf("missing %s", strings.Join(names, ", "))
+}
+
+// referencedObjectEventHandler enqueues the SparkApplications waiting for dependencies in the namespace of objects
+// being created.
+type referencedObjectEventHandler struct {
+	client client.Client
+}
+
+// referencedObjectEventHandler implements handler.EventHandler.
+var _ handler.EventHandler = &referencedObjectEventHandler{}
+
+// newReferencedObjectEventHandler creates a new referencedObjectEventHandler instance.
+func newReferencedObjectEventHandler(client client.Client) *referencedObjectEventHandler {
+	return &referencedObjectEventHandler{client: client}
+}
+
+// Create implements handler.EventHandler.
+func (h *referencedObjectEventHandler) Create(ctx context.Context, event event.CreateEvent, queue workqueue.TypedRateLimitingInterface[ctrl.Request]) {
+	namespace := event.Object.GetNamespace()
+	apps := &v1beta2.SparkApplicationList{}
+	if err := h.client.List(ctx, apps, client.InNamespace(namespace)); err != nil {
+		logger.Error(err, "Failed to list SparkApplications waiting for dependencies", "namespace", namespace)
+		return
+	}
+
+	for _, app := range apps.Items {
+		if app.Status.AppState.State == v1beta2.ApplicationStateWaitingForDependencies {
+			queue.Add(ctrl.Request{NamespacedName: types.NamespacedName{Namespace: app.Namespace, Name: app.Name}})
+		}
+	}
+}
+
+// Update implements handler.EventHandler.
+func (h *referencedObjectEventHandler) Update(ctx context.Context, event event.UpdateEvent, queue workqueue.TypedRateLimitingInterface[ctrl.Request]) {
+}
+
+// Delete implements handler.EventHandler.
+func (h *referencedObjectEventHandler) Delete(ctx context.Context, event event.DeleteEvent, queue workqueue.TypedRateLimitingInterface[ctrl.Request]) {
+}
+
+// Generic implements handler.EventHandler.
+func (h *referencedObjectEventHandler) Generic(ctx context.Context, event event.GenericEvent, queue workqueue.TypedRateLimitingInterface[ctrl.Request]) {
+}
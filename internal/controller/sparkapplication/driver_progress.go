@@ -0,0 +1,160 @@
+/*
+Copyright 2024 The Kubeflow authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package sparkapplication
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/kubeflow/spark-operator/api/v1beta2"
+	"github.com/kubeflow/spark-operator/pkg/util"
+)
+
+const (
+	// driverProgressTimeout is the timeout of each request scraping the progress of a driver.
+	driverProgressTimeout = 5 * time.Second
+
+	// targetExecutorsGaugeSuffix is the suffix of the name of the gauge of the number of executors dynamic
+	// allocation targets in the metrics of the driver.
+	targetExecutorsGaugeSuffix = ".ExecutorAllocationManager.executors.numberTargetExecutors"
+)
+
+// driverProgressClient is the HTTP client scraping the progress of drivers.
+var driverProgressClient = &http.Client{Timeout: driverProgressTimeout}
+
+// sparkStage is a stage returned by the REST API of the driver.
+type sparkStage struct {
+	StageID int `json:"stageId"`
+}
+
+// sparkExecutor is an executor returned by the REST API of the driver.
+type sparkExecutor struct {
+	ID       string `json:"id"`
+	IsActive bool   `json:"isActive"`
+}
+
+// sparkMetrics are the metrics returned by the JSON metrics servlet of the driver.
+type sparkMetrics struct {
+	Gauges map[string]struct {
+		Value json.Number `json:"value"`
+	} `json:"gauges"`
+}
+
+// scrapeDriverProgress records the progress of the running app scraped from its driver in the status, unless it was
+// recorded less than the scrape interval ago.
+func (r *Reconciler) scrapeDriverProgress(ctx context.Context, app *v1beta2.SparkApplication) error {
+	if progress := app.Status.DriverProgress; progress != nil && time.Since(progress.UpdateTime.Time) < r.options.DriverProgressScrapeInterval {
+		return nil
+	}
+	if app.Status.SparkApplicationID == "" {
+		return nil
+	}
+
+	pod := &corev1.Pod{}
+	if err := r.client.Get(ctx, client.ObjectKey{Namespace: app.Namespace, Name: util.GetDriverPodName(app)}, pod); err != nil {
+		return fmt.Errorf("failed to get driver pod: %v", err)
+	}
+	if pod.Status.PodIP == "" {
+		return nil
+	}
+
+	baseURL := fmt.Sprintf("http://%s", net.JoinHostPort(pod.Status.PodIP, strconv.Itoa(int(util.GetWebUIPort(app)))))
+	progress, err := getDriverProgress(ctx, baseURL, app.Status.SparkApplicationID)
+	if err != nil {
+		return err
+	}
+	app.Status.DriverProgress = progress
+	return nil
+}
+
+// getDriverProgress scrapes the progress of the Spark application with the given ID from the REST API and the JSON
+// metrics servlet of the driver listening at the given base URL.
+func getDriverProgress(ctx context.Context, baseURL string, appID string) (*v1beta2.DriverProgress, error) {
+	apiURL := fmt.Sprintf("%s/api/v1/applications/%s", baseURL, url.PathEscape(appID))
+
+	var activeStages, pendingStages []sparkStage
+	if err := getDriverJSON(ctx, apiURL+"/stages?status=active", &activeStages); err != nil {
+		return nil, err
+	}
+	if err := getDriverJSON(ctx, apiURL+"/stages?status=pending", &pendingStages); err != nil {
+		return nil, err
+	}
+	var executors []sparkExecutor
+	if err := getDriverJSON(ctx, apiURL+"/executors", &executors); err != nil {
+		return nil, err
+	}
+
+	progress := &v1beta2.DriverProgress{
+		ActiveStages:  int32(len(activeStages)),
+		PendingStages: int32(len(pendingStages)),
+		UpdateTime:    metav1.Now(),
+	}
+	for _, executor := range executors {
+		if executor.ID != "driver" && executor.IsActive {
+			progress.ActiveExecutors++
+		}
+	}
+
+	// The metrics servlet is optional, so failing to read the number of target executors is not an error.
+	metrics := &sparkMetrics{}
+	if err := getDriverJSON(ctx, baseURL+"/metrics/json/", metrics); err == nil {
+		for name, gauge := range metrics.Gauges {
+			if !strings.HasSuffix(name, targetExecutorsGaugeSuffix) {
+				continue
+			}
+			if value, err := gauge.Value.Int64(); err == nil {
+				target := int32(value)
+				progress.TargetExecutors = &target
+			}
+		}
+	}
+	return progress, nil
+}
+
+// getDriverJSON gets the given URL of the driver and decodes the JSON response into v.
+func getDriverJSON(ctx context.Context, url string, v any) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return fmt.Errorf("failed to create request: %v", err)
+	}
+	resp, err := driverProgressClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to get %s: %v", url, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		return fmt.Errorf("failed to get %s: status %d: %s", url, resp.StatusCode, string(body))
+	}
+	if err := json.NewDecoder(resp.Body).Decode(v); err != nil {
+		return fmt.Errorf("failed to decode response of %s: %v", url, err)
+	}
+	return nil
+}
@@ -0,0 +1,89 @@
+/*
+Copyright 2024 The Kubeflow authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package sparkapplication
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"k8s.io/utils/ptr"
+)
+
+// newTestDriverServer returns a server serving the REST API of a driver running the Spark application spark-123,
+// as well as its JSON metrics servlet if withMetrics is true.
+func newTestDriverServer(t *testing.T, withMetrics bool) *httptest.Server {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/api/v1/applications/spark-123/stages", func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Query().Get("status") {
+		case "active":
+			fmt.Fprint(w, `[{"stageId": 3}, {"stageId": 4}]`)
+		case "pending":
+			fmt.Fprint(w, `[{"stageId": 5}]`)
+		default:
+			w.WriteHeader(http.StatusBadRequest)
+		}
+	})
+	mux.HandleFunc("/api/v1/applications/spark-123/executors", func(w http.ResponseWriter, _ *http.Request) {
+		fmt.Fprint(w, `[{"id": "driver", "isActive": true}, {"id": "1", "isActive": true}, {"id": "2", "isActive": false}]`)
+	})
+	if withMetrics {
+		mux.HandleFunc("/metrics/json/", func(w http.ResponseWriter, _ *http.Request) {
+			fmt.Fprint(w, `{"gauges": {
+				"spark-123.driver.ExecutorAllocationManager.executors.numberTargetExecutors": {"value": 8},
+				"spark-123.driver.BlockManager.memory.memUsed_MB": {"value": 12}
+			}}`)
+		})
+	}
+	server := httptest.NewServer(mux)
+	t.Cleanup(server.Close)
+	return server
+}
+
+func TestGetDriverProgress(t *testing.T) {
+	t.Run("with metrics", func(t *testing.T) {
+		server := newTestDriverServer(t, true)
+
+		progress, err := getDriverProgress(context.Background(), server.URL, "spark-123")
+		require.NoError(t, err)
+		assert.Equal(t, int32(2), progress.ActiveStages)
+		assert.Equal(t, int32(1), progress.PendingStages)
+		assert.Equal(t, int32(1), progress.ActiveExecutors)
+		assert.Equal(t, ptr.To[int32](8), progress.TargetExecutors)
+		assert.False(t, progress.UpdateTime.IsZero())
+	})
+
+	t.Run("without metrics", func(t *testing.T) {
+		server := newTestDriverServer(t, false)
+
+		progress, err := getDriverProgress(context.Background(), server.URL, "spark-123")
+		require.NoError(t, err)
+		assert.Equal(t, int32(2), progress.ActiveStages)
+		assert.Nil(t, progress.TargetExecutors)
+	})
+
+	t.Run("unknown application", func(t *testing.T) {
+		server := newTestDriverServer(t, true)
+
+		_, err := getDriverProgress(context.Background(), server.URL, "spark-456")
+		assert.ErrorContains(t, err, "status 404")
+	})
+}
@@ -0,0 +1,63 @@
+/*
+Copyright 2024 The Kubeflow authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package sparkapplication
+
+import (
+	"context"
+	"fmt"
+
+	corev1 "k8s.io/api/core/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/kubeflow/spark-operator/api/v1beta2"
+	"github.com/kubeflow/spark-operator/pkg/common"
+	"github.com/kubeflow/spark-operator/pkg/util"
+)
+
+// configDriverService sets publishNotReadyAddresses on the headless service created by Spark for the driver if
+// the app requests it, as Spark provides no configuration for it.
+func (r *Reconciler) configDriverService(ctx context.Context, app *v1beta2.SparkApplication) error {
+	if !util.DriverServicePublishNotReadyAddresses(app) {
+		return nil
+	}
+
+	services := &corev1.ServiceList{}
+	if err := r.client.List(
+		ctx,
+		services,
+		client.InNamespace(app.Namespace),
+		client.MatchingLabels{common.LabelSparkAppName: app.Name},
+	); err != nil {
+		return fmt.Errorf("failed to list services: %v", err)
+	}
+
+	for i := range services.Items {
+		service := &services.Items[i]
+		// Skip the web UI service, which is not headless.
+		if service.Spec.ClusterIP != corev1.ClusterIPNone || service.Spec.PublishNotReadyAddresses {
+			continue
+		}
+
+		patch := client.MergeFrom(service.DeepCopy())
+		service.Spec.PublishNotReadyAddresses = true
+		logger.Info("Publishing not-ready addresses of driver service", "name", service.Name, "namespace", service.Namespace)
+		if err := r.client.Patch(ctx, service, patch); err != nil {
+			return fmt.Errorf("failed to patch service %s/%s: %v", service.Namespace, service.Name, err)
+		}
+	}
+	return nil
+}
@@ -19,18 +19,24 @@ package sparkapplication
 import (
 	"context"
 	"fmt"
+	"maps"
 	"net/url"
 	"regexp"
 
 	corev1 "k8s.io/api/core/v1"
 	extensionsv1beta1 "k8s.io/api/extensions/v1beta1"
 	networkingv1 "k8s.io/api/networking/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
 	"k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/util/intstr"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"k8s.io/utils/ptr"
 
 	"github.com/kubeflow/spark-operator/api/v1beta2"
 	"github.com/kubeflow/spark-operator/pkg/common"
+	"github.com/kubeflow/spark-operator/pkg/naming"
 	"github.com/kubeflow/spark-operator/pkg/util"
 )
 
@@ -74,18 +80,18 @@ func getDriverIngressURL(ingressURLFormat string, appName string, appNamespace s
 	return parsedURL, nil
 }
 
-func (r *Reconciler) createDriverIngress(app *v1beta2.SparkApplication, driverIngressConfiguration *v1beta2.DriverIngressConfiguration, service SparkService, ingressURL *url.URL, ingressClassName string) (*SparkIngress, error) {
+func (r *Reconciler) createDriverIngress(ctx context.Context, app *v1beta2.SparkApplication, driverIngressConfiguration *v1beta2.DriverIngressConfiguration, service SparkService, ingressURL *url.URL, ingressClassName string) (*SparkIngress, error) {
 	if driverIngressConfiguration.ServicePort == nil {
 		return nil, fmt.Errorf("cannot create Driver Ingress for application %s/%s due to empty ServicePort on driverIngressConfiguration", app.Namespace, app.Name)
 	}
-	ingressName := fmt.Sprintf("%s-ing-%d", app.Name, *driverIngressConfiguration.ServicePort)
+	ingressName := naming.DriverIngressName(app, *driverIngressConfiguration.ServicePort)
 	if util.IngressCapabilities.Has("networking.k8s.io/v1") {
-		return r.createDriverIngressV1(app, service, ingressName, ingressURL, ingressClassName)
+		return r.createDriverIngressV1(ctx, app, service, ingressName, ingressURL, ingressClassName)
 	}
-	return r.createDriverIngressLegacy(app, service, ingressName, ingressURL)
+	return r.createDriverIngressLegacy(ctx, app, service, ingressName, ingressURL)
 }
 
-func (r *Reconciler) createDriverIngressV1(app *v1beta2.SparkApplication, service SparkService, ingressName string, ingressURL *url.URL, ingressClassName string) (*SparkIngress, error) {
+func (r *Reconciler) createDriverIngressV1(ctx context.Context, app *v1beta2.SparkApplication, service SparkService, ingressName string, ingressURL *url.URL, ingressClassName string) (*SparkIngress, error) {
 	ingressResourceAnnotations := util.GetWebUIIngressAnnotations(app)
 	ingressTLSHosts := util.GetWebUIIngressTLS(app)
 
@@ -102,7 +108,7 @@ func (r *Reconciler) createDriverIngressV1(app *v1beta2.SparkApplication, servic
 			Name:            ingressName,
 			Namespace:       app.Namespace,
 			Labels:          util.GetResourceLabels(app),
-			OwnerReferences: []metav1.OwnerReference{util.GetOwnerReference(app)},
+			OwnerReferences: r.getOwnerReferences(app),
 		},
 		Spec: networkingv1.IngressSpec{
 			Rules: []networkingv1.IngressRule{{
@@ -145,9 +151,8 @@ func (r *Reconciler) createDriverIngressV1(app *v1beta2.SparkApplication, servic
 		ingress.Spec.IngressClassName = &ingressClassName
 	}
 
-	logger.Info("Creating networking.v1/Ingress for SparkApplication web UI", "name", app.Name, "namespace", app.Namespace, "ingressName", ingress.Name)
-	if err := r.client.Create(context.TODO(), ingress); err != nil {
-		return nil, fmt.Errorf("failed to create ingress %s/%s: %v", ingress.Namespace, ingress.Name, err)
+	if err := r.applyIngressV1(ctx, app, ingress); err != nil {
+		return nil, err
 	}
 	return &SparkIngress{
 		ingressName:      ingress.Name,
@@ -158,7 +163,7 @@ func (r *Reconciler) createDriverIngressV1(app *v1beta2.SparkApplication, servic
 	}, nil
 }
 
-func (r *Reconciler) createDriverIngressLegacy(app *v1beta2.SparkApplication, service SparkService, ingressName string, ingressURL *url.URL) (*SparkIngress, error) {
+func (r *Reconciler) createDriverIngressLegacy(ctx context.Context, app *v1beta2.SparkApplication, service SparkService, ingressName string, ingressURL *url.URL) (*SparkIngress, error) {
 	ingressResourceAnnotations := util.GetWebUIIngressAnnotations(app)
 	// var ingressTLSHosts networkingv1.IngressTLS[]
 	// That we convert later for extensionsv1beta1, but return as is in SparkIngress.
@@ -175,7 +180,7 @@ func (r *Reconciler) createDriverIngressLegacy(app *v1beta2.SparkApplication, se
 			Name:            ingressName,
 			Namespace:       app.Namespace,
 			Labels:          util.GetResourceLabels(app),
-			OwnerReferences: []metav1.OwnerReference{util.GetOwnerReference(app)},
+			OwnerReferences: r.getOwnerReferences(app),
 		},
 		Spec: extensionsv1beta1.IngressSpec{
 			Rules: []extensionsv1beta1.IngressRule{{
@@ -190,7 +195,8 @@ func (r *Reconciler) createDriverIngressLegacy(app *v1beta2.SparkApplication, se
 									IntVal: service.servicePort,
 								},
 							},
-							Path: ingressURLPath,
+							Path:     ingressURLPath,
+							PathType: ptr.To(extensionsv1beta1.PathTypeImplementationSpecific),
 						}},
 					},
 				},
@@ -212,9 +218,8 @@ func (r *Reconciler) createDriverIngressLegacy(app *v1beta2.SparkApplication, se
 	if len(ingressTLSHosts) != 0 {
 		ingress.Spec.TLS = convertIngressTLSHostsToLegacy(ingressTLSHosts)
 	}
-	logger.Info("Creating extensions.v1beta1/Ingress for SparkApplication web UI", "name", app.Name, "namespace", app.Namespace, "ingressName", ingress.Name)
-	if err := r.client.Create(context.TODO(), ingress); err != nil {
-		return nil, fmt.Errorf("failed to create ingress %s/%s: %v", ingress.Namespace, ingress.Name, err)
+	if err := r.applyIngressLegacy(ctx, app, ingress); err != nil {
+		return nil, err
 	}
 	return &SparkIngress{
 		ingressName: ingress.Name,
@@ -236,6 +241,7 @@ func convertIngressTLSHostsToLegacy(ingressTLSHosts []networkingv1.IngressTLS) [
 }
 
 func (r *Reconciler) createDriverIngressService(
+	ctx context.Context,
 	app *v1beta2.SparkApplication,
 	portName string,
 	port int32,
@@ -250,13 +256,14 @@ func (r *Reconciler) createDriverIngressService(
 			Name:            serviceName,
 			Namespace:       app.Namespace,
 			Labels:          util.GetResourceLabels(app),
-			OwnerReferences: []metav1.OwnerReference{util.GetOwnerReference(app)},
+			OwnerReferences: r.getOwnerReferences(app),
 		},
 		Spec: corev1.ServiceSpec{
 			Ports: []corev1.ServicePort{
 				{
-					Name: portName,
-					Port: port,
+					Name:     portName,
+					Port:     port,
+					Protocol: corev1.ProtocolTCP,
 					TargetPort: intstr.IntOrString{
 						Type:   intstr.Int,
 						IntVal: targetPort,
@@ -271,23 +278,16 @@ func (r *Reconciler) createDriverIngressService(
 		},
 	}
 
-	if len(serviceLabels) != 0 {
-		service.ObjectMeta.Labels = serviceLabels
-	}
+	// The resource labels are kept so that the service can be identified as owned by the app.
+	maps.Copy(service.ObjectMeta.Labels, serviceLabels)
 
 	if len(serviceAnnotations) != 0 {
 		service.ObjectMeta.Annotations = serviceAnnotations
 	}
 
-	if err := r.client.Create(context.TODO(), service); err != nil {
-		if !errors.IsAlreadyExists(err) {
-			return nil, err
-		}
-
-		// Update the service if it already exists.
-		if err := r.client.Update(context.TODO(), service); err != nil {
-			return nil, err
-		}
+	service, err := r.applyService(ctx, app, service)
+	if err != nil {
+		return nil, err
 	}
 
 	return &SparkService{
@@ -323,7 +323,7 @@ func getDriverIngressServicePortName(driverIngressConfiguration *v1beta2.DriverI
 }
 
 func getDriverIngressServiceName(app *v1beta2.SparkApplication, port int32) string {
-	return fmt.Sprintf("%s-driver-%d", app.Name, port)
+	return naming.DriverIngressServiceName(app, port)
 }
 
 func getDriverIngressServiceType(driverIngressConfiguration *v1beta2.DriverIngressConfiguration) corev1.ServiceType {
@@ -354,6 +354,7 @@ func getDriverIngressServiceLabels(driverIngressConfiguration *v1beta2.DriverIng
 }
 
 func (r *Reconciler) createDriverIngressServiceFromConfiguration(
+	ctx context.Context,
 	app *v1beta2.SparkApplication,
 	driverIngressConfiguration *v1beta2.DriverIngressConfiguration,
 ) (*SparkService, error) {
@@ -366,5 +367,138 @@ func (r *Reconciler) createDriverIngressServiceFromConfiguration(
 	serviceType := getDriverIngressServiceType(driverIngressConfiguration)
 	serviceAnnotations := getDriverIngressServiceAnnotations(driverIngressConfiguration)
 	serviceLabels := getDriverIngressServiceLabels(driverIngressConfiguration)
-	return r.createDriverIngressService(app, portName, port, port, serviceName, serviceType, serviceAnnotations, serviceLabels)
+	return r.createDriverIngressService(ctx, app, portName, port, port, serviceName, serviceType, serviceAnnotations, serviceLabels)
+}
+
+// applyService creates the desired service or, if it already exists, updates it to the desired state, correcting
+// any drift of its type, ports, selector, labels and annotations. Labels and annotations not in the desired state
+// are kept, so that those added by other controllers are not removed. Services not owned by the app are not touched.
+func (r *Reconciler) applyService(ctx context.Context, app *v1beta2.SparkApplication, desired *corev1.Service) (*corev1.Service, error) {
+	existing := &corev1.Service{}
+	if err := r.client.Get(ctx, client.ObjectKeyFromObject(desired), existing); err != nil {
+		if !errors.IsNotFound(err) {
+			return nil, fmt.Errorf("failed to get service %s/%s: %v", desired.Namespace, desired.Name, err)
+		}
+		appLogger(app).Info("Creating service for SparkApplication", "serviceName", desired.Name)
+		if err := r.client.Create(ctx, desired); err != nil {
+			return nil, fmt.Errorf("failed to create service %s/%s: %v", desired.Namespace, desired.Name, err)
+		}
+		return desired, nil
+	}
+	if !r.isOwnedBy(existing, app) {
+		return nil, fmt.Errorf("service %s/%s already exists and is not owned by SparkApplication %s", existing.Namespace, existing.Name, app.Name)
+	}
+
+	updated := existing.DeepCopy()
+	updated.Labels = mergeMaps(existing.Labels, desired.Labels)
+	updated.Annotations = mergeMaps(existing.Annotations, desired.Annotations)
+	if desired.OwnerReferences != nil {
+		updated.OwnerReferences = desired.OwnerReferences
+	}
+	updated.Spec.Type = desired.Spec.Type
+	updated.Spec.Selector = desired.Spec.Selector
+	updated.Spec.Ports = make([]corev1.ServicePort, len(desired.Spec.Ports))
+	for i, port := range desired.Spec.Ports {
+		// Keep the node ports allocated by the API server.
+		if desired.Spec.Type == corev1.ServiceTypeNodePort || desired.Spec.Type == corev1.ServiceTypeLoadBalancer {
+			for _, existingPort := range existing.Spec.Ports {
+				if existingPort.Name == port.Name && port.NodePort == 0 {
+					port.NodePort = existingPort.NodePort
+				}
+			}
+		}
+		updated.Spec.Ports[i] = port
+	}
+	if equality.Semantic.DeepEqual(existing, updated) {
+		return existing, nil
+	}
+
+	appLogger(app).Info("Updating service of SparkApplication to its desired state", "serviceName", updated.Name)
+	if err := r.client.Update(ctx, updated); err != nil {
+		return nil, fmt.Errorf("failed to update service %s/%s: %v", updated.Namespace, updated.Name, err)
+	}
+	return updated, nil
+}
+
+// applyIngressV1 creates the desired networking.k8s.io/v1 ingress or, if it already exists, updates it to the
+// desired state, correcting any drift of its spec, e.g. the backend service, labels and annotations.
+func (r *Reconciler) applyIngressV1(ctx context.Context, app *v1beta2.SparkApplication, desired *networkingv1.Ingress) error {
+	existing := &networkingv1.Ingress{}
+	if err := r.client.Get(ctx, client.ObjectKeyFromObject(desired), existing); err != nil {
+		if !errors.IsNotFound(err) {
+			return fmt.Errorf("failed to get ingress %s/%s: %v", desired.Namespace, desired.Name, err)
+		}
+		appLogger(app).Info("Creating networking.v1/Ingress for SparkApplication web UI", "ingressName", desired.Name)
+		if err := r.client.Create(ctx, desired); err != nil {
+			return fmt.Errorf("failed to create ingress %s/%s: %v", desired.Namespace, desired.Name, err)
+		}
+		return nil
+	}
+	if !r.isOwnedBy(existing, app) {
+		return fmt.Errorf("ingress %s/%s already exists and is not owned by SparkApplication %s", existing.Namespace, existing.Name, app.Name)
+	}
+
+	updated := existing.DeepCopy()
+	updated.Labels = mergeMaps(existing.Labels, desired.Labels)
+	updated.Annotations = mergeMaps(existing.Annotations, desired.Annotations)
+	if desired.OwnerReferences != nil {
+		updated.OwnerReferences = desired.OwnerReferences
+	}
+	updated.Spec = desired.Spec
+	if equality.Semantic.DeepEqual(existing, updated) {
+		return nil
+	}
+
+	appLogger(app).Info("Updating networking.v1/Ingress of SparkApplication to its desired state", "ingressName", updated.Name)
+	if err := r.client.Update(ctx, updated); err != nil {
+		return fmt.Errorf("failed to update ingress %s/%s: %v", updated.Namespace, updated.Name, err)
+	}
+	return nil
+}
+
+// applyIngressLegacy creates the desired extensions/v1beta1 ingress or, if it already exists, updates it to the
+// desired state.
+func (r *Reconciler) applyIngressLegacy(ctx context.Context, app *v1beta2.SparkApplication, desired *extensionsv1beta1.Ingress) error {
+	existing := &extensionsv1beta1.Ingress{}
+	if err := r.client.Get(ctx, client.ObjectKeyFromObject(desired), existing); err != nil {
+		if !errors.IsNotFound(err) {
+			return fmt.Errorf("failed to get ingress %s/%s: %v", desired.Namespace, desired.Name, err)
+		}
+		appLogger(app).Info("Creating extensions.v1beta1/Ingress for SparkApplication web UI", "ingressName", desired.Name)
+		if err := r.client.Create(ctx, desired); err != nil {
+			return fmt.Errorf("failed to create ingress %s/%s: %v", desired.Namespace, desired.Name, err)
+		}
+		return nil
+	}
+	if !r.isOwnedBy(existing, app) {
+		return fmt.Errorf("ingress %s/%s already exists and is not owned by SparkApplication %s", existing.Namespace, existing.Name, app.Name)
+	}
+
+	updated := existing.DeepCopy()
+	updated.Labels = mergeMaps(existing.Labels, desired.Labels)
+	updated.Annotations = mergeMaps(existing.Annotations, desired.Annotations)
+	if desired.OwnerReferences != nil {
+		updated.OwnerReferences = desired.OwnerReferences
+	}
+	updated.Spec = desired.Spec
+	if equality.Semantic.DeepEqual(existing, updated) {
+		return nil
+	}
+
+	appLogger(app).Info("Updating extensions.v1beta1/Ingress of SparkApplication to its desired state", "ingressName", updated.Name)
+	if err := r.client.Update(ctx, updated); err != nil {
+		return fmt.Errorf("failed to update ingress %s/%s: %v", updated.Namespace, updated.Name, err)
+	}
+	return nil
+}
+
+// mergeMaps returns a copy of existing overlaid with desired.
+func mergeMaps(existing, desired map[string]string) map[string]string {
+	if len(existing) == 0 && len(desired) == 0 {
+		return existing
+	}
+	merged := make(map[string]string, len(existing)+len(desired))
+	maps.Copy(merged, existing)
+	maps.Copy(merged, desired)
+	return merged
 }
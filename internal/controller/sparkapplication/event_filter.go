@@ -171,9 +171,10 @@ func (f *EventFilter) Update(e event.UpdateEvent) bool {
 	// and end up in an inconsistent state.
 	if !equality.Semantic.DeepEqual(oldApp.Spec, newApp.Spec) {
 		// Force-set the application status to Invalidating which handles clean-up and application re-run.
+		patch := client.MergeFrom(newApp.DeepCopy())
 		newApp.Status.AppState.State = v1beta2.ApplicationStateInvalidating
 		logger.Info("Updating SparkApplication status", "name", newApp.Name, "namespace", newApp.Namespace, " oldState", oldApp.Status.AppState.State, "newState", newApp.Status.AppState.State)
-		if err := f.client.Status().Update(context.TODO(), newApp); err != nil {
+		if err := f.client.Status().Patch(context.TODO(), newApp, patch); err != nil {
 			logger.Error(err, "Failed to update application status", "application", newApp.Name)
 			f.recorder.Eventf(
 				newApp,
@@ -28,6 +28,7 @@ import (
 	"sigs.k8s.io/controller-runtime/pkg/handler"
 
 	"github.com/kubeflow/spark-operator/api/v1beta2"
+	"github.com/kubeflow/spark-operator/internal/chaos"
 	"github.com/kubeflow/spark-operator/internal/metrics"
 	"github.com/kubeflow/spark-operator/pkg/common"
 	"github.com/kubeflow/spark-operator/pkg/util"
@@ -120,6 +121,9 @@ func (h *SparkPodEventHandler) enqueueSparkAppForUpdate(ctx context.Context, pod
 	if name == "" {
 		return
 	}
+	if chaos.DropPodEvent() {
+		return
+	}
 	namespace := pod.Namespace
 	key := types.NamespacedName{
 		Namespace: namespace,
@@ -146,15 +150,17 @@ func (h *SparkPodEventHandler) enqueueSparkAppForUpdate(ctx context.Context, pod
 
 // EventHandler watches SparkApplication events.
 type EventHandler struct {
-	metrics *metrics.SparkApplicationMetrics
+	metrics           *metrics.SparkApplicationMetrics
+	submissionMetrics *metrics.SparkSubmissionMetrics
 }
 
 var _ handler.EventHandler = &EventHandler{}
 
 // NewSparkApplicationEventHandler creates a new SparkApplicationEventHandler instance.
-func NewSparkApplicationEventHandler(metrics *metrics.SparkApplicationMetrics) *EventHandler {
+func NewSparkApplicationEventHandler(metrics *metrics.SparkApplicationMetrics, submissionMetrics *metrics.SparkSubmissionMetrics) *EventHandler {
 	return &EventHandler{
-		metrics: metrics,
+		metrics:           metrics,
+		submissionMetrics: submissionMetrics,
 	}
 }
 
@@ -165,12 +171,15 @@ func (h *EventHandler) Create(ctx context.Context, event event.CreateEvent, queu
 		return
 	}
 
-	logger.Info("SparkApplication created", "name", app.Name, "namespace", app.Namespace, "state", app.Status.AppState.State)
+	appLogger(app).Info("SparkApplication created", "state", app.Status.AppState.State)
 	queue.AddRateLimited(ctrl.Request{NamespacedName: types.NamespacedName{Name: app.Name, Namespace: app.Namespace}})
 
 	if h.metrics != nil {
 		h.metrics.HandleSparkApplicationCreate(app)
 	}
+	if h.submissionMetrics != nil {
+		h.submissionMetrics.HandleSparkApplicationCreate(app)
+	}
 }
 
 // Update implements handler.EventHandler.
@@ -191,6 +200,9 @@ func (h *EventHandler) Update(ctx context.Context, event event.UpdateEvent, queu
 	if h.metrics != nil {
 		h.metrics.HandleSparkApplicationUpdate(oldApp, newApp)
 	}
+	if h.submissionMetrics != nil {
+		h.submissionMetrics.HandleSparkApplicationUpdate(oldApp, newApp)
+	}
 }
 
 // Delete implements handler.EventHandler.
@@ -200,12 +212,15 @@ func (h *EventHandler) Delete(ctx context.Context, event event.DeleteEvent, queu
 		return
 	}
 
-	logger.Info("SparkApplication deleted", "name", app.Name, "namespace", app.Namespace, "state", app.Status.AppState.State)
+	appLogger(app).Info("SparkApplication deleted", "state", app.Status.AppState.State)
 	queue.AddRateLimited(ctrl.Request{NamespacedName: types.NamespacedName{Name: app.Name, Namespace: app.Namespace}})
 
 	if h.metrics != nil {
 		h.metrics.HandleSparkApplicationDelete(app)
 	}
+	if h.submissionMetrics != nil {
+		h.submissionMetrics.HandleSparkApplicationDelete(app)
+	}
 }
 
 // Generic implements handler.EventHandler.
@@ -215,6 +230,6 @@ func (h *EventHandler) Generic(ctx context.Context, event event.GenericEvent, qu
 		return
 	}
 
-	logger.Info("SparkApplication generic event", "name", app.Name, "namespace", app.Namespace, "state", app.Status.AppState.State)
+	appLogger(app).Info("SparkApplication generic event", "state", app.Status.AppState.State)
 	queue.AddRateLimited(ctrl.Request{NamespacedName: types.NamespacedName{Name: app.Name, Namespace: app.Namespace}})
 }
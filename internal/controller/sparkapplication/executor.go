@@ -0,0 +1,369 @@
+/*
+Copyright 2024 The Kubeflow authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package sparkapplication
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/golang/glog"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	utilerrors "k8s.io/apimachinery/pkg/util/errors"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/kubeflow/spark-operator/api/v1beta2"
+	"github.com/kubeflow/spark-operator/pkg/common"
+	"github.com/kubeflow/spark-operator/pkg/util"
+)
+
+// ExecutorOptions configures the lifecycle of the executors of SparkApplications.
+type ExecutorOptions struct {
+	// DeletionBatchSize is the number of executor pods deleted in parallel per batch when tearing down a
+	// SparkApplication. Executor pods are left to be garbage collected along with the driver pod if set to 0.
+	DeletionBatchSize int
+	// DeletionBatchInterval is the interval between two batches of executor pod deletions.
+	DeletionBatchInterval time.Duration
+
+	// UnreachableGracePeriod is the period after which executors on nodes not being ready are treated as failed.
+	// Executors on nodes which do not exist are treated as failed once the period has passed since they were scheduled.
+	// Executors on unreachable nodes are counted as running indefinitely if set to 0.
+	UnreachableGracePeriod time.Duration
+	// ForceDeleteUnreachable enables force deleting executors treated as failed because of unreachable nodes.
+	ForceDeleteUnreachable bool
+
+	// EnablePlacementTracking enables recording the node and zone of the executors tracked in the status of
+	// SparkApplications, as well as exporting the number of running executors per zone. The zones are read from
+	// the metadata of nodes, which are then cached.
+	EnablePlacementTracking bool
+
+	// MaxAllocationBatchSize caps the number of executor pods the driver of every SparkApplication creates
+	// in each round of executor allocation. Unlimited if set to 0.
+	MaxAllocationBatchSize int
+
+	// EnableSecretRotation enables restarting the executors of running SparkApplications with secret rotation
+	// configured in batches whenever the Secrets mounted into them change.
+	EnableSecretRotation bool
+
+	// EnableKarpenterDisruptionProtection enables protecting drivers from voluntary disruptions by Karpenter,
+	// creating PodDisruptionBudgets for executors and decommissioning executors on nodes Karpenter disrupts.
+	EnableKarpenterDisruptionProtection bool
+}
+
+// configExecutors configures the executors of the SparkApplication before it is submitted.
+func (r *Reconciler) configExecutors(ctx context.Context, app *v1beta2.SparkApplication) error {
+	if r.options.Executor.EnableKarpenterDisruptionProtection {
+		configKarpenterDisruptionProtection(app)
+		if app.Spec.Executor.DisruptionBudget != nil {
+			if err := r.createExecutorPodDisruptionBudget(ctx, app); err != nil {
+				return err
+			}
+		}
+	}
+
+	if r.options.Executor.MaxAllocationBatchSize > 0 {
+		if err := capExecutorAllocationBatchSize(app, r.options.Executor.MaxAllocationBatchSize); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// updateExecutorState lists the executor pods of the application
+// and updates the executor state based on the current phase of the pods.
+func (r *Reconciler) updateExecutorState(ctx context.Context, app *v1beta2.SparkApplication) error {
+	podList, err := r.getExecutorPods(ctx, app)
+	if err != nil {
+		return err
+	}
+	pods := podList.Items
+
+	executorStateMap := make(map[string]v1beta2.ExecutorState)
+	nodes := make(map[string]*corev1.Node)
+	nodeZones := make(map[string]string)
+	var executorApplicationID string
+	for _, pod := range pods {
+		if util.IsExecutorPod(&pod) {
+			// If the executor number is higher than the `MaxTrackedExecutorPerApp` we want to stop persisting executors
+			if executorID, _ := strconv.Atoi(util.GetSparkExecutorID(&pod)); executorID > r.options.MaxTrackedExecutorPerApp {
+				continue
+			}
+			newState := util.GetExecutorState(&pod)
+			oldState, exists := app.Status.ExecutorState[pod.Name]
+			unreachable, err := r.isExecutorNodeUnreachable(ctx, &pod, newState, nodes)
+			if err != nil {
+				return err
+			}
+			if unreachable {
+				newState = v1beta2.ExecutorStateFailed
+				if err := r.handleUnreachableExecutor(ctx, app, &pod, !exists || newState != oldState); err != nil {
+					return err
+				}
+			} else if !exists || newState != oldState {
+				// Only record an executor event if the executor state is new or it has changed.
+				if newState == v1beta2.ExecutorStateFailed {
+					execContainerState := util.GetExecutorContainerTerminatedState(&pod)
+					if execContainerState != nil {
+						r.recordExecutorEvent(app, newState, pod.Name, execContainerState.ExitCode, execContainerState.Reason)
+					} else {
+						// If we can't find the container state,
+						// we need to set the exitCode and the Reason to unambiguous values.
+						r.recordExecutorEvent(app, newState, pod.Name, -1, "Unknown (Container not Found)")
+					}
+				} else {
+					r.recordExecutorEvent(app, newState, pod.Name)
+				}
+			}
+			if r.options.Executor.EnableKarpenterDisruptionProtection && newState == v1beta2.ExecutorStateRunning {
+				if err := r.decommissionExecutorOnDisruptedNode(ctx, app, &pod); err != nil {
+					return err
+				}
+			}
+			executorStateMap[pod.Name] = newState
+
+			if r.options.Executor.EnablePlacementTracking {
+				if err := r.updateExecutorPlacement(ctx, app, &pod, nodeZones); err != nil {
+					return err
+				}
+			}
+
+			if executorApplicationID == "" {
+				executorApplicationID = util.GetSparkApplicationID(&pod)
+			}
+		}
+	}
+
+	// ApplicationID label can be different on driver/executors. Prefer executor ApplicationID if set.
+	// Refer https://issues.apache.org/jira/projects/SPARK/issues/SPARK-25922 for details.
+	if executorApplicationID != "" {
+		app.Status.SparkApplicationID = executorApplicationID
+	}
+
+	if app.Status.ExecutorState == nil {
+		app.Status.ExecutorState = make(map[string]v1beta2.ExecutorState)
+	}
+	for name, state := range executorStateMap {
+		app.Status.ExecutorState[name] = state
+	}
+
+	// Handle missing/deleted executors.
+	for name, oldStatus := range app.Status.ExecutorState {
+		_, exists := executorStateMap[name]
+		if !util.IsExecutorTerminated(oldStatus) && !exists {
+			if !util.IsDriverRunning(app) {
+				// If ApplicationState is COMPLETED, in other words, the driver pod has been completed
+				// successfully. The executor pods terminate and are cleaned up, so we could not found
+				// the executor pod, under this circumstances, we assume the executor pod are completed.
+				if app.Status.AppState.State == v1beta2.ApplicationStateCompleted {
+					app.Status.ExecutorState[name] = v1beta2.ExecutorStateCompleted
+				} else {
+					glog.Infof("Executor pod %s not found, assuming it was deleted.", name)
+					app.Status.ExecutorState[name] = v1beta2.ExecutorStateFailed
+				}
+			} else {
+				app.Status.ExecutorState[name] = v1beta2.ExecutorStateUnknown
+			}
+		}
+	}
+
+	if r.options.Executor.EnablePlacementTracking && r.options.SparkExecutorMetrics != nil {
+		r.options.SparkExecutorMetrics.SetZoneCounts(app, util.GetRunningExecutorCountByZone(app))
+	}
+
+	return nil
+}
+
+// updateExecutorPlacement records the node and zone the executor pod is scheduled to in the application status, for up
+// to MaxTrackedExecutorPerApp executors. Nodes are read metadata-only from the cache, and their zones are kept in the
+// given map.
+func (r *Reconciler) updateExecutorPlacement(ctx context.Context, app *v1beta2.SparkApplication, pod *corev1.Pod, nodeZones map[string]string) error {
+	nodeName := pod.Spec.NodeName
+	if nodeName == "" {
+		return nil
+	}
+	placement, ok := app.Status.ExecutorPlacement[pod.Name]
+	if ok && placement.NodeName == nodeName {
+		return nil
+	}
+	if !ok && len(app.Status.ExecutorPlacement) >= r.options.MaxTrackedExecutorPerApp {
+		return nil
+	}
+
+	zone, ok := nodeZones[nodeName]
+	if !ok {
+		node := &metav1.PartialObjectMetadata{}
+		node.SetGroupVersionKind(corev1.SchemeGroupVersion.WithKind("Node"))
+		if err := r.client.Get(ctx, types.NamespacedName{Name: nodeName}, node); err != nil && !errors.IsNotFound(err) {
+			return fmt.Errorf("failed to get node %s: %v", nodeName, err)
+		}
+		zone = node.Labels[corev1.LabelTopologyZone]
+		nodeZones[nodeName] = zone
+	}
+
+	if app.Status.ExecutorPlacement == nil {
+		app.Status.ExecutorPlacement = make(map[string]v1beta2.ExecutorPlacement)
+	}
+	app.Status.ExecutorPlacement[pod.Name] = v1beta2.ExecutorPlacement{
+		NodeName: nodeName,
+		Zone:     zone,
+	}
+	return nil
+}
+
+// isExecutorNodeUnreachable returns whether the executor pod is running on a node which has not been ready
+// for longer than the configured grace period. Executors on nodes which do not exist, i.e. which were deleted or are
+// not in the cache yet, are treated the same once the grace period has passed since they were scheduled. Nodes are
+// read from the cache and kept in the given map, where nodes which do not exist are nil.
+func (r *Reconciler) isExecutorNodeUnreachable(
+	ctx context.Context,
+	pod *corev1.Pod,
+	state v1beta2.ExecutorState,
+	nodes map[string]*corev1.Node,
+) (bool, error) {
+	if r.options.Executor.UnreachableGracePeriod <= 0 || util.IsExecutorTerminated(state) || pod.Spec.NodeName == "" {
+		return false, nil
+	}
+	// Pods on nodes not being ready are marked as not ready by the node lifecycle controller,
+	// so the node is only looked up for pods not being ready.
+	if state == v1beta2.ExecutorStateRunning && util.IsPodReady(pod) {
+		return false, nil
+	}
+
+	nodeName := pod.Spec.NodeName
+	node, ok := nodes[nodeName]
+	if !ok {
+		node = &corev1.Node{}
+		if err := r.client.Get(ctx, types.NamespacedName{Name: nodeName}, node); err != nil {
+			if !errors.IsNotFound(err) {
+				return false, fmt.Errorf("failed to get node %s: %v", nodeName, err)
+			}
+			node = nil
+		}
+		nodes[nodeName] = node
+	}
+
+	now := time.Now()
+	if node == nil {
+		return now.Sub(util.GetPodScheduledTime(pod)) >= r.options.Executor.UnreachableGracePeriod, nil
+	}
+	return util.IsNodeUnreachable(node, r.options.Executor.UnreachableGracePeriod, now), nil
+}
+
+// handleUnreachableExecutor records the failure of an executor on an unreachable node and force deletes the executor pod
+// if configured, so that Spark can request a replacement.
+func (r *Reconciler) handleUnreachableExecutor(ctx context.Context, app *v1beta2.SparkApplication, pod *corev1.Pod, stateChanged bool) error {
+	if stateChanged {
+		appLogger(app).Info("Treating executor on unreachable node as failed", "executor", pod.Name, "node", pod.Spec.NodeName)
+		r.recorder.Eventf(app, corev1.EventTypeWarning, common.EventSparkExecutorNodeUnreachable, "Executor %s failed as node %s is unreachable", pod.Name, pod.Spec.NodeName)
+	}
+
+	if !r.options.Executor.ForceDeleteUnreachable {
+		return nil
+	}
+	if err := r.client.Delete(ctx, pod, client.GracePeriodSeconds(0)); err != nil && !errors.IsNotFound(err) {
+		return fmt.Errorf("failed to force delete executor pod %s: %v", pod.Name, err)
+	}
+	return nil
+}
+
+func (r *Reconciler) getExecutorPods(ctx context.Context, app *v1beta2.SparkApplication) (*corev1.PodList, error) {
+	matchLabels := util.GetResourceLabels(app)
+	matchLabels[common.LabelSparkRole] = common.SparkRoleExecutor
+	pods := &corev1.PodList{}
+	if err := r.client.List(ctx, pods, client.InNamespace(app.Namespace), client.MatchingLabels(matchLabels)); err != nil {
+		return nil, fmt.Errorf("failed to get pods for SparkApplication %s/%s: %v", app.Namespace, app.Name, err)
+	}
+	return pods, nil
+}
+
+// deleteExecutorPods deletes a batch of the executor pods not being deleted yet if executor deletion batching is
+// enabled, and returns whether executor pods remain to be deleted. A single reconcile deletes at most one batch, the
+// next one being deleted by the reconcile after the batch interval, so that tearing down huge applications does not
+// hold a worker. Otherwise, the executor pods are left to be garbage collected along with the driver pod.
+func (r *Reconciler) deleteExecutorPods(ctx context.Context, app *v1beta2.SparkApplication) (bool, error) {
+	batchSize := r.options.Executor.DeletionBatchSize
+	if batchSize <= 0 {
+		return false, nil
+	}
+
+	pods, err := r.getExecutorPods(ctx, app)
+	if err != nil {
+		return false, err
+	}
+	var remaining []*corev1.Pod
+	for i := range pods.Items {
+		if pods.Items[i].DeletionTimestamp.IsZero() {
+			remaining = append(remaining, &pods.Items[i])
+		}
+	}
+	if len(remaining) == 0 {
+		return false, nil
+	}
+
+	batch := remaining[:min(batchSize, len(remaining))]
+	appLogger(app).Info("Deleting a batch of executor pods", "count", len(batch), "remaining", len(remaining)-len(batch))
+	var wg sync.WaitGroup
+	errs := make([]error, len(batch))
+	for i, pod := range batch {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if err := r.client.Delete(ctx, pod); err != nil && !errors.IsNotFound(err) {
+				errs[i] = err
+			}
+		}()
+	}
+	wg.Wait()
+
+	if err := utilerrors.NewAggregate(errs); err != nil {
+		return false, fmt.Errorf("failed to delete executor pods: %v", err)
+	}
+	return len(remaining) > len(batch), nil
+}
+
+// executorDeletionResult returns the result requeuing a SparkApplication whose executor pods remain to be deleted for
+// the next batch of deletions.
+func (r *Reconciler) executorDeletionResult() ctrl.Result {
+	if r.options.Executor.DeletionBatchInterval > 0 {
+		return ctrl.Result{RequeueAfter: r.options.Executor.DeletionBatchInterval}
+	}
+	return ctrl.Result{Requeue: true}
+}
+
+// capExecutorAllocationBatchSize caps the number of executor pods the driver of the app creates in each round of
+// executor allocation at maxBatchSize.
+func capExecutorAllocationBatchSize(app *v1beta2.SparkApplication, maxBatchSize int) error {
+	batchSize, err := util.GetExecutorAllocationBatchSize(app)
+	if err != nil {
+		return err
+	}
+	if batchSize <= maxBatchSize {
+		return nil
+	}
+	appLogger(app).Info("Capping executor allocation batch size", "batchSize", batchSize, "maxBatchSize", maxBatchSize)
+	if app.Spec.SparkConf == nil {
+		app.Spec.SparkConf = make(map[string]string)
+	}
+	app.Spec.SparkConf[common.SparkKubernetesAllocationBatchSize] = strconv.Itoa(maxBatchSize)
+	return nil
+}
@@ -0,0 +1,169 @@
+/*
+Copyright 2024 The Kubeflow authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package sparkapplication
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/kubeflow/spark-operator/api/v1beta2"
+	"github.com/kubeflow/spark-operator/internal/scheduler/yunikorn/resourceusage"
+	"github.com/kubeflow/spark-operator/pkg/common"
+	"github.com/kubeflow/spark-operator/pkg/util"
+)
+
+// executorQuotaCheckInterval is the interval at which running SparkApplications missing executors are checked
+// against the ResourceQuotas of their namespaces, as executor pods rejected by a ResourceQuota trigger no events.
+const executorQuotaCheckInterval = 30 * time.Second
+
+// shouldCheckExecutorQuota returns whether the executors of the SparkApplication are checked against the
+// ResourceQuotas of its namespace.
+func (r *Reconciler) shouldCheckExecutorQuota(app *v1beta2.SparkApplication) bool {
+	return r.options.Scheduling.EnableExecutorQuotaFailureDetection && app.Spec.Executor.QuotaFailurePolicy != nil
+}
+
+// checkExecutorQuota records in the status of the running SparkApplication whether fewer executors than required
+// are pending or running while a ResourceQuota of its namespace has no room for another executor, and fails the
+// application if its executor quota failure policy says so. The shortfall is cleared once it is resolved.
+func (r *Reconciler) checkExecutorQuota(ctx context.Context, app *v1beta2.SparkApplication) error {
+	required := util.GetRequiredExecutorNumber(app)
+	active := getActiveExecutorNumber(app)
+	if active >= required {
+		app.Status.ExecutorQuotaShortfall = nil
+		return nil
+	}
+
+	quotaName, err := r.getExhaustedResourceQuota(ctx, app)
+	if err != nil {
+		return err
+	}
+	if quotaName == "" {
+		app.Status.ExecutorQuotaShortfall = nil
+		return nil
+	}
+
+	message := fmt.Sprintf("%d of %d executors are pending or running and ResourceQuota %s has no room for another executor", active, required, quotaName)
+	shortfall := app.Status.ExecutorQuotaShortfall
+	if shortfall == nil {
+		shortfall = &v1beta2.ExecutorQuotaShortfall{DetectedTime: metav1.Now()}
+		app.Status.ExecutorQuotaShortfall = shortfall
+		appLogger(app).Info("SparkApplication is missing executors because of a ResourceQuota", "resourceQuota", quotaName, "active", active, "required", required)
+		r.recorder.Eventf(
+			app,
+			corev1.EventTypeWarning,
+			common.EventSparkApplicationExecutorQuotaExceeded,
+			"SparkApplication %s is missing executors: %s",
+			app.Name,
+			message,
+		)
+	}
+	shortfall.RequestedExecutors = required
+	shortfall.ActiveExecutors = active
+	shortfall.ResourceQuota = quotaName
+	shortfall.Message = message
+
+	if app.Spec.Executor.QuotaFailurePolicy.Action != v1beta2.ExecutorQuotaFailureActionFail {
+		return nil
+	}
+	if err := r.deleteDriverPod(ctx, app); err != nil {
+		return fmt.Errorf("failed to delete driver pod: %v", err)
+	}
+	app.Status.AppState.State = v1beta2.ApplicationStateFailing
+	app.Status.AppState.ErrorMessage = fmt.Sprintf("executor quota exceeded: %s", message)
+	app.Status.TerminationTime = metav1.Now()
+	return nil
+}
+
+// getActiveExecutorNumber returns the number of pending and running executors recorded in the status of the
+// SparkApplication.
+func getActiveExecutorNumber(app *v1beta2.SparkApplication) int32 {
+	var active int32
+	for _, state := range app.Status.ExecutorState {
+		if state == v1beta2.ExecutorStatePending || state == v1beta2.ExecutorStateRunning {
+			active++
+		}
+	}
+	return active
+}
+
+// getExhaustedResourceQuota returns the name of the first ResourceQuota in the namespace of the SparkApplication
+// without room for another executor, or an empty string if there is none. ResourceQuotas are read from the API
+// server to avoid watching them. ResourceQuotas with scopes are ignored, as they may not apply to executors.
+func (r *Reconciler) getExhaustedResourceQuota(ctx context.Context, app *v1beta2.SparkApplication) (string, error) {
+	requests, err := getExecutorQuotaUsage(app)
+	if err != nil {
+		return "", err
+	}
+
+	quotas := &corev1.ResourceQuotaList{}
+	if err := r.manager.GetAPIReader().List(ctx, quotas, client.InNamespace(app.Namespace)); err != nil {
+		return "", fmt.Errorf("failed to list resource quotas: %v", err)
+	}
+	for _, quota := range quotas.Items {
+		if quota.Spec.ScopeSelector != nil || len(quota.Spec.Scopes) > 0 {
+			continue
+		}
+		if exceedsResourceQuota(requests, &quota) {
+			return quota.Name, nil
+		}
+	}
+	return "", nil
+}
+
+// getExecutorQuotaUsage returns the quota usage of a single executor pod of the SparkApplication.
+func getExecutorQuotaUsage(app *v1beta2.SparkApplication) (corev1.ResourceList, error) {
+	requests, err := resourceusage.ExecutorPodRequests(app)
+	if err != nil {
+		return nil, fmt.Errorf("failed to calculate executor resource requests: %v", err)
+	}
+
+	usage := corev1.ResourceList{
+		corev1.ResourcePods: *resource.NewQuantity(1, resource.DecimalSI),
+	}
+	for name, value := range requests {
+		quantity, err := resource.ParseQuantity(value)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse %s request %s: %v", name, value, err)
+		}
+		usage[corev1.ResourceName(name)] = quantity
+		usage[corev1.ResourceName(corev1.DefaultResourceRequestsPrefix+name)] = quantity
+	}
+	return usage, nil
+}
+
+// exceedsResourceQuota returns whether the given usage on top of the current usage of the ResourceQuota exceeds
+// any of its hard limits.
+func exceedsResourceQuota(usage corev1.ResourceList, quota *corev1.ResourceQuota) bool {
+	for name, quantity := range usage {
+		hard, ok := quota.Status.Hard[name]
+		if !ok {
+			continue
+		}
+		used := quota.Status.Used[name].DeepCopy()
+		used.Add(quantity)
+		if used.Cmp(hard) > 0 {
+			return true
+		}
+	}
+	return false
+}
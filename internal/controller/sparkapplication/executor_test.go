@@ -0,0 +1,73 @@
+/*
+Copyright 2024 The Kubeflow authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package sparkapplication
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/kubeflow/spark-operator/api/v1beta2"
+	"github.com/kubeflow/spark-operator/pkg/common"
+)
+
+func TestConfigExecutors(t *testing.T) {
+	testCases := []struct {
+		name     string
+		options  ExecutorOptions
+		conf     map[string]string
+		expected map[string]string
+	}{
+		{
+			name:     "no executor options",
+			conf:     map[string]string{common.SparkKubernetesAllocationBatchSize: "50"},
+			expected: map[string]string{common.SparkKubernetesAllocationBatchSize: "50"},
+		},
+		{
+			name:     "allocation batch size above the maximum",
+			options:  ExecutorOptions{MaxAllocationBatchSize: 20},
+			conf:     map[string]string{common.SparkKubernetesAllocationBatchSize: "50"},
+			expected: map[string]string{common.SparkKubernetesAllocationBatchSize: "20"},
+		},
+		{
+			name:     "allocation batch size below the maximum",
+			options:  ExecutorOptions{MaxAllocationBatchSize: 20},
+			expected: nil,
+		},
+		{
+			name:    "karpenter disruption protection without disruption budget",
+			options: ExecutorOptions{EnableKarpenterDisruptionProtection: true, MaxAllocationBatchSize: 5},
+			expected: map[string]string{
+				fmt.Sprintf(common.SparkKubernetesDriverAnnotationTemplate, common.KarpenterDoNotDisruptAnnotation): "true",
+				common.SparkDecommissionEnabled:           "true",
+				common.SparkKubernetesAllocationBatchSize: "5",
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			r := NewReconciler(nil, nil, nil, nil, nil, Options{Executor: tc.options})
+			app := &v1beta2.SparkApplication{Spec: v1beta2.SparkApplicationSpec{SparkConf: tc.conf}}
+			require.NoError(t, r.configExecutors(context.TODO(), app))
+			assert.Equal(t, tc.expected, app.Spec.SparkConf)
+		})
+	}
+}
@@ -0,0 +1,176 @@
+/*
+Copyright 2024 The Kubeflow authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package sparkapplication
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+
+	"github.com/kubeflow/spark-operator/api/v1beta2"
+	"github.com/kubeflow/spark-operator/internal/webhook"
+	"github.com/kubeflow/spark-operator/pkg/common"
+)
+
+const (
+	// defaultHookTimeout is the timeout of calls of hooks configured without a timeout.
+	defaultHookTimeout = 10 * time.Second
+
+	// maxHookTimeout bounds every hook call, so that a hanging hook cannot block reconciles indefinitely.
+	maxHookTimeout = 5 * time.Minute
+)
+
+// hookClient is the HTTP client calling hooks. Redirects are not followed, so that a hook cannot forward the payload
+// to endpoints the operator is not configured to call.
+var hookClient = &http.Client{
+	Timeout: maxHookTimeout,
+	CheckRedirect: func(*http.Request, []*http.Request) error {
+		return http.ErrUseLastResponse
+	},
+}
+
+// HookPhase is the phase of the SparkApplication lifecycle in which an external hook is called.
+type HookPhase string
+
+const (
+	// HookPhasePreSubmission hooks are called before the application is submitted.
+	HookPhasePreSubmission HookPhase = "PreSubmission"
+	// HookPhasePostCompletion hooks are called after the application reaches a terminal state.
+	HookPhasePostCompletion HookPhase = "PostCompletion"
+)
+
+// HookFailurePolicy defines how the controller handles a failed hook call.
+type HookFailurePolicy string
+
+const (
+	// HookFailurePolicyFail fails the submission if a pre-submission hook call fails.
+	HookFailurePolicyFail HookFailurePolicy = "Fail"
+	// HookFailurePolicyIgnore ignores hook call failures.
+	HookFailurePolicyIgnore HookFailurePolicy = "Ignore"
+)
+
+// Hook is an external HTTP endpoint called by the controller with the SparkApplication payload.
+type Hook struct {
+	// URL is the endpoint the SparkApplication payload is POSTed to.
+	URL string
+	// Timeout is the timeout of a single hook call. Defaults to 10 seconds if not positive.
+	Timeout time.Duration
+	// FailurePolicy defines how to handle a failed hook call.
+	FailurePolicy HookFailurePolicy
+}
+
+// hookRequest is the payload sent to an external hook.
+type hookRequest struct {
+	Phase            HookPhase                 `json:"phase"`
+	SparkApplication *v1beta2.SparkApplication `json:"sparkApplication"`
+}
+
+// hookResponse is the optional payload returned by an external hook.
+type hookResponse struct {
+	// SparkApplication is the mutated application returned by a pre-submission hook. The spec of the
+	// returned application is used for the submission.
+	SparkApplication *v1beta2.SparkApplication `json:"sparkApplication,omitempty"`
+}
+
+// call calls the hook with the given SparkApplication and returns the mutated application if any.
+func (h *Hook) call(ctx context.Context, phase HookPhase, app *v1beta2.SparkApplication) (*v1beta2.SparkApplication, error) {
+	body, err := json.Marshal(hookRequest{Phase: phase, SparkApplication: app})
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal hook request: %v", err)
+	}
+
+	timeout := h.Timeout
+	if timeout <= 0 {
+		timeout = defaultHookTimeout
+	}
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, h.URL, bytes.NewReader(body))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create hook request: %v", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := hookClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to call hook %s: %v", h.URL, err)
+	}
+	defer resp.Body.Close()
+
+	respBody, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read response of hook %s: %v", h.URL, err)
+	}
+
+	if resp.StatusCode < http.StatusOK || resp.StatusCode >= http.StatusMultipleChoices {
+		return nil, fmt.Errorf("hook %s returned status %d: %s", h.URL, resp.StatusCode, string(respBody))
+	}
+
+	if len(bytes.TrimSpace(respBody)) == 0 {
+		return nil, nil
+	}
+
+	response := &hookResponse{}
+	if err := json.Unmarshal(respBody, response); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal response of hook %s: %v", h.URL, err)
+	}
+	return response.SparkApplication, nil
+}
+
+// runPreSubmissionHooks calls the pre-submission hooks in order, applying the spec returned by each
+// hook to the application before it is submitted. The returned specs bypass the webhook, so they are
+// defaulted and validated the same way, and the submission fails if one is invalid regardless of the
+// failure policy of the hook.
+func (r *Reconciler) runPreSubmissionHooks(ctx context.Context, app *v1beta2.SparkApplication) error {
+	for _, hook := range r.options.PreSubmissionHooks {
+		mutated, err := hook.call(ctx, HookPhasePreSubmission, app)
+		if err != nil {
+			if hook.FailurePolicy == HookFailurePolicyFail {
+				return err
+			}
+			appLogger(app).Error(err, "Ignoring failed pre-submission hook", "url", hook.URL)
+			continue
+		}
+		if mutated == nil {
+			continue
+		}
+		mutated.Spec.DeepCopyInto(&app.Spec)
+		if err := webhook.DefaultAndValidateSpec(ctx, app, r.options.ApplicationDefaults, r.options.SparkConfPolicy); err != nil {
+			return fmt.Errorf("invalid spec returned by pre-submission hook %s: %v", hook.URL, err)
+		}
+	}
+	return nil
+}
+
+// runPostCompletionHooks calls the post-completion hooks in order once the terminal state of the application has
+// been persisted. The calls are best-effort: each hook is called at most once per run and failed calls are not
+// retried, they are only logged and recorded as warning events since the application has already terminated.
+func (r *Reconciler) runPostCompletionHooks(ctx context.Context, app *v1beta2.SparkApplication) {
+	for _, hook := range r.options.PostCompletionHooks {
+		if _, err := hook.call(ctx, HookPhasePostCompletion, app); err != nil {
+			appLogger(app).Error(err, "Failed to call post-completion hook", "url", hook.URL)
+			r.recorder.Eventf(app, corev1.EventTypeWarning, common.EventSparkApplicationPostCompletionHookFailed, "Failed to call post-completion hook %s: %v", hook.URL, err)
+		}
+	}
+}
@@ -0,0 +1,121 @@
+/*
+Copyright 2024 The Kubeflow authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package sparkapplication
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/kubeflow/spark-operator/api/v1beta2"
+)
+
+// newTestHookServer returns a server decoding hook requests and replying with the response of the given handler,
+// which is marshaled unless nil. The decoded requests are sent to the returned channel.
+func newTestHookServer(t *testing.T, status int, handler func(*hookRequest) *hookResponse) (*httptest.Server, <-chan *hookRequest) {
+	requests := make(chan *hookRequest, 10)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, http.MethodPost, r.Method)
+		assert.Equal(t, "application/json", r.Header.Get("Content-Type"))
+		request := &hookRequest{}
+		assert.NoError(t, json.NewDecoder(r.Body).Decode(request))
+		requests <- request
+
+		w.WriteHeader(status)
+		if response := handler(request); response != nil {
+			assert.NoError(t, json.NewEncoder(w).Encode(response))
+		}
+	}))
+	t.Cleanup(server.Close)
+	return server, requests
+}
+
+func mutateSparkConf(key, value string) func(*hookRequest) *hookResponse {
+	return func(request *hookRequest) *hookResponse {
+		app := request.SparkApplication.DeepCopy()
+		if app.Spec.SparkConf == nil {
+			app.Spec.SparkConf = map[string]string{}
+		}
+		app.Spec.SparkConf[key] = value
+		return &hookResponse{SparkApplication: app}
+	}
+}
+
+func noResponse(*hookRequest) *hookResponse {
+	return nil
+}
+
+func TestHookCall(t *testing.T) {
+	ctx := context.Background()
+	app := &v1beta2.SparkApplication{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "test-app",
+			Namespace: "default",
+		},
+	}
+
+	t.Run("returns the mutated application", func(t *testing.T) {
+		server, requests := newTestHookServer(t, http.StatusOK, mutateSparkConf("spark.foo", "bar"))
+		hook := &Hook{URL: server.URL}
+
+		mutated, err := hook.call(ctx, HookPhasePreSubmission, app)
+		require.NoError(t, err)
+		require.NotNil(t, mutated)
+		assert.Equal(t, "bar", mutated.Spec.SparkConf["spark.foo"])
+
+		request := <-requests
+		assert.Equal(t, HookPhasePreSubmission, request.Phase)
+		assert.Equal(t, "test-app", request.SparkApplication.Name)
+	})
+
+	t.Run("returns no application for an empty response", func(t *testing.T) {
+		server, _ := newTestHookServer(t, http.StatusNoContent, noResponse)
+		hook := &Hook{URL: server.URL}
+
+		mutated, err := hook.call(ctx, HookPhasePostCompletion, app)
+		require.NoError(t, err)
+		assert.Nil(t, mutated)
+	})
+
+	t.Run("fails on an error status", func(t *testing.T) {
+		server, _ := newTestHookServer(t, http.StatusInternalServerError, noResponse)
+		hook := &Hook{URL: server.URL}
+
+		_, err := hook.call(ctx, HookPhasePreSubmission, app)
+		assert.ErrorContains(t, err, "returned status 500")
+	})
+
+	t.Run("fails once the timeout expires", func(t *testing.T) {
+		release := make(chan struct{})
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			<-release
+		}))
+		t.Cleanup(server.Close)
+		t.Cleanup(func() { close(release) })
+		hook := &Hook{URL: server.URL, Timeout: 50 * time.Millisecond}
+
+		_, err := hook.call(ctx, HookPhasePreSubmission, app)
+		assert.ErrorContains(t, err, context.DeadlineExceeded.Error())
+	})
+}
@@ -0,0 +1,200 @@
+/*
+Copyright 2024 The Kubeflow authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package sparkapplication
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/kubeflow/spark-operator/api/v1beta2"
+	"github.com/kubeflow/spark-operator/pkg/common"
+	"github.com/kubeflow/spark-operator/pkg/naming"
+	"github.com/kubeflow/spark-operator/pkg/util"
+)
+
+const (
+	// defaultImagePrefetchTimeout is the default maximum time to wait for the executor image to be prefetched.
+	defaultImagePrefetchTimeout = 300 * time.Second
+
+	// imagePrefetchPollInterval is the interval at which the progress of image prefetching is checked.
+	imagePrefetchPollInterval = 5 * time.Second
+
+	// imagePrefetchContainerName is the name of the init container pulling the executor image.
+	imagePrefetchContainerName = "prefetch"
+)
+
+// prefetchExecutorImage ensures a DaemonSet pulling the executor image onto the candidate nodes of the executors
+// exists, and returns whether the application can be submitted, i.e. whether the image has been pulled onto all
+// candidate nodes or the prefetch timeout has expired. The DaemonSet is deleted once prefetching is done.
+func (r *Reconciler) prefetchExecutorImage(ctx context.Context, app *v1beta2.SparkApplication) (bool, error) {
+	name := naming.ImagePrefetchName(app)
+	daemonSet := &appsv1.DaemonSet{}
+	if err := r.manager.GetAPIReader().Get(ctx, client.ObjectKey{Namespace: app.Namespace, Name: name}, daemonSet); err != nil {
+		if !errors.IsNotFound(err) {
+			return true, fmt.Errorf("failed to get image prefetch daemonset %s: %v", name, err)
+		}
+
+		daemonSet, err = r.newImagePrefetchDaemonSet(app, name)
+		if err != nil {
+			return true, err
+		}
+		if err := r.client.Create(ctx, daemonSet); err != nil && !errors.IsAlreadyExists(err) {
+			return true, fmt.Errorf("failed to create image prefetch daemonset %s: %v", name, err)
+		}
+		appLogger(app).Info("Created image prefetch daemonset for SparkApplication", "daemonset", name)
+		return false, nil
+	}
+
+	timeout := defaultImagePrefetchTimeout
+	if app.Spec.ImagePrefetch.TimeoutSeconds != nil {
+		timeout = time.Duration(*app.Spec.ImagePrefetch.TimeoutSeconds) * time.Second
+	}
+	timedOut := time.Since(daemonSet.CreationTimestamp.Time) >= timeout
+	if !isImagePrefetchDone(daemonSet) && !timedOut {
+		return false, nil
+	}
+	if timedOut {
+		appLogger(app).Info("Timed out prefetching executor image",
+			"ready", daemonSet.Status.NumberReady, "desired", daemonSet.Status.DesiredNumberScheduled)
+	}
+
+	if err := r.client.Delete(ctx, daemonSet, client.PropagationPolicy(metav1.DeletePropagationBackground)); err != nil && !errors.IsNotFound(err) {
+		return true, fmt.Errorf("failed to delete image prefetch daemonset %s: %v", name, err)
+	}
+	return true, nil
+}
+
+// isImagePrefetchDone returns whether the prefetch pods are ready on all nodes the DaemonSet is scheduled to.
+func isImagePrefetchDone(daemonSet *appsv1.DaemonSet) bool {
+	status := daemonSet.Status
+	return status.ObservedGeneration >= daemonSet.Generation &&
+		status.DesiredNumberScheduled > 0 &&
+		status.NumberReady >= status.DesiredNumberScheduled
+}
+
+// newImagePrefetchDaemonSet returns a DaemonSet whose pods pull the executor image in an init container and are
+// scheduled onto the nodes matching the node selector, node affinity and tolerations of the executors.
+func (r *Reconciler) newImagePrefetchDaemonSet(app *v1beta2.SparkApplication, name string) (*appsv1.DaemonSet, error) {
+	image := getExecutorImage(app)
+	if image == "" {
+		return nil, fmt.Errorf("no executor image specified for SparkApplication %s", app.Name)
+	}
+
+	labels := map[string]string{
+		common.LabelSparkAppName: app.Name,
+		common.LabelSparkRole:    common.SparkRoleImagePrefetch,
+	}
+
+	nodeSelector := make(map[string]string)
+	for key, value := range app.Spec.NodeSelector {
+		nodeSelector[key] = value
+	}
+	for key, value := range app.Spec.Executor.NodeSelector {
+		nodeSelector[key] = value
+	}
+
+	var affinity *corev1.Affinity
+	if app.Spec.Executor.Affinity != nil && app.Spec.Executor.Affinity.NodeAffinity != nil {
+		affinity = &corev1.Affinity{NodeAffinity: app.Spec.Executor.Affinity.NodeAffinity.DeepCopy()}
+	}
+
+	var imagePullSecrets []corev1.LocalObjectReference
+	for _, secret := range app.Spec.ImagePullSecrets {
+		imagePullSecrets = append(imagePullSecrets, corev1.LocalObjectReference{Name: secret})
+	}
+
+	imagePullPolicy := corev1.PullIfNotPresent
+	if app.Spec.ImagePullPolicy != nil {
+		imagePullPolicy = corev1.PullPolicy(*app.Spec.ImagePullPolicy)
+	}
+
+	var priorityClassName string
+	if app.Spec.ImagePrefetch.PriorityClassName != nil {
+		priorityClassName = *app.Spec.ImagePrefetch.PriorityClassName
+	}
+
+	resources := corev1.ResourceRequirements{
+		Requests: corev1.ResourceList{
+			corev1.ResourceCPU:    resource.MustParse("1m"),
+			corev1.ResourceMemory: resource.MustParse("8Mi"),
+		},
+	}
+
+	daemonSet := &appsv1.DaemonSet{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:            name,
+			Namespace:       app.Namespace,
+			Labels:          labels,
+			OwnerReferences: []metav1.OwnerReference{util.GetOwnerReference(app)},
+		},
+		Spec: appsv1.DaemonSetSpec{
+			Selector: &metav1.LabelSelector{MatchLabels: labels},
+			Template: corev1.PodTemplateSpec{
+				ObjectMeta: metav1.ObjectMeta{
+					Labels: labels,
+				},
+				Spec: corev1.PodSpec{
+					InitContainers: []corev1.Container{
+						{
+							Name:            imagePrefetchContainerName,
+							Image:           image,
+							ImagePullPolicy: imagePullPolicy,
+							Command:         []string{"/bin/sh", "-c", "exit 0"},
+							Resources:       resources,
+						},
+					},
+					Containers: []corev1.Container{
+						{
+							Name:      "pause",
+							Image:     r.options.Scheduling.ImagePrefetchPauseImage,
+							Resources: resources,
+						},
+					},
+					NodeSelector:                  nodeSelector,
+					Affinity:                      affinity,
+					Tolerations:                   app.Spec.Executor.Tolerations,
+					ImagePullSecrets:              imagePullSecrets,
+					PriorityClassName:             priorityClassName,
+					TerminationGracePeriodSeconds: util.Int64Ptr(0),
+				},
+			},
+		},
+	}
+	return daemonSet, nil
+}
+
+// getExecutorImage returns the container image of the executors of the SparkApplication.
+func getExecutorImage(app *v1beta2.SparkApplication) string {
+	if app.Spec.Executor.Image != nil && *app.Spec.Executor.Image != "" {
+		return *app.Spec.Executor.Image
+	}
+	if image := app.Spec.SparkConf[common.SparkKubernetesExecutorContainerImage]; image != "" {
+		return image
+	}
+	if app.Spec.Image != nil {
+		return *app.Spec.Image
+	}
+	return app.Spec.SparkConf[common.SparkKubernetesContainerImage]
+}
@@ -0,0 +1,98 @@
+/*
+Copyright 2024 The Kubeflow authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package sparkapplication
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	authenticationv1 "k8s.io/api/authentication/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/rest"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/kubeflow/spark-operator/api/v1beta2"
+	"github.com/kubeflow/spark-operator/pkg/common"
+)
+
+// impersonationTokenExpirationSeconds is the lifetime of the tokens spark-submit authenticates with when impersonation
+// is enabled, which only need to outlive the submission.
+const impersonationTokenExpirationSeconds = 600
+
+// getImpersonatedUserName returns the user name of the service account impersonated for the given namespace.
+func (r *Reconciler) getImpersonatedUserName(namespace string) string {
+	return fmt.Sprintf("system:serviceaccount:%s:%s", namespace, r.options.Submission.ImpersonateServiceAccount)
+}
+
+// getDriverResourceClient returns the client to create the driver resources of the SparkApplication with. If
+// impersonation is enabled, the client impersonates the configured service account of the application namespace, so
+// that the API server authorizes the requests against the RBAC of that namespace and attributes them to it.
+func (r *Reconciler) getDriverResourceClient(app *v1beta2.SparkApplication) (client.Client, error) {
+	if r.options.Submission.ImpersonateServiceAccount == "" {
+		return r.client, nil
+	}
+
+	if c, ok := r.impersonatingClients.Load(app.Namespace); ok {
+		return c.(client.Client), nil
+	}
+
+	config := rest.CopyConfig(r.manager.GetConfig())
+	config.Impersonate = rest.ImpersonationConfig{UserName: r.getImpersonatedUserName(app.Namespace)}
+	c, err := client.New(config, client.Options{Scheme: r.scheme, Mapper: r.manager.GetRESTMapper()})
+	if err != nil {
+		return nil, fmt.Errorf("failed to create client impersonating %s: %v", config.Impersonate.UserName, err)
+	}
+	actual, _ := r.impersonatingClients.LoadOrStore(app.Namespace, c)
+	return actual.(client.Client), nil
+}
+
+// configImpersonation requests a short-lived token of the impersonated service account of the application namespace
+// and configures spark-submit to authenticate with it, so that the driver pod is created on behalf of that account.
+func (r *Reconciler) configImpersonation(ctx context.Context, app *v1beta2.SparkApplication) error {
+	serviceAccount := &corev1.ServiceAccount{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      r.options.Submission.ImpersonateServiceAccount,
+			Namespace: app.Namespace,
+		},
+	}
+	tokenRequest := &authenticationv1.TokenRequest{
+		Spec: authenticationv1.TokenRequestSpec{
+			ExpirationSeconds: ptr.To[int64](impersonationTokenExpirationSeconds),
+		},
+	}
+	if err := r.client.SubResource("token").Create(ctx, serviceAccount, tokenRequest); err != nil {
+		return fmt.Errorf("failed to request token of service account %s: %v", r.options.Submission.ImpersonateServiceAccount, err)
+	}
+
+	tokenFile := fmt.Sprintf("/tmp/spark/%s/submission-token", app.Status.SubmissionID)
+	if err := os.MkdirAll(filepath.Dir(tokenFile), 0755); err != nil {
+		return fmt.Errorf("failed to create directory of token file: %v", err)
+	}
+	if err := os.WriteFile(tokenFile, []byte(tokenRequest.Status.Token), 0600); err != nil {
+		return fmt.Errorf("failed to write token file: %v", err)
+	}
+
+	if app.Spec.SparkConf == nil {
+		app.Spec.SparkConf = make(map[string]string)
+	}
+	app.Spec.SparkConf[common.SparkKubernetesAuthenticateSubmissionOAuthTokenFile] = tokenFile
+	return nil
+}
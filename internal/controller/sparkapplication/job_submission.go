@@ -0,0 +1,353 @@
+/*
+Copyright 2024 The Kubeflow authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package sparkapplication
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/util/workqueue"
+	"k8s.io/utils/ptr"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+
+	"github.com/kubeflow/spark-operator/api/v1beta2"
+	"github.com/kubeflow/spark-operator/pkg/common"
+	"github.com/kubeflow/spark-operator/pkg/naming"
+	"github.com/kubeflow/spark-operator/pkg/util"
+)
+
+const (
+	// defaultSubmitterJobTimeout is the default maximum time to wait for a submitter Job to complete.
+	defaultSubmitterJobTimeout = 5 * time.Minute
+
+	// submitterContainerName is the name of the container running spark-submit in a submitter Job.
+	submitterContainerName = "spark-submit"
+
+	// submitterFilesVolumeName is the name of the volume holding the submission files in a submitter Job.
+	submitterFilesVolumeName = "submission-files"
+
+	// submitterCommand runs spark-submit of the Spark distribution in the image with the arguments that follow it.
+	submitterCommand = `exec "${SPARK_HOME:-/opt/spark}/bin/spark-submit" "$@"`
+)
+
+// runSubmitterJob submits the application by creating a Job running spark-submit in the namespace of the application,
+// so that spark-submit does not run in the operator process. It does not wait for the Job, whose completion is picked
+// up by reconcileSubmitterJob once the Job is updated. The Job runs as the driver service account and is retained
+// until the next submission, keeping the logs of spark-submit. The files in the submission directory, e.g. pod
+// templates, are mounted into the Job from a ConfigMap owned by the Job.
+func (r *Reconciler) runSubmitterJob(ctx context.Context, app *v1beta2.SparkApplication, sparkSubmitArgs []string) error {
+	files, err := readSubmissionFiles(app)
+	if err != nil {
+		return err
+	}
+	job, err := r.buildSubmitterJob(app, sparkSubmitArgs, len(files) > 0)
+	if err != nil {
+		return err
+	}
+
+	if err := r.deleteSubmitterJobs(ctx, app); err != nil {
+		return err
+	}
+	if err := r.client.Create(ctx, job); err != nil {
+		return fmt.Errorf("failed to create submitter job %s: %v", job.Name, err)
+	}
+
+	if len(files) > 0 {
+		configMap := &corev1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      job.Name,
+				Namespace: job.Namespace,
+				Labels:    job.Labels,
+				OwnerReferences: []metav1.OwnerReference{{
+					APIVersion: "batch/v1",
+					Kind:       "Job",
+					Name:       job.Name,
+					UID:        job.UID,
+					Controller: ptr.To(true),
+				}},
+			},
+			Data: files,
+		}
+		if err := r.client.Create(ctx, configMap); err != nil {
+			if deleteErr := r.client.Delete(ctx, job, client.PropagationPolicy(metav1.DeletePropagationBackground)); deleteErr != nil && !errors.IsNotFound(deleteErr) {
+				appLogger(app).Error(deleteErr, "Failed to delete submitter job after failed submission", "job", job.Name)
+			}
+			return fmt.Errorf("failed to create configmap of submitter job %s: %v", job.Name, err)
+		}
+	}
+	return nil
+}
+
+// buildSubmitterJob builds the Job running spark-submit with the given arguments for the current submission of the
+// application. The submission files are mounted from the ConfigMap named after the Job if mountFiles is true.
+func (r *Reconciler) buildSubmitterJob(app *v1beta2.SparkApplication, sparkSubmitArgs []string, mountFiles bool) (*batchv1.Job, error) {
+	image := r.options.Submission.SubmitterJobImage
+	if image == "" && app.Spec.Image != nil {
+		image = *app.Spec.Image
+	}
+	if image == "" && app.Spec.Driver.Image != nil {
+		image = *app.Spec.Driver.Image
+	}
+	if image == "" {
+		return nil, fmt.Errorf("no image to run the submitter job")
+	}
+
+	timeout := r.getSubmitterJobTimeout()
+	labels := map[string]string{
+		common.LabelSparkAppName: app.Name,
+		common.LabelSubmissionID: app.Status.SubmissionID,
+		common.LabelSubmitterJob: "true",
+	}
+	container := corev1.Container{
+		Name:                     submitterContainerName,
+		Image:                    image,
+		Command:                  []string{"/bin/sh", "-c", submitterCommand, submitterContainerName},
+		Args:                     sparkSubmitArgs,
+		TerminationMessagePolicy: corev1.TerminationMessageFallbackToLogsOnError,
+	}
+	if app.Spec.ImagePullPolicy != nil {
+		container.ImagePullPolicy = corev1.PullPolicy(*app.Spec.ImagePullPolicy)
+	}
+
+	podSpec := corev1.PodSpec{
+		ServiceAccountName: util.GetDriverServiceAccountName(app),
+		RestartPolicy:      corev1.RestartPolicyNever,
+		Containers:         []corev1.Container{container},
+	}
+	for _, secret := range app.Spec.ImagePullSecrets {
+		podSpec.ImagePullSecrets = append(podSpec.ImagePullSecrets, corev1.LocalObjectReference{Name: secret})
+	}
+
+	name := naming.SubmitterJobName(app)
+	if mountFiles {
+		podSpec.Volumes = []corev1.Volume{{
+			Name: submitterFilesVolumeName,
+			VolumeSource: corev1.VolumeSource{
+				ConfigMap: &corev1.ConfigMapVolumeSource{
+					LocalObjectReference: corev1.LocalObjectReference{Name: name},
+				},
+			},
+		}}
+		podSpec.Containers[0].VolumeMounts = []corev1.VolumeMount{{
+			Name:      submitterFilesVolumeName,
+			MountPath: getSubmissionDir(app),
+			ReadOnly:  true,
+		}}
+	}
+
+	return &batchv1.Job{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:            name,
+			Namespace:       app.Namespace,
+			Labels:          labels,
+			OwnerReferences: r.getOwnerReferences(app),
+		},
+		Spec: batchv1.JobSpec{
+			BackoffLimit:          ptr.To[int32](0),
+			ActiveDeadlineSeconds: ptr.To(int64(timeout.Seconds())),
+			Template: corev1.PodTemplateSpec{
+				ObjectMeta: metav1.ObjectMeta{
+					Labels: map[string]string{common.LabelSubmissionID: app.Status.SubmissionID},
+				},
+				Spec: podSpec,
+			},
+		},
+	}, nil
+}
+
+// reconcileSubmitterJob checks the submitter Job of the current submission of the application, and returns whether
+// the Job is done so that the driver state can be updated. If the Job failed, the application is moved to the failed
+// submission state with the error of spark-submit. The application is kept waiting while the Job is active, while a
+// Job which no longer exists leaves it to the driver pod creation grace period.
+func (r *Reconciler) reconcileSubmitterJob(ctx context.Context, app *v1beta2.SparkApplication) (bool, error) {
+	job := &batchv1.Job{}
+	key := types.NamespacedName{Namespace: app.Namespace, Name: naming.SubmitterJobName(app)}
+	if err := r.client.Get(ctx, key, job); err != nil {
+		if errors.IsNotFound(err) {
+			return true, nil
+		}
+		return false, fmt.Errorf("failed to get submitter job %s: %v", key.Name, err)
+	}
+
+	condition := getSubmitterJobFinishedCondition(job)
+	if condition == nil {
+		return false, nil
+	}
+	if condition.Type == batchv1.JobComplete {
+		return true, nil
+	}
+
+	jobErr := r.getSubmitterJobError(ctx, job, condition.Message)
+	appLogger(app).Info("Failed to submit SparkApplication", "state", app.Status.AppState.State, "error", jobErr)
+	app.Status.AppState = v1beta2.ApplicationState{
+		State:        v1beta2.ApplicationStateFailedSubmission,
+		ErrorMessage: fmt.Sprintf("failed to run spark-submit in a job: %v", jobErr),
+	}
+	// The submission was counted as an execution attempt when the Job was created.
+	if app.Status.ExecutionAttempts > 0 {
+		app.Status.ExecutionAttempts--
+	}
+	r.recordSparkApplicationEvent(app)
+	return false, nil
+}
+
+// getSubmitterJobFinishedCondition returns the Complete or Failed condition of the submitter Job if it finished.
+func getSubmitterJobFinishedCondition(job *batchv1.Job) *batchv1.JobCondition {
+	for i, condition := range job.Status.Conditions {
+		if condition.Status != corev1.ConditionTrue {
+			continue
+		}
+		if condition.Type == batchv1.JobComplete || condition.Type == batchv1.JobFailed {
+			return &job.Status.Conditions[i]
+		}
+	}
+	return nil
+}
+
+// getSubmitterJobError returns the error of the failed submitter Job, which is the termination message of the
+// spark-submit container holding the tail of its logs if available, or else the given message of the Job.
+func (r *Reconciler) getSubmitterJobError(ctx context.Context, job *batchv1.Job, message string) error {
+	pods := &corev1.PodList{}
+	if err := r.manager.GetAPIReader().List(ctx, pods, client.InNamespace(job.Namespace), client.MatchingLabels{batchv1.JobNameLabel: job.Name}); err != nil {
+		logger.Error(err, "Failed to list pods of submitter job", "name", job.Name, "namespace", job.Namespace)
+	}
+	for _, pod := range pods.Items {
+		for _, status := range pod.Status.ContainerStatuses {
+			if status.Name != submitterContainerName || status.State.Terminated == nil || status.State.Terminated.Message == "" {
+				continue
+			}
+			message = status.State.Terminated.Message
+		}
+	}
+	// The driver pod of the application already exists.
+	if strings.Contains(message, common.ErrorCodePodAlreadyExists) {
+		return fmt.Errorf("driver pod already exist")
+	}
+	return fmt.Errorf("submitter job %s failed: %s", job.Name, message)
+}
+
+// deleteSubmitterJobs deletes the submitter Jobs of the previous submissions of the application with their pods.
+func (r *Reconciler) deleteSubmitterJobs(ctx context.Context, app *v1beta2.SparkApplication) error {
+	if err := r.client.DeleteAllOf(
+		ctx,
+		&batchv1.Job{},
+		client.InNamespace(app.Namespace),
+		client.MatchingLabels{common.LabelSparkAppName: app.Name, common.LabelSubmitterJob: "true"},
+		client.PropagationPolicy(metav1.DeletePropagationBackground),
+	); err != nil {
+		return fmt.Errorf("failed to delete previous submitter jobs: %v", err)
+	}
+	return nil
+}
+
+// getSubmitterJobTimeout returns the maximum time a submitter Job may run.
+func (r *Reconciler) getSubmitterJobTimeout() time.Duration {
+	if r.options.Submission.SubmitterJobTimeout > 0 {
+		return r.options.Submission.SubmitterJobTimeout
+	}
+	return defaultSubmitterJobTimeout
+}
+
+// getSubmissionDir returns the local directory holding the files of the current submission of the application.
+func getSubmissionDir(app *v1beta2.SparkApplication) string {
+	return fmt.Sprintf("/tmp/spark/%s", app.Status.SubmissionID)
+}
+
+// readSubmissionFiles reads the files in the submission directory of the application by their names.
+func readSubmissionFiles(app *v1beta2.SparkApplication) (map[string]string, error) {
+	dir := getSubmissionDir(app)
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to read submission directory: %v", err)
+	}
+
+	files := make(map[string]string)
+	for _, entry := range entries {
+		if entry.IsDir() {
+			continue
+		}
+		data, err := os.ReadFile(filepath.Join(dir, entry.Name()))
+		if err != nil {
+			return nil, fmt.Errorf("failed to read submission file %s: %v", entry.Name(), err)
+		}
+		files[entry.Name()] = string(data)
+	}
+	return files, nil
+}
+
+// submitterJobEventHandler enqueues the SparkApplication of a submitter Job once the Job finishes or is deleted.
+type submitterJobEventHandler struct{}
+
+// submitterJobEventHandler implements handler.EventHandler.
+var _ handler.EventHandler = &submitterJobEventHandler{}
+
+// newSubmitterJobEventHandler creates a new submitterJobEventHandler instance.
+func newSubmitterJobEventHandler() *submitterJobEventHandler {
+	return &submitterJobEventHandler{}
+}
+
+// Create implements handler.EventHandler.
+func (h *submitterJobEventHandler) Create(ctx context.Context, event event.CreateEvent, queue workqueue.TypedRateLimitingInterface[ctrl.Request]) {
+}
+
+// Update implements handler.EventHandler.
+func (h *submitterJobEventHandler) Update(ctx context.Context, event event.UpdateEvent, queue workqueue.TypedRateLimitingInterface[ctrl.Request]) {
+	oldJob, ok := event.ObjectOld.(*batchv1.Job)
+	if !ok {
+		return
+	}
+	newJob, ok := event.ObjectNew.(*batchv1.Job)
+	if !ok {
+		return
+	}
+	if getSubmitterJobFinishedCondition(oldJob) != nil || getSubmitterJobFinishedCondition(newJob) == nil {
+		return
+	}
+	h.enqueue(newJob, queue)
+}
+
+// Delete implements handler.EventHandler.
+func (h *submitterJobEventHandler) Delete(ctx context.Context, event event.DeleteEvent, queue workqueue.TypedRateLimitingInterface[ctrl.Request]) {
+	h.enqueue(event.Object, queue)
+}
+
+// Generic implements handler.EventHandler.
+func (h *submitterJobEventHandler) Generic(ctx context.Context, event event.GenericEvent, queue workqueue.TypedRateLimitingInterface[ctrl.Request]) {
+}
+
+func (h *submitterJobEventHandler) enqueue(job client.Object, queue workqueue.TypedRateLimitingInterface[ctrl.Request]) {
+	name := job.GetLabels()[common.LabelSparkAppName]
+	if name == "" || job.GetLabels()[common.LabelSubmitterJob] != "true" {
+		return
+	}
+	queue.Add(ctrl.Request{NamespacedName: types.NamespacedName{Namespace: job.GetNamespace(), Name: name}})
+}
@@ -0,0 +1,133 @@
+/*
+Copyright 2024 The Kubeflow authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package sparkapplication
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/util/workqueue"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+
+	"github.com/kubeflow/spark-operator/api/v1beta2"
+	"github.com/kubeflow/spark-operator/pkg/common"
+	"github.com/kubeflow/spark-operator/pkg/naming"
+)
+
+// newTestJobSubmittedSparkApplication returns a SparkApplication submitted with the Job submission engine.
+func newTestJobSubmittedSparkApplication() *v1beta2.SparkApplication {
+	engine := v1beta2.SubmissionEngineJob
+	return &v1beta2.SparkApplication{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "test-app",
+			Namespace: "default",
+		},
+		Spec: v1beta2.SparkApplicationSpec{
+			SubmissionEngine: &engine,
+		},
+		Status: v1beta2.SparkApplicationStatus{
+			SubmissionID: "test-submission-id",
+		},
+	}
+}
+
+// newTestSubmitterJob returns the submitter Job of the app with the given finished condition, if any.
+func newTestSubmitterJob(app *v1beta2.SparkApplication, conditionType batchv1.JobConditionType, message string) *batchv1.Job {
+	job := &batchv1.Job{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      naming.SubmitterJobName(app),
+			Namespace: app.Namespace,
+			Labels: map[string]string{
+				common.LabelSparkAppName: app.Name,
+				common.LabelSubmissionID: app.Status.SubmissionID,
+				common.LabelSubmitterJob: "true",
+			},
+		},
+	}
+	if conditionType != "" {
+		job.Status.Conditions = []batchv1.JobCondition{{Type: conditionType, Status: corev1.ConditionTrue, Message: message}}
+	}
+	return job
+}
+
+func TestSubmitterJobEventHandler(t *testing.T) {
+	app := newTestJobSubmittedSparkApplication()
+	active := newTestSubmitterJob(app, "", "")
+	completed := newTestSubmitterJob(app, batchv1.JobComplete, "")
+	unlabeled := completed.DeepCopy()
+	unlabeled.Labels = nil
+
+	testCases := []struct {
+		name     string
+		send     func(*submitterJobEventHandler, workqueue.TypedRateLimitingInterface[ctrl.Request])
+		expected int
+	}{
+		{
+			name: "job finished",
+			send: func(h *submitterJobEventHandler, q workqueue.TypedRateLimitingInterface[ctrl.Request]) {
+				h.Update(context.TODO(), event.UpdateEvent{ObjectOld: active, ObjectNew: completed}, q)
+			},
+			expected: 1,
+		},
+		{
+			name: "job still active",
+			send: func(h *submitterJobEventHandler, q workqueue.TypedRateLimitingInterface[ctrl.Request]) {
+				h.Update(context.TODO(), event.UpdateEvent{ObjectOld: active, ObjectNew: active}, q)
+			},
+		},
+		{
+			name: "job already finished",
+			send: func(h *submitterJobEventHandler, q workqueue.TypedRateLimitingInterface[ctrl.Request]) {
+				h.Update(context.TODO(), event.UpdateEvent{ObjectOld: completed, ObjectNew: completed}, q)
+			},
+		},
+		{
+			name: "job deleted",
+			send: func(h *submitterJobEventHandler, q workqueue.TypedRateLimitingInterface[ctrl.Request]) {
+				h.Delete(context.TODO(), event.DeleteEvent{Object: active}, q)
+			},
+			expected: 1,
+		},
+		{
+			name: "job not created by the operator",
+			send: func(h *submitterJobEventHandler, q workqueue.TypedRateLimitingInterface[ctrl.Request]) {
+				h.Update(context.TODO(), event.UpdateEvent{ObjectOld: active, ObjectNew: unlabeled}, q)
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			queue := workqueue.NewTypedRateLimitingQueue(workqueue.DefaultTypedControllerRateLimiter[ctrl.Request]())
+			defer queue.ShutDown()
+
+			tc.send(newSubmitterJobEventHandler(), queue)
+			require.Equal(t, tc.expected, queue.Len())
+			if tc.expected > 0 {
+				request, _ := queue.Get()
+				assert.Equal(t, types.NamespacedName{Namespace: app.Namespace, Name: app.Name}, request.NamespacedName)
+			}
+		})
+	}
+}
@@ -0,0 +1,192 @@
+/*
+Copyright 2024 The Kubeflow authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package sparkapplication
+
+import (
+	"context"
+	"fmt"
+
+	corev1 "k8s.io/api/core/v1"
+	policyv1 "k8s.io/api/policy/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/util/workqueue"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+
+	"github.com/kubeflow/spark-operator/api/v1beta2"
+	"github.com/kubeflow/spark-operator/pkg/common"
+	"github.com/kubeflow/spark-operator/pkg/naming"
+	"github.com/kubeflow/spark-operator/pkg/util"
+)
+
+const (
+	// podNodeNameField is the field index of pods by the name of the node they are scheduled to.
+	podNodeNameField = "spec.nodeName"
+)
+
+// configKarpenterDisruptionProtection annotates the driver pod so that Karpenter does not voluntarily disrupt its
+// node, and enables graceful decommissioning of executors unless configured otherwise.
+func configKarpenterDisruptionProtection(app *v1beta2.SparkApplication) {
+	if app.Spec.SparkConf == nil {
+		app.Spec.SparkConf = make(map[string]string)
+	}
+	if _, ok := app.Spec.Driver.Annotations[common.KarpenterDoNotDisruptAnnotation]; !ok {
+		property := fmt.Sprintf(common.SparkKubernetesDriverAnnotationTemplate, common.KarpenterDoNotDisruptAnnotation)
+		app.Spec.SparkConf[property] = "true"
+	}
+	if _, ok := app.Spec.SparkConf[common.SparkDecommissionEnabled]; !ok {
+		app.Spec.SparkConf[common.SparkDecommissionEnabled] = "true"
+	}
+}
+
+// createExecutorPodDisruptionBudget creates or updates the PodDisruptionBudget limiting the number of executors
+// of the SparkApplication voluntarily disrupted at once.
+func (r *Reconciler) createExecutorPodDisruptionBudget(ctx context.Context, app *v1beta2.SparkApplication) error {
+	name := naming.ExecutorPDBName(app)
+	pdb := &policyv1.PodDisruptionBudget{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:            name,
+			Namespace:       app.Namespace,
+			Labels:          map[string]string{common.LabelSparkAppName: app.Name},
+			OwnerReferences: []metav1.OwnerReference{util.GetOwnerReference(app)},
+		},
+		Spec: policyv1.PodDisruptionBudgetSpec{
+			MaxUnavailable: app.Spec.Executor.DisruptionBudget.MaxUnavailable,
+			Selector: &metav1.LabelSelector{
+				MatchLabels: map[string]string{
+					common.LabelSparkAppName: app.Name,
+					common.LabelSparkRole:    common.SparkRoleExecutor,
+				},
+			},
+		},
+	}
+
+	if err := r.client.Create(ctx, pdb); err != nil {
+		if !errors.IsAlreadyExists(err) {
+			return fmt.Errorf("failed to create pod disruption budget %s: %v", name, err)
+		}
+
+		existing := &policyv1.PodDisruptionBudget{}
+		if err := r.manager.GetAPIReader().Get(ctx, client.ObjectKeyFromObject(pdb), existing); err != nil {
+			return fmt.Errorf("failed to get pod disruption budget %s: %v", name, err)
+		}
+		existing.Spec.MaxUnavailable = pdb.Spec.MaxUnavailable
+		existing.Spec.MinAvailable = nil
+		if err := r.client.Update(ctx, existing); err != nil {
+			return fmt.Errorf("failed to update pod disruption budget %s: %v", name, err)
+		}
+	}
+	appLogger(app).Info("Created pod disruption budget for executors of SparkApplication", "pdb", name)
+	return nil
+}
+
+// decommissionExecutorOnDisruptedNode gracefully deletes the executor pod if Karpenter has tainted its node for
+// disruption, so that Spark decommissions the executor and migrates its blocks before the node is drained.
+func (r *Reconciler) decommissionExecutorOnDisruptedNode(ctx context.Context, app *v1beta2.SparkApplication, pod *corev1.Pod) error {
+	if pod.DeletionTimestamp != nil || pod.Spec.NodeName == "" {
+		return nil
+	}
+
+	node := &corev1.Node{}
+	if err := r.client.Get(ctx, types.NamespacedName{Name: pod.Spec.NodeName}, node); err != nil {
+		if errors.IsNotFound(err) {
+			return nil
+		}
+		return fmt.Errorf("failed to get node %s: %v", pod.Spec.NodeName, err)
+	}
+	if !util.IsNodeDisruptedByKarpenter(node) {
+		return nil
+	}
+
+	appLogger(app).Info("Decommissioning executor on node disrupted by Karpenter", "executor", pod.Name, "node", node.Name)
+	r.recorder.Eventf(app, corev1.EventTypeNormal, common.EventSparkExecutorDecommissioning, "Decommissioning executor %s as node %s is being disrupted", pod.Name, node.Name)
+	if err := r.client.Delete(ctx, pod); err != nil && !errors.IsNotFound(err) {
+		return fmt.Errorf("failed to delete executor pod %s: %v", pod.Name, err)
+	}
+	return nil
+}
+
+// indexPodByNodeName indexes pods by the name of the node they are scheduled to.
+func indexPodByNodeName(obj client.Object) []string {
+	pod, ok := obj.(*corev1.Pod)
+	if !ok || pod.Spec.NodeName == "" {
+		return nil
+	}
+	return []string{pod.Spec.NodeName}
+}
+
+// karpenterNodeEventHandler enqueues the SparkApplications with executors on nodes Karpenter taints for disruption.
+type karpenterNodeEventHandler struct {
+	client client.Client
+}
+
+// karpenterNodeEventHandler implements handler.EventHandler.
+var _ handler.EventHandler = &karpenterNodeEventHandler{}
+
+// newKarpenterNodeEventHandler creates a new karpenterNodeEventHandler instance.
+func newKarpenterNodeEventHandler(client client.Client) *karpenterNodeEventHandler {
+	return &karpenterNodeEventHandler{client: client}
+}
+
+// Create implements handler.EventHandler.
+func (h *karpenterNodeEventHandler) Create(ctx context.Context, event event.CreateEvent, queue workqueue.TypedRateLimitingInterface[ctrl.Request]) {
+}
+
+// Update implements handler.EventHandler.
+func (h *karpenterNodeEventHandler) Update(ctx context.Context, event event.UpdateEvent, queue workqueue.TypedRateLimitingInterface[ctrl.Request]) {
+	oldNode, ok := event.ObjectOld.(*corev1.Node)
+	if !ok {
+		return
+	}
+	newNode, ok := event.ObjectNew.(*corev1.Node)
+	if !ok {
+		return
+	}
+	if util.IsNodeDisruptedByKarpenter(oldNode) || !util.IsNodeDisruptedByKarpenter(newNode) {
+		return
+	}
+
+	pods := &corev1.PodList{}
+	if err := h.client.List(ctx, pods,
+		client.MatchingFields{podNodeNameField: newNode.Name},
+		client.MatchingLabels{common.LabelSparkRole: common.SparkRoleExecutor},
+	); err != nil {
+		logger.Error(err, "Failed to list executor pods on node disrupted by Karpenter", "node", newNode.Name)
+		return
+	}
+
+	logger.Info("Node disrupted by Karpenter", "node", newNode.Name, "executors", len(pods.Items))
+	for _, pod := range pods.Items {
+		name := util.GetAppName(&pod)
+		if name == "" {
+			continue
+		}
+		queue.AddRateLimited(ctrl.Request{NamespacedName: types.NamespacedName{Namespace: pod.Namespace, Name: name}})
+	}
+}
+
+// Delete implements handler.EventHandler.
+func (h *karpenterNodeEventHandler) Delete(ctx context.Context, event event.DeleteEvent, queue workqueue.TypedRateLimitingInterface[ctrl.Request]) {
+}
+
+// Generic implements handler.EventHandler.
+func (h *karpenterNodeEventHandler) Generic(ctx context.Context, event event.GenericEvent, queue workqueue.TypedRateLimitingInterface[ctrl.Request]) {
+}
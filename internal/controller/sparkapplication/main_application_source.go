@@ -0,0 +1,99 @@
+/*
+Copyright 2024 The Kubeflow authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package sparkapplication
+
+import (
+	"context"
+	"fmt"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/util/retry"
+
+	"github.com/kubeflow/spark-operator/api/v1beta2"
+	"github.com/kubeflow/spark-operator/pkg/common"
+	"github.com/kubeflow/spark-operator/pkg/util"
+)
+
+// createOrUpdateMainApplicationSourceConfigMap writes the inline source code of the main application of the app into
+// the ConfigMap that is mounted into the driver pod, creating the ConfigMap if it does not exist yet.
+func (r *Reconciler) createOrUpdateMainApplicationSourceConfigMap(ctx context.Context, app *v1beta2.SparkApplication) error {
+	if !util.IsMainApplicationSourceInline(app) {
+		return nil
+	}
+
+	configMapName, key, err := util.GetMainApplicationFileConfigMap(app)
+	if err != nil {
+		return err
+	}
+	data := map[string]string{key: *app.Spec.MainApplicationSource.Inline}
+	if err := r.createOrUpdateAppConfigMap(ctx, app, configMapName, data); err != nil {
+		return fmt.Errorf("failed to create or update ConfigMap %s for inline main application source: %v", configMapName, err)
+	}
+	return nil
+}
+
+// createOrUpdateAppConfigMap makes the ConfigMap with the given name owned by the app hold exactly the given data,
+// creating the ConfigMap if it does not exist yet.
+func (r *Reconciler) createOrUpdateAppConfigMap(
+	ctx context.Context,
+	app *v1beta2.SparkApplication,
+	name string,
+	data map[string]string,
+) error {
+	driverResourceClient, err := r.getDriverResourceClient(app)
+	if err != nil {
+		return err
+	}
+
+	configMap := &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:            name,
+			Namespace:       app.Namespace,
+			Labels:          map[string]string{common.LabelSparkAppName: app.Name},
+			OwnerReferences: r.getOwnerReferences(app),
+		},
+		Data: data,
+	}
+
+	return retry.RetryOnConflict(retry.DefaultRetry, func() error {
+		cm := &corev1.ConfigMap{}
+		if err := driverResourceClient.Get(ctx, types.NamespacedName{Namespace: configMap.Namespace, Name: configMap.Name}, cm); err != nil {
+			if errors.IsNotFound(err) {
+				appLogger(app).V(1).Info("Creating ConfigMap", "ConfigMap name", name)
+				return driverResourceClient.Create(ctx, configMap)
+			}
+			return err
+		}
+
+		if equality.Semantic.DeepEqual(cm.Data, configMap.Data) &&
+			equality.Semantic.DeepEqual(cm.OwnerReferences, configMap.OwnerReferences) {
+			return nil
+		}
+		cm.Data = configMap.Data
+		cm.BinaryData = nil
+		if cm.Labels == nil {
+			cm.Labels = make(map[string]string)
+		}
+		cm.Labels[common.LabelSparkAppName] = app.Name
+		cm.OwnerReferences = configMap.OwnerReferences
+		return driverResourceClient.Update(ctx, cm)
+	})
+}
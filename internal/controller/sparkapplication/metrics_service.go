@@ -0,0 +1,130 @@
+/*
+Copyright 2024 The Kubeflow authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package sparkapplication
+
+import (
+	"context"
+	"fmt"
+	"maps"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/kubeflow/spark-operator/api/v1beta2"
+	"github.com/kubeflow/spark-operator/pkg/common"
+	"github.com/kubeflow/spark-operator/pkg/naming"
+	"github.com/kubeflow/spark-operator/pkg/util"
+)
+
+// createDriverMetricsService creates or updates the ClusterIP Service exposing the Prometheus JMX exporter port of
+// the driver. The Service carries labels that are stable across runs of the app so that ServiceMonitors and similar
+// scraping solutions can select it without knowing the submission ID.
+func (r *Reconciler) createDriverMetricsService(ctx context.Context, app *v1beta2.SparkApplication) error {
+	if !util.DriverMetricsServiceEnabled(app) {
+		return nil
+	}
+
+	port := common.DefaultPrometheusJavaAgentPort
+	if app.Spec.Monitoring.Prometheus.Port != nil {
+		port = *app.Spec.Monitoring.Prometheus.Port
+	}
+	portName := common.DefaultPrometheusPortName
+	if app.Spec.Monitoring.Prometheus.PortName != nil {
+		portName = *app.Spec.Monitoring.Prometheus.PortName
+	}
+
+	spec := app.Spec.Monitoring.DriverMetricsService
+	labels := maps.Clone(spec.Labels)
+	if labels == nil {
+		labels = map[string]string{}
+	}
+	labels[common.LabelSparkAppName] = app.Name
+	labels[common.LabelMetricsService] = common.SparkRoleDriver
+
+	service := &corev1.Service{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:            naming.DriverMetricsServiceName(app),
+			Namespace:       app.Namespace,
+			Labels:          labels,
+			Annotations:     spec.Annotations,
+			OwnerReferences: r.getOwnerReferences(app),
+		},
+		Spec: corev1.ServiceSpec{
+			Type: corev1.ServiceTypeClusterIP,
+			Ports: []corev1.ServicePort{{
+				Name:       portName,
+				Port:       port,
+				TargetPort: intstr.FromInt32(port),
+				Protocol:   corev1.Protocol(common.DefaultPrometheusPortProtocol),
+			}},
+			Selector: map[string]string{
+				common.LabelSparkAppName: app.Name,
+				common.LabelSparkRole:    common.SparkRoleDriver,
+			},
+		},
+	}
+
+	existing := &corev1.Service{}
+	if err := r.client.Get(ctx, client.ObjectKeyFromObject(service), existing); err != nil {
+		if !errors.IsNotFound(err) {
+			return fmt.Errorf("failed to get driver metrics service %s/%s: %v", service.Namespace, service.Name, err)
+		}
+		logger.Info("Creating driver metrics service", "name", service.Name, "namespace", service.Namespace, "port", port)
+		if err := r.client.Create(ctx, service); err != nil {
+			return fmt.Errorf("failed to create driver metrics service %s/%s: %v", service.Namespace, service.Name, err)
+		}
+		return nil
+	}
+
+	if equality.Semantic.DeepEqual(existing.Labels, service.Labels) &&
+		equality.Semantic.DeepEqual(existing.Annotations, service.Annotations) &&
+		equality.Semantic.DeepEqual(existing.Spec.Ports, service.Spec.Ports) &&
+		equality.Semantic.DeepEqual(existing.Spec.Selector, service.Spec.Selector) {
+		return nil
+	}
+	existing.Labels = service.Labels
+	existing.Annotations = service.Annotations
+	existing.Spec.Ports = service.Spec.Ports
+	existing.Spec.Selector = service.Spec.Selector
+	logger.Info("Updating driver metrics service", "name", service.Name, "namespace", service.Namespace, "port", port)
+	if err := r.client.Update(ctx, existing); err != nil {
+		return fmt.Errorf("failed to update driver metrics service %s/%s: %v", service.Namespace, service.Name, err)
+	}
+	return nil
+}
+
+// deleteDriverMetricsService deletes the Service exposing the metrics port of the driver, if any.
+func (r *Reconciler) deleteDriverMetricsService(ctx context.Context, app *v1beta2.SparkApplication) error {
+	if !util.DriverMetricsServiceEnabled(app) {
+		return nil
+	}
+
+	service := &corev1.Service{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      naming.DriverMetricsServiceName(app),
+			Namespace: app.Namespace,
+		},
+	}
+	if err := r.client.Delete(ctx, service); err != nil && !errors.IsNotFound(err) {
+		return fmt.Errorf("failed to delete driver metrics service %s/%s: %v", service.Namespace, service.Name, err)
+	}
+	return nil
+}
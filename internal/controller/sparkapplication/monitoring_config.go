@@ -22,6 +22,7 @@ import (
 
 	"github.com/golang/glog"
 	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
 	"k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/types"
@@ -33,32 +34,14 @@ import (
 	"github.com/kubeflow/spark-operator/pkg/util"
 )
 
-func configPrometheusMonitoring(app *v1beta2.SparkApplication, client client.Client) error {
+func configPrometheusMonitoring(app *v1beta2.SparkApplication, client client.Client, ownerReferences []metav1.OwnerReference) error {
 	port := common.DefaultPrometheusJavaAgentPort
 	if app.Spec.Monitoring.Prometheus.Port != nil {
 		port = *app.Spec.Monitoring.Prometheus.Port
 	}
 
-	// If one or both of the metricsPropertiesFile and Prometheus.ConfigFile are not set
-	if !util.HasMetricsPropertiesFile(app) || !util.HasPrometheusConfigFile(app) {
-		logger.V(1).Info("Creating a ConfigMap for metrics and Prometheus configurations")
-		configMapName := util.GetPrometheusConfigMapName(app)
-		configMap := buildPrometheusConfigMap(app, configMapName)
-		key := types.NamespacedName{Namespace: configMap.Namespace, Name: configMap.Name}
-		if retryErr := retry.RetryOnConflict(retry.DefaultRetry, func() error {
-			cm := &corev1.ConfigMap{}
-			if err := client.Get(context.TODO(), key, cm); err != nil {
-				if errors.IsNotFound(err) {
-					return client.Create(context.TODO(), configMap)
-				}
-				return err
-			}
-			cm.Data = configMap.Data
-			return client.Update(context.TODO(), cm)
-		}); retryErr != nil {
-			logger.Error(retryErr, "Failed to create/update Prometheus ConfigMap for SparkApplication", "name", app.Name, "ConfigMap name", configMap.Name, "namespace", app.Namespace)
-			return retryErr
-		}
+	if err := createOrUpdatePrometheusConfigMap(context.TODO(), client, app, ownerReferences); err != nil {
+		return err
 	}
 
 	var javaOption string
@@ -122,7 +105,57 @@ func configPrometheusMonitoring(app *v1beta2.SparkApplication, client client.Cli
 	return nil
 }
 
-func buildPrometheusConfigMap(app *v1beta2.SparkApplication, prometheusConfigMapName string) *corev1.ConfigMap {
+// reconcilePrometheusConfigMap keeps the Prometheus ConfigMap of the running app in line with its spec.
+func (r *Reconciler) reconcilePrometheusConfigMap(ctx context.Context, app *v1beta2.SparkApplication) error {
+	driverResourceClient, err := r.getDriverResourceClient(app)
+	if err != nil {
+		return err
+	}
+	return createOrUpdatePrometheusConfigMap(ctx, driverResourceClient, app, r.getOwnerReferences(app))
+}
+
+// createOrUpdatePrometheusConfigMap creates the ConfigMap for metrics and Prometheus configurations of the app if one or both
+// of them are not provided as files in the Spark image, and restores its data, label and owner references if they
+// have been modified or lost, e.g. by manual edits or restores from backups.
+func createOrUpdatePrometheusConfigMap(ctx context.Context, client client.Client, app *v1beta2.SparkApplication, ownerReferences []metav1.OwnerReference) error {
+	if util.HasMetricsPropertiesFile(app) && util.HasPrometheusConfigFile(app) {
+		return nil
+	}
+
+	configMapName := util.GetPrometheusConfigMapName(app)
+	configMap := buildPrometheusConfigMap(app, configMapName, ownerReferences)
+	key := types.NamespacedName{Namespace: configMap.Namespace, Name: configMap.Name}
+	if retryErr := retry.RetryOnConflict(retry.DefaultRetry, func() error {
+		cm := &corev1.ConfigMap{}
+		if err := client.Get(ctx, key, cm); err != nil {
+			if errors.IsNotFound(err) {
+				logger.V(1).Info("Creating a ConfigMap for metrics and Prometheus configurations", "name", configMapName, "namespace", app.Namespace)
+				return client.Create(ctx, configMap)
+			}
+			return err
+		}
+
+		if equality.Semantic.DeepEqual(cm.Data, configMap.Data) &&
+			cm.Labels[common.LabelSparkAppName] == app.Name &&
+			equality.Semantic.DeepEqual(cm.OwnerReferences, configMap.OwnerReferences) {
+			return nil
+		}
+		logger.Info("Restoring modified Prometheus ConfigMap of SparkApplication", "name", app.Name, "namespace", app.Namespace, "ConfigMap name", configMapName)
+		cm.Data = configMap.Data
+		if cm.Labels == nil {
+			cm.Labels = make(map[string]string)
+		}
+		cm.Labels[common.LabelSparkAppName] = app.Name
+		cm.OwnerReferences = configMap.OwnerReferences
+		return client.Update(ctx, cm)
+	}); retryErr != nil {
+		logger.Error(retryErr, "Failed to create/update Prometheus ConfigMap for SparkApplication", "name", app.Name, "ConfigMap name", configMapName, "namespace", app.Namespace)
+		return retryErr
+	}
+	return nil
+}
+
+func buildPrometheusConfigMap(app *v1beta2.SparkApplication, prometheusConfigMapName string, ownerReferences []metav1.OwnerReference) *corev1.ConfigMap {
 	configMapData := make(map[string]string)
 
 	if !util.HasMetricsPropertiesFile(app) {
@@ -145,7 +178,8 @@ func buildPrometheusConfigMap(app *v1beta2.SparkApplication, prometheusConfigMap
 		ObjectMeta: metav1.ObjectMeta{
 			Name:            prometheusConfigMapName,
 			Namespace:       app.Namespace,
-			OwnerReferences: []metav1.OwnerReference{util.GetOwnerReference(app)},
+			Labels:          map[string]string{common.LabelSparkAppName: app.Name},
+			OwnerReferences: ownerReferences,
 		},
 		Data: configMapData,
 	}
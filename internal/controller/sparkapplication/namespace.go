@@ -0,0 +1,136 @@
+/*
+Copyright 2024 The Kubeflow authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package sparkapplication
+
+import (
+	"context"
+	"fmt"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/util/retry"
+	"k8s.io/client-go/util/workqueue"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+
+	"github.com/kubeflow/spark-operator/api/v1beta2"
+	"github.com/kubeflow/spark-operator/pkg/util"
+)
+
+// isNamespaceTerminating returns whether the given namespace is being deleted.
+func (r *Reconciler) isNamespaceTerminating(ctx context.Context, name string) (bool, error) {
+	namespace := &corev1.Namespace{}
+	if err := r.client.Get(ctx, client.ObjectKey{Name: name}, namespace); err != nil {
+		if errors.IsNotFound(err) {
+			return true, nil
+		}
+		return false, fmt.Errorf("failed to get namespace %s: %v", name, err)
+	}
+	return isNamespaceTerminating(namespace), nil
+}
+
+// reconcileSparkApplicationInTerminatingNamespace moves a SparkApplication in a namespace being deleted directly
+// to the failed state without retries or cleanup, as the namespace deletion removes all of its resources and
+// rejects the creation of new ones.
+func (r *Reconciler) reconcileSparkApplicationInTerminatingNamespace(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	key := req.NamespacedName
+	retryErr := retry.RetryOnConflict(
+		retry.DefaultRetry,
+		func() error {
+			old, err := r.getSparkApplication(ctx, key)
+			if err != nil {
+				return err
+			}
+			if util.IsTerminated(old) {
+				return nil
+			}
+			app := old.DeepCopy()
+			app.Status.AppState = v1beta2.ApplicationState{
+				State:        v1beta2.ApplicationStateFailed,
+				ErrorMessage: fmt.Sprintf("namespace %s is being terminated", app.Namespace),
+			}
+			app.Status.TerminationTime = metav1.Now()
+			r.recordSparkApplicationEvent(app)
+			return r.updateSparkApplicationStatus(ctx, old, app)
+		},
+	)
+	if retryErr != nil && !errors.IsNotFound(retryErr) {
+		logger.Error(retryErr, "Failed to reconcile SparkApplication in terminating namespace", "name", key.Name, "namespace", key.Namespace)
+		return ctrl.Result{}, retryErr
+	}
+	return ctrl.Result{}, nil
+}
+
+// isNamespaceTerminating returns whether the given namespace is being deleted.
+func isNamespaceTerminating(namespace *corev1.Namespace) bool {
+	return !namespace.DeletionTimestamp.IsZero() || namespace.Status.Phase == corev1.NamespaceTerminating
+}
+
+// namespaceEventHandler enqueues the SparkApplications in namespaces which start being deleted.
+type namespaceEventHandler struct {
+	client client.Client
+}
+
+// namespaceEventHandler implements handler.EventHandler.
+var _ handler.EventHandler = &namespaceEventHandler{}
+
+// newNamespaceEventHandler creates a new namespaceEventHandler instance.
+func newNamespaceEventHandler(client client.Client) *namespaceEventHandler {
+	return &namespaceEventHandler{client: client}
+}
+
+// Create implements handler.EventHandler.
+func (h *namespaceEventHandler) Create(ctx context.Context, event event.CreateEvent, queue workqueue.TypedRateLimitingInterface[ctrl.Request]) {
+}
+
+// Update implements handler.EventHandler.
+func (h *namespaceEventHandler) Update(ctx context.Context, event event.UpdateEvent, queue workqueue.TypedRateLimitingInterface[ctrl.Request]) {
+	oldNamespace, ok := event.ObjectOld.(*corev1.Namespace)
+	if !ok {
+		return
+	}
+	newNamespace, ok := event.ObjectNew.(*corev1.Namespace)
+	if !ok {
+		return
+	}
+	if isNamespaceTerminating(oldNamespace) || !isNamespaceTerminating(newNamespace) {
+		return
+	}
+
+	apps := &v1beta2.SparkApplicationList{}
+	if err := h.client.List(ctx, apps, client.InNamespace(newNamespace.Name)); err != nil {
+		logger.Error(err, "Failed to list SparkApplications in terminating namespace", "namespace", newNamespace.Name)
+		return
+	}
+
+	logger.Info("Namespace is being terminated", "namespace", newNamespace.Name, "applications", len(apps.Items))
+	for _, app := range apps.Items {
+		queue.Add(ctrl.Request{NamespacedName: types.NamespacedName{Namespace: app.Namespace, Name: app.Name}})
+	}
+}
+
+// Delete implements handler.EventHandler.
+func (h *namespaceEventHandler) Delete(ctx context.Context, event event.DeleteEvent, queue workqueue.TypedRateLimitingInterface[ctrl.Request]) {
+}
+
+// Generic implements handler.EventHandler.
+func (h *namespaceEventHandler) Generic(ctx context.Context, event event.GenericEvent, queue workqueue.TypedRateLimitingInterface[ctrl.Request]) {
+}
@@ -0,0 +1,704 @@
+/*
+Copyright 2024 The Kubeflow authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package sparkapplication
+
+import (
+	"context"
+	"fmt"
+	"maps"
+	"math"
+	"net/url"
+	"os"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/google/uuid"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/yaml"
+
+	"github.com/kubeflow/spark-operator/api/v1beta2"
+	"github.com/kubeflow/spark-operator/pkg/common"
+	"github.com/kubeflow/spark-operator/pkg/naming"
+	"github.com/kubeflow/spark-operator/pkg/util"
+)
+
+// The layout of the driver pod created by the native submission engine follows the one of spark-submit, which the
+// entrypoint of the Spark image relies on.
+const (
+	nativeSparkConfDir                = "/opt/spark/conf"
+	nativeSparkConfVolumeName         = "spark-conf-volume-driver"
+	nativeSparkPropertiesFileName     = "spark.properties"
+	nativePodTemplateDir              = "/opt/spark/pod-template"
+	nativePodTemplateVolumeName       = "pod-template-volume"
+	nativePodTemplateFileName         = "pod-spec-template.yml"
+	nativeDefaultDriverPort           = 7078
+	nativeDefaultBlockManagerPort     = 7079
+	nativeDefaultUIPort               = 4040
+	nativeDefaultDriverMemory         = "1g"
+	nativeMaxExecutorPodNamePrefixLen = 47
+)
+
+// sparkSubmitOptionConfKeys maps the spark-submit options to the Spark configuration properties they set.
+var sparkSubmitOptionConfKeys = map[string]string{
+	"--master":           "spark.master",
+	"--deploy-mode":      "spark.submit.deployMode",
+	"--name":             common.SparkAppName,
+	"--jars":             "spark.jars",
+	"--packages":         "spark.jars.packages",
+	"--exclude-packages": "spark.jars.excludes",
+	"--repositories":     "spark.jars.repositories",
+	"--py-files":         "spark.submit.pyFiles",
+	"--files":            "spark.files",
+	"--archives":         "spark.archives",
+}
+
+// sparkMemoryPattern matches Spark memory strings, e.g. `512m` or `2g`.
+var sparkMemoryPattern = regexp.MustCompile(`^([0-9]+)([kmgtp]?)b?$`)
+
+// sparkSubmitArguments are the arguments of spark-submit resolved into the Spark configuration of the driver.
+type sparkSubmitArguments struct {
+	conf      map[string]string
+	mainClass string
+	proxyUser string
+}
+
+// nativeDriverResources are the resources the native submission engine creates for the driver.
+type nativeDriverResources struct {
+	pod       *corev1.Pod
+	service   *corev1.Service
+	configMap *corev1.ConfigMap
+}
+
+// runNativeSubmission submits the application without spark-submit by creating the driver pod, its headless Service
+// and the ConfigMap of its Spark configuration directly. The Service and ConfigMap are owned by the driver pod.
+func runNativeSubmission(ctx context.Context, c client.Client, app *v1beta2.SparkApplication, sparkSubmitArgs []string) error {
+	resources, err := buildNativeDriverResources(app, sparkSubmitArgs)
+	if err != nil {
+		return err
+	}
+
+	if err := c.Create(ctx, resources.pod); err != nil {
+		if errors.IsAlreadyExists(err) {
+			return fmt.Errorf("driver pod already exist")
+		}
+		return fmt.Errorf("failed to create driver pod: %v", err)
+	}
+
+	ownerReference := metav1.OwnerReference{
+		APIVersion: "v1",
+		Kind:       "Pod",
+		Name:       resources.pod.Name,
+		UID:        resources.pod.UID,
+		Controller: ptr.To(true),
+	}
+	for _, obj := range []client.Object{resources.configMap, resources.service} {
+		obj.SetOwnerReferences([]metav1.OwnerReference{ownerReference})
+		if err := c.Create(ctx, obj); err != nil {
+			if deleteErr := c.Delete(ctx, resources.pod); deleteErr != nil && !errors.IsNotFound(deleteErr) {
+				appLogger(app).Error(deleteErr, "Failed to delete driver pod after failed submission", "pod", resources.pod.Name)
+			}
+			return fmt.Errorf("failed to create %s of driver pod: %v", obj.GetName(), err)
+		}
+	}
+	return nil
+}
+
+// parseSparkSubmitArgs resolves the options of the spark-submit arguments into Spark configuration properties. The
+// trailing positional arguments, i.e. the main application file and the application arguments, are not parsed.
+func parseSparkSubmitArgs(args []string) (*sparkSubmitArguments, error) {
+	parsed := &sparkSubmitArguments{conf: make(map[string]string)}
+	// The options take precedence over the configuration properties passed with --conf, as in spark-submit.
+	options := make(map[string]string)
+	for i := 0; i < len(args); i += 2 {
+		option := args[i]
+		if i+1 >= len(args) {
+			return nil, fmt.Errorf("missing value of spark-submit option %s", option)
+		}
+		value := args[i+1]
+		switch option {
+		case "--conf":
+			key, confValue, found := strings.Cut(value, "=")
+			if !found {
+				return nil, fmt.Errorf("invalid Spark configuration property %q", value)
+			}
+			parsed.conf[key] = confValue
+		case "--class":
+			parsed.mainClass = value
+		case "--proxy-user":
+			parsed.proxyUser = value
+		default:
+			key, ok := sparkSubmitOptionConfKeys[option]
+			if !ok {
+				return nil, fmt.Errorf("spark-submit option %s is not supported by the native submission engine", option)
+			}
+			options[key] = value
+		}
+	}
+	maps.Copy(parsed.conf, options)
+	return parsed, nil
+}
+
+// buildNativeDriverResources builds the driver pod, Service and ConfigMap of the application from the arguments
+// built for spark-submit, so that both submission engines share the translation of the spec.
+func buildNativeDriverResources(app *v1beta2.SparkApplication, sparkSubmitArgs []string) (*nativeDriverResources, error) {
+	positional := len(app.Spec.Arguments)
+	mainApplicationFile, err := util.GetMainApplicationFile(app)
+	if err != nil {
+		return nil, err
+	}
+	if mainApplicationFile != "" {
+		positional++
+	}
+	if positional > len(sparkSubmitArgs) {
+		return nil, fmt.Errorf("invalid spark-submit arguments")
+	}
+	args, err := parseSparkSubmitArgs(sparkSubmitArgs[:len(sparkSubmitArgs)-positional])
+	if err != nil {
+		return nil, err
+	}
+	conf := args.conf
+
+	if mode := conf["spark.submit.deployMode"]; mode != "" && mode != string(v1beta2.DeployModeCluster) {
+		return nil, fmt.Errorf("deploy mode %q is not supported by the native submission engine", mode)
+	}
+	for _, key := range []string{"spark.jars", "spark.files", "spark.submit.pyFiles", "spark.archives"} {
+		if conf[key] == "" {
+			continue
+		}
+		for _, file := range strings.Split(conf[key], ",") {
+			if err := checkNativeDependency(file); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	mainClass := args.mainClass
+	primaryResource := mainApplicationFile
+	switch app.Spec.Type {
+	case v1beta2.SparkApplicationTypePython:
+		mainClass = "org.apache.spark.deploy.PythonRunner"
+	case v1beta2.SparkApplicationTypeR:
+		mainClass = "org.apache.spark.deploy.RRunner"
+	}
+	if primaryResource == "" {
+		primaryResource = "spark-internal"
+	} else if err := checkNativeDependency(primaryResource); err != nil {
+		return nil, err
+	}
+
+	podName := conf[common.SparkKubernetesDriverPodName]
+	appID := fmt.Sprintf("spark-%s", strings.ReplaceAll(uuid.New().String(), "-", ""))
+	serviceName := naming.Generate(podName, "svc", naming.MaxDNSLabelLength)
+	configMapName := naming.Generate(podName, "conf-map", naming.MaxDNSSubdomainLength)
+
+	driverPort, err := getNativePort(conf, nativeDefaultDriverPort, common.SparkDriverPort)
+	if err != nil {
+		return nil, err
+	}
+	blockManagerPort, err := getNativePort(conf, nativeDefaultBlockManagerPort, common.SparkDriverBlockManagerPort, common.SparkBlockManagerPort)
+	if err != nil {
+		return nil, err
+	}
+	uiPort, err := getNativePort(conf, nativeDefaultUIPort, common.SparkUIPortKey)
+	if err != nil {
+		return nil, err
+	}
+
+	// Complete the configuration as spark-submit does before handing it to the driver.
+	conf["spark.app.id"] = appID
+	conf["spark.kubernetes.submitInDriver"] = "true"
+	conf[common.SparkDriverHost] = fmt.Sprintf("%s.%s.svc", serviceName, app.Namespace)
+	conf[common.SparkDriverPort] = strconv.Itoa(int(driverPort))
+	conf[common.SparkDriverBlockManagerPort] = strconv.Itoa(int(blockManagerPort))
+	if conf[common.SparkKubernetesExecutorPodNamePrefix] == "" {
+		conf[common.SparkKubernetesExecutorPodNamePrefix] = naming.Generate(strings.ReplaceAll(app.Name, ".", "-"), appID[len(appID)-8:], nativeMaxExecutorPodNamePrefixLen)
+	}
+	for key := range conf {
+		if strings.HasPrefix(key, "spark.kubernetes.authenticate.submission.") {
+			delete(conf, key)
+		}
+	}
+
+	configMap := &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      configMapName,
+			Namespace: app.Namespace,
+			Labels:    map[string]string{common.LabelSparkApplicationSelector: appID},
+		},
+		Data: map[string]string{},
+	}
+	if file := conf[common.SparkKubernetesExecutorPodTemplateFile]; file != "" {
+		template, err := os.ReadFile(file)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read executor pod template file: %v", err)
+		}
+		configMap.Data[nativePodTemplateFileName] = string(template)
+		conf[common.SparkKubernetesExecutorPodTemplateFile] = fmt.Sprintf("%s/%s", nativePodTemplateDir, nativePodTemplateFileName)
+	}
+
+	pod, err := buildNativeDriverPod(conf)
+	if err != nil {
+		return nil, err
+	}
+	delete(conf, common.SparkKubernetesDriverPodTemplateFile)
+	configMap.Data[nativeSparkPropertiesFileName] = buildSparkProperties(conf)
+
+	pod.Name = podName
+	pod.Namespace = app.Namespace
+	pod.Labels = mergeStringMaps(pod.Labels, getConfWithPrefix(conf, "spark.kubernetes.driver.label."))
+	pod.Labels[common.LabelSparkApplicationSelector] = appID
+	pod.Labels[common.LabelSparkRole] = common.SparkRoleDriver
+	pod.Annotations = mergeStringMaps(pod.Annotations, getConfWithPrefix(conf, "spark.kubernetes.driver.annotation."))
+	pod.Spec.RestartPolicy = corev1.RestartPolicyNever
+	if serviceAccount := conf[common.SparkKubernetesAuthenticateDriverServiceAccountName]; serviceAccount != "" {
+		pod.Spec.ServiceAccountName = serviceAccount
+	}
+	if schedulerName := getFirstConf(conf, common.SparkKubernetesDriverSchedulerName, "spark.kubernetes.scheduler.name"); schedulerName != "" {
+		pod.Spec.SchedulerName = schedulerName
+	}
+	pod.Spec.NodeSelector = mergeStringMaps(pod.Spec.NodeSelector, getConfWithPrefix(conf, "spark.kubernetes.node.selector."))
+	pod.Spec.NodeSelector = mergeStringMaps(pod.Spec.NodeSelector, getConfWithPrefix(conf, "spark.kubernetes.driver.node.selector."))
+	if secrets := conf[common.SparkKubernetesContainerImagePullSecrets]; secrets != "" {
+		for _, secret := range strings.Split(secrets, ",") {
+			pod.Spec.ImagePullSecrets = append(pod.Spec.ImagePullSecrets, corev1.LocalObjectReference{Name: strings.TrimSpace(secret)})
+		}
+	}
+
+	container := &pod.Spec.Containers[0]
+	container.Image = getFirstConf(conf, common.SparkKubernetesDriverContainerImage, common.SparkKubernetesContainerImage)
+	if container.Image == "" {
+		return nil, fmt.Errorf("driver container image is not specified")
+	}
+	if pullPolicy := conf[common.SparkKubernetesContainerImagePullPolicy]; pullPolicy != "" {
+		container.ImagePullPolicy = corev1.PullPolicy(pullPolicy)
+	}
+	container.Args = []string{"driver", "--properties-file", fmt.Sprintf("%s/%s", nativeSparkConfDir, nativeSparkPropertiesFileName)}
+	if args.proxyUser != "" {
+		container.Args = append(container.Args, "--proxy-user", args.proxyUser)
+	}
+	if mainClass != "" {
+		container.Args = append(container.Args, "--class", mainClass)
+	}
+	container.Args = append(container.Args, primaryResource)
+	container.Args = append(container.Args, app.Spec.Arguments...)
+	container.Ports = append(container.Ports,
+		corev1.ContainerPort{Name: "driver-rpc-port", ContainerPort: driverPort, Protocol: corev1.ProtocolTCP},
+		corev1.ContainerPort{Name: "blockmanager", ContainerPort: blockManagerPort, Protocol: corev1.ProtocolTCP},
+		corev1.ContainerPort{Name: "spark-ui", ContainerPort: uiPort, Protocol: corev1.ProtocolTCP},
+	)
+	if err := setNativeDriverResources(app, conf, container); err != nil {
+		return nil, err
+	}
+
+	container.Env = append(container.Env,
+		corev1.EnvVar{Name: "SPARK_APPLICATION_ID", Value: appID},
+		corev1.EnvVar{
+			Name:      "SPARK_DRIVER_BIND_ADDRESS",
+			ValueFrom: &corev1.EnvVarSource{FieldRef: &corev1.ObjectFieldSelector{APIVersion: "v1", FieldPath: "status.podIP"}},
+		},
+		corev1.EnvVar{Name: common.EnvSparkConfDir, Value: nativeSparkConfDir},
+	)
+	driverEnv := getConfWithPrefix(conf, "spark.kubernetes.driverEnv.")
+	for _, name := range slices.Sorted(maps.Keys(driverEnv)) {
+		container.Env = append(container.Env, corev1.EnvVar{Name: name, Value: driverEnv[name]})
+	}
+	secretKeyRefs := getConfWithPrefix(conf, "spark.kubernetes.driver.secretKeyRef.")
+	for _, name := range slices.Sorted(maps.Keys(secretKeyRefs)) {
+		secretName, key, found := strings.Cut(secretKeyRefs[name], ":")
+		if !found {
+			return nil, fmt.Errorf("invalid secret key reference %q of environment variable %s", secretKeyRefs[name], name)
+		}
+		container.Env = append(container.Env, corev1.EnvVar{
+			Name: name,
+			ValueFrom: &corev1.EnvVarSource{SecretKeyRef: &corev1.SecretKeySelector{
+				LocalObjectReference: corev1.LocalObjectReference{Name: secretName},
+				Key:                  key,
+			}},
+		})
+	}
+
+	if err := addNativeDriverVolumes(pod, container, conf, configMapName); err != nil {
+		return nil, err
+	}
+
+	service := &corev1.Service{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        serviceName,
+			Namespace:   app.Namespace,
+			Labels:      mergeStringMaps(getConfWithPrefix(conf, "spark.kubernetes.driver.service.label."), map[string]string{common.LabelSparkApplicationSelector: appID}),
+			Annotations: getConfWithPrefix(conf, "spark.kubernetes.driver.service.annotation."),
+		},
+		Spec: corev1.ServiceSpec{
+			ClusterIP: corev1.ClusterIPNone,
+			Selector: map[string]string{
+				common.LabelSparkApplicationSelector: appID,
+				common.LabelSparkRole:                common.SparkRoleDriver,
+			},
+			Ports: []corev1.ServicePort{
+				{Name: "driver-rpc-port", Port: driverPort, Protocol: corev1.ProtocolTCP},
+				{Name: "blockmanager", Port: blockManagerPort, Protocol: corev1.ProtocolTCP},
+				{Name: "spark-ui", Port: uiPort, Protocol: corev1.ProtocolTCP},
+			},
+		},
+	}
+
+	return &nativeDriverResources{pod: pod, service: service, configMap: configMap}, nil
+}
+
+// buildNativeDriverPod returns the driver pod to complete, which is read from the driver pod template file if set.
+// The driver container is moved to the front of the containers.
+func buildNativeDriverPod(conf map[string]string) (*corev1.Pod, error) {
+	pod := &corev1.Pod{}
+	if file := conf[common.SparkKubernetesDriverPodTemplateFile]; file != "" {
+		data, err := os.ReadFile(file)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read driver pod template file: %v", err)
+		}
+		if err := yaml.Unmarshal(data, pod); err != nil {
+			return nil, fmt.Errorf("failed to parse driver pod template file: %v", err)
+		}
+	}
+
+	containerName := conf[common.SparkKubernetesDriverPodTemplateContainerName]
+	index := slices.IndexFunc(pod.Spec.Containers, func(c corev1.Container) bool { return c.Name == containerName })
+	if index < 0 {
+		pod.Spec.Containers = append([]corev1.Container{{Name: common.SparkDriverContainerName}}, pod.Spec.Containers...)
+	} else if index > 0 {
+		container := pod.Spec.Containers[index]
+		pod.Spec.Containers = slices.Delete(pod.Spec.Containers, index, index+1)
+		pod.Spec.Containers = slices.Insert(pod.Spec.Containers, 0, container)
+	}
+	return pod, nil
+}
+
+// setNativeDriverResources sets the CPU and memory resources of the driver container as spark-submit does, i.e. the
+// memory request and limit include the memory overhead.
+func setNativeDriverResources(app *v1beta2.SparkApplication, conf map[string]string, container *corev1.Container) error {
+	memoryMiB, err := sparkMemoryStringAsMiB(getFirstConf(conf, common.SparkDriverMemory), nativeDefaultDriverMemory)
+	if err != nil {
+		return fmt.Errorf("invalid driver memory: %v", err)
+	}
+
+	var overheadMiB int64
+	if overhead := conf[common.SparkDriverMemoryOverhead]; overhead != "" {
+		if overheadMiB, err = sparkMemoryStringAsMiB(overhead, ""); err != nil {
+			return fmt.Errorf("invalid driver memory overhead: %v", err)
+		}
+	} else {
+		factor := common.DefaultJVMMemoryOverheadFactor
+		if app.Spec.Type == v1beta2.SparkApplicationTypePython || app.Spec.Type == v1beta2.SparkApplicationTypeR {
+			factor = common.DefaultNonJVMMemoryOverheadFactor
+		}
+		if value := getFirstConf(conf, "spark.driver.memoryOverheadFactor", common.SparkKubernetesMemoryOverheadFactor); value != "" {
+			if factor, err = strconv.ParseFloat(value, 64); err != nil {
+				return fmt.Errorf("invalid memory overhead factor %q: %v", value, err)
+			}
+		}
+		overheadMiB = int64(math.Max(float64(memoryMiB)*factor, common.MinMemoryOverhead/(1<<20)))
+	}
+	memory := resource.MustParse(fmt.Sprintf("%dMi", memoryMiB+overheadMiB))
+
+	cores := getFirstConf(conf, common.SparkKubernetesDriverRequestCores, common.SparkDriverCores)
+	if cores == "" {
+		cores = "1"
+	}
+	cpu, err := resource.ParseQuantity(cores)
+	if err != nil {
+		return fmt.Errorf("invalid driver cores %q: %v", cores, err)
+	}
+
+	if container.Resources.Requests == nil {
+		container.Resources.Requests = corev1.ResourceList{}
+	}
+	if container.Resources.Limits == nil {
+		container.Resources.Limits = corev1.ResourceList{}
+	}
+	container.Resources.Requests[corev1.ResourceCPU] = cpu
+	container.Resources.Requests[corev1.ResourceMemory] = memory
+	container.Resources.Limits[corev1.ResourceMemory] = memory
+	if limit := conf[common.SparkKubernetesDriverLimitCores]; limit != "" {
+		cpuLimit, err := resource.ParseQuantity(limit)
+		if err != nil {
+			return fmt.Errorf("invalid driver core limit %q: %v", limit, err)
+		}
+		container.Resources.Limits[corev1.ResourceCPU] = cpuLimit
+	}
+	return nil
+}
+
+// addNativeDriverVolumes adds the volumes of the Spark configuration, the Secrets, the volumes configured with
+// Spark configuration properties and the local directories to the driver pod.
+func addNativeDriverVolumes(pod *corev1.Pod, container *corev1.Container, conf map[string]string, configMapName string) error {
+	pod.Spec.Volumes = append(pod.Spec.Volumes, corev1.Volume{
+		Name: nativeSparkConfVolumeName,
+		VolumeSource: corev1.VolumeSource{ConfigMap: &corev1.ConfigMapVolumeSource{
+			LocalObjectReference: corev1.LocalObjectReference{Name: configMapName},
+			Items:                []corev1.KeyToPath{{Key: nativeSparkPropertiesFileName, Path: nativeSparkPropertiesFileName}},
+		}},
+	})
+	container.VolumeMounts = append(container.VolumeMounts, corev1.VolumeMount{Name: nativeSparkConfVolumeName, MountPath: nativeSparkConfDir})
+
+	if strings.HasPrefix(conf[common.SparkKubernetesExecutorPodTemplateFile], nativePodTemplateDir) {
+		pod.Spec.Volumes = append(pod.Spec.Volumes, corev1.Volume{
+			Name: nativePodTemplateVolumeName,
+			VolumeSource: corev1.VolumeSource{ConfigMap: &corev1.ConfigMapVolumeSource{
+				LocalObjectReference: corev1.LocalObjectReference{Name: configMapName},
+				Items:                []corev1.KeyToPath{{Key: nativePodTemplateFileName, Path: nativePodTemplateFileName}},
+			}},
+		})
+		container.VolumeMounts = append(container.VolumeMounts, corev1.VolumeMount{Name: nativePodTemplateVolumeName, MountPath: nativePodTemplateDir})
+	}
+
+	secrets := getConfWithPrefix(conf, "spark.kubernetes.driver.secrets.")
+	for _, name := range slices.Sorted(maps.Keys(secrets)) {
+		volumeName := fmt.Sprintf("%s-volume", name)
+		pod.Spec.Volumes = append(pod.Spec.Volumes, corev1.Volume{
+			Name:         volumeName,
+			VolumeSource: corev1.VolumeSource{Secret: &corev1.SecretVolumeSource{SecretName: name}},
+		})
+		container.VolumeMounts = append(container.VolumeMounts, corev1.VolumeMount{Name: volumeName, MountPath: secrets[name]})
+	}
+
+	volumes, err := getNativeDriverVolumes(conf)
+	if err != nil {
+		return err
+	}
+	var localDirs []string
+	for _, volume := range volumes {
+		pod.Spec.Volumes = append(pod.Spec.Volumes, volume.volume)
+		container.VolumeMounts = append(container.VolumeMounts, volume.mount)
+		if strings.HasPrefix(volume.volume.Name, common.SparkLocalDirVolumePrefix) {
+			localDirs = append(localDirs, volume.mount.MountPath)
+		}
+	}
+	if len(localDirs) == 0 {
+		localDir := fmt.Sprintf("%sspark-%s", common.SparkLocalDirMountPathPrefix, uuid.New().String())
+		pod.Spec.Volumes = append(pod.Spec.Volumes, corev1.Volume{
+			Name:         fmt.Sprintf("%s1", common.SparkLocalDirVolumePrefix),
+			VolumeSource: corev1.VolumeSource{EmptyDir: &corev1.EmptyDirVolumeSource{}},
+		})
+		container.VolumeMounts = append(container.VolumeMounts, corev1.VolumeMount{Name: fmt.Sprintf("%s1", common.SparkLocalDirVolumePrefix), MountPath: localDir})
+		localDirs = append(localDirs, localDir)
+	}
+	container.Env = append(container.Env, corev1.EnvVar{Name: "SPARK_LOCAL_DIRS", Value: strings.Join(localDirs, ",")})
+	return nil
+}
+
+// nativeDriverVolume is a volume of the driver configured with Spark configuration properties.
+type nativeDriverVolume struct {
+	volume corev1.Volume
+	mount  corev1.VolumeMount
+}
+
+// getNativeDriverVolumes returns the volumes configured with `spark.kubernetes.driver.volumes.[type].[name].*`.
+func getNativeDriverVolumes(conf map[string]string) ([]nativeDriverVolume, error) {
+	type volumeKey struct{ volumeType, name string }
+	properties := make(map[volumeKey]map[string]string)
+	for key, value := range getConfWithPrefix(conf, common.SparkKubernetesDriverVolumesPrefix) {
+		parts := strings.SplitN(key, ".", 3)
+		if len(parts) != 3 {
+			return nil, fmt.Errorf("invalid volume configuration %s%s", common.SparkKubernetesDriverVolumesPrefix, key)
+		}
+		k := volumeKey{volumeType: parts[0], name: parts[1]}
+		if properties[k] == nil {
+			properties[k] = make(map[string]string)
+		}
+		properties[k][parts[2]] = value
+	}
+
+	keys := slices.SortedFunc(maps.Keys(properties), func(a, b volumeKey) int { return strings.Compare(a.name, b.name) })
+	volumes := make([]nativeDriverVolume, 0, len(keys))
+	for _, k := range keys {
+		props := properties[k]
+		readOnly := props["mount.readOnly"] == "true"
+		volume := corev1.Volume{Name: k.name}
+		switch k.volumeType {
+		case common.VolumeTypeEmptyDir:
+			volume.EmptyDir = &corev1.EmptyDirVolumeSource{Medium: corev1.StorageMedium(props["options.medium"])}
+			if sizeLimit := props["options.sizeLimit"]; sizeLimit != "" {
+				quantity, err := resource.ParseQuantity(sizeLimit)
+				if err != nil {
+					return nil, fmt.Errorf("invalid size limit of volume %s: %v", k.name, err)
+				}
+				volume.EmptyDir.SizeLimit = &quantity
+			}
+		case common.VolumeTypeHostPath:
+			volume.HostPath = &corev1.HostPathVolumeSource{Path: props["options.path"]}
+			if hostPathType := props["options.type"]; hostPathType != "" {
+				volume.HostPath.Type = ptr.To(corev1.HostPathType(hostPathType))
+			}
+		case common.VolumeTypeNFS:
+			volume.NFS = &corev1.NFSVolumeSource{Server: props["options.server"], Path: props["options.path"], ReadOnly: props["options.readOnly"] == "true"}
+		case common.VolumeTypePersistentVolumeClaim:
+			claimName := props["options.claimName"]
+			if claimName == common.SparkVolumeOnDemandClaimName {
+				return nil, fmt.Errorf("on-demand persistent volume claims of the driver are not supported by the native submission engine")
+			}
+			volume.PersistentVolumeClaim = &corev1.PersistentVolumeClaimVolumeSource{ClaimName: claimName, ReadOnly: props["options.readOnly"] == "true"}
+		default:
+			return nil, fmt.Errorf("unsupported type %s of volume %s", k.volumeType, k.name)
+		}
+		volumes = append(volumes, nativeDriverVolume{
+			volume: volume,
+			mount: corev1.VolumeMount{
+				Name:      k.name,
+				MountPath: props["mount.path"],
+				SubPath:   props["mount.subPath"],
+				ReadOnly:  readOnly,
+			},
+		})
+	}
+	return volumes, nil
+}
+
+// checkNativeDependency returns an error if the dependency is a local file, which spark-submit uploads but the native
+// submission engine cannot.
+func checkNativeDependency(file string) error {
+	file = strings.TrimSpace(file)
+	if file == "" {
+		return nil
+	}
+	u, err := url.Parse(file)
+	if err != nil {
+		return fmt.Errorf("invalid dependency %q: %v", file, err)
+	}
+	if u.Scheme == "" || u.Scheme == "file" {
+		return fmt.Errorf("local dependency %q must be uploaded by spark-submit, use `local://` for files in the image", file)
+	}
+	return nil
+}
+
+// getNativePort returns the port set by the first of the given configuration properties, or the default port.
+func getNativePort(conf map[string]string, defaultPort int32, keys ...string) (int32, error) {
+	value := getFirstConf(conf, keys...)
+	if value == "" {
+		return defaultPort, nil
+	}
+	port, err := strconv.ParseInt(value, 10, 32)
+	if err != nil || port <= 0 {
+		return 0, fmt.Errorf("invalid port %q of %s", value, keys[0])
+	}
+	return int32(port), nil
+}
+
+// getFirstConf returns the value of the first of the given configuration properties being set.
+func getFirstConf(conf map[string]string, keys ...string) string {
+	for _, key := range keys {
+		if value := conf[key]; value != "" {
+			return value
+		}
+	}
+	return ""
+}
+
+// getConfWithPrefix returns the configuration properties with the given prefix, keyed by the rest of their keys.
+func getConfWithPrefix(conf map[string]string, prefix string) map[string]string {
+	result := make(map[string]string)
+	for key, value := range conf {
+		if name, found := strings.CutPrefix(key, prefix); found && name != "" {
+			result[name] = value
+		}
+	}
+	return result
+}
+
+// sparkMemoryStringAsMiB parses a Spark memory string, whose unit defaults to MiB, e.g. `512m` or `2g`.
+func sparkMemoryStringAsMiB(s string, defaultValue string) (int64, error) {
+	if s == "" {
+		s = defaultValue
+	}
+	matches := sparkMemoryPattern.FindStringSubmatch(strings.ToLower(strings.TrimSpace(s)))
+	if matches == nil {
+		return 0, fmt.Errorf("could not parse %q as a memory value, e.g. 512m or 2g", s)
+	}
+	value, err := strconv.ParseInt(matches[1], 10, 64)
+	if err != nil {
+		return 0, err
+	}
+	switch matches[2] {
+	case "k":
+		return value >> 10, nil
+	case "", "m":
+		return value, nil
+	case "g":
+		return value << 10, nil
+	case "t":
+		return value << 20, nil
+	default:
+		return value << 30, nil
+	}
+}
+
+// buildSparkProperties renders the Spark configuration as a Java properties file, sorted by key.
+func buildSparkProperties(conf map[string]string) string {
+	var b strings.Builder
+	for _, key := range slices.Sorted(maps.Keys(conf)) {
+		b.WriteString(escapeJavaProperty(key, true))
+		b.WriteString("=")
+		b.WriteString(escapeJavaProperty(conf[key], false))
+		b.WriteString("\n")
+	}
+	return b.String()
+}
+
+// escapeJavaProperty escapes the key or value of a Java properties file entry.
+func escapeJavaProperty(s string, isKey bool) string {
+	var b strings.Builder
+	for i, r := range s {
+		switch r {
+		case '\\':
+			b.WriteString(`\\`)
+		case '\n':
+			b.WriteString(`\n`)
+		case '\r':
+			b.WriteString(`\r`)
+		case '\t':
+			b.WriteString(`\t`)
+		case '\f':
+			b.WriteString(`\f`)
+		case '=', ':', '#', '!':
+			if isKey || i == 0 {
+				b.WriteRune('\\')
+			}
+			b.WriteRune(r)
+		case ' ':
+			if isKey || i == 0 {
+				b.WriteRune('\\')
+			}
+			b.WriteRune(r)
+		default:
+			b.WriteRune(r)
+		}
+	}
+	return b.String()
+}
+
+// mergeStringMaps copies the entries of src into dst, which is created if nil, and returns dst.
+func mergeStringMaps(dst map[string]string, src map[string]string) map[string]string {
+	if dst == nil {
+		dst = make(map[string]string, len(src))
+	}
+	maps.Copy(dst, src)
+	return dst
+}
@@ -0,0 +1,524 @@
+/*
+Copyright 2024 The Kubeflow authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package sparkapplication
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/ptr"
+
+	"github.com/kubeflow/spark-operator/api/v1beta2"
+	"github.com/kubeflow/spark-operator/pkg/common"
+)
+
+func newTestNativeSparkApplication() *v1beta2.SparkApplication {
+	app := &v1beta2.SparkApplication{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "test-app",
+			Namespace: "default",
+		},
+		Spec: v1beta2.SparkApplicationSpec{
+			Type:         v1beta2.SparkApplicationTypeScala,
+			SparkVersion: "3.5.3",
+		},
+	}
+	app.Spec.Image = ptr.To("spark:3.5.3")
+	app.Spec.MainClass = ptr.To("org.apache.spark.examples.SparkPi")
+	app.Spec.MainApplicationFile = ptr.To("local:///opt/spark/examples/jars/spark-examples.jar")
+	app.Spec.Arguments = []string{"1000"}
+	return app
+}
+
+// buildTestNativeDriverResources builds the driver resources from the arguments built for spark-submit, as the
+// native submission engine does.
+func buildTestNativeDriverResources(t *testing.T, app *v1beta2.SparkApplication) (*nativeDriverResources, error) {
+	t.Setenv(common.EnvKubernetesServiceHost, "10.0.0.1")
+	t.Setenv(common.EnvKubernetesServicePort, "443")
+	args, err := buildSparkSubmitArgs(app)
+	require.NoError(t, err)
+	return buildNativeDriverResources(app, args)
+}
+
+// parseTestSparkProperties parses the Spark properties file of the driver ConfigMap.
+func parseTestSparkProperties(t *testing.T, resources *nativeDriverResources) map[string]string {
+	conf := make(map[string]string)
+	for _, line := range strings.Split(strings.TrimSpace(resources.configMap.Data[nativeSparkPropertiesFileName]), "\n") {
+		key, value, found := strings.Cut(line, "=")
+		require.True(t, found, line)
+		conf[key] = value
+	}
+	return conf
+}
+
+// TestBuildNativeDriverResourcesMemory checks that the driver container requests the resources spark-submit
+// requests for the same spec, i.e. the memory includes max(factor * memory, 384Mi) of overhead.
+func TestBuildNativeDriverResourcesMemory(t *testing.T) {
+	testCases := []struct {
+		name          string
+		mutate        func(app *v1beta2.SparkApplication)
+		memory        string
+		cpuRequest    string
+		cpuLimit      string
+		expectedError string
+	}{
+		{
+			name:       "defaults",
+			mutate:     func(*v1beta2.SparkApplication) {},
+			memory:     "1408Mi",
+			cpuRequest: "1",
+		},
+		{
+			name: "JVM overhead factor",
+			mutate: func(app *v1beta2.SparkApplication) {
+				app.Spec.Driver.Memory = ptr.To("4g")
+			},
+			memory:     "4505Mi",
+			cpuRequest: "1",
+		},
+		{
+			name: "non-JVM overhead factor",
+			mutate: func(app *v1beta2.SparkApplication) {
+				app.Spec.Type = v1beta2.SparkApplicationTypePython
+				app.Spec.MainClass = nil
+				app.Spec.MainApplicationFile = ptr.To("local:///opt/spark/examples/src/main/python/pi.py")
+				app.Spec.Driver.Memory = ptr.To("2g")
+			},
+			memory:     "2867Mi",
+			cpuRequest: "1",
+		},
+		{
+			name: "overhead factor of the spec",
+			mutate: func(app *v1beta2.SparkApplication) {
+				app.Spec.MemoryOverheadFactor = ptr.To("0.2")
+				app.Spec.Driver.Memory = ptr.To("4g")
+			},
+			memory:     "4915Mi",
+			cpuRequest: "1",
+		},
+		{
+			name: "explicit overhead",
+			mutate: func(app *v1beta2.SparkApplication) {
+				app.Spec.Driver.Memory = ptr.To("2g")
+				app.Spec.Driver.MemoryOverhead = ptr.To("512m")
+			},
+			memory:     "2560Mi",
+			cpuRequest: "1",
+		},
+		{
+			name: "cores",
+			mutate: func(app *v1beta2.SparkApplication) {
+				app.Spec.Driver.Cores = ptr.To[int32](2)
+				app.Spec.Driver.CoreLimit = ptr.To("2500m")
+			},
+			memory:     "1408Mi",
+			cpuRequest: "2",
+			cpuLimit:   "2500m",
+		},
+		{
+			name: "core request takes precedence over cores",
+			mutate: func(app *v1beta2.SparkApplication) {
+				app.Spec.Driver.Cores = ptr.To[int32](2)
+				app.Spec.Driver.CoreRequest = ptr.To("500m")
+			},
+			memory:     "1408Mi",
+			cpuRequest: "500m",
+		},
+		{
+			name: "invalid memory",
+			mutate: func(app *v1beta2.SparkApplication) {
+				app.Spec.Driver.Memory = ptr.To("lots")
+			},
+			expectedError: "invalid driver memory",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			app := newTestNativeSparkApplication()
+			tc.mutate(app)
+
+			resources, err := buildTestNativeDriverResources(t, app)
+			if tc.expectedError != "" {
+				assert.ErrorContains(t, err, tc.expectedError)
+				return
+			}
+			require.NoError(t, err)
+			container := resources.pod.Spec.Containers[0]
+			assert.Equal(t, tc.memory, ptr.To(container.Resources.Requests[corev1.ResourceMemory]).String())
+			assert.Equal(t, tc.memory, ptr.To(container.Resources.Limits[corev1.ResourceMemory]).String())
+			assert.Equal(t, tc.cpuRequest, ptr.To(container.Resources.Requests[corev1.ResourceCPU]).String())
+			if tc.cpuLimit == "" {
+				assert.NotContains(t, container.Resources.Limits, corev1.ResourceCPU)
+			} else {
+				assert.Equal(t, tc.cpuLimit, ptr.To(container.Resources.Limits[corev1.ResourceCPU]).String())
+			}
+		})
+	}
+}
+
+// TestBuildNativeDriverResourcesConf checks that the Spark configuration spark-submit would pass to the driver is
+// propagated to its properties file, completed with the properties spark-submit sets.
+func TestBuildNativeDriverResourcesConf(t *testing.T) {
+	app := newTestNativeSparkApplication()
+	app.Spec.SparkConf = map[string]string{
+		"spark.eventLog.enabled":                              "true",
+		"spark.kubernetes.authenticate.submission.oauthToken": "token",
+	}
+	app.Spec.HadoopConf = map[string]string{"fs.defaultFS": "hdfs://namenode:8020"}
+	app.Spec.Executor.Memory = ptr.To("2g")
+	app.Spec.Driver.Labels = map[string]string{"team": "data"}
+	app.Spec.Driver.EnvVars = map[string]string{"FOO": "bar"}
+	app.Spec.Driver.ServiceAccount = ptr.To("spark")
+
+	resources, err := buildTestNativeDriverResources(t, app)
+	require.NoError(t, err)
+	conf := parseTestSparkProperties(t, resources)
+
+	assert.Equal(t, "k8s://https://10.0.0.1:443", conf["spark.master"])
+	assert.Equal(t, "test-app", conf[common.SparkAppName])
+	assert.Equal(t, "default", conf[common.SparkKubernetesNamespace])
+	assert.Equal(t, "true", conf["spark.eventLog.enabled"])
+	assert.Equal(t, "hdfs://namenode:8020", conf["spark.hadoop.fs.defaultFS"])
+	assert.Equal(t, "2g", conf[common.SparkExecutorMemory])
+	assert.Equal(t, "true", conf["spark.kubernetes.submitInDriver"])
+	assert.Equal(t, resources.service.Name+".default.svc", conf[common.SparkDriverHost])
+	assert.Equal(t, resources.pod.Labels[common.LabelSparkApplicationSelector], conf["spark.app.id"])
+	assert.NotEmpty(t, conf[common.SparkKubernetesExecutorPodNamePrefix])
+	// Submission credentials are only used by spark-submit and never reach the driver.
+	assert.NotContains(t, conf, "spark.kubernetes.authenticate.submission.oauthToken")
+
+	pod := resources.pod
+	assert.Equal(t, "test-app-driver", pod.Name)
+	assert.Equal(t, "data", pod.Labels["team"])
+	assert.Equal(t, "test-app", pod.Labels[common.LabelSparkAppName])
+	assert.Equal(t, common.SparkRoleDriver, pod.Labels[common.LabelSparkRole])
+	assert.Equal(t, "spark", pod.Spec.ServiceAccountName)
+	assert.Equal(t, corev1.RestartPolicyNever, pod.Spec.RestartPolicy)
+
+	container := pod.Spec.Containers[0]
+	assert.Equal(t, common.SparkDriverContainerName, container.Name)
+	assert.Equal(t, "spark:3.5.3", container.Image)
+	assert.Equal(t, []string{
+		"driver", "--properties-file", "/opt/spark/conf/spark.properties",
+		"--class", "org.apache.spark.examples.SparkPi",
+		"local:///opt/spark/examples/jars/spark-examples.jar", "1000",
+	}, container.Args)
+	assert.Contains(t, container.Env, corev1.EnvVar{Name: "FOO", Value: "bar"})
+	assert.Contains(t, container.Env, corev1.EnvVar{Name: common.EnvSparkConfDir, Value: nativeSparkConfDir})
+}
+
+// TestBuildNativeDriverResourcesVolumes checks that the driver gets the volumes spark-submit would mount for the
+// same spec.
+func TestBuildNativeDriverResourcesVolumes(t *testing.T) {
+	testCases := []struct {
+		name          string
+		mutate        func(app *v1beta2.SparkApplication)
+		volumes       []corev1.Volume
+		mounts        []corev1.VolumeMount
+		localDirs     string
+		expectedError string
+	}{
+		{
+			name: "local dir and volumes of the Spark configuration",
+			mutate: func(app *v1beta2.SparkApplication) {
+				app.Spec.Volumes = []corev1.Volume{
+					{Name: "spark-local-dir-scratch", VolumeSource: corev1.VolumeSource{EmptyDir: &corev1.EmptyDirVolumeSource{}}},
+				}
+				app.Spec.Driver.VolumeMounts = []corev1.VolumeMount{{Name: "spark-local-dir-scratch", MountPath: "/scratch"}}
+				app.Spec.Driver.Secrets = []v1beta2.SecretInfo{{Name: "creds", Path: "/etc/creds", Type: v1beta2.SecretTypeGeneric}}
+				app.Spec.SparkConf = map[string]string{
+					"spark.kubernetes.driver.volumes.persistentVolumeClaim.data.mount.path":        "/data",
+					"spark.kubernetes.driver.volumes.persistentVolumeClaim.data.mount.readOnly":    "true",
+					"spark.kubernetes.driver.volumes.persistentVolumeClaim.data.options.claimName": "data-pvc",
+				}
+			},
+			volumes: []corev1.Volume{
+				{Name: "creds-volume", VolumeSource: corev1.VolumeSource{Secret: &corev1.SecretVolumeSource{SecretName: "creds"}}},
+				{Name: "data", VolumeSource: corev1.VolumeSource{PersistentVolumeClaim: &corev1.PersistentVolumeClaimVolumeSource{ClaimName: "data-pvc"}}},
+				{Name: "spark-local-dir-scratch", VolumeSource: corev1.VolumeSource{EmptyDir: &corev1.EmptyDirVolumeSource{}}},
+			},
+			mounts: []corev1.VolumeMount{
+				{Name: "creds-volume", MountPath: "/etc/creds"},
+				{Name: "data", MountPath: "/data", ReadOnly: true},
+				{Name: "spark-local-dir-scratch", MountPath: "/scratch"},
+			},
+			localDirs: "/scratch",
+		},
+		{
+			name: "host path",
+			mutate: func(app *v1beta2.SparkApplication) {
+				app.Spec.SparkConf = map[string]string{
+					"spark.kubernetes.driver.volumes.hostPath.logs.mount.path":    "/logs",
+					"spark.kubernetes.driver.volumes.hostPath.logs.mount.subPath": "driver",
+					"spark.kubernetes.driver.volumes.hostPath.logs.options.path":  "/var/log/spark",
+					"spark.kubernetes.driver.volumes.hostPath.logs.options.type":  "Directory",
+				}
+			},
+			volumes: []corev1.Volume{
+				{Name: "logs", VolumeSource: corev1.VolumeSource{HostPath: &corev1.HostPathVolumeSource{
+					Path: "/var/log/spark",
+					Type: ptr.To(corev1.HostPathDirectory),
+				}}},
+			},
+			mounts: []corev1.VolumeMount{{Name: "logs", MountPath: "/logs", SubPath: "driver"}},
+		},
+		{
+			name: "on-demand persistent volume claim",
+			mutate: func(app *v1beta2.SparkApplication) {
+				app.Spec.SparkConf = map[string]string{
+					"spark.kubernetes.driver.volumes.persistentVolumeClaim.data.mount.path":        "/data",
+					"spark.kubernetes.driver.volumes.persistentVolumeClaim.data.options.claimName": common.SparkVolumeOnDemandClaimName,
+				}
+			},
+			expectedError: "on-demand persistent volume claims of the driver are not supported",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			app := newTestNativeSparkApplication()
+			tc.mutate(app)
+
+			resources, err := buildTestNativeDriverResources(t, app)
+			if tc.expectedError != "" {
+				assert.ErrorContains(t, err, tc.expectedError)
+				return
+			}
+			require.NoError(t, err)
+			pod := resources.pod
+			container := pod.Spec.Containers[0]
+
+			// The Spark configuration is always mounted from the ConfigMap of the driver.
+			require.NotEmpty(t, pod.Spec.Volumes)
+			assert.Equal(t, nativeSparkConfVolumeName, pod.Spec.Volumes[0].Name)
+			assert.Equal(t, resources.configMap.Name, pod.Spec.Volumes[0].ConfigMap.Name)
+			assert.Equal(t, corev1.VolumeMount{Name: nativeSparkConfVolumeName, MountPath: nativeSparkConfDir}, container.VolumeMounts[0])
+
+			for _, volume := range tc.volumes {
+				assert.Contains(t, pod.Spec.Volumes, volume)
+			}
+			for _, mount := range tc.mounts {
+				assert.Contains(t, container.VolumeMounts, mount)
+			}
+
+			// Without a local dir volume, spark-submit adds an emptyDir one.
+			var localDirs string
+			for _, env := range container.Env {
+				if env.Name == "SPARK_LOCAL_DIRS" {
+					localDirs = env.Value
+				}
+			}
+			if tc.localDirs != "" {
+				assert.Equal(t, tc.localDirs, localDirs)
+			} else {
+				assert.True(t, strings.HasPrefix(localDirs, common.SparkLocalDirMountPathPrefix+"spark-"), localDirs)
+				assert.Contains(t, pod.Spec.Volumes, corev1.Volume{
+					Name:         common.SparkLocalDirVolumePrefix + "1",
+					VolumeSource: corev1.VolumeSource{EmptyDir: &corev1.EmptyDirVolumeSource{}},
+				})
+			}
+		})
+	}
+}
+
+// TestBuildNativeDriverResourcesPorts checks that the driver container and Service expose the ports spark-submit
+// would expose for the same spec.
+func TestBuildNativeDriverResourcesPorts(t *testing.T) {
+	testCases := []struct {
+		name             string
+		networkPorts     *v1beta2.NetworkPorts
+		sparkConf        map[string]string
+		driverPort       int32
+		blockManagerPort int32
+		uiPort           int32
+		expectedError    string
+	}{
+		{
+			name:             "defaults",
+			driverPort:       7078,
+			blockManagerPort: 7079,
+			uiPort:           4040,
+		},
+		{
+			name: "network ports",
+			networkPorts: &v1beta2.NetworkPorts{
+				DriverPort:             ptr.To[int32](7100),
+				DriverBlockManagerPort: ptr.To[int32](7101),
+				UIPort:                 ptr.To[int32](4100),
+			},
+			driverPort:       7100,
+			blockManagerPort: 7101,
+			uiPort:           4100,
+		},
+		{
+			name:             "block manager port of the driver defaults to the one of all block managers",
+			networkPorts:     &v1beta2.NetworkPorts{BlockManagerPort: ptr.To[int32](7200)},
+			driverPort:       7078,
+			blockManagerPort: 7200,
+			uiPort:           4040,
+		},
+		{
+			name:             "Spark configuration",
+			sparkConf:        map[string]string{common.SparkDriverPort: "7300", common.SparkUIPortKey: "4300"},
+			driverPort:       7300,
+			blockManagerPort: 7079,
+			uiPort:           4300,
+		},
+		{
+			name:          "invalid port",
+			sparkConf:     map[string]string{common.SparkUIPortKey: "ui"},
+			expectedError: `invalid port "ui"`,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			app := newTestNativeSparkApplication()
+			app.Spec.NetworkPorts = tc.networkPorts
+			app.Spec.SparkConf = tc.sparkConf
+
+			resources, err := buildTestNativeDriverResources(t, app)
+			if tc.expectedError != "" {
+				assert.ErrorContains(t, err, tc.expectedError)
+				return
+			}
+			require.NoError(t, err)
+
+			assert.Equal(t, []corev1.ContainerPort{
+				{Name: "driver-rpc-port", ContainerPort: tc.driverPort, Protocol: corev1.ProtocolTCP},
+				{Name: "blockmanager", ContainerPort: tc.blockManagerPort, Protocol: corev1.ProtocolTCP},
+				{Name: "spark-ui", ContainerPort: tc.uiPort, Protocol: corev1.ProtocolTCP},
+			}, resources.pod.Spec.Containers[0].Ports)
+
+			service := resources.service
+			assert.Equal(t, corev1.ClusterIPNone, service.Spec.ClusterIP)
+			assert.Equal(t, []corev1.ServicePort{
+				{Name: "driver-rpc-port", Port: tc.driverPort, Protocol: corev1.ProtocolTCP},
+				{Name: "blockmanager", Port: tc.blockManagerPort, Protocol: corev1.ProtocolTCP},
+				{Name: "spark-ui", Port: tc.uiPort, Protocol: corev1.ProtocolTCP},
+			}, service.Spec.Ports)
+			assert.Equal(t, resources.pod.Labels[common.LabelSparkApplicationSelector], service.Spec.Selector[common.LabelSparkApplicationSelector])
+			assert.Equal(t, common.SparkRoleDriver, service.Spec.Selector[common.LabelSparkRole])
+
+			conf := parseTestSparkProperties(t, resources)
+			assert.Equal(t, fmt.Sprint(tc.driverPort), conf[common.SparkDriverPort])
+			assert.Equal(t, fmt.Sprint(tc.blockManagerPort), conf[common.SparkDriverBlockManagerPort])
+		})
+	}
+}
+
+func TestBuildNativeDriverResourcesRejectsLocalDependencies(t *testing.T) {
+	testCases := []struct {
+		name   string
+		mutate func(app *v1beta2.SparkApplication)
+	}{
+		{
+			name: "main application file",
+			mutate: func(app *v1beta2.SparkApplication) {
+				app.Spec.MainApplicationFile = ptr.To("/tmp/spark-examples.jar")
+			},
+		},
+		{
+			name: "jars",
+			mutate: func(app *v1beta2.SparkApplication) {
+				app.Spec.Deps.Jars = []string{"local:///opt/spark/jars/dep.jar", "file:///tmp/dep.jar"}
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			app := newTestNativeSparkApplication()
+			tc.mutate(app)
+
+			_, err := buildTestNativeDriverResources(t, app)
+			assert.ErrorContains(t, err, "must be uploaded by spark-submit")
+		})
+	}
+}
+
+func TestParseSparkSubmitArgs(t *testing.T) {
+	args, err := parseSparkSubmitArgs([]string{
+		"--master", "k8s://https://10.0.0.1:443",
+		"--class", "org.apache.spark.examples.SparkPi",
+		"--name", "test-app",
+		"--conf", "spark.app.name=ignored",
+		"--conf", "spark.executor.extraJavaOptions=-Dkey=value",
+		"--proxy-user", "alice",
+	})
+	require.NoError(t, err)
+	assert.Equal(t, "org.apache.spark.examples.SparkPi", args.mainClass)
+	assert.Equal(t, "alice", args.proxyUser)
+	// The options take precedence over the same properties passed with --conf.
+	assert.Equal(t, map[string]string{
+		"spark.master":                    "k8s://https://10.0.0.1:443",
+		common.SparkAppName:               "test-app",
+		"spark.executor.extraJavaOptions": "-Dkey=value",
+	}, args.conf)
+
+	_, err = parseSparkSubmitArgs([]string{"--verbose", "true"})
+	assert.ErrorContains(t, err, "not supported by the native submission engine")
+	_, err = parseSparkSubmitArgs([]string{"--conf", "spark.executor.memory"})
+	assert.ErrorContains(t, err, "invalid Spark configuration property")
+	_, err = parseSparkSubmitArgs([]string{"--class"})
+	assert.ErrorContains(t, err, "missing value of spark-submit option --class")
+}
+
+func TestSparkMemoryStringAsMiB(t *testing.T) {
+	testCases := []struct {
+		value    string
+		expected int64
+		wantErr  bool
+	}{
+		{value: "", expected: 1024},
+		{value: "512", expected: 512},
+		{value: "512m", expected: 512},
+		{value: "512MB", expected: 512},
+		{value: "2g", expected: 2048},
+		{value: "1t", expected: 1 << 20},
+		{value: "2048k", expected: 2},
+		{value: "1.5g", wantErr: true},
+		{value: "-1g", wantErr: true},
+	}
+	for _, tc := range testCases {
+		value, err := sparkMemoryStringAsMiB(tc.value, nativeDefaultDriverMemory)
+		if tc.wantErr {
+			assert.Error(t, err, tc.value)
+			continue
+		}
+		require.NoError(t, err, tc.value)
+		assert.Equal(t, tc.expected, value, tc.value)
+	}
+}
+
+func TestBuildSparkProperties(t *testing.T) {
+	assert.Equal(t,
+		"spark.a=1\nspark.b=\\ leading space\nspark.c=x=y\\\\z\\n\nweird\\ key\\=name=\\#value\n",
+		buildSparkProperties(map[string]string{
+			"spark.c":        "x=y\\z\n",
+			"spark.a":        "1",
+			"spark.b":        " leading space",
+			"weird key=name": "#value",
+		}),
+	)
+}
@@ -0,0 +1,109 @@
+/*
+Copyright 2024 The Kubeflow authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package sparkapplication
+
+import (
+	"context"
+	"fmt"
+	"io"
+
+	"gocloud.dev/blob"
+	_ "gocloud.dev/blob/fileblob"
+	_ "gocloud.dev/blob/gcsblob"
+	_ "gocloud.dev/blob/s3blob"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/kubeflow/spark-operator/api/v1beta2"
+	"github.com/kubeflow/spark-operator/pkg/common"
+	"github.com/kubeflow/spark-operator/pkg/util"
+)
+
+// shouldVerifyOutputs tells whether the outputs of the given SparkApplication have yet to be verified since the
+// current run completed.
+func shouldVerifyOutputs(app *v1beta2.SparkApplication) bool {
+	return len(app.Spec.Outputs) > 0 && app.Status.Outputs == nil &&
+		app.Status.AppState.State == v1beta2.ApplicationStateCompleted
+}
+
+// verifyOutputs verifies that the outputs of the given SparkApplication exist and records them in the status.
+// Missing outputs are recorded and reported with a Warning event, without failing the application.
+func (r *Reconciler) verifyOutputs(ctx context.Context, app *v1beta2.SparkApplication) {
+	statuses := make([]v1beta2.OutputStatus, 0, len(app.Spec.Outputs))
+	for _, output := range app.Spec.Outputs {
+		status := v1beta2.OutputStatus{
+			Name:             output.Name,
+			State:            v1beta2.OutputStateAvailable,
+			VerificationTime: metav1.Now(),
+		}
+		var err error
+		if output.URI != nil {
+			status.Location = *output.URI
+			err = verifyObjectOutput(ctx, *output.URI)
+		} else if output.ConfigMapName != nil {
+			status.Location = *output.ConfigMapName
+			err = r.verifyConfigMapOutput(ctx, app.Namespace, *output.ConfigMapName)
+		}
+		if err != nil {
+			appLogger(app).Info("Output of SparkApplication is missing", "output", output.Name, "error", err)
+			r.recorder.Eventf(app, corev1.EventTypeWarning, common.EventSparkApplicationOutputMissing, "Output %s is missing: %v", output.Name, err)
+			status.State = v1beta2.OutputStateMissing
+			status.Message = err.Error()
+		}
+		statuses = append(statuses, status)
+	}
+	app.Status.Outputs = statuses
+}
+
+// verifyObjectOutput checks that an object with the given URI, or at least one object under it, exists.
+func verifyObjectOutput(ctx context.Context, uri string) error {
+	bucketURL, key, err := util.ParseObjectURI(uri)
+	if err != nil {
+		return err
+	}
+	bucket, err := blob.OpenBucket(ctx, bucketURL)
+	if err != nil {
+		return fmt.Errorf("failed to open bucket %s: %v", bucketURL, err)
+	}
+	defer bucket.Close()
+
+	exists, err := bucket.Exists(ctx, key)
+	if err != nil {
+		return fmt.Errorf("failed to check object %s: %v", uri, err)
+	}
+	if exists {
+		return nil
+	}
+
+	iter := bucket.List(&blob.ListOptions{Prefix: key + "/"})
+	if _, err := iter.Next(ctx); err == io.EOF {
+		return fmt.Errorf("no object found at %s", uri)
+	} else if err != nil {
+		return fmt.Errorf("failed to list objects under %s: %v", uri, err)
+	}
+	return nil
+}
+
+// verifyConfigMapOutput checks that the ConfigMap with the given name exists.
+func (r *Reconciler) verifyConfigMapOutput(ctx context.Context, namespace string, name string) error {
+	configMap := &corev1.ConfigMap{}
+	if err := r.client.Get(ctx, client.ObjectKey{Namespace: namespace, Name: name}, configMap); err != nil {
+		return fmt.Errorf("failed to get ConfigMap %s/%s: %v", namespace, name, err)
+	}
+	return nil
+}
@@ -0,0 +1,104 @@
+/*
+Copyright 2024 The Kubeflow authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package sparkapplication
+
+import (
+	"context"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/util/retry"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+
+	"github.com/kubeflow/spark-operator/api/v1beta2"
+	"github.com/kubeflow/spark-operator/pkg/common"
+	"github.com/kubeflow/spark-operator/pkg/util"
+)
+
+// getOwnerReferences returns the owner references of the services, ingresses, ConfigMaps and ResourceQuotas created
+// for the app.
+// If owner references are disabled, the resources are only labeled and are cleaned up when the app is deleted.
+func (r *Reconciler) getOwnerReferences(app *v1beta2.SparkApplication) []metav1.OwnerReference {
+	if r.options.DisableOwnerReferences {
+		return nil
+	}
+	return []metav1.OwnerReference{util.GetOwnerReference(app)}
+}
+
+// isOwnedBy returns whether the object was created for the app, i.e. it has an owner reference to the app or, if it
+// has no owner references, it is labeled with the name of the app.
+func (r *Reconciler) isOwnedBy(obj metav1.Object, app *v1beta2.SparkApplication) bool {
+	refs := obj.GetOwnerReferences()
+	for _, ref := range refs {
+		if ref.UID == app.UID {
+			return true
+		}
+	}
+	return len(refs) == 0 && obj.GetLabels()[common.LabelSparkAppName] == app.Name
+}
+
+// addFinalizer adds the finalizer to the app so that the resources created for it can be cleaned up explicitly
+// before it is deleted.
+func (r *Reconciler) addFinalizer(ctx context.Context, app *v1beta2.SparkApplication) error {
+	if controllerutil.ContainsFinalizer(app, common.SparkApplicationFinalizerName) {
+		return nil
+	}
+
+	return retry.RetryOnConflict(retry.DefaultRetry, func() error {
+		old, err := r.getSparkApplication(ctx, client.ObjectKeyFromObject(app))
+		if err != nil {
+			return err
+		}
+		if !controllerutil.AddFinalizer(old, common.SparkApplicationFinalizerName) {
+			return nil
+		}
+		return r.client.Update(ctx, old)
+	})
+}
+
+// removeFinalizer removes the finalizer from the app so that it can be deleted.
+func (r *Reconciler) removeFinalizer(ctx context.Context, app *v1beta2.SparkApplication) error {
+	return retry.RetryOnConflict(retry.DefaultRetry, func() error {
+		old, err := r.getSparkApplication(ctx, client.ObjectKeyFromObject(app))
+		if err != nil {
+			return client.IgnoreNotFound(err)
+		}
+		if !controllerutil.RemoveFinalizer(old, common.SparkApplicationFinalizerName) {
+			return nil
+		}
+		return r.client.Update(ctx, old)
+	})
+}
+
+func (r *Reconciler) deletePrometheusConfigMap(ctx context.Context, app *v1beta2.SparkApplication) error {
+	configMapName := util.GetPrometheusConfigMapName(app)
+	logger.Info("Deleting Prometheus ConfigMap", "name", configMapName, "namespace", app.Namespace)
+	if err := r.client.Delete(
+		ctx,
+		&corev1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      configMapName,
+				Namespace: app.Namespace,
+			},
+		},
+	); err != nil && !errors.IsNotFound(err) {
+		return err
+	}
+	return nil
+}
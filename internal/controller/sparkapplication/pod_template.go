@@ -0,0 +1,70 @@
+/*
+Copyright 2024 The Kubeflow authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package sparkapplication
+
+import (
+	"context"
+	"fmt"
+
+	corev1 "k8s.io/api/core/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/yaml"
+
+	"github.com/kubeflow/spark-operator/api/v1beta2"
+)
+
+// resolvePodTemplateConfigMaps reads the pod templates the driver and executor specs of the app reference in
+// ConfigMaps into their templates, so that they are rendered like inline templates. The spec of the app is changed
+// in memory only.
+func (r *Reconciler) resolvePodTemplateConfigMaps(ctx context.Context, app *v1beta2.SparkApplication) error {
+	podSpecs := []struct {
+		role string
+		spec *v1beta2.SparkPodSpec
+	}{
+		{role: "driver", spec: &app.Spec.Driver.SparkPodSpec},
+		{role: "executor", spec: &app.Spec.Executor.SparkPodSpec},
+	}
+	for _, podSpec := range podSpecs {
+		if podSpec.spec.TemplateConfigMap == nil || podSpec.spec.Template != nil {
+			continue
+		}
+		template, err := r.getPodTemplateFromConfigMap(ctx, app.Namespace, podSpec.spec.TemplateConfigMap)
+		if err != nil {
+			return fmt.Errorf("failed to get %s pod template: %v", podSpec.role, err)
+		}
+		podSpec.spec.Template = template
+	}
+	return nil
+}
+
+// getPodTemplateFromConfigMap parses the pod template held by the given key of a ConfigMap in the given namespace.
+func (r *Reconciler) getPodTemplateFromConfigMap(ctx context.Context, namespace string, ref *v1beta2.NameKey) (*corev1.PodTemplateSpec, error) {
+	configMap := &corev1.ConfigMap{}
+	if err := r.client.Get(ctx, client.ObjectKey{Namespace: namespace, Name: ref.Name}, configMap); err != nil {
+		return nil, fmt.Errorf("failed to get ConfigMap %s: %v", ref.Name, err)
+	}
+	data, ok := configMap.Data[ref.Key]
+	if !ok {
+		return nil, fmt.Errorf("key %s not found in ConfigMap %s", ref.Key, ref.Name)
+	}
+
+	pod := &corev1.Pod{}
+	if err := yaml.Unmarshal([]byte(data), pod); err != nil {
+		return nil, fmt.Errorf("failed to parse pod template in key %s of ConfigMap %s: %v", ref.Key, ref.Name, err)
+	}
+	return &corev1.PodTemplateSpec{ObjectMeta: pod.ObjectMeta, Spec: pod.Spec}, nil
+}
@@ -0,0 +1,233 @@
+/*
+Copyright 2024 The Kubeflow authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package sparkapplication
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"path"
+	"strconv"
+	"strings"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	corev1client "k8s.io/client-go/kubernetes/typed/core/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/kubeflow/spark-operator/api/v1beta2"
+	"github.com/kubeflow/spark-operator/pkg/common"
+	"github.com/kubeflow/spark-operator/pkg/naming"
+)
+
+const (
+	// defaultPostRunWebhookTimeout is the timeout of a post-run webhook call if none is specified.
+	defaultPostRunWebhookTimeout = 10 * time.Second
+)
+
+// getPendingPostRunActions returns the post-run actions that have not been executed since the current run
+// terminated.
+func getPendingPostRunActions(app *v1beta2.SparkApplication) []v1beta2.PostRunAction {
+	executed := make(map[string]bool, len(app.Status.PostRunActions))
+	for _, status := range app.Status.PostRunActions {
+		executed[status.Name] = true
+	}
+
+	var pending []v1beta2.PostRunAction
+	for _, action := range app.Spec.PostRunActions {
+		if !executed[action.Name] {
+			pending = append(pending, action)
+		}
+	}
+	return pending
+}
+
+// runPostRunActions executes the given post-run actions in order and records their results in the status. Each
+// action is executed once per run; failed actions are recorded and not retried.
+func (r *Reconciler) runPostRunActions(ctx context.Context, app *v1beta2.SparkApplication, actions []v1beta2.PostRunAction) {
+	for _, action := range actions {
+		status := v1beta2.PostRunActionStatus{
+			Name:  action.Name,
+			State: v1beta2.PostRunActionStateSucceeded,
+		}
+		appLogger(app).Info("Running post-run action", "action", action.Name)
+		if err := r.runPostRunAction(ctx, app, action); err != nil {
+			appLogger(app).Error(err, "Failed to run post-run action", "action", action.Name)
+			r.recorder.Eventf(app, corev1.EventTypeWarning, common.EventSparkApplicationPostRunActionFailed, "Post-run action %s failed: %v", action.Name, err)
+			status.State = v1beta2.PostRunActionStateFailed
+			status.Message = err.Error()
+		}
+		status.CompletionTime = metav1.Now()
+		app.Status.PostRunActions = append(app.Status.PostRunActions, status)
+	}
+}
+
+func (r *Reconciler) runPostRunAction(ctx context.Context, app *v1beta2.SparkApplication, action v1beta2.PostRunAction) error {
+	switch {
+	case action.Webhook != nil:
+		if !isPostRunWebhookURLAllowed(action.Webhook.URL, r.options.PostRunWebhookURLPrefixes) {
+			return fmt.Errorf("webhook URL %s is not allowed by the operator", action.Webhook.URL)
+		}
+		timeout := defaultPostRunWebhookTimeout
+		if action.Webhook.TimeoutSeconds != nil {
+			timeout = time.Duration(*action.Webhook.TimeoutSeconds) * time.Second
+		}
+		hook := &Hook{URL: action.Webhook.URL, Timeout: timeout, FailurePolicy: HookFailurePolicyFail}
+		_, err := hook.call(ctx, HookPhasePostCompletion, app)
+		return err
+	case action.ConfigMapSummary != nil:
+		return r.createPostRunSummary(ctx, app, action.ConfigMapSummary)
+	case action.DriverLogs != nil:
+		return r.copyDriverLogs(ctx, app, action.DriverLogs)
+	}
+	return fmt.Errorf("post-run action %s does not define an action", action.Name)
+}
+
+// isPostRunWebhookURLAllowed returns whether the webhook URL of a post-run action starts with one of the allowed URL
+// prefixes configured by the operator admin. The scheme and host must match exactly, and the cleaned path must start
+// with the path of the prefix, so that app authors cannot make the operator call arbitrary endpoints.
+func isPostRunWebhookURLAllowed(rawURL string, prefixes []string) bool {
+	u, err := url.Parse(rawURL)
+	if err != nil || u.User != nil || u.Host == "" {
+		return false
+	}
+	urlPath := path.Clean("/" + u.Path)
+	if strings.HasSuffix(u.Path, "/") && urlPath != "/" {
+		urlPath += "/"
+	}
+	for _, prefix := range prefixes {
+		allowed, err := url.Parse(prefix)
+		if err != nil || allowed.Host == "" {
+			continue
+		}
+		if u.Scheme == allowed.Scheme && u.Host == allowed.Host && strings.HasPrefix(urlPath, allowed.Path) {
+			return true
+		}
+	}
+	return false
+}
+
+// createPostRunSummary creates or updates a ConfigMap summarizing the current run of the app. An existing ConfigMap
+// is only updated if it is labeled with the name of the app, so that the operator never overwrites ConfigMaps not
+// created for the app.
+func (r *Reconciler) createPostRunSummary(ctx context.Context, app *v1beta2.SparkApplication, action *v1beta2.ConfigMapSummaryPostRunAction) error {
+	name := naming.PostRunSummaryName(app)
+	if action.ConfigMapName != nil && *action.ConfigMapName != "" {
+		name = *action.ConfigMapName
+	}
+
+	configMap := &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: app.Namespace,
+			Labels: map[string]string{
+				common.LabelSparkAppName: app.Name,
+				common.LabelSubmissionID: app.Status.SubmissionID,
+			},
+		},
+		Data: buildPostRunSummary(app),
+	}
+
+	existing := &corev1.ConfigMap{}
+	if err := r.client.Get(ctx, client.ObjectKeyFromObject(configMap), existing); err != nil {
+		if !errors.IsNotFound(err) {
+			return fmt.Errorf("failed to get summary ConfigMap %s/%s: %v", configMap.Namespace, configMap.Name, err)
+		}
+		if err := r.client.Create(ctx, configMap); err != nil {
+			return fmt.Errorf("failed to create summary ConfigMap %s/%s: %v", configMap.Namespace, configMap.Name, err)
+		}
+		return nil
+	}
+
+	if existing.Labels[common.LabelSparkAppName] != app.Name {
+		return fmt.Errorf("ConfigMap %s/%s already exists and was not created for SparkApplication %s", configMap.Namespace, configMap.Name, app.Name)
+	}
+	existing.Labels = configMap.Labels
+	existing.Data = configMap.Data
+	if err := r.client.Update(ctx, existing); err != nil {
+		return fmt.Errorf("failed to update summary ConfigMap %s/%s: %v", configMap.Namespace, configMap.Name, err)
+	}
+	return nil
+}
+
+// buildPostRunSummary returns the summary of the current run of the app.
+func buildPostRunSummary(app *v1beta2.SparkApplication) map[string]string {
+	summary := map[string]string{
+		"name":               app.Name,
+		"namespace":          app.Namespace,
+		"submissionID":       app.Status.SubmissionID,
+		"state":              string(app.Status.AppState.State),
+		"submissionAttempts": strconv.FormatInt(int64(app.Status.SubmissionAttempts), 10),
+		"executionAttempts":  strconv.FormatInt(int64(app.Status.ExecutionAttempts), 10),
+		"driverPodName":      app.Status.DriverInfo.PodName,
+		"executors":          strconv.Itoa(len(app.Status.ExecutorState)),
+	}
+	if app.Status.AppState.ErrorMessage != "" {
+		summary["errorMessage"] = app.Status.AppState.ErrorMessage
+	}
+	if !app.Status.LastSubmissionAttemptTime.IsZero() {
+		summary["submissionTime"] = app.Status.LastSubmissionAttemptTime.UTC().Format(time.RFC3339)
+	}
+	if !app.Status.TerminationTime.IsZero() {
+		summary["terminationTime"] = app.Status.TerminationTime.UTC().Format(time.RFC3339)
+	}
+	return summary
+}
+
+// copyDriverLogs copies the logs of the driver container to the application archive.
+func (r *Reconciler) copyDriverLogs(ctx context.Context, app *v1beta2.SparkApplication, action *v1beta2.DriverLogsPostRunAction) error {
+	if r.options.Archive == nil {
+		return fmt.Errorf("the operator is not configured with an application archive")
+	}
+	if app.Status.DriverInfo.PodName == "" {
+		return fmt.Errorf("driver pod name is unknown")
+	}
+
+	podsClient, err := r.getPodsClient()
+	if err != nil {
+		return err
+	}
+	logs, err := podsClient.Pods(app.Namespace).GetLogs(app.Status.DriverInfo.PodName, &corev1.PodLogOptions{
+		Container: common.SparkDriverContainerName,
+		TailLines: action.TailLines,
+	}).DoRaw(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to get logs of driver pod %s/%s: %v", app.Namespace, app.Status.DriverInfo.PodName, err)
+	}
+
+	key, err := r.options.Archive.PutDriverLogs(ctx, app, logs)
+	if err != nil {
+		return err
+	}
+	appLogger(app).Info("Archived driver logs", "key", key)
+	return nil
+}
+
+// getPodsClient returns the client reading the logs of pods, which is created on first use.
+func (r *Reconciler) getPodsClient() (corev1client.PodsGetter, error) {
+	r.podsClientOnce.Do(func() {
+		clientset, err := corev1client.NewForConfig(r.manager.GetConfig())
+		if err != nil {
+			r.podsClientErr = fmt.Errorf("failed to create client: %v", err)
+			return
+		}
+		r.podsClient = clientset
+	})
+	return r.podsClient, r.podsClientErr
+}
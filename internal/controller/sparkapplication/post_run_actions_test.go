@@ -0,0 +1,107 @@
+/*
+Copyright 2024 The Kubeflow authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package sparkapplication
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gocloud.dev/blob/memblob"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+
+	"github.com/kubeflow/spark-operator/api/v1beta2"
+	"github.com/kubeflow/spark-operator/pkg/archive"
+)
+
+func TestGetPendingPostRunActions(t *testing.T) {
+	app := &v1beta2.SparkApplication{}
+	app.Spec.PostRunActions = []v1beta2.PostRunAction{
+		{Name: "summary", ConfigMapSummary: &v1beta2.ConfigMapSummaryPostRunAction{}},
+		{Name: "logs", DriverLogs: &v1beta2.DriverLogsPostRunAction{}},
+	}
+	app.Status.PostRunActions = []v1beta2.PostRunActionStatus{{Name: "summary", State: v1beta2.PostRunActionStateSucceeded}}
+
+	pending := getPendingPostRunActions(app)
+	require.Len(t, pending, 1)
+	assert.Equal(t, "logs", pending[0].Name)
+}
+
+func TestIsPostRunWebhookURLAllowed(t *testing.T) {
+	prefixes := []string{"https://hooks.example.com/spark/", "http://notifier.monitoring.svc:8080"}
+
+	testCases := []struct {
+		url      string
+		expected bool
+	}{
+		{url: "https://hooks.example.com/spark/done", expected: true},
+		{url: "https://hooks.example.com/spark/", expected: true},
+		{url: "http://notifier.monitoring.svc:8080/any/path", expected: true},
+		{url: "http://hooks.example.com/spark/done", expected: false},
+		{url: "https://hooks.example.com/admin", expected: false},
+		{url: "https://hooks.example.com/spark/../admin", expected: false},
+		{url: "https://hooks.example.com.attacker.io/spark/done", expected: false},
+		{url: "https://user@hooks.example.com/spark/done", expected: false},
+		{url: "http://notifier.monitoring.svc/any/path", expected: false},
+		{url: "http://169.254.169.254/latest/meta-data/", expected: false},
+		{url: "/spark/done", expected: false},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.url, func(t *testing.T) {
+			assert.Equal(t, tc.expected, isPostRunWebhookURLAllowed(tc.url, prefixes))
+		})
+	}
+	assert.False(t, isPostRunWebhookURLAllowed("https://hooks.example.com/spark/done", nil))
+}
+
+func TestCopyDriverLogs(t *testing.T) {
+	ctx := context.Background()
+	app := &v1beta2.SparkApplication{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "test-app",
+			Namespace: "default",
+		},
+	}
+	app.Status.SubmissionID = "test-submission-id"
+	app.Status.DriverInfo.PodName = "test-app-driver"
+	action := &v1beta2.DriverLogsPostRunAction{}
+
+	r := NewReconciler(nil, nil, nil, nil, nil, Options{})
+	assert.ErrorContains(t, r.copyDriverLogs(ctx, app, action), "not configured with an application archive")
+
+	bucket := memblob.OpenBucket(nil)
+	defer bucket.Close()
+	r = NewReconciler(nil, nil, nil, nil, nil, Options{Archive: archive.New(bucket)})
+	clientset := fake.NewSimpleClientset(&corev1.Pod{ObjectMeta: metav1.ObjectMeta{Name: "test-app-driver", Namespace: app.Namespace}})
+	fakePodsClient := clientset.CoreV1()
+	r.podsClientOnce.Do(func() { r.podsClient = fakePodsClient })
+
+	require.NoError(t, r.copyDriverLogs(ctx, app, action))
+	require.NoError(t, r.copyDriverLogs(ctx, app, action))
+	logs, err := bucket.ReadAll(ctx, archive.GetDriverLogsKey(app.Namespace, app.Name, archive.GetRunID(app)))
+	require.NoError(t, err)
+	assert.Equal(t, "fake logs", string(logs))
+
+	// The pods client is created once and reused across calls.
+	podsClient, err := r.getPodsClient()
+	require.NoError(t, err)
+	assert.Same(t, fakePodsClient, podsClient)
+}
@@ -0,0 +1,105 @@
+/*
+Copyright 2024 The Kubeflow authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package sparkapplication
+
+import (
+	"context"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/manager"
+
+	"github.com/kubeflow/spark-operator/api/v1beta2"
+	"github.com/kubeflow/spark-operator/pkg/common"
+	"github.com/kubeflow/spark-operator/pkg/util"
+)
+
+// prometheusConfigMapCollector periodically deletes the Prometheus ConfigMaps of SparkApplications that no longer
+// exist. Such ConfigMaps are left behind if they lost their owner references, e.g. when restored from a backup
+// without the application, so the garbage collector of Kubernetes does not delete them.
+type prometheusConfigMapCollector struct {
+	client     client.Client
+	reader     client.Reader
+	namespaces []string
+	interval   time.Duration
+}
+
+// prometheusConfigMapCollector implements manager.Runnable and manager.LeaderElectionRunnable.
+var _ manager.Runnable = &prometheusConfigMapCollector{}
+var _ manager.LeaderElectionRunnable = &prometheusConfigMapCollector{}
+
+func newPrometheusConfigMapCollector(client client.Client, reader client.Reader, namespaces []string, interval time.Duration) *prometheusConfigMapCollector {
+	return &prometheusConfigMapCollector{
+		client:     client,
+		reader:     reader,
+		namespaces: namespaces,
+		interval:   interval,
+	}
+}
+
+// Start implements manager.Runnable.
+func (c *prometheusConfigMapCollector) Start(ctx context.Context) error {
+	wait.UntilWithContext(ctx, c.collect, c.interval)
+	return nil
+}
+
+// NeedLeaderElection implements manager.LeaderElectionRunnable.
+func (c *prometheusConfigMapCollector) NeedLeaderElection() bool {
+	return true
+}
+
+func (c *prometheusConfigMapCollector) collect(ctx context.Context) {
+	namespaces := c.namespaces
+	if len(namespaces) == 0 || util.ContainsString(namespaces, metav1.NamespaceAll) {
+		namespaces = []string{metav1.NamespaceAll}
+	}
+
+	for _, namespace := range namespaces {
+		// ConfigMaps are not cached by the manager, so they are listed from the API server directly.
+		configMaps := &corev1.ConfigMapList{}
+		if err := c.reader.List(ctx, configMaps, client.InNamespace(namespace), client.HasLabels{common.LabelSparkAppName}); err != nil {
+			logger.Error(err, "Failed to list ConfigMaps of SparkApplications", "namespace", namespace)
+			continue
+		}
+
+		for i := range configMaps.Items {
+			configMap := &configMaps.Items[i]
+			app := &v1beta2.SparkApplication{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      configMap.Labels[common.LabelSparkAppName],
+					Namespace: configMap.Namespace,
+				},
+			}
+			if configMap.Name != util.GetPrometheusConfigMapName(app) {
+				continue
+			}
+
+			if err := c.client.Get(ctx, client.ObjectKeyFromObject(app), app); err == nil || !errors.IsNotFound(err) {
+				continue
+			}
+
+			logger.Info("Deleting Prometheus ConfigMap of deleted SparkApplication", "name", configMap.Name, "namespace", configMap.Namespace)
+			if err := c.client.Delete(ctx, configMap, client.Preconditions{UID: &configMap.UID}); err != nil && !errors.IsNotFound(err) {
+				logger.Error(err, "Failed to delete Prometheus ConfigMap", "name", configMap.Name, "namespace", configMap.Namespace)
+			}
+		}
+	}
+}
@@ -0,0 +1,113 @@
+/*
+Copyright 2024 The Kubeflow authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package sparkapplication
+
+import (
+	"context"
+	"fmt"
+
+	rbacv1 "k8s.io/api/rbac/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/kubeflow/spark-operator/api/v1beta2"
+	"github.com/kubeflow/spark-operator/pkg/util"
+)
+
+// createDriverPVCRBAC creates a Role and RoleBinding granting the driver service account of the given
+// SparkApplication the permissions Spark needs to create, reuse and delete executor persistent volume
+// claims. Both objects are owned by the SparkApplication and garbage collected along with it.
+func (r *Reconciler) createDriverPVCRBAC(ctx context.Context, app *v1beta2.SparkApplication) error {
+	name := util.GetDriverPVCRBACName(app)
+	serviceAccount := util.GetDriverServiceAccountName(app)
+
+	role := &rbacv1.Role{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:            name,
+			Namespace:       app.Namespace,
+			Labels:          util.GetResourceLabels(app),
+			OwnerReferences: []metav1.OwnerReference{util.GetOwnerReference(app)},
+		},
+		Rules: []rbacv1.PolicyRule{
+			{
+				APIGroups: []string{""},
+				Resources: []string{"persistentvolumeclaims"},
+				Verbs:     []string{"get", "list", "watch", "create", "update", "patch", "delete", "deletecollection"},
+			},
+		},
+	}
+	if err := r.client.Create(ctx, role); err != nil {
+		if !errors.IsAlreadyExists(err) {
+			return wrapRBACError(err, "role", name)
+		}
+		existing := &rbacv1.Role{}
+		if err := r.client.Get(ctx, client.ObjectKeyFromObject(role), existing); err != nil {
+			return fmt.Errorf("failed to get role %s: %v", name, err)
+		}
+		existing.Rules = role.Rules
+		if err := r.client.Update(ctx, existing); err != nil {
+			return wrapRBACError(err, "role", name)
+		}
+	}
+
+	roleBinding := &rbacv1.RoleBinding{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:            name,
+			Namespace:       app.Namespace,
+			Labels:          util.GetResourceLabels(app),
+			OwnerReferences: []metav1.OwnerReference{util.GetOwnerReference(app)},
+		},
+		Subjects: []rbacv1.Subject{
+			{
+				Kind:      rbacv1.ServiceAccountKind,
+				Name:      serviceAccount,
+				Namespace: app.Namespace,
+			},
+		},
+		RoleRef: rbacv1.RoleRef{
+			APIGroup: rbacv1.GroupName,
+			Kind:     "Role",
+			Name:     name,
+		},
+	}
+	if err := r.client.Create(ctx, roleBinding); err != nil {
+		if !errors.IsAlreadyExists(err) {
+			return wrapRBACError(err, "role binding", name)
+		}
+		existing := &rbacv1.RoleBinding{}
+		if err := r.client.Get(ctx, client.ObjectKeyFromObject(roleBinding), existing); err != nil {
+			return fmt.Errorf("failed to get role binding %s: %v", name, err)
+		}
+		existing.Subjects = roleBinding.Subjects
+		if err := r.client.Update(ctx, existing); err != nil {
+			return wrapRBACError(err, "role binding", name)
+		}
+	}
+
+	appLogger(app).Info("Created RBAC for driver service account", "serviceAccount", serviceAccount)
+	return nil
+}
+
+// wrapRBACError turns a permission error into an actionable message, since the operator can only grant
+// permissions it holds itself.
+func wrapRBACError(err error, kind string, name string) error {
+	if errors.IsForbidden(err) {
+		return fmt.Errorf("spark operator is not allowed to manage %s %s, grant it permissions on roles, rolebindings and persistentvolumeclaims: %v", kind, name, err)
+	}
+	return fmt.Errorf("failed to create %s %s: %v", kind, name, err)
+}
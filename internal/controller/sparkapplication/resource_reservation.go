@@ -0,0 +1,132 @@
+/*
+Copyright 2024 The Kubeflow authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package sparkapplication
+
+import (
+	"context"
+	"fmt"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/kubeflow/spark-operator/api/v1beta2"
+	"github.com/kubeflow/spark-operator/pkg/common"
+	"github.com/kubeflow/spark-operator/pkg/naming"
+	"github.com/kubeflow/spark-operator/pkg/util"
+)
+
+// reconcileResourceReservation creates or updates the ResourceQuota recording the resources reserved for the
+// executors the app may still scale up to with dynamic allocation, given the number of its active executors. The
+// reservation is enforced by the resource quota enforcement of the webhook, which subtracts it from the ResourceQuotas
+// of the namespace when admitting other SparkApplications and their pods. The ResourceQuota itself is scoped to a
+// priority class no pods use, so that Kubernetes does not cap the pods of the namespace at the reservation.
+func (r *Reconciler) reconcileResourceReservation(ctx context.Context, app *v1beta2.SparkApplication, activeExecutors int32) error {
+	reservation := util.GetResourceReservation(app, activeExecutors)
+	if reservation == nil {
+		return nil
+	}
+
+	quota := &corev1.ResourceQuota{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      naming.ResourceReservationName(app),
+			Namespace: app.Namespace,
+			Labels: map[string]string{
+				common.LabelSparkAppName:        app.Name,
+				common.LabelResourceReservation: "true",
+			},
+			OwnerReferences: r.getOwnerReferences(app),
+		},
+		Spec: corev1.ResourceQuotaSpec{
+			Hard: reservation,
+			ScopeSelector: &corev1.ScopeSelector{
+				MatchExpressions: []corev1.ScopedResourceSelectorRequirement{{
+					ScopeName: corev1.ResourceQuotaScopePriorityClass,
+					Operator:  corev1.ScopeSelectorOpIn,
+					Values:    []string{common.ResourceReservationPriorityClass},
+				}},
+			},
+		},
+	}
+
+	existing := &corev1.ResourceQuota{}
+	if err := r.client.Get(ctx, client.ObjectKeyFromObject(quota), existing); err != nil {
+		if !errors.IsNotFound(err) {
+			return fmt.Errorf("failed to get resource reservation %s/%s: %v", quota.Namespace, quota.Name, err)
+		}
+		appLogger(app).Info("Creating resource reservation", "reservation", quota.Name, "hard", reservation)
+		if err := r.client.Create(ctx, quota); err != nil {
+			return fmt.Errorf("failed to create resource reservation %s/%s: %v", quota.Namespace, quota.Name, err)
+		}
+		return nil
+	}
+
+	if equality.Semantic.DeepEqual(existing.Spec, quota.Spec) {
+		return nil
+	}
+	existing.Spec = quota.Spec
+	appLogger(app).Info("Updating resource reservation", "reservation", quota.Name, "hard", reservation)
+	if err := r.client.Update(ctx, existing); err != nil {
+		return fmt.Errorf("failed to update resource reservation %s/%s: %v", quota.Namespace, quota.Name, err)
+	}
+	return nil
+}
+
+// updateResourceReservation shrinks or grows the resources reserved for the app as its executors start and terminate.
+func (r *Reconciler) updateResourceReservation(ctx context.Context, app *v1beta2.SparkApplication) error {
+	activeExecutors, err := r.getActiveExecutorCount(ctx, app)
+	if err != nil {
+		return err
+	}
+	return r.reconcileResourceReservation(ctx, app, activeExecutors)
+}
+
+// getActiveExecutorCount returns the number of executor pods of the app which have not terminated. They are accounted
+// for by the ResourceQuotas and thus no longer need to be reserved.
+func (r *Reconciler) getActiveExecutorCount(ctx context.Context, app *v1beta2.SparkApplication) (int32, error) {
+	pods, err := r.getExecutorPods(ctx, app)
+	if err != nil {
+		return 0, err
+	}
+	var count int32
+	for _, pod := range pods.Items {
+		if pod.Status.Phase != corev1.PodSucceeded && pod.Status.Phase != corev1.PodFailed {
+			count++
+		}
+	}
+	return count, nil
+}
+
+// deleteResourceReservation deletes the ResourceQuota recording the resources reserved for the app, if any.
+func (r *Reconciler) deleteResourceReservation(ctx context.Context, app *v1beta2.SparkApplication) error {
+	if !r.options.Scheduling.EnableResourceReservation {
+		return nil
+	}
+
+	quota := &corev1.ResourceQuota{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      naming.ResourceReservationName(app),
+			Namespace: app.Namespace,
+		},
+	}
+	if err := r.client.Delete(ctx, quota); err != nil && !errors.IsNotFound(err) {
+		return fmt.Errorf("failed to delete resource reservation %s/%s: %v", quota.Namespace, quota.Name, err)
+	}
+	return nil
+}
@@ -0,0 +1,98 @@
+/*
+Copyright 2024 The Kubeflow authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package sparkapplication
+
+import (
+	"context"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	ctrl "sigs.k8s.io/controller-runtime"
+
+	"github.com/kubeflow/spark-operator/api/v1beta2"
+)
+
+// SchedulingOptions configures the features holding SparkApplications back until they can be scheduled, as well as
+// detecting the SparkApplications whose executors cannot be scheduled.
+type SchedulingOptions struct {
+	// EnableWaitingForDependencies enables holding SparkApplications referencing Secrets, ConfigMaps or service
+	// accounts which do not exist in the WaitingForDependencies state until they do, instead of submitting them.
+	// ConfigMaps and service accounts are watched, while Secrets are polled for as only operator-created ones are cached.
+	EnableWaitingForDependencies bool
+
+	// EnableResourceReservation enables reserving the resources of the executors SparkApplications may scale up to
+	// with dynamic allocation, so that later SparkApplications cannot starve their scale-ups. The reservations shrink
+	// as executors start and are enforced by the resource quota enforcement of the webhook, which must be enabled.
+	EnableResourceReservation bool
+
+	// EnableExecutorQuotaFailureDetection enables detecting running SparkApplications with an executor quota failure
+	// policy missing executors because of an exhausted ResourceQuota, recording the shortfall in their status and
+	// failing those whose policy says so.
+	EnableExecutorQuotaFailureDetection bool
+
+	// EnableImagePrefetch enables pulling the executor image onto the candidate nodes of the executors before
+	// submitting SparkApplications with image prefetch configured.
+	EnableImagePrefetch bool
+	// ImagePrefetchPauseImage is the image of the main container of the image prefetch pods.
+	ImagePrefetchPauseImage string
+}
+
+// checkSubmissionPrerequisites returns whether the new SparkApplication can be submitted, i.e., whether the resources
+// it waits for are ready, the objects it references exist and its executor image is prefetched. Otherwise, the status
+// of the SparkApplication is updated accordingly and the result to requeue it with is returned.
+func (r *Reconciler) checkSubmissionPrerequisites(ctx context.Context, app *v1beta2.SparkApplication) (bool, ctrl.Result) {
+	if app.Spec.WaitFor != nil && len(app.Spec.WaitFor.Resources) > 0 {
+		ready, err := r.checkDependencies(ctx, app)
+		if err != nil {
+			app.Status.AppState = v1beta2.ApplicationState{
+				State:        v1beta2.ApplicationStateFailed,
+				ErrorMessage: err.Error(),
+			}
+			app.Status.TerminationTime = metav1.Now()
+			r.recordSparkApplicationEvent(app)
+			return false, ctrl.Result{}
+		}
+		if !ready {
+			return false, ctrl.Result{RequeueAfter: dependencyPollInterval}
+		}
+	}
+
+	if r.options.Scheduling.EnableWaitingForDependencies {
+		missing, err := r.getMissingReferencedObjects(ctx, app)
+		if err != nil {
+			appLogger(app).Error(err, "Failed to check referenced objects")
+		}
+		if len(missing) > 0 {
+			app.Status.AppState = v1beta2.ApplicationState{
+				State:        v1beta2.ApplicationStateWaitingForDependencies,
+				ErrorMessage: getMissingReferencedObjectsMessage(missing),
+			}
+			r.recordSparkApplicationEvent(app)
+			return false, ctrl.Result{RequeueAfter: dependencyPollInterval}
+		}
+	}
+
+	if r.options.Scheduling.EnableImagePrefetch && app.Spec.ImagePrefetch != nil {
+		done, err := r.prefetchExecutorImage(ctx, app)
+		if err != nil {
+			appLogger(app).Error(err, "Failed to prefetch executor image")
+		}
+		if !done {
+			return false, ctrl.Result{RequeueAfter: imagePrefetchPollInterval}
+		}
+	}
+	return true, ctrl.Result{}
+}
@@ -0,0 +1,175 @@
+/*
+Copyright 2024 The Kubeflow authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package sparkapplication
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"slices"
+	"sort"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/kubeflow/spark-operator/api/v1beta2"
+	"github.com/kubeflow/spark-operator/pkg/common"
+	"github.com/kubeflow/spark-operator/pkg/util"
+)
+
+const (
+	// defaultSecretRotationBatchSize is the default maximum number of executors restarted at once.
+	defaultSecretRotationBatchSize = 1
+
+	// defaultSecretRotationBatchInterval is the default minimum interval between restarting two batches of executors.
+	defaultSecretRotationBatchInterval = 60 * time.Second
+
+	// secretRotationStartDelay is the delay between publishing the hash of the rotated Secrets and restarting the first
+	// batch of executors, so that the webhook records the new hash on the executors replacing them.
+	secretRotationStartDelay = 5 * time.Second
+)
+
+// rotateExecutorSecrets rolls out changes to the contents of the Secrets mounted into the executors of a running
+// application by deleting in batches the executors whose recorded secret hash, set by the webhook from the status
+// when they were created, differs from the hash of the current contents. Spark replaces the deleted executors with
+// new ones mounting the current contents. It returns the duration after which the rollout should be checked again.
+func (r *Reconciler) rotateExecutorSecrets(ctx context.Context, app *v1beta2.SparkApplication) (time.Duration, error) {
+	if app.Spec.SecretRotation == nil || len(getRotatedExecutorSecretNames(app)) == 0 {
+		return 0, nil
+	}
+
+	batchSize := defaultSecretRotationBatchSize
+	if app.Spec.SecretRotation.BatchSize != nil {
+		batchSize = int(*app.Spec.SecretRotation.BatchSize)
+	}
+	batchInterval := defaultSecretRotationBatchInterval
+	if app.Spec.SecretRotation.BatchIntervalSeconds != nil {
+		batchInterval = time.Duration(*app.Spec.SecretRotation.BatchIntervalSeconds) * time.Second
+	}
+	// Secrets are polled, as changes to them do not trigger reconciliations.
+	requeueAfter := max(batchInterval, defaultSecretRotationBatchInterval)
+
+	hash, err := r.getExecutorSecretsHash(ctx, app)
+	if err != nil {
+		return requeueAfter, err
+	}
+
+	status := app.Status.SecretRotation
+	now := metav1.Now()
+	switch {
+	case status == nil:
+		// The executors of the current run were created with the current contents of the Secrets.
+		app.Status.SecretRotation = &v1beta2.SecretRotationStatus{SecretHash: hash}
+		return requeueAfter, nil
+	case status.SecretHash != hash:
+		// Start a new rollout, restarting any rollout in progress.
+		status.SecretHash = hash
+		status.RolloutStartTime = &now
+		r.recorder.Eventf(app, corev1.EventTypeNormal, common.EventSparkExecutorSecretRotation, "Rolling out rotated secrets to executors")
+		return secretRotationStartDelay, nil
+	case status.RolloutStartTime == nil:
+		return requeueAfter, nil
+	}
+
+	pods, err := r.getExecutorPods(ctx, app)
+	if err != nil {
+		return requeueAfter, err
+	}
+
+	var stale []*corev1.Pod
+	pending := false
+	for i := range pods.Items {
+		pod := &pods.Items[i]
+		if pod.DeletionTimestamp != nil || pod.Status.Phase == corev1.PodSucceeded || pod.Status.Phase == corev1.PodFailed {
+			continue
+		}
+		if pod.Annotations[common.AnnotationSecretHash] != status.SecretHash {
+			stale = append(stale, pod)
+		} else if pod.Status.Phase != corev1.PodRunning {
+			pending = true
+		}
+	}
+
+	if len(stale) == 0 {
+		status.RolloutStartTime = nil
+		r.recorder.Eventf(app, corev1.EventTypeNormal, common.EventSparkExecutorSecretRotation, "Rolled out rotated secrets to all executors")
+		return requeueAfter, nil
+	}
+
+	// Wait for the replacements of the previous batch to be running before restarting the next batch.
+	if pending {
+		return batchInterval, nil
+	}
+	if status.LastBatchTime != nil {
+		if elapsed := now.Sub(status.LastBatchTime.Time); elapsed < batchInterval {
+			return batchInterval - elapsed, nil
+		}
+	}
+
+	slices.SortFunc(stale, func(a, b *corev1.Pod) int {
+		return a.CreationTimestamp.Compare(b.CreationTimestamp.Time)
+	})
+	for _, pod := range stale[:min(batchSize, len(stale))] {
+		if err := r.client.Delete(ctx, pod); err != nil && !errors.IsNotFound(err) {
+			return batchInterval, fmt.Errorf("failed to delete executor pod %s: %v", pod.Name, err)
+		}
+		appLogger(app).Info("Restarted executor to roll out rotated secrets", "pod", pod.Name)
+	}
+	status.LastBatchTime = &now
+	return batchInterval, nil
+}
+
+// getRotatedExecutorSecretNames returns the sorted names of the Secrets mounted into the executors whose changes are
+// rolled out. The generated authentication secret is excluded, as executors restarted with a new one could not
+// authenticate with the driver, which keeps the one it was started with.
+func getRotatedExecutorSecretNames(app *v1beta2.SparkApplication) []string {
+	authSecretName := util.GetSparkAuthSecretName(app)
+	names := make([]string, 0, len(app.Spec.Executor.Secrets))
+	for _, secret := range app.Spec.Executor.Secrets {
+		if secret.Name != authSecretName {
+			names = append(names, secret.Name)
+		}
+	}
+	sort.Strings(names)
+	return slices.Compact(names)
+}
+
+// getExecutorSecretsHash returns the hash of the contents of the Secrets mounted into the executors.
+func (r *Reconciler) getExecutorSecretsHash(ctx context.Context, app *v1beta2.SparkApplication) (string, error) {
+	hasher := sha256.New()
+	for _, name := range getRotatedExecutorSecretNames(app) {
+		secret := &corev1.Secret{}
+		// Secrets of users are not cached, so read them from the API server.
+		if err := r.manager.GetAPIReader().Get(ctx, client.ObjectKey{Namespace: app.Namespace, Name: name}, secret); err != nil {
+			return "", fmt.Errorf("failed to get secret %s: %v", name, err)
+		}
+		keys := make([]string, 0, len(secret.Data))
+		for key := range secret.Data {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+		fmt.Fprintf(hasher, "%s\n", name)
+		for _, key := range keys {
+			fmt.Fprintf(hasher, "%s=%s\n", key, secret.Data[key])
+		}
+	}
+	return hex.EncodeToString(hasher.Sum(nil)), nil
+}
@@ -0,0 +1,126 @@
+/*
+Copyright 2024 The Kubeflow authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package sparkapplication
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/kubeflow/spark-operator/api/v1beta2"
+	"github.com/kubeflow/spark-operator/pkg/common"
+	"github.com/kubeflow/spark-operator/pkg/util"
+)
+
+// slaViolationTypes are the types of SLA violations in the order they are checked.
+var slaViolationTypes = []v1beta2.SLAViolationType{
+	v1beta2.SLAViolationMaxPendingDuration,
+	v1beta2.SLAViolationMaxRunDuration,
+	v1beta2.SLAViolationExpectedCompletionBy,
+}
+
+// checkSLA records the violations of the SLA of the SparkApplication that were not recorded yet in its status, and
+// emits a Warning event and increments the SLA violation metric for each of them. It returns the duration until the
+// next deadline of the SLA, or zero if there is none.
+func (r *Reconciler) checkSLA(ctx context.Context, app *v1beta2.SparkApplication) (time.Duration, error) {
+	deadlines, err := util.GetSLADeadlines(app)
+	if err != nil {
+		return 0, fmt.Errorf("failed to get SLA deadlines: %v", err)
+	}
+
+	old := app.DeepCopy()
+	now := time.Now()
+	var violations []v1beta2.SLAViolation
+	var requeueAfter time.Duration
+	for _, violationType := range slaViolationTypes {
+		deadline, ok := deadlines[violationType]
+		if !ok || hasSLAViolation(app, violationType) {
+			continue
+		}
+		if remaining := deadline.Sub(now); remaining > 0 {
+			if requeueAfter == 0 || remaining < requeueAfter {
+				requeueAfter = remaining
+			}
+			continue
+		}
+		violations = append(violations, v1beta2.SLAViolation{
+			Type:    violationType,
+			Message: getSLAViolationMessage(app, violationType, deadline),
+			Time:    metav1.NewTime(now),
+		})
+	}
+	if len(violations) == 0 {
+		return requeueAfter, nil
+	}
+
+	app.Status.SLAViolations = append(app.Status.SLAViolations, violations...)
+	if err := r.updateSparkApplicationStatus(ctx, old, app); err != nil {
+		return 0, fmt.Errorf("failed to record SLA violations: %v", err)
+	}
+	for _, violation := range violations {
+		appLogger(app).Info("SparkApplication violated its SLA", "violation", violation.Type, "message", violation.Message)
+		r.recorder.Eventf(
+			app,
+			corev1.EventTypeWarning,
+			common.EventSparkApplicationSLAViolated,
+			"SparkApplication %s violated its SLA: %s",
+			app.Name,
+			violation.Message,
+		)
+		if r.options.SparkApplicationMetrics != nil {
+			r.options.SparkApplicationMetrics.IncSLAViolationCount(app, violation.Type)
+		}
+	}
+	return requeueAfter, nil
+}
+
+// hasSLAViolation returns whether a violation of the given type is recorded in the status of the SparkApplication.
+func hasSLAViolation(app *v1beta2.SparkApplication, violationType v1beta2.SLAViolationType) bool {
+	for _, violation := range app.Status.SLAViolations {
+		if violation.Type == violationType {
+			return true
+		}
+	}
+	return false
+}
+
+// clearRunSLAViolations clears the violations of the SLA objectives that apply to every run of the SparkApplication.
+func clearRunSLAViolations(app *v1beta2.SparkApplication) {
+	var violations []v1beta2.SLAViolation
+	for _, violation := range app.Status.SLAViolations {
+		if violation.Type == v1beta2.SLAViolationExpectedCompletionBy {
+			violations = append(violations, violation)
+		}
+	}
+	app.Status.SLAViolations = violations
+}
+
+func getSLAViolationMessage(app *v1beta2.SparkApplication, violationType v1beta2.SLAViolationType, deadline time.Time) string {
+	sla := app.Spec.SLA
+	switch violationType {
+	case v1beta2.SLAViolationMaxPendingDuration:
+		return fmt.Sprintf("driver is not running after %s", *sla.MaxPendingDuration)
+	case v1beta2.SLAViolationMaxRunDuration:
+		return fmt.Sprintf("driver is still running after %s", *sla.MaxRunDuration)
+	case v1beta2.SLAViolationExpectedCompletionBy:
+		return fmt.Sprintf("application did not complete by %s", deadline.Format(time.RFC3339))
+	}
+	return string(violationType)
+}
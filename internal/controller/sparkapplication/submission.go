@@ -18,11 +18,16 @@ package sparkapplication
 
 import (
 	"fmt"
+	"maps"
 	"os"
 	"os/exec"
 	"path/filepath"
+	"regexp"
+	"slices"
 	"strings"
 
+	corev1 "k8s.io/api/core/v1"
+
 	"github.com/kubeflow/spark-operator/api/v1beta2"
 	"github.com/kubeflow/spark-operator/pkg/common"
 	"github.com/kubeflow/spark-operator/pkg/util"
@@ -32,24 +37,56 @@ import (
 type submission struct {
 	namespace string
 	name      string
+	sparkHome string
 	args      []string
 }
 
-func newSubmission(args []string, app *v1beta2.SparkApplication) *submission {
+func newSubmission(sparkHome string, args []string, app *v1beta2.SparkApplication) *submission {
 	return &submission{
 		namespace: app.Namespace,
 		name:      app.Name,
+		sparkHome: sparkHome,
 		args:      args,
 	}
 }
 
-func runSparkSubmit(submission *submission) error {
+// getSparkHome returns the Spark distribution running spark-submit for the app, i.e. the one configured for the
+// longest version prefix matching the sparkVersion of the app, falling back to $SPARK_HOME.
+func (r *Reconciler) getSparkHome(app *v1beta2.SparkApplication) (string, error) {
+	if sparkHome := matchSparkHome(r.options.Submission.SparkHomes, app.Spec.SparkVersion); sparkHome != "" {
+		return sparkHome, nil
+	}
 	sparkHome, present := os.LookupEnv(common.EnvSparkHome)
 	if !present {
-		return fmt.Errorf("env %s is not specified", common.EnvSparkHome)
+		return "", fmt.Errorf("env %s is not specified and no Spark distribution is configured for Spark version %q", common.EnvSparkHome, app.Spec.SparkVersion)
 	}
-	command := filepath.Join(sparkHome, "bin", "spark-submit")
+	return sparkHome, nil
+}
+
+// matchSparkHome returns the Spark distribution of the longest version prefix in sparkHomes matching the given Spark
+// version, or an empty string if none matches. Prefixes match whole version components, i.e. `3.5` matches `3.5.3`
+// but not `3.50.0`.
+func matchSparkHome(sparkHomes map[string]string, version string) string {
+	var match string
+	for prefix := range sparkHomes {
+		if version != prefix && !strings.HasPrefix(version, prefix+".") {
+			continue
+		}
+		if len(prefix) > len(match) {
+			match = prefix
+		}
+	}
+	if match == "" {
+		return ""
+	}
+	return sparkHomes[match]
+}
+
+func runSparkSubmit(submission *submission) error {
+	command := filepath.Join(submission.sparkHome, "bin", "spark-submit")
 	cmd := exec.Command(command, submission.args...)
+	// spark-submit reads the default configuration of the distribution in $SPARK_HOME.
+	cmd.Env = append(os.Environ(), fmt.Sprintf("%s=%s", common.EnvSparkHome, submission.sparkHome))
 	_, err := cmd.Output()
 	if err != nil {
 		var errorMsg string
@@ -68,6 +105,59 @@ func runSparkSubmit(submission *submission) error {
 	return nil
 }
 
+// defaultSparkRedactionRegex is the default value of `spark.redaction.regex` in Spark.
+const defaultSparkRedactionRegex = "(?i)secret|password|token|access[.]key"
+
+// redactedValue is the replacement for the values of sensitive Spark configuration properties.
+const redactedValue = "*********(redacted)"
+
+// redactSparkSubmitArgs returns a copy of the spark-submit arguments with sensitive values redacted, following the
+// `spark.redaction.regex` of the application. Like Spark, the value of a configuration property is redacted if either
+// its key or its value matches, e.g. the values of `spark.kubernetes.driverEnv.*` properties holding credentials. The
+// value of --proxy-user is redacted if it matches. An invalid regex of the application falls back to the default one.
+func redactSparkSubmitArgs(app *v1beta2.SparkApplication, args []string) []string {
+	pattern := defaultSparkRedactionRegex
+	if value, ok := app.Spec.SparkConf[common.SparkRedactionRegex]; ok && value != "" {
+		pattern = value
+	}
+	regex, err := regexp.Compile(pattern)
+	if err != nil {
+		appLogger(app).Error(err, "Invalid Spark redaction regex, falling back to the default one", "regex", pattern)
+		regex = regexp.MustCompile(defaultSparkRedactionRegex)
+	}
+
+	redacted := make([]string, len(args))
+	copy(redacted, args)
+	for i := 1; i < len(redacted); i++ {
+		switch redacted[i-1] {
+		case "--conf":
+			key, value, found := strings.Cut(redacted[i], "=")
+			if found && (regex.MatchString(key) || regex.MatchString(value)) {
+				redacted[i] = fmt.Sprintf("%s=%s", key, redactedValue)
+			}
+		case "--proxy-user":
+			if regex.MatchString(redacted[i]) {
+				redacted[i] = redactedValue
+			}
+		}
+	}
+	return redacted
+}
+
+// getSparkConfFromArgs returns the Spark configuration properties passed to spark-submit with --conf.
+func getSparkConfFromArgs(args []string) map[string]string {
+	sparkConf := make(map[string]string)
+	for i := 1; i < len(args); i++ {
+		if args[i-1] != "--conf" {
+			continue
+		}
+		if key, value, found := strings.Cut(args[i], "="); found {
+			sparkConf[key] = value
+		}
+	}
+	return sparkConf
+}
+
 // buildSparkSubmitArgs builds the arguments for spark-submit.
 func buildSparkSubmitArgs(app *v1beta2.SparkApplication) ([]string, error) {
 	optionFuncs := []sparkSubmitOptionFunc{
@@ -82,7 +172,9 @@ func buildSparkSubmitArgs(app *v1beta2.SparkApplication) ([]string, error) {
 		memoryOverheadFactorOption,
 		submissionWaitAppCompletionOption,
 		sparkConfOption,
+		networkPortsOption,
 		hadoopConfOption,
+		kerberosOption,
 		driverPodTemplateOption,
 		driverPodNameOption,
 		driverConfOption,
@@ -91,9 +183,12 @@ func buildSparkSubmitArgs(app *v1beta2.SparkApplication) ([]string, error) {
 		driverVolumeMountsOption,
 		executorPodTemplateOption,
 		executorConfOption,
+		executorLaunchRateLimitOption,
+		executorQuotaFailurePolicyOption,
 		executorEnvOption,
 		executorSecretOption,
 		executorVolumeMountsOption,
+		localDirPolicyOption,
 		nodeSelectorOption,
 		dynamicAllocationOption,
 		proxyUserOption,
@@ -171,7 +266,11 @@ func dependenciesOption(app *v1beta2.SparkApplication) ([]string, error) {
 	var args []string
 
 	if len(app.Spec.Deps.Jars) > 0 {
-		args = append(args, "--jars", strings.Join(app.Spec.Deps.Jars, ","))
+		jars, err := resolveOCIArtifacts(app.Spec.Deps.Jars)
+		if err != nil {
+			return nil, err
+		}
+		args = append(args, "--jars", strings.Join(jars, ","))
 	}
 
 	if len(app.Spec.Deps.Packages) > 0 {
@@ -187,20 +286,45 @@ func dependenciesOption(app *v1beta2.SparkApplication) ([]string, error) {
 	}
 
 	if len(app.Spec.Deps.PyFiles) > 0 {
-		args = append(args, "--py-files", strings.Join(app.Spec.Deps.PyFiles, ","))
+		pyFiles, err := resolveOCIArtifacts(app.Spec.Deps.PyFiles)
+		if err != nil {
+			return nil, err
+		}
+		args = append(args, "--py-files", strings.Join(pyFiles, ","))
 	}
 
 	if len(app.Spec.Deps.Files) > 0 {
-		args = append(args, "--files", strings.Join(app.Spec.Deps.Files, ","))
+		files, err := resolveOCIArtifacts(app.Spec.Deps.Files)
+		if err != nil {
+			return nil, err
+		}
+		args = append(args, "--files", strings.Join(files, ","))
 	}
 
 	if len(app.Spec.Deps.Archives) > 0 {
-		args = append(args, "--archives", strings.Join(app.Spec.Deps.Archives, ","))
+		archives, err := resolveOCIArtifacts(app.Spec.Deps.Archives)
+		if err != nil {
+			return nil, err
+		}
+		args = append(args, "--archives", strings.Join(archives, ","))
 	}
 
 	return args, nil
 }
 
+// resolveOCIArtifacts rewrites the files referenced as OCI artifacts to the local paths they are pulled to.
+func resolveOCIArtifacts(files []string) ([]string, error) {
+	resolved := make([]string, 0, len(files))
+	for _, file := range files {
+		path, err := util.ResolveOCIArtifact(file)
+		if err != nil {
+			return nil, err
+		}
+		resolved = append(resolved, path)
+	}
+	return resolved, nil
+}
+
 func imageOption(app *v1beta2.SparkApplication) ([]string, error) {
 	var args []string
 	if app.Spec.Image != nil && *app.Spec.Image != "" {
@@ -274,6 +398,16 @@ func sparkConfOption(app *v1beta2.SparkApplication) ([]string, error) {
 	return args, nil
 }
 
+// networkPortsOption sets the Spark configuration properties of the network ports set in the spec, which Spark
+// also uses for the ports of the driver Service.
+func networkPortsOption(app *v1beta2.SparkApplication) ([]string, error) {
+	var args []string
+	for key, port := range util.GetNetworkPortConf(app) {
+		args = append(args, "--conf", fmt.Sprintf("%s=%d", key, port))
+	}
+	return args, nil
+}
+
 func hadoopConfOption(app *v1beta2.SparkApplication) ([]string, error) {
 	if app.Spec.HadoopConf == nil {
 		return nil, nil
@@ -281,7 +415,29 @@ func hadoopConfOption(app *v1beta2.SparkApplication) ([]string, error) {
 	var args []string
 	// Add Hadoop configuration properties.
 	for key, value := range app.Spec.HadoopConf {
-		args = append(args, "--conf", fmt.Sprintf("spark.hadoop.%s=%s", key, value))
+		args = append(args, "--conf", fmt.Sprintf("%s%s=%s", common.SparkHadoopPrefix, key, value))
+	}
+	return args, nil
+}
+
+func kerberosOption(app *v1beta2.SparkApplication) ([]string, error) {
+	if app.Spec.Kerberos == nil || app.Spec.Kerberos.RenewalInterval == nil {
+		return nil, nil
+	}
+	// The ticket cache is renewed by the sidecar container, so let the driver renew credentials from it.
+	return []string{"--conf", fmt.Sprintf("%s=ccache", common.SparkKerberosRenewalCredentials)}, nil
+}
+
+// localDirPolicyOption expands the local dir policy of the application into local dir volumes.
+func localDirPolicyOption(app *v1beta2.SparkApplication) ([]string, error) {
+	conf, err := util.GetLocalDirPolicyConf(app)
+	if err != nil {
+		return nil, fmt.Errorf("failed to expand local dir policy: %v", err)
+	}
+
+	var args []string
+	for _, key := range slices.Sorted(maps.Keys(conf)) {
+		args = append(args, "--conf", fmt.Sprintf("%s=%s", key, conf[key]))
 	}
 	return args, nil
 }
@@ -385,6 +541,12 @@ func driverConfOption(app *v1beta2.SparkApplication) ([]string, error) {
 		args = append(args, "--conf", fmt.Sprintf("%s=%s", property, value))
 	}
 
+	// Label the driver headless service so that the controller can find it to publish not-ready addresses.
+	if util.DriverServicePublishNotReadyAddresses(app) {
+		property = fmt.Sprintf(common.SparkKubernetesDriverServiceLabelTemplate, common.LabelSparkAppName)
+		args = append(args, "--conf", fmt.Sprintf("%s=%s", property, app.Name))
+	}
+
 	for key, value := range app.Spec.Driver.ServiceAnnotations {
 		property = fmt.Sprintf(common.SparkKubernetesDriverServiceAnnotationTemplate, key)
 		args = append(args, "--conf", fmt.Sprintf("%s=%s", property, value))
@@ -970,6 +1132,33 @@ func executorVolumeMountsOption(app *v1beta2.SparkApplication) ([]string, error)
 	return args, nil
 }
 
+// executorLaunchRateLimitOption paces the creation of executor pods according to the executor launch rate limit.
+func executorLaunchRateLimitOption(app *v1beta2.SparkApplication) ([]string, error) {
+	conf, err := util.GetExecutorLaunchRateLimitConf(app)
+	if err != nil {
+		return nil, err
+	}
+	var args []string
+	for _, key := range slices.Sorted(maps.Keys(conf)) {
+		args = append(args, "--conf", fmt.Sprintf("%s=%s", key, conf[key]))
+	}
+	return args, nil
+}
+
+// executorQuotaFailurePolicyOption makes the driver schedule tasks right away or wait for all requested executors
+// according to the executor quota failure policy.
+func executorQuotaFailurePolicyOption(app *v1beta2.SparkApplication) ([]string, error) {
+	conf, err := util.GetExecutorQuotaFailurePolicyConf(app)
+	if err != nil {
+		return nil, err
+	}
+	var args []string
+	for _, key := range slices.Sorted(maps.Keys(conf)) {
+		args = append(args, "--conf", fmt.Sprintf("%s=%s", key, conf[key]))
+	}
+	return args, nil
+}
+
 func executorEnvOption(app *v1beta2.SparkApplication) ([]string, error) {
 	var args []string
 	for key, value := range app.Spec.Executor.EnvVars {
@@ -1010,6 +1199,14 @@ func dynamicAllocationOption(app *v1beta2.SparkApplication) ([]string, error) {
 			fmt.Sprintf("%s=%d", common.SparkDynamicAllocationShuffleTrackingTimeout, *dynamicAllocation.ShuffleTrackingTimeout))
 	}
 
+	timeoutConf, err := util.GetDynamicAllocationTimeoutConf(dynamicAllocation)
+	if err != nil {
+		return nil, err
+	}
+	for _, key := range slices.Sorted(maps.Keys(timeoutConf)) {
+		args = append(args, "--conf", fmt.Sprintf("%s=%s", key, timeoutConf[key]))
+	}
+
 	return args, nil
 }
 
@@ -1025,10 +1222,14 @@ func proxyUserOption(app *v1beta2.SparkApplication) ([]string, error) {
 }
 
 func mainApplicationFileOption(app *v1beta2.SparkApplication) ([]string, error) {
-	if app.Spec.MainApplicationFile == nil {
+	mainApplicationFile, err := util.GetMainApplicationFile(app)
+	if err != nil {
+		return nil, err
+	}
+	if mainApplicationFile == "" {
 		return nil, nil
 	}
-	args := []string{*app.Spec.MainApplicationFile}
+	args := []string{mainApplicationFile}
 	return args, nil
 }
 
@@ -1044,10 +1245,14 @@ func driverPodTemplateOption(app *v1beta2.SparkApplication) ([]string, error) {
 	}
 
 	podTemplateFile := fmt.Sprintf("/tmp/spark/%s/driver-pod-template.yaml", app.Status.SubmissionID)
-	if err := util.WriteObjectToFile(app.Spec.Driver.Template, podTemplateFile); err != nil {
+	template, err := buildPodTemplate(&app.Spec.Driver.SparkPodSpec, common.SparkDriverContainerName)
+	if err != nil {
+		return []string{}, err
+	}
+	if err := util.WriteObjectToFile(template, podTemplateFile); err != nil {
 		return []string{}, err
 	}
-	logger.V(1).Info("Created driver pod template file for SparkApplication", "name", app.Name, "namespace", app.Namespace, "file", podTemplateFile)
+	appLogger(app).V(1).Info("Created driver pod template file for SparkApplication", "file", podTemplateFile)
 
 	args := []string{
 		"--conf",
@@ -1065,10 +1270,14 @@ func executorPodTemplateOption(app *v1beta2.SparkApplication) ([]string, error)
 	}
 
 	podTemplateFile := fmt.Sprintf("/tmp/spark/%s/executor-pod-template.yaml", app.Status.SubmissionID)
-	if err := util.WriteObjectToFile(app.Spec.Executor.Template, podTemplateFile); err != nil {
+	template, err := buildPodTemplate(&app.Spec.Executor.SparkPodSpec, common.Spark3DefaultExecutorContainerName)
+	if err != nil {
 		return []string{}, err
 	}
-	logger.V(1).Info("Created executor pod template file for SparkApplication", "name", app.Name, "namespace", app.Namespace, "file", podTemplateFile)
+	if err := util.WriteObjectToFile(template, podTemplateFile); err != nil {
+		return []string{}, err
+	}
+	appLogger(app).V(1).Info("Created executor pod template file for SparkApplication", "file", podTemplateFile)
 
 	args := []string{
 		"--conf",
@@ -1078,3 +1287,35 @@ func executorPodTemplateOption(app *v1beta2.SparkApplication) ([]string, error)
 	}
 	return args, nil
 }
+
+// buildPodTemplate returns a copy of the pod template of the given driver or executor spec with the typed fields
+// applied that the webhook would otherwise apply, so that they take effect when the operator runs without the
+// webhook. Pods created from the template are still mutated by the webhook if it is enabled, which skips the init
+// containers already present.
+func buildPodTemplate(podSpec *v1beta2.SparkPodSpec, containerName string) (*corev1.PodTemplateSpec, error) {
+	template := podSpec.Template.DeepCopy()
+
+	for _, container := range slices.Concat(podSpec.InitContainers, util.GetNativeSidecars(podSpec)) {
+		if !slices.ContainsFunc(template.Spec.InitContainers, func(c corev1.Container) bool { return c.Name == container.Name }) {
+			template.Spec.InitContainers = append(template.Spec.InitContainers, *container.DeepCopy())
+		}
+	}
+
+	requests, limits, err := util.GetEphemeralStorageResources(podSpec)
+	if err != nil {
+		return nil, err
+	}
+	if len(requests) > 0 || len(limits) > 0 {
+		// Spark uses the first container if no container has the configured name.
+		i := slices.IndexFunc(template.Spec.Containers, func(c corev1.Container) bool { return c.Name == containerName })
+		if i < 0 && len(template.Spec.Containers) > 0 {
+			i = 0
+		}
+		if i < 0 {
+			template.Spec.Containers = append(template.Spec.Containers, corev1.Container{Name: containerName})
+			i = 0
+		}
+		util.SetContainerResources(&template.Spec.Containers[i], requests, limits)
+	}
+	return template, nil
+}
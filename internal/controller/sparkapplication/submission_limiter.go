@@ -0,0 +1,275 @@
+/*
+Copyright 2024 The Kubeflow authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package sparkapplication
+
+import (
+	"context"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"k8s.io/apimachinery/pkg/types"
+
+	"github.com/kubeflow/spark-operator/internal/metrics"
+	"github.com/kubeflow/spark-operator/pkg/statestore"
+)
+
+const (
+	// submissionThrottleInterval is how often submissions throttled by the per-namespace limit are retried.
+	submissionThrottleInterval = 5 * time.Second
+
+	// queuedSubmissionTimeout is the period after which a queued submission that was not retried, e.g. because its
+	// application was deleted while the operator was down, no longer holds its position in the queue.
+	queuedSubmissionTimeout = 12 * submissionThrottleInterval
+
+	// Prefixes of the keys of the records of queued and in-flight submissions in the state store.
+	queuedSubmissionKeyPrefix   = "queued_"
+	inFlightSubmissionKeyPrefix = "inflight_"
+)
+
+// queuedSubmission is a submission waiting for a free slot of its namespace.
+type queuedSubmission struct {
+	// queueTime is when the submission was first throttled, which determines its position in the queue.
+	queueTime time.Time
+	// lastSeen is when the submission was last throttled.
+	lastSeen time.Time
+}
+
+// submissionLimiter bounds the number of spark-submit processes running concurrently in every namespace, so that the
+// SparkApplications of one namespace cannot monopolize the submission throughput of the controller. Free slots are
+// granted to queued submissions in the order they were first throttled. The limiter also keeps track of the
+// submissions in flight and queued, which are reported as metrics and, if a state store is configured, persisted so
+// that queue positions survive restarts of the operator.
+type submissionLimiter struct {
+	limit   int
+	metrics *metrics.SparkSubmissionMetrics
+	store   statestore.Store
+
+	restoreOnce sync.Once
+
+	mu      sync.Mutex
+	running map[string]int
+	queued  map[string]map[types.NamespacedName]*queuedSubmission
+}
+
+// newSubmissionLimiter creates a new submissionLimiter allowing the given number of concurrent submissions per
+// namespace. Submissions are not limited if the limit is not positive. Metrics are not reported and the bookkeeping
+// is not persisted if metrics and store are nil, respectively.
+func newSubmissionLimiter(limit int, metrics *metrics.SparkSubmissionMetrics, store statestore.Store) *submissionLimiter {
+	return &submissionLimiter{
+		limit:   limit,
+		metrics: metrics,
+		store:   store,
+		running: make(map[string]int),
+		queued:  make(map[string]map[types.NamespacedName]*queuedSubmission),
+	}
+}
+
+// tryAcquire takes a submission slot of the namespace of the given application without blocking, returning false and
+// queueing the application if the free slots of the namespace are taken or granted to applications queued earlier.
+// A slot taken must be given back with release.
+func (l *submissionLimiter) tryAcquire(ctx context.Context, key types.NamespacedName) bool {
+	l.restoreOnce.Do(func() { l.restore(ctx) })
+
+	now := time.Now()
+	l.mu.Lock()
+	acquired := l.limit <= 0
+	var expired []types.NamespacedName
+	if !acquired {
+		acquired, expired = l.hasSlotLocked(key, now)
+	}
+	if acquired {
+		delete(l.queued[key.Namespace], key)
+		l.running[key.Namespace]++
+	} else if queued := l.queued[key.Namespace][key]; queued != nil {
+		queued.lastSeen = now
+	} else {
+		if l.queued[key.Namespace] == nil {
+			l.queued[key.Namespace] = make(map[types.NamespacedName]*queuedSubmission)
+		}
+		l.queued[key.Namespace][key] = &queuedSubmission{queueTime: now, lastSeen: now}
+	}
+	l.reportLocked(key.Namespace)
+	submission := l.queued[key.Namespace][key]
+	l.mu.Unlock()
+
+	for _, other := range expired {
+		l.deleteRecord(ctx, queuedSubmissionKeyPrefix, other)
+	}
+	if acquired {
+		l.deleteRecord(ctx, queuedSubmissionKeyPrefix, key)
+		l.putRecord(ctx, inFlightSubmissionKeyPrefix, key, now)
+	} else if submission.queueTime.Equal(now) {
+		l.putRecord(ctx, queuedSubmissionKeyPrefix, key, now)
+	}
+	return acquired
+}
+
+// release gives back a submission slot of the namespace of the given application taken with tryAcquire.
+func (l *submissionLimiter) release(ctx context.Context, key types.NamespacedName) {
+	l.mu.Lock()
+	l.running[key.Namespace]--
+	l.reportLocked(key.Namespace)
+	l.mu.Unlock()
+
+	l.deleteRecord(ctx, inFlightSubmissionKeyPrefix, key)
+}
+
+// forget removes the given application from the queue, e.g. once it is deleted.
+func (l *submissionLimiter) forget(ctx context.Context, key types.NamespacedName) {
+	l.mu.Lock()
+	_, queued := l.queued[key.Namespace][key]
+	if queued {
+		delete(l.queued[key.Namespace], key)
+		l.reportLocked(key.Namespace)
+	}
+	l.mu.Unlock()
+
+	if queued {
+		l.deleteRecord(ctx, queuedSubmissionKeyPrefix, key)
+	}
+}
+
+// hasSlotLocked returns whether a free slot of the namespace of the given application can be granted to it, i.e.
+// whether there are fewer applications queued before it than free slots. Queued submissions that timed out are
+// dropped and returned, so that their records can be removed from the state store. Must be called with mu held.
+func (l *submissionLimiter) hasSlotLocked(key types.NamespacedName, now time.Time) (bool, []types.NamespacedName) {
+	free := l.limit - l.running[key.Namespace]
+	if free <= 0 {
+		return false, nil
+	}
+
+	queued := l.queued[key.Namespace]
+	var queueTime time.Time
+	if submission := queued[key]; submission != nil {
+		queueTime = submission.queueTime
+	}
+	ahead := 0
+	var expired []types.NamespacedName
+	for other, submission := range queued {
+		if other == key {
+			continue
+		}
+		if now.Sub(submission.lastSeen) > queuedSubmissionTimeout {
+			delete(queued, other)
+			expired = append(expired, other)
+			continue
+		}
+		if queueTime.IsZero() || submission.queueTime.Before(queueTime) {
+			ahead++
+		}
+	}
+	return ahead < free, expired
+}
+
+// reportLocked reports the submissions in flight and queued in the given namespace and drops the bookkeeping of the
+// namespace once it is idle. Must be called with mu held.
+func (l *submissionLimiter) reportLocked(namespace string) {
+	running, queued := l.running[namespace], len(l.queued[namespace])
+	if l.metrics != nil {
+		l.metrics.SetInFlightCount(namespace, running)
+		l.metrics.SetQueuedCount(namespace, queued)
+	}
+	if running <= 0 {
+		delete(l.running, namespace)
+	}
+	if queued == 0 {
+		delete(l.queued, namespace)
+	}
+}
+
+// restore loads the queued submissions from the state store, so that they keep their positions in the queue. Records
+// of submissions that were in flight are dropped as their spark-submit processes ended with the previous operator.
+func (l *submissionLimiter) restore(ctx context.Context) {
+	if l.store == nil {
+		return
+	}
+
+	records, err := l.store.List(ctx)
+	if err != nil {
+		logger.Error(err, "Failed to restore submission bookkeeping from state store")
+		return
+	}
+
+	now := time.Now()
+	l.mu.Lock()
+	var interrupted []types.NamespacedName
+	for recordKey, value := range records {
+		if key, ok := parseSubmissionRecordKey(recordKey, inFlightSubmissionKeyPrefix); ok {
+			interrupted = append(interrupted, key)
+			continue
+		}
+		key, ok := parseSubmissionRecordKey(recordKey, queuedSubmissionKeyPrefix)
+		if !ok {
+			continue
+		}
+		queueTime, err := time.Parse(time.RFC3339Nano, value)
+		if err != nil {
+			logger.Error(err, "Ignoring invalid queued submission record", "key", recordKey)
+			continue
+		}
+		if l.queued[key.Namespace] == nil {
+			l.queued[key.Namespace] = make(map[types.NamespacedName]*queuedSubmission)
+		}
+		l.queued[key.Namespace][key] = &queuedSubmission{queueTime: queueTime, lastSeen: now}
+		l.reportLocked(key.Namespace)
+	}
+	l.mu.Unlock()
+
+	sort.Slice(interrupted, func(i, j int) bool { return interrupted[i].String() < interrupted[j].String() })
+	for _, key := range interrupted {
+		logger.Info("Submission was interrupted by a restart of the operator", "name", key.Name, "namespace", key.Namespace)
+		l.deleteRecord(ctx, inFlightSubmissionKeyPrefix, key)
+	}
+}
+
+func (l *submissionLimiter) putRecord(ctx context.Context, prefix string, key types.NamespacedName, t time.Time) {
+	if l.store == nil {
+		return
+	}
+	if err := l.store.Put(ctx, submissionRecordKey(prefix, key), t.UTC().Format(time.RFC3339Nano)); err != nil {
+		logger.Error(err, "Failed to persist submission bookkeeping", "name", key.Name, "namespace", key.Namespace)
+	}
+}
+
+func (l *submissionLimiter) deleteRecord(ctx context.Context, prefix string, key types.NamespacedName) {
+	if l.store == nil {
+		return
+	}
+	if err := l.store.Delete(ctx, submissionRecordKey(prefix, key)); err != nil {
+		logger.Error(err, "Failed to persist submission bookkeeping", "name", key.Name, "namespace", key.Namespace)
+	}
+}
+
+// submissionRecordKey returns the key of the record of a submission in the state store. Kubernetes names cannot
+// contain `_`, which therefore separates the namespace from the name.
+func submissionRecordKey(prefix string, key types.NamespacedName) string {
+	return prefix + key.Namespace + "_" + key.Name
+}
+
+// parseSubmissionRecordKey parses a key returned by submissionRecordKey with the given prefix.
+func parseSubmissionRecordKey(recordKey string, prefix string) (types.NamespacedName, bool) {
+	if !strings.HasPrefix(recordKey, prefix) {
+		return types.NamespacedName{}, false
+	}
+	namespace, name, found := strings.Cut(strings.TrimPrefix(recordKey, prefix), "_")
+	if !found || namespace == "" || name == "" {
+		return types.NamespacedName{}, false
+	}
+	return types.NamespacedName{Namespace: namespace, Name: name}, true
+}
@@ -0,0 +1,129 @@
+/*
+Copyright 2024 The Kubeflow authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package sparkapplication
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gocloud.dev/blob/memblob"
+	"k8s.io/apimachinery/pkg/types"
+
+	"github.com/kubeflow/spark-operator/pkg/statestore"
+)
+
+func newTestSubmissionStore(t *testing.T) statestore.Store {
+	store := statestore.NewBucketStore(memblob.OpenBucket(nil))
+	t.Cleanup(func() { store.Close() })
+	return store
+}
+
+func listSubmissionRecords(t *testing.T, store statestore.Store) []string {
+	records, err := store.List(context.Background())
+	require.NoError(t, err)
+	keys := make([]string, 0, len(records))
+	for key := range records {
+		keys = append(keys, key)
+	}
+	return keys
+}
+
+func TestSubmissionLimiterGrantsSlotsInQueueOrder(t *testing.T) {
+	ctx := context.Background()
+	first := types.NamespacedName{Namespace: "default", Name: "first"}
+	second := types.NamespacedName{Namespace: "default", Name: "second"}
+	third := types.NamespacedName{Namespace: "default", Name: "third"}
+	other := types.NamespacedName{Namespace: "other", Name: "other"}
+	limiter := newSubmissionLimiter(1, nil, nil)
+
+	require.True(t, limiter.tryAcquire(ctx, first))
+	assert.False(t, limiter.tryAcquire(ctx, second))
+	assert.False(t, limiter.tryAcquire(ctx, third))
+	// Namespaces are limited independently.
+	assert.True(t, limiter.tryAcquire(ctx, other))
+
+	limiter.release(ctx, first)
+	// The free slot is granted to the submission queued first.
+	assert.False(t, limiter.tryAcquire(ctx, third))
+	assert.True(t, limiter.tryAcquire(ctx, second))
+
+	limiter.release(ctx, second)
+	assert.True(t, limiter.tryAcquire(ctx, third))
+}
+
+func TestSubmissionLimiterDropsTimedOutSubmissions(t *testing.T) {
+	ctx := context.Background()
+	running := types.NamespacedName{Namespace: "default", Name: "running"}
+	abandoned := types.NamespacedName{Namespace: "default", Name: "abandoned"}
+	waiting := types.NamespacedName{Namespace: "default", Name: "waiting"}
+	store := newTestSubmissionStore(t)
+	limiter := newSubmissionLimiter(1, nil, store)
+
+	require.True(t, limiter.tryAcquire(ctx, running))
+	require.False(t, limiter.tryAcquire(ctx, abandoned))
+	require.False(t, limiter.tryAcquire(ctx, waiting))
+	assert.ElementsMatch(t, []string{
+		"inflight_default_running",
+		"queued_default_abandoned",
+		"queued_default_waiting",
+	}, listSubmissionRecords(t, store))
+
+	// The abandoned submission is not retried anymore.
+	limiter.mu.Lock()
+	limiter.queued[abandoned.Namespace][abandoned].lastSeen = time.Now().Add(-2 * queuedSubmissionTimeout)
+	limiter.mu.Unlock()
+
+	limiter.release(ctx, running)
+	assert.True(t, limiter.tryAcquire(ctx, waiting))
+	assert.ElementsMatch(t, []string{"inflight_default_waiting"}, listSubmissionRecords(t, store))
+}
+
+func TestSubmissionLimiterRestoresQueue(t *testing.T) {
+	ctx := context.Background()
+	first := types.NamespacedName{Namespace: "default", Name: "first"}
+	second := types.NamespacedName{Namespace: "default", Name: "second"}
+	interrupted := types.NamespacedName{Namespace: "default", Name: "interrupted"}
+	store := newTestSubmissionStore(t)
+	now := time.Now()
+	require.NoError(t, store.Put(ctx, submissionRecordKey(queuedSubmissionKeyPrefix, second), now.Format(time.RFC3339Nano)))
+	require.NoError(t, store.Put(ctx, submissionRecordKey(queuedSubmissionKeyPrefix, first), now.Add(-time.Minute).Format(time.RFC3339Nano)))
+	require.NoError(t, store.Put(ctx, submissionRecordKey(inFlightSubmissionKeyPrefix, interrupted), now.Format(time.RFC3339Nano)))
+	limiter := newSubmissionLimiter(1, nil, store)
+
+	// The interrupted submission does not hold a slot and the restored submissions keep their queue order.
+	assert.False(t, limiter.tryAcquire(ctx, second))
+	assert.True(t, limiter.tryAcquire(ctx, first))
+	assert.ElementsMatch(t, []string{
+		"inflight_default_first",
+		"queued_default_second",
+	}, listSubmissionRecords(t, store))
+}
+
+func TestParseSubmissionRecordKey(t *testing.T) {
+	key := types.NamespacedName{Namespace: "default", Name: "spark-pi"}
+	parsed, ok := parseSubmissionRecordKey(submissionRecordKey(queuedSubmissionKeyPrefix, key), queuedSubmissionKeyPrefix)
+	assert.True(t, ok)
+	assert.Equal(t, key, parsed)
+
+	_, ok = parseSubmissionRecordKey(submissionRecordKey(queuedSubmissionKeyPrefix, key), inFlightSubmissionKeyPrefix)
+	assert.False(t, ok)
+	_, ok = parseSubmissionRecordKey("queued_default", queuedSubmissionKeyPrefix)
+	assert.False(t, ok)
+}
@@ -16,6 +16,19 @@ limitations under the License.
 
 package sparkapplication
 
+import (
+	"os"
+	"slices"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+
+	"github.com/kubeflow/spark-operator/api/v1beta2"
+	"github.com/kubeflow/spark-operator/pkg/common"
+)
+
 // import (
 // 	"fmt"
 // 	"os"
@@ -694,3 +707,186 @@ package sparkapplication
 // 		})
 // 	}
 // }
+
+func TestMatchSparkHome(t *testing.T) {
+	sparkHomes := map[string]string{
+		"3":     "/opt/spark-3",
+		"3.5":   "/opt/spark-3.5",
+		"3.5.1": "/opt/spark-3.5.1",
+		"4.0":   "/opt/spark-4.0",
+	}
+
+	testCases := []struct {
+		version  string
+		expected string
+	}{
+		{version: "3.5.1", expected: "/opt/spark-3.5.1"},
+		{version: "3.5.3", expected: "/opt/spark-3.5"},
+		{version: "3.5", expected: "/opt/spark-3.5"},
+		{version: "3.4.0", expected: "/opt/spark-3"},
+		{version: "3.50.0", expected: "/opt/spark-3"},
+		{version: "4.0.0", expected: "/opt/spark-4.0"},
+		{version: "4.1.0", expected: ""},
+		{version: "", expected: ""},
+	}
+	for _, tc := range testCases {
+		assert.Equal(t, tc.expected, matchSparkHome(sparkHomes, tc.version), tc.version)
+	}
+	assert.Empty(t, matchSparkHome(nil, "3.5.3"))
+}
+
+func TestGetSparkHome(t *testing.T) {
+	app := &v1beta2.SparkApplication{}
+	r := NewReconciler(nil, nil, nil, nil, nil, Options{Submission: SubmissionOptions{SparkHomes: map[string]string{"4.0": "/opt/spark-4.0"}}})
+
+	t.Setenv(common.EnvSparkHome, "/opt/spark")
+	sparkHome, err := r.getSparkHome(app)
+	require.NoError(t, err)
+	assert.Equal(t, "/opt/spark", sparkHome)
+
+	app.Spec.SparkVersion = "4.0.1"
+	sparkHome, err = r.getSparkHome(app)
+	require.NoError(t, err)
+	assert.Equal(t, "/opt/spark-4.0", sparkHome)
+
+	// $SPARK_HOME is only required for versions without a configured distribution.
+	require.NoError(t, os.Unsetenv(common.EnvSparkHome))
+	_, err = r.getSparkHome(app)
+	assert.NoError(t, err)
+	app.Spec.SparkVersion = "3.5.3"
+	_, err = r.getSparkHome(app)
+	assert.ErrorContains(t, err, `no Spark distribution is configured for Spark version "3.5.3"`)
+}
+
+func TestRedactSparkSubmitArgs(t *testing.T) {
+	testCases := []struct {
+		name      string
+		sparkConf map[string]string
+		args      []string
+		expected  []string
+	}{
+		{
+			name: "sensitive keys with the default regex",
+			args: []string{
+				"--master", "k8s://https://10.0.0.1:443",
+				"--conf", "spark.hadoop.fs.s3a.access.key=AKIA",
+				"--conf", "spark.kubernetes.driver.secretKeyRef.DB_PASSWORD=db:credentials",
+				"--conf", "spark.executor.memory=4g",
+			},
+			expected: []string{
+				"--master", "k8s://https://10.0.0.1:443",
+				"--conf", "spark.hadoop.fs.s3a.access.key=" + redactedValue,
+				"--conf", "spark.kubernetes.driver.secretKeyRef.DB_PASSWORD=" + redactedValue,
+				"--conf", "spark.executor.memory=4g",
+			},
+		},
+		{
+			name: "sensitive values with the default regex",
+			args: []string{
+				"--conf", "spark.kubernetes.driverEnv.DB_URL=jdbc:postgresql://db/app?user=app&password=hunter2",
+				"--conf", "spark.kubernetes.driverEnv.API_HEADER=Bearer token-1234",
+				"--conf", "spark.kubernetes.driverEnv.LOG_LEVEL=INFO",
+				"--conf", "spark.executorEnv.AWS_SECRET=abc",
+			},
+			expected: []string{
+				"--conf", "spark.kubernetes.driverEnv.DB_URL=" + redactedValue,
+				"--conf", "spark.kubernetes.driverEnv.API_HEADER=" + redactedValue,
+				"--conf", "spark.kubernetes.driverEnv.LOG_LEVEL=INFO",
+				"--conf", "spark.executorEnv.AWS_SECRET=" + redactedValue,
+			},
+		},
+		{
+			name: "proxy user",
+			args: []string{
+				"--proxy-user", "svc-token-reader",
+				"--conf", "spark.app.name=test",
+			},
+			expected: []string{
+				"--proxy-user", redactedValue,
+				"--conf", "spark.app.name=test",
+			},
+		},
+		{
+			name: "proxy user not matching",
+			args: []string{
+				"--proxy-user", "alice",
+			},
+			expected: []string{
+				"--proxy-user", "alice",
+			},
+		},
+		{
+			name: "positional arguments are left untouched",
+			args: []string{
+				"--conf", "spark.executor.memory=4g",
+				"local:///opt/spark/examples/jars/spark-examples.jar", "password=positional",
+			},
+			expected: []string{
+				"--conf", "spark.executor.memory=4g",
+				"local:///opt/spark/examples/jars/spark-examples.jar", "password=positional",
+			},
+		},
+		{
+			name:      "regex of the application",
+			sparkConf: map[string]string{common.SparkRedactionRegex: "custom"},
+			args: []string{
+				"--conf", "spark.hadoop.fs.s3a.access.key=AKIA",
+				"--conf", "spark.custom.value=sensitive",
+				"--conf", "spark.kubernetes.driverEnv.MODE=custom",
+			},
+			expected: []string{
+				"--conf", "spark.hadoop.fs.s3a.access.key=AKIA",
+				"--conf", "spark.custom.value=" + redactedValue,
+				"--conf", "spark.kubernetes.driverEnv.MODE=" + redactedValue,
+			},
+		},
+		{
+			name:      "invalid regex of the application",
+			sparkConf: map[string]string{common.SparkRedactionRegex: "("},
+			args: []string{
+				"--conf", "spark.hadoop.fs.s3a.access.key=AKIA",
+				"--conf", "spark.custom.value=sensitive",
+			},
+			expected: []string{
+				"--conf", "spark.hadoop.fs.s3a.access.key=" + redactedValue,
+				"--conf", "spark.custom.value=sensitive",
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			app := &v1beta2.SparkApplication{}
+			app.Spec.SparkConf = tc.sparkConf
+			args := slices.Clone(tc.args)
+
+			assert.Equal(t, tc.expected, redactSparkSubmitArgs(app, args))
+			// The arguments passed to spark-submit are left untouched.
+			assert.Equal(t, tc.args, args)
+		})
+	}
+}
+
+func TestBuildPodTemplate(t *testing.T) {
+	podSpec := &v1beta2.SparkPodSpec{
+		InitContainers: []corev1.Container{
+			{Name: "setup", Image: "setup:latest"},
+			{Name: "prefetch", Image: "prefetch:latest"},
+		},
+		Template: &corev1.PodTemplateSpec{
+			Spec: corev1.PodSpec{
+				InitContainers: []corev1.Container{{Name: "setup", Image: "setup:template"}},
+				Containers:     []corev1.Container{{Name: common.Spark3DefaultExecutorContainerName}},
+			},
+		},
+	}
+
+	template, err := buildPodTemplate(podSpec, common.Spark3DefaultExecutorContainerName)
+	require.NoError(t, err)
+	// Every init container is added exactly once, and those of the template take precedence.
+	require.Len(t, template.Spec.InitContainers, 2)
+	assert.Equal(t, corev1.Container{Name: "setup", Image: "setup:template"}, template.Spec.InitContainers[0])
+	assert.Equal(t, corev1.Container{Name: "prefetch", Image: "prefetch:latest"}, template.Spec.InitContainers[1])
+	// The template of the spec is left untouched.
+	assert.Len(t, podSpec.Template.Spec.InitContainers, 1)
+}
@@ -0,0 +1,305 @@
+/*
+Copyright 2024 The Kubeflow authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package sparkapplication
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/kubeflow/spark-operator/api/v1beta2"
+	"github.com/kubeflow/spark-operator/internal/chaos"
+	"github.com/kubeflow/spark-operator/pkg/archive"
+	"github.com/kubeflow/spark-operator/pkg/common"
+	"github.com/kubeflow/spark-operator/pkg/util"
+)
+
+// SubmissionOptions configures how SparkApplications are submitted.
+type SubmissionOptions struct {
+	// DefaultEngine is the engine submitting SparkApplications not specifying one.
+	DefaultEngine v1beta2.SubmissionEngine
+
+	// EnableSubmitterJob enables the Job submission engine, which requires Jobs to be cached and watched.
+	// SparkApplications using the Job submission engine fail to be submitted if disabled.
+	EnableSubmitterJob bool
+
+	// SubmitterJobImage is the image of the Jobs running spark-submit for the Job submission engine.
+	// Defaults to the image of the SparkApplication if empty.
+	SubmitterJobImage string
+
+	// SubmitterJobTimeout is the maximum time a Job running spark-submit may take to complete.
+	SubmitterJobTimeout time.Duration
+
+	// SparkHomes maps Spark version prefixes, e.g. `3.5`, to the Spark distributions in the operator image running
+	// spark-submit for applications of matching sparkVersion. Applications matching none use $SPARK_HOME.
+	SparkHomes map[string]string
+
+	// MaxConcurrentPerNamespace is the maximum number of spark-submit processes running concurrently for the
+	// SparkApplications of a namespace. Further submissions are retried later. Unlimited if set to 0.
+	MaxConcurrentPerNamespace int
+
+	// ImpersonateServiceAccount is the name of the service account in the namespace of each SparkApplication the
+	// operator impersonates when creating driver resources. Impersonation is disabled if empty.
+	ImpersonateServiceAccount string
+
+	// EnableDriverPodValidation enables creating the driver pod with a server-side dry run before submission,
+	// so that admission denials fail the application without retries.
+	EnableDriverPodValidation bool
+}
+
+// submitSparkApplication creates a new submission for the given SparkApplication and submits it using spark-submit.
+func (r *Reconciler) submitSparkApplication(ctx context.Context, app *v1beta2.SparkApplication) (submitErr error) {
+	// SubmissionID must be set before creating any resources to ensure all the resources are labeled.
+	app.Status.SubmissionID = uuid.New().String()
+	appLogger(app).Info("Submitting SparkApplication", "state", app.Status.AppState.State)
+	app.Status.DriverInfo.PodName = util.GetDriverPodName(app)
+	app.Status.LastSubmissionAttemptTime = metav1.Now()
+	app.Status.SubmissionAttempts = app.Status.SubmissionAttempts + 1
+	app.Status.SecretRotation = nil
+	app.Status.PostRunActions = nil
+	app.Status.Outputs = nil
+	app.Status.InjectedDefaults = nil
+
+	defer func() {
+		if submitErr == nil {
+			app.Status.AppState = v1beta2.ApplicationState{
+				State: v1beta2.ApplicationStateSubmitted,
+			}
+			app.Status.ExecutionAttempts = app.Status.ExecutionAttempts + 1
+		} else if isDriverPodRejectedError(submitErr) {
+			appLogger(app).Info("Driver pod of SparkApplication rejected by admission", "error", submitErr)
+			app.Status.AppState = v1beta2.ApplicationState{
+				State:        v1beta2.ApplicationStateFailed,
+				ErrorMessage: submitErr.Error(),
+			}
+			app.Status.TerminationTime = metav1.Now()
+		} else {
+			appLogger(app).Info("Failed to submit SparkApplication", "state", app.Status.AppState.State, "error", submitErr)
+			app.Status.AppState = v1beta2.ApplicationState{
+				State:        v1beta2.ApplicationStateFailedSubmission,
+				ErrorMessage: submitErr.Error(),
+			}
+		}
+		r.recordSparkApplicationEvent(app)
+	}()
+
+	if err := chaos.DelaySubmission(ctx); err != nil {
+		return err
+	}
+
+	if err := r.runPreSubmissionHooks(ctx, app); err != nil {
+		return fmt.Errorf("failed to run pre-submission hooks: %v", err)
+	}
+
+	submissionEngine := r.getSubmissionEngine(app)
+	if submissionEngine == v1beta2.SubmissionEngineJob && !r.options.Submission.EnableSubmitterJob {
+		return fmt.Errorf("submission engine %s is not enabled in the operator", v1beta2.SubmissionEngineJob)
+	}
+
+	if err := r.resolvePodTemplateConfigMaps(ctx, app); err != nil {
+		return err
+	}
+
+	if err := r.createOrUpdateMainApplicationSourceConfigMap(ctx, app); err != nil {
+		return err
+	}
+
+	if err := r.createOrUpdateConfPropertiesConfigMap(ctx, app); err != nil {
+		return err
+	}
+
+	if r.options.Submission.EnableDriverPodValidation {
+		if err := r.validateDriverPod(ctx, app); err != nil {
+			return err
+		}
+	}
+
+	if r.options.EnableDriverPVCRBAC && util.IsShuffleTrackingEnabled(app) && util.IsPVCReuseEnabled(app) {
+		if err := r.createDriverPVCRBAC(ctx, app); err != nil {
+			return fmt.Errorf("failed to create RBAC for driver service account %s: %v", util.GetDriverServiceAccountName(app), err)
+		}
+	}
+
+	if err := r.configExecutors(ctx, app); err != nil {
+		return err
+	}
+
+	if r.options.EnableSparkAuthSecret && app.Spec.SparkConf[common.SparkAuthenticate] == "" {
+		if err := r.configSparkAuthSecret(ctx, app); err != nil {
+			return fmt.Errorf("failed to configure authentication secret: %v", err)
+		}
+	}
+
+	if r.options.Scheduling.EnableResourceReservation {
+		// No executor of the new run is active yet, so all executors the app may scale up to are reserved.
+		if err := r.reconcileResourceReservation(ctx, app, 0); err != nil {
+			return fmt.Errorf("failed to reserve resources: %v", err)
+		}
+	}
+
+	if util.PrometheusMonitoringEnabled(app) {
+		appLogger(app).Info("Configure Prometheus monitoring for SparkApplication")
+		driverResourceClient, err := r.getDriverResourceClient(app)
+		if err != nil {
+			return err
+		}
+		if err := configPrometheusMonitoring(app, driverResourceClient, r.getOwnerReferences(app)); err != nil {
+			return fmt.Errorf("failed to configure Prometheus monitoring: %v", err)
+		}
+	}
+
+	if err := r.createDriverMetricsService(ctx, app); err != nil {
+		return err
+	}
+
+	// Use batch scheduler to perform scheduling task before submitting (before build command arguments).
+	if needScheduling, scheduler := r.shouldDoBatchScheduling(app); needScheduling {
+		appLogger(app).Info("Do batch scheduling for SparkApplication")
+		if err := scheduler.Schedule(app); err != nil {
+			return fmt.Errorf("failed to process batch scheduler: %v", err)
+		}
+	}
+
+	// Need to ensure the spark.ui variables are configured correctly if the web UI is served on a subpath of the
+	// ingress.
+	if r.options.EnableUIService && r.options.IngressURLFormat != "" {
+		ingressURL, err := getDriverIngressURL(r.options.IngressURLFormat, app.Name, app.Namespace)
+		if err != nil {
+			return fmt.Errorf("failed to get ingress url: %v", err)
+		}
+		if ingressURL.Path != "" {
+			if app.Spec.SparkConf == nil {
+				app.Spec.SparkConf = make(map[string]string)
+			}
+			app.Spec.SparkConf[common.SparkUIProxyBase] = ingressURL.Path
+			app.Spec.SparkConf[common.SparkUIProxyRedirectURI] = "/"
+		}
+	}
+
+	if err := r.reconcileDriverNetworking(ctx, app); err != nil {
+		return err
+	}
+
+	defer func() {
+		if err := r.cleanUpPodTemplateFiles(app); err != nil {
+			logger.Error(fmt.Errorf("failed to clean up pod template files: %v", err), "name", app.Name, "namespace", app.Namespace)
+		}
+	}()
+
+	if r.options.Submission.ImpersonateServiceAccount != "" && submissionEngine == v1beta2.SubmissionEngineSparkSubmit {
+		if err := r.configImpersonation(ctx, app); err != nil {
+			return fmt.Errorf("failed to configure impersonation: %v", err)
+		}
+	}
+
+	sparkSubmitArgs, err := buildSparkSubmitArgs(app)
+	if err != nil {
+		return fmt.Errorf("failed to build spark-submit arguments: %v", err)
+	}
+
+	// Record the effective spark-submit command with sensitive values redacted.
+	redactedArgs := redactSparkSubmitArgs(app, sparkSubmitArgs)
+	app.Status.SparkSubmitCommand = strings.Join(append([]string{"spark-submit"}, redactedArgs...), " ")
+
+	if err := r.runSubmission(ctx, app, submissionEngine, sparkSubmitArgs, redactedArgs); err != nil {
+		r.recordSparkApplicationEvent(app)
+		return err
+	}
+
+	r.recordConfigSnapshot(ctx, app, redactedArgs)
+	return nil
+}
+
+// getSubmissionEngine returns the engine submitting the app, which defaults to the default engine of the operator.
+func (r *Reconciler) getSubmissionEngine(app *v1beta2.SparkApplication) v1beta2.SubmissionEngine {
+	if app.Spec.SubmissionEngine != nil {
+		return *app.Spec.SubmissionEngine
+	}
+	if r.options.Submission.DefaultEngine != "" {
+		return r.options.Submission.DefaultEngine
+	}
+	return v1beta2.SubmissionEngineSparkSubmit
+}
+
+// recordConfigSnapshot records the hash of the normalized configuration of the submitted run in the status, and
+// writes the configuration snapshot to the archive if configured. Failures are logged without failing the submission.
+func (r *Reconciler) recordConfigSnapshot(ctx context.Context, app *v1beta2.SparkApplication, sparkSubmitArgs []string) {
+	snapshot, err := archive.NewSnapshot(app, getSparkConfFromArgs(sparkSubmitArgs))
+	if err != nil {
+		appLogger(app).Error(err, "Failed to create configuration snapshot")
+		return
+	}
+	app.Status.ConfigHash = snapshot.Hash
+
+	if r.options.Archive == nil {
+		return
+	}
+	if err := r.options.Archive.PutSnapshot(ctx, snapshot); err != nil {
+		appLogger(app).Error(err, "Failed to archive configuration snapshot")
+	}
+}
+
+// runSubmission submits the app with the given submission engine, i.e., creates the driver resources directly, runs
+// spark-submit in a Job or runs spark-submit in the operator, with the given spark-submit arguments.
+func (r *Reconciler) runSubmission(
+	ctx context.Context,
+	app *v1beta2.SparkApplication,
+	submissionEngine v1beta2.SubmissionEngine,
+	sparkSubmitArgs []string,
+	redactedArgs []string,
+) error {
+	var err error
+	submitStartTime := time.Now()
+	switch submissionEngine {
+	case v1beta2.SubmissionEngineNative:
+		// Create the driver resources directly with the arguments that would be passed to spark-submit.
+		appLogger(app).Info("Creating driver resources of SparkApplication", "arguments", redactedArgs)
+		var driverResourceClient client.Client
+		if driverResourceClient, err = r.getDriverResourceClient(app); err == nil {
+			err = runNativeSubmission(ctx, driverResourceClient, app, sparkSubmitArgs)
+		}
+	case v1beta2.SubmissionEngineJob:
+		// Try submitting the application by running spark-submit in a Job.
+		appLogger(app).Info("Running spark-submit in a Job for SparkApplication", "arguments", redactedArgs)
+		err = r.runSubmitterJob(ctx, app, sparkSubmitArgs)
+	default:
+		// Try submitting the application by running spark-submit.
+		appLogger(app).Info("Running spark-submit for SparkApplication", "arguments", redactedArgs)
+		var sparkHome string
+		if sparkHome, err = r.getSparkHome(app); err == nil {
+			err = runSparkSubmit(newSubmission(sparkHome, sparkSubmitArgs, app))
+		}
+	}
+	if r.options.SparkSubmissionMetrics != nil {
+		r.options.SparkSubmissionMetrics.ObserveDuration(app.Namespace, time.Since(submitStartTime))
+	}
+	if err != nil {
+		switch submissionEngine {
+		case v1beta2.SubmissionEngineNative:
+			return fmt.Errorf("failed to submit natively: %v", err)
+		case v1beta2.SubmissionEngineJob:
+			return fmt.Errorf("failed to run spark-submit in a job: %v", err)
+		}
+		return fmt.Errorf("failed to run spark-submit: %v", err)
+	}
+	return nil
+}
@@ -0,0 +1,59 @@
+/*
+Copyright 2024 The Kubeflow authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package sparkapplication
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/kubeflow/spark-operator/api/v1beta2"
+)
+
+func TestGetSubmissionEngine(t *testing.T) {
+	native := v1beta2.SubmissionEngineNative
+	testCases := []struct {
+		name     string
+		options  SubmissionOptions
+		engine   *v1beta2.SubmissionEngine
+		expected v1beta2.SubmissionEngine
+	}{
+		{
+			name:     "no engine",
+			expected: v1beta2.SubmissionEngineSparkSubmit,
+		},
+		{
+			name:     "default engine of the operator",
+			options:  SubmissionOptions{DefaultEngine: v1beta2.SubmissionEngineJob},
+			expected: v1beta2.SubmissionEngineJob,
+		},
+		{
+			name:     "engine of the application",
+			options:  SubmissionOptions{DefaultEngine: v1beta2.SubmissionEngineJob},
+			engine:   &native,
+			expected: v1beta2.SubmissionEngineNative,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			r := NewReconciler(nil, nil, nil, nil, nil, Options{Submission: tc.options})
+			app := &v1beta2.SparkApplication{Spec: v1beta2.SparkApplicationSpec{SubmissionEngine: tc.engine}}
+			assert.Equal(t, tc.expected, r.getSubmissionEngine(app))
+		})
+	}
+}
@@ -0,0 +1,144 @@
+/*
+Copyright 2024 The Kubeflow authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package sparkapplication
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/kubeflow/spark-operator/api/v1beta2"
+	"github.com/kubeflow/spark-operator/pkg/common"
+	"github.com/kubeflow/spark-operator/pkg/util"
+)
+
+// driverPodRejectedError is returned if admission rejects the driver pod of a SparkApplication. Such rejections are
+// caused by the spec of the application or cluster policies, so the submission is not retried.
+type driverPodRejectedError struct {
+	err error
+}
+
+func (e *driverPodRejectedError) Error() string {
+	return fmt.Sprintf("driver pod rejected by admission: %v", e.err)
+}
+
+// isDriverPodRejectedError returns whether the error is caused by admission rejecting the driver pod.
+func isDriverPodRejectedError(err error) bool {
+	var rejectedErr *driverPodRejectedError
+	return errors.As(err, &rejectedErr)
+}
+
+// validateDriverPod creates the driver pod of the SparkApplication with a server-side dry run, so that denials of
+// PodSecurity admission or policy engines surface before spark-submit runs. The outcome is recorded in the
+// DriverPodAdmitted condition of the application.
+func (r *Reconciler) validateDriverPod(ctx context.Context, app *v1beta2.SparkApplication) error {
+	driverResourceClient, err := r.getDriverResourceClient(app)
+	if err != nil {
+		return err
+	}
+	pod := newDriverPodForValidation(app)
+	err = driverResourceClient.Create(ctx, pod, client.DryRunAll)
+	if err == nil {
+		meta.SetStatusCondition(&app.Status.Conditions, metav1.Condition{
+			Type:               string(v1beta2.SparkApplicationConditionDriverPodAdmitted),
+			Status:             metav1.ConditionTrue,
+			ObservedGeneration: app.Generation,
+			Reason:             "Admitted",
+		})
+		return nil
+	}
+
+	// Quota errors are transient, so they are handled as any other submission failure.
+	if !(apierrors.IsForbidden(err) || apierrors.IsInvalid(err)) || strings.Contains(err.Error(), "exceeded quota") {
+		return fmt.Errorf("failed to validate driver pod: %v", err)
+	}
+
+	meta.SetStatusCondition(&app.Status.Conditions, metav1.Condition{
+		Type:               string(v1beta2.SparkApplicationConditionDriverPodAdmitted),
+		Status:             metav1.ConditionFalse,
+		ObservedGeneration: app.Generation,
+		Reason:             "Rejected",
+		Message:            err.Error(),
+	})
+	return &driverPodRejectedError{err: err}
+}
+
+// newDriverPodForValidation returns a driver pod approximating the one spark-submit creates. Fields configured by
+// the mutating webhook are added by the webhook during the dry run.
+func newDriverPodForValidation(app *v1beta2.SparkApplication) *corev1.Pod {
+	pod := &corev1.Pod{}
+	if app.Spec.Driver.Template != nil {
+		pod.ObjectMeta = *app.Spec.Driver.Template.ObjectMeta.DeepCopy()
+		pod.Spec = *app.Spec.Driver.Template.Spec.DeepCopy()
+	}
+
+	pod.Name = ""
+	pod.GenerateName = util.GetDriverPodName(app) + "-"
+	pod.Namespace = app.Namespace
+	if pod.Labels == nil {
+		pod.Labels = make(map[string]string)
+	}
+	for key, value := range app.Spec.Driver.Labels {
+		pod.Labels[key] = value
+	}
+	for key, value := range util.GetResourceLabels(app) {
+		pod.Labels[key] = value
+	}
+	pod.Labels[common.LabelSparkRole] = common.SparkRoleDriver
+	pod.Labels[common.LabelLaunchedBySparkOperator] = "true"
+	if len(app.Spec.Driver.Annotations) > 0 && pod.Annotations == nil {
+		pod.Annotations = make(map[string]string)
+	}
+	for key, value := range app.Spec.Driver.Annotations {
+		pod.Annotations[key] = value
+	}
+
+	pod.Spec.RestartPolicy = corev1.RestartPolicyNever
+	pod.Spec.ServiceAccountName = util.GetDriverServiceAccountName(app)
+	if app.Spec.Driver.PodSecurityContext != nil {
+		pod.Spec.SecurityContext = app.Spec.Driver.PodSecurityContext.DeepCopy()
+	}
+
+	image := ""
+	if app.Spec.Driver.Image != nil {
+		image = *app.Spec.Driver.Image
+	} else if app.Spec.Image != nil {
+		image = *app.Spec.Image
+	}
+	var container *corev1.Container
+	for i := range pod.Spec.Containers {
+		if pod.Spec.Containers[i].Name == common.SparkDriverContainerName {
+			container = &pod.Spec.Containers[i]
+		}
+	}
+	if container == nil {
+		pod.Spec.Containers = append([]corev1.Container{{Name: common.SparkDriverContainerName}}, pod.Spec.Containers...)
+		container = &pod.Spec.Containers[0]
+	}
+	container.Image = image
+	if app.Spec.Driver.SecurityContext != nil {
+		container.SecurityContext = app.Spec.Driver.SecurityContext.DeepCopy()
+	}
+	return pod
+}
@@ -17,16 +17,16 @@ limitations under the License.
 package sparkapplication
 
 import (
+	"context"
 	"fmt"
 	"net/url"
-	"strconv"
 
 	"github.com/kubeflow/spark-operator/api/v1beta2"
 	"github.com/kubeflow/spark-operator/pkg/common"
 	"github.com/kubeflow/spark-operator/pkg/util"
 )
 
-func (r *Reconciler) createWebUIService(app *v1beta2.SparkApplication) (*SparkService, error) {
+func (r *Reconciler) createWebUIService(ctx context.Context, app *v1beta2.SparkApplication) (*SparkService, error) {
 	portName := getWebUIServicePortName(app)
 	port, err := getWebUIServicePort(app)
 	if err != nil {
@@ -43,15 +43,15 @@ func (r *Reconciler) createWebUIService(app *v1beta2.SparkApplication) (*SparkSe
 	serviceLabels := util.GetWebUIServiceLabels(app)
 	serviceAnnotations := util.GetWebUIServiceAnnotations(app)
 
-	return r.createDriverIngressService(app, portName, port, targetPort, serviceName, serviceType, serviceAnnotations, serviceLabels)
+	return r.createDriverIngressService(ctx, app, portName, port, targetPort, serviceName, serviceType, serviceAnnotations, serviceLabels)
 }
 
-func (r *Reconciler) createWebUIIngress(app *v1beta2.SparkApplication, service SparkService, ingressURL *url.URL, ingressClassName string) (*SparkIngress, error) {
+func (r *Reconciler) createWebUIIngress(ctx context.Context, app *v1beta2.SparkApplication, service SparkService, ingressURL *url.URL, ingressClassName string) (*SparkIngress, error) {
 	ingressName := util.GetDefaultUIIngressName(app)
 	if util.IngressCapabilities.Has("networking.k8s.io/v1") {
-		return r.createDriverIngressV1(app, service, ingressName, ingressURL, ingressClassName)
+		return r.createDriverIngressV1(ctx, app, service, ingressName, ingressURL, ingressClassName)
 	}
-	return r.createDriverIngressLegacy(app, service, ingressName, ingressURL)
+	return r.createDriverIngressLegacy(ctx, app, service, ingressName, ingressURL)
 }
 
 func getWebUIServicePortName(app *v1beta2.SparkApplication) string {
@@ -76,17 +76,11 @@ func getWebUIServicePort(app *v1beta2.SparkApplication) (int32, error) {
 	return common.DefaultSparkWebUIPort, nil
 }
 
-// getWebUITargetPort attempts to get the Spark web UI port from configuration property spark.ui.port
-// in Spec.SparkConf if it is present, otherwise the default port is returned.
-// Note that we don't attempt to get the port from Spec.SparkConfigMap.
+// getWebUITargetPort returns the port the web UI service targets, which is the port of the oauth2-proxy sidecar
+// if the web UI is authenticated by oauth2-proxy, and the port of the web UI otherwise.
 func getWebUITargetPort(app *v1beta2.SparkApplication) (int32, error) {
-	portStr, ok := app.Spec.SparkConf[common.SparkUIPortKey]
-	if !ok {
-		return common.DefaultSparkWebUIPort, nil
+	if proxy := util.GetWebUIOAuth2Proxy(app); proxy != nil {
+		return util.GetWebUIOAuth2ProxyPort(proxy), nil
 	}
-	port, err := strconv.Atoi(portStr)
-	if err != nil {
-		return common.DefaultSparkWebUIPort, nil
-	}
-	return int32(port), nil
+	return util.GetWebUIPort(app), nil
 }
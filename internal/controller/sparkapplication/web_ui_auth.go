@@ -0,0 +1,122 @@
+/*
+Copyright 2024 The Kubeflow authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package sparkapplication
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"golang.org/x/crypto/bcrypt"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/kubeflow/spark-operator/api/v1beta2"
+	"github.com/kubeflow/spark-operator/pkg/common"
+	"github.com/kubeflow/spark-operator/pkg/naming"
+	"github.com/kubeflow/spark-operator/pkg/util"
+)
+
+const (
+	// webUIAuthUsernameKey and webUIAuthPasswordKey are the keys of the credentials in the Secret referenced by the
+	// basic authentication configuration of the web UI.
+	webUIAuthUsernameKey = "username"
+	webUIAuthPasswordKey = "password"
+)
+
+// syncWebUIBasicAuthSecret creates the htpasswd Secret read by ingress-nginx for basic authentication of the web UI
+// from the credentials in the Secret referenced by the app, or updates it if the credentials changed.
+func (r *Reconciler) syncWebUIBasicAuthSecret(ctx context.Context, app *v1beta2.SparkApplication, basicAuth *v1beta2.SparkUIBasicAuth) error {
+	// Secrets not created by the operator are not cached, so the credentials are read from the API server.
+	credentials := &corev1.Secret{}
+	if err := r.manager.GetAPIReader().Get(ctx, client.ObjectKey{Namespace: app.Namespace, Name: basicAuth.SecretName}, credentials); err != nil {
+		return fmt.Errorf("failed to get web UI credentials secret %s: %v", basicAuth.SecretName, err)
+	}
+	username := string(credentials.Data[webUIAuthUsernameKey])
+	password := credentials.Data[webUIAuthPasswordKey]
+	if username == "" || len(password) == 0 || strings.Contains(username, ":") {
+		return fmt.Errorf("web UI credentials secret %s must hold a username without colons and a password", basicAuth.SecretName)
+	}
+
+	name := naming.UIAuthSecretName(app)
+	secret := &corev1.Secret{}
+	if err := r.client.Get(ctx, client.ObjectKey{Namespace: app.Namespace, Name: name}, secret); err != nil {
+		if !errors.IsNotFound(err) {
+			return fmt.Errorf("failed to get secret %s: %v", name, err)
+		}
+
+		htpasswd, err := newHtpasswd(username, password)
+		if err != nil {
+			return err
+		}
+		secret = &corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:            name,
+				Namespace:       app.Namespace,
+				Labels:          util.GetResourceLabels(app),
+				OwnerReferences: r.getOwnerReferences(app),
+			},
+			Type: corev1.SecretTypeOpaque,
+			Data: map[string][]byte{
+				common.SparkUIAuthSecretKey: htpasswd,
+			},
+		}
+		if err := r.client.Create(ctx, secret); err != nil && !errors.IsAlreadyExists(err) {
+			return fmt.Errorf("failed to create secret %s: %v", name, err)
+		}
+		appLogger(app).Info("Created web UI authentication secret for SparkApplication", "secret", name)
+		return nil
+	}
+
+	// Hashes are salted, so the htpasswd file is only regenerated if it does not match the credentials.
+	if matchesHtpasswd(secret.Data[common.SparkUIAuthSecretKey], username, password) {
+		return nil
+	}
+	if !r.isOwnedBy(secret, app) {
+		return fmt.Errorf("secret %s already exists and is not owned by SparkApplication %s", name, app.Name)
+	}
+	htpasswd, err := newHtpasswd(username, password)
+	if err != nil {
+		return err
+	}
+	secret.Data = map[string][]byte{common.SparkUIAuthSecretKey: htpasswd}
+	if err := r.client.Update(ctx, secret); err != nil {
+		return fmt.Errorf("failed to update secret %s: %v", name, err)
+	}
+	appLogger(app).Info("Updated web UI authentication secret for SparkApplication", "secret", name)
+	return nil
+}
+
+// newHtpasswd returns an htpasswd file holding the given user with the password hashed with bcrypt.
+func newHtpasswd(username string, password []byte) ([]byte, error) {
+	hash, err := bcrypt.GenerateFromPassword(password, bcrypt.DefaultCost)
+	if err != nil {
+		return nil, fmt.Errorf("failed to hash web UI password: %v", err)
+	}
+	return []byte(fmt.Sprintf("%s:%s\n", username, hash)), nil
+}
+
+// matchesHtpasswd returns whether the htpasswd file holds exactly the given user with the given password.
+func matchesHtpasswd(htpasswd []byte, username string, password []byte) bool {
+	user, hash, ok := strings.Cut(strings.TrimSpace(string(htpasswd)), ":")
+	if !ok || user != username {
+		return false
+	}
+	return bcrypt.CompareHashAndPassword([]byte(hash), password) == nil
+}
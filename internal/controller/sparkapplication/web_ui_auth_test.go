@@ -0,0 +1,49 @@
+/*
+Copyright 2024 The Kubeflow authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package sparkapplication
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHtpasswd(t *testing.T) {
+	htpasswd, err := newHtpasswd("admin", []byte("secret"))
+	require.NoError(t, err)
+
+	user, hash, ok := strings.Cut(strings.TrimSpace(string(htpasswd)), ":")
+	require.True(t, ok)
+	assert.Equal(t, "admin", user)
+	assert.True(t, strings.HasPrefix(hash, "$2a$"), "expected a bcrypt hash, got %s", hash)
+	assert.NotContains(t, hash, "secret")
+
+	other, err := newHtpasswd("admin", []byte("secret"))
+	require.NoError(t, err)
+	assert.NotEqual(t, htpasswd, other, "hashes must be salted")
+
+	assert.True(t, matchesHtpasswd(htpasswd, "admin", []byte("secret")))
+	assert.False(t, matchesHtpasswd(htpasswd, "admin", []byte("wrong")))
+	assert.False(t, matchesHtpasswd(htpasswd, "other", []byte("secret")))
+	assert.False(t, matchesHtpasswd([]byte("admin:{SSHA}c2VjcmV0"), "admin", []byte("secret")))
+	assert.False(t, matchesHtpasswd([]byte("garbage"), "admin", []byte("secret")))
+
+	_, err = newHtpasswd("admin", []byte(strings.Repeat("x", 73)))
+	assert.Error(t, err)
+}
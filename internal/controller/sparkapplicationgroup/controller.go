@@ -0,0 +1,316 @@
+/*
+Copyright 2024 The Kubeflow authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package sparkapplicationgroup
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/tools/record"
+	"k8s.io/client-go/util/retry"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/builder"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	"github.com/kubeflow/spark-operator/api/v1beta2"
+	"github.com/kubeflow/spark-operator/pkg/common"
+	"github.com/kubeflow/spark-operator/pkg/naming"
+	"github.com/kubeflow/spark-operator/pkg/util"
+)
+
+var (
+	logger = ctrl.Log.WithName("")
+)
+
+// Options defines the options of the SparkApplicationGroup controller.
+type Options struct {
+	Namespaces []string
+}
+
+// Reconciler reconciles a SparkApplicationGroup object by creating its member SparkApplications,
+// stopping them when the group is suspended or killed and aggregating their states.
+type Reconciler struct {
+	scheme   *runtime.Scheme
+	client   client.Client
+	recorder record.EventRecorder
+	options  Options
+}
+
+// Reconciler implements reconcile.Reconciler.
+var _ reconcile.Reconciler = &Reconciler{}
+
+// NewReconciler creates a new Reconciler instance.
+func NewReconciler(scheme *runtime.Scheme, client client.Client, recorder record.EventRecorder, options Options) *Reconciler {
+	return &Reconciler{
+		scheme:   scheme,
+		client:   client,
+		recorder: recorder,
+		options:  options,
+	}
+}
+
+// SetupWithManager sets up the controller with the Manager.
+func (r *Reconciler) SetupWithManager(mgr ctrl.Manager, options controller.Options) error {
+	namespaces := make(map[string]bool)
+	for _, ns := range r.options.Namespaces {
+		namespaces[ns] = true
+	}
+
+	return ctrl.NewControllerManagedBy(mgr).
+		Named("spark-application-group-controller").
+		For(
+			&v1beta2.SparkApplicationGroup{},
+			builder.WithPredicates(predicate.NewPredicateFuncs(func(object client.Object) bool {
+				return len(namespaces) == 0 || namespaces[""] || namespaces[object.GetNamespace()]
+			})),
+		).
+		Owns(&v1beta2.SparkApplication{}).
+		WithOptions(options).
+		Complete(r)
+}
+
+// +kubebuilder:rbac:groups=sparkoperator.k8s.io,resources=sparkapplications,verbs=get;list;watch;create;delete
+// +kubebuilder:rbac:groups=sparkoperator.k8s.io,resources=sparkapplicationgroups,verbs=get;list;watch;update;patch
+// +kubebuilder:rbac:groups=sparkoperator.k8s.io,resources=sparkapplicationgroups/status,verbs=get;update;patch
+// +kubebuilder:rbac:groups=sparkoperator.k8s.io,resources=sparkapplicationgroups/finalizers,verbs=update
+
+// Reconcile creates the missing member SparkApplications of a SparkApplicationGroup, stops the running
+// ones if the group is suspended or killed, and reports the aggregated state of the members. Members
+// stopped by suspending the group are created again once it is resumed.
+func (r *Reconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	group := &v1beta2.SparkApplicationGroup{}
+	if err := r.client.Get(ctx, req.NamespacedName, group); err != nil {
+		if errors.IsNotFound(err) {
+			return ctrl.Result{}, nil
+		}
+		return ctrl.Result{}, err
+	}
+	if !group.DeletionTimestamp.IsZero() {
+		return ctrl.Result{}, nil
+	}
+	logger.V(1).Info("Reconciling SparkApplicationGroup", "name", group.Name, "namespace", group.Namespace, "state", group.Status.State)
+
+	apps, err := r.listMemberApplications(ctx, group)
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+
+	killed := isKilled(group)
+	suspended := group.Spec.Suspend != nil && *group.Spec.Suspend
+	if killed || suspended {
+		if err := r.stopMemberApplications(ctx, group, apps); err != nil {
+			return ctrl.Result{}, err
+		}
+	} else {
+		if err := r.createMemberApplications(ctx, group, apps); err != nil {
+			return ctrl.Result{}, err
+		}
+	}
+
+	status := getGroupStatus(group, apps, suspended, killed)
+	if err := r.updateStatus(ctx, group, status); err != nil {
+		return ctrl.Result{}, err
+	}
+	return ctrl.Result{}, nil
+}
+
+func (r *Reconciler) listMemberApplications(ctx context.Context, group *v1beta2.SparkApplicationGroup) (map[string]*v1beta2.SparkApplication, error) {
+	appList := &v1beta2.SparkApplicationList{}
+	if err := r.client.List(
+		ctx,
+		appList,
+		client.InNamespace(group.Namespace),
+		client.MatchingLabelsSelector{Selector: labels.SelectorFromSet(labels.Set{common.LabelSparkAppGroupName: group.Name})},
+	); err != nil {
+		return nil, fmt.Errorf("failed to list member applications: %v", err)
+	}
+
+	apps := make(map[string]*v1beta2.SparkApplication)
+	for i := range appList.Items {
+		app := &appList.Items[i]
+		if !metav1.IsControlledBy(app, group) {
+			continue
+		}
+		apps[app.Labels[common.LabelSparkAppGroupMemberName]] = app
+	}
+	return apps, nil
+}
+
+// createMemberApplications creates the member applications that neither exist nor have already run to completion.
+func (r *Reconciler) createMemberApplications(ctx context.Context, group *v1beta2.SparkApplicationGroup, apps map[string]*v1beta2.SparkApplication) error {
+	terminated := getTerminatedMembers(group)
+	for _, member := range group.Spec.Applications {
+		if _, ok := apps[member.Name]; ok || terminated[member.Name] {
+			continue
+		}
+
+		app := newMemberApplication(group, member)
+		if err := r.client.Create(ctx, app); err != nil {
+			if errors.IsAlreadyExists(err) {
+				continue
+			}
+			return fmt.Errorf("failed to create member application %s: %v", app.Name, err)
+		}
+		apps[member.Name] = app
+		logger.Info("Created member SparkApplication", "name", app.Name, "namespace", app.Namespace, "group", group.Name)
+		r.recorder.Eventf(group, corev1.EventTypeNormal, common.EventSparkApplicationGroupMemberCreated, "Created member SparkApplication %s", app.Name)
+	}
+	return nil
+}
+
+// stopMemberApplications deletes the member applications that have not terminated yet.
+func (r *Reconciler) stopMemberApplications(ctx context.Context, group *v1beta2.SparkApplicationGroup, apps map[string]*v1beta2.SparkApplication) error {
+	for name, app := range apps {
+		if util.IsTerminated(app) || !app.DeletionTimestamp.IsZero() {
+			continue
+		}
+		if err := r.client.Delete(ctx, app, client.GracePeriodSeconds(0)); err != nil && !errors.IsNotFound(err) {
+			return fmt.Errorf("failed to delete member application %s: %v", app.Name, err)
+		}
+		delete(apps, name)
+		logger.Info("Stopped member SparkApplication", "name", app.Name, "namespace", app.Namespace, "group", group.Name)
+		r.recorder.Eventf(group, corev1.EventTypeNormal, common.EventSparkApplicationGroupMemberStopped, "Stopped member SparkApplication %s", app.Name)
+	}
+	return nil
+}
+
+func (r *Reconciler) updateStatus(ctx context.Context, group *v1beta2.SparkApplicationGroup, status v1beta2.SparkApplicationGroupStatus) error {
+	if equality.Semantic.DeepEqual(group.Status, status) {
+		return nil
+	}
+
+	return retry.RetryOnConflict(retry.DefaultRetry, func() error {
+		old := &v1beta2.SparkApplicationGroup{}
+		if err := r.client.Get(ctx, client.ObjectKeyFromObject(group), old); err != nil {
+			return err
+		}
+		old.Status = status
+		if err := r.client.Status().Update(ctx, old); err != nil {
+			return err
+		}
+		return nil
+	})
+}
+
+// getMemberApplicationName returns the name of the SparkApplication of the given group member.
+func getMemberApplicationName(group *v1beta2.SparkApplicationGroup, member string) string {
+	return naming.GroupMemberApplicationName(group, member)
+}
+
+func newMemberApplication(group *v1beta2.SparkApplicationGroup, member v1beta2.SparkApplicationGroupMember) *v1beta2.SparkApplication {
+	labels := make(map[string]string)
+	for key, value := range group.Spec.Labels {
+		labels[key] = value
+	}
+	labels[common.LabelSparkAppGroupName] = group.Name
+	labels[common.LabelSparkAppGroupMemberName] = member.Name
+
+	return &v1beta2.SparkApplication{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      getMemberApplicationName(group, member.Name),
+			Namespace: group.Namespace,
+			Labels:    labels,
+			OwnerReferences: []metav1.OwnerReference{{
+				APIVersion:         v1beta2.SchemeGroupVersion.String(),
+				Kind:               reflect.TypeOf(v1beta2.SparkApplicationGroup{}).Name(),
+				Name:               group.Name,
+				UID:                group.UID,
+				Controller:         util.BoolPtr(true),
+				BlockOwnerDeletion: util.BoolPtr(true),
+			}},
+		},
+		Spec: *member.Template.DeepCopy(),
+	}
+}
+
+// isKilled returns whether the given group was killed. A killed group stays killed even if the kill flag is reset.
+func isKilled(group *v1beta2.SparkApplicationGroup) bool {
+	return (group.Spec.Kill != nil && *group.Spec.Kill) || group.Status.State == v1beta2.SparkApplicationGroupStateKilled
+}
+
+// getTerminatedMembers returns the members recorded as terminated in the status of the given group.
+func getTerminatedMembers(group *v1beta2.SparkApplicationGroup) map[string]bool {
+	terminated := make(map[string]bool)
+	for _, member := range group.Status.Members {
+		if member.State == v1beta2.ApplicationStateCompleted || member.State == v1beta2.ApplicationStateFailed {
+			terminated[member.Name] = true
+		}
+	}
+	return terminated
+}
+
+// getGroupStatus aggregates the states of the member applications into the status of the group.
+func getGroupStatus(group *v1beta2.SparkApplicationGroup, apps map[string]*v1beta2.SparkApplication, suspended bool, killed bool) v1beta2.SparkApplicationGroupStatus {
+	previousStates := make(map[string]v1beta2.ApplicationStateType)
+	for _, member := range group.Status.Members {
+		previousStates[member.Name] = member.State
+	}
+
+	status := v1beta2.SparkApplicationGroupStatus{}
+	running := false
+	for _, member := range group.Spec.Applications {
+		state := v1beta2.ApplicationStateNew
+		if app, ok := apps[member.Name]; ok {
+			state = app.Status.AppState.State
+		} else if previous := previousStates[member.Name]; previous == v1beta2.ApplicationStateCompleted || previous == v1beta2.ApplicationStateFailed {
+			// Terminated members deleted afterwards keep their final state.
+			state = previous
+		}
+
+		switch state {
+		case v1beta2.ApplicationStateCompleted:
+			status.Completed++
+		case v1beta2.ApplicationStateFailed:
+			status.Failed++
+		case v1beta2.ApplicationStateNew, v1beta2.ApplicationStateWaitingForDependencies:
+		default:
+			running = true
+		}
+
+		status.Members = append(status.Members, v1beta2.SparkApplicationGroupMemberStatus{
+			Name:            member.Name,
+			ApplicationName: getMemberApplicationName(group, member.Name),
+			State:           state,
+		})
+	}
+
+	switch {
+	case killed:
+		status.State = v1beta2.SparkApplicationGroupStateKilled
+	case status.Failed > 0:
+		status.State = v1beta2.SparkApplicationGroupStateAnyFailed
+	case int(status.Completed) == len(group.Spec.Applications):
+		status.State = v1beta2.SparkApplicationGroupStateAllCompleted
+	case suspended:
+		status.State = v1beta2.SparkApplicationGroupStateSuspended
+	case running:
+		status.State = v1beta2.SparkApplicationGroupStateRunning
+	default:
+		status.State = v1beta2.SparkApplicationGroupStatePending
+	}
+	return status
+}
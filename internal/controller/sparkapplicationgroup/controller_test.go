@@ -0,0 +1,318 @@
+/*
+Copyright 2024 The Kubeflow authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package sparkapplicationgroup
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	"github.com/kubeflow/spark-operator/api/v1beta2"
+	"github.com/kubeflow/spark-operator/pkg/common"
+	"github.com/kubeflow/spark-operator/pkg/util"
+)
+
+func newTestGroup() *v1beta2.SparkApplicationGroup {
+	return &v1beta2.SparkApplicationGroup{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "pipeline",
+			Namespace: "default",
+		},
+		Spec: v1beta2.SparkApplicationGroupSpec{
+			Labels: map[string]string{"team": "data"},
+			Applications: []v1beta2.SparkApplicationGroupMember{
+				{Name: "extract"},
+				{Name: "load"},
+			},
+		},
+	}
+}
+
+func newTestApp(state v1beta2.ApplicationStateType) *v1beta2.SparkApplication {
+	return &v1beta2.SparkApplication{
+		Status: v1beta2.SparkApplicationStatus{
+			AppState: v1beta2.ApplicationState{State: state},
+		},
+	}
+}
+
+func TestNewMemberApplication(t *testing.T) {
+	group := newTestGroup()
+	app := newMemberApplication(group, group.Spec.Applications[0])
+
+	assert.Equal(t, "pipeline-extract", app.Name)
+	assert.Equal(t, "default", app.Namespace)
+	assert.Equal(t, "data", app.Labels["team"])
+	assert.Equal(t, "pipeline", app.Labels[common.LabelSparkAppGroupName])
+	assert.Equal(t, "extract", app.Labels[common.LabelSparkAppGroupMemberName])
+	assert.True(t, *app.OwnerReferences[0].Controller)
+}
+
+func TestGetGroupStatus(t *testing.T) {
+	testCases := []struct {
+		name      string
+		apps      map[string]*v1beta2.SparkApplication
+		previous  []v1beta2.SparkApplicationGroupMemberStatus
+		suspended bool
+		killed    bool
+		expected  v1beta2.SparkApplicationGroupState
+	}{
+		{
+			name:     "no member created",
+			apps:     map[string]*v1beta2.SparkApplication{},
+			expected: v1beta2.SparkApplicationGroupStatePending,
+		},
+		{
+			name: "one member running",
+			apps: map[string]*v1beta2.SparkApplication{
+				"extract": newTestApp(v1beta2.ApplicationStateRunning),
+				"load":    newTestApp(v1beta2.ApplicationStateNew),
+			},
+			expected: v1beta2.SparkApplicationGroupStateRunning,
+		},
+		{
+			name: "all members completed",
+			apps: map[string]*v1beta2.SparkApplication{
+				"extract": newTestApp(v1beta2.ApplicationStateCompleted),
+				"load":    newTestApp(v1beta2.ApplicationStateCompleted),
+			},
+			expected: v1beta2.SparkApplicationGroupStateAllCompleted,
+		},
+		{
+			name: "completed member deleted afterwards",
+			apps: map[string]*v1beta2.SparkApplication{
+				"load": newTestApp(v1beta2.ApplicationStateCompleted),
+			},
+			previous: []v1beta2.SparkApplicationGroupMemberStatus{
+				{Name: "extract", State: v1beta2.ApplicationStateCompleted},
+			},
+			expected: v1beta2.SparkApplicationGroupStateAllCompleted,
+		},
+		{
+			name: "one member failed",
+			apps: map[string]*v1beta2.SparkApplication{
+				"extract": newTestApp(v1beta2.ApplicationStateFailed),
+				"load":    newTestApp(v1beta2.ApplicationStateRunning),
+			},
+			expected: v1beta2.SparkApplicationGroupStateAnyFailed,
+		},
+		{
+			name:      "suspended",
+			apps:      map[string]*v1beta2.SparkApplication{},
+			suspended: true,
+			expected:  v1beta2.SparkApplicationGroupStateSuspended,
+		},
+		{
+			name: "killed",
+			apps: map[string]*v1beta2.SparkApplication{
+				"extract": newTestApp(v1beta2.ApplicationStateCompleted),
+			},
+			killed:   true,
+			expected: v1beta2.SparkApplicationGroupStateKilled,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			group := newTestGroup()
+			group.Status.Members = tc.previous
+			status := getGroupStatus(group, tc.apps, tc.suspended, tc.killed)
+			assert.Equal(t, tc.expected, status.State)
+			assert.Len(t, status.Members, 2)
+		})
+	}
+}
+
+func TestIsKilled(t *testing.T) {
+	group := newTestGroup()
+	assert.False(t, isKilled(group))
+
+	group.Spec.Kill = util.BoolPtr(true)
+	assert.True(t, isKilled(group))
+
+	group.Spec.Kill = nil
+	group.Status.State = v1beta2.SparkApplicationGroupStateKilled
+	assert.True(t, isKilled(group))
+}
+
+func TestReconcileSuspendAndKill(t *testing.T) {
+	testCases := []struct {
+		name          string
+		suspend       bool
+		kill          bool
+		expectExtract bool
+		expectLoad    bool
+		expectedState v1beta2.SparkApplicationGroupState
+	}{
+		{
+			name:          "creates the missing members",
+			expectExtract: true,
+			expectLoad:    true,
+			expectedState: v1beta2.SparkApplicationGroupStateRunning,
+		},
+		{
+			name:          "suspended group stops running members and starts no new ones",
+			suspend:       true,
+			expectExtract: false,
+			expectLoad:    false,
+			expectedState: v1beta2.SparkApplicationGroupStateSuspended,
+		},
+		{
+			name:          "killed group stops running members",
+			kill:          true,
+			expectExtract: false,
+			expectLoad:    false,
+			expectedState: v1beta2.SparkApplicationGroupStateKilled,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			group := newTestGroup()
+			group.UID = "pipeline-uid"
+			group.Spec.Suspend = util.BoolPtr(tc.suspend)
+			group.Spec.Kill = util.BoolPtr(tc.kill)
+			running := newMemberApplication(group, group.Spec.Applications[0])
+			running.Status.AppState.State = v1beta2.ApplicationStateRunning
+
+			scheme := runtime.NewScheme()
+			require.NoError(t, v1beta2.AddToScheme(scheme))
+			c := fake.NewClientBuilder().
+				WithScheme(scheme).
+				WithObjects(group, running).
+				WithStatusSubresource(group, running).
+				Build()
+			r := NewReconciler(scheme, c, record.NewFakeRecorder(10), Options{})
+
+			ctx := context.Background()
+			_, err := r.Reconcile(ctx, ctrl.Request{NamespacedName: client.ObjectKeyFromObject(group)})
+			require.NoError(t, err)
+
+			for member, expected := range map[string]bool{"extract": tc.expectExtract, "load": tc.expectLoad} {
+				key := types.NamespacedName{Namespace: group.Namespace, Name: getMemberApplicationName(group, member)}
+				err := c.Get(ctx, key, &v1beta2.SparkApplication{})
+				if expected {
+					assert.NoError(t, err, member)
+				} else {
+					assert.True(t, errors.IsNotFound(err), member)
+				}
+			}
+
+			require.NoError(t, c.Get(ctx, client.ObjectKeyFromObject(group), group))
+			assert.Equal(t, tc.expectedState, group.Status.State)
+		})
+	}
+}
+
+func TestReconcileResumesSuspendedGroup(t *testing.T) {
+	group := newTestGroup()
+	group.UID = "pipeline-uid"
+	group.Spec.Applications = append(group.Spec.Applications, v1beta2.SparkApplicationGroupMember{Name: "report"})
+	group.Spec.Suspend = util.BoolPtr(true)
+	running := newMemberApplication(group, group.Spec.Applications[0])
+	running.Status.AppState.State = v1beta2.ApplicationStateRunning
+	completed := newMemberApplication(group, group.Spec.Applications[1])
+	completed.Status.AppState.State = v1beta2.ApplicationStateCompleted
+
+	scheme := runtime.NewScheme()
+	require.NoError(t, v1beta2.AddToScheme(scheme))
+	c := fake.NewClientBuilder().
+		WithScheme(scheme).
+		WithObjects(group, running, completed).
+		WithStatusSubresource(group, running, completed).
+		Build()
+	r := NewReconciler(scheme, c, record.NewFakeRecorder(10), Options{})
+
+	ctx := context.Background()
+	memberExists := func(member string) bool {
+		key := types.NamespacedName{Namespace: group.Namespace, Name: getMemberApplicationName(group, member)}
+		err := c.Get(ctx, key, &v1beta2.SparkApplication{})
+		if err != nil && !errors.IsNotFound(err) {
+			require.NoError(t, err)
+		}
+		return err == nil
+	}
+
+	// Suspending the group stops the running member but keeps the completed one.
+	_, err := r.Reconcile(ctx, ctrl.Request{NamespacedName: client.ObjectKeyFromObject(group)})
+	require.NoError(t, err)
+	assert.False(t, memberExists("extract"))
+	assert.True(t, memberExists("load"))
+	assert.False(t, memberExists("report"))
+	require.NoError(t, c.Get(ctx, client.ObjectKeyFromObject(group), group))
+	assert.Equal(t, v1beta2.SparkApplicationGroupStateSuspended, group.Status.State)
+	assert.Equal(t, v1beta2.ApplicationStateNew, group.Status.Members[0].State)
+
+	// Resuming the group starts the stopped member over along with the members not started yet.
+	group.Spec.Suspend = util.BoolPtr(false)
+	require.NoError(t, c.Update(ctx, group))
+	_, err = r.Reconcile(ctx, ctrl.Request{NamespacedName: client.ObjectKeyFromObject(group)})
+	require.NoError(t, err)
+	assert.True(t, memberExists("extract"))
+	assert.True(t, memberExists("load"))
+	assert.True(t, memberExists("report"))
+}
+
+func TestReconcileNeverRestartsMembersOfKilledGroup(t *testing.T) {
+	group := newTestGroup()
+	group.UID = "pipeline-uid"
+	group.Spec.Kill = util.BoolPtr(true)
+	running := newMemberApplication(group, group.Spec.Applications[0])
+	running.Status.AppState.State = v1beta2.ApplicationStateRunning
+
+	scheme := runtime.NewScheme()
+	require.NoError(t, v1beta2.AddToScheme(scheme))
+	c := fake.NewClientBuilder().
+		WithScheme(scheme).
+		WithObjects(group, running).
+		WithStatusSubresource(group, running).
+		Build()
+	r := NewReconciler(scheme, c, record.NewFakeRecorder(10), Options{})
+
+	ctx := context.Background()
+	memberCount := func() int {
+		apps := &v1beta2.SparkApplicationList{}
+		require.NoError(t, c.List(ctx, apps, client.InNamespace(group.Namespace)))
+		return len(apps.Items)
+	}
+
+	// Killing the group stops the running member.
+	_, err := r.Reconcile(ctx, ctrl.Request{NamespacedName: client.ObjectKeyFromObject(group)})
+	require.NoError(t, err)
+	assert.Zero(t, memberCount())
+
+	// Unlike a suspended group, a killed group is not resumed by resetting the flag, so that an application
+	// killed for misbehaving is not started over by accident.
+	require.NoError(t, c.Get(ctx, client.ObjectKeyFromObject(group), group))
+	group.Spec.Kill = util.BoolPtr(false)
+	require.NoError(t, c.Update(ctx, group))
+	_, err = r.Reconcile(ctx, ctrl.Request{NamespacedName: client.ObjectKeyFromObject(group)})
+	require.NoError(t, err)
+	assert.Zero(t, memberCount())
+	require.NoError(t, c.Get(ctx, client.ObjectKeyFromObject(group), group))
+	assert.Equal(t, v1beta2.SparkApplicationGroupStateKilled, group.Status.State)
+}
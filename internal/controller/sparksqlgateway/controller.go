@@ -0,0 +1,267 @@
+/*
+Copyright 2024 The Kubeflow authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package sparksqlgateway
+
+import (
+	"context"
+	"fmt"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	networkingv1 "k8s.io/api/networking/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/tools/record"
+	"k8s.io/client-go/util/retry"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/builder"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	"github.com/kubeflow/spark-operator/api/v1beta2"
+	"github.com/kubeflow/spark-operator/pkg/common"
+)
+
+var (
+	logger = ctrl.Log.WithName("")
+)
+
+// Options defines the options of the SparkSQLGateway controller.
+type Options struct {
+	Namespaces []string
+}
+
+// Reconciler reconciles a SparkSQLGateway object by managing the Deployment, Service and
+// optional Ingress which run and expose a Spark Thrift server.
+type Reconciler struct {
+	scheme   *runtime.Scheme
+	client   client.Client
+	recorder record.EventRecorder
+	options  Options
+}
+
+// Reconciler implements reconcile.Reconciler.
+var _ reconcile.Reconciler = &Reconciler{}
+
+// NewReconciler creates a new Reconciler instance.
+func NewReconciler(scheme *runtime.Scheme, client client.Client, recorder record.EventRecorder, options Options) *Reconciler {
+	return &Reconciler{
+		scheme:   scheme,
+		client:   client,
+		recorder: recorder,
+		options:  options,
+	}
+}
+
+// SetupWithManager sets up the controller with the Manager.
+func (r *Reconciler) SetupWithManager(mgr ctrl.Manager, options controller.Options) error {
+	namespaces := make(map[string]bool)
+	for _, ns := range r.options.Namespaces {
+		namespaces[ns] = true
+	}
+
+	return ctrl.NewControllerManagedBy(mgr).
+		Named("spark-sql-gateway-controller").
+		For(
+			&v1beta2.SparkSQLGateway{},
+			builder.WithPredicates(predicate.NewPredicateFuncs(func(object client.Object) bool {
+				return len(namespaces) == 0 || namespaces[""] || namespaces[object.GetNamespace()]
+			})),
+		).
+		Owns(&appsv1.Deployment{}).
+		Owns(&corev1.Service{}).
+		Owns(&networkingv1.Ingress{}).
+		WithOptions(options).
+		Complete(r)
+}
+
+// +kubebuilder:rbac:groups=apps,resources=deployments,verbs=get;list;watch;create;update;patch;delete
+// +kubebuilder:rbac:groups=,resources=services,verbs=get;list;watch;create;update;patch;delete
+// +kubebuilder:rbac:groups=networking.k8s.io,resources=ingresses,verbs=get;list;watch;create;update;patch;delete
+// +kubebuilder:rbac:groups=sparkoperator.k8s.io,resources=sparksqlgateways,verbs=get;list;watch;update;patch
+// +kubebuilder:rbac:groups=sparkoperator.k8s.io,resources=sparksqlgateways/status,verbs=get;update;patch
+
+// Reconcile makes the Deployment, Service and Ingress of a SparkSQLGateway match its spec and
+// reports the observed replicas and endpoint in its status.
+func (r *Reconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	gateway := &v1beta2.SparkSQLGateway{}
+	if err := r.client.Get(ctx, req.NamespacedName, gateway); err != nil {
+		if errors.IsNotFound(err) {
+			return ctrl.Result{}, nil
+		}
+		return ctrl.Result{}, err
+	}
+	if !gateway.DeletionTimestamp.IsZero() {
+		return ctrl.Result{}, nil
+	}
+	logger.V(1).Info("Reconciling SparkSQLGateway", "name", gateway.Name, "namespace", gateway.Namespace)
+
+	deployment, err := r.reconcileDeployment(ctx, gateway)
+	if err != nil {
+		r.recorder.Eventf(gateway, corev1.EventTypeWarning, common.EventSparkSQLGatewayReconcileFailed, "Failed to reconcile deployment: %v", err)
+		return ctrl.Result{}, err
+	}
+
+	service, err := r.reconcileService(ctx, gateway)
+	if err != nil {
+		r.recorder.Eventf(gateway, corev1.EventTypeWarning, common.EventSparkSQLGatewayReconcileFailed, "Failed to reconcile service: %v", err)
+		return ctrl.Result{}, err
+	}
+
+	ingressName, err := r.reconcileIngress(ctx, gateway)
+	if err != nil {
+		r.recorder.Eventf(gateway, corev1.EventTypeWarning, common.EventSparkSQLGatewayReconcileFailed, "Failed to reconcile ingress: %v", err)
+		return ctrl.Result{}, err
+	}
+
+	if err := r.updateStatus(ctx, gateway, deployment, service, ingressName); err != nil {
+		return ctrl.Result{}, err
+	}
+	return ctrl.Result{}, nil
+}
+
+func (r *Reconciler) reconcileDeployment(ctx context.Context, gateway *v1beta2.SparkSQLGateway) (*appsv1.Deployment, error) {
+	deployment := &appsv1.Deployment{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      getDeploymentName(gateway),
+			Namespace: gateway.Namespace,
+		},
+	}
+	desired := buildDeploymentSpec(gateway)
+	if _, err := controllerutil.CreateOrUpdate(ctx, r.client, deployment, func() error {
+		if err := checkOwnership(deployment, gateway); err != nil {
+			return err
+		}
+		deployment.Labels = getLabels(gateway)
+		deployment.Spec.Replicas = desired.Replicas
+		deployment.Spec.Selector = desired.Selector
+		deployment.Spec.Template = desired.Template
+		return controllerutil.SetControllerReference(gateway, deployment, r.scheme)
+	}); err != nil {
+		return nil, fmt.Errorf("failed to create or update deployment %s: %v", deployment.Name, err)
+	}
+	return deployment, nil
+}
+
+func (r *Reconciler) reconcileService(ctx context.Context, gateway *v1beta2.SparkSQLGateway) (*corev1.Service, error) {
+	service := &corev1.Service{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      getServiceName(gateway),
+			Namespace: gateway.Namespace,
+		},
+	}
+	desired := buildServiceSpec(gateway)
+	if _, err := controllerutil.CreateOrUpdate(ctx, r.client, service, func() error {
+		if err := checkOwnership(service, gateway); err != nil {
+			return err
+		}
+		service.Labels = getLabels(gateway)
+		// Keep the fields allocated by the API server, e.g. the cluster IP and node ports.
+		service.Spec.Type = desired.Type
+		service.Spec.Selector = desired.Selector
+		if len(service.Spec.Ports) == 1 && service.Spec.Ports[0].Port == desired.Ports[0].Port {
+			desired.Ports[0].NodePort = service.Spec.Ports[0].NodePort
+		}
+		service.Spec.Ports = desired.Ports
+		return controllerutil.SetControllerReference(gateway, service, r.scheme)
+	}); err != nil {
+		return nil, fmt.Errorf("failed to create or update service %s: %v", service.Name, err)
+	}
+	return service, nil
+}
+
+// reconcileIngress creates or updates the Ingress of the gateway if one is configured, and deletes
+// a previously created Ingress otherwise. Ingresses not controlled by the gateway are never updated
+// or deleted. It returns the name of the Ingress if one exists.
+func (r *Reconciler) reconcileIngress(ctx context.Context, gateway *v1beta2.SparkSQLGateway) (string, error) {
+	ingress := &networkingv1.Ingress{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      getIngressName(gateway),
+			Namespace: gateway.Namespace,
+		},
+	}
+
+	if gateway.Spec.Ingress == nil {
+		if err := r.client.Get(ctx, client.ObjectKeyFromObject(ingress), ingress); err != nil {
+			if errors.IsNotFound(err) {
+				return "", nil
+			}
+			return "", fmt.Errorf("failed to get ingress %s: %v", ingress.Name, err)
+		}
+		if !metav1.IsControlledBy(ingress, gateway) {
+			return "", nil
+		}
+		if err := r.client.Delete(ctx, ingress, client.Preconditions{UID: &ingress.UID}); err != nil && !errors.IsNotFound(err) {
+			return "", fmt.Errorf("failed to delete ingress %s: %v", ingress.Name, err)
+		}
+		return "", nil
+	}
+
+	desired := buildIngressSpec(gateway)
+	if _, err := controllerutil.CreateOrUpdate(ctx, r.client, ingress, func() error {
+		if err := checkOwnership(ingress, gateway); err != nil {
+			return err
+		}
+		ingress.Labels = getLabels(gateway)
+		ingress.Annotations = gateway.Spec.Ingress.Annotations
+		ingress.Spec = desired
+		return controllerutil.SetControllerReference(gateway, ingress, r.scheme)
+	}); err != nil {
+		return "", fmt.Errorf("failed to create or update ingress %s: %v", ingress.Name, err)
+	}
+	return ingress.Name, nil
+}
+
+// checkOwnership returns an error if the object already exists and is not controlled by the gateway, so that
+// objects of the same name created by users or other controllers are not taken over.
+func checkOwnership(obj client.Object, gateway *v1beta2.SparkSQLGateway) error {
+	if obj.GetResourceVersion() == "" || metav1.IsControlledBy(obj, gateway) {
+		return nil
+	}
+	return fmt.Errorf("%s already exists and is not owned by SparkSQLGateway %s", obj.GetName(), gateway.Name)
+}
+
+func (r *Reconciler) updateStatus(ctx context.Context, gateway *v1beta2.SparkSQLGateway, deployment *appsv1.Deployment, service *corev1.Service, ingressName string) error {
+	status := v1beta2.SparkSQLGatewayStatus{
+		Replicas:      deployment.Status.Replicas,
+		ReadyReplicas: deployment.Status.ReadyReplicas,
+		ServiceName:   service.Name,
+		Endpoint:      fmt.Sprintf("%s.%s.svc:%d", service.Name, service.Namespace, getPort(gateway)),
+		IngressName:   ingressName,
+		Selector:      labels.SelectorFromSet(getLabels(gateway)).String(),
+	}
+	if gateway.Status == status {
+		return nil
+	}
+
+	return retry.RetryOnConflict(retry.DefaultRetry, func() error {
+		old := &v1beta2.SparkSQLGateway{}
+		if err := r.client.Get(ctx, client.ObjectKeyFromObject(gateway), old); err != nil {
+			return err
+		}
+		old.Status = status
+		if err := r.client.Status().Update(ctx, old); err != nil {
+			return err
+		}
+		return nil
+	})
+}
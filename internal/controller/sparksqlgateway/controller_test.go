@@ -0,0 +1,143 @@
+/*
+Copyright 2024 The Kubeflow authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package sparksqlgateway
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	appsv1 "k8s.io/api/apps/v1"
+	networkingv1 "k8s.io/api/networking/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/tools/record"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+
+	"github.com/kubeflow/spark-operator/api/v1beta2"
+)
+
+func newTestScheme(t *testing.T) *runtime.Scheme {
+	scheme := runtime.NewScheme()
+	require.NoError(t, clientgoscheme.AddToScheme(scheme))
+	require.NoError(t, v1beta2.AddToScheme(scheme))
+	return scheme
+}
+
+func newTestReconciler(t *testing.T, objs ...client.Object) (*Reconciler, client.Client) {
+	scheme := newTestScheme(t)
+	c := fake.NewClientBuilder().
+		WithScheme(scheme).
+		WithObjects(objs...).
+		WithStatusSubresource(&v1beta2.SparkSQLGateway{}).
+		Build()
+	return NewReconciler(scheme, c, record.NewFakeRecorder(10), Options{}), c
+}
+
+func newTestGateway() *v1beta2.SparkSQLGateway {
+	return &v1beta2.SparkSQLGateway{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "gateway",
+			Namespace: "default",
+			UID:       "gateway-uid",
+		},
+		Spec: v1beta2.SparkSQLGatewaySpec{
+			Image: "spark:3.5.3",
+		},
+	}
+}
+
+func newTestIngress() *networkingv1.Ingress {
+	return &networkingv1.Ingress{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "gateway",
+			Namespace: "default",
+		},
+	}
+}
+
+func TestReconcileRecordsSelector(t *testing.T) {
+	gateway := newTestGateway()
+	r, c := newTestReconciler(t, gateway)
+
+	_, err := r.Reconcile(context.Background(), ctrl.Request{NamespacedName: client.ObjectKeyFromObject(gateway)})
+	require.NoError(t, err)
+
+	require.NoError(t, c.Get(context.Background(), client.ObjectKeyFromObject(gateway), gateway))
+	assert.Equal(t, "sparkoperator.k8s.io/sql-gateway-name=gateway", gateway.Status.Selector)
+}
+
+func TestReconcileIngress(t *testing.T) {
+	t.Run("keeps an ingress not controlled by the gateway", func(t *testing.T) {
+		gateway := newTestGateway()
+		r, c := newTestReconciler(t, gateway, newTestIngress())
+
+		name, err := r.reconcileIngress(context.Background(), gateway)
+		require.NoError(t, err)
+		assert.Empty(t, name)
+		assert.NoError(t, c.Get(context.Background(), client.ObjectKeyFromObject(newTestIngress()), &networkingv1.Ingress{}))
+	})
+
+	t.Run("deletes the ingress controlled by the gateway once unset", func(t *testing.T) {
+		gateway := newTestGateway()
+		ingress := newTestIngress()
+		require.NoError(t, controllerutil.SetControllerReference(gateway, ingress, newTestScheme(t)))
+		r, c := newTestReconciler(t, gateway, ingress)
+
+		name, err := r.reconcileIngress(context.Background(), gateway)
+		require.NoError(t, err)
+		assert.Empty(t, name)
+		err = c.Get(context.Background(), client.ObjectKeyFromObject(ingress), &networkingv1.Ingress{})
+		assert.True(t, errors.IsNotFound(err))
+	})
+
+	t.Run("refuses to take over an ingress not controlled by the gateway", func(t *testing.T) {
+		gateway := newTestGateway()
+		gateway.Spec.Ingress = &v1beta2.SparkSQLGatewayIngress{Host: "sql.example.com"}
+		r, c := newTestReconciler(t, gateway, newTestIngress())
+
+		_, err := r.reconcileIngress(context.Background(), gateway)
+		assert.Error(t, err)
+		ingress := &networkingv1.Ingress{}
+		require.NoError(t, c.Get(context.Background(), client.ObjectKeyFromObject(newTestIngress()), ingress))
+		assert.Empty(t, ingress.Spec.Rules)
+	})
+}
+
+func TestReconcileDeploymentRefusesToTakeOver(t *testing.T) {
+	gateway := newTestGateway()
+	deployment := &appsv1.Deployment{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "gateway",
+			Namespace: "default",
+			Labels:    map[string]string{"app": "other"},
+		},
+	}
+	r, c := newTestReconciler(t, gateway, deployment)
+
+	_, err := r.reconcileDeployment(context.Background(), gateway)
+	assert.Error(t, err)
+	require.NoError(t, c.Get(context.Background(), client.ObjectKeyFromObject(deployment), deployment))
+	assert.Equal(t, map[string]string{"app": "other"}, deployment.Labels)
+	assert.Empty(t, deployment.OwnerReferences)
+}
@@ -0,0 +1,316 @@
+/*
+Copyright 2024 The Kubeflow authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package sparksqlgateway
+
+import (
+	"fmt"
+	"maps"
+	"sort"
+	"strings"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	networkingv1 "k8s.io/api/networking/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+
+	"github.com/kubeflow/spark-operator/api/v1beta2"
+	"github.com/kubeflow/spark-operator/pkg/common"
+	"github.com/kubeflow/spark-operator/pkg/util"
+)
+
+const (
+	// defaultThriftServerPort is the default port of the Spark Thrift server.
+	defaultThriftServerPort = 10000
+
+	// thriftServerContainerName is the name of the Thrift server container.
+	thriftServerContainerName = "spark-thrift-server"
+
+	// thriftServerPortName is the name of the Thrift server port.
+	thriftServerPortName = "thrift"
+
+	// thriftServerScript is the script starting the Spark Thrift server in the Spark image.
+	thriftServerScript = "/opt/spark/sbin/start-thriftserver.sh"
+
+	// authVolumeName is the name of the volume holding the HiveServer2 authentication configuration.
+	authVolumeName = "hive-site"
+
+	// hiveSiteFileName is the key of the HiveServer2 configuration in the auth Secret.
+	hiveSiteFileName = "hive-site.xml"
+
+	// sparkConfDir is the Spark configuration directory in the Spark image.
+	sparkConfDir = "/opt/spark/conf"
+
+	// inClusterMasterURL is the master URL of the Kubernetes API server as seen from inside the cluster.
+	inClusterMasterURL = "k8s://https://kubernetes.default.svc"
+)
+
+func getDeploymentName(gateway *v1beta2.SparkSQLGateway) string {
+	return gateway.Name
+}
+
+func getServiceName(gateway *v1beta2.SparkSQLGateway) string {
+	return gateway.Name
+}
+
+func getIngressName(gateway *v1beta2.SparkSQLGateway) string {
+	return gateway.Name
+}
+
+func getLabels(gateway *v1beta2.SparkSQLGateway) map[string]string {
+	return map[string]string{
+		common.LabelSparkSQLGatewayName: gateway.Name,
+	}
+}
+
+func getPort(gateway *v1beta2.SparkSQLGateway) int32 {
+	if gateway.Spec.Port != nil {
+		return *gateway.Spec.Port
+	}
+	return defaultThriftServerPort
+}
+
+func getReplicas(gateway *v1beta2.SparkSQLGateway) *int32 {
+	if gateway.Spec.Replicas != nil {
+		return gateway.Spec.Replicas
+	}
+	return util.Int32Ptr(1)
+}
+
+// getSparkConf returns the Spark configuration properties of the Thrift server, with the properties
+// derived from the gateway spec taking precedence over the user-specified ones.
+func getSparkConf(gateway *v1beta2.SparkSQLGateway) map[string]string {
+	conf := make(map[string]string)
+	for key, value := range gateway.Spec.SparkConf {
+		conf[key] = value
+	}
+
+	conf[common.SparkKubernetesNamespace] = gateway.Namespace
+	conf[common.SparkKubernetesContainerImage] = gateway.Spec.Image
+	if gateway.Spec.ImagePullPolicy != nil {
+		conf[common.SparkKubernetesContainerImagePullPolicy] = string(*gateway.Spec.ImagePullPolicy)
+	}
+	if len(gateway.Spec.ImagePullSecrets) > 0 {
+		conf[common.SparkKubernetesContainerImagePullSecrets] = strings.Join(gateway.Spec.ImagePullSecrets, ",")
+	}
+	if gateway.Spec.ServiceAccount != nil {
+		conf[common.SparkKubernetesAuthenticateExecutorServiceAccountName] = *gateway.Spec.ServiceAccount
+	}
+	// Each replica is its own driver, so executors must connect back to the pod rather than the Service.
+	conf[common.SparkKubernetesDriverPodName] = "$(POD_NAME)"
+	conf[common.SparkDriverHost] = "$(POD_IP)"
+	conf[common.SparkDriverBindAddress] = "0.0.0.0"
+	conf[fmt.Sprintf(common.SparkKubernetesExecutorLabelTemplate, common.LabelSparkSQLGatewayName)] = gateway.Name
+
+	if dynamicAllocation := gateway.Spec.DynamicAllocation; dynamicAllocation != nil && dynamicAllocation.Enabled {
+		conf[common.SparkDynamicAllocationEnabled] = "true"
+		if _, ok := conf[common.SparkDynamicAllocationShuffleTrackingEnabled]; !ok {
+			conf[common.SparkDynamicAllocationShuffleTrackingEnabled] = "true"
+		}
+		if dynamicAllocation.InitialExecutors != nil {
+			conf[common.SparkDynamicAllocationInitialExecutors] = fmt.Sprintf("%d", *dynamicAllocation.InitialExecutors)
+		}
+		if dynamicAllocation.MinExecutors != nil {
+			conf[common.SparkDynamicAllocationMinExecutors] = fmt.Sprintf("%d", *dynamicAllocation.MinExecutors)
+		}
+		if dynamicAllocation.MaxExecutors != nil {
+			conf[common.SparkDynamicAllocationMaxExecutors] = fmt.Sprintf("%d", *dynamicAllocation.MaxExecutors)
+		}
+		if dynamicAllocation.ShuffleTrackingTimeout != nil {
+			conf[common.SparkDynamicAllocationShuffleTrackingTimeout] = fmt.Sprintf("%d", *dynamicAllocation.ShuffleTrackingTimeout)
+		}
+		// Invalid timeouts are left to the defaults of Spark.
+		if timeoutConf, err := util.GetDynamicAllocationTimeoutConf(dynamicAllocation); err == nil {
+			maps.Copy(conf, timeoutConf)
+		}
+	}
+	return conf
+}
+
+// getHiveConf returns the HiveServer2 configuration properties of the Thrift server.
+func getHiveConf(gateway *v1beta2.SparkSQLGateway) map[string]string {
+	port := fmt.Sprintf("%d", getPort(gateway))
+	if gateway.Spec.Ingress != nil {
+		return map[string]string{
+			"hive.server2.transport.mode":   "http",
+			"hive.server2.thrift.http.port": port,
+		}
+	}
+	return map[string]string{
+		"hive.server2.transport.mode": "binary",
+		"hive.server2.thrift.port":    port,
+	}
+}
+
+// buildThriftServerArgs builds the arguments of the Thrift server start script in a deterministic order.
+func buildThriftServerArgs(gateway *v1beta2.SparkSQLGateway) []string {
+	args := []string{"--master", inClusterMasterURL}
+
+	conf := getSparkConf(gateway)
+	keys := make([]string, 0, len(conf))
+	for key := range conf {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		args = append(args, "--conf", fmt.Sprintf("%s=%s", key, conf[key]))
+	}
+
+	hiveConf := getHiveConf(gateway)
+	keys = keys[:0]
+	for key := range hiveConf {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		args = append(args, "--hiveconf", fmt.Sprintf("%s=%s", key, hiveConf[key]))
+	}
+	return args
+}
+
+func buildDeploymentSpec(gateway *v1beta2.SparkSQLGateway) appsv1.DeploymentSpec {
+	labels := getLabels(gateway)
+	port := getPort(gateway)
+
+	container := corev1.Container{
+		Name:    thriftServerContainerName,
+		Image:   gateway.Spec.Image,
+		Command: []string{thriftServerScript},
+		Args:    buildThriftServerArgs(gateway),
+		Env: []corev1.EnvVar{
+			{
+				Name:  "SPARK_NO_DAEMONIZE",
+				Value: "true",
+			},
+			{
+				Name: "POD_NAME",
+				ValueFrom: &corev1.EnvVarSource{
+					FieldRef: &corev1.ObjectFieldSelector{FieldPath: "metadata.name"},
+				},
+			},
+			{
+				Name: "POD_IP",
+				ValueFrom: &corev1.EnvVarSource{
+					FieldRef: &corev1.ObjectFieldSelector{FieldPath: "status.podIP"},
+				},
+			},
+		},
+		Ports: []corev1.ContainerPort{
+			{
+				Name:          thriftServerPortName,
+				ContainerPort: port,
+				Protocol:      corev1.ProtocolTCP,
+			},
+		},
+		ReadinessProbe: &corev1.Probe{
+			ProbeHandler: corev1.ProbeHandler{
+				TCPSocket: &corev1.TCPSocketAction{Port: intstr.FromString(thriftServerPortName)},
+			},
+			InitialDelaySeconds: 10,
+			PeriodSeconds:       10,
+		},
+	}
+	if gateway.Spec.ImagePullPolicy != nil {
+		container.ImagePullPolicy = *gateway.Spec.ImagePullPolicy
+	}
+	if gateway.Spec.Resources != nil {
+		container.Resources = *gateway.Spec.Resources
+	}
+
+	podSpec := corev1.PodSpec{
+		Containers:   []corev1.Container{container},
+		NodeSelector: gateway.Spec.NodeSelector,
+		Tolerations:  gateway.Spec.Tolerations,
+	}
+	if gateway.Spec.ServiceAccount != nil {
+		podSpec.ServiceAccountName = *gateway.Spec.ServiceAccount
+	}
+	for _, secret := range gateway.Spec.ImagePullSecrets {
+		podSpec.ImagePullSecrets = append(podSpec.ImagePullSecrets, corev1.LocalObjectReference{Name: secret})
+	}
+	if gateway.Spec.AuthSecretName != nil {
+		podSpec.Volumes = append(podSpec.Volumes, corev1.Volume{
+			Name: authVolumeName,
+			VolumeSource: corev1.VolumeSource{
+				Secret: &corev1.SecretVolumeSource{SecretName: *gateway.Spec.AuthSecretName},
+			},
+		})
+		podSpec.Containers[0].VolumeMounts = append(podSpec.Containers[0].VolumeMounts, corev1.VolumeMount{
+			Name:      authVolumeName,
+			MountPath: fmt.Sprintf("%s/%s", sparkConfDir, hiveSiteFileName),
+			SubPath:   hiveSiteFileName,
+			ReadOnly:  true,
+		})
+	}
+
+	return appsv1.DeploymentSpec{
+		Replicas: getReplicas(gateway),
+		Selector: &metav1.LabelSelector{MatchLabels: labels},
+		Template: corev1.PodTemplateSpec{
+			ObjectMeta: metav1.ObjectMeta{Labels: labels},
+			Spec:       podSpec,
+		},
+	}
+}
+
+func buildServiceSpec(gateway *v1beta2.SparkSQLGateway) corev1.ServiceSpec {
+	serviceType := corev1.ServiceTypeClusterIP
+	if gateway.Spec.ServiceType != nil {
+		serviceType = *gateway.Spec.ServiceType
+	}
+	return corev1.ServiceSpec{
+		Type:     serviceType,
+		Selector: getLabels(gateway),
+		Ports: []corev1.ServicePort{
+			{
+				Name:       thriftServerPortName,
+				Port:       getPort(gateway),
+				TargetPort: intstr.FromString(thriftServerPortName),
+				Protocol:   corev1.ProtocolTCP,
+			},
+		},
+	}
+}
+
+func buildIngressSpec(gateway *v1beta2.SparkSQLGateway) networkingv1.IngressSpec {
+	pathType := networkingv1.PathTypePrefix
+	return networkingv1.IngressSpec{
+		IngressClassName: gateway.Spec.Ingress.IngressClassName,
+		TLS:              gateway.Spec.Ingress.TLS,
+		Rules: []networkingv1.IngressRule{
+			{
+				Host: gateway.Spec.Ingress.Host,
+				IngressRuleValue: networkingv1.IngressRuleValue{
+					HTTP: &networkingv1.HTTPIngressRuleValue{
+						Paths: []networkingv1.HTTPIngressPath{
+							{
+								Path:     "/",
+								PathType: &pathType,
+								Backend: networkingv1.IngressBackend{
+									Service: &networkingv1.IngressServiceBackend{
+										Name: getServiceName(gateway),
+										Port: networkingv1.ServiceBackendPort{Number: getPort(gateway)},
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+}
@@ -0,0 +1,85 @@
+/*
+Copyright 2024 The Kubeflow authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package sparksqlgateway
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/kubeflow/spark-operator/api/v1beta2"
+	"github.com/kubeflow/spark-operator/pkg/common"
+	"github.com/kubeflow/spark-operator/pkg/util"
+)
+
+func TestBuildDeploymentSpec(t *testing.T) {
+	gateway := &v1beta2.SparkSQLGateway{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "gateway",
+			Namespace: "default",
+		},
+		Spec: v1beta2.SparkSQLGatewaySpec{
+			Image:          "spark:3.5.3",
+			Replicas:       util.Int32Ptr(2),
+			AuthSecretName: util.StringPtr("hive-auth"),
+			SparkConf: map[string]string{
+				"spark.executor.memory":         "1g",
+				common.SparkKubernetesNamespace: "other",
+			},
+		},
+	}
+
+	spec := buildDeploymentSpec(gateway)
+	assert.Equal(t, int32(2), *spec.Replicas)
+	assert.Equal(t, "gateway", spec.Selector.MatchLabels[common.LabelSparkSQLGatewayName])
+
+	container := spec.Template.Spec.Containers[0]
+	assert.Equal(t, []string{thriftServerScript}, container.Command)
+	assert.Equal(t, int32(defaultThriftServerPort), container.Ports[0].ContainerPort)
+	assert.Contains(t, container.Args, "spark.executor.memory=1g")
+	assert.Contains(t, container.Args, "spark.kubernetes.namespace=default")
+	assert.Contains(t, container.Args, "spark.driver.host=$(POD_IP)")
+	assert.Contains(t, container.Args, "hive.server2.transport.mode=binary")
+	assert.Contains(t, container.Args, "hive.server2.thrift.port=10000")
+
+	assert.Equal(t, "hive-auth", spec.Template.Spec.Volumes[0].Secret.SecretName)
+	assert.Equal(t, "/opt/spark/conf/hive-site.xml", container.VolumeMounts[0].MountPath)
+	assert.Equal(t, hiveSiteFileName, container.VolumeMounts[0].SubPath)
+}
+
+func TestBuildThriftServerArgsWithIngress(t *testing.T) {
+	gateway := &v1beta2.SparkSQLGateway{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "gateway",
+			Namespace: "default",
+		},
+		Spec: v1beta2.SparkSQLGatewaySpec{
+			Image:   "spark:3.5.3",
+			Port:    util.Int32Ptr(10001),
+			Ingress: &v1beta2.SparkSQLGatewayIngress{Host: "sql.example.com"},
+		},
+	}
+
+	args := buildThriftServerArgs(gateway)
+	assert.Contains(t, args, "hive.server2.transport.mode=http")
+	assert.Contains(t, args, "hive.server2.thrift.http.port=10001")
+
+	ingress := buildIngressSpec(gateway)
+	assert.Equal(t, "sql.example.com", ingress.Rules[0].Host)
+	assert.Equal(t, int32(10001), ingress.Rules[0].HTTP.Paths[0].Backend.Service.Port.Number)
+}
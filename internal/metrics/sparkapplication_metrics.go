@@ -45,6 +45,8 @@ type SparkApplicationMetrics struct {
 
 	startLatencySeconds          *prometheus.SummaryVec
 	startLatencySecondsHistogram *prometheus.HistogramVec
+
+	slaViolationCount *prometheus.CounterVec
 }
 
 func NewSparkApplicationMetrics(prefix string, labels []string, jobStartLatencyBuckets []float64) *SparkApplicationMetrics {
@@ -128,6 +130,13 @@ func NewSparkApplicationMetrics(prefix string, labels []string, jobStartLatencyB
 			},
 			validLabels,
 		),
+		slaViolationCount: prometheus.NewCounterVec(
+			prometheus.CounterOpts{
+				Name: util.CreateValidMetricNameLabel(prefix, common.MetricSparkApplicationSLAViolationCount),
+				Help: "Total number of SLA violations of SparkApplication",
+			},
+			append(validLabels, common.MetricLabelSLAViolation),
+		),
 	}
 }
 
@@ -162,6 +171,9 @@ func (m *SparkApplicationMetrics) Register() {
 	if err := metrics.Registry.Register(m.startLatencySecondsHistogram); err != nil {
 		logger.Error(err, "Failed to register spark application metric", "name", common.MetricSparkApplicationStartLatencySecondsHistogram)
 	}
+	if err := metrics.Registry.Register(m.slaViolationCount); err != nil {
+		logger.Error(err, "Failed to register spark application metric", "name", common.MetricSparkApplicationSLAViolationCount)
+	}
 }
 
 func (m *SparkApplicationMetrics) HandleSparkApplicationCreate(app *v1beta2.SparkApplication) {
@@ -197,7 +209,10 @@ func (m *SparkApplicationMetrics) HandleSparkApplicationUpdate(oldApp *v1beta2.S
 
 	switch newState {
 	case v1beta2.ApplicationStateNew:
-		m.incCount(newApp)
+		// Applications only return to the new state once their dependencies exist, which is not a new application.
+		if oldState != v1beta2.ApplicationStateWaitingForDependencies {
+			m.incCount(newApp)
+		}
 	case v1beta2.ApplicationStateSubmitted:
 		m.incSubmitCount(newApp)
 	case v1beta2.ApplicationStateFailedSubmission:
@@ -364,6 +379,20 @@ func (m *SparkApplicationMetrics) observeStartLatencySeconds(app *v1beta2.SparkA
 	}
 }
 
+// IncSLAViolationCount increases the SLA violation count of the given SparkApplication for the given violation type.
+func (m *SparkApplicationMetrics) IncSLAViolationCount(app *v1beta2.SparkApplication, violation v1beta2.SLAViolationType) {
+	labels := m.getMetricLabels(app)
+	labels[common.MetricLabelSLAViolation] = string(violation)
+	counter, err := m.slaViolationCount.GetMetricWith(labels)
+	if err != nil {
+		logger.Error(err, "Failed to collect metric for SparkApplication", "name", app.Name, "namespace", app.Namespace, "metric", common.MetricSparkApplicationSLAViolationCount, "labels", labels)
+		return
+	}
+
+	counter.Inc()
+	logger.V(1).Info("Increased spark application SLA violation count", "name", app.Name, "namespace", app.Namespace, "metric", common.MetricSparkApplicationSLAViolationCount, "labels", labels)
+}
+
 func (m *SparkApplicationMetrics) getMetricLabels(app *v1beta2.SparkApplication) map[string]string {
 	// Convert spark application validLabels to valid metric validLabels.
 	validLabels := make(map[string]string)
@@ -33,6 +33,8 @@ type SparkExecutorMetrics struct {
 	runningCount *prometheus.GaugeVec
 	successCount *prometheus.CounterVec
 	failureCount *prometheus.CounterVec
+
+	zoneRunningCount *prometheus.GaugeVec
 }
 
 func NewSparkExecutorMetrics(prefix string, labels []string) *SparkExecutorMetrics {
@@ -67,6 +69,13 @@ func NewSparkExecutorMetrics(prefix string, labels []string) *SparkExecutorMetri
 			},
 			validLabels,
 		),
+		zoneRunningCount: prometheus.NewGaugeVec(
+			prometheus.GaugeOpts{
+				Name: util.CreateValidMetricNameLabel(prefix, common.MetricSparkExecutorZoneRunningCount),
+				Help: "Number of running Spark executors of an application per zone",
+			},
+			[]string{common.MetricLabelNamespace, common.MetricLabelAppName, common.MetricLabelZone},
+		),
 	}
 }
 
@@ -80,6 +89,9 @@ func (m *SparkExecutorMetrics) Register() {
 	if err := metrics.Registry.Register(m.failureCount); err != nil {
 		logger.Error(err, "Failed to register spark executor metric", "name", common.MetricSparkExecutorFailureCount)
 	}
+	if err := metrics.Registry.Register(m.zoneRunningCount); err != nil {
+		logger.Error(err, "Failed to register spark executor metric", "name", common.MetricSparkExecutorZoneRunningCount)
+	}
 }
 
 func (m *SparkExecutorMetrics) HandleSparkExecutorCreate(pod *corev1.Pod) {
@@ -121,6 +133,19 @@ func (m *SparkExecutorMetrics) HandleSparkExecutorDelete(pod *corev1.Pod) {
 	}
 }
 
+// SetZoneCounts sets the number of running executors of the application per zone. Zones without running executors
+// are removed from the metric.
+func (m *SparkExecutorMetrics) SetZoneCounts(app *v1beta2.SparkApplication, counts map[string]int) {
+	labels := prometheus.Labels{
+		common.MetricLabelNamespace: app.Namespace,
+		common.MetricLabelAppName:   app.Name,
+	}
+	m.zoneRunningCount.DeletePartialMatch(labels)
+	for zone, count := range counts {
+		m.zoneRunningCount.WithLabelValues(app.Namespace, app.Name, zone).Set(float64(count))
+	}
+}
+
 func (m *SparkExecutorMetrics) incRunningCount(pod *corev1.Pod) {
 	labels := m.getMetricLabels(pod)
 	runningCount, err := m.runningCount.GetMetricWith(labels)
@@ -0,0 +1,136 @@
+/*
+Copyright 2024 The Kubeflow authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package metrics
+
+import (
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"sigs.k8s.io/controller-runtime/pkg/metrics"
+
+	"github.com/kubeflow/spark-operator/api/v1beta2"
+	"github.com/kubeflow/spark-operator/pkg/common"
+	"github.com/kubeflow/spark-operator/pkg/util"
+)
+
+// SparkSubmissionMetrics reports the pressure on the spark-submit workers of the controller per namespace.
+type SparkSubmissionMetrics struct {
+	prefix string
+
+	inFlightCount   *prometheus.GaugeVec
+	queuedCount     *prometheus.GaugeVec
+	backlogCount    *prometheus.GaugeVec
+	durationSeconds *prometheus.SummaryVec
+}
+
+func NewSparkSubmissionMetrics(prefix string) *SparkSubmissionMetrics {
+	labels := []string{common.MetricLabelNamespace}
+
+	return &SparkSubmissionMetrics{
+		prefix: prefix,
+
+		inFlightCount: prometheus.NewGaugeVec(
+			prometheus.GaugeOpts{
+				Name: util.CreateValidMetricNameLabel(prefix, common.MetricSparkSubmissionInFlightCount),
+				Help: "Number of spark-submit processes in flight",
+			},
+			labels,
+		),
+		queuedCount: prometheus.NewGaugeVec(
+			prometheus.GaugeOpts{
+				Name: util.CreateValidMetricNameLabel(prefix, common.MetricSparkSubmissionQueuedCount),
+				Help: "Number of submissions waiting for a free submission slot of their namespace",
+			},
+			labels,
+		),
+		backlogCount: prometheus.NewGaugeVec(
+			prometheus.GaugeOpts{
+				Name: util.CreateValidMetricNameLabel(prefix, common.MetricSparkSubmissionBacklogCount),
+				Help: "Number of SparkApplication waiting to be submitted",
+			},
+			labels,
+		),
+		durationSeconds: prometheus.NewSummaryVec(
+			prometheus.SummaryOpts{
+				Name: util.CreateValidMetricNameLabel(prefix, common.MetricSparkSubmissionDurationSeconds),
+				Help: "Duration of spark-submit processes",
+			},
+			labels,
+		),
+	}
+}
+
+func (m *SparkSubmissionMetrics) Register() {
+	if err := metrics.Registry.Register(m.inFlightCount); err != nil {
+		logger.Error(err, "Failed to register spark submission metric", "name", common.MetricSparkSubmissionInFlightCount)
+	}
+	if err := metrics.Registry.Register(m.queuedCount); err != nil {
+		logger.Error(err, "Failed to register spark submission metric", "name", common.MetricSparkSubmissionQueuedCount)
+	}
+	if err := metrics.Registry.Register(m.backlogCount); err != nil {
+		logger.Error(err, "Failed to register spark submission metric", "name", common.MetricSparkSubmissionBacklogCount)
+	}
+	if err := metrics.Registry.Register(m.durationSeconds); err != nil {
+		logger.Error(err, "Failed to register spark submission metric", "name", common.MetricSparkSubmissionDurationSeconds)
+	}
+}
+
+// SetInFlightCount sets the number of spark-submit processes in flight in the given namespace.
+func (m *SparkSubmissionMetrics) SetInFlightCount(namespace string, count int) {
+	m.inFlightCount.WithLabelValues(namespace).Set(float64(count))
+}
+
+// SetQueuedCount sets the number of submissions of the given namespace throttled by the submission limit.
+func (m *SparkSubmissionMetrics) SetQueuedCount(namespace string, count int) {
+	m.queuedCount.WithLabelValues(namespace).Set(float64(count))
+}
+
+// ObserveDuration records the duration of a spark-submit process run in the given namespace.
+func (m *SparkSubmissionMetrics) ObserveDuration(namespace string, duration time.Duration) {
+	m.durationSeconds.WithLabelValues(namespace).Observe(duration.Seconds())
+}
+
+func (m *SparkSubmissionMetrics) HandleSparkApplicationCreate(app *v1beta2.SparkApplication) {
+	if isAwaitingSubmission(app) {
+		m.backlogCount.WithLabelValues(app.Namespace).Inc()
+	}
+}
+
+func (m *SparkSubmissionMetrics) HandleSparkApplicationUpdate(oldApp *v1beta2.SparkApplication, newApp *v1beta2.SparkApplication) {
+	oldAwaiting := isAwaitingSubmission(oldApp)
+	newAwaiting := isAwaitingSubmission(newApp)
+	if oldAwaiting && !newAwaiting {
+		m.backlogCount.WithLabelValues(oldApp.Namespace).Dec()
+	} else if !oldAwaiting && newAwaiting {
+		m.backlogCount.WithLabelValues(newApp.Namespace).Inc()
+	}
+}
+
+func (m *SparkSubmissionMetrics) HandleSparkApplicationDelete(app *v1beta2.SparkApplication) {
+	if isAwaitingSubmission(app) {
+		m.backlogCount.WithLabelValues(app.Namespace).Dec()
+	}
+}
+
+// isAwaitingSubmission returns whether the application is waiting to be submitted or resubmitted.
+func isAwaitingSubmission(app *v1beta2.SparkApplication) bool {
+	switch util.GetApplicationState(app) {
+	case v1beta2.ApplicationStateNew, v1beta2.ApplicationStateWaitingForDependencies, v1beta2.ApplicationStatePendingRerun:
+		return true
+	}
+	return false
+}
@@ -0,0 +1,74 @@
+/*
+Copyright 2024 The Kubeflow authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package metrics
+
+import (
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/kubeflow/spark-operator/api/v1beta2"
+)
+
+func newTestApp(namespace string, state v1beta2.ApplicationStateType) *v1beta2.SparkApplication {
+	return &v1beta2.SparkApplication{
+		ObjectMeta: metav1.ObjectMeta{Name: "test-app", Namespace: namespace},
+		Status:     v1beta2.SparkApplicationStatus{AppState: v1beta2.ApplicationState{State: state}},
+	}
+}
+
+func TestSparkSubmissionMetricsBacklog(t *testing.T) {
+	m := NewSparkSubmissionMetrics("")
+	backlog := func(namespace string) float64 {
+		return testutil.ToFloat64(m.backlogCount.WithLabelValues(namespace))
+	}
+
+	m.HandleSparkApplicationCreate(newTestApp("default", v1beta2.ApplicationStateNew))
+	m.HandleSparkApplicationCreate(newTestApp("default", v1beta2.ApplicationStateNew))
+	m.HandleSparkApplicationCreate(newTestApp("other", v1beta2.ApplicationStateRunning))
+	assert.Equal(t, 2.0, backlog("default"))
+	assert.Equal(t, 0.0, backlog("other"))
+
+	m.HandleSparkApplicationUpdate(newTestApp("default", v1beta2.ApplicationStateNew), newTestApp("default", v1beta2.ApplicationStateSubmitted))
+	assert.Equal(t, 1.0, backlog("default"))
+
+	m.HandleSparkApplicationUpdate(newTestApp("default", v1beta2.ApplicationStateFailed), newTestApp("default", v1beta2.ApplicationStatePendingRerun))
+	assert.Equal(t, 2.0, backlog("default"))
+
+	m.HandleSparkApplicationUpdate(newTestApp("default", v1beta2.ApplicationStateRunning), newTestApp("default", v1beta2.ApplicationStateCompleted))
+	assert.Equal(t, 2.0, backlog("default"))
+
+	m.HandleSparkApplicationDelete(newTestApp("default", v1beta2.ApplicationStateWaitingForDependencies))
+	m.HandleSparkApplicationDelete(newTestApp("default", v1beta2.ApplicationStateRunning))
+	assert.Equal(t, 1.0, backlog("default"))
+}
+
+func TestSparkSubmissionMetricsWorkers(t *testing.T) {
+	m := NewSparkSubmissionMetrics("spark_operator")
+
+	m.SetInFlightCount("default", 3)
+	m.SetQueuedCount("default", 5)
+	m.ObserveDuration("default", 2*time.Second)
+	m.ObserveDuration("default", 4*time.Second)
+
+	assert.Equal(t, 3.0, testutil.ToFloat64(m.inFlightCount.WithLabelValues("default")))
+	assert.Equal(t, 5.0, testutil.ToFloat64(m.queuedCount.WithLabelValues("default")))
+	assert.Equal(t, 1, testutil.CollectAndCount(m.durationSeconds))
+}
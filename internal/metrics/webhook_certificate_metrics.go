@@ -0,0 +1,106 @@
+/*
+Copyright 2024 The Kubeflow authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package metrics
+
+import (
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"sigs.k8s.io/controller-runtime/pkg/metrics"
+
+	"github.com/kubeflow/spark-operator/pkg/certificate"
+	"github.com/kubeflow/spark-operator/pkg/common"
+	"github.com/kubeflow/spark-operator/pkg/util"
+)
+
+// WebhookCertificateMetrics exposes the expiry of the webhook certificates and the health of their rotation, which
+// are read from the certificate provider and rotator whenever the metrics are collected.
+type WebhookCertificateMetrics struct {
+	caExpiry      prometheus.GaugeFunc
+	servingExpiry prometheus.GaugeFunc
+	lastReload    prometheus.GaugeFunc
+	failureCount  prometheus.CounterFunc
+}
+
+func NewWebhookCertificateMetrics(prefix string, provider *certificate.Provider, rotator *certificate.Rotator) *WebhookCertificateMetrics {
+	expiryName := util.CreateValidMetricNameLabel(prefix, common.MetricWebhookCertificateExpiryTimestampSeconds)
+	expiryHelp := "Expiry time of the webhook certificate in seconds since the Unix epoch"
+
+	return &WebhookCertificateMetrics{
+		caExpiry: prometheus.NewGaugeFunc(
+			prometheus.GaugeOpts{
+				Name:        expiryName,
+				Help:        expiryHelp,
+				ConstLabels: prometheus.Labels{common.MetricLabelCertificate: "ca"},
+			},
+			func() float64 {
+				return timestampSeconds(provider.CACertNotAfter())
+			},
+		),
+		servingExpiry: prometheus.NewGaugeFunc(
+			prometheus.GaugeOpts{
+				Name:        expiryName,
+				Help:        expiryHelp,
+				ConstLabels: prometheus.Labels{common.MetricLabelCertificate: "serving"},
+			},
+			func() float64 {
+				return timestampSeconds(rotator.ServingCertNotAfter())
+			},
+		),
+		lastReload: prometheus.NewGaugeFunc(
+			prometheus.GaugeOpts{
+				Name: util.CreateValidMetricNameLabel(prefix, common.MetricWebhookCertificateLastReloadTimestampSeconds),
+				Help: "Time of the last successful sync of the webhook certificates in seconds since the Unix epoch",
+			},
+			func() float64 {
+				return timestampSeconds(rotator.LastSuccessfulSync(), nil)
+			},
+		),
+		failureCount: prometheus.NewCounterFunc(
+			prometheus.CounterOpts{
+				Name: util.CreateValidMetricNameLabel(prefix, common.MetricWebhookCertificateReloadFailureCount),
+				Help: "Total number of failed syncs of the webhook certificates",
+			},
+			func() float64 {
+				return float64(rotator.SyncFailures())
+			},
+		),
+	}
+}
+
+func (m *WebhookCertificateMetrics) Register() {
+	if err := metrics.Registry.Register(m.caExpiry); err != nil {
+		logger.Error(err, "Failed to register webhook certificate metric", "name", common.MetricWebhookCertificateExpiryTimestampSeconds)
+	}
+	if err := metrics.Registry.Register(m.servingExpiry); err != nil {
+		logger.Error(err, "Failed to register webhook certificate metric", "name", common.MetricWebhookCertificateExpiryTimestampSeconds)
+	}
+	if err := metrics.Registry.Register(m.lastReload); err != nil {
+		logger.Error(err, "Failed to register webhook certificate metric", "name", common.MetricWebhookCertificateLastReloadTimestampSeconds)
+	}
+	if err := metrics.Registry.Register(m.failureCount); err != nil {
+		logger.Error(err, "Failed to register webhook certificate metric", "name", common.MetricWebhookCertificateReloadFailureCount)
+	}
+}
+
+// timestampSeconds returns the given time in seconds since the Unix epoch, or zero if it is unknown.
+func timestampSeconds(t time.Time, err error) float64 {
+	if err != nil || t.IsZero() {
+		return 0
+	}
+	return float64(t.UnixNano()) / float64(time.Second)
+}
@@ -0,0 +1,20 @@
+/*
+Copyright 2024 The Kubeflow authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package query maintains secondary indexes over the cached SparkApplications and serves queries against them
+// through a REST API, so that SparkApplications can be looked up by state, queue or parent
+// ScheduledSparkApplication without listing and scanning all of them on the client side.
+package query
@@ -0,0 +1,78 @@
+/*
+Copyright 2024 The Kubeflow authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package query
+
+import (
+	"context"
+	"fmt"
+
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/kubeflow/spark-operator/api/v1beta2"
+	"github.com/kubeflow/spark-operator/pkg/common"
+)
+
+const (
+	// IndexKeyState is the index key of SparkApplications by application state.
+	IndexKeyState = "status.applicationState.state"
+
+	// IndexKeyQueue is the index key of SparkApplications by batch scheduler queue.
+	IndexKeyQueue = "spec.batchSchedulerOptions.queue"
+
+	// IndexKeyScheduledApp is the index key of SparkApplications by the name of the ScheduledSparkApplication
+	// which created them.
+	IndexKeyScheduledApp = "metadata.labels.scheduledAppName"
+)
+
+// SetupIndexes registers the secondary indexes over SparkApplications with the given field indexer.
+func SetupIndexes(ctx context.Context, indexer client.FieldIndexer) error {
+	indexes := map[string]client.IndexerFunc{
+		IndexKeyState:        indexByState,
+		IndexKeyQueue:        indexByQueue,
+		IndexKeyScheduledApp: indexByScheduledApp,
+	}
+	for key, fn := range indexes {
+		if err := indexer.IndexField(ctx, &v1beta2.SparkApplication{}, key, fn); err != nil {
+			return fmt.Errorf("failed to index SparkApplications by %s: %v", key, err)
+		}
+	}
+	return nil
+}
+
+func indexByState(obj client.Object) []string {
+	app, ok := obj.(*v1beta2.SparkApplication)
+	if !ok {
+		return nil
+	}
+	return []string{string(app.Status.AppState.State)}
+}
+
+func indexByQueue(obj client.Object) []string {
+	app, ok := obj.(*v1beta2.SparkApplication)
+	if !ok || app.Spec.BatchSchedulerOptions == nil || app.Spec.BatchSchedulerOptions.Queue == nil {
+		return nil
+	}
+	return []string{*app.Spec.BatchSchedulerOptions.Queue}
+}
+
+func indexByScheduledApp(obj client.Object) []string {
+	name, ok := obj.GetLabels()[common.LabelScheduledSparkAppName]
+	if !ok {
+		return nil
+	}
+	return []string{name}
+}
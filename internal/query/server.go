@@ -0,0 +1,145 @@
+/*
+Copyright 2024 The Kubeflow authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package query
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"time"
+
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+
+	"github.com/kubeflow/spark-operator/api/v1beta2"
+	"github.com/kubeflow/spark-operator/pkg/schema"
+)
+
+const (
+	// SparkApplicationsPath is the path of the REST API endpoint listing SparkApplications.
+	SparkApplicationsPath = "/api/v1/sparkapplications"
+
+	// ParamNamespace is the query parameter restricting the results to a namespace.
+	ParamNamespace = "namespace"
+	// ParamState is the query parameter selecting SparkApplications by application state.
+	ParamState = "state"
+	// ParamQueue is the query parameter selecting SparkApplications by batch scheduler queue.
+	ParamQueue = "queue"
+	// ParamScheduledApp is the query parameter selecting SparkApplications by parent ScheduledSparkApplication.
+	ParamScheduledApp = "scheduledApp"
+)
+
+var (
+	logger = logf.Log.WithName("")
+)
+
+// Server serves queries against the indexed SparkApplications in the cache.
+type Server struct {
+	bindAddress string
+	reader      client.Reader
+	quotaReader client.Reader
+}
+
+// NewServer creates a new Server listening on the given address and reading from the given cache. ResourceQuotas
+// are read from quotaReader if not nil, which should not be a cache to avoid watching ResourceQuotas.
+func NewServer(bindAddress string, reader client.Reader, quotaReader client.Reader) *Server {
+	return &Server{
+		bindAddress: bindAddress,
+		reader:      reader,
+		quotaReader: quotaReader,
+	}
+}
+
+// Start implements manager.Runnable.
+func (s *Server) Start(ctx context.Context) error {
+	server := &http.Server{
+		Addr:              s.bindAddress,
+		Handler:           s.Handler(),
+		ReadHeaderTimeout: 10 * time.Second,
+	}
+
+	go func() {
+		<-ctx.Done()
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		if err := server.Shutdown(shutdownCtx); err != nil {
+			logger.Error(err, "Failed to shut down query server")
+		}
+	}()
+
+	logger.Info("Starting query server", "address", s.bindAddress)
+	if err := server.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+		return err
+	}
+	return nil
+}
+
+// NeedLeaderElection implements manager.LeaderElectionRunnable. Queries are served by every replica.
+func (s *Server) NeedLeaderElection() bool {
+	return false
+}
+
+// Handler returns the HTTP handler of the REST API, which also serves the tenant reports and the schemas of the CustomResourceDefinitions.
+func (s *Server) Handler() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc(SparkApplicationsPath, s.listSparkApplications)
+	mux.HandleFunc(TenantsPath, s.reportTenants)
+	mux.Handle(schema.SchemasPath, schema.Handler())
+	mux.Handle(schema.SchemasPath+"/", schema.Handler())
+	return mux
+}
+
+func (s *Server) listSparkApplications(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	query := r.URL.Query()
+	opts := []client.ListOption{}
+	if namespace := query.Get(ParamNamespace); namespace != "" {
+		opts = append(opts, client.InNamespace(namespace))
+	}
+	fields := client.MatchingFields{}
+	if query.Has(ParamState) {
+		fields[IndexKeyState] = query.Get(ParamState)
+	}
+	if queue := query.Get(ParamQueue); queue != "" {
+		fields[IndexKeyQueue] = queue
+	}
+	if scheduledApp := query.Get(ParamScheduledApp); scheduledApp != "" {
+		fields[IndexKeyScheduledApp] = scheduledApp
+	}
+	if len(fields) > 0 {
+		opts = append(opts, fields)
+	}
+
+	apps := &v1beta2.SparkApplicationList{}
+	if err := s.reader.List(r.Context(), apps, opts...); err != nil {
+		logger.Error(err, "Failed to list SparkApplications", "query", r.URL.RawQuery)
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	apps.APIVersion = v1beta2.SchemeGroupVersion.String()
+	apps.Kind = "SparkApplicationList"
+
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(apps); err != nil {
+		logger.Error(err, "Failed to write SparkApplications", "query", r.URL.RawQuery)
+	}
+}
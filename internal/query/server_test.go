@@ -0,0 +1,116 @@
+/*
+Copyright 2024 The Kubeflow authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package query
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	"github.com/kubeflow/spark-operator/api/v1beta2"
+	"github.com/kubeflow/spark-operator/pkg/common"
+)
+
+func newTestApp(namespace, name string, state v1beta2.ApplicationStateType, queue string) *v1beta2.SparkApplication {
+	app := &v1beta2.SparkApplication{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: namespace,
+		},
+		Status: v1beta2.SparkApplicationStatus{
+			AppState: v1beta2.ApplicationState{State: state},
+		},
+	}
+	if queue != "" {
+		app.Spec.BatchSchedulerOptions = &v1beta2.BatchSchedulerConfiguration{Queue: ptr.To(queue)}
+	}
+	return app
+}
+
+func TestListSparkApplications(t *testing.T) {
+	scheme := runtime.NewScheme()
+	require.NoError(t, v1beta2.AddToScheme(scheme))
+
+	scheduled := newTestApp("ns1", "scheduled-run", v1beta2.ApplicationStateRunning, "")
+	scheduled.Labels = map[string]string{common.LabelScheduledSparkAppName: "nightly"}
+
+	c := fake.NewClientBuilder().
+		WithScheme(scheme).
+		WithObjects(
+			newTestApp("ns1", "app1", v1beta2.ApplicationStateRunning, "queue-a"),
+			newTestApp("ns2", "app2", v1beta2.ApplicationStateRunning, "queue-a"),
+			newTestApp("ns1", "app3", v1beta2.ApplicationStateCompleted, "queue-a"),
+			newTestApp("ns1", "app4", v1beta2.ApplicationStateRunning, "queue-b"),
+			scheduled,
+		).
+		WithIndex(&v1beta2.SparkApplication{}, IndexKeyState, indexByState).
+		WithIndex(&v1beta2.SparkApplication{}, IndexKeyQueue, indexByQueue).
+		WithIndex(&v1beta2.SparkApplication{}, IndexKeyScheduledApp, indexByScheduledApp).
+		Build()
+	handler := NewServer(":0", c, nil).Handler()
+
+	testCases := []struct {
+		name     string
+		query    string
+		expected []string
+	}{
+		{
+			name:     "all applications",
+			query:    "",
+			expected: []string{"app1", "app2", "app3", "app4", "scheduled-run"},
+		},
+		{
+			name:     "running applications in queue",
+			query:    "?state=RUNNING&queue=queue-a",
+			expected: []string{"app1", "app2"},
+		},
+		{
+			name:     "running applications in queue and namespace",
+			query:    "?state=RUNNING&queue=queue-a&namespace=ns1",
+			expected: []string{"app1"},
+		},
+		{
+			name:     "applications of scheduled application",
+			query:    "?scheduledApp=nightly",
+			expected: []string{"scheduled-run"},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			recorder := httptest.NewRecorder()
+			handler.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, SparkApplicationsPath+tc.query, nil))
+			require.Equal(t, http.StatusOK, recorder.Code)
+
+			apps := &v1beta2.SparkApplicationList{}
+			require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), apps))
+			var names []string
+			for _, app := range apps.Items {
+				names = append(names, app.Name)
+			}
+			assert.ElementsMatch(t, tc.expected, names)
+		})
+	}
+}
@@ -0,0 +1,185 @@
+/*
+Copyright 2024 The Kubeflow authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package query
+
+import (
+	"encoding/json"
+	"net/http"
+	"sort"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/kubeflow/spark-operator/api/v1beta2"
+	"github.com/kubeflow/spark-operator/pkg/util"
+)
+
+const (
+	// TenantsPath is the path of the REST API endpoint reporting the usage and the applications of tenants.
+	TenantsPath = "/api/v1/tenants"
+
+	// ParamGroupBy is the query parameter selecting what tenants are, either namespaces or batch scheduler queues.
+	ParamGroupBy = "groupBy"
+	// GroupByNamespace groups SparkApplications into tenants by namespace.
+	GroupByNamespace = "namespace"
+	// GroupByQueue groups SparkApplications into tenants by batch scheduler queue.
+	GroupByQueue = "queue"
+
+	// successRateWindow is the window of termination times over which the success rates of tenants are computed.
+	successRateWindow = 24 * time.Hour
+)
+
+// TenantReport reports the usage and the applications of a tenant, i.e., a namespace or a batch scheduler queue.
+type TenantReport struct {
+	// Name is the name of the namespace or the queue.
+	Name string `json:"name"`
+	// Requests is the sum of the resource requests of the submitted and running applications of the tenant.
+	Requests corev1.ResourceList `json:"requests,omitempty"`
+	// Quotas are the ResourceQuotas of the namespace, only reported for tenants grouped by namespace.
+	Quotas []QuotaReport `json:"quotas,omitempty"`
+	// Queued is the number of applications waiting to be submitted or for their driver to start.
+	Queued int `json:"queued"`
+	// Running is the number of applications with a running driver.
+	Running int `json:"running"`
+	// Failed is the number of applications which failed or failed to be submitted.
+	Failed int `json:"failed"`
+	// Succeeded24h is the number of applications which completed within the last 24 hours.
+	Succeeded24h int `json:"succeeded24h"`
+	// Failed24h is the number of applications which failed within the last 24 hours.
+	Failed24h int `json:"failed24h"`
+	// SuccessRate24h is the ratio of the applications which completed among the applications which terminated
+	// within the last 24 hours, unset if none terminated.
+	SuccessRate24h *float64 `json:"successRate24h,omitempty"`
+}
+
+// QuotaReport reports the hard limits and the usage of a ResourceQuota.
+type QuotaReport struct {
+	Name string              `json:"name"`
+	Hard corev1.ResourceList `json:"hard,omitempty"`
+	Used corev1.ResourceList `json:"used,omitempty"`
+}
+
+func (s *Server) reportTenants(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	query := r.URL.Query()
+	groupBy := query.Get(ParamGroupBy)
+	if groupBy == "" {
+		groupBy = GroupByNamespace
+	}
+	if groupBy != GroupByNamespace && groupBy != GroupByQueue {
+		http.Error(w, "groupBy must be one of namespace and queue", http.StatusBadRequest)
+		return
+	}
+	opts := []client.ListOption{}
+	if namespace := query.Get(ParamNamespace); namespace != "" {
+		opts = append(opts, client.InNamespace(namespace))
+	}
+
+	apps := &v1beta2.SparkApplicationList{}
+	if err := s.reader.List(r.Context(), apps, opts...); err != nil {
+		logger.Error(err, "Failed to list SparkApplications", "query", r.URL.RawQuery)
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	now := time.Now()
+	tenants := make(map[string]*TenantReport)
+	requests := make(map[string][]corev1.ResourceList)
+	for i := range apps.Items {
+		app := &apps.Items[i]
+		name := app.Namespace
+		if groupBy == GroupByQueue {
+			if app.Spec.BatchSchedulerOptions == nil || app.Spec.BatchSchedulerOptions.Queue == nil {
+				continue
+			}
+			name = *app.Spec.BatchSchedulerOptions.Queue
+		}
+		tenant, ok := tenants[name]
+		if !ok {
+			tenant = &TenantReport{Name: name}
+			tenants[name] = tenant
+		}
+
+		switch util.GetApplicationState(app) {
+		case v1beta2.ApplicationStateNew, v1beta2.ApplicationStateWaitingForDependencies, v1beta2.ApplicationStatePendingRerun:
+			tenant.Queued++
+		case v1beta2.ApplicationStateSubmitted:
+			tenant.Queued++
+			requests[name] = append(requests[name], getRequests(app))
+		case v1beta2.ApplicationStateRunning, v1beta2.ApplicationStateSucceeding, v1beta2.ApplicationStateFailing:
+			tenant.Running++
+			requests[name] = append(requests[name], getRequests(app))
+		case v1beta2.ApplicationStateFailed, v1beta2.ApplicationStateFailedSubmission:
+			tenant.Failed++
+			if now.Sub(app.Status.TerminationTime.Time) <= successRateWindow {
+				tenant.Failed24h++
+			}
+		case v1beta2.ApplicationStateCompleted:
+			if now.Sub(app.Status.TerminationTime.Time) <= successRateWindow {
+				tenant.Succeeded24h++
+			}
+		}
+	}
+
+	for name, tenant := range tenants {
+		if len(requests[name]) > 0 {
+			tenant.Requests = util.SumResourceList(requests[name])
+		}
+		if terminated := tenant.Succeeded24h + tenant.Failed24h; terminated > 0 {
+			rate := float64(tenant.Succeeded24h) / float64(terminated)
+			tenant.SuccessRate24h = &rate
+		}
+	}
+
+	if groupBy == GroupByNamespace && s.quotaReader != nil {
+		quotas := &corev1.ResourceQuotaList{}
+		if err := s.quotaReader.List(r.Context(), quotas, opts...); err != nil {
+			// Quotas are best effort, as the operator may not be allowed to list them.
+			logger.Error(err, "Failed to list resource quotas", "query", r.URL.RawQuery)
+		}
+		for _, quota := range quotas.Items {
+			if tenant, ok := tenants[quota.Namespace]; ok {
+				tenant.Quotas = append(tenant.Quotas, QuotaReport{Name: quota.Name, Hard: quota.Status.Hard, Used: quota.Status.Used})
+			}
+		}
+	}
+
+	report := make([]*TenantReport, 0, len(tenants))
+	for _, tenant := range tenants {
+		report = append(report, tenant)
+	}
+	sort.Slice(report, func(i, j int) bool { return report[i].Name < report[j].Name })
+
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(report); err != nil {
+		logger.Error(err, "Failed to write tenant report", "query", r.URL.RawQuery)
+	}
+}
+
+// getRequests returns the resource requests of the driver and the executors of the given SparkApplication.
+func getRequests(app *v1beta2.SparkApplication) corev1.ResourceList {
+	lists := []corev1.ResourceList{util.GetDriverRequestResource(app)}
+	if app.Spec.Executor.Instances != nil {
+		lists = append(lists, util.GetExecutorRequestResource(app))
+	}
+	return util.SumResourceList(lists)
+}
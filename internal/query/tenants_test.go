@@ -0,0 +1,108 @@
+/*
+Copyright 2024 The Kubeflow authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package query
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	"github.com/kubeflow/spark-operator/api/v1beta2"
+)
+
+func TestReportTenants(t *testing.T) {
+	scheme := runtime.NewScheme()
+	require.NoError(t, v1beta2.AddToScheme(scheme))
+	require.NoError(t, corev1.AddToScheme(scheme))
+
+	running := newTestApp("ns1", "running", v1beta2.ApplicationStateRunning, "queue-a")
+	running.Spec.Driver.Cores = ptr.To[int32](1)
+	running.Spec.Executor.Cores = ptr.To[int32](2)
+	running.Spec.Executor.Instances = ptr.To[int32](2)
+	terminated := func(name string, state v1beta2.ApplicationStateType, age time.Duration) *v1beta2.SparkApplication {
+		app := newTestApp("ns1", name, state, "queue-a")
+		app.Status.TerminationTime = metav1.NewTime(time.Now().Add(-age))
+		return app
+	}
+	quota := &corev1.ResourceQuota{
+		ObjectMeta: metav1.ObjectMeta{Name: "quota", Namespace: "ns1"},
+		Status: corev1.ResourceQuotaStatus{
+			Hard: corev1.ResourceList{corev1.ResourceRequestsCPU: resource.MustParse("10")},
+			Used: corev1.ResourceList{corev1.ResourceRequestsCPU: resource.MustParse("5")},
+		},
+	}
+
+	c := fake.NewClientBuilder().
+		WithScheme(scheme).
+		WithObjects(
+			running,
+			newTestApp("ns1", "new", v1beta2.ApplicationStateNew, ""),
+			terminated("completed", v1beta2.ApplicationStateCompleted, time.Hour),
+			terminated("failed", v1beta2.ApplicationStateFailed, time.Hour),
+			terminated("completed-long-ago", v1beta2.ApplicationStateCompleted, 48*time.Hour),
+			newTestApp("ns2", "other", v1beta2.ApplicationStateRunning, "queue-b"),
+			quota,
+		).
+		Build()
+	handler := NewServer(":0", c, c).Handler()
+
+	get := func(query string) []TenantReport {
+		recorder := httptest.NewRecorder()
+		handler.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, TenantsPath+query, nil))
+		require.Equal(t, http.StatusOK, recorder.Code)
+		var report []TenantReport
+		require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &report))
+		return report
+	}
+
+	report := get("")
+	require.Len(t, report, 2)
+	ns1 := report[0]
+	assert.Equal(t, "ns1", ns1.Name)
+	assert.Equal(t, 1, ns1.Queued)
+	assert.Equal(t, 1, ns1.Running)
+	assert.Equal(t, 1, ns1.Failed)
+	assert.Equal(t, 1, ns1.Succeeded24h)
+	assert.Equal(t, 1, ns1.Failed24h)
+	require.NotNil(t, ns1.SuccessRate24h)
+	assert.InDelta(t, 0.5, *ns1.SuccessRate24h, 1e-9)
+	assert.Equal(t, "5", ns1.Requests.Cpu().String())
+	require.Len(t, ns1.Quotas, 1)
+	assert.Equal(t, "10", ns1.Quotas[0].Hard.Name(corev1.ResourceRequestsCPU, resource.DecimalSI).String())
+	assert.Nil(t, report[1].SuccessRate24h)
+
+	report = get("?groupBy=queue")
+	require.Len(t, report, 2)
+	assert.Equal(t, "queue-a", report[0].Name)
+	assert.Equal(t, 0, report[0].Queued)
+	assert.Empty(t, report[0].Quotas)
+
+	recorder := httptest.NewRecorder()
+	handler.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, TenantsPath+"?groupBy=cluster", nil))
+	assert.Equal(t, http.StatusBadRequest, recorder.Code)
+}
@@ -21,6 +21,7 @@ import (
 	"fmt"
 
 	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
 	"k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/types"
@@ -88,6 +89,9 @@ func (s *Scheduler) ShouldSchedule(app *v1beta2.SparkApplication) bool {
 // Schedule implements scheduler.Interface.
 func (s *Scheduler) Schedule(app *v1beta2.SparkApplication) error {
 	minResources := util.SumResourceList([]corev1.ResourceList{util.GetDriverRequestResource(app), util.GetExecutorRequestResource(app)})
+	if app.Spec.BatchSchedulerOptions != nil && len(app.Spec.BatchSchedulerOptions.Resources) > 0 {
+		minResources = app.Spec.BatchSchedulerOptions.Resources
+	}
 	podGroup := &schedulingv1alpha1.PodGroup{
 		ObjectMeta: metav1.ObjectMeta{
 			Name:      getPodGroupName(app),
@@ -139,7 +143,8 @@ func (s *Scheduler) syncPodGroup(podGroup *schedulingv1alpha1.PodGroup) error {
 		Name:      podGroup.Name,
 	}
 
-	if err := s.client.Get(context.TODO(), key, &schedulingv1alpha1.PodGroup{}); err != nil {
+	existing := &schedulingv1alpha1.PodGroup{}
+	if err := s.client.Get(context.TODO(), key, existing); err != nil {
 		if !errors.IsNotFound(err) {
 			return err
 		}
@@ -151,7 +156,11 @@ func (s *Scheduler) syncPodGroup(podGroup *schedulingv1alpha1.PodGroup) error {
 		return nil
 	}
 
-	if err := s.client.Update(context.TODO(), podGroup); err != nil {
+	if equality.Semantic.DeepEqual(existing.Spec, podGroup.Spec) {
+		return nil
+	}
+	existing.Spec = podGroup.Spec
+	if err := s.client.Update(context.TODO(), existing); err != nil {
 		return err
 	}
 	logger.Info("Updated PodGroup", "Name", podGroup.Name, "Namespace", podGroup.Namespace)
@@ -0,0 +1,79 @@
+/*
+Copyright 2024 The Kubeflow authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package kubescheduler_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	schedulingv1alpha1 "sigs.k8s.io/scheduler-plugins/apis/scheduling/v1alpha1"
+
+	"github.com/kubeflow/spark-operator/api/v1beta2"
+	"github.com/kubeflow/spark-operator/internal/scheduler/kubescheduler"
+)
+
+func newScheduler(t *testing.T) (*kubescheduler.Config, func() *schedulingv1alpha1.PodGroup) {
+	scheme := runtime.NewScheme()
+	require.NoError(t, v1beta2.AddToScheme(scheme))
+	require.NoError(t, schedulingv1alpha1.AddToScheme(scheme))
+	c := fake.NewClientBuilder().WithScheme(scheme).Build()
+
+	getPodGroup := func() *schedulingv1alpha1.PodGroup {
+		podGroup := &schedulingv1alpha1.PodGroup{}
+		require.NoError(t, c.Get(context.TODO(), types.NamespacedName{Namespace: "default", Name: "spark-pi-pg"}, podGroup))
+		return podGroup
+	}
+	return &kubescheduler.Config{SchedulerName: "scheduler-plugins-scheduler", Client: c}, getPodGroup
+}
+
+func TestSchedule(t *testing.T) {
+	config, getPodGroup := newScheduler(t)
+	s, err := kubescheduler.Factory(config)
+	require.NoError(t, err)
+	assert.Equal(t, "scheduler-plugins-scheduler", s.Name())
+
+	app := &v1beta2.SparkApplication{
+		ObjectMeta: metav1.ObjectMeta{Name: "spark-pi", Namespace: "default", UID: "spark-pi-uid"},
+		Spec: v1beta2.SparkApplicationSpec{
+			Executor: v1beta2.ExecutorSpec{Instances: ptr.To[int32](2)},
+		},
+	}
+	require.NoError(t, s.Schedule(app))
+	assert.Equal(t, "spark-pi-pg", app.Labels[schedulingv1alpha1.PodGroupLabel])
+	podGroup := getPodGroup()
+	assert.Equal(t, int32(1), podGroup.Spec.MinMember)
+	assert.Equal(t, "spark-pi", podGroup.OwnerReferences[0].Name)
+
+	// Scheduling the application again updates the existing PodGroup.
+	app.Spec.BatchSchedulerOptions = &v1beta2.BatchSchedulerConfiguration{
+		Resources: corev1.ResourceList{corev1.ResourceCPU: resource.MustParse("4")},
+	}
+	require.NoError(t, s.Schedule(app))
+	assert.Equal(t, corev1.ResourceList{corev1.ResourceCPU: resource.MustParse("4")}, getPodGroup().Spec.MinResources)
+
+	require.NoError(t, s.Cleanup(app))
+	require.NoError(t, s.Cleanup(app))
+}
@@ -17,11 +17,10 @@ limitations under the License.
 package kubescheduler
 
 import (
-	"fmt"
-
 	"github.com/kubeflow/spark-operator/api/v1beta2"
+	"github.com/kubeflow/spark-operator/pkg/naming"
 )
 
 func getPodGroupName(app *v1beta2.SparkApplication) string {
-	return fmt.Sprintf("%s-pg", app.Name)
+	return naming.PodGroupName("", app)
 }
@@ -0,0 +1,69 @@
+/*
+Copyright 2024 The Kubeflow authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package scheduler
+
+import (
+	"fmt"
+	"maps"
+	"sync"
+
+	"k8s.io/client-go/rest"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+var (
+	plugins   = make(map[string]Factory)
+	pluginsMu sync.Mutex
+)
+
+// PluginConfig is the configuration passed to the factories of scheduler plugins.
+type PluginConfig struct {
+	// Name is the name the plugin is registered with, which SparkApplications select it by.
+	Name string
+	// Client is the client of the controller manager.
+	Client client.Client
+	// RestConfig is the REST config of the controller manager.
+	RestConfig *rest.Config
+}
+
+// PluginConfig implements Config.
+var _ Config = &PluginConfig{}
+
+// RegisterPlugin registers a batch scheduler implementation compiled into the operator, so that it is available
+// without changes to the controller. It is meant to be called from the init function of the package implementing
+// the scheduler, which the controller command imports for its side effects. The factory is passed a *PluginConfig.
+// RegisterPlugin panics if a plugin with the same name is already registered.
+func RegisterPlugin(name string, factory Factory) {
+	pluginsMu.Lock()
+	defer pluginsMu.Unlock()
+
+	if factory == nil {
+		panic("scheduler: RegisterPlugin factory is nil")
+	}
+	if _, ok := plugins[name]; ok {
+		panic(fmt.Sprintf("scheduler: RegisterPlugin called twice for plugin %s", name))
+	}
+	plugins[name] = factory
+}
+
+// Plugins returns the factories of the registered scheduler plugins by name.
+func Plugins() map[string]Factory {
+	pluginsMu.Lock()
+	defer pluginsMu.Unlock()
+
+	return maps.Clone(plugins)
+}
@@ -18,14 +18,21 @@ package scheduler
 
 import (
 	"fmt"
+	"slices"
 	"sync"
+
+	"k8s.io/client-go/rest"
+	"sigs.k8s.io/controller-runtime/pkg/client"
 )
 
 var registry *Registry
 
-// Registry is a registry of scheduler factories.
+// Registry is a registry of batch schedulers. Each scheduler is registered with its factory and configuration, and
+// is created by the factory the first time it is requested.
 type Registry struct {
-	factories map[string]Factory
+	factories  map[string]Factory
+	configs    map[string]Config
+	schedulers map[string]Interface
 
 	mu sync.Mutex
 }
@@ -33,26 +40,38 @@ type Registry struct {
 func GetRegistry() *Registry {
 	if registry == nil {
 		registry = &Registry{
-			factories: make(map[string]Factory),
+			factories:  make(map[string]Factory),
+			configs:    make(map[string]Config),
+			schedulers: make(map[string]Interface),
 		}
 	}
 	return registry
 }
 
-func (r *Registry) GetScheduler(name string, config Config) (Interface, error) {
+// GetScheduler returns the scheduler registered with the given name, creating it if needed.
+func (r *Registry) GetScheduler(name string) (Interface, error) {
 	r.mu.Lock()
 	defer r.mu.Unlock()
 
+	if scheduler, ok := r.schedulers[name]; ok {
+		return scheduler, nil
+	}
+
 	factory, exists := r.factories[name]
 	if !exists {
 		return nil, fmt.Errorf("scheduler %s not found", name)
 	}
 
-	return factory(config)
+	scheduler, err := factory(r.configs[name])
+	if err != nil {
+		return nil, err
+	}
+	r.schedulers[name] = scheduler
+	return scheduler, nil
 }
 
-// RegisterScheduler registers a scheduler to the manager.
-func (r *Registry) Register(name string, factory Factory) error {
+// Register registers a scheduler with the factory creating it and the configuration passed to the factory.
+func (r *Registry) Register(name string, factory Factory, config Config) error {
 	r.mu.Lock()
 	defer r.mu.Unlock()
 
@@ -61,15 +80,35 @@ func (r *Registry) Register(name string, factory Factory) error {
 	}
 
 	r.factories[name] = factory
+	r.configs[name] = config
 	logger.Info("Registered scheduler", "name", name)
 	return nil
 }
 
-// GetRegisteredSchedulerNames gets the registered scheduler names.
+// RegisterPlugins registers the scheduler plugins, passing each of them a PluginConfig with the given clients.
+func (r *Registry) RegisterPlugins(client client.Client, restConfig *rest.Config) error {
+	for name, factory := range Plugins() {
+		config := &PluginConfig{
+			Name:       name,
+			Client:     client,
+			RestConfig: restConfig,
+		}
+		if err := r.Register(name, factory, config); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// GetRegisteredSchedulerNames gets the registered scheduler names in sorted order.
 func (r *Registry) GetRegisteredSchedulerNames() []string {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
 	var names []string
 	for name := range r.factories {
 		names = append(names, name)
 	}
+	slices.Sort(names)
 	return names
 }
@@ -0,0 +1,72 @@
+/*
+Copyright 2024 The Kubeflow authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package scheduler_test
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/kubeflow/spark-operator/api/v1beta2"
+	"github.com/kubeflow/spark-operator/internal/scheduler"
+)
+
+type fakeScheduler struct {
+	name string
+}
+
+func (s *fakeScheduler) Name() string                                    { return s.name }
+func (s *fakeScheduler) ShouldSchedule(_ *v1beta2.SparkApplication) bool { return true }
+func (s *fakeScheduler) Schedule(_ *v1beta2.SparkApplication) error      { return nil }
+func (s *fakeScheduler) Cleanup(_ *v1beta2.SparkApplication) error       { return nil }
+
+func TestRegistry(t *testing.T) {
+	created := 0
+	factory := func(config scheduler.Config) (scheduler.Interface, error) {
+		created++
+		c, ok := config.(*scheduler.PluginConfig)
+		if !ok {
+			return nil, fmt.Errorf("unexpected config %T", config)
+		}
+		return &fakeScheduler{name: c.Name}, nil
+	}
+
+	scheduler.RegisterPlugin("test-plugin", factory)
+	assert.Panics(t, func() { scheduler.RegisterPlugin("test-plugin", factory) })
+	assert.Contains(t, scheduler.Plugins(), "test-plugin")
+
+	registry := scheduler.GetRegistry()
+	require.NoError(t, registry.RegisterPlugins(nil, nil))
+	assert.Error(t, registry.Register("test-plugin", factory, nil))
+	require.NoError(t, registry.Register("test-scheduler", factory, nil))
+	assert.Equal(t, []string{"test-plugin", "test-scheduler"}, registry.GetRegisteredSchedulerNames())
+
+	s, err := registry.GetScheduler("test-plugin")
+	require.NoError(t, err)
+	assert.Equal(t, "test-plugin", s.Name())
+	again, err := registry.GetScheduler("test-plugin")
+	require.NoError(t, err)
+	assert.Same(t, s, again)
+	assert.Equal(t, 1, created)
+
+	_, err = registry.GetScheduler("test-scheduler")
+	assert.Error(t, err)
+	_, err = registry.GetScheduler("unknown")
+	assert.Error(t, err)
+}
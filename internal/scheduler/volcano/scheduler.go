@@ -19,6 +19,7 @@ package volcano
 import (
 	"context"
 	"fmt"
+	"time"
 
 	corev1 "k8s.io/api/core/v1"
 	apiextensionsclientset "k8s.io/apiextensions-apiserver/pkg/client/clientset/clientset"
@@ -129,6 +130,11 @@ func (s *Scheduler) Schedule(app *v1beta2.SparkApplication) error {
 
 // Cleanup implements batchscheduler.Interface.
 func (s *Scheduler) Cleanup(app *v1beta2.SparkApplication) error {
+	// Keep the PodGroup until its TTL after the application terminated expires.
+	if expiry, ok := util.GetPodGroupExpiry(app); ok && time.Now().Before(expiry) {
+		return nil
+	}
+
 	name := getPodGroupName(app)
 	namespace := app.Namespace
 	if err := s.volcanoClient.SchedulingV1beta1().PodGroups(namespace).Delete(context.TODO(), name, metav1.DeleteOptions{}); err != nil && !errors.IsNotFound(err) {
@@ -141,10 +147,9 @@ func (s *Scheduler) Cleanup(app *v1beta2.SparkApplication) error {
 func (s *Scheduler) syncPodGroupInClientMode(app *v1beta2.SparkApplication) error {
 	// We only care about the executor pods in client mode
 	if _, ok := app.Spec.Executor.Annotations[v1beta1.KubeGroupNameAnnotationKey]; !ok {
-		totalResource := util.GetExecutorRequestResource(app)
-
-		if app.Spec.BatchSchedulerOptions != nil && len(app.Spec.BatchSchedulerOptions.Resources) > 0 {
-			totalResource = app.Spec.BatchSchedulerOptions.Resources
+		totalResource, err := getMinResources(app)
+		if err != nil {
+			return err
 		}
 		if err := s.syncPodGroup(app, 1, totalResource); err == nil {
 			app.Spec.Executor.Annotations[v1beta1.KubeGroupNameAnnotationKey] = getPodGroupName(app)
@@ -160,9 +165,9 @@ func (s *Scheduler) syncPodGroupInClusterMode(app *v1beta2.SparkApplication) err
 	// In cluster mode, the initial size of PodGroup is set to 1 in order to schedule driver pod first.
 	if _, ok := app.Spec.Driver.Annotations[v1beta1.KubeGroupNameAnnotationKey]; !ok {
 		// Both driver and executor resource will be considered.
-		totalResource := util.SumResourceList([]corev1.ResourceList{util.GetDriverRequestResource(app), util.GetExecutorRequestResource(app)})
-		if app.Spec.BatchSchedulerOptions != nil && len(app.Spec.BatchSchedulerOptions.Resources) > 0 {
-			totalResource = app.Spec.BatchSchedulerOptions.Resources
+		totalResource, err := getMinResources(app)
+		if err != nil {
+			return err
 		}
 
 		if err := s.syncPodGroup(app, 1, totalResource); err != nil {
@@ -180,6 +185,15 @@ func (s *Scheduler) syncPodGroup(app *v1beta2.SparkApplication, size int32, minR
 	name := getPodGroupName(app)
 	namespace := app.Namespace
 
+	var queue, priorityClassName string
+	if app.Spec.BatchSchedulerOptions != nil && app.Spec.BatchSchedulerOptions.Queue != nil {
+		// Update pod group queue if it's specified in Spark Application
+		queue = *app.Spec.BatchSchedulerOptions.Queue
+	}
+	if className := util.GetPodGroupPriorityClassName(app); className != nil {
+		priorityClassName = *className
+	}
+
 	if pg, err = s.volcanoClient.SchedulingV1beta1().PodGroups(namespace).Get(context.TODO(), name, metav1.GetOptions{}); err != nil {
 		if !errors.IsNotFound(err) {
 			return err
@@ -194,30 +208,18 @@ func (s *Scheduler) syncPodGroup(app *v1beta2.SparkApplication, size int32, minR
 				},
 			},
 			Spec: v1beta1.PodGroupSpec{
-				MinMember:    size,
-				MinResources: &minResource,
+				MinMember:         size,
+				MinResources:      &minResource,
+				Queue:             queue,
+				PriorityClassName: priorityClassName,
 			},
 			Status: v1beta1.PodGroupStatus{
 				Phase: v1beta1.PodGroupPending,
 			},
 		}
-
-		if app.Spec.BatchSchedulerOptions != nil {
-			// Update pod group queue if it's specified in Spark Application
-			if app.Spec.BatchSchedulerOptions.Queue != nil {
-				podGroup.Spec.Queue = *app.Spec.BatchSchedulerOptions.Queue
-			}
-			// Update pod group priorityClassName if it's specified in Spark Application
-			if app.Spec.BatchSchedulerOptions.PriorityClassName != nil {
-				podGroup.Spec.PriorityClassName = *app.Spec.BatchSchedulerOptions.PriorityClassName
-			}
-		}
 		_, err = s.volcanoClient.SchedulingV1beta1().PodGroups(namespace).Create(context.TODO(), &podGroup, metav1.CreateOptions{})
-	} else {
-		if pg.Spec.MinMember != size {
-			pg.Spec.MinMember = size
-			_, err = s.volcanoClient.SchedulingV1beta1().PodGroups(namespace).Update(context.TODO(), pg, metav1.UpdateOptions{})
-		}
+	} else if updatePodGroupSpec(&pg.Spec, size, minResource, queue, priorityClassName) {
+		_, err = s.volcanoClient.SchedulingV1beta1().PodGroups(namespace).Update(context.TODO(), pg, metav1.UpdateOptions{})
 	}
 
 	if err != nil {
@@ -19,9 +19,89 @@ package volcano
 import (
 	"fmt"
 
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
+	"k8s.io/apimachinery/pkg/api/resource"
+	"volcano.sh/apis/pkg/apis/scheduling/v1beta1"
+
 	"github.com/kubeflow/spark-operator/api/v1beta2"
+	"github.com/kubeflow/spark-operator/internal/scheduler/yunikorn/resourceusage"
+	"github.com/kubeflow/spark-operator/pkg/naming"
+	"github.com/kubeflow/spark-operator/pkg/util"
 )
 
 func getPodGroupName(app *v1beta2.SparkApplication) string {
-	return fmt.Sprintf("spark-%s-pg", app.Name)
+	return naming.PodGroupName("spark", app)
+}
+
+// getMinResources returns the minimum resources of the PodGroup of the application, i.e. the resources requested
+// by the driver in cluster mode and by the initial executors, including the memory overhead added by Spark, unless
+// the resources are specified in the batch scheduler options.
+func getMinResources(app *v1beta2.SparkApplication) (corev1.ResourceList, error) {
+	if app.Spec.BatchSchedulerOptions != nil && len(app.Spec.BatchSchedulerOptions.Resources) > 0 {
+		return app.Spec.BatchSchedulerOptions.Resources, nil
+	}
+
+	resourceLists := []corev1.ResourceList{{}}
+	if app.Spec.Mode != v1beta2.DeployModeClient {
+		requests, err := resourceusage.DriverPodRequests(app)
+		if err != nil {
+			return nil, fmt.Errorf("failed to calculate driver minResources: %v", err)
+		}
+		driverResources, err := toResourceList(requests)
+		if err != nil {
+			return nil, err
+		}
+		resourceLists = append(resourceLists, driverResources)
+	}
+
+	if numInitialExecutors := util.GetInitialExecutorNumber(app); numInitialExecutors > 0 {
+		requests, err := resourceusage.ExecutorPodRequests(app)
+		if err != nil {
+			return nil, fmt.Errorf("failed to calculate executor minResources: %v", err)
+		}
+		executorResources, err := toResourceList(requests)
+		if err != nil {
+			return nil, err
+		}
+		for i := int32(0); i < numInitialExecutors; i++ {
+			resourceLists = append(resourceLists, executorResources)
+		}
+	}
+	return util.SumResourceList(resourceLists), nil
+}
+
+func toResourceList(requests map[string]string) (corev1.ResourceList, error) {
+	resources := corev1.ResourceList{}
+	for name, value := range requests {
+		quantity, err := resource.ParseQuantity(value)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse %s request %s: %v", name, value, err)
+		}
+		resources[corev1.ResourceName(name)] = quantity
+	}
+	return resources, nil
+}
+
+// updatePodGroupSpec updates the given PodGroup spec to the given settings, and returns whether it changed. The queue
+// and priority class are left untouched if not specified, as Volcano may have defaulted them.
+func updatePodGroupSpec(spec *v1beta1.PodGroupSpec, size int32, minResource corev1.ResourceList, queue string, priorityClassName string) bool {
+	changed := false
+	if spec.MinMember != size {
+		spec.MinMember = size
+		changed = true
+	}
+	if spec.MinResources == nil || !equality.Semantic.DeepEqual(*spec.MinResources, minResource) {
+		spec.MinResources = &minResource
+		changed = true
+	}
+	if queue != "" && spec.Queue != queue {
+		spec.Queue = queue
+		changed = true
+	}
+	if priorityClassName != "" && spec.PriorityClassName != priorityClassName {
+		spec.PriorityClassName = priorityClassName
+		changed = true
+	}
+	return changed
 }
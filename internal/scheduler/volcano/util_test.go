@@ -0,0 +1,89 @@
+/*
+Copyright 2024 The Kubeflow authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package volcano
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	"k8s.io/utils/ptr"
+	"volcano.sh/apis/pkg/apis/scheduling/v1beta1"
+
+	"github.com/kubeflow/spark-operator/api/v1beta2"
+)
+
+func TestGetMinResources(t *testing.T) {
+	app := &v1beta2.SparkApplication{
+		Spec: v1beta2.SparkApplicationSpec{
+			Type: v1beta2.SparkApplicationTypeScala,
+			Mode: v1beta2.DeployModeCluster,
+			Driver: v1beta2.DriverSpec{
+				SparkPodSpec: v1beta2.SparkPodSpec{Cores: ptr.To[int32](1), Memory: ptr.To("1g")},
+			},
+			Executor: v1beta2.ExecutorSpec{
+				SparkPodSpec: v1beta2.SparkPodSpec{Cores: ptr.To[int32](2), Memory: ptr.To("2g"), MemoryOverhead: ptr.To("1g")},
+				Instances:    ptr.To[int32](2),
+			},
+		},
+	}
+
+	// The driver memory overhead defaults to max(10% of the memory, 384Mi).
+	resources, err := getMinResources(app)
+	require.NoError(t, err)
+	assert.True(t, resource.MustParse("5").Equal(resources[corev1.ResourceCPU]))
+	assert.True(t, resource.MustParse("7552Mi").Equal(resources[corev1.ResourceMemory]), resources.Memory().String())
+
+	// The initial executors of dynamic allocation are taken into account.
+	app.Spec.Executor.Instances = nil
+	app.Spec.DynamicAllocation = &v1beta2.DynamicAllocation{Enabled: true, InitialExecutors: ptr.To[int32](1)}
+	resources, err = getMinResources(app)
+	require.NoError(t, err)
+	assert.True(t, resource.MustParse("3").Equal(resources[corev1.ResourceCPU]))
+
+	// Only the executors are taken into account in client mode.
+	app.Spec.Mode = v1beta2.DeployModeClient
+	resources, err = getMinResources(app)
+	require.NoError(t, err)
+	assert.True(t, resource.MustParse("2").Equal(resources[corev1.ResourceCPU]))
+	assert.True(t, resource.MustParse("3Gi").Equal(resources[corev1.ResourceMemory]))
+
+	app.Spec.BatchSchedulerOptions = &v1beta2.BatchSchedulerConfiguration{
+		Resources: corev1.ResourceList{corev1.ResourceCPU: resource.MustParse("10")},
+	}
+	resources, err = getMinResources(app)
+	require.NoError(t, err)
+	assert.Equal(t, app.Spec.BatchSchedulerOptions.Resources, resources)
+}
+
+func TestUpdatePodGroupSpec(t *testing.T) {
+	minResource := corev1.ResourceList{corev1.ResourceCPU: resource.MustParse("2")}
+	spec := &v1beta1.PodGroupSpec{MinMember: 1, MinResources: &minResource, Queue: "default"}
+
+	assert.False(t, updatePodGroupSpec(spec, 1, minResource, "", ""))
+	assert.Equal(t, "default", spec.Queue)
+
+	assert.True(t, updatePodGroupSpec(spec, 1, minResource, "batch", "high-priority"))
+	assert.Equal(t, "batch", spec.Queue)
+	assert.Equal(t, "high-priority", spec.PriorityClassName)
+
+	newResource := corev1.ResourceList{corev1.ResourceCPU: resource.MustParse("4")}
+	assert.True(t, updatePodGroupSpec(spec, 1, newResource, "batch", "high-priority"))
+	assert.Equal(t, newResource, *spec.MinResources)
+}
@@ -22,6 +22,7 @@ import (
 	"k8s.io/apimachinery/pkg/api/resource"
 
 	"github.com/kubeflow/spark-operator/api/v1beta2"
+	"github.com/kubeflow/spark-operator/pkg/util"
 )
 
 func cpuRequest(cores *int32, coreRequest *string) (string, error) {
@@ -41,6 +42,17 @@ func cpuRequest(cores *int32, coreRequest *string) (string, error) {
 	return "1", nil
 }
 
+func addEphemeralStorageRequest(requests map[string]string, podSpec *v1beta2.SparkPodSpec) error {
+	ephemeralStorage, _, err := util.GetEphemeralStorageResources(podSpec)
+	if err != nil {
+		return err
+	}
+	for name, quantity := range ephemeralStorage {
+		requests[name.String()] = quantity.String()
+	}
+	return nil
+}
+
 func DriverPodRequests(app *v1beta2.SparkApplication) (map[string]string, error) {
 	cpuValue, err := cpuRequest(app.Spec.Driver.Cores, app.Spec.Driver.CoreRequest)
 	if err != nil {
@@ -52,10 +64,14 @@ func DriverPodRequests(app *v1beta2.SparkApplication) (map[string]string, error)
 		return nil, err
 	}
 
-	return map[string]string{
+	requests := map[string]string{
 		"cpu":    cpuValue,
 		"memory": memoryValue,
-	}, nil
+	}
+	if err := addEphemeralStorageRequest(requests, &app.Spec.Driver.SparkPodSpec); err != nil {
+		return nil, err
+	}
+	return requests, nil
 }
 
 func ExecutorPodRequests(app *v1beta2.SparkApplication) (map[string]string, error) {
@@ -69,8 +85,12 @@ func ExecutorPodRequests(app *v1beta2.SparkApplication) (map[string]string, erro
 		return nil, err
 	}
 
-	return map[string]string{
+	requests := map[string]string{
 		"cpu":    cpuValue,
 		"memory": memoryValue,
-	}, nil
+	}
+	if err := addEphemeralStorageRequest(requests, &app.Spec.Executor.SparkPodSpec); err != nil {
+		return nil, err
+	}
+	return requests, nil
 }
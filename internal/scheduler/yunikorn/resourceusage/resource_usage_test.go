@@ -21,6 +21,7 @@ import (
 
 	"github.com/stretchr/testify/assert"
 
+	"github.com/kubeflow/spark-operator/api/v1beta2"
 	"github.com/kubeflow/spark-operator/pkg/util"
 )
 
@@ -55,3 +56,20 @@ func TestCpuRequestInvalid(t *testing.T) {
 		assert.NotNil(t, err)
 	}
 }
+
+func TestExecutorPodRequestsEphemeralStorage(t *testing.T) {
+	app := &v1beta2.SparkApplication{
+		Spec: v1beta2.SparkApplicationSpec{
+			Executor: v1beta2.ExecutorSpec{
+				SparkPodSpec: v1beta2.SparkPodSpec{
+					Memory:           util.StringPtr("1g"),
+					EphemeralStorage: util.StringPtr("10Gi"),
+				},
+			},
+		},
+	}
+
+	requests, err := ExecutorPodRequests(app)
+	assert.Nil(t, err)
+	assert.Equal(t, "10Gi", requests["ephemeral-storage"])
+}
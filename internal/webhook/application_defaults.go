@@ -0,0 +1,83 @@
+/*
+Copyright 2024 The Kubeflow authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package webhook
+
+import (
+	"fmt"
+	"os"
+
+	"sigs.k8s.io/yaml"
+
+	"github.com/kubeflow/spark-operator/api/v1beta2"
+	"github.com/kubeflow/spark-operator/pkg/util"
+)
+
+// ApplicationDefaults are the operator-level defaults applied to the fields a SparkApplication leaves unset, so that
+// users can submit minimal specs while cluster admins control the baseline.
+type ApplicationDefaults struct {
+	// Image is the default container image of the driver and executors.
+	Image *string `json:"image,omitempty"`
+	// ImagePullPolicy is the default image pull policy.
+	ImagePullPolicy *string `json:"imagePullPolicy,omitempty"`
+	// ImagePullSecrets are the default image pull secrets.
+	ImagePullSecrets []string `json:"imagePullSecrets,omitempty"`
+	// ServiceAccount is the default service account of the driver.
+	ServiceAccount *string `json:"serviceAccount,omitempty"`
+	// RestartPolicy is the default restart policy.
+	RestartPolicy *v1beta2.RestartPolicy `json:"restartPolicy,omitempty"`
+	// Monitoring is the default monitoring configuration.
+	Monitoring *v1beta2.MonitoringSpec `json:"monitoring,omitempty"`
+	// SparkConf holds Spark configuration properties added unless already set.
+	SparkConf map[string]string `json:"sparkConf,omitempty"`
+}
+
+// LoadApplicationDefaults loads the application defaults from the given YAML file.
+func LoadApplicationDefaults(path string) (*ApplicationDefaults, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read application defaults file %s: %v", path, err)
+	}
+
+	defaults := &ApplicationDefaults{}
+	if err := yaml.UnmarshalStrict(data, defaults); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal application defaults file %s: %v", path, err)
+	}
+	return defaults, nil
+}
+
+// apply applies the defaults to the fields the application leaves unset.
+func (d *ApplicationDefaults) apply(app *v1beta2.SparkApplication) {
+	if app.Spec.Image == nil && d.Image != nil {
+		app.Spec.Image = util.StringPtr(*d.Image)
+	}
+	if app.Spec.ImagePullPolicy == nil && d.ImagePullPolicy != nil {
+		app.Spec.ImagePullPolicy = util.StringPtr(*d.ImagePullPolicy)
+	}
+	if len(app.Spec.ImagePullSecrets) == 0 && len(d.ImagePullSecrets) > 0 {
+		app.Spec.ImagePullSecrets = append([]string(nil), d.ImagePullSecrets...)
+	}
+	if app.Spec.Driver.ServiceAccount == nil && d.ServiceAccount != nil {
+		app.Spec.Driver.ServiceAccount = util.StringPtr(*d.ServiceAccount)
+	}
+	if app.Spec.RestartPolicy.Type == "" && d.RestartPolicy != nil {
+		app.Spec.RestartPolicy = *d.RestartPolicy.DeepCopy()
+	}
+	if app.Spec.Monitoring == nil && d.Monitoring != nil {
+		app.Spec.Monitoring = d.Monitoring.DeepCopy()
+	}
+	app.Spec.SparkConf = mergeDefaults(app.Spec.SparkConf, d.SparkConf)
+}
@@ -0,0 +1,107 @@
+/*
+Copyright 2024 The Kubeflow authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package webhook
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/kubeflow/spark-operator/api/v1beta2"
+	"github.com/kubeflow/spark-operator/pkg/util"
+)
+
+func TestLoadApplicationDefaults(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "application-defaults.yaml")
+	data := `
+image: spark:3.5.3
+serviceAccount: spark
+restartPolicy:
+  type: OnFailure
+  onFailureRetries: 3
+sparkConf:
+  spark.eventLog.enabled: "true"
+`
+	require.NoError(t, os.WriteFile(path, []byte(data), 0644))
+
+	defaults, err := LoadApplicationDefaults(path)
+	require.NoError(t, err)
+	assert.Equal(t, "spark:3.5.3", *defaults.Image)
+	assert.Equal(t, "spark", *defaults.ServiceAccount)
+	assert.Equal(t, v1beta2.RestartPolicyOnFailure, defaults.RestartPolicy.Type)
+	assert.Equal(t, map[string]string{"spark.eventLog.enabled": "true"}, defaults.SparkConf)
+	assert.Nil(t, defaults.Monitoring)
+
+	require.NoError(t, os.WriteFile(path, []byte("unknown: true\n"), 0644))
+	_, err = LoadApplicationDefaults(path)
+	assert.Error(t, err)
+}
+
+func TestApplicationDefaults_Apply(t *testing.T) {
+	defaults := &ApplicationDefaults{
+		Image:            util.StringPtr("spark:3.5.3"),
+		ImagePullPolicy:  util.StringPtr("IfNotPresent"),
+		ImagePullSecrets: []string{"registry"},
+		ServiceAccount:   util.StringPtr("spark"),
+		RestartPolicy: &v1beta2.RestartPolicy{
+			Type:             v1beta2.RestartPolicyOnFailure,
+			OnFailureRetries: util.Int32Ptr(3),
+		},
+		Monitoring: &v1beta2.MonitoringSpec{ExposeDriverMetrics: true},
+		SparkConf: map[string]string{
+			"spark.eventLog.enabled": "true",
+			"spark.eventLog.dir":     "s3a://logs/",
+		},
+	}
+
+	minimal := &v1beta2.SparkApplication{}
+	defaults.apply(minimal)
+	assert.Equal(t, "spark:3.5.3", *minimal.Spec.Image)
+	assert.Equal(t, "IfNotPresent", *minimal.Spec.ImagePullPolicy)
+	assert.Equal(t, []string{"registry"}, minimal.Spec.ImagePullSecrets)
+	assert.Equal(t, "spark", *minimal.Spec.Driver.ServiceAccount)
+	assert.Equal(t, v1beta2.RestartPolicyOnFailure, minimal.Spec.RestartPolicy.Type)
+	assert.Equal(t, int32(3), *minimal.Spec.RestartPolicy.OnFailureRetries)
+	assert.True(t, minimal.Spec.Monitoring.ExposeDriverMetrics)
+	assert.Equal(t, defaults.SparkConf, minimal.Spec.SparkConf)
+
+	// Defaults must not be shared with the application.
+	*minimal.Spec.Image = "changed"
+	minimal.Spec.Monitoring.ExposeDriverMetrics = false
+	assert.Equal(t, "spark:3.5.3", *defaults.Image)
+	assert.True(t, defaults.Monitoring.ExposeDriverMetrics)
+
+	app := &v1beta2.SparkApplication{
+		Spec: v1beta2.SparkApplicationSpec{
+			Image:         util.StringPtr("custom:latest"),
+			RestartPolicy: v1beta2.RestartPolicy{Type: v1beta2.RestartPolicyNever},
+			SparkConf:     map[string]string{"spark.eventLog.enabled": "false"},
+			Driver: v1beta2.DriverSpec{
+				SparkPodSpec: v1beta2.SparkPodSpec{ServiceAccount: util.StringPtr("custom")},
+			},
+		},
+	}
+	defaults.apply(app)
+	assert.Equal(t, "custom:latest", *app.Spec.Image)
+	assert.Equal(t, "custom", *app.Spec.Driver.ServiceAccount)
+	assert.Equal(t, v1beta2.RestartPolicyNever, app.Spec.RestartPolicy.Type)
+	assert.Nil(t, app.Spec.RestartPolicy.OnFailureRetries)
+	assert.Equal(t, map[string]string{"spark.eventLog.enabled": "false", "spark.eventLog.dir": "s3a://logs/"}, app.Spec.SparkConf)
+}
@@ -0,0 +1,238 @@
+/*
+Copyright 2024 The Kubeflow authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package webhook
+
+import (
+	"fmt"
+	"os"
+	"slices"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"sigs.k8s.io/yaml"
+
+	"github.com/kubeflow/spark-operator/api/v1beta2"
+	"github.com/kubeflow/spark-operator/pkg/common"
+	"github.com/kubeflow/spark-operator/pkg/util"
+)
+
+// PodDefaults are the defaults applied to Spark pods on top of the SparkApplication spec, e.g. the node selectors,
+// tolerations and annotations that cluster-autoscaler and Karpenter need to provision nodes from scale-from-zero
+// node pools for large executor fan-outs.
+type PodDefaults struct {
+	// Driver holds the defaults applied to driver pods.
+	Driver RolePodDefaults `json:"driver,omitempty"`
+	// Executor holds the defaults applied to executor pods.
+	Executor RolePodDefaults `json:"executor,omitempty"`
+	// Sidecars are injected into the driver and executor pods matching their selectors, e.g. a secrets agent or
+	// a log shipper, so that applications do not need to repeat them in their specs.
+	Sidecars []SidecarInjection `json:"sidecars,omitempty"`
+}
+
+// RolePodDefaults are the defaults applied to Spark pods of a given role. Labels, annotations and node selectors
+// are only added if the pod does not set the same key, and tolerations are only added if the pod does not have
+// an identical toleration.
+type RolePodDefaults struct {
+	Labels       map[string]string   `json:"labels,omitempty"`
+	Annotations  map[string]string   `json:"annotations,omitempty"`
+	NodeSelector map[string]string   `json:"nodeSelector,omitempty"`
+	Tolerations  []corev1.Toleration `json:"tolerations,omitempty"`
+}
+
+// SidecarInjection holds the containers, init containers and volumes injected into the Spark pods matching the
+// selector. Containers, init containers and volumes are only added if the pod does not have one of the same name.
+type SidecarInjection struct {
+	// Name identifies the injection.
+	Name string `json:"name"`
+	// Selector selects the pods by their labels, e.g. `spark-role: executor`. All driver and executor pods are
+	// selected if unset.
+	Selector       *metav1.LabelSelector `json:"selector,omitempty"`
+	Containers     []corev1.Container    `json:"containers,omitempty"`
+	InitContainers []corev1.Container    `json:"initContainers,omitempty"`
+	Volumes        []corev1.Volume       `json:"volumes,omitempty"`
+}
+
+// LoadPodDefaults loads the pod defaults from the given YAML file.
+func LoadPodDefaults(path string) (*PodDefaults, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read pod defaults file %s: %v", path, err)
+	}
+
+	defaults := &PodDefaults{}
+	if err := yaml.UnmarshalStrict(data, defaults); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal pod defaults file %s: %v", path, err)
+	}
+	for _, sidecar := range defaults.Sidecars {
+		if _, err := metav1.LabelSelectorAsSelector(sidecar.Selector); err != nil {
+			return nil, fmt.Errorf("invalid selector of sidecar injection %s: %v", sidecar.Name, err)
+		}
+	}
+	return defaults, nil
+}
+
+// apply applies the defaults of the role of the pod to the pod, except for the kinds of defaults the application
+// disables. The kinds of defaults applied and suppressed are recorded in annotations on driver pods.
+func (d *PodDefaults) apply(pod *corev1.Pod, app *v1beta2.SparkApplication) {
+	var defaults RolePodDefaults
+	if util.IsDriverPod(pod) {
+		defaults = d.Driver
+	} else if util.IsExecutorPod(pod) {
+		defaults = d.Executor
+	} else {
+		return
+	}
+
+	var applied, suppressed []v1beta2.InjectedDefault
+	// record tracks whether defaults of the given kind are configured for the pod and injects them if not disabled.
+	record := func(kind v1beta2.InjectedDefault, configured bool, inject func()) {
+		if !configured {
+			return
+		}
+		if app != nil && slices.Contains(app.Spec.DisableDefaults, kind) {
+			suppressed = append(suppressed, kind)
+			return
+		}
+		inject()
+		applied = append(applied, kind)
+	}
+
+	record(v1beta2.InjectedDefaultMetadata, len(defaults.Labels) > 0 || len(defaults.Annotations) > 0, func() {
+		pod.Labels = mergeDefaults(pod.Labels, defaults.Labels)
+		pod.Annotations = mergeDefaults(pod.Annotations, defaults.Annotations)
+	})
+	record(v1beta2.InjectedDefaultNodeSelector, len(defaults.NodeSelector) > 0, func() {
+		pod.Spec.NodeSelector = mergeDefaults(pod.Spec.NodeSelector, defaults.NodeSelector)
+	})
+	record(v1beta2.InjectedDefaultTolerations, len(defaults.Tolerations) > 0, func() {
+		for _, toleration := range defaults.Tolerations {
+			if !hasToleration(pod, toleration) {
+				pod.Spec.Tolerations = append(pod.Spec.Tolerations, toleration)
+			}
+		}
+	})
+
+	var sidecars []SidecarInjection
+	for _, sidecar := range d.Sidecars {
+		if sidecar.matches(pod) {
+			sidecars = append(sidecars, sidecar)
+		}
+	}
+	record(v1beta2.InjectedDefaultSidecars, len(sidecars) > 0, func() {
+		for _, sidecar := range sidecars {
+			sidecar.inject(pod)
+		}
+	})
+
+	// The driver pod records them for the status of the application.
+	if util.IsDriverPod(pod) {
+		setDefaultsAnnotation(pod, common.AnnotationAppliedDefaults, applied)
+		setDefaultsAnnotation(pod, common.AnnotationSuppressedDefaults, suppressed)
+	}
+}
+
+// setDefaultsAnnotation records the given kinds of defaults in the annotation with the given key on the pod.
+func setDefaultsAnnotation(pod *corev1.Pod, key string, kinds []v1beta2.InjectedDefault) {
+	if len(kinds) == 0 {
+		return
+	}
+	values := make([]string, 0, len(kinds))
+	for _, kind := range kinds {
+		values = append(values, string(kind))
+	}
+	if pod.Annotations == nil {
+		pod.Annotations = make(map[string]string)
+	}
+	pod.Annotations[key] = strings.Join(values, ",")
+}
+
+// matches returns whether the pod matches the selector of the sidecar injection.
+func (s *SidecarInjection) matches(pod *corev1.Pod) bool {
+	if s.Selector == nil {
+		return true
+	}
+	selector, err := metav1.LabelSelectorAsSelector(s.Selector)
+	if err != nil {
+		logger.Error(err, "Invalid selector of sidecar injection", "sidecar", s.Name)
+		return false
+	}
+	return selector.Matches(labels.Set(pod.Labels))
+}
+
+// inject injects the sidecar into the pod.
+func (s *SidecarInjection) inject(pod *corev1.Pod) {
+	for _, container := range s.Containers {
+		if !hasContainerNamed(pod.Spec.Containers, container.Name) {
+			pod.Spec.Containers = append(pod.Spec.Containers, *container.DeepCopy())
+		}
+	}
+	for _, container := range s.InitContainers {
+		if !hasContainerNamed(pod.Spec.InitContainers, container.Name) {
+			pod.Spec.InitContainers = append(pod.Spec.InitContainers, *container.DeepCopy())
+		}
+	}
+	for _, volume := range s.Volumes {
+		if !hasVolumeNamed(pod, volume.Name) {
+			pod.Spec.Volumes = append(pod.Spec.Volumes, *volume.DeepCopy())
+		}
+	}
+}
+
+// mergeDefaults adds the default entries whose keys are not present to m.
+func mergeDefaults(m map[string]string, defaults map[string]string) map[string]string {
+	if len(defaults) == 0 {
+		return m
+	}
+	if m == nil {
+		m = make(map[string]string)
+	}
+	for key, value := range defaults {
+		if _, ok := m[key]; !ok {
+			m[key] = value
+		}
+	}
+	return m
+}
+
+func hasToleration(pod *corev1.Pod, toleration corev1.Toleration) bool {
+	for _, t := range pod.Spec.Tolerations {
+		if t.MatchToleration(&toleration) {
+			return true
+		}
+	}
+	return false
+}
+
+func hasContainerNamed(containers []corev1.Container, name string) bool {
+	for _, c := range containers {
+		if c.Name == name {
+			return true
+		}
+	}
+	return false
+}
+
+func hasVolumeNamed(pod *corev1.Pod, name string) bool {
+	for _, v := range pod.Spec.Volumes {
+		if v.Name == name {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,199 @@
+/*
+Copyright 2024 The Kubeflow authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package webhook
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/kubeflow/spark-operator/api/v1beta2"
+	"github.com/kubeflow/spark-operator/pkg/common"
+)
+
+func TestLoadPodDefaults(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "pod-defaults.yaml")
+	data := `
+executor:
+  nodeSelector:
+    karpenter.sh/nodepool: spark-executors
+  tolerations:
+  - key: dedicated
+    operator: Equal
+    value: spark
+    effect: NoSchedule
+`
+	require.NoError(t, os.WriteFile(path, []byte(data), 0644))
+
+	defaults, err := LoadPodDefaults(path)
+	require.NoError(t, err)
+	assert.Equal(t, map[string]string{"karpenter.sh/nodepool": "spark-executors"}, defaults.Executor.NodeSelector)
+	assert.Len(t, defaults.Executor.Tolerations, 1)
+	assert.Empty(t, defaults.Driver.NodeSelector)
+
+	require.NoError(t, os.WriteFile(path, []byte("executor:\n  unknown: true\n"), 0644))
+	_, err = LoadPodDefaults(path)
+	assert.Error(t, err)
+
+	invalidSelector := `
+sidecars:
+- name: log-shipper
+  selector:
+    matchExpressions:
+    - key: spark-role
+      operator: Unknown
+`
+	require.NoError(t, os.WriteFile(path, []byte(invalidSelector), 0644))
+	_, err = LoadPodDefaults(path)
+	assert.Error(t, err)
+}
+
+func TestPodDefaults_Apply(t *testing.T) {
+	toleration := corev1.Toleration{
+		Key:      "dedicated",
+		Operator: corev1.TolerationOpEqual,
+		Value:    "spark",
+		Effect:   corev1.TaintEffectNoSchedule,
+	}
+	defaults := &PodDefaults{
+		Driver: RolePodDefaults{
+			Annotations: map[string]string{"cluster-autoscaler.kubernetes.io/safe-to-evict": "false"},
+		},
+		Executor: RolePodDefaults{
+			Labels:       map[string]string{"team": "data"},
+			NodeSelector: map[string]string{"karpenter.sh/nodepool": "spark-executors", "zone": "a"},
+			Tolerations:  []corev1.Toleration{toleration},
+		},
+	}
+
+	executor := &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Labels: map[string]string{common.LabelSparkRole: common.SparkRoleExecutor},
+		},
+		Spec: corev1.PodSpec{
+			NodeSelector: map[string]string{"zone": "b"},
+			Tolerations:  []corev1.Toleration{toleration},
+		},
+	}
+	defaults.apply(executor, nil)
+	assert.Equal(t, "data", executor.Labels["team"])
+	assert.Equal(t, map[string]string{"karpenter.sh/nodepool": "spark-executors", "zone": "b"}, executor.Spec.NodeSelector)
+	assert.Len(t, executor.Spec.Tolerations, 1)
+	assert.Empty(t, executor.Annotations)
+
+	driver := &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Labels: map[string]string{common.LabelSparkRole: common.SparkRoleDriver},
+		},
+	}
+	defaults.apply(driver, nil)
+	assert.Equal(t, "false", driver.Annotations["cluster-autoscaler.kubernetes.io/safe-to-evict"])
+	assert.Empty(t, driver.Spec.NodeSelector)
+	assert.Empty(t, driver.Spec.Tolerations)
+}
+
+func TestPodDefaults_ApplyDisabledDefaults(t *testing.T) {
+	defaults := &PodDefaults{
+		Driver: RolePodDefaults{
+			Labels:       map[string]string{"team": "data"},
+			NodeSelector: map[string]string{"karpenter.sh/nodepool": "spark-drivers"},
+		},
+		Sidecars: []SidecarInjection{
+			{
+				Name:       "log-shipper",
+				Containers: []corev1.Container{{Name: "log-shipper", Image: "shipper:1.0"}},
+			},
+		},
+	}
+	app := &v1beta2.SparkApplication{
+		Spec: v1beta2.SparkApplicationSpec{
+			DisableDefaults: []v1beta2.InjectedDefault{v1beta2.InjectedDefaultNodeSelector, v1beta2.InjectedDefaultSidecars},
+		},
+	}
+
+	driver := &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Labels: map[string]string{common.LabelSparkRole: common.SparkRoleDriver},
+		},
+		Spec: corev1.PodSpec{
+			Containers: []corev1.Container{{Name: common.SparkDriverContainerName}},
+		},
+	}
+	defaults.apply(driver, app)
+	assert.Equal(t, "data", driver.Labels["team"])
+	assert.Empty(t, driver.Spec.NodeSelector)
+	assert.Len(t, driver.Spec.Containers, 1)
+	assert.Equal(t, "metadata", driver.Annotations[common.AnnotationAppliedDefaults])
+	assert.Equal(t, "nodeSelector,sidecars", driver.Annotations[common.AnnotationSuppressedDefaults])
+}
+
+func TestPodDefaults_ApplySidecars(t *testing.T) {
+	defaults := &PodDefaults{
+		Sidecars: []SidecarInjection{
+			{
+				Name:           "secrets-agent",
+				InitContainers: []corev1.Container{{Name: "secrets-init", Image: "agent:1.0"}},
+				Containers:     []corev1.Container{{Name: "secrets-agent", Image: "agent:1.0"}},
+				Volumes:        []corev1.Volume{{Name: "secrets", VolumeSource: corev1.VolumeSource{EmptyDir: &corev1.EmptyDirVolumeSource{}}}},
+			},
+			{
+				Name:       "log-shipper",
+				Selector:   &metav1.LabelSelector{MatchLabels: map[string]string{common.LabelSparkRole: common.SparkRoleExecutor}},
+				Containers: []corev1.Container{{Name: "log-shipper", Image: "shipper:1.0"}},
+			},
+		},
+	}
+
+	driver := &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Labels: map[string]string{common.LabelSparkRole: common.SparkRoleDriver},
+		},
+		Spec: corev1.PodSpec{
+			Containers: []corev1.Container{{Name: common.SparkDriverContainerName}},
+		},
+	}
+	defaults.apply(driver, nil)
+	assert.Len(t, driver.Spec.Containers, 2)
+	assert.Equal(t, "secrets-agent", driver.Spec.Containers[1].Name)
+	assert.Len(t, driver.Spec.InitContainers, 1)
+	assert.Len(t, driver.Spec.Volumes, 1)
+
+	executor := &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Labels: map[string]string{common.LabelSparkRole: common.SparkRoleExecutor},
+		},
+		Spec: corev1.PodSpec{
+			Containers: []corev1.Container{
+				{Name: common.SparkExecutorContainerName},
+				{Name: "secrets-agent", Image: "agent:custom"},
+			},
+		},
+	}
+	defaults.apply(executor, nil)
+	assert.Len(t, executor.Spec.Containers, 3)
+	assert.Equal(t, "agent:custom", executor.Spec.Containers[1].Image)
+	assert.Equal(t, "log-shipper", executor.Spec.Containers[2].Name)
+
+	other := &corev1.Pod{}
+	defaults.apply(other, nil)
+	assert.Empty(t, other.Spec.Containers)
+}
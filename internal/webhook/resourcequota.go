@@ -244,6 +244,31 @@ func parseJavaMemoryString(s string) (int64, error) {
 	return 0, fmt.Errorf("could not parse string '%s' as a Java-style memory value. Examples: 100kb, 1.5mb, 1g", s)
 }
 
+// getPodResourceList returns the resource requests and limits of the containers of the given pod under the names
+// ResourceQuotas account for them with.
+func getPodResourceList(pod *corev1.Pod) corev1.ResourceList {
+	var lists []corev1.ResourceList
+	for _, container := range pod.Spec.Containers {
+		list := corev1.ResourceList{}
+		if quantity, ok := container.Resources.Requests[corev1.ResourceCPU]; ok {
+			list[corev1.ResourceCPU] = quantity
+			list[corev1.ResourceRequestsCPU] = quantity
+		}
+		if quantity, ok := container.Resources.Requests[corev1.ResourceMemory]; ok {
+			list[corev1.ResourceMemory] = quantity
+			list[corev1.ResourceRequestsMemory] = quantity
+		}
+		if quantity, ok := container.Resources.Limits[corev1.ResourceCPU]; ok {
+			list[corev1.ResourceLimitsCPU] = quantity
+		}
+		if quantity, ok := container.Resources.Limits[corev1.ResourceMemory]; ok {
+			list[corev1.ResourceLimitsMemory] = quantity
+		}
+		lists = append(lists, list)
+	}
+	return util.SumResourceList(lists)
+}
+
 // Check whether the resource list will satisfy the resource quota.
 func validateResourceQuota(resourceList corev1.ResourceList, resourceQuota corev1.ResourceQuota) bool {
 	for key, quantity := range resourceList {
@@ -18,11 +18,13 @@ package webhook
 
 import (
 	"context"
+	"fmt"
 
 	"k8s.io/apimachinery/pkg/runtime"
 	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
 
 	"github.com/kubeflow/spark-operator/api/v1beta2"
+	"github.com/kubeflow/spark-operator/pkg/util"
 )
 
 // NOTE: The 'path' attribute must follow a specific pattern and should not be modified directly here.
@@ -45,7 +47,7 @@ func (v *ScheduledSparkApplicationValidator) ValidateCreate(ctx context.Context,
 		return nil, nil
 	}
 	logger.Info("Validating SchedulingSparkApplication create", "name", app.Name, "namespace", app.Namespace)
-	if err := v.validate(app); err != nil {
+	if err := v.validate(ctx, app); err != nil {
 		return nil, err
 	}
 	return nil, nil
@@ -58,7 +60,7 @@ func (v *ScheduledSparkApplicationValidator) ValidateUpdate(ctx context.Context,
 		return nil, nil
 	}
 	logger.Info("Validating SchedulingSparkApplication update", "name", newApp.Name, "namespace", newApp.Namespace)
-	if err := v.validate(newApp); err != nil {
+	if err := v.validate(ctx, newApp); err != nil {
 		return nil, err
 	}
 	return nil, nil
@@ -74,7 +76,22 @@ func (v *ScheduledSparkApplicationValidator) ValidateDelete(ctx context.Context,
 	return nil, nil
 }
 
-func (v *ScheduledSparkApplicationValidator) validate(_ *v1beta2.ScheduledSparkApplication) error {
-	// TODO: implement validate logic
+func (v *ScheduledSparkApplicationValidator) validate(ctx context.Context, app *v1beta2.ScheduledSparkApplication) error {
+	if _, err := util.ParseSchedule(&app.Spec, nil); err != nil {
+		return err
+	}
+
+	// A template referencing a SparkApplicationTemplate is only complete once merged into the created runs.
+	if app.Spec.Template.TemplateRef != nil {
+		return nil
+	}
+
+	sparkApp := &v1beta2.SparkApplication{
+		ObjectMeta: app.ObjectMeta,
+		Spec:       app.Spec.Template,
+	}
+	if err := (&SparkApplicationValidator{}).validateSpec(ctx, sparkApp); err != nil {
+		return fmt.Errorf("invalid template: %v", err)
+	}
 	return nil
 }
@@ -0,0 +1,60 @@
+/*
+Copyright 2024 The Kubeflow authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package webhook
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/ptr"
+
+	"github.com/kubeflow/spark-operator/api/v1beta2"
+)
+
+func TestScheduledSparkApplicationValidator_Validate(t *testing.T) {
+	newApp := func(schedule string, memory string) *v1beta2.ScheduledSparkApplication {
+		return &v1beta2.ScheduledSparkApplication{
+			ObjectMeta: metav1.ObjectMeta{Name: "spark-pi-scheduled", Namespace: "default"},
+			Spec: v1beta2.ScheduledSparkApplicationSpec{
+				Schedule: schedule,
+				Template: v1beta2.SparkApplicationSpec{
+					Type:                v1beta2.SparkApplicationTypeScala,
+					MainApplicationFile: ptr.To("local:///opt/spark/examples/jars/spark-examples.jar"),
+					Driver: v1beta2.DriverSpec{
+						SparkPodSpec: v1beta2.SparkPodSpec{Memory: ptr.To(memory)},
+					},
+				},
+			},
+		}
+	}
+
+	validator := NewScheduledSparkApplicationValidator()
+	assert.NoError(t, validator.validate(context.TODO(), newApp("@every 10m", "512m")))
+	assert.Error(t, validator.validate(context.TODO(), newApp("every ten minutes", "512m")))
+	assert.Error(t, validator.validate(context.TODO(), newApp("@every 10m", "512 MB")))
+	assert.NoError(t, validator.validate(context.TODO(), newApp("@lastbusinessday 18:30", "512m")))
+
+	app := newApp("@every 10m", "512m")
+	app.Spec.TimeZone = ptr.To("Mars/Olympus_Mons")
+	assert.Error(t, validator.validate(context.TODO(), app))
+
+	app = newApp("@every 10m", "512 MB")
+	app.Spec.Template.TemplateRef = &v1beta2.SparkApplicationTemplateReference{Name: "spark-pi-template"}
+	assert.NoError(t, validator.validate(context.TODO(), app))
+}
@@ -0,0 +1,132 @@
+/*
+Copyright 2024 The Kubeflow authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package webhook
+
+import (
+	"fmt"
+	"os"
+	"slices"
+	"strings"
+
+	"sigs.k8s.io/yaml"
+
+	"github.com/kubeflow/spark-operator/api/v1beta2"
+	"github.com/kubeflow/spark-operator/pkg/common"
+)
+
+// SparkConfPolicy is the operator-level policy on the Spark configuration properties of SparkApplications, so that
+// cluster admins can keep users from e.g. turning off authentication or event logging.
+type SparkConfPolicy struct {
+	// Forbidden are the Spark configuration properties SparkApplications are not allowed to set. A key ending with
+	// `*` forbids every property starting with the rest of the key. Hadoop configuration properties are matched with
+	// the `spark.hadoop.` prefix.
+	Forbidden []string `json:"forbidden,omitempty"`
+	// Enforced holds Spark configuration properties set on every SparkApplication, overriding the values set by users.
+	Enforced map[string]string `json:"enforced,omitempty"`
+}
+
+// LoadSparkConfPolicy loads the Spark configuration policy from the given YAML file.
+func LoadSparkConfPolicy(path string) (*SparkConfPolicy, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read Spark conf policy file %s: %v", path, err)
+	}
+
+	policy := &SparkConfPolicy{}
+	if err := yaml.UnmarshalStrict(data, policy); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal Spark conf policy file %s: %v", path, err)
+	}
+	for key := range policy.Enforced {
+		if policy.isForbidden(key) {
+			return nil, fmt.Errorf("invalid Spark conf policy file %s: enforced property %s is forbidden", path, key)
+		}
+	}
+	return policy, nil
+}
+
+// apply sets the enforced properties on the application and returns the keys of the properties whose values
+// were overridden.
+func (p *SparkConfPolicy) apply(app *v1beta2.SparkApplication) []string {
+	if len(p.Enforced) == 0 {
+		return nil
+	}
+	if app.Spec.SparkConf == nil {
+		app.Spec.SparkConf = make(map[string]string)
+	}
+
+	var overridden []string
+	for key, value := range p.Enforced {
+		if old, ok := app.Spec.SparkConf[key]; ok && old != value {
+			overridden = append(overridden, key)
+		}
+		app.Spec.SparkConf[key] = value
+	}
+	slices.Sort(overridden)
+	return overridden
+}
+
+// validate returns an error listing the properties of the application that are forbidden or that do not have
+// their enforced values.
+func (p *SparkConfPolicy) validate(app *v1beta2.SparkApplication) error {
+	var forbidden, overridden []string
+	for key, value := range app.Spec.SparkConf {
+		if p.isForbidden(key) {
+			forbidden = append(forbidden, key)
+		}
+		if enforced, ok := p.Enforced[key]; ok && enforced != value {
+			overridden = append(overridden, key)
+		}
+	}
+	for key := range app.Spec.HadoopConf {
+		if p.isForbidden(common.SparkHadoopPrefix + key) {
+			forbidden = append(forbidden, common.SparkHadoopPrefix+key)
+		}
+	}
+	for key := range p.Enforced {
+		if _, ok := app.Spec.SparkConf[key]; !ok {
+			overridden = append(overridden, key)
+		}
+	}
+
+	var violations []string
+	if len(forbidden) > 0 {
+		slices.Sort(forbidden)
+		violations = append(violations, fmt.Sprintf("forbidden properties %s", strings.Join(forbidden, ", ")))
+	}
+	if len(overridden) > 0 {
+		slices.Sort(overridden)
+		violations = append(violations, fmt.Sprintf("properties %s must have their enforced values", strings.Join(overridden, ", ")))
+	}
+	if len(violations) > 0 {
+		return fmt.Errorf("sparkConf violates the Spark conf policy of the operator: %s", strings.Join(violations, "; "))
+	}
+	return nil
+}
+
+// isForbidden returns whether the given Spark configuration property is forbidden.
+func (p *SparkConfPolicy) isForbidden(key string) bool {
+	for _, pattern := range p.Forbidden {
+		if prefix, ok := strings.CutSuffix(pattern, "*"); ok {
+			if strings.HasPrefix(key, prefix) {
+				return true
+			}
+		} else if key == pattern {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,143 @@
+/*
+Copyright 2024 The Kubeflow authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package webhook
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/kubeflow/spark-operator/api/v1beta2"
+)
+
+func TestLoadSparkConfPolicy(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "spark-conf-policy.yaml")
+	data := `
+forbidden:
+- spark.authenticate
+- spark.kubernetes.authenticate.*
+enforced:
+  spark.eventLog.enabled: "true"
+`
+	require.NoError(t, os.WriteFile(path, []byte(data), 0644))
+
+	policy, err := LoadSparkConfPolicy(path)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"spark.authenticate", "spark.kubernetes.authenticate.*"}, policy.Forbidden)
+	assert.Equal(t, map[string]string{"spark.eventLog.enabled": "true"}, policy.Enforced)
+
+	require.NoError(t, os.WriteFile(path, []byte("unknown: true\n"), 0644))
+	_, err = LoadSparkConfPolicy(path)
+	assert.Error(t, err)
+
+	data = `
+forbidden:
+- spark.eventLog.*
+enforced:
+  spark.eventLog.enabled: "true"
+`
+	require.NoError(t, os.WriteFile(path, []byte(data), 0644))
+	_, err = LoadSparkConfPolicy(path)
+	assert.Error(t, err)
+}
+
+func TestSparkConfPolicy_Apply(t *testing.T) {
+	policy := &SparkConfPolicy{
+		Enforced: map[string]string{
+			"spark.eventLog.enabled": "true",
+			"spark.eventLog.dir":     "s3a://logs/",
+		},
+	}
+
+	app := &v1beta2.SparkApplication{}
+	assert.Empty(t, policy.apply(app))
+	assert.Equal(t, policy.Enforced, app.Spec.SparkConf)
+	assert.NoError(t, policy.validate(app))
+
+	app = &v1beta2.SparkApplication{
+		Spec: v1beta2.SparkApplicationSpec{
+			SparkConf: map[string]string{"spark.eventLog.enabled": "false", "spark.executor.cores": "2"},
+		},
+	}
+	assert.Equal(t, []string{"spark.eventLog.enabled"}, policy.apply(app))
+	assert.Equal(t, map[string]string{
+		"spark.eventLog.enabled": "true",
+		"spark.eventLog.dir":     "s3a://logs/",
+		"spark.executor.cores":   "2",
+	}, app.Spec.SparkConf)
+}
+
+func TestSparkConfPolicy_Validate(t *testing.T) {
+	policy := &SparkConfPolicy{
+		Forbidden: []string{"spark.authenticate", "spark.kubernetes.authenticate.*", "spark.hadoop.fs.s3a.access.key"},
+		Enforced:  map[string]string{"spark.eventLog.enabled": "true"},
+	}
+
+	testCases := []struct {
+		name       string
+		sparkConf  map[string]string
+		hadoopConf map[string]string
+		wantErr    string
+	}{
+		{
+			name:      "compliant",
+			sparkConf: map[string]string{"spark.eventLog.enabled": "true", "spark.executor.cores": "2"},
+		},
+		{
+			name:      "forbidden key",
+			sparkConf: map[string]string{"spark.eventLog.enabled": "true", "spark.authenticate": "false"},
+			wantErr:   "forbidden properties spark.authenticate",
+		},
+		{
+			name:      "forbidden prefix",
+			sparkConf: map[string]string{"spark.eventLog.enabled": "true", "spark.kubernetes.authenticate.driver.serviceAccountName": "admin"},
+			wantErr:   "forbidden properties spark.kubernetes.authenticate.driver.serviceAccountName",
+		},
+		{
+			name:       "forbidden hadoop property",
+			sparkConf:  map[string]string{"spark.eventLog.enabled": "true"},
+			hadoopConf: map[string]string{"fs.s3a.access.key": "key"},
+			wantErr:    "forbidden properties spark.hadoop.fs.s3a.access.key",
+		},
+		{
+			name:      "enforced property overridden",
+			sparkConf: map[string]string{"spark.eventLog.enabled": "false"},
+			wantErr:   "properties spark.eventLog.enabled must have their enforced values",
+		},
+		{
+			name:    "enforced property missing",
+			wantErr: "properties spark.eventLog.enabled must have their enforced values",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			app := &v1beta2.SparkApplication{
+				Spec: v1beta2.SparkApplicationSpec{SparkConf: tc.sparkConf, HadoopConf: tc.hadoopConf},
+			}
+			err := policy.validate(app)
+			if tc.wantErr == "" {
+				assert.NoError(t, err)
+			} else {
+				assert.ErrorContains(t, err, tc.wantErr)
+			}
+		})
+	}
+}
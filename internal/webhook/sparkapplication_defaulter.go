@@ -18,9 +18,15 @@ package webhook
 
 import (
 	"context"
+	"encoding/json"
+	"fmt"
 	"strconv"
 
+	admissionv1 "k8s.io/api/admission/v1"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/strategicpatch"
+	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
 
 	"github.com/kubeflow/spark-operator/api/v1beta2"
@@ -31,11 +37,20 @@ import (
 // +kubebuilder:webhook:admissionReviewVersions=v1,failurePolicy=fail,groups=sparkoperator.k8s.io,matchPolicy=Exact,mutating=true,name=mutate-sparkapplication.sparkoperator.k8s.io,path=/mutate-sparkoperator-k8s-io-v1beta2-sparkapplication,reinvocationPolicy=Never,resources=sparkapplications,sideEffects=None,verbs=create;update,versions=v1beta2,webhookVersions=v1
 
 // SparkApplicationDefaulter sets default values for a SparkApplication.
-type SparkApplicationDefaulter struct{}
+type SparkApplicationDefaulter struct {
+	client              client.Client
+	applicationDefaults *ApplicationDefaults
+	sparkConfPolicy     *SparkConfPolicy
+}
 
-// NewSparkApplicationValidator creates a new SparkApplicationValidator instance.
-func NewSparkApplicationDefaulter() *SparkApplicationDefaulter {
-	return &SparkApplicationDefaulter{}
+// NewSparkApplicationDefaulter creates a new SparkApplicationDefaulter instance. The application defaults and the
+// Spark conf policy are optional.
+func NewSparkApplicationDefaulter(client client.Client, applicationDefaults *ApplicationDefaults, sparkConfPolicy *SparkConfPolicy) *SparkApplicationDefaulter {
+	return &SparkApplicationDefaulter{
+		client:              client,
+		applicationDefaults: applicationDefaults,
+		sparkConfPolicy:     sparkConfPolicy,
+	}
 }
 
 // SparkApplicationDefaulter implements admission.CustomDefaulter.
@@ -54,11 +69,94 @@ func (d *SparkApplicationDefaulter) Default(ctx context.Context, obj runtime.Obj
 		return nil
 	}
 
-	logger.Info("Defaulting SparkApplication", "name", app.Name, "namespace", app.Namespace, "state", util.GetApplicationState(app))
+	logger.Info("Defaulting SparkApplication", "name", app.Name, "namespace", app.Namespace, "correlationID", util.GetCorrelationID(app), "state", util.GetApplicationState(app))
+	if err := d.applySparkApplicationTemplate(ctx, app); err != nil {
+		return err
+	}
+	if d.applicationDefaults != nil {
+		d.applicationDefaults.apply(app)
+	}
+	if d.sparkConfPolicy != nil {
+		if overridden := d.sparkConfPolicy.apply(app); len(overridden) > 0 {
+			logger.Info("Overrode Spark configuration properties enforced by the Spark conf policy", "name", app.Name, "namespace", app.Namespace, "properties", overridden)
+		}
+	}
 	defaultSparkApplication(app)
 	return nil
 }
 
+// DefaultAndValidateSpec defaults and validates the spec of the application like the webhook, for specs changed
+// outside of admission such as those returned by the pre-submission hooks of the controller. The
+// SparkApplicationTemplate is not applied again and the resource usage is not checked against resource quotas.
+func DefaultAndValidateSpec(ctx context.Context, app *v1beta2.SparkApplication, applicationDefaults *ApplicationDefaults, sparkConfPolicy *SparkConfPolicy) error {
+	if applicationDefaults != nil {
+		applicationDefaults.apply(app)
+	}
+	if sparkConfPolicy != nil {
+		sparkConfPolicy.apply(app)
+	}
+	defaultSparkApplication(app)
+	return NewSparkApplicationValidator(nil, false, sparkConfPolicy).validateSpec(ctx, app)
+}
+
+// applySparkApplicationTemplate merges the spec of the referenced SparkApplicationTemplate into the application spec.
+// Only the fields set in the submitted application override the template, and the template is only applied on
+// creation, so that updates do not re-apply it over changes made to the application since.
+func (d *SparkApplicationDefaulter) applySparkApplicationTemplate(ctx context.Context, app *v1beta2.SparkApplication) error {
+	if app.Spec.TemplateRef == nil {
+		return nil
+	}
+
+	req, err := admission.RequestFromContext(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to get admission request: %v", err)
+	}
+	if req.Operation != admissionv1.Create {
+		return nil
+	}
+
+	template := &v1beta2.SparkApplicationTemplate{}
+	key := types.NamespacedName{Namespace: app.Namespace, Name: app.Spec.TemplateRef.Name}
+	if err := d.client.Get(ctx, key, template); err != nil {
+		return fmt.Errorf("failed to get SparkApplicationTemplate %s: %v", key, err)
+	}
+
+	submitted := struct {
+		Spec json.RawMessage `json:"spec"`
+	}{}
+	if err := json.Unmarshal(req.Object.Raw, &submitted); err != nil {
+		return fmt.Errorf("failed to decode SparkApplication %s/%s: %v", app.Namespace, app.Name, err)
+	}
+
+	spec, err := mergeSparkApplicationSpec(&template.Spec.Template, submitted.Spec)
+	if err != nil {
+		return fmt.Errorf("failed to apply SparkApplicationTemplate %s: %v", key, err)
+	}
+	app.Spec = *spec
+	return nil
+}
+
+// mergeSparkApplicationSpec merges the overrides, the JSON of a spec holding only the fields to override, into the
+// base spec and returns the merged spec. Maps are merged key by key, while lists and scalar values set in overrides
+// replace those in base. Overrides must not be marshaled from a spec, as that sets every field without omitempty.
+func mergeSparkApplicationSpec(base *v1beta2.SparkApplicationSpec, overrides []byte) (*v1beta2.SparkApplicationSpec, error) {
+	baseBytes, err := json.Marshal(base)
+	if err != nil {
+		return nil, err
+	}
+
+	mergedBytes, err := strategicpatch.StrategicMergePatch(baseBytes, overrides, v1beta2.SparkApplicationSpec{})
+	if err != nil {
+		return nil, err
+	}
+
+	merged := &v1beta2.SparkApplicationSpec{}
+	if err := json.Unmarshal(mergedBytes, merged); err != nil {
+		return nil, err
+	}
+	return merged, nil
+}
+
 // defaultSparkApplication sets default values for certain fields of a SparkApplication.
 func defaultSparkApplication(app *v1beta2.SparkApplication) {
 	if app.Spec.Mode == "" {
@@ -80,6 +178,7 @@ func defaultSparkApplication(app *v1beta2.SparkApplication) {
 
 	defaultDriverSpec(app)
 	defaultExecutorSpec(app)
+	defaultDynamicAllocation(app)
 }
 
 func defaultDriverSpec(app *v1beta2.SparkApplication) {
@@ -100,3 +199,19 @@ func defaultExecutorSpec(app *v1beta2.SparkApplication) {
 		}
 	}
 }
+
+// defaultDynamicAllocation sets the default timeouts of dynamic allocation, unless they are set in the Spark
+// configuration of the SparkApplication.
+func defaultDynamicAllocation(app *v1beta2.SparkApplication) {
+	dynamicAllocation := app.Spec.DynamicAllocation
+	if dynamicAllocation == nil || !dynamicAllocation.Enabled {
+		return
+	}
+
+	if _, ok := app.Spec.SparkConf[common.SparkDynamicAllocationExecutorIdleTimeout]; !ok && dynamicAllocation.ExecutorIdleTimeout == nil {
+		dynamicAllocation.ExecutorIdleTimeout = util.StringPtr(common.DefaultSparkDynamicAllocationExecutorIdleTimeout)
+	}
+	if _, ok := app.Spec.SparkConf[common.SparkDynamicAllocationSchedulerBacklogTimeout]; !ok && dynamicAllocation.SchedulerBacklogTimeout == nil {
+		dynamicAllocation.SchedulerBacklogTimeout = util.StringPtr(common.DefaultSparkDynamicAllocationSchedulerBacklogTimeout)
+	}
+}
@@ -0,0 +1,188 @@
+/*
+Copyright 2024 The Kubeflow authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package webhook
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	admissionv1 "k8s.io/api/admission/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+
+	"github.com/kubeflow/spark-operator/api/v1beta2"
+	"github.com/kubeflow/spark-operator/pkg/common"
+	"github.com/kubeflow/spark-operator/pkg/util"
+)
+
+func TestMergeSparkApplicationSpec(t *testing.T) {
+	base := &v1beta2.SparkApplicationSpec{
+		Image:     util.StringPtr("spark:3.5.3"),
+		Arguments: []string{"1000"},
+		SparkConf: map[string]string{
+			"spark.eventLog.enabled": "true",
+			"spark.ui.enabled":       "true",
+		},
+		Driver: v1beta2.DriverSpec{
+			SparkPodSpec: v1beta2.SparkPodSpec{
+				Memory:         util.StringPtr("1g"),
+				ServiceAccount: util.StringPtr("spark"),
+			},
+		},
+	}
+	overrides := []byte(`{
+		"type": "Scala",
+		"sparkVersion": "3.5.3",
+		"mainApplicationFile": "local:///opt/spark/examples/jars/spark-examples.jar",
+		"arguments": ["5000"],
+		"sparkConf": {"spark.ui.enabled": "false"},
+		"driver": {"memory": "2g"}
+	}`)
+
+	merged, err := mergeSparkApplicationSpec(base, overrides)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	assert.Equal(t, v1beta2.SparkApplicationTypeScala, merged.Type)
+	assert.Equal(t, "3.5.3", merged.SparkVersion)
+	assert.Equal(t, "spark:3.5.3", *merged.Image)
+	assert.Equal(t, "local:///opt/spark/examples/jars/spark-examples.jar", *merged.MainApplicationFile)
+	assert.Equal(t, []string{"5000"}, merged.Arguments)
+	assert.Equal(t, map[string]string{
+		"spark.eventLog.enabled": "true",
+		"spark.ui.enabled":       "false",
+	}, merged.SparkConf)
+	assert.Equal(t, "2g", *merged.Driver.Memory)
+	assert.Equal(t, "spark", *merged.Driver.ServiceAccount)
+}
+
+func TestDefaultAppliesSparkApplicationTemplate(t *testing.T) {
+	scheme := runtime.NewScheme()
+	require.NoError(t, v1beta2.AddToScheme(scheme))
+	template := &v1beta2.SparkApplicationTemplate{
+		ObjectMeta: metav1.ObjectMeta{Name: "template", Namespace: "default"},
+		Spec: v1beta2.SparkApplicationTemplateSpec{
+			Template: v1beta2.SparkApplicationSpec{
+				Type:         v1beta2.SparkApplicationTypePython,
+				SparkVersion: "3.5.3",
+				Image:        util.StringPtr("spark:3.5.3"),
+				Monitoring: &v1beta2.MonitoringSpec{
+					ExposeDriverMetrics:   true,
+					ExposeExecutorMetrics: true,
+				},
+				DriverIngressOptions: []v1beta2.DriverIngressConfiguration{
+					{ServicePort: util.Int32Ptr(4040), ServicePortName: util.StringPtr("ui")},
+				},
+			},
+		},
+	}
+	defaulter := NewSparkApplicationDefaulter(fake.NewClientBuilder().WithScheme(scheme).WithObjects(template).Build(), nil, nil)
+
+	// The application only sets the main application file, so every other field must come from the template,
+	// including those whose zero values are marshaled without omitempty.
+	newApp := func() *v1beta2.SparkApplication {
+		return &v1beta2.SparkApplication{
+			ObjectMeta: metav1.ObjectMeta{Name: "test-app", Namespace: "default"},
+			Spec: v1beta2.SparkApplicationSpec{
+				TemplateRef:         &v1beta2.SparkApplicationTemplateReference{Name: "template"},
+				MainApplicationFile: util.StringPtr("local:///opt/spark/examples/src/main/python/pi.py"),
+			},
+		}
+	}
+	raw := []byte(`{"apiVersion":"sparkoperator.k8s.io/v1beta2","kind":"SparkApplication",` +
+		`"metadata":{"name":"test-app","namespace":"default"},` +
+		`"spec":{"templateRef":{"name":"template"},"mainApplicationFile":"local:///opt/spark/examples/src/main/python/pi.py"}}`)
+	newContext := func(operation admissionv1.Operation) context.Context {
+		return admission.NewContextWithRequest(context.TODO(), admission.Request{
+			AdmissionRequest: admissionv1.AdmissionRequest{
+				Operation: operation,
+				Object:    runtime.RawExtension{Raw: raw},
+			},
+		})
+	}
+
+	app := newApp()
+	require.NoError(t, defaulter.Default(newContext(admissionv1.Create), app))
+	assert.Equal(t, v1beta2.SparkApplicationTypePython, app.Spec.Type)
+	assert.Equal(t, "3.5.3", app.Spec.SparkVersion)
+	assert.Equal(t, "spark:3.5.3", *app.Spec.Image)
+	assert.Equal(t, "local:///opt/spark/examples/src/main/python/pi.py", *app.Spec.MainApplicationFile)
+	require.NotNil(t, app.Spec.Monitoring)
+	assert.True(t, app.Spec.Monitoring.ExposeDriverMetrics)
+	assert.True(t, app.Spec.Monitoring.ExposeExecutorMetrics)
+	require.Len(t, app.Spec.DriverIngressOptions, 1)
+	assert.Equal(t, int32(4040), *app.Spec.DriverIngressOptions[0].ServicePort)
+
+	// Updates leave the spec alone instead of re-applying the template over it.
+	app = newApp()
+	app.Spec.Image = util.StringPtr("spark:3.5.4")
+	require.NoError(t, defaulter.Default(newContext(admissionv1.Update), app))
+	assert.Equal(t, "spark:3.5.4", *app.Spec.Image)
+	assert.Empty(t, app.Spec.SparkVersion)
+	assert.Nil(t, app.Spec.Monitoring)
+}
+
+func TestDefaultDynamicAllocation(t *testing.T) {
+	app := &v1beta2.SparkApplication{}
+	app.Spec.DynamicAllocation = &v1beta2.DynamicAllocation{Enabled: true}
+	app.Spec.SparkConf = map[string]string{common.SparkDynamicAllocationSchedulerBacklogTimeout: "5s"}
+
+	defaultDynamicAllocation(app)
+
+	assert.Equal(t, util.StringPtr(common.DefaultSparkDynamicAllocationExecutorIdleTimeout), app.Spec.DynamicAllocation.ExecutorIdleTimeout)
+	assert.Nil(t, app.Spec.DynamicAllocation.CachedExecutorIdleTimeout)
+	assert.Nil(t, app.Spec.DynamicAllocation.SchedulerBacklogTimeout)
+}
+
+func TestDefaultAndValidateSpec(t *testing.T) {
+	newApp := func() *v1beta2.SparkApplication {
+		return &v1beta2.SparkApplication{
+			ObjectMeta: metav1.ObjectMeta{Name: "test-app", Namespace: "default"},
+			Spec: v1beta2.SparkApplicationSpec{
+				Type:                v1beta2.SparkApplicationTypeScala,
+				SparkVersion:        "3.5.3",
+				MainApplicationFile: util.StringPtr("local:///opt/spark/examples/jars/spark-examples.jar"),
+				SparkConf:           map[string]string{"spark.eventLog.enabled": "false"},
+			},
+		}
+	}
+	applicationDefaults := &ApplicationDefaults{Image: util.StringPtr("spark:3.5.3")}
+	policy := &SparkConfPolicy{
+		Forbidden: []string{"spark.authenticate"},
+		Enforced:  map[string]string{"spark.eventLog.enabled": "true"},
+	}
+
+	app := newApp()
+	require.NoError(t, DefaultAndValidateSpec(context.TODO(), app, applicationDefaults, policy))
+	assert.Equal(t, util.StringPtr("spark:3.5.3"), app.Spec.Image)
+	assert.Equal(t, "true", app.Spec.SparkConf["spark.eventLog.enabled"])
+	assert.Equal(t, v1beta2.DeployModeCluster, app.Spec.Mode)
+	assert.Equal(t, v1beta2.RestartPolicyNever, app.Spec.RestartPolicy.Type)
+
+	app = newApp()
+	app.Spec.SparkConf["spark.authenticate"] = "false"
+	assert.Error(t, DefaultAndValidateSpec(context.TODO(), app, applicationDefaults, policy))
+
+	app = newApp()
+	app.Spec.MainApplicationSource = &v1beta2.MainApplicationSource{}
+	assert.Error(t, DefaultAndValidateSpec(context.TODO(), app, nil, nil))
+}
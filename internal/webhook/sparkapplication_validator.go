@@ -19,14 +19,21 @@ package webhook
 import (
 	"context"
 	"fmt"
+	"net/url"
+	"strconv"
+	"time"
 
 	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/equality"
+	"k8s.io/apimachinery/pkg/api/resource"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
 
 	"github.com/kubeflow/spark-operator/api/v1beta2"
+	"github.com/kubeflow/spark-operator/pkg/common"
+	"github.com/kubeflow/spark-operator/pkg/naming"
 	"github.com/kubeflow/spark-operator/pkg/util"
 )
 
@@ -38,14 +45,16 @@ type SparkApplicationValidator struct {
 	client client.Client
 
 	enableResourceQuotaEnforcement bool
+	sparkConfPolicy                *SparkConfPolicy
 }
 
-// NewSparkApplicationValidator creates a new SparkApplicationValidator instance.
-func NewSparkApplicationValidator(client client.Client, enableResourceQuotaEnforcement bool) *SparkApplicationValidator {
+// NewSparkApplicationValidator creates a new SparkApplicationValidator instance. The Spark conf policy is optional.
+func NewSparkApplicationValidator(client client.Client, enableResourceQuotaEnforcement bool, sparkConfPolicy *SparkConfPolicy) *SparkApplicationValidator {
 	return &SparkApplicationValidator{
 		client: client,
 
 		enableResourceQuotaEnforcement: enableResourceQuotaEnforcement,
+		sparkConfPolicy:                sparkConfPolicy,
 	}
 }
 
@@ -57,7 +66,7 @@ func (v *SparkApplicationValidator) ValidateCreate(ctx context.Context, obj runt
 	if !ok {
 		return nil, nil
 	}
-	logger.Info("Validating SparkApplication create", "name", app.Name, "namespace", app.Namespace, "state", util.GetApplicationState(app))
+	logger.Info("Validating SparkApplication create", "name", app.Name, "namespace", app.Namespace, "correlationID", util.GetCorrelationID(app), "state", util.GetApplicationState(app))
 	if err := v.validateSpec(ctx, app); err != nil {
 		return nil, err
 	}
@@ -83,7 +92,7 @@ func (v *SparkApplicationValidator) ValidateUpdate(ctx context.Context, oldObj r
 		return nil, nil
 	}
 
-	logger.Info("Validating SparkApplication update", "name", newApp.Name, "namespace", newApp.Namespace)
+	logger.Info("Validating SparkApplication update", "name", newApp.Name, "namespace", newApp.Namespace, "correlationID", util.GetCorrelationID(newApp))
 
 	// Skip validating when spec does not change.
 	if equality.Semantic.DeepEqual(oldApp.Spec, newApp.Spec) {
@@ -110,21 +119,142 @@ func (v *SparkApplicationValidator) ValidateDelete(ctx context.Context, obj runt
 	if !ok {
 		return nil, nil
 	}
-	logger.Info("Validating SparkApplication delete", "name", app.Name, "namespace", app.Namespace, "state", util.GetApplicationState(app))
+	logger.Info("Validating SparkApplication delete", "name", app.Name, "namespace", app.Namespace, "correlationID", util.GetCorrelationID(app), "state", util.GetApplicationState(app))
 	return nil, nil
 }
 
 func (v *SparkApplicationValidator) validateSpec(_ context.Context, app *v1beta2.SparkApplication) error {
-	logger.V(1).Info("Validating SparkApplication spec", "name", app.Name, "namespace", app.Namespace, "state", util.GetApplicationState(app))
+	logger.V(1).Info("Validating SparkApplication spec", "name", app.Name, "namespace", app.Namespace, "correlationID", util.GetCorrelationID(app), "state", util.GetApplicationState(app))
 
 	if err := v.validateSparkVersion(app); err != nil {
 		return err
 	}
 
+	if v.sparkConfPolicy != nil {
+		if err := v.sparkConfPolicy.validate(app); err != nil {
+			return err
+		}
+	}
+
+	if err := v.validateMainApplicationFile(app); err != nil {
+		return err
+	}
+
+	if err := v.validateResources(app); err != nil {
+		return err
+	}
+
+	if err := v.validateDynamicAllocation(app); err != nil {
+		return err
+	}
+
+	if err := v.validatePostRunActions(app); err != nil {
+		return err
+	}
+
+	if err := v.validateOutputs(app); err != nil {
+		return err
+	}
+
+	if err := v.validateEnvFrom(app); err != nil {
+		return err
+	}
+
+	if err := v.validateDNSPolicy(app); err != nil {
+		return err
+	}
+
+	if err := v.validateWaitFor(app); err != nil {
+		return err
+	}
+
+	if err := v.validateSparkUIAuth(app); err != nil {
+		return err
+	}
+
+	if util.IsMainApplicationFileInConfigMap(app) {
+		if _, _, err := util.GetMainApplicationFileConfigMap(app); err != nil {
+			return err
+		}
+	}
+
+	if _, err := util.GetOCIArtifacts(app); err != nil {
+		return err
+	}
+
+	if app.Spec.Driver.Template != nil && app.Spec.Driver.TemplateConfigMap != nil {
+		return fmt.Errorf("driver template and template ConfigMap are mutually exclusive")
+	}
+	if app.Spec.Executor.Template != nil && app.Spec.Executor.TemplateConfigMap != nil {
+		return fmt.Errorf("executor template and template ConfigMap are mutually exclusive")
+	}
+
+	if engine := app.Spec.SubmissionEngine; engine != nil && *engine != v1beta2.SubmissionEngineSparkSubmit &&
+		app.Spec.Mode != "" && app.Spec.Mode != v1beta2.DeployModeCluster {
+		return fmt.Errorf("submission engine %s only supports the %s mode", *engine, v1beta2.DeployModeCluster)
+	}
+
+	if backoff := app.Spec.RestartPolicy.Backoff; backoff != nil {
+		if _, err := util.GetRetryBackoffInterval(backoff, 1); err != nil {
+			return err
+		}
+	}
+
+	if _, err := util.GetExecutorLaunchRateLimitConf(app); err != nil {
+		return err
+	}
+
+	if _, err := util.GetExecutorQuotaFailurePolicyConf(app); err != nil {
+		return err
+	}
+
+	if app.Spec.SLA != nil {
+		if _, err := util.ParseSLA(app.Spec.SLA); err != nil {
+			return err
+		}
+	}
+
+	if util.IsPVCReuseEnabled(app) {
+		if own, err := strconv.ParseBool(app.Spec.SparkConf[common.SparkKubernetesDriverOwnPersistentVolumeClaim]); err == nil && !own {
+			return fmt.Errorf("%s requires %s to be enabled", common.SparkKubernetesDriverReusePersistentVolumeClaim, common.SparkKubernetesDriverOwnPersistentVolumeClaim)
+		}
+	}
+
+	if kerberos := app.Spec.Kerberos; kerberos != nil {
+		if kerberos.Principal == "" || kerberos.KeytabSecret == "" || kerberos.Krb5ConfigMap == "" {
+			return fmt.Errorf("kerberos requires principal, keytabSecret and krb5ConfigMap to be set")
+		}
+		if kerberos.RenewalInterval != nil {
+			if interval, err := time.ParseDuration(*kerberos.RenewalInterval); err != nil || interval <= 0 {
+				return fmt.Errorf("invalid Kerberos renewal interval %q", *kerberos.RenewalInterval)
+			}
+		}
+	}
+
 	if app.Spec.NodeSelector != nil && (app.Spec.Driver.NodeSelector != nil || app.Spec.Executor.NodeSelector != nil) {
 		return fmt.Errorf("node selector cannot be defined at both SparkApplication and Driver/Executor")
 	}
 
+	if err := util.ValidateNetworkPorts(app); err != nil {
+		return err
+	}
+
+	if _, err := util.GetLocalDirPolicyConf(app); err != nil {
+		return err
+	}
+
+	if monitoring := app.Spec.Monitoring; monitoring != nil && monitoring.DriverMetricsService != nil {
+		if monitoring.Prometheus == nil || !monitoring.ExposeDriverMetrics {
+			return fmt.Errorf("driverMetricsService requires Prometheus to be configured and exposeDriverMetrics to be true")
+		}
+	}
+
+	for _, podSpec := range []*v1beta2.SparkPodSpec{&app.Spec.Driver.SparkPodSpec, &app.Spec.Executor.SparkPodSpec} {
+		if _, _, err := util.GetEphemeralStorageResources(podSpec); err != nil {
+			return err
+		}
+	}
+
 	servicePorts := make(map[int32]bool)
 	ingressURLFormats := make(map[string]bool)
 	for _, item := range app.Spec.DriverIngressOptions {
@@ -150,7 +280,8 @@ func (v *SparkApplicationValidator) validateSpec(_ context.Context, app *v1beta2
 
 func (v *SparkApplicationValidator) validateSparkVersion(app *v1beta2.SparkApplication) error {
 	// The pod template feature requires Spark version 3.0.0 or higher.
-	if app.Spec.Driver.Template != nil || app.Spec.Executor.Template != nil {
+	if app.Spec.Driver.Template != nil || app.Spec.Executor.Template != nil ||
+		app.Spec.Driver.TemplateConfigMap != nil || app.Spec.Executor.TemplateConfigMap != nil {
 		if util.CompareSemanticVersion(app.Spec.SparkVersion, "3.0.0") < 0 {
 			return fmt.Errorf("pod template feature requires Spark version 3.0.0 or higher")
 		}
@@ -158,8 +289,261 @@ func (v *SparkApplicationValidator) validateSparkVersion(app *v1beta2.SparkAppli
 	return nil
 }
 
+func (v *SparkApplicationValidator) validateMainApplicationFile(app *v1beta2.SparkApplication) error {
+	if app.Spec.MainApplicationSource != nil {
+		if app.Spec.MainApplicationFile != nil {
+			return fmt.Errorf("mainApplicationFile and mainApplicationSource are mutually exclusive")
+		}
+		if app.Spec.MainApplicationSource.Inline == nil || *app.Spec.MainApplicationSource.Inline == "" {
+			return fmt.Errorf("mainApplicationSource.inline must not be empty")
+		}
+		// spark-submit can only run Python and R applications from source.
+		if app.Spec.Type != v1beta2.SparkApplicationTypePython && app.Spec.Type != v1beta2.SparkApplicationTypeR {
+			return fmt.Errorf("mainApplicationSource.inline is not supported for %s applications", app.Spec.Type)
+		}
+		return nil
+	}
+	if app.Spec.MainApplicationFile != nil && *app.Spec.MainApplicationFile != "" {
+		return nil
+	}
+	// JVM applications may run a main class already on the classpath of the image.
+	if (app.Spec.Type == v1beta2.SparkApplicationTypeJava || app.Spec.Type == v1beta2.SparkApplicationTypeScala) &&
+		app.Spec.MainClass != nil && *app.Spec.MainClass != "" {
+		return nil
+	}
+	return fmt.Errorf("mainApplicationFile is required for %s applications without mainClass", app.Spec.Type)
+}
+
+func (v *SparkApplicationValidator) validateResources(app *v1beta2.SparkApplication) error {
+	podSpecs := []struct {
+		role    string
+		podSpec *v1beta2.SparkPodSpec
+	}{
+		{role: "driver", podSpec: &app.Spec.Driver.SparkPodSpec},
+		{role: "executor", podSpec: &app.Spec.Executor.SparkPodSpec},
+	}
+	for _, item := range podSpecs {
+		if item.podSpec.Memory != nil {
+			if _, err := parseJavaMemoryString(*item.podSpec.Memory); err != nil {
+				return fmt.Errorf("invalid %s memory: %v", item.role, err)
+			}
+		}
+		if item.podSpec.MemoryOverhead != nil {
+			if _, err := parseJavaMemoryString(*item.podSpec.MemoryOverhead); err != nil {
+				return fmt.Errorf("invalid %s memory overhead: %v", item.role, err)
+			}
+		}
+		if item.podSpec.Cores != nil && *item.podSpec.Cores < 1 {
+			return fmt.Errorf("%s cores must be positive, got %d", item.role, *item.podSpec.Cores)
+		}
+		if item.podSpec.CoreLimit != nil {
+			if _, err := resource.ParseQuantity(*item.podSpec.CoreLimit); err != nil {
+				return fmt.Errorf("invalid %s core limit %q: %v", item.role, *item.podSpec.CoreLimit, err)
+			}
+		}
+	}
+
+	if app.Spec.Driver.CoreRequest != nil {
+		if _, err := resource.ParseQuantity(*app.Spec.Driver.CoreRequest); err != nil {
+			return fmt.Errorf("invalid driver core request %q: %v", *app.Spec.Driver.CoreRequest, err)
+		}
+	}
+	if app.Spec.Executor.CoreRequest != nil {
+		if _, err := resource.ParseQuantity(*app.Spec.Executor.CoreRequest); err != nil {
+			return fmt.Errorf("invalid executor core request %q: %v", *app.Spec.Executor.CoreRequest, err)
+		}
+	}
+
+	if app.Spec.Executor.Instances != nil && *app.Spec.Executor.Instances < 0 {
+		return fmt.Errorf("executor instances must not be negative, got %d", *app.Spec.Executor.Instances)
+	}
+
+	return nil
+}
+
+// validateEnvFrom checks that every envFrom source of the driver and executors references exactly one named
+// ConfigMap or Secret.
+func (v *SparkApplicationValidator) validateEnvFrom(app *v1beta2.SparkApplication) error {
+	podSpecs := []struct {
+		role    string
+		podSpec *v1beta2.SparkPodSpec
+	}{
+		{role: "driver", podSpec: &app.Spec.Driver.SparkPodSpec},
+		{role: "executor", podSpec: &app.Spec.Executor.SparkPodSpec},
+	}
+	for _, item := range podSpecs {
+		for i, source := range item.podSpec.EnvFrom {
+			switch {
+			case source.ConfigMapRef != nil && source.SecretRef != nil:
+				return fmt.Errorf("%s envFrom[%d] must reference either a ConfigMap or a Secret, not both", item.role, i)
+			case source.ConfigMapRef != nil && source.ConfigMapRef.Name == "":
+				return fmt.Errorf("%s envFrom[%d] references a ConfigMap without name", item.role, i)
+			case source.SecretRef != nil && source.SecretRef.Name == "":
+				return fmt.Errorf("%s envFrom[%d] references a Secret without name", item.role, i)
+			case source.ConfigMapRef == nil && source.SecretRef == nil:
+				return fmt.Errorf("%s envFrom[%d] must reference a ConfigMap or a Secret", item.role, i)
+			}
+		}
+	}
+	return nil
+}
+
+// validateDNSPolicy checks that the driver and executors with the `None` DNS policy specify nameservers.
+func (v *SparkApplicationValidator) validateDNSPolicy(app *v1beta2.SparkApplication) error {
+	podSpecs := []struct {
+		role    string
+		podSpec *v1beta2.SparkPodSpec
+	}{
+		{role: "driver", podSpec: &app.Spec.Driver.SparkPodSpec},
+		{role: "executor", podSpec: &app.Spec.Executor.SparkPodSpec},
+	}
+	for _, item := range podSpecs {
+		if item.podSpec.DNSPolicy == nil || *item.podSpec.DNSPolicy != corev1.DNSNone {
+			continue
+		}
+		if item.podSpec.DNSConfig == nil || len(item.podSpec.DNSConfig.Nameservers) == 0 {
+			return fmt.Errorf("%s dnsPolicy %s requires dnsConfig with nameservers", item.role, corev1.DNSNone)
+		}
+	}
+	return nil
+}
+
+// validateOutputs checks that outputs have unique names and define exactly one of a URI and a ConfigMap name each.
+func (v *SparkApplicationValidator) validateOutputs(app *v1beta2.SparkApplication) error {
+	names := make(map[string]bool, len(app.Spec.Outputs))
+	for _, output := range app.Spec.Outputs {
+		if output.Name == "" {
+			return fmt.Errorf("output name must not be empty")
+		}
+		if names[output.Name] {
+			return fmt.Errorf("duplicate output name %q", output.Name)
+		}
+		names[output.Name] = true
+
+		if (output.URI == nil) == (output.ConfigMapName == nil) {
+			return fmt.Errorf("output %q must define exactly one of uri and configMapName", output.Name)
+		}
+		if output.URI != nil {
+			if _, _, err := util.ParseObjectURI(*output.URI); err != nil {
+				return fmt.Errorf("invalid URI of output %q: %v", output.Name, err)
+			}
+		}
+	}
+	return nil
+}
+
+// validatePostRunActions checks that post-run actions have unique names and define exactly one action each.
+func (v *SparkApplicationValidator) validatePostRunActions(app *v1beta2.SparkApplication) error {
+	names := make(map[string]bool, len(app.Spec.PostRunActions))
+	for _, action := range app.Spec.PostRunActions {
+		if action.Name == "" {
+			return fmt.Errorf("post-run action name must not be empty")
+		}
+		if names[action.Name] {
+			return fmt.Errorf("duplicate post-run action name %q", action.Name)
+		}
+		names[action.Name] = true
+
+		count := 0
+		if action.Webhook != nil {
+			count++
+			if action.Webhook.URL == "" {
+				return fmt.Errorf("post-run action %q must set the webhook URL", action.Name)
+			}
+			if u, err := url.Parse(action.Webhook.URL); err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+				return fmt.Errorf("post-run action %q must set an absolute http or https webhook URL", action.Name)
+			}
+		}
+		if action.ConfigMapSummary != nil {
+			count++
+		}
+		if action.DriverLogs != nil {
+			count++
+		}
+		if count != 1 {
+			return fmt.Errorf("post-run action %q must define exactly one of webhook, configMapSummary and driverLogs", action.Name)
+		}
+	}
+	return nil
+}
+
+// validateWaitFor checks that the resources the application waits for are fully referenced.
+func (v *SparkApplicationValidator) validateWaitFor(app *v1beta2.SparkApplication) error {
+	if app.Spec.WaitFor == nil {
+		return nil
+	}
+	for _, dependency := range app.Spec.WaitFor.Resources {
+		if dependency.Kind == "" || dependency.Name == "" {
+			return fmt.Errorf("resources in waitFor must set both kind and name")
+		}
+		if dependency.APIVersion != "" {
+			if _, err := schema.ParseGroupVersion(dependency.APIVersion); err != nil {
+				return fmt.Errorf("invalid API version of %s %s in waitFor: %v", dependency.Kind, dependency.Name, err)
+			}
+		}
+	}
+	return nil
+}
+
+// validateSparkUIAuth checks that exactly one authentication method is configured for the web UI.
+func (v *SparkApplicationValidator) validateSparkUIAuth(app *v1beta2.SparkApplication) error {
+	if app.Spec.SparkUIOptions == nil || app.Spec.SparkUIOptions.Auth == nil {
+		return nil
+	}
+	auth := app.Spec.SparkUIOptions.Auth
+	if (auth.BasicAuth == nil) == (auth.OAuth2Proxy == nil) {
+		return fmt.Errorf("exactly one of basicAuth and oauth2Proxy must be set in sparkUIOptions.auth")
+	}
+	if auth.BasicAuth != nil && auth.BasicAuth.SecretName == "" {
+		return fmt.Errorf("sparkUIOptions.auth.basicAuth requires secretName to be set")
+	}
+	if auth.OAuth2Proxy != nil && auth.OAuth2Proxy.SecretName == "" {
+		return fmt.Errorf("sparkUIOptions.auth.oauth2Proxy requires secretName to be set")
+	}
+	return nil
+}
+
+func (v *SparkApplicationValidator) validateDynamicAllocation(app *v1beta2.SparkApplication) error {
+	dynamicAllocation := app.Spec.DynamicAllocation
+	if dynamicAllocation == nil || !dynamicAllocation.Enabled {
+		return nil
+	}
+
+	if util.CompareSemanticVersion(app.Spec.SparkVersion, "3.0.0") < 0 {
+		return fmt.Errorf("dynamic allocation requires Spark version 3.0.0 or higher")
+	}
+
+	if enabled, err := strconv.ParseBool(app.Spec.SparkConf[common.SparkDynamicAllocationEnabled]); err == nil && !enabled {
+		return fmt.Errorf("dynamic allocation is enabled but %s is set to false", common.SparkDynamicAllocationEnabled)
+	}
+
+	minExecutors := dynamicAllocation.MinExecutors
+	maxExecutors := dynamicAllocation.MaxExecutors
+	initialExecutors := dynamicAllocation.InitialExecutors
+	if minExecutors != nil && *minExecutors < 0 {
+		return fmt.Errorf("dynamic allocation minExecutors must not be negative, got %d", *minExecutors)
+	}
+	if minExecutors != nil && maxExecutors != nil && *minExecutors > *maxExecutors {
+		return fmt.Errorf("dynamic allocation minExecutors (%d) must not be greater than maxExecutors (%d)", *minExecutors, *maxExecutors)
+	}
+	if initialExecutors != nil {
+		if minExecutors != nil && *initialExecutors < *minExecutors {
+			return fmt.Errorf("dynamic allocation initialExecutors (%d) must not be less than minExecutors (%d)", *initialExecutors, *minExecutors)
+		}
+		if maxExecutors != nil && *initialExecutors > *maxExecutors {
+			return fmt.Errorf("dynamic allocation initialExecutors (%d) must not be greater than maxExecutors (%d)", *initialExecutors, *maxExecutors)
+		}
+	}
+
+	if _, err := util.GetDynamicAllocationTimeoutConf(dynamicAllocation); err != nil {
+		return err
+	}
+
+	return nil
+}
+
 func (v *SparkApplicationValidator) validateResourceUsage(ctx context.Context, app *v1beta2.SparkApplication) error {
-	logger.V(1).Info("Validating SparkApplication resource usage", "name", app.Name, "namespace", app.Namespace, "state", util.GetApplicationState(app))
+	logger.V(1).Info("Validating SparkApplication resource usage", "name", app.Name, "namespace", app.Namespace, "correlationID", util.GetCorrelationID(app), "state", util.GetApplicationState(app))
 
 	requests, err := getResourceList(app)
 	if err != nil {
@@ -171,6 +555,15 @@ func (v *SparkApplicationValidator) validateResourceUsage(ctx context.Context, a
 		return fmt.Errorf("failed to list resource quotas: %v", err)
 	}
 
+	// Account for the resources reserved for the scale-ups of other SparkApplications.
+	reservations := []corev1.ResourceList{requests}
+	for _, resourceQuota := range resourceQuotaList.Items {
+		if resourceQuota.Labels[common.LabelResourceReservation] == "true" && resourceQuota.Name != naming.ResourceReservationName(app) {
+			reservations = append(reservations, resourceQuota.Spec.Hard)
+		}
+	}
+	requests = util.SumResourceList(reservations)
+
 	for _, resourceQuota := range resourceQuotaList.Items {
 		// Scope selectors not currently supported, ignore any ResourceQuota that does not match everything.
 		// TODO: Add support for scope selectors.
@@ -0,0 +1,485 @@
+/*
+Copyright 2024 The Kubeflow authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package webhook
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	"github.com/kubeflow/spark-operator/api/v1beta2"
+	"github.com/kubeflow/spark-operator/pkg/common"
+)
+
+func TestValidateResourceUsage_ResourceReservations(t *testing.T) {
+	app := &v1beta2.SparkApplication{
+		ObjectMeta: metav1.ObjectMeta{Name: "spark-pi", Namespace: "default"},
+		Spec: v1beta2.SparkApplicationSpec{
+			Driver: v1beta2.DriverSpec{SparkPodSpec: v1beta2.SparkPodSpec{Cores: ptr.To[int32](1)}},
+			Executor: v1beta2.ExecutorSpec{
+				SparkPodSpec: v1beta2.SparkPodSpec{Cores: ptr.To[int32](1)},
+				Instances:    ptr.To[int32](1),
+			},
+		},
+	}
+	quota := &corev1.ResourceQuota{
+		ObjectMeta: metav1.ObjectMeta{Name: "quota", Namespace: "default"},
+		Spec: corev1.ResourceQuotaSpec{
+			Hard: corev1.ResourceList{corev1.ResourceRequestsCPU: resource.MustParse("4")},
+		},
+		Status: corev1.ResourceQuotaStatus{
+			Hard: corev1.ResourceList{corev1.ResourceRequestsCPU: resource.MustParse("4")},
+			Used: corev1.ResourceList{corev1.ResourceRequestsCPU: resource.MustParse("1")},
+		},
+	}
+	reservation := &corev1.ResourceQuota{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "other-reservation",
+			Namespace: "default",
+			Labels:    map[string]string{common.LabelResourceReservation: "true"},
+		},
+		Spec: corev1.ResourceQuotaSpec{
+			Hard: corev1.ResourceList{corev1.ResourceRequestsCPU: resource.MustParse("2")},
+			ScopeSelector: &corev1.ScopeSelector{
+				MatchExpressions: []corev1.ScopedResourceSelectorRequirement{{
+					ScopeName: corev1.ResourceQuotaScopePriorityClass,
+					Operator:  corev1.ScopeSelectorOpIn,
+					Values:    []string{common.ResourceReservationPriorityClass},
+				}},
+			},
+		},
+	}
+
+	validator := NewSparkApplicationValidator(fake.NewClientBuilder().WithObjects(quota).Build(), true, nil)
+	assert.NoError(t, validator.validateResourceUsage(context.TODO(), app))
+
+	validator = NewSparkApplicationValidator(fake.NewClientBuilder().WithObjects(quota, reservation).Build(), true, nil)
+	assert.Error(t, validator.validateResourceUsage(context.TODO(), app))
+}
+
+func TestValidateSpec(t *testing.T) {
+	newApp := func() *v1beta2.SparkApplication {
+		return &v1beta2.SparkApplication{
+			ObjectMeta: metav1.ObjectMeta{Name: "spark-pi", Namespace: "default"},
+			Spec: v1beta2.SparkApplicationSpec{
+				Type:                v1beta2.SparkApplicationTypeScala,
+				SparkVersion:        "3.5.3",
+				MainClass:           ptr.To("org.apache.spark.examples.SparkPi"),
+				MainApplicationFile: ptr.To("local:///opt/spark/examples/jars/spark-examples.jar"),
+				Driver: v1beta2.DriverSpec{
+					SparkPodSpec: v1beta2.SparkPodSpec{Memory: ptr.To("512m")},
+				},
+				Executor: v1beta2.ExecutorSpec{
+					SparkPodSpec: v1beta2.SparkPodSpec{Memory: ptr.To("1g")},
+					Instances:    ptr.To[int32](2),
+				},
+			},
+		}
+	}
+
+	testCases := []struct {
+		name    string
+		mutate  func(app *v1beta2.SparkApplication)
+		wantErr bool
+	}{
+		{
+			name:   "valid application",
+			mutate: func(_ *v1beta2.SparkApplication) {},
+		},
+		{
+			name:   "JVM application without main application file",
+			mutate: func(app *v1beta2.SparkApplication) { app.Spec.MainApplicationFile = nil },
+		},
+		{
+			name: "Python application without main application file",
+			mutate: func(app *v1beta2.SparkApplication) {
+				app.Spec.Type = v1beta2.SparkApplicationTypePython
+				app.Spec.MainApplicationFile = nil
+			},
+			wantErr: true,
+		},
+		{
+			name: "envFrom referencing a Secret",
+			mutate: func(app *v1beta2.SparkApplication) {
+				app.Spec.Executor.EnvFrom = []corev1.EnvFromSource{
+					{SecretRef: &corev1.SecretEnvSource{LocalObjectReference: corev1.LocalObjectReference{Name: "env"}}},
+				}
+			},
+		},
+		{
+			name: "envFrom referencing both a ConfigMap and a Secret",
+			mutate: func(app *v1beta2.SparkApplication) {
+				app.Spec.Driver.EnvFrom = []corev1.EnvFromSource{{
+					ConfigMapRef: &corev1.ConfigMapEnvSource{LocalObjectReference: corev1.LocalObjectReference{Name: "env"}},
+					SecretRef:    &corev1.SecretEnvSource{LocalObjectReference: corev1.LocalObjectReference{Name: "env"}},
+				}}
+			},
+			wantErr: true,
+		},
+		{
+			name: "envFrom referencing a ConfigMap without name",
+			mutate: func(app *v1beta2.SparkApplication) {
+				app.Spec.Driver.EnvFrom = []corev1.EnvFromSource{{ConfigMapRef: &corev1.ConfigMapEnvSource{}}}
+			},
+			wantErr: true,
+		},
+		{
+			name: "dnsPolicy None with nameservers",
+			mutate: func(app *v1beta2.SparkApplication) {
+				app.Spec.Driver.DNSPolicy = ptr.To(corev1.DNSNone)
+				app.Spec.Driver.DNSConfig = &corev1.PodDNSConfig{Nameservers: []string{"10.0.0.10"}}
+			},
+		},
+		{
+			name: "dnsPolicy None without nameservers",
+			mutate: func(app *v1beta2.SparkApplication) {
+				app.Spec.Executor.DNSPolicy = ptr.To(corev1.DNSNone)
+			},
+			wantErr: true,
+		},
+		{
+			name: "Python application with inline main application source",
+			mutate: func(app *v1beta2.SparkApplication) {
+				app.Spec.Type = v1beta2.SparkApplicationTypePython
+				app.Spec.MainClass = nil
+				app.Spec.MainApplicationFile = nil
+				app.Spec.MainApplicationSource = &v1beta2.MainApplicationSource{Inline: ptr.To("print('hello')")}
+			},
+		},
+		{
+			name: "inline main application source together with main application file",
+			mutate: func(app *v1beta2.SparkApplication) {
+				app.Spec.Type = v1beta2.SparkApplicationTypePython
+				app.Spec.MainApplicationSource = &v1beta2.MainApplicationSource{Inline: ptr.To("print('hello')")}
+			},
+			wantErr: true,
+		},
+		{
+			name: "empty inline main application source",
+			mutate: func(app *v1beta2.SparkApplication) {
+				app.Spec.Type = v1beta2.SparkApplicationTypePython
+				app.Spec.MainApplicationFile = nil
+				app.Spec.MainApplicationSource = &v1beta2.MainApplicationSource{Inline: ptr.To("")}
+			},
+			wantErr: true,
+		},
+		{
+			name: "inline main application source for Scala application",
+			mutate: func(app *v1beta2.SparkApplication) {
+				app.Spec.MainApplicationFile = nil
+				app.Spec.MainApplicationSource = &v1beta2.MainApplicationSource{Inline: ptr.To("object Main")}
+			},
+			wantErr: true,
+		},
+		{
+			name:    "invalid memory",
+			mutate:  func(app *v1beta2.SparkApplication) { app.Spec.Executor.Memory = ptr.To("1 GB") },
+			wantErr: true,
+		},
+		{
+			name:    "negative executor instances",
+			mutate:  func(app *v1beta2.SparkApplication) { app.Spec.Executor.Instances = ptr.To[int32](-1) },
+			wantErr: true,
+		},
+		{
+			name: "min executors greater than max executors",
+			mutate: func(app *v1beta2.SparkApplication) {
+				app.Spec.DynamicAllocation = &v1beta2.DynamicAllocation{
+					Enabled:      true,
+					MinExecutors: ptr.To[int32](5),
+					MaxExecutors: ptr.To[int32](2),
+				}
+			},
+			wantErr: true,
+		},
+		{
+			name: "dynamic allocation disabled in Spark conf",
+			mutate: func(app *v1beta2.SparkApplication) {
+				app.Spec.DynamicAllocation = &v1beta2.DynamicAllocation{Enabled: true}
+				app.Spec.SparkConf = map[string]string{common.SparkDynamicAllocationEnabled: "false"}
+			},
+			wantErr: true,
+		},
+		{
+			name: "dynamic allocation timeouts",
+			mutate: func(app *v1beta2.SparkApplication) {
+				app.Spec.DynamicAllocation = &v1beta2.DynamicAllocation{
+					Enabled:                   true,
+					ExecutorIdleTimeout:       ptr.To("2m"),
+					CachedExecutorIdleTimeout: ptr.To("30m"),
+					SchedulerBacklogTimeout:   ptr.To("5s"),
+				}
+			},
+		},
+		{
+			name: "invalid dynamic allocation executor idle timeout",
+			mutate: func(app *v1beta2.SparkApplication) {
+				app.Spec.DynamicAllocation = &v1beta2.DynamicAllocation{Enabled: true, ExecutorIdleTimeout: ptr.To("60")}
+			},
+			wantErr: true,
+		},
+		{
+			name: "outputs",
+			mutate: func(app *v1beta2.SparkApplication) {
+				app.Spec.Outputs = []v1beta2.Output{
+					{Name: "table", URI: ptr.To("s3://bucket/path/to/table?region=us-west-1")},
+					{Name: "report", ConfigMapName: ptr.To("report")},
+				}
+			},
+		},
+		{
+			name: "output with both URI and ConfigMap name",
+			mutate: func(app *v1beta2.SparkApplication) {
+				app.Spec.Outputs = []v1beta2.Output{{Name: "table", URI: ptr.To("s3://bucket/table"), ConfigMapName: ptr.To("table")}}
+			},
+			wantErr: true,
+		},
+		{
+			name: "output URI without object key",
+			mutate: func(app *v1beta2.SparkApplication) {
+				app.Spec.Outputs = []v1beta2.Output{{Name: "table", URI: ptr.To("s3://bucket")}}
+			},
+			wantErr: true,
+		},
+		{
+			name: "post-run actions",
+			mutate: func(app *v1beta2.SparkApplication) {
+				app.Spec.PostRunActions = []v1beta2.PostRunAction{
+					{Name: "notify", Webhook: &v1beta2.WebhookPostRunAction{URL: "http://example.com/done"}},
+					{Name: "summary", ConfigMapSummary: &v1beta2.ConfigMapSummaryPostRunAction{}},
+				}
+			},
+		},
+		{
+			name: "duplicate post-run action names",
+			mutate: func(app *v1beta2.SparkApplication) {
+				app.Spec.PostRunActions = []v1beta2.PostRunAction{
+					{Name: "summary", ConfigMapSummary: &v1beta2.ConfigMapSummaryPostRunAction{}},
+					{Name: "summary", DriverLogs: &v1beta2.DriverLogsPostRunAction{}},
+				}
+			},
+			wantErr: true,
+		},
+		{
+			name: "post-run webhook with relative URL",
+			mutate: func(app *v1beta2.SparkApplication) {
+				app.Spec.PostRunActions = []v1beta2.PostRunAction{
+					{Name: "notify", Webhook: &v1beta2.WebhookPostRunAction{URL: "/done"}},
+				}
+			},
+			wantErr: true,
+		},
+		{
+			name: "post-run webhook with unsupported scheme",
+			mutate: func(app *v1beta2.SparkApplication) {
+				app.Spec.PostRunActions = []v1beta2.PostRunAction{
+					{Name: "notify", Webhook: &v1beta2.WebhookPostRunAction{URL: "file:///etc/passwd"}},
+				}
+			},
+			wantErr: true,
+		},
+		{
+			name: "post-run action defining several actions",
+			mutate: func(app *v1beta2.SparkApplication) {
+				app.Spec.PostRunActions = []v1beta2.PostRunAction{{
+					Name:             "summary",
+					ConfigMapSummary: &v1beta2.ConfigMapSummaryPostRunAction{},
+					DriverLogs:       &v1beta2.DriverLogsPostRunAction{},
+				}}
+			},
+			wantErr: true,
+		},
+		{
+			name: "native submission engine in client mode",
+			mutate: func(app *v1beta2.SparkApplication) {
+				app.Spec.SubmissionEngine = ptr.To(v1beta2.SubmissionEngineNative)
+				app.Spec.Mode = v1beta2.DeployModeClient
+			},
+			wantErr: true,
+		},
+		{
+			name: "job submission engine in client mode",
+			mutate: func(app *v1beta2.SparkApplication) {
+				app.Spec.SubmissionEngine = ptr.To(v1beta2.SubmissionEngineJob)
+				app.Spec.Mode = v1beta2.DeployModeClient
+			},
+			wantErr: true,
+		},
+		{
+			name: "executor launch rate limit",
+			mutate: func(app *v1beta2.SparkApplication) {
+				app.Spec.Executor.LaunchRateLimit = &v1beta2.ExecutorLaunchRateLimit{BatchSize: ptr.To[int32](20), BatchDelay: ptr.To("2s")}
+			},
+		},
+		{
+			name: "executor launch rate limit with invalid batch delay",
+			mutate: func(app *v1beta2.SparkApplication) {
+				app.Spec.Executor.LaunchRateLimit = &v1beta2.ExecutorLaunchRateLimit{BatchDelay: ptr.To("2 seconds")}
+			},
+			wantErr: true,
+		},
+		{
+			name: "executor template ConfigMap",
+			mutate: func(app *v1beta2.SparkApplication) {
+				app.Spec.Executor.TemplateConfigMap = &v1beta2.NameKey{Name: "executor-template", Key: "pod.yaml"}
+			},
+		},
+		{
+			name: "driver template and template ConfigMap",
+			mutate: func(app *v1beta2.SparkApplication) {
+				app.Spec.Driver.Template = &corev1.PodTemplateSpec{}
+				app.Spec.Driver.TemplateConfigMap = &v1beta2.NameKey{Name: "driver-template", Key: "pod.yaml"}
+			},
+			wantErr: true,
+		},
+		{
+			name: "SLA",
+			mutate: func(app *v1beta2.SparkApplication) {
+				app.Spec.SLA = &v1beta2.SLASpec{
+					MaxPendingDuration:   ptr.To("15m"),
+					MaxRunDuration:       ptr.To("2h"),
+					ExpectedCompletionBy: ptr.To("CRON_TZ=Europe/Berlin 0 6 * * *"),
+				}
+			},
+		},
+		{
+			name: "SLA with negative max run duration",
+			mutate: func(app *v1beta2.SparkApplication) {
+				app.Spec.SLA = &v1beta2.SLASpec{MaxRunDuration: ptr.To("-1h")}
+			},
+			wantErr: true,
+		},
+		{
+			name: "SLA with invalid expected completion time",
+			mutate: func(app *v1beta2.SparkApplication) {
+				app.Spec.SLA = &v1beta2.SLASpec{ExpectedCompletionBy: ptr.To("every morning")}
+			},
+			wantErr: true,
+		},
+		{
+			name: "retry backoff",
+			mutate: func(app *v1beta2.SparkApplication) {
+				app.Spec.RestartPolicy.Backoff = &v1beta2.RetryBackoff{InitialIntervalSeconds: 10, Multiplier: ptr.To("1.5")}
+			},
+		},
+		{
+			name: "retry backoff with multiplier less than 1",
+			mutate: func(app *v1beta2.SparkApplication) {
+				app.Spec.RestartPolicy.Backoff = &v1beta2.RetryBackoff{InitialIntervalSeconds: 10, Multiplier: ptr.To("0.5")}
+			},
+			wantErr: true,
+		},
+		{
+			name: "wait for resources",
+			mutate: func(app *v1beta2.SparkApplication) {
+				app.Spec.WaitFor = &v1beta2.WaitForSpec{
+					Resources: []v1beta2.ResourceDependency{
+						{Kind: "ConfigMap", Name: "rendered-config"},
+						{APIVersion: "apps/v1", Kind: "Deployment", Name: "metastore", Condition: ptr.To("Available")},
+					},
+				}
+			},
+		},
+		{
+			name: "wait for resource without name",
+			mutate: func(app *v1beta2.SparkApplication) {
+				app.Spec.WaitFor = &v1beta2.WaitForSpec{
+					Resources: []v1beta2.ResourceDependency{{Kind: "ConfigMap"}},
+				}
+			},
+			wantErr: true,
+		},
+		{
+			name: "wait for resource with invalid API version",
+			mutate: func(app *v1beta2.SparkApplication) {
+				app.Spec.WaitFor = &v1beta2.WaitForSpec{
+					Resources: []v1beta2.ResourceDependency{{APIVersion: "apps/v1/beta", Kind: "Deployment", Name: "metastore"}},
+				}
+			},
+			wantErr: true,
+		},
+		{
+			name: "web UI basic auth",
+			mutate: func(app *v1beta2.SparkApplication) {
+				app.Spec.SparkUIOptions = &v1beta2.SparkUIConfiguration{
+					Auth: &v1beta2.SparkUIAuth{BasicAuth: &v1beta2.SparkUIBasicAuth{SecretName: "spark-ui-users"}},
+				}
+			},
+		},
+		{
+			name: "web UI basic auth and oauth2-proxy",
+			mutate: func(app *v1beta2.SparkApplication) {
+				app.Spec.SparkUIOptions = &v1beta2.SparkUIConfiguration{
+					Auth: &v1beta2.SparkUIAuth{
+						BasicAuth:   &v1beta2.SparkUIBasicAuth{SecretName: "spark-ui-users"},
+						OAuth2Proxy: &v1beta2.SparkUIOAuth2Proxy{SecretName: "spark-ui-oauth2"},
+					},
+				}
+			},
+			wantErr: true,
+		},
+		{
+			name: "web UI oauth2-proxy without secret",
+			mutate: func(app *v1beta2.SparkApplication) {
+				app.Spec.SparkUIOptions = &v1beta2.SparkUIConfiguration{
+					Auth: &v1beta2.SparkUIAuth{OAuth2Proxy: &v1beta2.SparkUIOAuth2Proxy{}},
+				}
+			},
+			wantErr: true,
+		},
+		{
+			name: "driver metrics service with Prometheus",
+			mutate: func(app *v1beta2.SparkApplication) {
+				app.Spec.Monitoring = &v1beta2.MonitoringSpec{
+					ExposeDriverMetrics:  true,
+					Prometheus:           &v1beta2.PrometheusSpec{JmxExporterJar: "/prometheus/jmx_prometheus_javaagent.jar"},
+					DriverMetricsService: &v1beta2.MetricsServiceSpec{},
+				}
+			},
+		},
+		{
+			name: "driver metrics service without Prometheus",
+			mutate: func(app *v1beta2.SparkApplication) {
+				app.Spec.Monitoring = &v1beta2.MonitoringSpec{
+					ExposeDriverMetrics:  true,
+					DriverMetricsService: &v1beta2.MetricsServiceSpec{},
+				}
+			},
+			wantErr: true,
+		},
+	}
+
+	validator := NewSparkApplicationValidator(nil, false, nil)
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			app := newApp()
+			tc.mutate(app)
+			err := validator.validateSpec(context.TODO(), app)
+			if tc.wantErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
@@ -18,12 +18,20 @@ package webhook
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
+	"slices"
+	"strconv"
 	"strings"
+	"time"
 
+	"gomodules.xyz/jsonpatch/v2"
+	admissionv1 "k8s.io/api/admission/v1"
 	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
 	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/types"
 	"sigs.k8s.io/controller-runtime/pkg/client"
@@ -31,6 +39,7 @@ import (
 
 	"github.com/kubeflow/spark-operator/api/v1beta2"
 	"github.com/kubeflow/spark-operator/pkg/common"
+	"github.com/kubeflow/spark-operator/pkg/naming"
 	"github.com/kubeflow/spark-operator/pkg/util"
 )
 
@@ -44,13 +53,24 @@ const (
 type SparkPodDefaulter struct {
 	client             client.Client
 	sparkJobNamespaces map[string]bool
+	namespaceSelector  labels.Selector
+	podDefaults        *PodDefaults
+	dryRun             bool
+	annotateMutations  bool
+
+	enableResourceQuotaEnforcement bool
 }
 
 // SparkPodDefaulter implements admission.CustomDefaulter.
 var _ admission.CustomDefaulter = &SparkPodDefaulter{}
 
-// NewSparkPodDefaulter creates a new SparkPodDefaulter instance.
-func NewSparkPodDefaulter(client client.Client, namespaces []string) *SparkPodDefaulter {
+// NewSparkPodDefaulter creates a new SparkPodDefaulter instance. Only pods in the given namespaces, or in all namespaces
+// if none is given, are mutated. The namespace selector further restricts mutation to the namespaces whose labels match
+// it. Both the namespace selector and the pod defaults are optional. In dry-run mode, pods are not mutated; the patch
+// that would have been applied is logged and recorded in an annotation on the pod instead. If annotateMutations is
+// true, mutated pods are annotated with the fields the webhook changed. If enableResourceQuotaEnforcement is true,
+// pods using resources reserved for the executors of other SparkApplications are denied.
+func NewSparkPodDefaulter(client client.Client, namespaces []string, namespaceSelector labels.Selector, podDefaults *PodDefaults, dryRun bool, annotateMutations bool, enableResourceQuotaEnforcement bool) *SparkPodDefaulter {
 	nsMap := make(map[string]bool)
 	if len(namespaces) == 0 {
 		nsMap[metav1.NamespaceAll] = true
@@ -63,6 +83,12 @@ func NewSparkPodDefaulter(client client.Client, namespaces []string) *SparkPodDe
 	return &SparkPodDefaulter{
 		client:             client,
 		sparkJobNamespaces: nsMap,
+		namespaceSelector:  namespaceSelector,
+		podDefaults:        podDefaults,
+		dryRun:             dryRun,
+		annotateMutations:  annotateMutations,
+
+		enableResourceQuotaEnforcement: enableResourceQuotaEnforcement,
 	}
 }
 
@@ -74,12 +100,16 @@ func (d *SparkPodDefaulter) Default(ctx context.Context, obj runtime.Object) err
 	}
 
 	namespace := pod.Namespace
-	if !d.isSparkJobNamespace(namespace) {
+	appName := pod.Labels[common.LabelSparkAppName]
+	if appName == "" {
 		return nil
 	}
 
-	appName := pod.Labels[common.LabelSparkAppName]
-	if appName == "" {
+	ok, err := d.isSparkJobNamespace(ctx, namespace)
+	if err != nil {
+		return err
+	}
+	if !ok {
 		return nil
 	}
 
@@ -88,17 +118,214 @@ func (d *SparkPodDefaulter) Default(ctx context.Context, obj runtime.Object) err
 		return fmt.Errorf("failed to get SparkApplication %s/%s: %v", namespace, appName, err)
 	}
 
-	logger.Info("Mutating Spark pod", "name", pod.Name, "namespace", namespace, "phase", pod.Status.Phase)
+	if d.dryRun {
+		return d.dryRunMutation(pod, app)
+	}
+
+	logger.Info("Mutating Spark pod", "name", pod.Name, "namespace", namespace, "phase", pod.Status.Phase, "correlationID", util.GetPodCorrelationID(pod))
+	original := pod.DeepCopy()
+	if err := d.mutate(pod, app); err != nil {
+		return err
+	}
+
+	// Reservations are validated against the mutated pod, whose resources are final.
+	if d.enableResourceQuotaEnforcement {
+		if err := d.validateResourceReservations(ctx, pod, app); err != nil {
+			logger.Info("Denying Spark pod", "name", pod.Name, "namespace", pod.Namespace, "correlationID", util.GetPodCorrelationID(pod), "errorMessage", err.Error())
+			return err
+		}
+	}
+
+	if !d.annotateMutations {
+		return nil
+	}
+	return annotateMutations(original, pod)
+}
+
+// validateResourceReservations denies the creation of Spark pods using resources reserved for the executors other
+// SparkApplications may still scale up to, i.e. pods which do not fit in a ResourceQuota of the namespace once both
+// its usage and the reservations of the other SparkApplications are accounted for.
+func (d *SparkPodDefaulter) validateResourceReservations(ctx context.Context, pod *corev1.Pod, app *v1beta2.SparkApplication) error {
+	req, err := admission.RequestFromContext(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to get admission request: %v", err)
+	}
+	if req.Operation != admissionv1.Create {
+		return nil
+	}
+
+	resourceQuotaList := &corev1.ResourceQuotaList{}
+	if err := d.client.List(ctx, resourceQuotaList, client.InNamespace(pod.Namespace)); err != nil {
+		return fmt.Errorf("failed to list resource quotas: %v", err)
+	}
+
+	reservations := []corev1.ResourceList{getPodResourceList(pod)}
+	for _, resourceQuota := range resourceQuotaList.Items {
+		if resourceQuota.Labels[common.LabelResourceReservation] == "true" && resourceQuota.Name != naming.ResourceReservationName(app) {
+			reservations = append(reservations, resourceQuota.Spec.Hard)
+		}
+	}
+	// Without reservations of other SparkApplications, the ResourceQuotas are enforced by Kubernetes alone.
+	if len(reservations) == 1 {
+		return nil
+	}
+	requests := util.SumResourceList(reservations)
+
+	for _, resourceQuota := range resourceQuotaList.Items {
+		if resourceQuota.Spec.ScopeSelector != nil || len(resourceQuota.Spec.Scopes) > 0 {
+			continue
+		}
+		if !validateResourceQuota(requests, resourceQuota) {
+			return fmt.Errorf("failed to validate resource quota \"%s/%s\": the remaining resources are reserved for the executors of other SparkApplications", resourceQuota.Namespace, resourceQuota.Name)
+		}
+	}
+	return nil
+}
+
+func (d *SparkPodDefaulter) mutate(pod *corev1.Pod, app *v1beta2.SparkApplication) error {
 	if err := mutateSparkPod(pod, app); err != nil {
-		logger.Info("Denying Spark pod", "name", pod.Name, "namespace", namespace, "errorMessage", err.Error())
+		logger.Info("Denying Spark pod", "name", pod.Name, "namespace", pod.Namespace, "correlationID", util.GetPodCorrelationID(pod), "errorMessage", err.Error())
 		return fmt.Errorf("failed to mutate Spark pod: %v", err)
 	}
+	if d.podDefaults != nil {
+		d.podDefaults.apply(pod, app)
+	}
+	return nil
+}
+
+// dryRunMutation computes the JSON patch the webhook would apply to the pod without applying it. The patch is logged
+// and recorded in an annotation on the pod, which is the only change made to the pod.
+func (d *SparkPodDefaulter) dryRunMutation(pod *corev1.Pod, app *v1beta2.SparkApplication) error {
+	mutated := pod.DeepCopy()
+	if err := d.mutate(mutated, app); err != nil {
+		return err
+	}
+
+	patch, err := createPodPatch(pod, mutated)
+	if err != nil {
+		return fmt.Errorf("failed to compute patch of Spark pod: %v", err)
+	}
+	logger.Info("Computed Spark pod patch in dry-run mode", "name", pod.Name, "namespace", pod.Namespace, "correlationID", util.GetPodCorrelationID(pod), "patch", string(patch))
+
+	if pod.Annotations == nil {
+		pod.Annotations = make(map[string]string)
+	}
+	pod.Annotations[common.AnnotationDryRunPatch] = string(patch)
+	return nil
+}
+
+// annotateMutations records the fields changed between the original and the mutated pod in an annotation on the
+// mutated pod, so that the mutation can be verified without comparing the pod to its spec.
+func annotateMutations(original *corev1.Pod, mutated *corev1.Pod) error {
+	mutations, err := getPodMutations(original, mutated)
+	if err != nil {
+		return fmt.Errorf("failed to compute mutations of Spark pod: %v", err)
+	}
+	if len(mutations) == 0 {
+		return nil
+	}
 
+	if mutated.Annotations == nil {
+		mutated.Annotations = make(map[string]string)
+	}
+	mutated.Annotations[common.AnnotationMutations] = strings.Join(mutations, ",")
 	return nil
 }
 
-func (d *SparkPodDefaulter) isSparkJobNamespace(ns string) bool {
-	return d.sparkJobNamespaces[metav1.NamespaceAll] || d.sparkJobNamespaces[ns]
+// getPodMutations returns the sorted fields changed between the original and the mutated pod. Fields of containers
+// present in both pods are qualified with the container name, e.g. `spec.containers[spark-kubernetes-driver].env`.
+func getPodMutations(original *corev1.Pod, mutated *corev1.Pod) ([]string, error) {
+	operations, err := createPodPatchOperations(original, mutated)
+	if err != nil {
+		return nil, err
+	}
+
+	seen := make(map[string]bool)
+	var mutations []string
+	for _, operation := range operations {
+		field := getPodMutationField(operation.Path, original, mutated)
+		if field != "" && !seen[field] {
+			seen[field] = true
+			mutations = append(mutations, field)
+		}
+	}
+	slices.Sort(mutations)
+	return mutations, nil
+}
+
+// getPodMutationField maps the path of a JSON patch operation on a pod to the field it changes.
+func getPodMutationField(path string, original *corev1.Pod, mutated *corev1.Pod) string {
+	segments := strings.Split(strings.TrimPrefix(path, "/"), "/")
+	for i := range segments {
+		segments[i] = strings.NewReplacer("~1", "/", "~0", "~").Replace(segments[i])
+	}
+	if len(segments) < 2 {
+		return segments[0]
+	}
+
+	field := segments[0] + "." + segments[1]
+	if segments[0] != "spec" || len(segments) < 4 {
+		return field
+	}
+
+	var originalContainers, mutatedContainers []corev1.Container
+	switch segments[1] {
+	case "containers":
+		originalContainers, mutatedContainers = original.Spec.Containers, mutated.Spec.Containers
+	case "initContainers":
+		originalContainers, mutatedContainers = original.Spec.InitContainers, mutated.Spec.InitContainers
+	default:
+		return field
+	}
+	index, err := strconv.Atoi(segments[2])
+	if err != nil {
+		return field
+	}
+	var name string
+	if index < len(mutatedContainers) {
+		name = mutatedContainers[index].Name
+	} else if index < len(originalContainers) {
+		name = originalContainers[index].Name
+	}
+	return fmt.Sprintf("%s[%s].%s", field, name, segments[3])
+}
+
+// createPodPatch returns the JSON patch transforming the original pod into the mutated one.
+func createPodPatch(original *corev1.Pod, mutated *corev1.Pod) ([]byte, error) {
+	operations, err := createPodPatchOperations(original, mutated)
+	if err != nil {
+		return nil, err
+	}
+	return json.Marshal(operations)
+}
+
+// createPodPatchOperations returns the JSON patch operations transforming the original pod into the mutated one.
+func createPodPatchOperations(original *corev1.Pod, mutated *corev1.Pod) ([]jsonpatch.Operation, error) {
+	originalJSON, err := json.Marshal(original)
+	if err != nil {
+		return nil, err
+	}
+	mutatedJSON, err := json.Marshal(mutated)
+	if err != nil {
+		return nil, err
+	}
+	return jsonpatch.CreatePatch(originalJSON, mutatedJSON)
+}
+
+func (d *SparkPodDefaulter) isSparkJobNamespace(ctx context.Context, ns string) (bool, error) {
+	if !d.sparkJobNamespaces[metav1.NamespaceAll] && !d.sparkJobNamespaces[ns] {
+		return false, nil
+	}
+
+	if d.namespaceSelector == nil || d.namespaceSelector.Empty() {
+		return true, nil
+	}
+
+	namespace := &corev1.Namespace{}
+	if err := d.client.Get(ctx, types.NamespacedName{Name: ns}, namespace); err != nil {
+		return false, fmt.Errorf("failed to get namespace %s: %v", ns, err)
+	}
+	return d.namespaceSelector.Matches(labels.Set(namespace.Labels)), nil
 }
 
 type mutateSparkPodOption func(pod *corev1.Pod, app *v1beta2.SparkApplication) error
@@ -111,25 +338,38 @@ func mutateSparkPod(pod *corev1.Pod, app *v1beta2.SparkApplication) error {
 		addHadoopConfigMap,
 		addSparkConfigMap,
 		addGeneralConfigMaps,
+		addMainApplicationFileConfigMap,
+		addConfPropertiesConfigMap,
+		addOCIArtifacts,
+		addKerberos,
 		addVolumes,
 		addContainerPorts,
 		addHostNetwork,
 		addHostAliases,
 		addInitContainers,
+		addEphemeralStorage,
 		addSidecarContainers,
+		addSparkUIOAuth2Proxy,
 		addDNSConfig,
 		addPriorityClassName,
 		addSchedulerName,
+		addRuntimeClassName,
 		addNodeSelectors,
 		addAffinity,
+		addTopologyPolicy,
+		addExecutorMaxPerNode,
 		addTolerations,
 		addGPU,
 		addPrometheusConfig,
 		addContainerSecurityContext,
+		addInjectedContainersSecurityContext,
+		addContainerProbes,
 		addPodSecurityContext,
 		addTerminationGracePeriodSeconds,
 		addPodLifeCycleConfig,
 		addShareProcessNamespace,
+		addSchedulingGates,
+		addSecretHash,
 	}
 
 	for _, option := range options {
@@ -226,15 +466,33 @@ func addEnvFrom(pod *corev1.Pod, app *v1beta2.SparkApplication) error {
 		envFrom = app.Spec.Executor.EnvFrom
 	}
 
+	if len(envFrom) == 0 {
+		return nil
+	}
+
 	i := findContainer(pod)
 	if i < 0 {
 		return fmt.Errorf("failed to add envFrom as Spark container not found")
 	}
 
-	pod.Spec.Containers[i].EnvFrom = append(pod.Spec.Containers[i].EnvFrom, envFrom...)
+	// Skip sources the container already has, e.g. from the pod template, so that they are not duplicated.
+	for _, source := range envFrom {
+		if !hasEnvFromSource(pod.Spec.Containers[i].EnvFrom, source) {
+			pod.Spec.Containers[i].EnvFrom = append(pod.Spec.Containers[i].EnvFrom, source)
+		}
+	}
 	return nil
 }
 
+func hasEnvFromSource(sources []corev1.EnvFromSource, source corev1.EnvFromSource) bool {
+	for _, s := range sources {
+		if equality.Semantic.DeepEqual(s, source) {
+			return true
+		}
+	}
+	return false
+}
+
 func addEnvironmentVariable(pod *corev1.Pod, name, value string) error {
 	i := findContainer(pod)
 	if i < 0 {
@@ -288,6 +546,243 @@ func addHadoopConfigMap(pod *corev1.Pod, app *v1beta2.SparkApplication) error {
 	return nil
 }
 
+func addMainApplicationFileConfigMap(pod *corev1.Pod, app *v1beta2.SparkApplication) error {
+	if !util.IsDriverPod(pod) || !util.IsMainApplicationFileInConfigMap(app) {
+		return nil
+	}
+
+	configMapName, _, err := util.GetMainApplicationFileConfigMap(app)
+	if err != nil {
+		return err
+	}
+
+	if err := addConfigMapVolume(pod, configMapName, common.MainApplicationFileConfigMapVolumeName); err != nil {
+		return err
+	}
+
+	return addConfigMapVolumeMount(pod, common.MainApplicationFileConfigMapVolumeName, common.DefaultMainApplicationFileMountPath)
+}
+
+// addConfPropertiesConfigMap mounts the ConfigMap holding the SparkConf and HadoopConf properties of the app into
+// the Spark container of the driver and executor pods.
+func addConfPropertiesConfigMap(pod *corev1.Pod, app *v1beta2.SparkApplication) error {
+	if !util.ShouldMountConfProperties(app) {
+		return nil
+	}
+
+	if err := addConfigMapVolume(pod, naming.ConfPropertiesConfigMapName(app), common.ConfPropertiesConfigMapVolumeName); err != nil {
+		return err
+	}
+
+	return addConfigMapVolumeMount(pod, common.ConfPropertiesConfigMapVolumeName, common.DefaultConfPropertiesMountPath)
+}
+
+// addOCIArtifacts adds an init container per OCI artifact referenced by the main application file or the
+// dependencies, which pulls the artifact into a volume mounted into the Spark container.
+func addOCIArtifacts(pod *corev1.Pod, app *v1beta2.SparkApplication) error {
+	references, err := util.GetOCIArtifacts(app)
+	if err != nil {
+		return err
+	}
+	if len(references) == 0 {
+		return nil
+	}
+
+	i := findContainer(pod)
+	if i < 0 {
+		return fmt.Errorf("failed to add OCI artifacts as Spark container not found")
+	}
+
+	image := common.DefaultOCIArtifactPullerImage
+	var registrySecret *string
+	if spec := app.Spec.Deps.OCIArtifacts; spec != nil {
+		if spec.Image != nil {
+			image = *spec.Image
+		}
+		registrySecret = spec.RegistrySecret
+	}
+
+	volume := corev1.Volume{
+		Name: common.OCIArtifactVolumeName,
+		VolumeSource: corev1.VolumeSource{
+			EmptyDir: &corev1.EmptyDirVolumeSource{},
+		},
+	}
+	if err := addVolume(pod, volume); err != nil {
+		return err
+	}
+	mounts := []corev1.VolumeMount{
+		{
+			Name:      common.OCIArtifactVolumeName,
+			MountPath: common.DefaultOCIArtifactMountPath,
+		},
+	}
+	pod.Spec.Containers[i].VolumeMounts = append(pod.Spec.Containers[i].VolumeMounts, corev1.VolumeMount{
+		Name:      common.OCIArtifactVolumeName,
+		MountPath: common.DefaultOCIArtifactMountPath,
+		ReadOnly:  true,
+	})
+
+	var registryArgs []string
+	if registrySecret != nil {
+		volume := corev1.Volume{
+			Name: common.OCIArtifactRegistryConfigVolumeName,
+			VolumeSource: corev1.VolumeSource{
+				Secret: &corev1.SecretVolumeSource{
+					SecretName: *registrySecret,
+					Items: []corev1.KeyToPath{
+						{Key: corev1.DockerConfigJsonKey, Path: "config.json"},
+					},
+				},
+			},
+		}
+		if err := addVolume(pod, volume); err != nil {
+			return err
+		}
+		mounts = append(mounts, corev1.VolumeMount{
+			Name:      common.OCIArtifactRegistryConfigVolumeName,
+			MountPath: common.OCIArtifactRegistryConfigMountPath,
+			ReadOnly:  true,
+		})
+		registryArgs = []string{"--registry-config", fmt.Sprintf("%s/config.json", common.OCIArtifactRegistryConfigMountPath)}
+	}
+
+	for index, reference := range references {
+		initContainer := corev1.Container{
+			Name:         fmt.Sprintf("%s-%d", common.OCIArtifactPullerContainerNamePrefix, index),
+			Image:        image,
+			Args:         append([]string{"pull", reference, "--output", util.GetOCIArtifactDir(reference)}, registryArgs...),
+			VolumeMounts: mounts,
+		}
+		if !hasInitContainer(pod, &initContainer) {
+			pod.Spec.InitContainers = append(pod.Spec.InitContainers, initContainer)
+		}
+	}
+	return nil
+}
+
+// addKerberos mounts the Kerberos keytab and configuration into the Spark container, points it to a ticket cache
+// shared with an init container obtaining the initial ticket, and, if a renewal interval is set, with a sidecar
+// container renewing the ticket periodically.
+func addKerberos(pod *corev1.Pod, app *v1beta2.SparkApplication) error {
+	kerberos := app.Spec.Kerberos
+	if kerberos == nil {
+		return nil
+	}
+
+	i := findContainer(pod)
+	if i < 0 {
+		return fmt.Errorf("failed to add Kerberos configuration as Spark container not found")
+	}
+
+	keytabKey := common.DefaultKerberosKeytabSecretKey
+	if kerberos.KeytabSecretKey != nil {
+		keytabKey = *kerberos.KeytabSecretKey
+	}
+	image := pod.Spec.Containers[i].Image
+	if kerberos.Image != nil {
+		image = *kerberos.Image
+	}
+
+	volumes := []corev1.Volume{
+		{
+			Name: common.KerberosKeytabVolumeName,
+			VolumeSource: corev1.VolumeSource{
+				Secret: &corev1.SecretVolumeSource{
+					SecretName: kerberos.KeytabSecret,
+					Items: []corev1.KeyToPath{
+						{Key: keytabKey, Path: common.DefaultKerberosKeytabSecretKey},
+					},
+				},
+			},
+		},
+		{
+			Name: common.KerberosKrb5ConfVolumeName,
+			VolumeSource: corev1.VolumeSource{
+				ConfigMap: &corev1.ConfigMapVolumeSource{
+					LocalObjectReference: corev1.LocalObjectReference{Name: kerberos.Krb5ConfigMap},
+				},
+			},
+		},
+		{
+			Name: common.KerberosCCacheVolumeName,
+			VolumeSource: corev1.VolumeSource{
+				EmptyDir: &corev1.EmptyDirVolumeSource{Medium: corev1.StorageMediumMemory},
+			},
+		},
+	}
+	for _, volume := range volumes {
+		if err := addVolume(pod, volume); err != nil {
+			return err
+		}
+	}
+
+	mounts := []corev1.VolumeMount{
+		{
+			Name:      common.KerberosKeytabVolumeName,
+			MountPath: common.KerberosKeytabMountPath,
+			ReadOnly:  true,
+		},
+		{
+			Name:      common.KerberosKrb5ConfVolumeName,
+			MountPath: common.KerberosKrb5ConfPath,
+			SubPath:   common.KerberosKrb5ConfFileName,
+			ReadOnly:  true,
+		},
+		{
+			Name:      common.KerberosCCacheVolumeName,
+			MountPath: common.KerberosCCacheMountPath,
+		},
+	}
+	env := []corev1.EnvVar{
+		{
+			Name:  common.EnvKrb5Config,
+			Value: common.KerberosKrb5ConfPath,
+		},
+		{
+			Name:  common.EnvKrb5CCName,
+			Value: fmt.Sprintf("FILE:%s/ccache", common.KerberosCCacheMountPath),
+		},
+	}
+	pod.Spec.Containers[i].VolumeMounts = append(pod.Spec.Containers[i].VolumeMounts, mounts...)
+	pod.Spec.Containers[i].Env = append(pod.Spec.Containers[i].Env, env...)
+
+	kinit := fmt.Sprintf("kinit -kt %s/%s %s", common.KerberosKeytabMountPath, common.DefaultKerberosKeytabSecretKey, kerberos.Principal)
+	initContainer := corev1.Container{
+		Name:         common.KerberosInitContainerName,
+		Image:        image,
+		Command:      []string{"/bin/sh", "-c", kinit},
+		Env:          env,
+		VolumeMounts: mounts,
+	}
+	if !hasInitContainer(pod, &initContainer) {
+		pod.Spec.InitContainers = append(pod.Spec.InitContainers, initContainer)
+	}
+
+	if kerberos.RenewalInterval == nil {
+		return nil
+	}
+	interval, err := time.ParseDuration(*kerberos.RenewalInterval)
+	if err != nil {
+		return fmt.Errorf("failed to parse Kerberos renewal interval: %v", err)
+	}
+	// The renewer never exits, so it runs as a native sidecar, which does not keep the pod from completing, and
+	// starts after the init container obtained the initial ticket.
+	restartPolicy := corev1.ContainerRestartPolicyAlways
+	renewer := corev1.Container{
+		Name:          common.KerberosRenewerContainerName,
+		Image:         image,
+		Command:       []string{"/bin/sh", "-c", fmt.Sprintf("while true; do sleep %d; %s; done", int64(interval.Seconds()), kinit)},
+		Env:           env,
+		VolumeMounts:  mounts,
+		RestartPolicy: &restartPolicy,
+	}
+	if !hasInitContainer(pod, &renewer) {
+		pod.Spec.InitContainers = append(pod.Spec.InitContainers, renewer)
+	}
+	return nil
+}
+
 func addGeneralConfigMaps(pod *corev1.Pod, app *v1beta2.SparkApplication) error {
 	var configMaps []v1beta2.NamePath
 	if util.IsDriverPod(pod) {
@@ -424,6 +919,131 @@ func addAffinity(pod *corev1.Pod, app *v1beta2.SparkApplication) error {
 	return nil
 }
 
+func addTopologyPolicy(pod *corev1.Pod, app *v1beta2.SparkApplication) error {
+	if !util.IsExecutorPod(pod) {
+		return nil
+	}
+
+	policy := app.Spec.Executor.TopologyPolicy
+	if policy == nil || *policy != v1beta2.TopologyPolicyZoneAffinity {
+		return nil
+	}
+
+	if pod.Spec.Affinity == nil {
+		pod.Spec.Affinity = &corev1.Affinity{}
+	}
+	if pod.Spec.Affinity.PodAffinity == nil {
+		pod.Spec.Affinity.PodAffinity = &corev1.PodAffinity{}
+	}
+	podAffinity := pod.Spec.Affinity.PodAffinity
+
+	// Prefer co-locating executors of the same application in the same zone.
+	zoneTerm := corev1.PodAffinityTerm{
+		LabelSelector: &metav1.LabelSelector{
+			MatchLabels: map[string]string{
+				common.LabelSparkAppName: app.Name,
+				common.LabelSparkRole:    common.SparkRoleExecutor,
+			},
+		},
+		TopologyKey: corev1.LabelTopologyZone,
+	}
+	exists := false
+	for _, term := range podAffinity.PreferredDuringSchedulingIgnoredDuringExecution {
+		if isSamePodAffinityTerm(term.PodAffinityTerm, zoneTerm) {
+			exists = true
+			break
+		}
+	}
+	if !exists {
+		podAffinity.PreferredDuringSchedulingIgnoredDuringExecution = append(
+			podAffinity.PreferredDuringSchedulingIgnoredDuringExecution,
+			corev1.WeightedPodAffinityTerm{Weight: 100, PodAffinityTerm: zoneTerm},
+		)
+	}
+
+	// Executors are created by the driver, so the driver pod is already scheduled at this point
+	// and its zone is known to the scheduler.
+	if pin := app.Spec.Executor.PinToDriverZone; pin != nil && *pin {
+		driverZoneTerm := corev1.PodAffinityTerm{
+			LabelSelector: &metav1.LabelSelector{
+				MatchLabels: map[string]string{
+					common.LabelSparkAppName: app.Name,
+					common.LabelSparkRole:    common.SparkRoleDriver,
+				},
+			},
+			TopologyKey: corev1.LabelTopologyZone,
+		}
+		exists := false
+		for _, term := range podAffinity.RequiredDuringSchedulingIgnoredDuringExecution {
+			if isSamePodAffinityTerm(term, driverZoneTerm) {
+				exists = true
+				break
+			}
+		}
+		if !exists {
+			podAffinity.RequiredDuringSchedulingIgnoredDuringExecution = append(
+				podAffinity.RequiredDuringSchedulingIgnoredDuringExecution,
+				driverZoneTerm,
+			)
+		}
+	}
+	return nil
+}
+
+// isSamePodAffinityTerm returns whether the pod affinity terms select the same pods in the same topology domain.
+func isSamePodAffinityTerm(a, b corev1.PodAffinityTerm) bool {
+	return a.TopologyKey == b.TopologyKey && equality.Semantic.DeepEqual(a.LabelSelector, b.LabelSelector)
+}
+
+// addExecutorMaxPerNode spreads the executors of the application across nodes so that at most MaxPerNode
+// executors land on the same node.
+func addExecutorMaxPerNode(pod *corev1.Pod, app *v1beta2.SparkApplication) error {
+	if !util.IsExecutorPod(pod) {
+		return nil
+	}
+
+	maxPerNode := app.Spec.Executor.MaxPerNode
+	if maxPerNode == nil {
+		return nil
+	}
+
+	selector := &metav1.LabelSelector{
+		MatchLabels: map[string]string{
+			common.LabelSparkAppName: app.Name,
+			common.LabelSparkRole:    common.SparkRoleExecutor,
+		},
+	}
+
+	// A hard limit of one executor per node is expressed exactly with a pod anti-affinity.
+	if *maxPerNode == 1 {
+		if pod.Spec.Affinity == nil {
+			pod.Spec.Affinity = &corev1.Affinity{}
+		}
+		if pod.Spec.Affinity.PodAntiAffinity == nil {
+			pod.Spec.Affinity.PodAntiAffinity = &corev1.PodAntiAffinity{}
+		}
+		podAntiAffinity := pod.Spec.Affinity.PodAntiAffinity
+		podAntiAffinity.RequiredDuringSchedulingIgnoredDuringExecution = append(
+			podAntiAffinity.RequiredDuringSchedulingIgnoredDuringExecution,
+			corev1.PodAffinityTerm{
+				LabelSelector: selector,
+				TopologyKey:   corev1.LabelHostname,
+			},
+		)
+		return nil
+	}
+
+	// The skew is measured against the node with the fewest executors, which is zero as long as some
+	// eligible node runs none of them, so it bounds the number of executors per node in that case.
+	pod.Spec.TopologySpreadConstraints = append(pod.Spec.TopologySpreadConstraints, corev1.TopologySpreadConstraint{
+		MaxSkew:           *maxPerNode,
+		TopologyKey:       corev1.LabelHostname,
+		WhenUnsatisfiable: corev1.DoNotSchedule,
+		LabelSelector:     selector,
+	})
+	return nil
+}
+
 func addTolerations(pod *corev1.Pod, app *v1beta2.SparkApplication) error {
 	var tolerations []corev1.Toleration
 	if util.IsDriverPod(pod) {
@@ -459,15 +1079,34 @@ func addNodeSelectors(pod *corev1.Pod, app *v1beta2.SparkApplication) error {
 }
 
 func addDNSConfig(pod *corev1.Pod, app *v1beta2.SparkApplication) error {
+	var dnsPolicy *corev1.DNSPolicy
 	var dnsConfig *corev1.PodDNSConfig
+	var searchDomains []string
 	if util.IsDriverPod(pod) {
+		dnsPolicy = app.Spec.Driver.DNSPolicy
 		dnsConfig = app.Spec.Driver.DNSConfig
+		searchDomains = app.Spec.Driver.DNSSearchDomains
 	} else if util.IsExecutorPod(pod) {
+		dnsPolicy = app.Spec.Executor.DNSPolicy
 		dnsConfig = app.Spec.Executor.DNSConfig
+		searchDomains = app.Spec.Executor.DNSSearchDomains
 	}
 
+	if dnsPolicy != nil {
+		pod.Spec.DNSPolicy = *dnsPolicy
+	}
 	if dnsConfig != nil {
-		pod.Spec.DNSConfig = dnsConfig
+		pod.Spec.DNSConfig = dnsConfig.DeepCopy()
+	}
+	if len(searchDomains) > 0 {
+		if pod.Spec.DNSConfig == nil {
+			pod.Spec.DNSConfig = &corev1.PodDNSConfig{}
+		}
+		for _, domain := range searchDomains {
+			if !slices.Contains(pod.Spec.DNSConfig.Searches, domain) {
+				pod.Spec.DNSConfig.Searches = append(pod.Spec.DNSConfig.Searches, domain)
+			}
+		}
 	}
 	return nil
 }
@@ -491,6 +1130,22 @@ func addSchedulerName(pod *corev1.Pod, app *v1beta2.SparkApplication) error {
 	return nil
 }
 
+func addRuntimeClassName(pod *corev1.Pod, app *v1beta2.SparkApplication) error {
+	var runtimeClassName *string
+	if util.IsDriverPod(pod) {
+		runtimeClassName = app.Spec.Driver.RuntimeClassName
+	} else if util.IsExecutorPod(pod) {
+		runtimeClassName = app.Spec.Executor.RuntimeClassName
+	}
+
+	if runtimeClassName == nil || *runtimeClassName == "" {
+		return nil
+	}
+
+	pod.Spec.RuntimeClassName = runtimeClassName
+	return nil
+}
+
 func addPriorityClassName(pod *corev1.Pod, app *v1beta2.SparkApplication) error {
 	var priorityClassName *string
 
@@ -545,15 +1200,89 @@ func addContainerSecurityContext(pod *corev1.Pod, app *v1beta2.SparkApplication)
 	return nil
 }
 
+// addInjectedContainersSecurityContext applies the security context of the Spark container to the containers the
+// operator injects, e.g. the Kerberos and OCI artifact init containers, which do not set one themselves, so that pods
+// complying with the restricted PodSecurity standard stay compliant.
+func addInjectedContainersSecurityContext(pod *corev1.Pod, app *v1beta2.SparkApplication) error {
+	var securityContext *corev1.SecurityContext
+	if util.IsDriverPod(pod) {
+		securityContext = app.Spec.Driver.SecurityContext
+	} else if util.IsExecutorPod(pod) {
+		securityContext = app.Spec.Executor.SecurityContext
+	}
+	if securityContext == nil {
+		return nil
+	}
+
+	for _, containers := range [][]corev1.Container{pod.Spec.InitContainers, pod.Spec.Containers} {
+		for i := range containers {
+			if containers[i].SecurityContext == nil && isInjectedContainer(containers[i].Name) {
+				containers[i].SecurityContext = securityContext.DeepCopy()
+			}
+		}
+	}
+	return nil
+}
+
+// isInjectedContainer returns whether the container with the given name is injected by the operator.
+func isInjectedContainer(name string) bool {
+	switch name {
+	case common.KerberosInitContainerName, common.KerberosRenewerContainerName, common.SparkUIOAuth2ProxyContainerName:
+		return true
+	}
+	return strings.HasPrefix(name, common.OCIArtifactPullerContainerNamePrefix+"-")
+}
+
+func addContainerProbes(pod *corev1.Pod, app *v1beta2.SparkApplication) error {
+	var podSpec *v1beta2.SparkPodSpec
+	if util.IsDriverPod(pod) {
+		podSpec = &app.Spec.Driver.SparkPodSpec
+	} else if util.IsExecutorPod(pod) {
+		podSpec = &app.Spec.Executor.SparkPodSpec
+	} else {
+		return nil
+	}
+	if podSpec.LivenessProbe == nil && podSpec.ReadinessProbe == nil && podSpec.StartupProbe == nil {
+		return nil
+	}
+
+	i := findContainer(pod)
+	if i < 0 {
+		return fmt.Errorf("Spark container not found in pod %s", pod.Name)
+	}
+	if podSpec.LivenessProbe != nil {
+		pod.Spec.Containers[i].LivenessProbe = podSpec.LivenessProbe
+	}
+	if podSpec.ReadinessProbe != nil {
+		pod.Spec.Containers[i].ReadinessProbe = podSpec.ReadinessProbe
+	}
+	if podSpec.StartupProbe != nil {
+		pod.Spec.Containers[i].StartupProbe = podSpec.StartupProbe
+	}
+	return nil
+}
+
 func addSidecarContainers(pod *corev1.Pod, app *v1beta2.SparkApplication) error {
-	var sidecars []corev1.Container
+	var podSpec *v1beta2.SparkPodSpec
 	if util.IsDriverPod(pod) {
-		sidecars = app.Spec.Driver.Sidecars
+		podSpec = &app.Spec.Driver.SparkPodSpec
 	} else if util.IsExecutorPod(pod) {
-		sidecars = app.Spec.Executor.Sidecars
+		podSpec = &app.Spec.Executor.SparkPodSpec
+	} else {
+		return nil
 	}
 
-	for _, sidecar := range sidecars {
+	// Native sidecars run as init containers, which Kubernetes terminates once the main container has exited.
+	if nativeSidecars := util.GetNativeSidecars(podSpec); nativeSidecars != nil {
+		for _, sidecar := range nativeSidecars {
+			if !hasInitContainer(pod, &sidecar) {
+				pod.Spec.InitContainers = append(pod.Spec.InitContainers, sidecar)
+			}
+		}
+		return nil
+	}
+
+	for _, sidecar := range podSpec.Sidecars {
 		if !hasContainer(pod, &sidecar) {
 			pod.Spec.Containers = append(pod.Spec.Containers, *sidecar.DeepCopy())
 		}
@@ -561,6 +1290,58 @@ func addSidecarContainers(pod *corev1.Pod, app *v1beta2.SparkApplication) error
 	return nil
 }
 
+// addSparkUIOAuth2Proxy adds the oauth2-proxy sidecar authenticating users of the web UI to the driver pod. The web UI
+// service targets the port of the sidecar, which proxies authenticated requests to the web UI.
+func addSparkUIOAuth2Proxy(pod *corev1.Pod, app *v1beta2.SparkApplication) error {
+	proxy := util.GetWebUIOAuth2Proxy(app)
+	if proxy == nil || !util.IsDriverPod(pod) {
+		return nil
+	}
+
+	image := common.DefaultSparkUIOAuth2ProxyImage
+	if proxy.Image != nil && *proxy.Image != "" {
+		image = *proxy.Image
+	}
+	port := util.GetWebUIOAuth2ProxyPort(proxy)
+
+	secretEnvVar := func(name string, key string) corev1.EnvVar {
+		return corev1.EnvVar{
+			Name: name,
+			ValueFrom: &corev1.EnvVarSource{
+				SecretKeyRef: &corev1.SecretKeySelector{
+					LocalObjectReference: corev1.LocalObjectReference{Name: proxy.SecretName},
+					Key:                  key,
+				},
+			},
+		}
+	}
+	container := corev1.Container{
+		Name:  common.SparkUIOAuth2ProxyContainerName,
+		Image: image,
+		Args: append([]string{
+			fmt.Sprintf("--http-address=0.0.0.0:%d", port),
+			fmt.Sprintf("--upstream=http://127.0.0.1:%d/", util.GetWebUIPort(app)),
+			"--reverse-proxy=true",
+		}, proxy.Args...),
+		Env: []corev1.EnvVar{
+			secretEnvVar("OAUTH2_PROXY_CLIENT_ID", "client-id"),
+			secretEnvVar("OAUTH2_PROXY_CLIENT_SECRET", "client-secret"),
+			secretEnvVar("OAUTH2_PROXY_COOKIE_SECRET", "cookie-secret"),
+		},
+		Ports: []corev1.ContainerPort{
+			{
+				Name:          "oauth2-proxy",
+				ContainerPort: port,
+				Protocol:      corev1.ProtocolTCP,
+			},
+		},
+	}
+	if !hasContainer(pod, &container) {
+		pod.Spec.Containers = append(pod.Spec.Containers, container)
+	}
+	return nil
+}
+
 func addInitContainers(pod *corev1.Pod, app *v1beta2.SparkApplication) error {
 	var initContainers []corev1.Container
 	if util.IsDriverPod(pod) {
@@ -581,6 +1362,29 @@ func addInitContainers(pod *corev1.Pod, app *v1beta2.SparkApplication) error {
 	return nil
 }
 
+func addEphemeralStorage(pod *corev1.Pod, app *v1beta2.SparkApplication) error {
+	var podSpec *v1beta2.SparkPodSpec
+	if util.IsDriverPod(pod) {
+		podSpec = &app.Spec.Driver.SparkPodSpec
+	} else if util.IsExecutorPod(pod) {
+		podSpec = &app.Spec.Executor.SparkPodSpec
+	}
+	if podSpec == nil || (podSpec.EphemeralStorage == nil && podSpec.EphemeralStorageLimit == nil) {
+		return nil
+	}
+
+	i := findContainer(pod)
+	if i < 0 {
+		return fmt.Errorf("failed to add ephemeral storage as Spark container was not found in pod %s", pod.Name)
+	}
+	requests, limits, err := util.GetEphemeralStorageResources(podSpec)
+	if err != nil {
+		return err
+	}
+	util.SetContainerResources(&pod.Spec.Containers[i], requests, limits)
+	return nil
+}
+
 func addGPU(pod *corev1.Pod, app *v1beta2.SparkApplication) error {
 	var gpu *v1beta2.GPUSpec
 	if util.IsDriverPod(pod) {
@@ -650,29 +1454,19 @@ func addTerminationGracePeriodSeconds(pod *corev1.Pod, app *v1beta2.SparkApplica
 
 func addPodLifeCycleConfig(pod *corev1.Pod, app *v1beta2.SparkApplication) error {
 	var lifeCycle *corev1.Lifecycle
-	var containerName string
 	if util.IsDriverPod(pod) {
 		lifeCycle = app.Spec.Driver.Lifecycle
-		containerName = common.SparkDriverContainerName
 	} else if util.IsExecutorPod(pod) {
 		lifeCycle = app.Spec.Executor.Lifecycle
-		containerName = common.SparkExecutorContainerName
 	}
 	if lifeCycle == nil {
 		return nil
 	}
 
-	i := 0
-	// Find the driver container in the pod.
-	for ; i < len(pod.Spec.Containers); i++ {
-		if pod.Spec.Containers[i].Name == containerName {
-			break
-		}
-	}
-	if i == len(pod.Spec.Containers) {
-		return fmt.Errorf("Spark container %s not found in pod %s", containerName, pod.Name)
+	i := findContainer(pod)
+	if i < 0 {
+		return fmt.Errorf("Spark container not found in pod %s", pod.Name)
 	}
-
 	pod.Spec.Containers[i].Lifecycle = lifeCycle
 	return nil
 }
@@ -705,6 +1499,43 @@ func addShareProcessNamespace(pod *corev1.Pod, app *v1beta2.SparkApplication) er
 	return nil
 }
 
+// addSecretHash records the hash of the Secrets the executor pod mounts, so that the controller only restarts the
+// executors created before the Secrets changed when rolling out rotated Secrets.
+func addSecretHash(pod *corev1.Pod, app *v1beta2.SparkApplication) error {
+	if !util.IsExecutorPod(pod) || app.Spec.SecretRotation == nil || app.Status.SecretRotation == nil ||
+		app.Status.SecretRotation.SecretHash == "" {
+		return nil
+	}
+	if pod.Annotations == nil {
+		pod.Annotations = make(map[string]string)
+	}
+	pod.Annotations[common.AnnotationSecretHash] = app.Status.SecretRotation.SecretHash
+	return nil
+}
+
+func addSchedulingGates(pod *corev1.Pod, app *v1beta2.SparkApplication) error {
+	var schedulingGates []corev1.PodSchedulingGate
+	if util.IsDriverPod(pod) {
+		schedulingGates = app.Spec.Driver.SchedulingGates
+	} else if util.IsExecutorPod(pod) {
+		schedulingGates = app.Spec.Executor.SchedulingGates
+	}
+
+	for _, gate := range schedulingGates {
+		exists := false
+		for _, g := range pod.Spec.SchedulingGates {
+			if g.Name == gate.Name {
+				exists = true
+				break
+			}
+		}
+		if !exists {
+			pod.Spec.SchedulingGates = append(pod.Spec.SchedulingGates, gate)
+		}
+	}
+	return nil
+}
+
 func findContainer(pod *corev1.Pod) int {
 	var candidateContainerNames []string
 	if util.IsDriverPod(pod) {
@@ -17,16 +17,27 @@ limitations under the License.
 package webhook
 
 import (
+	"context"
 	"fmt"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
+	admissionv1 "k8s.io/api/admission/v1"
 	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
 
 	"github.com/kubeflow/spark-operator/api/v1beta2"
 	"github.com/kubeflow/spark-operator/pkg/common"
+	"github.com/kubeflow/spark-operator/pkg/naming"
+	"github.com/kubeflow/spark-operator/pkg/util"
 )
 
 func TestPatchSparkPod_OwnerReference(t *testing.T) {
@@ -493,6 +504,200 @@ func TestPatchSparkPod_HadoopConfigMap(t *testing.T) {
 	assert.Equal(t, common.DefaultHadoopConfDir, modifiedPod.Spec.Containers[0].Env[0].Value)
 }
 
+func TestPatchSparkPod_ConfPropertiesConfigMap(t *testing.T) {
+	app := &v1beta2.SparkApplication{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: "spark-test",
+			UID:  "spark-test-1",
+		},
+		Spec: v1beta2.SparkApplicationSpec{
+			MountConfProperties: ptr.To(true),
+		},
+	}
+
+	for _, pod := range []*corev1.Pod{
+		{
+			ObjectMeta: metav1.ObjectMeta{
+				Name: "spark-driver",
+				Labels: map[string]string{
+					common.LabelSparkRole:               common.SparkRoleDriver,
+					common.LabelLaunchedBySparkOperator: "true",
+				},
+			},
+			Spec: corev1.PodSpec{
+				Containers: []corev1.Container{{Name: common.SparkDriverContainerName, Image: "spark-driver:latest"}},
+			},
+		},
+		{
+			ObjectMeta: metav1.ObjectMeta{
+				Name: "spark-executor",
+				Labels: map[string]string{
+					common.LabelSparkRole:               common.SparkRoleExecutor,
+					common.LabelLaunchedBySparkOperator: "true",
+				},
+			},
+			Spec: corev1.PodSpec{
+				Containers: []corev1.Container{{Name: common.SparkExecutorContainerName, Image: "spark-executor:latest"}},
+			},
+		},
+	} {
+		modifiedPod, err := getModifiedPod(pod, app)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		assert.Len(t, modifiedPod.Spec.Volumes, 1)
+		assert.Equal(t, common.ConfPropertiesConfigMapVolumeName, modifiedPod.Spec.Volumes[0].Name)
+		assert.Equal(t, "spark-test-conf-properties", modifiedPod.Spec.Volumes[0].ConfigMap.Name)
+		assert.Len(t, modifiedPod.Spec.Containers[0].VolumeMounts, 1)
+		assert.Equal(t, common.DefaultConfPropertiesMountPath, modifiedPod.Spec.Containers[0].VolumeMounts[0].MountPath)
+	}
+
+	app.Spec.MountConfProperties = nil
+	pod := &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: "spark-driver",
+			Labels: map[string]string{
+				common.LabelSparkRole:               common.SparkRoleDriver,
+				common.LabelLaunchedBySparkOperator: "true",
+			},
+		},
+		Spec: corev1.PodSpec{
+			Containers: []corev1.Container{{Name: common.SparkDriverContainerName, Image: "spark-driver:latest"}},
+		},
+	}
+	modifiedPod, err := getModifiedPod(pod, app)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Empty(t, modifiedPod.Spec.Volumes)
+}
+
+func TestPatchSparkPod_Kerberos(t *testing.T) {
+	app := &v1beta2.SparkApplication{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: "spark-test",
+			UID:  "spark-test-1",
+		},
+		Spec: v1beta2.SparkApplicationSpec{
+			Kerberos: &v1beta2.KerberosSpec{
+				Principal:       "spark@EXAMPLE.COM",
+				KeytabSecret:    "spark-keytab",
+				Krb5ConfigMap:   "krb5-conf",
+				RenewalInterval: util.StringPtr("1h"),
+			},
+		},
+	}
+
+	pod := &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: "spark-executor",
+			Labels: map[string]string{
+				common.LabelSparkRole:               common.SparkRoleExecutor,
+				common.LabelLaunchedBySparkOperator: "true",
+			},
+		},
+		Spec: corev1.PodSpec{
+			Containers: []corev1.Container{
+				{
+					Name:  common.SparkExecutorContainerName,
+					Image: "spark-executor:latest",
+				},
+			},
+		},
+	}
+
+	modifiedPod, err := getModifiedPod(pod, app)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	assert.Len(t, modifiedPod.Spec.Volumes, 3)
+	assert.Equal(t, "spark-keytab", modifiedPod.Spec.Volumes[0].Secret.SecretName)
+	assert.Equal(t, "krb5-conf", modifiedPod.Spec.Volumes[1].ConfigMap.Name)
+	assert.NotNil(t, modifiedPod.Spec.Volumes[2].EmptyDir)
+	assert.Len(t, modifiedPod.Spec.Containers[0].VolumeMounts, 3)
+	assert.Contains(t, modifiedPod.Spec.Containers[0].Env, corev1.EnvVar{Name: common.EnvKrb5Config, Value: common.KerberosKrb5ConfPath})
+
+	// The renewer runs as a native sidecar started after the initial ticket is obtained, so that it does not keep
+	// the pod from completing.
+	assert.Len(t, modifiedPod.Spec.InitContainers, 2)
+	assert.Equal(t, common.KerberosInitContainerName, modifiedPod.Spec.InitContainers[0].Name)
+	assert.Equal(t, "spark-executor:latest", modifiedPod.Spec.InitContainers[0].Image)
+	assert.Contains(t, modifiedPod.Spec.InitContainers[0].Command[2], "kinit -kt /etc/security/keytabs/krb5.keytab spark@EXAMPLE.COM")
+	assert.Nil(t, modifiedPod.Spec.InitContainers[0].RestartPolicy)
+
+	assert.Equal(t, common.KerberosRenewerContainerName, modifiedPod.Spec.InitContainers[1].Name)
+	assert.Contains(t, modifiedPod.Spec.InitContainers[1].Command[2], "sleep 3600")
+	assert.Equal(t, ptr.To(corev1.ContainerRestartPolicyAlways), modifiedPod.Spec.InitContainers[1].RestartPolicy)
+
+	assert.Len(t, modifiedPod.Spec.Containers, 1)
+	assert.Equal(t, common.SparkExecutorContainerName, modifiedPod.Spec.Containers[0].Name)
+}
+
+func TestPatchSparkPod_InjectedContainersSecurityContext(t *testing.T) {
+	securityContext := &corev1.SecurityContext{
+		RunAsNonRoot:             util.BoolPtr(true),
+		AllowPrivilegeEscalation: util.BoolPtr(false),
+		ReadOnlyRootFilesystem:   util.BoolPtr(true),
+		Capabilities:             &corev1.Capabilities{Drop: []corev1.Capability{"ALL"}},
+		SeccompProfile:           &corev1.SeccompProfile{Type: corev1.SeccompProfileTypeRuntimeDefault},
+	}
+	app := &v1beta2.SparkApplication{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: "spark-test",
+			UID:  "spark-test-1",
+		},
+		Spec: v1beta2.SparkApplicationSpec{
+			Kerberos: &v1beta2.KerberosSpec{
+				Principal:       "spark@EXAMPLE.COM",
+				KeytabSecret:    "spark-keytab",
+				Krb5ConfigMap:   "krb5-conf",
+				RenewalInterval: util.StringPtr("1h"),
+			},
+			Executor: v1beta2.ExecutorSpec{
+				SparkPodSpec: v1beta2.SparkPodSpec{
+					SecurityContext: securityContext,
+				},
+			},
+		},
+	}
+
+	pod := &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: "spark-executor",
+			Labels: map[string]string{
+				common.LabelSparkRole:               common.SparkRoleExecutor,
+				common.LabelLaunchedBySparkOperator: "true",
+			},
+		},
+		Spec: corev1.PodSpec{
+			Containers: []corev1.Container{
+				{
+					Name:  common.SparkExecutorContainerName,
+					Image: "spark-executor:latest",
+				},
+				{
+					Name:  "user-sidecar",
+					Image: "sidecar:latest",
+				},
+			},
+		},
+	}
+
+	modifiedPod, err := getModifiedPod(pod, app)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	assert.Equal(t, securityContext, modifiedPod.Spec.Containers[0].SecurityContext)
+	assert.Nil(t, modifiedPod.Spec.Containers[1].SecurityContext)
+	assert.Equal(t, common.KerberosInitContainerName, modifiedPod.Spec.InitContainers[0].Name)
+	assert.Equal(t, securityContext, modifiedPod.Spec.InitContainers[0].SecurityContext)
+	assert.Equal(t, common.KerberosRenewerContainerName, modifiedPod.Spec.InitContainers[1].Name)
+	assert.Equal(t, securityContext, modifiedPod.Spec.InitContainers[1].SecurityContext)
+}
+
 // func TestPatchSparkPod_PrometheusConfigMaps(t *testing.T) {
 // 	var appPort int32 = 9999
 // 	appPortName := "jmx-exporter"
@@ -772,6 +977,75 @@ func TestPatchSparkPod_SchedulerName(t *testing.T) {
 	assert.Equal(t, defaultScheduler, modifiedExecutorPod.Spec.SchedulerName)
 }
 
+func TestPatchSparkPod_RuntimeClassName(t *testing.T) {
+	var runtimeClassName = "gvisor"
+
+	app := &v1beta2.SparkApplication{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: "spark-test-patch-runtimeclassname",
+			UID:  "spark-test-1",
+		},
+		Spec: v1beta2.SparkApplicationSpec{
+			Driver: v1beta2.DriverSpec{
+				SparkPodSpec: v1beta2.SparkPodSpec{
+					RuntimeClassName: &runtimeClassName,
+				},
+			},
+			Executor: v1beta2.ExecutorSpec{
+				SparkPodSpec: v1beta2.SparkPodSpec{},
+			},
+		},
+	}
+
+	driverPod := &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: "spark-driver",
+			Labels: map[string]string{
+				common.LabelSparkRole:               common.SparkRoleDriver,
+				common.LabelLaunchedBySparkOperator: "true",
+			},
+		},
+		Spec: corev1.PodSpec{
+			Containers: []corev1.Container{
+				{
+					Name:  common.SparkDriverContainerName,
+					Image: "spark-driver:latest",
+				},
+			},
+		},
+	}
+
+	modifiedDriverPod, err := getModifiedPod(driverPod, app)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, &runtimeClassName, modifiedDriverPod.Spec.RuntimeClassName)
+
+	executorPod := &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: "spark-executor",
+			Labels: map[string]string{
+				common.LabelSparkRole:               common.SparkRoleExecutor,
+				common.LabelLaunchedBySparkOperator: "true",
+			},
+		},
+		Spec: corev1.PodSpec{
+			Containers: []corev1.Container{
+				{
+					Name:  common.SparkExecutorContainerName,
+					Image: "spark-executor:latest",
+				},
+			},
+		},
+	}
+
+	modifiedExecutorPod, err := getModifiedPod(executorPod, app)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Nil(t, modifiedExecutorPod.Spec.RuntimeClassName)
+}
+
 func TestPatchSparkPod_PriorityClassName(t *testing.T) {
 	var priorityClassName = "critical"
 
@@ -940,7 +1214,7 @@ func TestPatchSparkPod_Sidecars(t *testing.T) {
 	assert.Equal(t, "sidecar2", modifiedExecutorPod.Spec.Containers[2].Name)
 }
 
-func TestPatchSparkPod_InitContainers(t *testing.T) {
+func TestPatchSparkPod_NativeSidecars(t *testing.T) {
 	app := &v1beta2.SparkApplication{
 		ObjectMeta: metav1.ObjectMeta{
 			Name: "spark-test",
@@ -949,29 +1223,59 @@ func TestPatchSparkPod_InitContainers(t *testing.T) {
 		Spec: v1beta2.SparkApplicationSpec{
 			Driver: v1beta2.DriverSpec{
 				SparkPodSpec: v1beta2.SparkPodSpec{
-					InitContainers: []corev1.Container{
-						{
-							Name:  "init-container1",
-							Image: "init-container1:latest",
-						},
+					Sidecars: []corev1.Container{
 						{
-							Name:  "init-container2",
-							Image: "init-container2:latest",
+							Name:  "log-shipper",
+							Image: "log-shipper:latest",
 						},
 					},
+					NativeSidecars: ptr.To(true),
 				},
 			},
-			Executor: v1beta2.ExecutorSpec{
-				SparkPodSpec: v1beta2.SparkPodSpec{
-					InitContainers: []corev1.Container{
-						{
-							Name:  "init-container1",
-							Image: "init-container1:latest",
-						},
-						{
-							Name:  "init-container2",
-							Image: "init-container2:latest",
-						},
+		},
+	}
+
+	driverPod := &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: "spark-driver",
+			Labels: map[string]string{
+				common.LabelSparkRole:               common.SparkRoleDriver,
+				common.LabelLaunchedBySparkOperator: "true",
+			},
+		},
+		Spec: corev1.PodSpec{
+			Containers: []corev1.Container{
+				{
+					Name:  common.SparkDriverContainerName,
+					Image: "spark-driver:latest",
+				},
+			},
+		},
+	}
+
+	modifiedDriverPod, err := getModifiedPod(driverPod, app)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Len(t, modifiedDriverPod.Spec.Containers, 1)
+	assert.Len(t, modifiedDriverPod.Spec.InitContainers, 1)
+	assert.Equal(t, "log-shipper", modifiedDriverPod.Spec.InitContainers[0].Name)
+	assert.Equal(t, ptr.To(corev1.ContainerRestartPolicyAlways), modifiedDriverPod.Spec.InitContainers[0].RestartPolicy)
+	assert.Nil(t, app.Spec.Driver.Sidecars[0].RestartPolicy)
+}
+
+func TestPatchSparkPod_SparkUIOAuth2Proxy(t *testing.T) {
+	app := &v1beta2.SparkApplication{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: "spark-test",
+			UID:  "spark-test-1",
+		},
+		Spec: v1beta2.SparkApplicationSpec{
+			SparkUIOptions: &v1beta2.SparkUIConfiguration{
+				Auth: &v1beta2.SparkUIAuth{
+					OAuth2Proxy: &v1beta2.SparkUIOAuth2Proxy{
+						SecretName: "spark-ui-oauth2",
+						Args:       []string{"--provider=github"},
 					},
 				},
 			},
@@ -1000,9 +1304,19 @@ func TestPatchSparkPod_InitContainers(t *testing.T) {
 	if err != nil {
 		t.Fatal(err)
 	}
-	assert.Len(t, modifiedDriverPod.Spec.InitContainers, 2)
-	assert.Equal(t, "init-container1", modifiedDriverPod.Spec.InitContainers[0].Name)
-	assert.Equal(t, "init-container2", modifiedDriverPod.Spec.InitContainers[1].Name)
+	assert.Len(t, modifiedDriverPod.Spec.Containers, 2)
+	proxy := modifiedDriverPod.Spec.Containers[1]
+	assert.Equal(t, common.SparkUIOAuth2ProxyContainerName, proxy.Name)
+	assert.Equal(t, common.DefaultSparkUIOAuth2ProxyImage, proxy.Image)
+	assert.Equal(t, []string{
+		"--http-address=0.0.0.0:4180",
+		"--upstream=http://127.0.0.1:4040/",
+		"--reverse-proxy=true",
+		"--provider=github",
+	}, proxy.Args)
+	assert.Len(t, proxy.Env, 3)
+	assert.Equal(t, "spark-ui-oauth2", proxy.Env[0].ValueFrom.SecretKeyRef.Name)
+	assert.Equal(t, common.DefaultSparkUIOAuth2ProxyPort, proxy.Ports[0].ContainerPort)
 
 	executorPod := &corev1.Pod{
 		ObjectMeta: metav1.ObjectMeta{
@@ -1026,9 +1340,174 @@ func TestPatchSparkPod_InitContainers(t *testing.T) {
 	if err != nil {
 		t.Fatal(err)
 	}
-	assert.Len(t, modifiedExecutorPod.Spec.InitContainers, 2)
-	assert.Equal(t, "init-container1", modifiedExecutorPod.Spec.InitContainers[0].Name)
-	assert.Equal(t, "init-container2", modifiedExecutorPod.Spec.InitContainers[1].Name)
+	assert.Len(t, modifiedExecutorPod.Spec.Containers, 1)
+}
+
+func TestPatchSparkPod_InitContainers(t *testing.T) {
+	app := &v1beta2.SparkApplication{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: "spark-test",
+			UID:  "spark-test-1",
+		},
+		Spec: v1beta2.SparkApplicationSpec{
+			Driver: v1beta2.DriverSpec{
+				SparkPodSpec: v1beta2.SparkPodSpec{
+					InitContainers: []corev1.Container{
+						{
+							Name:  "init-container1",
+							Image: "init-container1:latest",
+						},
+						{
+							Name:  "init-container2",
+							Image: "init-container2:latest",
+						},
+					},
+				},
+			},
+			Executor: v1beta2.ExecutorSpec{
+				SparkPodSpec: v1beta2.SparkPodSpec{
+					InitContainers: []corev1.Container{
+						{
+							Name:  "init-container1",
+							Image: "init-container1:latest",
+						},
+						{
+							Name:  "init-container2",
+							Image: "init-container2:latest",
+						},
+					},
+				},
+			},
+		},
+	}
+
+	driverPod := &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: "spark-driver",
+			Labels: map[string]string{
+				common.LabelSparkRole:               common.SparkRoleDriver,
+				common.LabelLaunchedBySparkOperator: "true",
+			},
+		},
+		Spec: corev1.PodSpec{
+			Containers: []corev1.Container{
+				{
+					Name:  common.SparkDriverContainerName,
+					Image: "spark-driver:latest",
+				},
+			},
+		},
+	}
+
+	modifiedDriverPod, err := getModifiedPod(driverPod, app)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Len(t, modifiedDriverPod.Spec.InitContainers, 2)
+	assert.Equal(t, "init-container1", modifiedDriverPod.Spec.InitContainers[0].Name)
+	assert.Equal(t, "init-container2", modifiedDriverPod.Spec.InitContainers[1].Name)
+
+	executorPod := &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: "spark-executor",
+			Labels: map[string]string{
+				common.LabelSparkRole:               common.SparkRoleExecutor,
+				common.LabelLaunchedBySparkOperator: "true",
+			},
+		},
+		Spec: corev1.PodSpec{
+			Containers: []corev1.Container{
+				{
+					Name:  common.SparkExecutorContainerName,
+					Image: "spark-executor:latest",
+				},
+			},
+		},
+	}
+
+	modifiedExecutorPod, err := getModifiedPod(executorPod, app)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Len(t, modifiedExecutorPod.Spec.InitContainers, 2)
+	assert.Equal(t, "init-container1", modifiedExecutorPod.Spec.InitContainers[0].Name)
+	assert.Equal(t, "init-container2", modifiedExecutorPod.Spec.InitContainers[1].Name)
+
+	// Pods created from pod templates already have the init containers, which are not added again.
+	templatedExecutorPod := executorPod.DeepCopy()
+	templatedExecutorPod.Spec.InitContainers = append([]corev1.Container{}, app.Spec.Executor.InitContainers...)
+	modifiedExecutorPod, err = getModifiedPod(templatedExecutorPod, app)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Len(t, modifiedExecutorPod.Spec.InitContainers, 2)
+}
+
+func TestPatchSparkPod_DNSPolicy(t *testing.T) {
+	app := &v1beta2.SparkApplication{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: "spark-test",
+			UID:  "spark-test-1",
+		},
+		Spec: v1beta2.SparkApplicationSpec{
+			Driver: v1beta2.DriverSpec{
+				SparkPodSpec: v1beta2.SparkPodSpec{
+					HostNetwork: util.BoolPtr(true),
+					DNSPolicy:   ptr.To(corev1.DNSDefault),
+				},
+			},
+		},
+	}
+
+	driverPod := &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: "spark-driver",
+			Labels: map[string]string{
+				common.LabelSparkRole:               common.SparkRoleDriver,
+				common.LabelLaunchedBySparkOperator: "true",
+			},
+		},
+		Spec: corev1.PodSpec{
+			Containers: []corev1.Container{
+				{
+					Name:  common.SparkDriverContainerName,
+					Image: "spark-driver:latest",
+				},
+			},
+		},
+	}
+
+	modifiedDriverPod, err := getModifiedPod(driverPod, app)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// The DNS policy takes precedence over the policy of hostNetwork pods.
+	assert.True(t, modifiedDriverPod.Spec.HostNetwork)
+	assert.Equal(t, corev1.DNSDefault, modifiedDriverPod.Spec.DNSPolicy)
+
+	executorPod := &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: "spark-executor",
+			Labels: map[string]string{
+				common.LabelSparkRole:               common.SparkRoleExecutor,
+				common.LabelLaunchedBySparkOperator: "true",
+			},
+		},
+		Spec: corev1.PodSpec{
+			Containers: []corev1.Container{
+				{
+					Name:  common.SparkExecutorContainerName,
+					Image: "spark-executor:latest",
+				},
+			},
+		},
+	}
+
+	modifiedExecutorPod, err := getModifiedPod(executorPod, app)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Empty(t, modifiedExecutorPod.Spec.DNSPolicy)
 }
 
 func TestPatchSparkPod_DNSConfig(t *testing.T) {
@@ -1108,6 +1587,80 @@ func TestPatchSparkPod_DNSConfig(t *testing.T) {
 	assert.Equal(t, sampleDNSConfig, modifiedExecutorPod.Spec.DNSConfig)
 }
 
+func TestPatchSparkPod_DNSSearchDomains(t *testing.T) {
+	app := &v1beta2.SparkApplication{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "spark-test",
+			Namespace: "spark",
+			UID:       "spark-test-1",
+		},
+		Spec: v1beta2.SparkApplicationSpec{
+			Driver: v1beta2.DriverSpec{
+				SparkPodSpec: v1beta2.SparkPodSpec{DNSSearchDomains: []string{"spark.svc.cluster.local"}},
+			},
+			Executor: v1beta2.ExecutorSpec{
+				SparkPodSpec: v1beta2.SparkPodSpec{
+					DNSConfig: &corev1.PodDNSConfig{
+						Searches: []string{"svc.cluster.local"},
+					},
+					DNSSearchDomains: []string{"spark.svc.cluster.local", "svc.cluster.local"},
+				},
+			},
+		},
+	}
+
+	driverPod := &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: "spark-driver",
+			Labels: map[string]string{
+				common.LabelSparkRole:               common.SparkRoleDriver,
+				common.LabelLaunchedBySparkOperator: "true",
+			},
+		},
+		Spec: corev1.PodSpec{
+			Containers: []corev1.Container{
+				{
+					Name:  common.SparkDriverContainerName,
+					Image: "spark-driver:latest",
+				},
+			},
+		},
+	}
+
+	modifiedDriverPod, err := getModifiedPod(driverPod, app)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.NotNil(t, modifiedDriverPod.Spec.DNSConfig)
+	assert.Equal(t, []string{"spark.svc.cluster.local"}, modifiedDriverPod.Spec.DNSConfig.Searches)
+
+	executorPod := &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: "spark-executor",
+			Labels: map[string]string{
+				common.LabelSparkRole:               common.SparkRoleExecutor,
+				common.LabelLaunchedBySparkOperator: "true",
+			},
+		},
+		Spec: corev1.PodSpec{
+			Containers: []corev1.Container{
+				{
+					Name:  common.SparkExecutorContainerName,
+					Image: "spark-executor:latest",
+				},
+			},
+		},
+	}
+
+	modifiedExecutorPod, err := getModifiedPod(executorPod, app)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.NotNil(t, modifiedExecutorPod.Spec.DNSConfig)
+	assert.Equal(t, []string{"svc.cluster.local", "spark.svc.cluster.local"}, modifiedExecutorPod.Spec.DNSConfig.Searches)
+	assert.Equal(t, []string{"svc.cluster.local"}, app.Spec.Executor.DNSConfig.Searches)
+}
+
 func TestPatchSparkPod_NodeSector(t *testing.T) {
 	app := &v1beta2.SparkApplication{
 		ObjectMeta: metav1.ObjectMeta{
@@ -1181,17 +1734,64 @@ func TestPatchSparkPod_NodeSector(t *testing.T) {
 	assert.Equal(t, "secondvalue", modifiedExecutorPod.Spec.NodeSelector["secondkey"])
 }
 
-func TestPatchSparkPod_GPU(t *testing.T) {
-	cpuLimit := int64(10)
-	cpuRequest := int64(5)
+func TestPatchSparkPod_EphemeralStorage(t *testing.T) {
+	app := &v1beta2.SparkApplication{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: "spark-test",
+			UID:  "spark-test-1",
+		},
+		Spec: v1beta2.SparkApplicationSpec{
+			Executor: v1beta2.ExecutorSpec{
+				SparkPodSpec: v1beta2.SparkPodSpec{
+					EphemeralStorage:      util.StringPtr("10Gi"),
+					EphemeralStorageLimit: util.StringPtr("20Gi"),
+				},
+			},
+		},
+	}
 
-	type testcase struct {
-		gpuSpec     *v1beta2.GPUSpec
-		cpuLimits   *int64
-		cpuRequests *int64
+	pod := &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: "spark-executor",
+			Labels: map[string]string{
+				common.LabelSparkRole:               common.SparkRoleExecutor,
+				common.LabelLaunchedBySparkOperator: "true",
+			},
+		},
+		Spec: corev1.PodSpec{
+			Containers: []corev1.Container{
+				{
+					Name:  common.SparkExecutorContainerName,
+					Image: "spark-executor:latest",
+					Resources: corev1.ResourceRequirements{
+						Requests: corev1.ResourceList{corev1.ResourceCPU: resource.MustParse("1")},
+					},
+				},
+			},
+		},
 	}
 
-	getResourceRequirements := func(test testcase) *corev1.ResourceRequirements {
+	modifiedPod, err := getModifiedPod(pod, app)
+	if err != nil {
+		t.Fatal(err)
+	}
+	resources := modifiedPod.Spec.Containers[0].Resources
+	assert.Equal(t, "1", resources.Requests.Cpu().String())
+	assert.Equal(t, "10Gi", resources.Requests.StorageEphemeral().String())
+	assert.Equal(t, "20Gi", resources.Limits.StorageEphemeral().String())
+}
+
+func TestPatchSparkPod_GPU(t *testing.T) {
+	cpuLimit := int64(10)
+	cpuRequest := int64(5)
+
+	type testcase struct {
+		gpuSpec     *v1beta2.GPUSpec
+		cpuLimits   *int64
+		cpuRequests *int64
+	}
+
+	getResourceRequirements := func(test testcase) *corev1.ResourceRequirements {
 		if test.cpuLimits == nil && test.cpuRequests == nil {
 			return nil
 		}
@@ -1636,6 +2236,16 @@ func TestPatchSparkPod_EnvFrom(t *testing.T) {
 				{
 					Name:  common.SparkExecutorContainerName,
 					Image: "spark-driver:latest",
+					// Sources already in the container, e.g. from a pod template, are not duplicated.
+					EnvFrom: []corev1.EnvFromSource{
+						{
+							ConfigMapRef: &corev1.ConfigMapEnvSource{
+								LocalObjectReference: corev1.LocalObjectReference{
+									Name: configMapName,
+								},
+							},
+						},
+					},
 				},
 			},
 		},
@@ -1755,13 +2365,17 @@ func TestPatchSparkPod_Lifecycle(t *testing.T) {
 		},
 		Spec: v1beta2.SparkApplicationSpec{
 			Driver: v1beta2.DriverSpec{
-				Lifecycle: &corev1.Lifecycle{
-					PreStop: &corev1.LifecycleHandler{Exec: preStopTest},
+				SparkPodSpec: v1beta2.SparkPodSpec{
+					Lifecycle: &corev1.Lifecycle{
+						PreStop: &corev1.LifecycleHandler{Exec: preStopTest},
+					},
 				},
 			},
 			Executor: v1beta2.ExecutorSpec{
-				Lifecycle: &corev1.Lifecycle{
-					PostStart: &corev1.LifecycleHandler{Exec: postStartTest},
+				SparkPodSpec: v1beta2.SparkPodSpec{
+					Lifecycle: &corev1.Lifecycle{
+						PostStart: &corev1.LifecycleHandler{Exec: postStartTest},
+					},
 				},
 			},
 		},
@@ -1796,7 +2410,11 @@ func TestPatchSparkPod_Lifecycle(t *testing.T) {
 		Spec: corev1.PodSpec{
 			Containers: []corev1.Container{
 				{
-					Name:  common.SparkExecutorContainerName,
+					Name:  "sidecar",
+					Image: "sidecar:latest",
+				},
+				{
+					Name:  common.Spark3DefaultExecutorContainerName,
 					Image: "spark-executor:latest",
 				},
 			},
@@ -1812,7 +2430,86 @@ func TestPatchSparkPod_Lifecycle(t *testing.T) {
 		t.Fatal(err)
 	}
 	assert.Equal(t, preStopTest, modifiedDriverPod.Spec.Containers[0].Lifecycle.PreStop.Exec)
-	assert.Equal(t, postStartTest, modifiedExecutorPod.Spec.Containers[0].Lifecycle.PostStart.Exec)
+	assert.Nil(t, modifiedExecutorPod.Spec.Containers[0].Lifecycle)
+	assert.Equal(t, postStartTest, modifiedExecutorPod.Spec.Containers[1].Lifecycle.PostStart.Exec)
+}
+
+func TestPatchSparkPod_Probes(t *testing.T) {
+	livenessProbe := &corev1.Probe{
+		ProbeHandler:  corev1.ProbeHandler{HTTPGet: &corev1.HTTPGetAction{Path: "/", Port: intstr.FromInt32(4040)}},
+		PeriodSeconds: 30,
+	}
+	startupProbe := &corev1.Probe{
+		ProbeHandler:     corev1.ProbeHandler{TCPSocket: &corev1.TCPSocketAction{Port: intstr.FromInt32(7079)}},
+		FailureThreshold: 30,
+	}
+	app := &v1beta2.SparkApplication{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: "spark-test",
+			UID:  "spark-test-1",
+		},
+		Spec: v1beta2.SparkApplicationSpec{
+			Driver: v1beta2.DriverSpec{
+				SparkPodSpec: v1beta2.SparkPodSpec{
+					LivenessProbe: livenessProbe,
+				},
+			},
+			Executor: v1beta2.ExecutorSpec{
+				SparkPodSpec: v1beta2.SparkPodSpec{
+					StartupProbe: startupProbe,
+				},
+			},
+		},
+	}
+
+	driverPod := &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: "spark-driver",
+			Labels: map[string]string{
+				common.LabelSparkRole:               common.SparkRoleDriver,
+				common.LabelLaunchedBySparkOperator: "true",
+			},
+		},
+		Spec: corev1.PodSpec{
+			Containers: []corev1.Container{
+				{
+					Name:  common.SparkDriverContainerName,
+					Image: "spark-driver:latest",
+				},
+			},
+		},
+	}
+
+	executorPod := &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: "spark-executor",
+			Labels: map[string]string{
+				common.LabelSparkRole:               common.SparkRoleExecutor,
+				common.LabelLaunchedBySparkOperator: "true",
+			},
+		},
+		Spec: corev1.PodSpec{
+			Containers: []corev1.Container{
+				{
+					Name:  common.Spark3DefaultExecutorContainerName,
+					Image: "spark-executor:latest",
+				},
+			},
+		},
+	}
+
+	modifiedDriverPod, err := getModifiedPod(driverPod, app)
+	if err != nil {
+		t.Fatal(err)
+	}
+	modifiedExecutorPod, err := getModifiedPod(executorPod, app)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, livenessProbe, modifiedDriverPod.Spec.Containers[0].LivenessProbe)
+	assert.Nil(t, modifiedDriverPod.Spec.Containers[0].StartupProbe)
+	assert.Equal(t, startupProbe, modifiedExecutorPod.Spec.Containers[0].StartupProbe)
+	assert.Nil(t, modifiedExecutorPod.Spec.Containers[0].LivenessProbe)
 }
 
 func getModifiedPod(old *corev1.Pod, app *v1beta2.SparkApplication) (*corev1.Pod, error) {
@@ -2086,3 +2783,673 @@ func TestPatchSparkPod_ShareProcessNamespace(t *testing.T) {
 		}
 	}
 }
+
+func TestPatchSparkPod_SchedulingGates(t *testing.T) {
+	app := &v1beta2.SparkApplication{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: "spark-test",
+			UID:  "spark-test-1",
+		},
+		Spec: v1beta2.SparkApplicationSpec{
+			Driver: v1beta2.DriverSpec{
+				SparkPodSpec: v1beta2.SparkPodSpec{
+					SchedulingGates: []corev1.PodSchedulingGate{
+						{Name: "example.com/quota"},
+					},
+				},
+			},
+			Executor: v1beta2.ExecutorSpec{
+				SparkPodSpec: v1beta2.SparkPodSpec{
+					SchedulingGates: []corev1.PodSchedulingGate{
+						{Name: "example.com/quota"},
+						{Name: "example.com/dependency"},
+					},
+				},
+			},
+		},
+	}
+
+	driverPod := &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: "spark-driver",
+			Labels: map[string]string{
+				common.LabelSparkRole:               common.SparkRoleDriver,
+				common.LabelLaunchedBySparkOperator: "true",
+			},
+		},
+		Spec: corev1.PodSpec{
+			Containers: []corev1.Container{
+				{
+					Name:  common.SparkDriverContainerName,
+					Image: "spark-driver:latest",
+				},
+			},
+		},
+	}
+
+	executorPod := &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: "spark-executor",
+			Labels: map[string]string{
+				common.LabelSparkRole:               common.SparkRoleExecutor,
+				common.LabelLaunchedBySparkOperator: "true",
+			},
+		},
+		Spec: corev1.PodSpec{
+			Containers: []corev1.Container{
+				{
+					Name:  common.SparkExecutorContainerName,
+					Image: "spark-executor:latest",
+				},
+			},
+			SchedulingGates: []corev1.PodSchedulingGate{
+				{Name: "example.com/quota"},
+			},
+		},
+	}
+
+	modifiedDriverPod, err := getModifiedPod(driverPod, app)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, []corev1.PodSchedulingGate{{Name: "example.com/quota"}}, modifiedDriverPod.Spec.SchedulingGates)
+
+	modifiedExecutorPod, err := getModifiedPod(executorPod, app)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, []corev1.PodSchedulingGate{{Name: "example.com/quota"}, {Name: "example.com/dependency"}}, modifiedExecutorPod.Spec.SchedulingGates)
+}
+
+func TestPatchSparkPod_TopologyPolicy(t *testing.T) {
+	policy := v1beta2.TopologyPolicyZoneAffinity
+	app := &v1beta2.SparkApplication{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: "spark-test",
+			UID:  "spark-test-1",
+		},
+		Spec: v1beta2.SparkApplicationSpec{
+			Executor: v1beta2.ExecutorSpec{
+				TopologyPolicy:  &policy,
+				PinToDriverZone: util.BoolPtr(true),
+			},
+		},
+	}
+
+	driverPod := &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: "spark-driver",
+			Labels: map[string]string{
+				common.LabelSparkRole:               common.SparkRoleDriver,
+				common.LabelLaunchedBySparkOperator: "true",
+			},
+		},
+		Spec: corev1.PodSpec{
+			Containers: []corev1.Container{
+				{
+					Name:  common.SparkDriverContainerName,
+					Image: "spark-driver:latest",
+				},
+			},
+		},
+	}
+
+	executorPod := &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: "spark-executor",
+			Labels: map[string]string{
+				common.LabelSparkRole:               common.SparkRoleExecutor,
+				common.LabelLaunchedBySparkOperator: "true",
+			},
+		},
+		Spec: corev1.PodSpec{
+			Containers: []corev1.Container{
+				{
+					Name:  common.SparkExecutorContainerName,
+					Image: "spark-executor:latest",
+				},
+			},
+		},
+	}
+
+	modifiedDriverPod, err := getModifiedPod(driverPod, app)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Nil(t, modifiedDriverPod.Spec.Affinity)
+
+	modifiedExecutorPod, err := getModifiedPod(executorPod, app)
+	if err != nil {
+		t.Fatal(err)
+	}
+	podAffinity := modifiedExecutorPod.Spec.Affinity.PodAffinity
+	assert.Len(t, podAffinity.PreferredDuringSchedulingIgnoredDuringExecution, 1)
+	preferredTerm := podAffinity.PreferredDuringSchedulingIgnoredDuringExecution[0].PodAffinityTerm
+	assert.Equal(t, corev1.LabelTopologyZone, preferredTerm.TopologyKey)
+	assert.Equal(t, common.SparkRoleExecutor, preferredTerm.LabelSelector.MatchLabels[common.LabelSparkRole])
+	assert.Equal(t, "spark-test", preferredTerm.LabelSelector.MatchLabels[common.LabelSparkAppName])
+	assert.Len(t, podAffinity.RequiredDuringSchedulingIgnoredDuringExecution, 1)
+	requiredTerm := podAffinity.RequiredDuringSchedulingIgnoredDuringExecution[0]
+	assert.Equal(t, corev1.LabelTopologyZone, requiredTerm.TopologyKey)
+	assert.Equal(t, common.SparkRoleDriver, requiredTerm.LabelSelector.MatchLabels[common.LabelSparkRole])
+
+	// Defaulting an executor pod which already carries the terms, e.g. from a pod template, must not duplicate them.
+	remodifiedExecutorPod, err := getModifiedPod(modifiedExecutorPod, app)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, modifiedExecutorPod.Spec.Affinity, remodifiedExecutorPod.Spec.Affinity)
+}
+
+func TestPatchSparkPod_ExecutorMaxPerNode(t *testing.T) {
+	executorPod := &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: "spark-executor",
+			Labels: map[string]string{
+				common.LabelSparkRole:               common.SparkRoleExecutor,
+				common.LabelLaunchedBySparkOperator: "true",
+			},
+		},
+		Spec: corev1.PodSpec{
+			Containers: []corev1.Container{
+				{
+					Name:  common.SparkExecutorContainerName,
+					Image: "spark-executor:latest",
+				},
+			},
+		},
+	}
+
+	app := &v1beta2.SparkApplication{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: "spark-test",
+			UID:  "spark-test-1",
+		},
+		Spec: v1beta2.SparkApplicationSpec{
+			Executor: v1beta2.ExecutorSpec{
+				MaxPerNode: util.Int32Ptr(1),
+			},
+		},
+	}
+	modifiedPod, err := getModifiedPod(executorPod, app)
+	if err != nil {
+		t.Fatal(err)
+	}
+	terms := modifiedPod.Spec.Affinity.PodAntiAffinity.RequiredDuringSchedulingIgnoredDuringExecution
+	assert.Len(t, terms, 1)
+	assert.Equal(t, corev1.LabelHostname, terms[0].TopologyKey)
+	assert.Equal(t, "spark-test", terms[0].LabelSelector.MatchLabels[common.LabelSparkAppName])
+	assert.Empty(t, modifiedPod.Spec.TopologySpreadConstraints)
+
+	app.Spec.Executor.MaxPerNode = util.Int32Ptr(3)
+	modifiedPod, err = getModifiedPod(executorPod, app)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Nil(t, modifiedPod.Spec.Affinity)
+	assert.Len(t, modifiedPod.Spec.TopologySpreadConstraints, 1)
+	constraint := modifiedPod.Spec.TopologySpreadConstraints[0]
+	assert.Equal(t, int32(3), constraint.MaxSkew)
+	assert.Equal(t, corev1.LabelHostname, constraint.TopologyKey)
+	assert.Equal(t, corev1.DoNotSchedule, constraint.WhenUnsatisfiable)
+	assert.Equal(t, common.SparkRoleExecutor, constraint.LabelSelector.MatchLabels[common.LabelSparkRole])
+}
+
+func TestPatchSparkPod_MainApplicationFileConfigMap(t *testing.T) {
+	app := &v1beta2.SparkApplication{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: "spark-test",
+			UID:  "spark-test-1",
+		},
+		Spec: v1beta2.SparkApplicationSpec{
+			MainApplicationFile: util.StringPtr("configmap://my-scripts/pi.py"),
+		},
+	}
+
+	driverPod := &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: "spark-driver",
+			Labels: map[string]string{
+				common.LabelSparkRole:               common.SparkRoleDriver,
+				common.LabelLaunchedBySparkOperator: "true",
+			},
+		},
+		Spec: corev1.PodSpec{
+			Containers: []corev1.Container{
+				{
+					Name:  common.SparkDriverContainerName,
+					Image: "spark-driver:latest",
+				},
+			},
+		},
+	}
+
+	executorPod := &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: "spark-executor",
+			Labels: map[string]string{
+				common.LabelSparkRole:               common.SparkRoleExecutor,
+				common.LabelLaunchedBySparkOperator: "true",
+			},
+		},
+		Spec: corev1.PodSpec{
+			Containers: []corev1.Container{
+				{
+					Name:  common.SparkExecutorContainerName,
+					Image: "spark-executor:latest",
+				},
+			},
+		},
+	}
+
+	modifiedDriverPod, err := getModifiedPod(driverPod, app)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Len(t, modifiedDriverPod.Spec.Volumes, 1)
+	assert.Equal(t, common.MainApplicationFileConfigMapVolumeName, modifiedDriverPod.Spec.Volumes[0].Name)
+	assert.Equal(t, "my-scripts", modifiedDriverPod.Spec.Volumes[0].ConfigMap.Name)
+	assert.Len(t, modifiedDriverPod.Spec.Containers[0].VolumeMounts, 1)
+	assert.Equal(t, common.DefaultMainApplicationFileMountPath, modifiedDriverPod.Spec.Containers[0].VolumeMounts[0].MountPath)
+
+	modifiedExecutorPod, err := getModifiedPod(executorPod, app)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Empty(t, modifiedExecutorPod.Spec.Volumes)
+}
+
+func TestPatchSparkPod_SecretHash(t *testing.T) {
+	app := &v1beta2.SparkApplication{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: "spark-test",
+			UID:  "spark-test-1",
+		},
+		Spec: v1beta2.SparkApplicationSpec{
+			SecretRotation: &v1beta2.SecretRotationSpec{},
+		},
+		Status: v1beta2.SparkApplicationStatus{
+			SecretRotation: &v1beta2.SecretRotationStatus{SecretHash: "hash"},
+		},
+	}
+
+	newPod := func(role string, containerName string) *corev1.Pod {
+		return &corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{
+				Name: "spark-" + role,
+				Labels: map[string]string{
+					common.LabelSparkRole:               role,
+					common.LabelLaunchedBySparkOperator: "true",
+				},
+			},
+			Spec: corev1.PodSpec{
+				Containers: []corev1.Container{{Name: containerName, Image: "spark:latest"}},
+			},
+		}
+	}
+
+	modifiedExecutorPod, err := getModifiedPod(newPod(common.SparkRoleExecutor, common.SparkExecutorContainerName), app)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, "hash", modifiedExecutorPod.Annotations[common.AnnotationSecretHash])
+
+	modifiedDriverPod, err := getModifiedPod(newPod(common.SparkRoleDriver, common.SparkDriverContainerName), app)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.NotContains(t, modifiedDriverPod.Annotations, common.AnnotationSecretHash)
+
+	app.Spec.SecretRotation = nil
+	modifiedExecutorPod, err = getModifiedPod(newPod(common.SparkRoleExecutor, common.SparkExecutorContainerName), app)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.NotContains(t, modifiedExecutorPod.Annotations, common.AnnotationSecretHash)
+}
+
+func TestPatchSparkPod_OCIArtifacts(t *testing.T) {
+	app := &v1beta2.SparkApplication{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: "spark-test",
+			UID:  "spark-test-1",
+		},
+		Spec: v1beta2.SparkApplicationSpec{
+			MainApplicationFile: util.StringPtr("oci://ghcr.io/org/app:v1/app.jar"),
+			Deps: v1beta2.Dependencies{
+				Jars: []string{"oci://ghcr.io/org/app:v1/lib.jar", "local:///opt/spark/jars/other.jar"},
+				OCIArtifacts: &v1beta2.OCIArtifactsSpec{
+					RegistrySecret: util.StringPtr("registry-credentials"),
+				},
+			},
+		},
+	}
+
+	for _, role := range []string{common.SparkRoleDriver, common.SparkRoleExecutor} {
+		containerName := common.SparkDriverContainerName
+		if role == common.SparkRoleExecutor {
+			containerName = common.SparkExecutorContainerName
+		}
+		pod := &corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{
+				Name: "spark-" + role,
+				Labels: map[string]string{
+					common.LabelSparkRole:               role,
+					common.LabelLaunchedBySparkOperator: "true",
+				},
+			},
+			Spec: corev1.PodSpec{
+				Containers: []corev1.Container{
+					{
+						Name:  containerName,
+						Image: "spark:latest",
+					},
+				},
+			},
+		}
+
+		modifiedPod, err := getModifiedPod(pod, app)
+		if err != nil {
+			t.Fatal(err)
+		}
+		assert.Len(t, modifiedPod.Spec.Volumes, 2)
+		assert.Equal(t, common.OCIArtifactVolumeName, modifiedPod.Spec.Volumes[0].Name)
+		assert.Equal(t, "registry-credentials", modifiedPod.Spec.Volumes[1].Secret.SecretName)
+		assert.Len(t, modifiedPod.Spec.Containers[0].VolumeMounts, 1)
+		assert.Equal(t, common.DefaultOCIArtifactMountPath, modifiedPod.Spec.Containers[0].VolumeMounts[0].MountPath)
+		assert.Len(t, modifiedPod.Spec.InitContainers, 1)
+		assert.Equal(t, common.DefaultOCIArtifactPullerImage, modifiedPod.Spec.InitContainers[0].Image)
+		assert.Equal(t, []string{
+			"pull", "ghcr.io/org/app:v1", "--output", util.GetOCIArtifactDir("ghcr.io/org/app:v1"),
+			"--registry-config", common.OCIArtifactRegistryConfigMountPath + "/config.json",
+		}, modifiedPod.Spec.InitContainers[0].Args)
+	}
+}
+
+func TestIsSparkJobNamespace(t *testing.T) {
+	selected := &corev1.Namespace{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:   "selected",
+			Labels: map[string]string{"spark-jobs": "enabled"},
+		},
+	}
+	unselected := &corev1.Namespace{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: "unselected",
+		},
+	}
+	client := fake.NewClientBuilder().WithObjects(selected, unselected).Build()
+	selector := labels.SelectorFromSet(labels.Set{"spark-jobs": "enabled"})
+
+	testCases := []struct {
+		name       string
+		namespaces []string
+		selector   labels.Selector
+		namespace  string
+		expected   bool
+	}{
+		{name: "all namespaces", namespaces: nil, selector: nil, namespace: "unselected", expected: true},
+		{name: "listed namespace", namespaces: []string{"selected", "unselected"}, selector: nil, namespace: "unselected", expected: true},
+		{name: "unlisted namespace", namespaces: []string{"selected"}, selector: nil, namespace: "unselected", expected: false},
+		{name: "selected namespace", namespaces: nil, selector: selector, namespace: "selected", expected: true},
+		{name: "unselected namespace", namespaces: nil, selector: selector, namespace: "unselected", expected: false},
+		{name: "listed but unselected namespace", namespaces: []string{"unselected"}, selector: selector, namespace: "unselected", expected: false},
+		{name: "empty selector", namespaces: nil, selector: labels.Everything(), namespace: "unselected", expected: true},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			defaulter := NewSparkPodDefaulter(client, tc.namespaces, tc.selector, nil, false, false, false)
+			ok, err := defaulter.isSparkJobNamespace(context.TODO(), tc.namespace)
+			assert.NoError(t, err)
+			assert.Equal(t, tc.expected, ok)
+		})
+	}
+
+	defaulter := NewSparkPodDefaulter(client, nil, selector, nil, false, false, false)
+	_, err := defaulter.isSparkJobNamespace(context.TODO(), "missing")
+	assert.Error(t, err)
+}
+
+func TestSparkPodDefaulter_DryRun(t *testing.T) {
+	scheme := runtime.NewScheme()
+	assert.NoError(t, clientgoscheme.AddToScheme(scheme))
+	assert.NoError(t, v1beta2.AddToScheme(scheme))
+
+	app := &v1beta2.SparkApplication{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "spark-test",
+			Namespace: "default",
+			UID:       "spark-test-1",
+		},
+		Spec: v1beta2.SparkApplicationSpec{
+			Driver: v1beta2.DriverSpec{
+				SparkPodSpec: v1beta2.SparkPodSpec{
+					SchedulerName: ptr.To("custom-scheduler"),
+				},
+			},
+		},
+	}
+	client := fake.NewClientBuilder().WithScheme(scheme).WithObjects(app).Build()
+
+	newDriverPod := func() *corev1.Pod {
+		return &corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "spark-driver",
+				Namespace: "default",
+				Labels: map[string]string{
+					common.LabelSparkAppName:            app.Name,
+					common.LabelSparkRole:               common.SparkRoleDriver,
+					common.LabelLaunchedBySparkOperator: "true",
+				},
+			},
+			Spec: corev1.PodSpec{
+				Containers: []corev1.Container{
+					{
+						Name:  common.SparkDriverContainerName,
+						Image: "spark-driver:latest",
+					},
+				},
+			},
+		}
+	}
+
+	pod := newDriverPod()
+	defaulter := NewSparkPodDefaulter(client, nil, nil, nil, true, false, false)
+	assert.NoError(t, defaulter.Default(context.TODO(), pod))
+
+	// Only the annotation recording the patch is added.
+	expected := newDriverPod()
+	expected.Annotations = map[string]string{common.AnnotationDryRunPatch: pod.Annotations[common.AnnotationDryRunPatch]}
+	assert.Equal(t, expected, pod)
+	assert.Contains(t, pod.Annotations[common.AnnotationDryRunPatch], `"path":"/spec/schedulerName","value":"custom-scheduler"`)
+
+	pod = newDriverPod()
+	defaulter = NewSparkPodDefaulter(client, nil, nil, nil, false, false, false)
+	assert.NoError(t, defaulter.Default(context.TODO(), pod))
+	assert.Equal(t, "custom-scheduler", pod.Spec.SchedulerName)
+	assert.NotContains(t, pod.Annotations, common.AnnotationDryRunPatch)
+}
+
+func TestSparkPodDefaulter_AnnotateMutations(t *testing.T) {
+	scheme := runtime.NewScheme()
+	assert.NoError(t, clientgoscheme.AddToScheme(scheme))
+	assert.NoError(t, v1beta2.AddToScheme(scheme))
+
+	app := &v1beta2.SparkApplication{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "spark-test",
+			Namespace: "default",
+			UID:       "spark-test-1",
+		},
+		Spec: v1beta2.SparkApplicationSpec{
+			Driver: v1beta2.DriverSpec{
+				SparkPodSpec: v1beta2.SparkPodSpec{
+					SchedulerName: ptr.To("custom-scheduler"),
+					Env:           []corev1.EnvVar{{Name: "FOO", Value: "bar"}},
+				},
+			},
+		},
+	}
+	client := fake.NewClientBuilder().WithScheme(scheme).WithObjects(app).Build()
+
+	newDriverPod := func() *corev1.Pod {
+		return &corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "spark-driver",
+				Namespace: "default",
+				Labels: map[string]string{
+					common.LabelSparkAppName:            app.Name,
+					common.LabelSparkRole:               common.SparkRoleDriver,
+					common.LabelLaunchedBySparkOperator: "true",
+				},
+			},
+			Spec: corev1.PodSpec{
+				Containers: []corev1.Container{
+					{
+						Name:  common.SparkDriverContainerName,
+						Image: "spark-driver:latest",
+					},
+				},
+			},
+		}
+	}
+
+	pod := newDriverPod()
+	defaulter := NewSparkPodDefaulter(client, nil, nil, nil, false, true, false)
+	assert.NoError(t, defaulter.Default(context.TODO(), pod))
+	assert.Equal(t, "custom-scheduler", pod.Spec.SchedulerName)
+	assert.Equal(t,
+		"metadata.ownerReferences,spec.containers["+common.SparkDriverContainerName+"].env,spec.schedulerName",
+		pod.Annotations[common.AnnotationMutations],
+	)
+
+	pod = newDriverPod()
+	defaulter = NewSparkPodDefaulter(client, nil, nil, nil, false, false, false)
+	assert.NoError(t, defaulter.Default(context.TODO(), pod))
+	assert.NotContains(t, pod.Annotations, common.AnnotationMutations)
+}
+
+func TestSparkPodDefaulterEnforcesResourceReservations(t *testing.T) {
+	scheme := runtime.NewScheme()
+	assert.NoError(t, clientgoscheme.AddToScheme(scheme))
+	assert.NoError(t, v1beta2.AddToScheme(scheme))
+
+	app := &v1beta2.SparkApplication{
+		ObjectMeta: metav1.ObjectMeta{Name: "spark-test", Namespace: "default", UID: "spark-test-1"},
+	}
+	quota := &corev1.ResourceQuota{
+		ObjectMeta: metav1.ObjectMeta{Name: "quota", Namespace: "default"},
+		Spec: corev1.ResourceQuotaSpec{
+			Hard: corev1.ResourceList{corev1.ResourceRequestsCPU: resource.MustParse("4")},
+		},
+		Status: corev1.ResourceQuotaStatus{
+			Hard: corev1.ResourceList{corev1.ResourceRequestsCPU: resource.MustParse("4")},
+			Used: corev1.ResourceList{corev1.ResourceRequestsCPU: resource.MustParse("1")},
+		},
+	}
+	newReservation := func(name string) *corev1.ResourceQuota {
+		return &corev1.ResourceQuota{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      name,
+				Namespace: "default",
+				Labels:    map[string]string{common.LabelResourceReservation: "true"},
+			},
+			Spec: corev1.ResourceQuotaSpec{
+				Hard: corev1.ResourceList{corev1.ResourceRequestsCPU: resource.MustParse("2")},
+				ScopeSelector: &corev1.ScopeSelector{
+					MatchExpressions: []corev1.ScopedResourceSelectorRequirement{{
+						ScopeName: corev1.ResourceQuotaScopePriorityClass,
+						Operator:  corev1.ScopeSelectorOpIn,
+						Values:    []string{common.ResourceReservationPriorityClass},
+					}},
+				},
+			},
+		}
+	}
+	newExecutorPod := func(cpu string) *corev1.Pod {
+		return &corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "spark-exec-1",
+				Namespace: "default",
+				Labels: map[string]string{
+					common.LabelSparkAppName:            app.Name,
+					common.LabelSparkRole:               common.SparkRoleExecutor,
+					common.LabelLaunchedBySparkOperator: "true",
+				},
+			},
+			Spec: corev1.PodSpec{
+				Containers: []corev1.Container{{
+					Name:  common.Spark3DefaultExecutorContainerName,
+					Image: "spark-executor:latest",
+					Resources: corev1.ResourceRequirements{
+						Requests: corev1.ResourceList{corev1.ResourceCPU: resource.MustParse(cpu)},
+					},
+				}},
+			},
+		}
+	}
+	newContext := func(operation admissionv1.Operation) context.Context {
+		return admission.NewContextWithRequest(context.TODO(), admission.Request{
+			AdmissionRequest: admissionv1.AdmissionRequest{Operation: operation},
+		})
+	}
+
+	testCases := []struct {
+		name        string
+		reservation *corev1.ResourceQuota
+		enforcement bool
+		operation   admissionv1.Operation
+		cpu         string
+		expectError bool
+	}{
+		{
+			name:        "denies pods using resources reserved for other applications",
+			reservation: newReservation("other-app-reservation"),
+			enforcement: true,
+			operation:   admissionv1.Create,
+			cpu:         "2",
+			expectError: true,
+		},
+		{
+			name:        "admits pods fitting next to the reservations of other applications",
+			reservation: newReservation("other-app-reservation"),
+			enforcement: true,
+			operation:   admissionv1.Create,
+			cpu:         "1",
+		},
+		{
+			name:        "admits pods using the resources reserved for their own application",
+			reservation: newReservation(naming.ResourceReservationName(app)),
+			enforcement: true,
+			operation:   admissionv1.Create,
+			cpu:         "2",
+		},
+		{
+			name:        "does not enforce reservations without resource quota enforcement",
+			reservation: newReservation("other-app-reservation"),
+			operation:   admissionv1.Create,
+			cpu:         "2",
+		},
+		{
+			name:        "only validates pods being created",
+			reservation: newReservation("other-app-reservation"),
+			enforcement: true,
+			operation:   admissionv1.Update,
+			cpu:         "2",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			client := fake.NewClientBuilder().WithScheme(scheme).WithObjects(app, quota, tc.reservation).Build()
+			defaulter := NewSparkPodDefaulter(client, nil, nil, nil, false, false, tc.enforcement)
+			err := defaulter.Default(newContext(tc.operation), newExecutorPod(tc.cpu))
+			if tc.expectError {
+				assert.ErrorContains(t, err, `failed to validate resource quota "default/quota"`)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
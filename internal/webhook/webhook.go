@@ -17,7 +17,14 @@ limitations under the License.
 package webhook
 
 import (
+	"fmt"
+	"slices"
+
+	admissionregistrationv1 "k8s.io/api/admissionregistration/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	ctrl "sigs.k8s.io/controller-runtime"
+
+	"github.com/kubeflow/spark-operator/pkg/common"
 )
 
 var (
@@ -35,3 +42,94 @@ type Options struct {
 	WebhookMetricsBindAddress      string
 	EnableResourceQuotaEnforcement bool
 }
+
+// AdmissionPolicy holds the policies enforced on every webhook of the webhook configurations of the operator.
+// Policies left unset keep the values the webhook configurations were registered with.
+type AdmissionPolicy struct {
+	FailurePolicy *admissionregistrationv1.FailurePolicyType
+	SideEffects   *admissionregistrationv1.SideEffectClass
+	MatchPolicy   *admissionregistrationv1.MatchPolicyType
+	// NamespaceSelector replaces the namespace selector of every webhook.
+	NamespaceSelector *metav1.LabelSelector
+	// ObjectSelector narrows down the pods sent to the pod webhooks. Pods not launched by the operator are never
+	// sent to the pod webhooks regardless of the selector.
+	ObjectSelector *metav1.LabelSelector
+}
+
+// NewAdmissionPolicy creates a new AdmissionPolicy from the given failure policy, side effects, match policy and
+// label selectors, any of which can be empty to leave the corresponding policy unset.
+func NewAdmissionPolicy(failurePolicy, sideEffects, matchPolicy, namespaceSelector, objectSelector string) (AdmissionPolicy, error) {
+	policy := AdmissionPolicy{}
+
+	switch v := admissionregistrationv1.FailurePolicyType(failurePolicy); v {
+	case "":
+	case admissionregistrationv1.Ignore, admissionregistrationv1.Fail:
+		policy.FailurePolicy = &v
+	default:
+		return policy, fmt.Errorf("invalid failure policy %q, must be one of Ignore and Fail", failurePolicy)
+	}
+
+	switch v := admissionregistrationv1.SideEffectClass(sideEffects); v {
+	case "":
+	case admissionregistrationv1.SideEffectClassNone, admissionregistrationv1.SideEffectClassNoneOnDryRun:
+		policy.SideEffects = &v
+	default:
+		return policy, fmt.Errorf("invalid side effects %q, must be one of None and NoneOnDryRun", sideEffects)
+	}
+
+	switch v := admissionregistrationv1.MatchPolicyType(matchPolicy); v {
+	case "":
+	case admissionregistrationv1.Exact, admissionregistrationv1.Equivalent:
+		policy.MatchPolicy = &v
+	default:
+		return policy, fmt.Errorf("invalid match policy %q, must be one of Exact and Equivalent", matchPolicy)
+	}
+
+	if namespaceSelector != "" {
+		selector, err := parseLabelSelector(namespaceSelector)
+		if err != nil {
+			return policy, fmt.Errorf("invalid namespace selector %q: %v", namespaceSelector, err)
+		}
+		policy.NamespaceSelector = selector
+	}
+
+	if objectSelector != "" {
+		selector, err := parseLabelSelector(objectSelector)
+		if err != nil {
+			return policy, fmt.Errorf("invalid object selector %q: %v", objectSelector, err)
+		}
+		if selector.MatchLabels == nil {
+			selector.MatchLabels = map[string]string{}
+		}
+		selector.MatchLabels[common.LabelLaunchedBySparkOperator] = "true"
+		policy.ObjectSelector = selector
+	}
+
+	return policy, nil
+}
+
+// parseLabelSelector parses the given label selector, leaving the empty parts of the selector unset as the API
+// server does, so that selectors read back from the API server compare equal.
+func parseLabelSelector(s string) (*metav1.LabelSelector, error) {
+	selector, err := metav1.ParseToLabelSelector(s)
+	if err != nil {
+		return nil, err
+	}
+	if len(selector.MatchLabels) == 0 {
+		selector.MatchLabels = nil
+	}
+	if len(selector.MatchExpressions) == 0 {
+		selector.MatchExpressions = nil
+	}
+	return selector, nil
+}
+
+// MatchesPods returns whether the given webhook rules match pods.
+func MatchesPods(rules []admissionregistrationv1.RuleWithOperations) bool {
+	for _, rule := range rules {
+		if slices.Contains(rule.APIGroups, "") && slices.Contains(rule.Resources, "pods") {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,78 @@
+/*
+Copyright 2024 The Kubeflow authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package webhook
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	admissionregistrationv1 "k8s.io/api/admissionregistration/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/ptr"
+
+	"github.com/kubeflow/spark-operator/pkg/common"
+)
+
+func TestNewAdmissionPolicy(t *testing.T) {
+	policy, err := NewAdmissionPolicy("", "", "", "", "")
+	assert.NoError(t, err)
+	assert.Equal(t, AdmissionPolicy{}, policy)
+
+	policy, err = NewAdmissionPolicy("Fail", "None", "Exact", "", "")
+	assert.NoError(t, err)
+	assert.Equal(t, AdmissionPolicy{
+		FailurePolicy: ptr.To(admissionregistrationv1.Fail),
+		SideEffects:   ptr.To(admissionregistrationv1.SideEffectClassNone),
+		MatchPolicy:   ptr.To(admissionregistrationv1.Exact),
+	}, policy)
+
+	_, err = NewAdmissionPolicy("Retry", "", "", "", "")
+	assert.Error(t, err)
+	_, err = NewAdmissionPolicy("", "Some", "", "", "")
+	assert.Error(t, err)
+	_, err = NewAdmissionPolicy("", "", "Loose", "", "")
+	assert.Error(t, err)
+	_, err = NewAdmissionPolicy("", "", "", "team in (", "")
+	assert.Error(t, err)
+}
+
+func TestNewAdmissionPolicySelectors(t *testing.T) {
+	policy, err := NewAdmissionPolicy("", "", "", "team=data", "spark-role in (driver,executor)")
+	assert.NoError(t, err)
+	assert.Equal(t, &metav1.LabelSelector{MatchLabels: map[string]string{"team": "data"}}, policy.NamespaceSelector)
+	assert.Equal(t, &metav1.LabelSelector{
+		MatchLabels: map[string]string{common.LabelLaunchedBySparkOperator: "true"},
+		MatchExpressions: []metav1.LabelSelectorRequirement{{
+			Key:      common.LabelSparkRole,
+			Operator: metav1.LabelSelectorOpIn,
+			Values:   []string{"driver", "executor"},
+		}},
+	}, policy.ObjectSelector)
+}
+
+func TestMatchesPods(t *testing.T) {
+	podRule := admissionregistrationv1.RuleWithOperations{
+		Operations: []admissionregistrationv1.OperationType{admissionregistrationv1.Create},
+		Rule:       admissionregistrationv1.Rule{APIGroups: []string{""}, APIVersions: []string{"v1"}, Resources: []string{"pods"}},
+	}
+	appRule := admissionregistrationv1.RuleWithOperations{
+		Operations: []admissionregistrationv1.OperationType{admissionregistrationv1.Create},
+		Rule:       admissionregistrationv1.Rule{APIGroups: []string{"sparkoperator.k8s.io"}, APIVersions: []string{"v1beta2"}, Resources: []string{"sparkapplications"}},
+	}
+	assert.True(t, MatchesPods([]admissionregistrationv1.RuleWithOperations{podRule}))
+	assert.False(t, MatchesPods([]admissionregistrationv1.RuleWithOperations{appRule}))
+}
@@ -0,0 +1,207 @@
+/*
+Copyright 2024 The Kubeflow authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package archive
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"path"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"gocloud.dev/blob"
+	// Register the bucket URL schemes supported by the archive.
+	_ "gocloud.dev/blob/fileblob"
+	_ "gocloud.dev/blob/gcsblob"
+	_ "gocloud.dev/blob/s3blob"
+
+	"github.com/kubeflow/spark-operator/api/v1beta2"
+)
+
+const (
+	// objectSuffix is the suffix of every archived object.
+	objectSuffix = ".json"
+
+	// driverLogsSuffix is the suffix of the archived driver logs of a run.
+	driverLogsSuffix = ".driver.log"
+)
+
+// Record describes an archived run of a SparkApplication.
+type Record struct {
+	// Key is the key of the archived object in the bucket.
+	Key string
+	// Namespace is the namespace of the SparkApplication.
+	Namespace string
+	// Name is the name of the SparkApplication.
+	Name string
+	// RunID identifies the archived run of the SparkApplication.
+	RunID string
+	// ModTime is the time when the run was archived.
+	ModTime time.Time
+}
+
+// Archive reads and writes terminated SparkApplications in a bucket.
+type Archive struct {
+	bucket *blob.Bucket
+}
+
+// Open opens the archive located at the given bucket URL, e.g. s3://bucket?region=us-west-1&prefix=archive/
+// or gs://bucket?prefix=archive/.
+func Open(ctx context.Context, url string) (*Archive, error) {
+	bucket, err := blob.OpenBucket(ctx, url)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open bucket %s: %v", url, err)
+	}
+	return New(bucket), nil
+}
+
+// New creates an archive backed by the given bucket.
+func New(bucket *blob.Bucket) *Archive {
+	return &Archive{bucket: bucket}
+}
+
+// Close releases the resources held by the archive.
+func (a *Archive) Close() error {
+	return a.bucket.Close()
+}
+
+// Put serializes the spec and status of the SparkApplication and writes it to the archive.
+func (a *Archive) Put(ctx context.Context, app *v1beta2.SparkApplication) error {
+	archived := app.DeepCopy()
+	archived.APIVersion = v1beta2.SchemeGroupVersion.String()
+	archived.Kind = "SparkApplication"
+	archived.ManagedFields = nil
+
+	data, err := json.Marshal(archived)
+	if err != nil {
+		return fmt.Errorf("failed to marshal SparkApplication: %v", err)
+	}
+
+	key := GetKey(app.Namespace, app.Name, GetRunID(app))
+	opts := &blob.WriterOptions{ContentType: "application/json"}
+	if err := a.bucket.WriteAll(ctx, key, data, opts); err != nil {
+		return fmt.Errorf("failed to write %s: %v", key, err)
+	}
+	return nil
+}
+
+// Get reads the archived SparkApplication with the given key.
+func (a *Archive) Get(ctx context.Context, key string) (*v1beta2.SparkApplication, error) {
+	data, err := a.bucket.ReadAll(ctx, key)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s: %v", key, err)
+	}
+
+	app := &v1beta2.SparkApplication{}
+	if err := json.Unmarshal(data, app); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal %s: %v", key, err)
+	}
+	return app, nil
+}
+
+// List returns the archived runs in the given namespace, sorted by archive time with the latest run first.
+// If name is not empty, only runs of the SparkApplication with that name are returned.
+func (a *Archive) List(ctx context.Context, namespace string, name string) ([]Record, error) {
+	prefix := namespace + "/"
+	if name != "" {
+		prefix += name + "/"
+	}
+	return a.list(ctx, prefix, objectSuffix)
+}
+
+// list returns the records of the objects with the given prefix and suffix, sorted by modification time
+// with the latest object first.
+func (a *Archive) list(ctx context.Context, prefix string, suffix string) ([]Record, error) {
+	var records []Record
+	iter := a.bucket.List(&blob.ListOptions{Prefix: prefix})
+	for {
+		obj, err := iter.Next(ctx)
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to list objects with prefix %s: %v", prefix, err)
+		}
+		record, ok := parseKey(obj.Key, suffix)
+		if !ok {
+			continue
+		}
+		record.ModTime = obj.ModTime
+		records = append(records, record)
+	}
+
+	sort.SliceStable(records, func(i, j int) bool {
+		return records[i].ModTime.After(records[j].ModTime)
+	})
+	return records, nil
+}
+
+// PutDriverLogs writes the driver logs of the current run of the SparkApplication to the archive and returns the
+// key of the written object.
+func (a *Archive) PutDriverLogs(ctx context.Context, app *v1beta2.SparkApplication, logs []byte) (string, error) {
+	key := GetDriverLogsKey(app.Namespace, app.Name, GetRunID(app))
+	opts := &blob.WriterOptions{ContentType: "text/plain"}
+	if err := a.bucket.WriteAll(ctx, key, logs, opts); err != nil {
+		return "", fmt.Errorf("failed to write %s: %v", key, err)
+	}
+	return key, nil
+}
+
+// GetDriverLogsKey returns the key of the archived driver logs for a run of a SparkApplication.
+func GetDriverLogsKey(namespace string, name string, runID string) string {
+	return path.Join(namespace, name, runID+driverLogsSuffix)
+}
+
+// GetKey returns the key of the archived object for a run of a SparkApplication.
+func GetKey(namespace string, name string, runID string) string {
+	return path.Join(namespace, name, runID+objectSuffix)
+}
+
+// GetRunID returns an identifier of the current run of the SparkApplication. The submission ID is used
+// if present, otherwise the termination time.
+func GetRunID(app *v1beta2.SparkApplication) string {
+	if app.Status.SubmissionID != "" {
+		return app.Status.SubmissionID
+	}
+	if !app.Status.TerminationTime.IsZero() {
+		return strconv.FormatInt(app.Status.TerminationTime.Unix(), 10)
+	}
+	return string(app.UID)
+}
+
+// parseKey parses an object key of the form <namespace>/<name>/<run-id><suffix>. Configuration snapshots
+// are not parsed as archived SparkApplications.
+func parseKey(key string, suffix string) (Record, bool) {
+	parts := strings.Split(key, "/")
+	if len(parts) != 3 || !strings.HasSuffix(parts[2], suffix) {
+		return Record{}, false
+	}
+	if suffix != snapshotSuffix && strings.HasSuffix(parts[2], snapshotSuffix) {
+		return Record{}, false
+	}
+	return Record{
+		Key:       key,
+		Namespace: parts[0],
+		Name:      parts[1],
+		RunID:     strings.TrimSuffix(parts[2], suffix),
+	}, true
+}
@@ -0,0 +1,81 @@
+/*
+Copyright 2024 The Kubeflow authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package archive_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gocloud.dev/blob/memblob"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/kubeflow/spark-operator/api/v1beta2"
+	"github.com/kubeflow/spark-operator/pkg/archive"
+)
+
+func TestArchive(t *testing.T) {
+	ctx := context.Background()
+	a := archive.New(memblob.OpenBucket(nil))
+	defer a.Close()
+
+	app1 := &v1beta2.SparkApplication{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "spark-pi",
+			Namespace: "default",
+		},
+		Status: v1beta2.SparkApplicationStatus{
+			SubmissionID: "run-1",
+			AppState:     v1beta2.ApplicationState{State: v1beta2.ApplicationStateCompleted},
+		},
+	}
+	app2 := app1.DeepCopy()
+	app2.Name = "spark-wordcount"
+	app2.Status.SubmissionID = "run-2"
+	app2.Status.AppState.State = v1beta2.ApplicationStateFailed
+
+	require.NoError(t, a.Put(ctx, app1))
+	require.NoError(t, a.Put(ctx, app2))
+
+	records, err := a.List(ctx, "default", "")
+	require.NoError(t, err)
+	assert.Len(t, records, 2)
+
+	records, err = a.List(ctx, "default", "spark-pi")
+	require.NoError(t, err)
+	require.Len(t, records, 1)
+	assert.Equal(t, "default/spark-pi/run-1.json", records[0].Key)
+	assert.Equal(t, "run-1", records[0].RunID)
+
+	records, err = a.List(ctx, "other", "")
+	require.NoError(t, err)
+	assert.Empty(t, records)
+
+	got, err := a.Get(ctx, archive.GetKey("default", "spark-wordcount", "run-2"))
+	require.NoError(t, err)
+	assert.Equal(t, "SparkApplication", got.Kind)
+	assert.Equal(t, v1beta2.ApplicationStateFailed, got.Status.AppState.State)
+
+	key, err := a.PutDriverLogs(ctx, app1, []byte("Pi is roughly 3.14"))
+	require.NoError(t, err)
+	assert.Equal(t, "default/spark-pi/run-1.driver.log", key)
+
+	records, err = a.List(ctx, "default", "spark-pi")
+	require.NoError(t, err)
+	assert.Len(t, records, 1, "driver logs must not be listed as archived runs")
+}
@@ -0,0 +1,225 @@
+/*
+Copyright 2024 The Kubeflow authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package archive
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/json"
+	"fmt"
+	"path"
+	"sort"
+	"strings"
+
+	"gocloud.dev/blob"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/kubeflow/spark-operator/api/v1beta2"
+)
+
+const (
+	// snapshotSuffix is the suffix of every configuration snapshot object.
+	snapshotSuffix = ".snapshot.json"
+)
+
+// Snapshot is a normalized snapshot of the configuration a run of a SparkApplication was submitted with.
+type Snapshot struct {
+	// Namespace is the namespace of the SparkApplication.
+	Namespace string `json:"namespace"`
+	// Name is the name of the SparkApplication.
+	Name string `json:"name"`
+	// RunID identifies the run of the SparkApplication.
+	RunID string `json:"runID"`
+	// SubmissionTime is the time when the run was submitted.
+	SubmissionTime metav1.Time `json:"submissionTime"`
+	// Hash is the hash of the spec and the Spark configuration.
+	Hash string `json:"hash"`
+	// Spec is the spec of the SparkApplication with the Spark and Hadoop configurations removed, as these
+	// are part of the effective Spark configuration.
+	Spec v1beta2.SparkApplicationSpec `json:"spec"`
+	// SparkConf is the effective Spark configuration passed to spark-submit.
+	SparkConf map[string]string `json:"sparkConf,omitempty"`
+}
+
+// NewSnapshot creates a snapshot of the current run of the SparkApplication submitted with the given effective
+// Spark configuration. Configuration properties whose values are derived from the submission ID are dropped, so
+// that runs with identical configurations have identical hashes.
+func NewSnapshot(app *v1beta2.SparkApplication, sparkConf map[string]string) (*Snapshot, error) {
+	spec := app.Spec.DeepCopy()
+	spec.SparkConf = nil
+	spec.HadoopConf = nil
+
+	conf := make(map[string]string, len(sparkConf))
+	for key, value := range sparkConf {
+		if app.Status.SubmissionID != "" && strings.Contains(value, app.Status.SubmissionID) {
+			continue
+		}
+		conf[key] = value
+	}
+
+	// Maps are marshaled with sorted keys, so the encoding is deterministic.
+	data, err := json.Marshal(struct {
+		Spec      *v1beta2.SparkApplicationSpec `json:"spec"`
+		SparkConf map[string]string             `json:"sparkConf"`
+	}{spec, conf})
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal configuration: %v", err)
+	}
+
+	return &Snapshot{
+		Namespace:      app.Namespace,
+		Name:           app.Name,
+		RunID:          GetRunID(app),
+		SubmissionTime: app.Status.LastSubmissionAttemptTime,
+		Hash:           fmt.Sprintf("%x", sha256.Sum256(data)),
+		Spec:           *spec,
+		SparkConf:      conf,
+	}, nil
+}
+
+// PutSnapshot writes the configuration snapshot to the archive.
+func (a *Archive) PutSnapshot(ctx context.Context, snapshot *Snapshot) error {
+	data, err := json.Marshal(snapshot)
+	if err != nil {
+		return fmt.Errorf("failed to marshal snapshot: %v", err)
+	}
+
+	key := GetSnapshotKey(snapshot.Namespace, snapshot.Name, snapshot.RunID)
+	opts := &blob.WriterOptions{ContentType: "application/json"}
+	if err := a.bucket.WriteAll(ctx, key, data, opts); err != nil {
+		return fmt.Errorf("failed to write %s: %v", key, err)
+	}
+	return nil
+}
+
+// GetSnapshot reads the configuration snapshot of the given run of a SparkApplication.
+func (a *Archive) GetSnapshot(ctx context.Context, namespace string, name string, runID string) (*Snapshot, error) {
+	key := GetSnapshotKey(namespace, name, runID)
+	data, err := a.bucket.ReadAll(ctx, key)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s: %v", key, err)
+	}
+
+	snapshot := &Snapshot{}
+	if err := json.Unmarshal(data, snapshot); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal %s: %v", key, err)
+	}
+	return snapshot, nil
+}
+
+// ListSnapshots returns the runs of the SparkApplication having a configuration snapshot, sorted by archive time
+// with the latest run first.
+func (a *Archive) ListSnapshots(ctx context.Context, namespace string, name string) ([]Record, error) {
+	return a.list(ctx, path.Join(namespace, name)+"/", snapshotSuffix)
+}
+
+// GetSnapshotKey returns the key of the configuration snapshot of a run of a SparkApplication.
+func GetSnapshotKey(namespace string, name string, runID string) string {
+	return path.Join(namespace, name, runID+snapshotSuffix)
+}
+
+// ChangeType is the type of change of a configuration value between two runs.
+type ChangeType string
+
+const (
+	ChangeTypeAdded    ChangeType = "+"
+	ChangeTypeRemoved  ChangeType = "-"
+	ChangeTypeModified ChangeType = "~"
+)
+
+// Change describes a configuration value that differs between two runs.
+type Change struct {
+	// Path identifies the value, e.g. spec.executor.instances or sparkConf[spark.executor.memory].
+	Path string
+	// Type is the type of change.
+	Type ChangeType
+	// Old is the value in the first run.
+	Old string
+	// New is the value in the second run.
+	New string
+}
+
+// Diff returns the changes of the spec and the Spark configuration from one snapshot to another, sorted by path.
+func Diff(from *Snapshot, to *Snapshot) ([]Change, error) {
+	fromValues, err := flattenSnapshot(from)
+	if err != nil {
+		return nil, err
+	}
+	toValues, err := flattenSnapshot(to)
+	if err != nil {
+		return nil, err
+	}
+
+	var changes []Change
+	for path, old := range fromValues {
+		value, ok := toValues[path]
+		switch {
+		case !ok:
+			changes = append(changes, Change{Path: path, Type: ChangeTypeRemoved, Old: old})
+		case value != old:
+			changes = append(changes, Change{Path: path, Type: ChangeTypeModified, Old: old, New: value})
+		}
+	}
+	for path, value := range toValues {
+		if _, ok := fromValues[path]; !ok {
+			changes = append(changes, Change{Path: path, Type: ChangeTypeAdded, New: value})
+		}
+	}
+
+	sort.Slice(changes, func(i, j int) bool {
+		return changes[i].Path < changes[j].Path
+	})
+	return changes, nil
+}
+
+// flattenSnapshot returns the leaf values of the spec and the Spark configuration of the snapshot keyed by path.
+func flattenSnapshot(snapshot *Snapshot) (map[string]string, error) {
+	data, err := json.Marshal(snapshot.Spec)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal spec: %v", err)
+	}
+	var spec interface{}
+	if err := json.Unmarshal(data, &spec); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal spec: %v", err)
+	}
+
+	values := make(map[string]string)
+	flatten("spec", spec, values)
+	for key, value := range snapshot.SparkConf {
+		values[fmt.Sprintf("sparkConf[%s]", key)] = value
+	}
+	return values, nil
+}
+
+// flatten adds the leaf values of the decoded JSON value to values, keyed by their path under prefix.
+func flatten(prefix string, value interface{}, values map[string]string) {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		for key, child := range v {
+			flatten(prefix+"."+key, child, values)
+		}
+	case []interface{}:
+		for i, child := range v {
+			flatten(fmt.Sprintf("%s[%d]", prefix, i), child, values)
+		}
+	case string:
+		values[prefix] = v
+	default:
+		data, _ := json.Marshal(v)
+		values[prefix] = string(data)
+	}
+}
@@ -0,0 +1,115 @@
+/*
+Copyright 2024 The Kubeflow authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package archive_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gocloud.dev/blob/memblob"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/ptr"
+
+	"github.com/kubeflow/spark-operator/api/v1beta2"
+	"github.com/kubeflow/spark-operator/pkg/archive"
+)
+
+func newTestApp(submissionID string, instances int32, memory string) *v1beta2.SparkApplication {
+	app := &v1beta2.SparkApplication{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "spark-pi",
+			Namespace: "default",
+		},
+		Spec: v1beta2.SparkApplicationSpec{
+			SparkConf: map[string]string{"spark.executor.memory": memory},
+		},
+		Status: v1beta2.SparkApplicationStatus{
+			SubmissionID: submissionID,
+		},
+	}
+	app.Spec.Executor.Instances = ptr.To(instances)
+	return app
+}
+
+func newTestSnapshot(t *testing.T, app *v1beta2.SparkApplication) *archive.Snapshot {
+	sparkConf := map[string]string{
+		"spark.executor.memory": app.Spec.SparkConf["spark.executor.memory"],
+		"spark.kubernetes.driver.label.sparkoperator.k8s.io/submission-id": app.Status.SubmissionID,
+	}
+	snapshot, err := archive.NewSnapshot(app, sparkConf)
+	require.NoError(t, err)
+	return snapshot
+}
+
+func TestNewSnapshot(t *testing.T) {
+	snapshot1 := newTestSnapshot(t, newTestApp("run-1", 2, "4g"))
+	snapshot2 := newTestSnapshot(t, newTestApp("run-2", 2, "4g"))
+	snapshot3 := newTestSnapshot(t, newTestApp("run-3", 2, "8g"))
+
+	assert.Equal(t, "run-1", snapshot1.RunID)
+	assert.Nil(t, snapshot1.Spec.SparkConf)
+	assert.Equal(t, map[string]string{"spark.executor.memory": "4g"}, snapshot1.SparkConf)
+	assert.Equal(t, snapshot1.Hash, snapshot2.Hash, "runs with identical configurations must have identical hashes")
+	assert.NotEqual(t, snapshot1.Hash, snapshot3.Hash)
+}
+
+func TestSnapshotArchive(t *testing.T) {
+	ctx := context.Background()
+	a := archive.New(memblob.OpenBucket(nil))
+	defer a.Close()
+
+	app := newTestApp("run-1", 2, "4g")
+	snapshot := newTestSnapshot(t, app)
+	require.NoError(t, a.PutSnapshot(ctx, snapshot))
+	require.NoError(t, a.Put(ctx, app))
+
+	records, err := a.ListSnapshots(ctx, "default", "spark-pi")
+	require.NoError(t, err)
+	require.Len(t, records, 1)
+	assert.Equal(t, "run-1", records[0].RunID)
+
+	records, err = a.List(ctx, "default", "spark-pi")
+	require.NoError(t, err)
+	require.Len(t, records, 1, "snapshots must not be listed as archived runs")
+	assert.Equal(t, "default/spark-pi/run-1.json", records[0].Key)
+
+	got, err := a.GetSnapshot(ctx, "default", "spark-pi", "run-1")
+	require.NoError(t, err)
+	assert.Equal(t, snapshot.Hash, got.Hash)
+}
+
+func TestDiff(t *testing.T) {
+	from := newTestSnapshot(t, newTestApp("run-1", 2, "4g"))
+	to := newTestSnapshot(t, newTestApp("run-2", 4, "8g"))
+	to.SparkConf["spark.sql.shuffle.partitions"] = "400"
+	to.Spec.Image = ptr.To("spark:3.5.3")
+
+	changes, err := archive.Diff(from, to)
+	require.NoError(t, err)
+	assert.Equal(t, []archive.Change{
+		{Path: "sparkConf[spark.executor.memory]", Type: archive.ChangeTypeModified, Old: "4g", New: "8g"},
+		{Path: "sparkConf[spark.sql.shuffle.partitions]", Type: archive.ChangeTypeAdded, New: "400"},
+		{Path: "spec.executor.instances", Type: archive.ChangeTypeModified, Old: "2", New: "4"},
+		{Path: "spec.image", Type: archive.ChangeTypeAdded, New: "spark:3.5.3"},
+	}, changes)
+
+	changes, err = archive.Diff(from, from)
+	require.NoError(t, err)
+	assert.Empty(t, changes)
+}
@@ -17,6 +17,7 @@ limitations under the License.
 package certificate
 
 import (
+	"bytes"
 	"context"
 	"crypto/rsa"
 	"crypto/tls"
@@ -25,6 +26,8 @@ import (
 	"fmt"
 	"net"
 	"os"
+	"sync"
+	"time"
 
 	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/errors"
@@ -38,30 +41,57 @@ import (
 
 const (
 	Organization = "spark-operator"
+
+	// DefaultValidity is the default validity of the server certificate.
+	DefaultValidity = 365 * 24 * time.Hour
+
+	// DefaultRenewBefore is the default period before the expiry of a certificate in which it is rotated.
+	DefaultRenewBefore = 30 * 24 * time.Hour
 )
 
 // Provider is a container of a X509 certificate file and a corresponding key file for the
 // webhook server, and a CA certificate file for the API server to verify the server certificate.
 type Provider struct {
-	client     client.Client
-	commonName string
-	caKey      *rsa.PrivateKey
-	caCert     *x509.Certificate
-	serverKey  *rsa.PrivateKey
-	serverCert *x509.Certificate
+	client      client.Client
+	commonName  string
+	validity    time.Duration
+	renewBefore time.Duration
+
+	mu    sync.RWMutex
+	certs *certificates
+	// caBundleListeners are called whenever the CA bundle changes.
+	caBundleListeners []func()
 }
 
-// NewProvider creates a new Provider instance.
-func NewProvider(client client.Client, name, namespace string) *Provider {
+// certificates are the certificates and keys stored in the webhook secret.
+type certificates struct {
+	caKey  *rsa.PrivateKey
+	caCert *x509.Certificate
+	// previousCACert is the CA certificate replaced by the last CA rotation, which is kept in the CA bundle until it
+	// expires so that server certificates it signed are still trusted.
+	previousCACert *x509.Certificate
+	serverKey      *rsa.PrivateKey
+	serverCert     *x509.Certificate
+}
+
+// NewProvider creates a new Provider instance. The server certificate is valid for the given validity and is
+// rotated by SyncSecret once it is within renewBefore of its expiry.
+func NewProvider(client client.Client, name, namespace string, validity, renewBefore time.Duration) *Provider {
 	commonName := fmt.Sprintf("%s.%s.svc", name, namespace)
 	certProvider := Provider{
-		client:     client,
-		commonName: commonName,
+		client:      client,
+		commonName:  commonName,
+		validity:    validity,
+		renewBefore: renewBefore,
 	}
 	return &certProvider
 }
 
-// SyncSecret syncs the secret containing the certificates to the given name and namespace.
+// SyncSecret syncs the secret containing the certificates to the given name and namespace. Certificates found in
+// the secret are loaded, and rotated if they are within the renewal period of their expiry. The server certificate
+// is re-signed by the existing CA as long as the CA outlives it, so that the CA bundle of the webhook
+// configurations does not change. Otherwise a new CA is generated and published along with the previous one, and
+// the server certificate is only switched to one signed by the new CA by SwitchServerCert.
 func (cp *Provider) SyncSecret(ctx context.Context, name, namespace string) error {
 	secret := &corev1.Secret{
 		ObjectMeta: metav1.ObjectMeta{
@@ -89,63 +119,241 @@ func (cp *Provider) SyncSecret(ctx context.Context, name, namespace string) erro
 		len(secret.Data[common.CACertPem]) == 0 ||
 		len(secret.Data[common.ServerCertPem]) == 0 ||
 		len(secret.Data[common.ServerKeyPem]) == 0 {
-		if err := cp.Generate(); err != nil {
+		certs, err := cp.generateCertificates()
+		if err != nil {
 			return fmt.Errorf("failed to generate certificate: %v", err)
 		}
-		if err := cp.updateSecret(ctx, secret); err != nil {
-			return err
-		}
+		return cp.updateOrLoadSecret(ctx, secret, certs)
+	}
+	certs, err := parseSecret(secret)
+	if err != nil {
+		return err
+	}
+	cp.setCertificates(certs)
+
+	rotated, err := cp.rotate(certs, time.Now())
+	if err != nil {
+		return fmt.Errorf("failed to rotate certificate: %v", err)
+	}
+	if rotated == nil {
+		return nil
+	}
+	return cp.updateOrLoadSecret(ctx, secret, rotated)
+}
+
+// SwitchServerCert replaces the server certificate signed by the previous CA with one signed by the current CA after
+// a CA rotation. It must only be called once the CA bundle of the webhook configurations includes the given CA
+// certificate, and does nothing if the CA of the secret is not the given one anymore.
+func (cp *Provider) SwitchServerCert(ctx context.Context, name, namespace string, trustedCACert []byte) error {
+	secret := &corev1.Secret{}
+	if err := cp.client.Get(ctx, types.NamespacedName{Name: name, Namespace: namespace}, secret); err != nil {
+		return fmt.Errorf("failed to get secret: %v", err)
+	}
+	certs, err := parseSecret(secret)
+	if err != nil {
+		return err
+	}
+	cp.setCertificates(certs)
+	if !bytes.Equal(encodeCertificate(certs.caCert), trustedCACert) || !certs.needsServerCertSwitch() {
+		return nil
+	}
+
+	serverKey, serverCert, err := cp.generateServerCert(certs.caKey, certs.caCert)
+	if err != nil {
+		return err
+	}
+	switched := *certs
+	switched.serverKey = serverKey
+	switched.serverCert = serverCert
+	return cp.updateOrLoadSecret(ctx, secret, &switched)
+}
+
+// updateOrLoadSecret writes the certificates to the secret, and only then makes them the certificates of the
+// provider. If another webhook replica has updated the secret in the meantime, the certificates it wrote are loaded
+// instead, so that all replicas serve the same certificate.
+func (cp *Provider) updateOrLoadSecret(ctx context.Context, secret *corev1.Secret, certs *certificates) error {
+	err := cp.updateSecret(ctx, secret, certs)
+	if err == nil {
+		cp.setCertificates(certs)
 		return nil
 	}
-	return cp.parseSecret(secret)
+	if !errors.IsConflict(err) {
+		return err
+	}
+
+	latest := &corev1.Secret{}
+	if err := cp.client.Get(ctx, types.NamespacedName{Name: secret.Name, Namespace: secret.Namespace}, latest); err != nil {
+		return fmt.Errorf("failed to get secret: %v", err)
+	}
+	latestCerts, parseErr := parseSecret(latest)
+	if parseErr != nil {
+		// The other replica has not populated the secret with certificates yet, the conflict is returned for the
+		// caller to retry.
+		return err
+	}
+	cp.setCertificates(latestCerts)
+	return nil
+}
+
+// NeedsRotation returns whether the CA or server certificate is missing or within the renewal period of its expiry
+// at the given time.
+func (cp *Provider) NeedsRotation(now time.Time) bool {
+	cp.mu.RLock()
+	defer cp.mu.RUnlock()
+	if cp.certs == nil {
+		return true
+	}
+	return !now.Before(cp.certs.caCert.NotAfter.Add(-cp.renewBefore)) ||
+		!now.Before(cp.certs.serverCert.NotAfter.Add(-cp.renewBefore))
+}
+
+// NeedsServerCertSwitch returns whether the CA has been rotated while the server certificate is still signed by the
+// previous CA, i.e. whether SwitchServerCert is to be called once the webhook configurations trust the new CA.
+func (cp *Provider) NeedsServerCertSwitch() bool {
+	cp.mu.RLock()
+	defer cp.mu.RUnlock()
+	return cp.certs != nil && cp.certs.needsServerCertSwitch()
+}
+
+// OnCABundleChange registers a function called whenever the CA bundle changes, which must not block.
+func (cp *Provider) OnCABundleChange(listener func()) {
+	cp.mu.Lock()
+	defer cp.mu.Unlock()
+	cp.caBundleListeners = append(cp.caBundleListeners, listener)
+}
+
+// rotate returns the certificates rotated at the given time, or nil if none needs to be rotated. A server
+// certificate in its renewal period is re-signed by the current CA if it fits in the validity of the CA. Otherwise,
+// or if the CA is in its renewal period itself, a new CA is generated and the previous one is kept in the CA bundle,
+// while the server certificate is kept until SwitchServerCert switches it.
+func (cp *Provider) rotate(certs *certificates, now time.Time) (*certificates, error) {
+	rotated := *certs
+	changed := false
+	if certs.previousCACert != nil && !now.Before(certs.previousCACert.NotAfter) {
+		rotated.previousCACert = nil
+		changed = true
+	}
+
+	// The CA is not rotated again before the server certificate has been switched to the current one, which would
+	// remove the CA of the served certificate from the CA bundle.
+	pendingSwitch := certs.needsServerCertSwitch()
+	serverCertDue := !now.Before(certs.serverCert.NotAfter.Add(-cp.renewBefore))
+	caCertDue := !now.Before(certs.caCert.NotAfter.Add(-cp.renewBefore)) ||
+		(serverCertDue && now.Add(cp.validity).After(certs.caCert.NotAfter.Add(-cp.renewBefore)))
+	switch {
+	case caCertDue && !pendingSwitch:
+		caKey, caCert, err := cp.generateCA()
+		if err != nil {
+			return nil, err
+		}
+		rotated.caKey = caKey
+		rotated.caCert = caCert
+		rotated.previousCACert = certs.caCert
+		changed = true
+	case serverCertDue && !pendingSwitch:
+		serverKey, serverCert, err := cp.generateServerCert(certs.caKey, certs.caCert)
+		if err != nil {
+			return nil, err
+		}
+		rotated.serverKey = serverKey
+		rotated.serverCert = serverCert
+		changed = true
+	}
+	if !changed {
+		return nil, nil
+	}
+	return &rotated, nil
+}
+
+// needsServerCertSwitch returns whether the server certificate is not signed by the current CA.
+func (c *certificates) needsServerCertSwitch() bool {
+	return c.serverCert.CheckSignatureFrom(c.caCert) != nil
+}
+
+// caBundle returns the PEM-encoded CA certificates trusted by the API server, i.e. the current and previous CA.
+func (c *certificates) caBundle() []byte {
+	caBundle := encodeCertificate(c.caCert)
+	if c.previousCACert != nil {
+		caBundle = append(caBundle, encodeCertificate(c.previousCACert)...)
+	}
+	return caBundle
+}
+
+// setCertificates makes the given certificates the ones of the provider, and notifies the listeners of the CA bundle
+// if it changes.
+func (cp *Provider) setCertificates(certs *certificates) {
+	cp.mu.Lock()
+	changed := cp.certs == nil || !bytes.Equal(cp.certs.caBundle(), certs.caBundle())
+	cp.certs = certs
+	listeners := cp.caBundleListeners
+	cp.mu.Unlock()
+
+	if changed {
+		for _, listener := range listeners {
+			listener()
+		}
+	}
 }
 
 // CAKey returns the PEM-encoded CA private key.
 func (cp *Provider) CAKey() ([]byte, error) {
-	if cp.caKey == nil {
+	cp.mu.RLock()
+	defer cp.mu.RUnlock()
+	if cp.certs == nil {
 		return nil, fmt.Errorf("CA key is not set")
 	}
-	data := pem.EncodeToMemory(&pem.Block{
-		Type:  "RSA PRIVATE KEY",
-		Bytes: x509.MarshalPKCS1PrivateKey(cp.caKey),
-	})
-	return data, nil
+	return encodePrivateKey(cp.certs.caKey), nil
 }
 
 // CACert returns the PEM-encoded CA certificate.
 func (cp *Provider) CACert() ([]byte, error) {
-	if cp.caCert == nil {
+	cp.mu.RLock()
+	defer cp.mu.RUnlock()
+	if cp.certs == nil {
 		return nil, fmt.Errorf("CA certificate is not set")
 	}
-	data := pem.EncodeToMemory(&pem.Block{
-		Type:  "CERTIFICATE",
-		Bytes: cp.caCert.Raw,
-	})
-	return data, nil
+	return encodeCertificate(cp.certs.caCert), nil
+}
+
+// CABundle returns the PEM-encoded CA certificates the API server is to trust, which include the previous CA
+// certificate after a CA rotation until it expires.
+func (cp *Provider) CABundle() ([]byte, error) {
+	cp.mu.RLock()
+	defer cp.mu.RUnlock()
+	if cp.certs == nil {
+		return nil, fmt.Errorf("CA certificate is not set")
+	}
+	return cp.certs.caBundle(), nil
 }
 
 // ServerKey returns the PEM-encoded server private key.
 func (cp *Provider) ServerKey() ([]byte, error) {
-	if cp.serverKey == nil {
+	cp.mu.RLock()
+	defer cp.mu.RUnlock()
+	if cp.certs == nil {
 		return nil, fmt.Errorf("server key is not set")
 	}
-	data := pem.EncodeToMemory(&pem.Block{
-		Type:  "RSA PRIVATE KEY",
-		Bytes: x509.MarshalPKCS1PrivateKey(cp.serverKey),
-	})
-	return data, nil
+	return encodePrivateKey(cp.certs.serverKey), nil
 }
 
 // ServerCert returns the PEM-encoded server cert.
 func (cp *Provider) ServerCert() ([]byte, error) {
-	if cp.serverCert == nil {
+	cp.mu.RLock()
+	defer cp.mu.RUnlock()
+	if cp.certs == nil {
 		return nil, fmt.Errorf("server cert is not set")
 	}
-	data := pem.EncodeToMemory(&pem.Block{
-		Type:  "CERTIFICATE",
-		Bytes: cp.serverCert.Raw,
-	})
-	return data, nil
+	return encodeCertificate(cp.certs.serverCert), nil
+}
+
+// CACertNotAfter returns the expiry time of the CA certificate.
+func (cp *Provider) CACertNotAfter() (time.Time, error) {
+	cp.mu.RLock()
+	defer cp.mu.RUnlock()
+	if cp.certs == nil {
+		return time.Time{}, fmt.Errorf("CA cert is not set")
+	}
+	return cp.certs.caCert.NotAfter, nil
 }
 
 // TLSConfig returns the TLS configuration.
@@ -193,11 +401,35 @@ func (cp *Provider) WriteFile(path, certName, keyName string) error {
 	return nil
 }
 
+// Generate generates new CA and server certificates.
 func (cp *Provider) Generate() error {
+	certs, err := cp.generateCertificates()
+	if err != nil {
+		return err
+	}
+	cp.setCertificates(certs)
+	return nil
+}
+
+// generateCertificates generates new CA and server certificates.
+func (cp *Provider) generateCertificates() (*certificates, error) {
+	caKey, caCert, err := cp.generateCA()
+	if err != nil {
+		return nil, err
+	}
+	serverKey, serverCert, err := cp.generateServerCert(caKey, caCert)
+	if err != nil {
+		return nil, err
+	}
+	return &certificates{caKey: caKey, caCert: caCert, serverKey: serverKey, serverCert: serverCert}, nil
+}
+
+// generateCA generates a CA private key and a self-signed CA certificate.
+func (cp *Provider) generateCA() (*rsa.PrivateKey, *x509.Certificate, error) {
 	// Generate CA private caKey
 	caKey, err := NewPrivateKey()
 	if err != nil {
-		return fmt.Errorf("failed to generate CA private key: %v", err)
+		return nil, nil, fmt.Errorf("failed to generate CA private key: %v", err)
 	}
 
 	// Generate self-signed CA certificate
@@ -207,13 +439,17 @@ func (cp *Provider) Generate() error {
 	}
 	caCert, err := cert.NewSelfSignedCACert(caCfg, caKey)
 	if err != nil {
-		return fmt.Errorf("failed to generate self-signed CA certificate: %v", err)
+		return nil, nil, fmt.Errorf("failed to generate self-signed CA certificate: %v", err)
 	}
+	return caKey, caCert, nil
+}
 
+// generateServerCert generates a server private key and a server certificate signed by the given CA.
+func (cp *Provider) generateServerCert(caKey *rsa.PrivateKey, caCert *x509.Certificate) (*rsa.PrivateKey, *x509.Certificate, error) {
 	// Generate server private key
 	serverKey, err := NewPrivateKey()
 	if err != nil {
-		return fmt.Errorf("failed to generate server private key: %v", err)
+		return nil, nil, fmt.Errorf("failed to generate server private key: %v", err)
 	}
 
 	// Generate signed server certificate
@@ -230,78 +466,85 @@ func (cp *Provider) Generate() error {
 		Organization: []string{Organization},
 		AltNames:     cert.AltNames{IPs: ips, DNSNames: dnsNames},
 	}
-	serverCert, err := NewSignedServerCert(serverCfg, caKey, caCert, serverKey)
+	serverCert, err := NewSignedServerCert(serverCfg, caKey, caCert, serverKey, cp.validity)
 	if err != nil {
-		return fmt.Errorf("failed to generate signed server certificate: %v", err)
+		return nil, nil, fmt.Errorf("failed to generate signed server certificate: %v", err)
 	}
-
-	cp.caKey = caKey
-	cp.caCert = caCert
-	cp.serverKey = serverKey
-	cp.serverCert = serverCert
-	return nil
+	return serverKey, serverCert, nil
 }
 
-func (cp *Provider) parseSecret(secret *corev1.Secret) error {
+// parseSecret parses the certificates of the secret. The previous CA certificate is optional.
+func parseSecret(secret *corev1.Secret) (*certificates, error) {
 	if secret == nil {
-		return fmt.Errorf("secret is nil")
+		return nil, fmt.Errorf("secret is nil")
 	}
 	caKeyPem, _ := pem.Decode(secret.Data[common.CAKeyPem])
 	caCertPem, _ := pem.Decode(secret.Data[common.CACertPem])
 	serverKeyPem, _ := pem.Decode(secret.Data[common.ServerKeyPem])
 	serverCertPem, _ := pem.Decode(secret.Data[common.ServerCertPem])
 	if caKeyPem == nil || caCertPem == nil || serverKeyPem == nil || serverCertPem == nil {
-		return fmt.Errorf("failed to decode secret data to pem block")
+		return nil, fmt.Errorf("failed to decode secret data to pem block")
 	}
 	caKey, err := x509.ParsePKCS1PrivateKey(caKeyPem.Bytes)
 	if err != nil {
-		return fmt.Errorf("failed to parse CA private key: %v", err)
+		return nil, fmt.Errorf("failed to parse CA private key: %v", err)
 	}
 	caCert, err := x509.ParseCertificate(caCertPem.Bytes)
 	if err != nil {
-		return fmt.Errorf("failed to prase CA certificate: %v", err)
+		return nil, fmt.Errorf("failed to prase CA certificate: %v", err)
 	}
 	serverKey, err := x509.ParsePKCS1PrivateKey(serverKeyPem.Bytes)
 	if err != nil {
-		return fmt.Errorf("failed to parse server private key: %v", err)
+		return nil, fmt.Errorf("failed to parse server private key: %v", err)
 	}
 	serverCert, err := x509.ParseCertificate(serverCertPem.Bytes)
 	if err != nil {
-		return fmt.Errorf("failed to parse server certificate: %v", err)
+		return nil, fmt.Errorf("failed to parse server certificate: %v", err)
 	}
-	cp.caKey = caKey
-	cp.caCert = caCert
-	cp.serverKey = serverKey
-	cp.serverCert = serverCert
-	return nil
+	certs := &certificates{caKey: caKey, caCert: caCert, serverKey: serverKey, serverCert: serverCert}
+	if data := secret.Data[common.PreviousCACertPem]; len(data) > 0 {
+		previousCACertPem, _ := pem.Decode(data)
+		if previousCACertPem == nil {
+			return nil, fmt.Errorf("failed to decode previous CA certificate to pem block")
+		}
+		if certs.previousCACert, err = x509.ParseCertificate(previousCACertPem.Bytes); err != nil {
+			return nil, fmt.Errorf("failed to parse previous CA certificate: %v", err)
+		}
+	}
+	return certs, nil
 }
 
-func (cp *Provider) updateSecret(ctx context.Context, secret *corev1.Secret) error {
-	caKey, err := cp.CAKey()
-	if err != nil {
-		return fmt.Errorf("failed to get CA key: %v", err)
-	}
-	caCert, err := cp.CACert()
-	if err != nil {
-		return fmt.Errorf("failed to get CA certificate: %v", err)
-	}
-	serverKey, err := cp.ServerKey()
-	if err != nil {
-		return fmt.Errorf("failed to get server key: %v", err)
-	}
-	serverCert, err := cp.ServerCert()
-	if err != nil {
-		return fmt.Errorf("failed to get server certificate: %v", err)
-	}
+func (cp *Provider) updateSecret(ctx context.Context, secret *corev1.Secret, certs *certificates) error {
 	if secret.Data == nil {
 		secret.Data = make(map[string][]byte)
 	}
-	secret.Data[common.CAKeyPem] = caKey
-	secret.Data[common.CACertPem] = caCert
-	secret.Data[common.ServerKeyPem] = serverKey
-	secret.Data[common.ServerCertPem] = serverCert
+	secret.Data[common.CAKeyPem] = encodePrivateKey(certs.caKey)
+	secret.Data[common.CACertPem] = encodeCertificate(certs.caCert)
+	secret.Data[common.ServerKeyPem] = encodePrivateKey(certs.serverKey)
+	secret.Data[common.ServerCertPem] = encodeCertificate(certs.serverCert)
+	if certs.previousCACert != nil {
+		secret.Data[common.PreviousCACertPem] = encodeCertificate(certs.previousCACert)
+	} else {
+		delete(secret.Data, common.PreviousCACertPem)
+	}
 	if err := cp.client.Update(ctx, secret); err != nil {
 		return err
 	}
 	return nil
 }
+
+// encodeCertificate returns the PEM-encoded certificate.
+func encodeCertificate(cert *x509.Certificate) []byte {
+	return pem.EncodeToMemory(&pem.Block{
+		Type:  "CERTIFICATE",
+		Bytes: cert.Raw,
+	})
+}
+
+// encodePrivateKey returns the PEM-encoded private key.
+func encodePrivateKey(key *rsa.PrivateKey) []byte {
+	return pem.EncodeToMemory(&pem.Block{
+		Type:  "RSA PRIVATE KEY",
+		Bytes: x509.MarshalPKCS1PrivateKey(key),
+	})
+}
@@ -18,6 +18,7 @@ package certificate_test
 
 import (
 	"context"
+	"time"
 
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
@@ -39,7 +40,7 @@ var _ = Describe("Certificate Provider", func() {
 
 		BeforeEach(func() {
 			By("Creating a new cert provider")
-			cp = certificate.NewProvider(k8sClient, secretName, secretNamespace)
+			cp = certificate.NewProvider(k8sClient, secretName, secretNamespace, certificate.DefaultValidity, certificate.DefaultRenewBefore)
 			Expect(cp).NotTo(BeNil())
 
 			By("Generating new certificates")
@@ -104,7 +105,7 @@ var _ = Describe("Certificate Provider", func() {
 
 		It("Should generate new certificates and update webhook secret", func() {
 			By("Creating a new CertProvider")
-			cp := certificate.NewProvider(k8sClient, secretName, secretNamespace)
+			cp := certificate.NewProvider(k8sClient, secretName, secretNamespace, certificate.DefaultValidity, certificate.DefaultRenewBefore)
 			Expect(cp.SyncSecret(context.TODO(), secretName, secretNamespace)).To(Succeed())
 
 			By("Checking out whether the data of webhook secret is populated")
@@ -136,7 +137,7 @@ var _ = Describe("Certificate Provider", func() {
 			Expect(k8sClient.Create(ctx, secret)).To(Succeed())
 
 			By("Creating a new CertProvider and synchronize generated certificates to webhook secret")
-			cp := certificate.NewProvider(k8sClient, secretName, secretNamespace)
+			cp := certificate.NewProvider(k8sClient, secretName, secretNamespace, certificate.DefaultValidity, certificate.DefaultRenewBefore)
 			Expect(cp.SyncSecret(context.TODO(), secretName, secretNamespace)).To(Succeed())
 
 			By("Creating a new webhook secret with data populated")
@@ -154,7 +155,7 @@ var _ = Describe("Certificate Provider", func() {
 
 		It("Should synchronize webhook certificates data", func() {
 			By("Creating a new cert provider and synchronize generated certificates to webhook secret")
-			cp := certificate.NewProvider(k8sClient, secretName, secretNamespace)
+			cp := certificate.NewProvider(k8sClient, secretName, secretNamespace, certificate.DefaultValidity, certificate.DefaultRenewBefore)
 			Expect(cp.SyncSecret(context.TODO(), secretName, secretNamespace)).To(Succeed())
 
 			By("Checking out whether the webhook certificates is synchronized into the cert provider")
@@ -172,4 +173,49 @@ var _ = Describe("Certificate Provider", func() {
 			Expect(serverCert).To(Equal(secret.Data[common.ServerCertPem]))
 		})
 	})
+
+	Context("The server certificate of webhook secret is about to expire", func() {
+		ctx := context.Background()
+		secretName := "spark-operator-webhook-secret"
+		secretNamespace := "default"
+		key := types.NamespacedName{
+			Name:      secretName,
+			Namespace: secretNamespace,
+		}
+		secret := &corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      secretName,
+				Namespace: secretNamespace,
+			},
+		}
+
+		BeforeEach(func() {
+			By("Creating a new webhook secret with a server certificate valid for an hour")
+			Expect(k8sClient.Create(ctx, secret)).To(Succeed())
+			cp := certificate.NewProvider(k8sClient, secretName, secretNamespace, time.Hour, 30*time.Minute)
+			Expect(cp.SyncSecret(context.TODO(), secretName, secretNamespace)).To(Succeed())
+			Expect(cp.NeedsRotation(time.Now())).To(BeFalse())
+			Expect(k8sClient.Get(ctx, key, secret)).To(Succeed())
+		})
+
+		AfterEach(func() {
+			By("Deleting the webhook secret")
+			Expect(k8sClient.Delete(ctx, secret)).To(Succeed())
+		})
+
+		It("Should rotate the server certificate and keep the CA certificate", func() {
+			By("Synchronizing the webhook secret with a renewal period longer than the remaining validity")
+			cp := certificate.NewProvider(k8sClient, secretName, secretNamespace, time.Hour, 2*time.Hour)
+			Expect(cp.SyncSecret(context.TODO(), secretName, secretNamespace)).To(Succeed())
+
+			By("Checking out whether the server certificate is rotated in the webhook secret")
+			rotated := &corev1.Secret{}
+			Expect(k8sClient.Get(ctx, key, rotated)).To(Succeed())
+			Expect(rotated.Data[common.CACertPem]).To(Equal(secret.Data[common.CACertPem]))
+			Expect(rotated.Data[common.ServerCertPem]).NotTo(Equal(secret.Data[common.ServerCertPem]))
+			serverCert, err := cp.ServerCert()
+			Expect(err).To(BeNil())
+			Expect(serverCert).To(Equal(rotated.Data[common.ServerCertPem]))
+		})
+	})
 })
@@ -0,0 +1,218 @@
+/*
+Copyright 2024 The Kubeflow authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package certificate
+
+import (
+	"bytes"
+	"context"
+	"crypto/x509"
+	"encoding/pem"
+	"fmt"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sync/atomic"
+	"time"
+
+	admissionregistrationv1 "k8s.io/api/admissionregistration/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/wait"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/manager"
+)
+
+var (
+	logger = ctrl.Log.WithName("")
+)
+
+// Rotator periodically syncs the secret containing the certificates, which rotates them before they expire, and
+// writes the server certificate and key to the directory served by the webhook server whenever they change. The
+// webhook server watches the directory and reloads the certificate without a restart. After a CA rotation, the
+// server certificate is only switched to one signed by the new CA once the webhook configurations trust it.
+type Rotator struct {
+	provider              *Provider
+	secretName            string
+	secretNamespace       string
+	mutatingWebhookName   string
+	validatingWebhookName string
+	certDir               string
+	certName              string
+	keyName               string
+	interval              time.Duration
+
+	// lastSuccessfulSync is the Unix time in nanoseconds of the last sync which succeeded to sync the secret and
+	// write rotated certificates, zero if none did yet.
+	lastSuccessfulSync atomic.Int64
+	// syncFailures is the number of syncs which failed.
+	syncFailures atomic.Int64
+}
+
+// Rotator implements manager.Runnable and manager.LeaderElectionRunnable.
+var _ manager.Runnable = &Rotator{}
+var _ manager.LeaderElectionRunnable = &Rotator{}
+
+// NewRotator creates a new Rotator instance.
+func NewRotator(provider *Provider, secretName, secretNamespace, mutatingWebhookName, validatingWebhookName, certDir, certName, keyName string, interval time.Duration) *Rotator {
+	return &Rotator{
+		provider:              provider,
+		secretName:            secretName,
+		secretNamespace:       secretNamespace,
+		mutatingWebhookName:   mutatingWebhookName,
+		validatingWebhookName: validatingWebhookName,
+		certDir:               certDir,
+		certName:              certName,
+		keyName:               keyName,
+		interval:              interval,
+	}
+}
+
+// Start implements manager.Runnable.
+func (r *Rotator) Start(ctx context.Context) error {
+	wait.UntilWithContext(ctx, r.sync, r.interval)
+	return nil
+}
+
+// NeedLeaderElection implements manager.LeaderElectionRunnable. Every replica serves the webhook and must keep its
+// own copy of the server certificate up to date.
+func (r *Rotator) NeedLeaderElection() bool {
+	return false
+}
+
+// LastSuccessfulSync returns the time of the last successful sync, or the zero time if no sync succeeded yet.
+func (r *Rotator) LastSuccessfulSync() time.Time {
+	if nanos := r.lastSuccessfulSync.Load(); nanos != 0 {
+		return time.Unix(0, nanos)
+	}
+	return time.Time{}
+}
+
+// SyncFailures returns the number of syncs which failed.
+func (r *Rotator) SyncFailures() int64 {
+	return r.syncFailures.Load()
+}
+
+// ServingCertNotAfter returns the expiry time of the server certificate in the directory served by the webhook server.
+func (r *Rotator) ServingCertNotAfter() (time.Time, error) {
+	data, err := os.ReadFile(filepath.Join(r.certDir, r.certName))
+	if err != nil {
+		return time.Time{}, fmt.Errorf("failed to read serving certificate: %v", err)
+	}
+	block, _ := pem.Decode(data)
+	if block == nil {
+		return time.Time{}, fmt.Errorf("failed to decode serving certificate")
+	}
+	cert, err := x509.ParseCertificate(block.Bytes)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("failed to parse serving certificate: %v", err)
+	}
+	return cert.NotAfter, nil
+}
+
+// CheckServingCert is a readiness check failing if the server certificate served by the webhook server has expired,
+// so that silently failing rotations are noticed before the API server rejects the webhook.
+func (r *Rotator) CheckServingCert(_ *http.Request) error {
+	notAfter, err := r.ServingCertNotAfter()
+	if err != nil {
+		return err
+	}
+	if !time.Now().Before(notAfter) {
+		return fmt.Errorf("serving certificate expired at %s", notAfter.Format(time.RFC3339))
+	}
+	return nil
+}
+
+func (r *Rotator) sync(ctx context.Context) {
+	if err := r.rotate(ctx); err != nil {
+		r.syncFailures.Add(1)
+		logger.Error(err, "Failed to rotate webhook certificates", "name", r.secretName, "namespace", r.secretNamespace)
+		return
+	}
+	r.lastSuccessfulSync.Store(time.Now().UnixNano())
+}
+
+func (r *Rotator) rotate(ctx context.Context) error {
+	oldCert, _ := r.provider.ServerCert()
+	if err := r.provider.SyncSecret(ctx, r.secretName, r.secretNamespace); err != nil {
+		return fmt.Errorf("failed to sync webhook secret: %v", err)
+	}
+	if r.provider.NeedsServerCertSwitch() {
+		caCert, err := r.provider.CACert()
+		if err != nil {
+			return err
+		}
+		trusted, err := r.isCATrusted(ctx, caCert)
+		if err != nil {
+			return err
+		}
+		if trusted {
+			if err := r.provider.SwitchServerCert(ctx, r.secretName, r.secretNamespace, caCert); err != nil {
+				return fmt.Errorf("failed to switch server certificate: %v", err)
+			}
+		} else {
+			logger.Info("Waiting for the webhook configurations to trust the rotated CA certificate before switching the server certificate")
+		}
+	}
+
+	newCert, err := r.provider.ServerCert()
+	if err != nil {
+		return err
+	}
+	if bytes.Equal(oldCert, newCert) {
+		return nil
+	}
+
+	logger.Info("Writing rotated certificates", "path", r.certDir, "certificate name", r.certName, "key name", r.keyName)
+	if err := r.provider.WriteFile(r.certDir, r.certName, r.keyName); err != nil {
+		return fmt.Errorf("failed to save rotated certificate: %v", err)
+	}
+	return nil
+}
+
+// isCATrusted returns whether the CA bundles of all webhooks of the mutating and validating webhook configurations
+// include the given CA certificate. The webhook configurations are read from the API server, as they are updated by
+// the leader replica.
+func (r *Rotator) isCATrusted(ctx context.Context, caCert []byte) (bool, error) {
+	var caBundles [][]byte
+	mutatingWebhookConfig := &admissionregistrationv1.MutatingWebhookConfiguration{}
+	if err := r.provider.client.Get(ctx, types.NamespacedName{Name: r.mutatingWebhookName}, mutatingWebhookConfig); err != nil {
+		if errors.IsNotFound(err) {
+			return false, nil
+		}
+		return false, fmt.Errorf("failed to get mutating webhook configuration %s: %v", r.mutatingWebhookName, err)
+	}
+	for _, webhook := range mutatingWebhookConfig.Webhooks {
+		caBundles = append(caBundles, webhook.ClientConfig.CABundle)
+	}
+	validatingWebhookConfig := &admissionregistrationv1.ValidatingWebhookConfiguration{}
+	if err := r.provider.client.Get(ctx, types.NamespacedName{Name: r.validatingWebhookName}, validatingWebhookConfig); err != nil {
+		if errors.IsNotFound(err) {
+			return false, nil
+		}
+		return false, fmt.Errorf("failed to get validating webhook configuration %s: %v", r.validatingWebhookName, err)
+	}
+	for _, webhook := range validatingWebhookConfig.Webhooks {
+		caBundles = append(caBundles, webhook.ClientConfig.CABundle)
+	}
+
+	for _, caBundle := range caBundles {
+		if !bytes.Contains(caBundle, caCert) {
+			return false, nil
+		}
+	}
+	return true, nil
+}
@@ -0,0 +1,342 @@
+/*
+Copyright 2024 The Kubeflow authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package certificate_test
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"fmt"
+	"math/big"
+	"os"
+	"path/filepath"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	admissionregistrationv1 "k8s.io/api/admissionregistration/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/wait"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/util/cert"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/client/interceptor"
+
+	"github.com/kubeflow/spark-operator/pkg/certificate"
+	"github.com/kubeflow/spark-operator/pkg/common"
+)
+
+const (
+	testSecretName      = "spark-operator-webhook-secret"
+	testSecretNamespace = "default"
+	testWebhookName     = "spark-operator-webhook"
+)
+
+// newTestWebhookSecret returns a webhook secret with a CA certificate expiring at caNotAfter and a server certificate
+// signed by it which is valid for serverValidity, along with the PEM-encoded CA and server certificates.
+func newTestWebhookSecret(t *testing.T, caNotAfter time.Time, serverValidity time.Duration) (*corev1.Secret, []byte, []byte) {
+	caKey, err := certificate.NewPrivateKey()
+	if err != nil {
+		t.Fatalf("failed to generate CA key: %v", err)
+	}
+	template := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "spark-operator-webhook-svc.default.svc"},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              caNotAfter,
+		KeyUsage:              x509.KeyUsageKeyEncipherment | x509.KeyUsageDigitalSignature | x509.KeyUsageCertSign,
+		BasicConstraintsValid: true,
+		IsCA:                  true,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, caKey.Public(), caKey)
+	if err != nil {
+		t.Fatalf("failed to create CA certificate: %v", err)
+	}
+	caCert, err := x509.ParseCertificate(der)
+	if err != nil {
+		t.Fatalf("failed to parse CA certificate: %v", err)
+	}
+	serverKey, err := certificate.NewPrivateKey()
+	if err != nil {
+		t.Fatalf("failed to generate server key: %v", err)
+	}
+	serverCert, err := certificate.NewSignedServerCert(cert.Config{
+		CommonName: "spark-operator-webhook-svc.default.svc",
+		AltNames:   cert.AltNames{DNSNames: []string{"spark-operator-webhook-svc.default.svc"}},
+	}, caKey, caCert, serverKey, serverValidity)
+	if err != nil {
+		t.Fatalf("failed to create server certificate: %v", err)
+	}
+
+	encodeKey := func(der []byte) []byte { return pem.EncodeToMemory(&pem.Block{Type: "RSA PRIVATE KEY", Bytes: der}) }
+	encodeCert := func(der []byte) []byte { return pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}) }
+	secret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: testSecretName, Namespace: testSecretNamespace},
+		Data: map[string][]byte{
+			common.CAKeyPem:      encodeKey(x509.MarshalPKCS1PrivateKey(caKey)),
+			common.CACertPem:     encodeCert(caCert.Raw),
+			common.ServerKeyPem:  encodeKey(x509.MarshalPKCS1PrivateKey(serverKey)),
+			common.ServerCertPem: encodeCert(serverCert.Raw),
+		},
+	}
+	return secret, secret.Data[common.CACertPem], secret.Data[common.ServerCertPem]
+}
+
+func newTestWebhookConfigurations(caBundle []byte) []client.Object {
+	return []client.Object{
+		&admissionregistrationv1.MutatingWebhookConfiguration{
+			ObjectMeta: metav1.ObjectMeta{Name: testWebhookName},
+			Webhooks: []admissionregistrationv1.MutatingWebhook{
+				{Name: "mutate--v1-pod.sparkoperator.k8s.io", ClientConfig: admissionregistrationv1.WebhookClientConfig{CABundle: caBundle}},
+			},
+		},
+		&admissionregistrationv1.ValidatingWebhookConfiguration{
+			ObjectMeta: metav1.ObjectMeta{Name: testWebhookName},
+			Webhooks: []admissionregistrationv1.ValidatingWebhook{
+				{Name: "validate-sparkoperator-k8s-io-v1beta2-sparkapplication.sparkoperator.k8s.io", ClientConfig: admissionregistrationv1.WebhookClientConfig{CABundle: caBundle}},
+			},
+		},
+	}
+}
+
+func newTestClientBuilder(t *testing.T, objs ...client.Object) *fake.ClientBuilder {
+	scheme := runtime.NewScheme()
+	if err := clientgoscheme.AddToScheme(scheme); err != nil {
+		t.Fatalf("failed to build scheme: %v", err)
+	}
+	return fake.NewClientBuilder().WithScheme(scheme).WithObjects(objs...)
+}
+
+func getTestWebhookSecret(t *testing.T, c client.Client) *corev1.Secret {
+	secret := &corev1.Secret{}
+	if err := c.Get(context.Background(), types.NamespacedName{Name: testSecretName, Namespace: testSecretNamespace}, secret); err != nil {
+		t.Fatalf("failed to get webhook secret: %v", err)
+	}
+	return secret
+}
+
+// waitForSync waits until the rotator completes a sync started after the call.
+func waitForSync(t *testing.T, rotator *certificate.Rotator) {
+	start := time.Now()
+	if err := wait.PollUntilContextTimeout(context.Background(), 5*time.Millisecond, 10*time.Second, true, func(context.Context) (bool, error) {
+		return rotator.LastSuccessfulSync().After(start), nil
+	}); err != nil {
+		t.Fatalf("rotator did not sync: %v", err)
+	}
+}
+
+func TestRotatorCheckServingCert(t *testing.T) {
+	certDir := t.TempDir()
+	cp := certificate.NewProvider(nil, "spark-operator-webhook-svc", "default", certificate.DefaultValidity, certificate.DefaultRenewBefore)
+	rotator := certificate.NewRotator(cp, "spark-operator-webhook-secret", "default", "spark-operator-webhook", "spark-operator-webhook", certDir, "tls.crt", "tls.key", time.Minute)
+
+	if err := rotator.CheckServingCert(nil); err == nil {
+		t.Errorf("expected readiness check to fail without serving certificate")
+	}
+
+	if err := cp.Generate(); err != nil {
+		t.Fatalf("failed to generate certificates: %v", err)
+	}
+	if err := cp.WriteFile(certDir, "tls.crt", "tls.key"); err != nil {
+		t.Fatalf("failed to write certificates: %v", err)
+	}
+	if err := rotator.CheckServingCert(nil); err != nil {
+		t.Errorf("expected readiness check to succeed, got %v", err)
+	}
+
+	notAfter, err := rotator.ServingCertNotAfter()
+	if err != nil {
+		t.Fatalf("failed to get serving certificate expiry: %v", err)
+	}
+	if remaining := time.Until(notAfter); remaining <= 0 || remaining > certificate.DefaultValidity {
+		t.Errorf("unexpected serving certificate expiry %s", notAfter)
+	}
+
+	if err := os.WriteFile(filepath.Join(certDir, "tls.crt"), []byte("invalid"), 0600); err != nil {
+		t.Fatalf("failed to overwrite certificate: %v", err)
+	}
+	if err := rotator.CheckServingCert(nil); err == nil {
+		t.Errorf("expected readiness check to fail with an invalid serving certificate")
+	}
+	if !rotator.LastSuccessfulSync().IsZero() || rotator.SyncFailures() != 0 {
+		t.Errorf("expected no sync to be recorded")
+	}
+}
+
+func TestRotatorSwitchesServerCertAfterCABundleUpdate(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	certDir := t.TempDir()
+	// The CA is within its renewal period while the server certificate is not.
+	secret, oldCACert, oldServerCert := newTestWebhookSecret(t, time.Now().Add(10*24*time.Hour), 300*24*time.Hour)
+	objs := append(newTestWebhookConfigurations(oldCACert), secret)
+	c := newTestClientBuilder(t, objs...).Build()
+
+	cp := certificate.NewProvider(c, "spark-operator-webhook-svc", "default", certificate.DefaultValidity, certificate.DefaultRenewBefore)
+	var caBundleChanges atomic.Int32
+	cp.OnCABundleChange(func() { caBundleChanges.Add(1) })
+	rotator := certificate.NewRotator(cp, testSecretName, testSecretNamespace, testWebhookName, testWebhookName, certDir, "tls.crt", "tls.key", 5*time.Millisecond)
+	go func() {
+		_ = rotator.Start(ctx)
+	}()
+	waitForSync(t, rotator)
+	waitForSync(t, rotator)
+
+	// The new CA is published along with the previous one, while the server certificate signed by the previous CA
+	// is still served.
+	rotated := getTestWebhookSecret(t, c)
+	newCACert := rotated.Data[common.CACertPem]
+	if bytes.Equal(newCACert, oldCACert) {
+		t.Fatalf("expected CA certificate to be rotated")
+	}
+	if !bytes.Equal(rotated.Data[common.PreviousCACertPem], oldCACert) {
+		t.Errorf("expected previous CA certificate to be kept in the secret")
+	}
+	if !bytes.Equal(rotated.Data[common.ServerCertPem], oldServerCert) {
+		t.Errorf("expected server certificate not to be switched before the CA bundle is updated")
+	}
+	caBundle, err := cp.CABundle()
+	if err != nil {
+		t.Fatalf("failed to get CA bundle: %v", err)
+	}
+	if !bytes.Equal(caBundle, append(append([]byte{}, newCACert...), oldCACert...)) {
+		t.Errorf("expected CA bundle to contain the new and previous CA certificates")
+	}
+	if caBundleChanges.Load() < 2 {
+		t.Errorf("expected CA bundle listener to be notified of the rotation, got %d notifications", caBundleChanges.Load())
+	}
+	if !cp.NeedsServerCertSwitch() {
+		t.Errorf("expected server certificate switch to be pending")
+	}
+	served, err := os.ReadFile(filepath.Join(certDir, "tls.crt"))
+	if err != nil {
+		t.Fatalf("failed to read serving certificate: %v", err)
+	}
+	if !bytes.Equal(served, oldServerCert) {
+		t.Errorf("expected previous server certificate to be served")
+	}
+
+	// Once the webhook configurations trust the new CA, the server certificate is switched.
+	for _, obj := range newTestWebhookConfigurations(caBundle) {
+		current := obj.DeepCopyObject().(client.Object)
+		if err := c.Get(ctx, client.ObjectKeyFromObject(obj), current); err != nil {
+			t.Fatalf("failed to get webhook configuration: %v", err)
+		}
+		obj.SetResourceVersion(current.GetResourceVersion())
+		if err := c.Update(ctx, obj); err != nil {
+			t.Fatalf("failed to update webhook configuration: %v", err)
+		}
+	}
+	if err := wait.PollUntilContextTimeout(ctx, 5*time.Millisecond, 10*time.Second, true, func(context.Context) (bool, error) {
+		return !cp.NeedsServerCertSwitch(), nil
+	}); err != nil {
+		t.Fatalf("server certificate was not switched: %v", err)
+	}
+	waitForSync(t, rotator)
+
+	switched := getTestWebhookSecret(t, c)
+	if bytes.Equal(switched.Data[common.ServerCertPem], oldServerCert) {
+		t.Fatalf("expected server certificate to be switched")
+	}
+	if !bytes.Equal(switched.Data[common.CACertPem], newCACert) {
+		t.Errorf("expected CA certificate to be kept")
+	}
+	served, err = os.ReadFile(filepath.Join(certDir, "tls.crt"))
+	if err != nil {
+		t.Fatalf("failed to read serving certificate: %v", err)
+	}
+	if !bytes.Equal(served, switched.Data[common.ServerCertPem]) {
+		t.Errorf("expected switched server certificate to be served")
+	}
+	block, _ := pem.Decode(served)
+	serverCert, err := x509.ParseCertificate(block.Bytes)
+	if err != nil {
+		t.Fatalf("failed to parse serving certificate: %v", err)
+	}
+	caBlock, _ := pem.Decode(newCACert)
+	caCert, err := x509.ParseCertificate(caBlock.Bytes)
+	if err != nil {
+		t.Fatalf("failed to parse CA certificate: %v", err)
+	}
+	if err := serverCert.CheckSignatureFrom(caCert); err != nil {
+		t.Errorf("expected served certificate to be signed by the new CA: %v", err)
+	}
+}
+
+func TestProviderSyncSecretFailedUpdate(t *testing.T) {
+	// The server certificate is within its renewal period.
+	secret, _, serverCert := newTestWebhookSecret(t, time.Now().Add(5*365*24*time.Hour), 24*time.Hour)
+
+	t.Run("keeps the certificates of the secret", func(t *testing.T) {
+		c := newTestClientBuilder(t, secret.DeepCopy()).WithInterceptorFuncs(interceptor.Funcs{
+			Update: func(context.Context, client.WithWatch, client.Object, ...client.UpdateOption) error {
+				return fmt.Errorf("connection refused")
+			},
+		}).Build()
+		cp := certificate.NewProvider(c, "spark-operator-webhook-svc", "default", certificate.DefaultValidity, certificate.DefaultRenewBefore)
+
+		if err := cp.SyncSecret(context.Background(), testSecretName, testSecretNamespace); err == nil {
+			t.Fatalf("expected sync to fail")
+		}
+		current, err := cp.ServerCert()
+		if err != nil {
+			t.Fatalf("failed to get server certificate: %v", err)
+		}
+		if !bytes.Equal(current, serverCert) {
+			t.Errorf("expected server certificate of the secret to be kept after a failed update")
+		}
+	})
+
+	t.Run("loads the certificates written by another replica", func(t *testing.T) {
+		other, _, otherServerCert := newTestWebhookSecret(t, time.Now().Add(5*365*24*time.Hour), 300*24*time.Hour)
+		c := newTestClientBuilder(t, secret.DeepCopy()).WithInterceptorFuncs(interceptor.Funcs{
+			Update: func(ctx context.Context, c client.WithWatch, obj client.Object, opts ...client.UpdateOption) error {
+				// Another replica rotates the certificates first.
+				latest := &corev1.Secret{}
+				if err := c.Get(ctx, client.ObjectKeyFromObject(obj), latest); err != nil {
+					return err
+				}
+				latest.Data = other.Data
+				if err := c.Update(ctx, latest, opts...); err != nil {
+					return err
+				}
+				return errors.NewConflict(schema.GroupResource{Resource: "secrets"}, obj.GetName(), fmt.Errorf("object has been modified"))
+			},
+		}).Build()
+		cp := certificate.NewProvider(c, "spark-operator-webhook-svc", "default", certificate.DefaultValidity, certificate.DefaultRenewBefore)
+
+		if err := cp.SyncSecret(context.Background(), testSecretName, testSecretNamespace); err != nil {
+			t.Fatalf("failed to sync secret: %v", err)
+		}
+		current, err := cp.ServerCert()
+		if err != nil {
+			t.Fatalf("failed to get server certificate: %v", err)
+		}
+		if !bytes.Equal(current, otherServerCert) {
+			t.Errorf("expected server certificate written by the other replica to be loaded")
+		}
+	})
+}
@@ -39,7 +39,7 @@ func NewPrivateKey() (*rsa.PrivateKey, error) {
 	return key, nil
 }
 
-func NewSignedServerCert(cfg cert.Config, caKey *rsa.PrivateKey, caCert *x509.Certificate, serverKey *rsa.PrivateKey) (*x509.Certificate, error) {
+func NewSignedServerCert(cfg cert.Config, caKey *rsa.PrivateKey, caCert *x509.Certificate, serverKey *rsa.PrivateKey, validity time.Duration) (*x509.Certificate, error) {
 	// Generate a random serial number in [1, max).
 	serial, err := rand.Int(rand.Reader, new(big.Int).SetInt64(math.MaxInt64-1))
 	if err != nil {
@@ -63,7 +63,7 @@ func NewSignedServerCert(cfg cert.Config, caKey *rsa.PrivateKey, caCert *x509.Ce
 		DNSNames:              cfg.AltNames.DNSNames,
 		IPAddresses:           cfg.AltNames.IPs,
 		NotBefore:             notBefore,
-		NotAfter:              now.AddDate(10, 0, 0),
+		NotAfter:              now.Add(validity).UTC(),
 		KeyUsage:              x509.KeyUsageContentCommitment | x509.KeyUsageDigitalSignature | x509.KeyUsageKeyEncipherment,
 		ExtKeyUsage:           []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth, x509.ExtKeyUsageClientAuth},
 		BasicConstraintsValid: true,
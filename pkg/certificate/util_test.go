@@ -47,12 +47,16 @@ func TestNewSignedServerCert(t *testing.T) {
 	caCert := &x509.Certificate{}
 	serverKey, _ := rsa.GenerateKey(rand.Reader, common.RSAKeySize)
 
-	serverCert, err := certificate.NewSignedServerCert(cfg, caKey, caCert, serverKey)
+	serverCert, err := certificate.NewSignedServerCert(cfg, caKey, caCert, serverKey, time.Hour)
 	if err != nil {
 		t.Errorf("failed to generate signed server certificate: %v", err)
 	}
 
 	if serverCert == nil {
-		t.Error("server certificate is nil")
+		t.Fatal("server certificate is nil")
+	}
+
+	if serverCert.NotAfter.After(time.Now().Add(time.Hour)) {
+		t.Errorf("server certificate expires at %v, later than its validity of 1h", serverCert.NotAfter)
 	}
 }
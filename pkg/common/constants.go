@@ -34,6 +34,9 @@ const (
 	CACertPem     = "ca-cert.pem"
 	ServerKeyPem  = "server-key.pem"
 	ServerCertPem = "server-cert.pem"
+
+	// PreviousCACertPem is the CA certificate replaced by the last CA rotation, which is trusted until it expires.
+	PreviousCACertPem = "ca-previous-cert.pem"
 )
 
 // Kubernetes volume types.
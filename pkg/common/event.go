@@ -29,6 +29,30 @@ const (
 	EventSparkApplicationFailed = "SparkApplicationFailed"
 
 	EventSparkApplicationPendingRerun = "SparkApplicationPendingRerun"
+
+	EventSparkApplicationWaitingForDependencies = "SparkApplicationWaitingForDependencies"
+
+	EventSparkApplicationResourceRecommendation = "SparkApplicationResourceRecommendation"
+
+	EventSparkApplicationSLAViolated = "SparkApplicationSLAViolated"
+
+	EventSparkApplicationExecutorQuotaExceeded = "SparkApplicationExecutorQuotaExceeded"
+
+	EventSparkApplicationOutputMissing = "SparkApplicationOutputMissing"
+
+	EventSparkApplicationPostCompletionHookFailed = "SparkApplicationPostCompletionHookFailed"
+)
+
+// SparkApplicationGroup events
+const (
+	EventSparkApplicationGroupMemberCreated = "SparkApplicationGroupMemberCreated"
+
+	EventSparkApplicationGroupMemberStopped = "SparkApplicationGroupMemberStopped"
+)
+
+// SparkSQLGateway events
+const (
+	EventSparkSQLGatewayReconcileFailed = "SparkSQLGatewayReconcileFailed"
 )
 
 // Spark driver events
@@ -42,6 +66,8 @@ const (
 	EventSparkDriverFailed = "SparkDriverFailed"
 
 	EventSparkDriverUnknown = "SparkDriverUnknown"
+
+	EventSparkDriverSidecarsTerminated = "SparkDriverSidecarsTerminated"
 )
 
 // Spark executor events
@@ -55,4 +81,12 @@ const (
 	EventSparkExecutorFailed = "SparkExecutorFailed"
 
 	EventSparkExecutorUnknown = "SparkExecutorUnknown"
+
+	EventSparkExecutorNodeUnreachable = "SparkExecutorNodeUnreachable"
+
+	EventSparkExecutorDecommissioning = "SparkExecutorDecommissioning"
+
+	EventSparkExecutorSecretRotation = "SparkExecutorSecretRotation"
+
+	EventSparkApplicationPostRunActionFailed = "SparkApplicationPostRunActionFailed"
 )
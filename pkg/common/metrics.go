@@ -37,6 +37,19 @@ const (
 	MetricSparkApplicationStartLatencySeconds = "spark_application_start_latency_seconds"
 
 	MetricSparkApplicationStartLatencySecondsHistogram = "spark_application_start_latency_seconds_histogram"
+
+	MetricSparkApplicationSLAViolationCount = "spark_application_sla_violation_count"
+)
+
+// Spark submission metric names.
+const (
+	MetricSparkSubmissionInFlightCount = "spark_submission_in_flight_count"
+
+	MetricSparkSubmissionQueuedCount = "spark_submission_queued_count"
+
+	MetricSparkSubmissionBacklogCount = "spark_submission_backlog_count"
+
+	MetricSparkSubmissionDurationSeconds = "spark_submission_duration_seconds"
 )
 
 // Spark executor metric names.
@@ -46,4 +59,28 @@ const (
 	MetricSparkExecutorSuccessCount = "spark_executor_success_count"
 
 	MetricSparkExecutorFailureCount = "spark_executor_failure_count"
+
+	MetricSparkExecutorZoneRunningCount = "spark_executor_zone_running_count"
+)
+
+// Webhook certificate metric names.
+const (
+	MetricWebhookCertificateExpiryTimestampSeconds = "webhook_certificate_expiry_timestamp_seconds"
+
+	MetricWebhookCertificateLastReloadTimestampSeconds = "webhook_certificate_last_reload_timestamp_seconds"
+
+	MetricWebhookCertificateReloadFailureCount = "webhook_certificate_reload_failure_count"
+)
+
+// Spark executor metric label names.
+const (
+	MetricLabelNamespace = "namespace"
+
+	MetricLabelAppName = "app_name"
+
+	MetricLabelZone = "zone"
+
+	MetricLabelSLAViolation = "violation"
+
+	MetricLabelCertificate = "certificate"
 )
@@ -48,6 +48,8 @@ const (
 
 	SparkUIProxyBase = "spark.ui.proxyBase"
 
+	SparkRedactionRegex = "spark.redaction.regex"
+
 	SparkUIProxyRedirectURI = "spark.ui.proxyRedirectUri"
 )
 
@@ -71,10 +73,26 @@ const (
 	// secrets.
 	SparkKubernetesContainerImagePullSecrets = "spark.kubernetes.container.image.pullSecrets"
 
+	// SparkKubernetesAllocationBatchSize is the Spark configuration key for specifying the number of executor pods
+	// created in each round of executor allocation.
 	SparkKubernetesAllocationBatchSize = "spark.kubernetes.allocation.batch.size"
 
+	// SparkKubernetesAllocationBatchDelay is the Spark configuration key for specifying the time to wait between
+	// rounds of executor allocation.
 	SparkKubernetesAllocationBatchDelay = "spark.kubernetes.allocation.batch.delay"
 
+	// SparkSchedulerMinRegisteredResourcesRatio is the Spark configuration key for specifying the ratio of requested
+	// executors that must register before tasks are scheduled.
+	SparkSchedulerMinRegisteredResourcesRatio = "spark.scheduler.minRegisteredResourcesRatio"
+
+	// SparkSchedulerMaxRegisteredResourcesWaitingTime is the Spark configuration key for specifying the maximum time
+	// to wait for the minimum ratio of executors to register before tasks are scheduled.
+	SparkSchedulerMaxRegisteredResourcesWaitingTime = "spark.scheduler.maxRegisteredResourcesWaitingTime"
+
+	// DefaultSparkKubernetesAllocationBatchSize is the default number of executor pods created in each round of
+	// executor allocation.
+	DefaultSparkKubernetesAllocationBatchSize = 10
+
 	// SparkKubernetesAuthenticateDriverServiceAccountName is the Spark configuration key for specifying name of the Kubernetes service
 	// account used by the driver pod.
 	SparkKubernetesAuthenticateDriverServiceAccountName = "spark.kubernetes.authenticate.driver.serviceAccountName"
@@ -82,6 +100,13 @@ const (
 	// account used by the executor pod.
 	SparkKubernetesAuthenticateExecutorServiceAccountName = "spark.kubernetes.authenticate.executor.serviceAccountName"
 
+	// SparkKubernetesAuthenticateSubmissionOAuthTokenFile is the Spark configuration key for specifying the file
+	// containing the OAuth token spark-submit authenticates against the Kubernetes API server with.
+	SparkKubernetesAuthenticateSubmissionOAuthTokenFile = "spark.kubernetes.authenticate.submission.oauthTokenFile"
+
+	// SparkHadoopPrefix is the prefix of the Spark configuration keys of Hadoop configuration properties.
+	SparkHadoopPrefix = "spark.hadoop."
+
 	// SparkKubernetesDriverLabelPrefix is the Spark configuration key prefix for labels on the driver Pod.
 	SparkKubernetesDriverLabelTemplate = "spark.kubernetes.driver.label.%s"
 
@@ -105,6 +130,29 @@ const (
 
 	SparkKubernetesExecutorPodNamePrefix = "spark.kubernetes.executor.podNamePrefix"
 
+	// SparkKubernetesDriverOwnPersistentVolumeClaim is the Spark configuration key for specifying whether the driver
+	// owns the on-demand persistent volume claims of the executors instead of the executor pods.
+	SparkKubernetesDriverOwnPersistentVolumeClaim = "spark.kubernetes.driver.ownPersistentVolumeClaim"
+
+	// SparkKubernetesDriverReusePersistentVolumeClaim is the Spark configuration key for specifying whether the driver
+	// reuses the persistent volume claims of deleted executors.
+	SparkKubernetesDriverReusePersistentVolumeClaim = "spark.kubernetes.driver.reusePersistentVolumeClaim"
+
+	// SparkAuthenticate is the Spark configuration key for specifying whether Spark authenticates its internal connections.
+	SparkAuthenticate = "spark.authenticate"
+
+	// SparkAuthenticateSecretFile is the Spark configuration key for specifying the file holding the authentication secret.
+	SparkAuthenticateSecretFile = "spark.authenticate.secret.file"
+
+	// SparkNetworkCryptoEnabled is the Spark configuration key for specifying whether AES-based RPC encryption is enabled.
+	SparkNetworkCryptoEnabled = "spark.network.crypto.enabled"
+
+	// SparkDriverHost is the Spark configuration key for the hostname or IP address executors use to reach the driver.
+	SparkDriverHost = "spark.driver.host"
+
+	// SparkDriverBindAddress is the Spark configuration key for the address the driver binds listening sockets to.
+	SparkDriverBindAddress = "spark.driver.bindAddress"
+
 	// SparkKubernetesDriverRequestCores is the configuration property for specifying the physical CPU request for the driver.
 	SparkKubernetesDriverRequestCores = "spark.kubernetes.driver.request.cores"
 
@@ -216,6 +264,10 @@ const (
 
 	// SparkKubernetesExecutorDeleteOnTermination is the Spark configuration for specifying whether executor pods should be deleted in case of failure or normal termination.
 	SparkKubernetesExecutorDeleteOnTermination = "spark.kubernetes.executor.deleteOnTermination"
+
+	// SparkDecommissionEnabled is the Spark configuration for enabling graceful decommissioning of executors,
+	// which migrates their blocks to other executors before they are shut down.
+	SparkDecommissionEnabled = "spark.decommission.enabled"
 )
 
 // Dynamic allocation properties.
@@ -225,8 +277,12 @@ const (
 	// allocation is enabled or not.
 	SparkDynamicAllocationEnabled = "spark.dynamicAllocation.enabled"
 
+	// SparkDynamicAllocationExecutorIdleTimeout is the Spark configuration key for specifying the
+	// duration after which idle executors are removed if dynamic allocation is enabled.
 	SparkDynamicAllocationExecutorIdleTimeout = "spark.dynamicAllocation.executorIdleTimeout"
 
+	// SparkDynamicAllocationCachedExecutorIdleTimeout is the Spark configuration key for specifying the
+	// duration after which idle executors holding cached data are removed if dynamic allocation is enabled.
 	SparkDynamicAllocationCachedExecutorIdleTimeout = "spark.dynamicAllocation.cachedExecutorIdleTimeout"
 
 	// SparkDynamicAllocationInitialExecutors is the Spark configuration key for specifying
@@ -243,6 +299,8 @@ const (
 
 	SparkDynamicAllocationExecutorAllocationRatio = "spark.dynamicAllocation.executorAllocationRatio"
 
+	// SparkDynamicAllocationSchedulerBacklogTimeout is the Spark configuration key for specifying the
+	// duration tasks may be backlogged before new executors are requested if dynamic allocation is enabled.
 	SparkDynamicAllocationSchedulerBacklogTimeout = "spark.dynamicAllocation.schedulerBacklogTimeout"
 
 	SparkDynamicAllocationSustainedSchedulerBacklogTimeout = "spark.dynamicAllocation.sustainedSchedulerBacklogTimeout"
@@ -254,6 +312,14 @@ const (
 	// SparkDynamicAllocationShuffleTrackingTimeout is the Spark configuration key for specifying
 	// the shuffle tracking timeout in milliseconds if shuffle tracking is enabled.
 	SparkDynamicAllocationShuffleTrackingTimeout = "spark.dynamicAllocation.shuffleTracking.timeout"
+
+	// DefaultSparkDynamicAllocationExecutorIdleTimeout is the default duration after which idle executors are
+	// removed if dynamic allocation is enabled.
+	DefaultSparkDynamicAllocationExecutorIdleTimeout = "60s"
+
+	// DefaultSparkDynamicAllocationSchedulerBacklogTimeout is the default duration tasks may be backlogged before
+	// new executors are requested if dynamic allocation is enabled.
+	DefaultSparkDynamicAllocationSchedulerBacklogTimeout = "1s"
 )
 
 const (
@@ -262,6 +328,15 @@ const (
 
 	// SparkRoleExecutor is the value of the spark-role label for the executors.
 	SparkRoleExecutor = "executor"
+
+	// SparkRoleImagePrefetch is the value of the spark-role label for the pods prefetching the executor image.
+	SparkRoleImagePrefetch = "image-prefetch"
+)
+
+const (
+	// ResourceReservationPriorityClass is the priority class the ResourceQuotas recording resource reservations are
+	// scoped to. No pods are expected to use it, so that the ResourceQuotas do not constrain any pods themselves.
+	ResourceReservationPriorityClass = "spark-operator-resource-reservation"
 )
 
 const (
@@ -288,6 +363,120 @@ const (
 	EnvHadoopConfDir = "HADOOP_CONF_DIR"
 )
 
+const (
+	// SparkAuthSecretKey is the key of the authentication secret in the Secret generated for an application.
+	SparkAuthSecretKey = "secret"
+
+	// SparkAuthSecretMountPath is the directory where the generated authentication Secret is mounted.
+	SparkAuthSecretMountPath = "/etc/spark-auth"
+)
+
+const (
+	// KerberosKeytabVolumeName is the name of the Secret volume holding the Kerberos keytab.
+	KerberosKeytabVolumeName = "kerberos-keytab-volume"
+
+	// KerberosKrb5ConfVolumeName is the name of the ConfigMap volume holding the Kerberos configuration.
+	KerberosKrb5ConfVolumeName = "kerberos-krb5-conf-volume"
+
+	// KerberosCCacheVolumeName is the name of the volume holding the Kerberos ticket cache shared by the containers of a pod.
+	KerberosCCacheVolumeName = "kerberos-ccache-volume"
+
+	// DefaultKerberosKeytabSecretKey is the default key of the keytab in the Kerberos keytab Secret.
+	DefaultKerberosKeytabSecretKey = "krb5.keytab"
+
+	// KerberosKeytabMountPath is the directory where the Kerberos keytab is mounted.
+	KerberosKeytabMountPath = "/etc/security/keytabs"
+
+	// KerberosKrb5ConfFileName is the key of the Kerberos configuration in the krb5 ConfigMap.
+	KerberosKrb5ConfFileName = "krb5.conf"
+
+	// KerberosKrb5ConfPath is the path where the Kerberos configuration is mounted.
+	KerberosKrb5ConfPath = "/etc/krb5.conf"
+
+	// KerberosCCacheMountPath is the directory where the Kerberos ticket cache is stored.
+	KerberosCCacheMountPath = "/var/run/krb5cc"
+
+	// KerberosInitContainerName is the name of the init container obtaining the initial Kerberos ticket.
+	KerberosInitContainerName = "kerberos-init"
+
+	// KerberosRenewerContainerName is the name of the sidecar container periodically renewing the Kerberos ticket.
+	KerberosRenewerContainerName = "kerberos-renewer"
+
+	// EnvKrb5Config is the environment variable pointing to the Kerberos configuration.
+	EnvKrb5Config = "KRB5_CONFIG"
+
+	// EnvKrb5CCName is the environment variable pointing to the Kerberos ticket cache.
+	EnvKrb5CCName = "KRB5CCNAME"
+
+	// SparkKerberosRenewalCredentials is the Spark configuration key for specifying how the driver renews Kerberos credentials.
+	SparkKerberosRenewalCredentials = "spark.kerberos.renewal.credentials"
+)
+
+const (
+	// MainApplicationFileConfigMapScheme is the scheme of a main application file embedded in a ConfigMap,
+	// in the form of `configmap://<configmap-name>/<key>`.
+	MainApplicationFileConfigMapScheme = "configmap://"
+
+	// DefaultMainApplicationFileMountPath is the directory where the ConfigMap holding the main application file
+	// is mounted in the driver container.
+	DefaultMainApplicationFileMountPath = "/opt/spark/main-application"
+
+	// MainApplicationFileConfigMapVolumeName is the name of the ConfigMap volume of the main application file.
+	MainApplicationFileConfigMapVolumeName = "main-application-file-volume"
+
+	// MainApplicationSourceConfigMapNameSuffix is the name suffix of the ConfigMap holding the inline source code
+	// of the main application.
+	MainApplicationSourceConfigMapNameSuffix = "main-source"
+)
+
+const (
+	// ConfPropertiesConfigMapNameSuffix is the name suffix of the ConfigMap holding the SparkConf and HadoopConf
+	// properties of a SparkApplication.
+	ConfPropertiesConfigMapNameSuffix = "conf-properties"
+
+	// ConfPropertiesConfigMapVolumeName is the name of the ConfigMap volume of the SparkConf and HadoopConf properties.
+	ConfPropertiesConfigMapVolumeName = "conf-properties-volume"
+
+	// DefaultConfPropertiesMountPath is the directory where the ConfigMap holding the SparkConf and HadoopConf
+	// properties is mounted in the driver and executor containers.
+	DefaultConfPropertiesMountPath = "/etc/spark-operator/conf"
+
+	// SparkConfPropertiesKey is the key of the SparkConf properties in the ConfigMap.
+	SparkConfPropertiesKey = "spark.properties"
+
+	// HadoopConfPropertiesKey is the key of the HadoopConf properties in the ConfigMap.
+	HadoopConfPropertiesKey = "hadoop.properties"
+)
+
+const (
+	// OCIArtifactScheme is the scheme of a main application file or dependency pulled from an OCI registry, in the
+	// form of `oci://<registry>/<repository>:<tag>/<file>` or `oci://<registry>/<repository>@<digest>/<file>`.
+	OCIArtifactScheme = "oci://"
+
+	// DefaultOCIArtifactMountPath is the directory where OCI artifacts are pulled to in the driver and executor pods.
+	DefaultOCIArtifactMountPath = "/opt/spark/oci-artifacts"
+
+	// OCIArtifactVolumeName is the name of the volume holding the pulled OCI artifacts.
+	OCIArtifactVolumeName = "oci-artifacts-volume"
+
+	// OCIArtifactRegistryConfigVolumeName is the name of the volume of the Secret holding the registry credentials.
+	OCIArtifactRegistryConfigVolumeName = "oci-artifacts-registry-config-volume"
+
+	// OCIArtifactRegistryConfigMountPath is the directory where the Secret holding the registry credentials is mounted.
+	OCIArtifactRegistryConfigMountPath = "/etc/oci-artifacts"
+
+	// OCIArtifactPullerContainerNamePrefix is the name prefix of the init containers pulling OCI artifacts.
+	OCIArtifactPullerContainerNamePrefix = "oci-artifact-puller"
+
+	// DefaultOCIArtifactPullerImage is the default image of the init containers pulling OCI artifacts.
+	DefaultOCIArtifactPullerImage = "ghcr.io/oras-project/oras:v1.2.0"
+
+	// ScheduledRunTimeFormat is the UTC time layout used in the names and the LabelScheduledRunTime label of
+	// SparkApplications created by a ScheduledSparkApplication. It sorts lexicographically in chronological order
+	// and is valid in both resource names and label values.
+	ScheduledRunTimeFormat = "20060102-150405"
+)
+
 const (
 	// LabelSparkApplicationSelector is the AppID set by the spark-distribution on the driver/executors Pods.
 	LabelSparkApplicationSelector = "spark-app-selector"
@@ -304,6 +493,10 @@ const (
 	// LabelScheduledSparkAppName is the name of the label for the ScheduledSparkApplication object name.
 	LabelScheduledSparkAppName = LabelAnnotationPrefix + "scheduled-app-name"
 
+	// LabelScheduledRunTime is the name of the label recording the scheduled fire time of a SparkApplication
+	// created by a ScheduledSparkApplication, formatted with ScheduledRunTimeFormat.
+	LabelScheduledRunTime = LabelAnnotationPrefix + "scheduled-run-time"
+
 	// LabelLaunchedBySparkOperator is a label on Spark pods launched through the Spark Operator.
 	LabelLaunchedBySparkOperator = LabelAnnotationPrefix + "launched-by-spark-operator"
 
@@ -313,8 +506,68 @@ const (
 	// LabelSubmissionID is the label that records the submission ID of the current run of an application.
 	LabelSubmissionID = LabelAnnotationPrefix + "submission-id"
 
+	// LabelResourceReservation is the label on the ResourceQuotas recording the resources reserved for SparkApplications.
+	LabelResourceReservation = LabelAnnotationPrefix + "resource-reservation"
+
+	// LabelSubmitterJob is the label on the Jobs running spark-submit for SparkApplications.
+	LabelSubmitterJob = LabelAnnotationPrefix + "submitter-job"
+
+	// LabelMetricsService is the label on the Services exposing the metrics port of Spark drivers.
+	LabelMetricsService = LabelAnnotationPrefix + "metrics-service"
+
 	// LabelSparkExecutorID is the label that records executor pod ID
 	LabelSparkExecutorID = "spark-exec-id"
+
+	// LabelSparkAppGroupName is the name of the label for the SparkApplicationGroup object name.
+	LabelSparkAppGroupName = LabelAnnotationPrefix + "app-group-name"
+
+	// LabelSparkAppGroupMemberName is the name of the label for the member name of a SparkApplication within its group.
+	LabelSparkAppGroupMemberName = LabelAnnotationPrefix + "app-group-member-name"
+
+	// LabelSparkSQLGatewayName is the name of the label for the SparkSQLGateway object name.
+	LabelSparkSQLGatewayName = LabelAnnotationPrefix + "sql-gateway-name"
+)
+
+const (
+	// AnnotationRecommendedDriverMemory is the annotation that records the recommended driver memory.
+	AnnotationRecommendedDriverMemory = LabelAnnotationPrefix + "recommended-driver-memory"
+
+	// AnnotationRecommendedExecutorMemory is the annotation that records the recommended executor memory.
+	AnnotationRecommendedExecutorMemory = LabelAnnotationPrefix + "recommended-executor-memory"
+
+	// AnnotationRecommendedDriverCores is the annotation that records the recommended driver cores.
+	AnnotationRecommendedDriverCores = LabelAnnotationPrefix + "recommended-driver-cores"
+
+	// AnnotationRecommendedExecutorCores is the annotation that records the recommended executor cores.
+	AnnotationRecommendedExecutorCores = LabelAnnotationPrefix + "recommended-executor-cores"
+
+	// AnnotationRecommendationHistory is the annotation that records the history of the runs the resource
+	// recommendations are derived from as a JSON array.
+	AnnotationRecommendationHistory = LabelAnnotationPrefix + "recommendation-history"
+
+	// AnnotationRecommendationSubmissionID is the annotation that records the submission ID of the run
+	// the resource recommendations are derived from.
+	AnnotationRecommendationSubmissionID = LabelAnnotationPrefix + "recommendation-submission-id"
+
+	// AnnotationDryRunPatch is the annotation on Spark pods that records the JSON patch the mutating webhook
+	// would have applied when running in dry-run mode.
+	AnnotationDryRunPatch = LabelAnnotationPrefix + "dry-run-patch"
+
+	// AnnotationMutations is the annotation on Spark pods that records the comma-separated fields the mutating
+	// webhook changed, e.g. `spec.volumes,spec.containers[spark-kubernetes-driver].env`.
+	AnnotationMutations = LabelAnnotationPrefix + "mutations"
+
+	// AnnotationAppliedDefaults is the annotation on Spark pods that records the comma-separated kinds of operator
+	// defaults injected into the pod.
+	AnnotationAppliedDefaults = LabelAnnotationPrefix + "applied-defaults"
+
+	// AnnotationSuppressedDefaults is the annotation on Spark pods that records the comma-separated kinds of operator
+	// defaults configured for the pod but disabled by the SparkApplication.
+	AnnotationSuppressedDefaults = LabelAnnotationPrefix + "suppressed-defaults"
+
+	// AnnotationSecretHash is the annotation on executor pods that records the hash of the contents of the Secrets
+	// mounted into them, as published in the status of the SparkApplication when the pods were created.
+	AnnotationSecretHash = LabelAnnotationPrefix + "secret-hash"
 )
 
 const (
@@ -331,6 +584,13 @@ const (
 
 	// SparkLocalDirVolumePrefix is the volume name prefix for "scratch" space directory.
 	SparkLocalDirVolumePrefix = "spark-local-dir-"
+
+	// SparkLocalDirMountPathPrefix is the path prefix where the local dir volumes provisioned according to
+	// the local dir policy of an application are mounted.
+	SparkLocalDirMountPathPrefix = "/var/data/"
+
+	// SparkVolumeOnDemandClaimName is the claim name that makes Spark create a PersistentVolumeClaim for each pod.
+	SparkVolumeOnDemandClaimName = "OnDemand"
 )
 
 const (
@@ -339,6 +599,41 @@ const (
 	DefaultSparkWebUIPort int32 = 4040
 
 	DefaultSparkWebUIPortName = "spark-driver-ui-port"
+
+	// SparkUIOAuth2ProxyContainerName is the name of the oauth2-proxy sidecar authenticating users of the web UI.
+	SparkUIOAuth2ProxyContainerName = "oauth2-proxy"
+
+	// DefaultSparkUIOAuth2ProxyImage is the default container image of the oauth2-proxy sidecar.
+	DefaultSparkUIOAuth2ProxyImage = "quay.io/oauth2-proxy/oauth2-proxy:v7.7.1"
+
+	// DefaultSparkUIOAuth2ProxyPort is the default port the oauth2-proxy sidecar listens on.
+	DefaultSparkUIOAuth2ProxyPort int32 = 4180
+
+	// DefaultSparkUIAuthRealm is the default realm of basic authentication of the web UI.
+	DefaultSparkUIAuthRealm = "Spark UI"
+
+	// SparkUIAuthSecretKey is the key of the htpasswd file in the basic authentication Secret read by ingress-nginx.
+	SparkUIAuthSecretKey = "auth"
+)
+
+// Network port properties.
+const (
+	// SparkDriverPort is the Spark configuration key for specifying the port of the driver RPC endpoint.
+	SparkDriverPort = "spark.driver.port"
+
+	// SparkDriverBlockManagerPort is the Spark configuration key for specifying the port of the block manager
+	// of the driver.
+	SparkDriverBlockManagerPort = "spark.driver.blockManager.port"
+
+	// SparkBlockManagerPort is the Spark configuration key for specifying the port of the block managers of the
+	// driver and executors.
+	SparkBlockManagerPort = "spark.blockManager.port"
+
+	// DefaultSparkDriverPort is the default port of the driver RPC endpoint on Kubernetes.
+	DefaultSparkDriverPort int32 = 7078
+
+	// DefaultSparkBlockManagerPort is the default port of the block managers on Kubernetes.
+	DefaultSparkBlockManagerPort int32 = 7079
 )
 
 // https://spark.apache.org/docs/latest/configuration.html
@@ -0,0 +1,23 @@
+/*
+Copyright 2024 The Kubeflow authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package naming generates the names of the resources created for Spark operator objects.
+//
+// Names are deterministic, so the resources of a SparkApplication keep their names across submission attempts
+// and are found again for clean-up, and are kept within the length limits of the resources. Names exceeding a
+// limit are truncated and disambiguated with a hash of the full name, so that long names of different objects
+// sharing a common prefix do not collide.
+package naming
@@ -0,0 +1,178 @@
+/*
+Copyright 2024 The Kubeflow authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package naming
+
+import (
+	"crypto/md5"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/kubeflow/spark-operator/api/v1beta2"
+	"github.com/kubeflow/spark-operator/pkg/common"
+)
+
+const (
+	// MaxDNSLabelLength is the maximum length of names used as DNS labels, e.g. names of Services and label values.
+	MaxDNSLabelLength = 63
+
+	// MaxDNSSubdomainLength is the maximum length of names of most resources, e.g. Pods and ConfigMaps.
+	MaxDNSSubdomainLength = 253
+
+	// hashLength is the number of hexadecimal characters of the hash inserted into truncated names.
+	hashLength = 8
+)
+
+// Generate returns the name <base>-<suffix> if it is at most maxLength characters long. Otherwise, base is
+// truncated and followed by a hash of the full name, i.e. <truncated-base>-<hash>-<suffix>.
+func Generate(base string, suffix string, maxLength int) string {
+	name := fmt.Sprintf("%s-%s", base, suffix)
+	if len(name) <= maxLength {
+		return name
+	}
+
+	hash := fmt.Sprintf("%x", md5.Sum([]byte(name)))[:hashLength]
+	maxBaseLength := maxLength - len(suffix) - hashLength - 2
+	if maxBaseLength <= 0 {
+		return fmt.Sprintf("%s-%s", hash, suffix)[:maxLength]
+	}
+	truncated := strings.TrimRight(base[:maxBaseLength], ".")
+	return fmt.Sprintf("%s-%s-%s", truncated, hash, suffix)
+}
+
+// DriverPodName returns the name of the driver pod of the SparkApplication.
+func DriverPodName(app *v1beta2.SparkApplication) string {
+	if name := app.Spec.Driver.PodName; name != nil && len(*name) > 0 {
+		return *name
+	}
+	if name := app.Spec.SparkConf[common.SparkKubernetesDriverPodName]; name != "" {
+		return name
+	}
+	return Generate(app.Name, "driver", MaxDNSSubdomainLength)
+}
+
+// ScheduledRunName returns the name of the SparkApplication created by the ScheduledSparkApplication for the run
+// scheduled at the given time.
+func ScheduledRunName(scheduledApp *v1beta2.ScheduledSparkApplication, runTime time.Time) string {
+	return Generate(scheduledApp.Name, runTime.UTC().Format(common.ScheduledRunTimeFormat), MaxDNSSubdomainLength)
+}
+
+// UIServiceName returns the name of the Service exposing the web UI of the SparkApplication.
+func UIServiceName(app *v1beta2.SparkApplication) string {
+	return Generate(app.Name, "ui-svc", MaxDNSLabelLength)
+}
+
+// UIIngressName returns the name of the Ingress exposing the web UI of the SparkApplication.
+func UIIngressName(app *v1beta2.SparkApplication) string {
+	return Generate(app.Name, "ui-ingress", MaxDNSLabelLength)
+}
+
+// DriverIngressServiceName returns the name of the Service exposing the given driver port of the SparkApplication.
+func DriverIngressServiceName(app *v1beta2.SparkApplication, port int32) string {
+	return Generate(app.Name, fmt.Sprintf("driver-%d", port), MaxDNSLabelLength)
+}
+
+// DriverIngressName returns the name of the Ingress exposing the given driver port of the SparkApplication.
+func DriverIngressName(app *v1beta2.SparkApplication, port int32) string {
+	return Generate(app.Name, fmt.Sprintf("ing-%d", port), MaxDNSLabelLength)
+}
+
+// PrometheusConfigMapName returns the name of the ConfigMap holding the Prometheus configuration of the SparkApplication.
+func PrometheusConfigMapName(app *v1beta2.SparkApplication) string {
+	return Generate(app.Name, common.PrometheusConfigMapNameSuffix, MaxDNSSubdomainLength)
+}
+
+// MainApplicationSourceConfigMapName returns the name of the ConfigMap holding the inline source code of the main
+// application of the SparkApplication.
+func MainApplicationSourceConfigMapName(app *v1beta2.SparkApplication) string {
+	return Generate(app.Name, common.MainApplicationSourceConfigMapNameSuffix, MaxDNSSubdomainLength)
+}
+
+// ConfPropertiesConfigMapName returns the name of the ConfigMap holding the SparkConf and HadoopConf properties of
+// the SparkApplication.
+func ConfPropertiesConfigMapName(app *v1beta2.SparkApplication) string {
+	return Generate(app.Name, common.ConfPropertiesConfigMapNameSuffix, MaxDNSSubdomainLength)
+}
+
+// SparkAuthSecretName returns the name of the Secret holding the generated authentication secret of the SparkApplication.
+func SparkAuthSecretName(app *v1beta2.SparkApplication) string {
+	return Generate(app.Name, "spark-auth", MaxDNSLabelLength)
+}
+
+// UIAuthSecretName returns the name of the Secret holding the htpasswd file for basic authentication of the web UI of
+// the SparkApplication.
+func UIAuthSecretName(app *v1beta2.SparkApplication) string {
+	return Generate(app.Name, "ui-auth", MaxDNSLabelLength)
+}
+
+// DriverPVCRBACName returns the name of the Role and RoleBinding granting the driver access to persistent volume claims.
+func DriverPVCRBACName(app *v1beta2.SparkApplication) string {
+	return Generate(app.Name, "driver-pvc", MaxDNSLabelLength)
+}
+
+// ExecutorPDBName returns the name of the PodDisruptionBudget of the executors of the SparkApplication.
+func ExecutorPDBName(app *v1beta2.SparkApplication) string {
+	return Generate(app.Name, "executor-pdb", MaxDNSSubdomainLength)
+}
+
+// ImagePrefetchName returns the name of the DaemonSet prefetching the executor image of the SparkApplication.
+func ImagePrefetchName(app *v1beta2.SparkApplication) string {
+	return Generate(app.Name, "image-prefetch", MaxDNSLabelLength)
+}
+
+// DriverMetricsServiceName returns the name of the Service exposing the metrics port of the driver of the
+// SparkApplication.
+func DriverMetricsServiceName(app *v1beta2.SparkApplication) string {
+	return Generate(app.Name, "driver-metrics", MaxDNSLabelLength)
+}
+
+// PostRunSummaryName returns the default name of the ConfigMap summarizing a run of the SparkApplication.
+func PostRunSummaryName(app *v1beta2.SparkApplication) string {
+	return Generate(app.Name, "summary", MaxDNSSubdomainLength)
+}
+
+// ResourceReservationName returns the name of the ResourceQuota recording the resources reserved for the
+// SparkApplication.
+func ResourceReservationName(app *v1beta2.SparkApplication) string {
+	return Generate(app.Name, "reservation", MaxDNSSubdomainLength)
+}
+
+// PodGroupName returns the name of the PodGroup gang scheduling the pods of the SparkApplication. The prefix
+// distinguishes PodGroups of different schedulers.
+func PodGroupName(prefix string, app *v1beta2.SparkApplication) string {
+	base := app.Name
+	if prefix != "" {
+		base = fmt.Sprintf("%s-%s", prefix, app.Name)
+	}
+	return Generate(base, "pg", MaxDNSSubdomainLength)
+}
+
+// GroupMemberApplicationName returns the name of the SparkApplication created for a member of a SparkApplicationGroup.
+// The name is used as a label value, so it is kept within the length of a DNS label.
+func GroupMemberApplicationName(group *v1beta2.SparkApplicationGroup, member string) string {
+	return Generate(group.Name, member, MaxDNSLabelLength)
+}
+
+// SubmitterJobName returns the name of the Job running spark-submit for the current submission of the SparkApplication.
+// The name is used as a label value of the pods of the Job, so it is kept within the length of a DNS label.
+func SubmitterJobName(app *v1beta2.SparkApplication) string {
+	suffix := "submit"
+	if id := app.Status.SubmissionID; id != "" {
+		suffix = fmt.Sprintf("submit-%s", id[:min(len(id), hashLength)])
+	}
+	return Generate(app.Name, suffix, MaxDNSLabelLength)
+}
@@ -0,0 +1,128 @@
+/*
+Copyright 2024 The Kubeflow authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package naming_test
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/validation"
+
+	"github.com/kubeflow/spark-operator/api/v1beta2"
+	"github.com/kubeflow/spark-operator/pkg/naming"
+)
+
+func TestGenerate(t *testing.T) {
+	assert.Equal(t, "test-app-ui-svc", naming.Generate("test-app", "ui-svc", naming.MaxDNSLabelLength))
+
+	longName1 := strings.Repeat("a", 70) + "-one"
+	longName2 := strings.Repeat("a", 70) + "-two"
+	name1 := naming.Generate(longName1, "ui-svc", naming.MaxDNSLabelLength)
+	name2 := naming.Generate(longName2, "ui-svc", naming.MaxDNSLabelLength)
+	assert.LessOrEqual(t, len(name1), naming.MaxDNSLabelLength)
+	assert.True(t, strings.HasSuffix(name1, "-ui-svc"))
+	assert.Empty(t, validation.IsDNS1035Label(name1))
+	assert.NotEqual(t, name1, name2, "long names sharing a prefix must not collide")
+	assert.Equal(t, name1, naming.Generate(longName1, "ui-svc", naming.MaxDNSLabelLength), "names must be deterministic")
+
+	assert.Len(t, naming.Generate("test-app", strings.Repeat("s", 70), naming.MaxDNSLabelLength), naming.MaxDNSLabelLength)
+}
+
+func TestResourceNames(t *testing.T) {
+	app := &v1beta2.SparkApplication{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: strings.Repeat("long-app-name-", 5),
+		},
+		Status: v1beta2.SparkApplicationStatus{
+			SubmissionID: "6f1d4e3c-2b8a-4d6e-9f0a-1c2b3d4e5f60",
+		},
+	}
+	labelNames := []string{
+		naming.UIServiceName(app),
+		naming.UIIngressName(app),
+		naming.DriverIngressServiceName(app, 4040),
+		naming.DriverIngressName(app, 4040),
+		naming.SparkAuthSecretName(app),
+		naming.UIAuthSecretName(app),
+		naming.DriverPVCRBACName(app),
+		naming.ImagePrefetchName(app),
+		naming.DriverMetricsServiceName(app),
+		naming.SubmitterJobName(app),
+	}
+	for _, name := range labelNames {
+		assert.Empty(t, validation.IsDNS1123Label(name), name)
+	}
+	assert.Len(t, dedupe(labelNames), len(labelNames))
+
+	subdomainNames := []string{
+		naming.DriverPodName(app),
+		naming.PrometheusConfigMapName(app),
+		naming.MainApplicationSourceConfigMapName(app),
+		naming.ConfPropertiesConfigMapName(app),
+		naming.PodGroupName("spark", app),
+		naming.ExecutorPDBName(app),
+		naming.PostRunSummaryName(app),
+	}
+	for _, name := range subdomainNames {
+		assert.Empty(t, validation.IsDNS1123Subdomain(name), name)
+	}
+}
+
+func TestDriverPodName(t *testing.T) {
+	app := &v1beta2.SparkApplication{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: "test-app",
+		},
+	}
+	assert.Equal(t, "test-app-driver", naming.DriverPodName(app))
+
+	app.Spec.SparkConf = map[string]string{"spark.kubernetes.driver.pod.name": "conf-driver"}
+	assert.Equal(t, "conf-driver", naming.DriverPodName(app))
+
+	podName := "spec-driver"
+	app.Spec.Driver.PodName = &podName
+	assert.Equal(t, "spec-driver", naming.DriverPodName(app))
+}
+
+func dedupe(names []string) map[string]struct{} {
+	set := make(map[string]struct{})
+	for _, name := range names {
+		set[name] = struct{}{}
+	}
+	return set
+}
+
+func TestScheduledRunName(t *testing.T) {
+	scheduledApp := &v1beta2.ScheduledSparkApplication{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: "test-app",
+		},
+	}
+	runTime := time.Date(2024, time.March, 5, 7, 30, 0, 0, time.FixedZone("UTC+2", 2*60*60))
+	assert.Equal(t, "test-app-20240305-053000", naming.ScheduledRunName(scheduledApp, runTime))
+
+	earlier := naming.ScheduledRunName(scheduledApp, runTime.Add(-time.Hour))
+	assert.Less(t, earlier, naming.ScheduledRunName(scheduledApp, runTime))
+
+	scheduledApp.Name = strings.Repeat("long-app-name-", 20)
+	name := naming.ScheduledRunName(scheduledApp, runTime)
+	assert.Empty(t, validation.IsDNS1123Subdomain(name))
+	assert.True(t, strings.HasSuffix(name, "-20240305-053000"))
+}
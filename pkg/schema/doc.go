@@ -0,0 +1,23 @@
+/*
+Copyright 2024 The Kubeflow authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package schema provides the OpenAPI schemas of the CustomResourceDefinitions of the Spark operator, which are
+// embedded in the operator binaries so that they always match the installed operator version.
+//
+// The schemas are served by the query server of the operator under SchemasPath, e.g.
+// /api/v1/schemas/sparkoperator.k8s.io/v1beta2/SparkApplication.json, and used by sparkctl to validate
+// SparkApplication manifests offline.
+package schema
@@ -0,0 +1,243 @@
+/*
+Copyright 2024 The Kubeflow authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package schema
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/fs"
+	"net/http"
+	"os"
+	"path"
+	"sort"
+	"strings"
+
+	apiextensions "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions"
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	structuralschema "k8s.io/apiextensions-apiserver/pkg/apiserver/schema"
+	"k8s.io/apiextensions-apiserver/pkg/apiserver/schema/pruning"
+	"k8s.io/apiextensions-apiserver/pkg/apiserver/validation"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	"sigs.k8s.io/yaml"
+
+	sparkoperator "github.com/kubeflow/spark-operator"
+)
+
+const (
+	// SchemasPath is the path of the REST API endpoint serving the schemas.
+	SchemasPath = "/api/v1/schemas"
+)
+
+// Schema is the OpenAPI schema of a version of a CustomResourceDefinition.
+type Schema struct {
+	// Group is the API group of the custom resource.
+	Group string `json:"group"`
+	// Version is the API version of the custom resource.
+	Version string `json:"version"`
+	// Kind is the kind of the custom resource.
+	Kind string `json:"kind"`
+	// Served is whether the version is served by the API server.
+	Served bool `json:"served"`
+	// Storage is whether the version is the storage version.
+	Storage bool `json:"storage"`
+	// OpenAPIV3Schema is the schema including validation rules, enums and bounds.
+	OpenAPIV3Schema *apiextensionsv1.JSONSchemaProps `json:"openAPIV3Schema,omitempty"`
+}
+
+// GroupVersionKind returns the group, version and kind of the schema.
+func (s *Schema) GroupVersionKind() schema.GroupVersionKind {
+	return schema.GroupVersionKind{Group: s.Group, Version: s.Version, Kind: s.Kind}
+}
+
+// Path returns the path of the schema relative to SchemasPath.
+func (s *Schema) Path() string {
+	return path.Join(s.Group, s.Version, s.Kind+".json")
+}
+
+// LoadCRDs returns the CustomResourceDefinitions of the Spark operator.
+func LoadCRDs() ([]*apiextensionsv1.CustomResourceDefinition, error) {
+	return loadCRDs(sparkoperator.CRDs, "config/crd/bases/*.yaml")
+}
+
+// LoadCRDsFromDir returns the CustomResourceDefinitions in the YAML files of the given directory, e.g., the
+// crds directory of a previous version of the Helm chart.
+func LoadCRDsFromDir(dir string) ([]*apiextensionsv1.CustomResourceDefinition, error) {
+	return loadCRDs(os.DirFS(dir), "*.yaml")
+}
+
+func loadCRDs(fsys fs.FS, pattern string) ([]*apiextensionsv1.CustomResourceDefinition, error) {
+	paths, err := fs.Glob(fsys, pattern)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list CustomResourceDefinitions: %v", err)
+	}
+
+	crds := make([]*apiextensionsv1.CustomResourceDefinition, 0, len(paths))
+	for _, path := range paths {
+		data, err := fs.ReadFile(fsys, path)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read %s: %v", path, err)
+		}
+		crd := &apiextensionsv1.CustomResourceDefinition{}
+		if err := yaml.Unmarshal(data, crd); err != nil {
+			return nil, fmt.Errorf("failed to unmarshal %s: %v", path, err)
+		}
+		crds = append(crds, crd)
+	}
+	return crds, nil
+}
+
+// List returns the schemas of all versions of the CustomResourceDefinitions, sorted by group, kind and version.
+func List() ([]*Schema, error) {
+	crds, err := LoadCRDs()
+	if err != nil {
+		return nil, err
+	}
+
+	var schemas []*Schema
+	for _, crd := range crds {
+		for _, version := range crd.Spec.Versions {
+			s := &Schema{
+				Group:   crd.Spec.Group,
+				Version: version.Name,
+				Kind:    crd.Spec.Names.Kind,
+				Served:  version.Served,
+				Storage: version.Storage,
+			}
+			if version.Schema != nil {
+				s.OpenAPIV3Schema = version.Schema.OpenAPIV3Schema
+			}
+			schemas = append(schemas, s)
+		}
+	}
+
+	sort.Slice(schemas, func(i, j int) bool {
+		return schemas[i].Path() < schemas[j].Path()
+	})
+	return schemas, nil
+}
+
+// Get returns the schema of the given group, version and kind.
+func Get(gvk schema.GroupVersionKind) (*Schema, error) {
+	schemas, err := List()
+	if err != nil {
+		return nil, err
+	}
+	for _, s := range schemas {
+		if s.GroupVersionKind() == gvk {
+			return s, nil
+		}
+	}
+	return nil, fmt.Errorf("no schema found for %s", gvk)
+}
+
+// Validate validates the given object against the schema of its group, version and kind.
+func Validate(obj *unstructured.Unstructured) (field.ErrorList, error) {
+	s, err := Get(obj.GroupVersionKind())
+	if err != nil {
+		return nil, err
+	}
+	return ValidateWithSchema(s.OpenAPIV3Schema, obj)
+}
+
+// ValidateWithSchema validates the given object against the given OpenAPI schema, e.g., the schema of a
+// CustomResourceDefinition installed in the cluster rather than the embedded one.
+func ValidateWithSchema(openAPIV3Schema *apiextensionsv1.JSONSchemaProps, obj *unstructured.Unstructured) (field.ErrorList, error) {
+	if openAPIV3Schema == nil {
+		return nil, nil
+	}
+
+	props, err := convert(openAPIV3Schema)
+	if err != nil {
+		return nil, fmt.Errorf("failed to convert schema of %s: %v", obj.GroupVersionKind(), err)
+	}
+	validator, _, err := validation.NewSchemaValidator(props)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create validator for %s: %v", obj.GroupVersionKind(), err)
+	}
+	return validation.ValidateCustomResource(nil, obj.UnstructuredContent(), validator), nil
+}
+
+// UnknownFields returns the paths of the fields of the given object not specified in the given OpenAPI schema,
+// i.e., the fields the API server would prune when storing the object.
+func UnknownFields(openAPIV3Schema *apiextensionsv1.JSONSchemaProps, obj *unstructured.Unstructured) ([]string, error) {
+	if openAPIV3Schema == nil {
+		return nil, nil
+	}
+
+	props, err := convert(openAPIV3Schema)
+	if err != nil {
+		return nil, fmt.Errorf("failed to convert schema of %s: %v", obj.GroupVersionKind(), err)
+	}
+	structural, err := structuralschema.NewStructural(props)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create structural schema of %s: %v", obj.GroupVersionKind(), err)
+	}
+	opts := structuralschema.UnknownFieldPathOptions{TrackUnknownFieldPaths: true}
+	return pruning.PruneWithOptions(obj.DeepCopy().UnstructuredContent(), structural, true, opts), nil
+}
+
+func convert(openAPIV3Schema *apiextensionsv1.JSONSchemaProps) (*apiextensions.JSONSchemaProps, error) {
+	props := &apiextensions.JSONSchemaProps{}
+	if err := apiextensionsv1.Convert_v1_JSONSchemaProps_To_apiextensions_JSONSchemaProps(openAPIV3Schema, props, nil); err != nil {
+		return nil, err
+	}
+	return props, nil
+}
+
+// Handler returns the HTTP handler serving the index of the schemas at SchemasPath and every schema at
+// SchemasPath/<group>/<version>/<kind>.json.
+func Handler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet {
+			http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+
+		schemas, err := List()
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+
+		name := strings.Trim(strings.TrimPrefix(r.URL.Path, SchemasPath), "/")
+		if name == "" {
+			index := make([]Schema, 0, len(schemas))
+			for _, s := range schemas {
+				index = append(index, Schema{Group: s.Group, Version: s.Version, Kind: s.Kind, Served: s.Served, Storage: s.Storage})
+			}
+			writeJSON(w, index)
+			return
+		}
+
+		for _, s := range schemas {
+			if s.Path() == name {
+				writeJSON(w, s.OpenAPIV3Schema)
+				return
+			}
+		}
+		http.NotFound(w, r)
+	})
+}
+
+func writeJSON(w http.ResponseWriter, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(v); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+	}
+}
@@ -0,0 +1,117 @@
+/*
+Copyright 2024 The Kubeflow authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package schema_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"sigs.k8s.io/yaml"
+
+	"github.com/kubeflow/spark-operator/api/v1beta2"
+	"github.com/kubeflow/spark-operator/pkg/schema"
+)
+
+func TestGet(t *testing.T) {
+	s, err := schema.Get(v1beta2.SchemeGroupVersion.WithKind("SparkApplication"))
+	require.NoError(t, err)
+	assert.True(t, s.Storage)
+	require.NotNil(t, s.OpenAPIV3Schema)
+	assert.Contains(t, s.OpenAPIV3Schema.Properties, "spec")
+
+	_, err = schema.Get(v1beta2.SchemeGroupVersion.WithKind("Unknown"))
+	assert.Error(t, err)
+}
+
+func TestValidate(t *testing.T) {
+	valid := `
+apiVersion: sparkoperator.k8s.io/v1beta2
+kind: SparkApplication
+metadata:
+  name: spark-pi
+spec:
+  type: Scala
+  mode: cluster
+  sparkVersion: 3.5.3
+  mainApplicationFile: local:///opt/spark/examples/jars/spark-examples.jar
+  driver: {}
+  executor: {}
+`
+	obj := &unstructured.Unstructured{}
+	require.NoError(t, yaml.Unmarshal([]byte(valid), &obj.Object))
+	errs, err := schema.Validate(obj)
+	require.NoError(t, err)
+	assert.Empty(t, errs)
+
+	require.NoError(t, unstructured.SetNestedField(obj.Object, "Go", "spec", "type"))
+	errs, err = schema.Validate(obj)
+	require.NoError(t, err)
+	require.Len(t, errs, 1)
+	assert.Equal(t, "spec.type", errs[0].Field)
+}
+
+func TestHandler(t *testing.T) {
+	handler := schema.Handler()
+
+	recorder := httptest.NewRecorder()
+	handler.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, schema.SchemasPath, nil))
+	require.Equal(t, http.StatusOK, recorder.Code)
+	var index []schema.Schema
+	require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &index))
+	assert.NotEmpty(t, index)
+
+	recorder = httptest.NewRecorder()
+	handler.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, schema.SchemasPath+"/sparkoperator.k8s.io/v1beta2/SparkApplication.json", nil))
+	require.Equal(t, http.StatusOK, recorder.Code)
+	assert.Contains(t, recorder.Body.String(), `"spec"`)
+
+	recorder = httptest.NewRecorder()
+	handler.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, schema.SchemasPath+"/sparkoperator.k8s.io/v1beta2/Unknown.json", nil))
+	assert.Equal(t, http.StatusNotFound, recorder.Code)
+}
+
+func TestLoadCRDsFromDir(t *testing.T) {
+	crds, err := schema.LoadCRDsFromDir("../../charts/spark-operator-chart/crds")
+	require.NoError(t, err)
+	embedded, err := schema.LoadCRDs()
+	require.NoError(t, err)
+	assert.Len(t, crds, len(embedded))
+}
+
+func TestUnknownFields(t *testing.T) {
+	s, err := schema.Get(v1beta2.SchemeGroupVersion.WithKind("SparkApplication"))
+	require.NoError(t, err)
+
+	obj := &unstructured.Unstructured{Object: map[string]interface{}{
+		"apiVersion": "sparkoperator.k8s.io/v1beta2",
+		"kind":       "SparkApplication",
+		"metadata":   map[string]interface{}{"name": "spark-pi"},
+		"spec": map[string]interface{}{
+			"type":    "Scala",
+			"unknown": true,
+		},
+	}}
+	fields, err := schema.UnknownFields(s.OpenAPIV3Schema, obj)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"spec.unknown"}, fields)
+	assert.Contains(t, obj.Object["spec"], "unknown")
+}
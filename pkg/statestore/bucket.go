@@ -0,0 +1,88 @@
+/*
+Copyright 2024 The Kubeflow authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package statestore
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+
+	"gocloud.dev/blob"
+	"gocloud.dev/gcerrors"
+)
+
+// bucketStore stores every record as an object of a bucket.
+type bucketStore struct {
+	bucket *blob.Bucket
+}
+
+var _ Store = &bucketStore{}
+
+// NewBucketStore creates a state store backed by the given bucket.
+func NewBucketStore(bucket *blob.Bucket) Store {
+	return &bucketStore{bucket: bucket}
+}
+
+// List implements Store.
+func (s *bucketStore) List(ctx context.Context) (map[string]string, error) {
+	records := make(map[string]string)
+	iter := s.bucket.List(nil)
+	for {
+		obj, err := iter.Next(ctx)
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to list records: %v", err)
+		}
+		if obj.IsDir {
+			continue
+		}
+		data, err := s.bucket.ReadAll(ctx, obj.Key)
+		if err != nil {
+			// The record may have been deleted since it was listed.
+			if gcerrors.Code(err) == gcerrors.NotFound {
+				continue
+			}
+			return nil, fmt.Errorf("failed to read record %s: %v", obj.Key, err)
+		}
+		records[obj.Key] = string(data)
+	}
+	return records, nil
+}
+
+// Put implements Store.
+func (s *bucketStore) Put(ctx context.Context, key string, value string) error {
+	if err := s.bucket.WriteAll(ctx, key, []byte(value), nil); err != nil {
+		return fmt.Errorf("failed to write record %s: %v", key, err)
+	}
+	return nil
+}
+
+// Delete implements Store.
+func (s *bucketStore) Delete(ctx context.Context, key string) error {
+	if err := s.bucket.Delete(ctx, key); err != nil && gcerrors.Code(err) != gcerrors.NotFound {
+		return fmt.Errorf("failed to delete record %s: %v", key, err)
+	}
+	return nil
+}
+
+// Close implements Store.
+func (s *bucketStore) Close() error {
+	return s.bucket.Close()
+}
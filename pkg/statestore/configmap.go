@@ -0,0 +1,132 @@
+/*
+Copyright 2024 The Kubeflow authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package statestore
+
+import (
+	"context"
+	"fmt"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/util/retry"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// configMapStore stores the records as the data of a ConfigMap, which is created on the first write.
+type configMapStore struct {
+	client client.Client
+	reader client.Reader
+	key    types.NamespacedName
+}
+
+var _ Store = &configMapStore{}
+
+// NewConfigMapStore creates a state store backed by the ConfigMap with the given namespace and name. The ConfigMap is
+// written with the given client and read with the given reader, which should not be a cache to see the latest writes.
+func NewConfigMapStore(c client.Client, reader client.Reader, namespace string, name string) Store {
+	return &configMapStore{
+		client: c,
+		reader: reader,
+		key:    types.NamespacedName{Namespace: namespace, Name: name},
+	}
+}
+
+// List implements Store.
+func (s *configMapStore) List(ctx context.Context) (map[string]string, error) {
+	configMap := &corev1.ConfigMap{}
+	if err := s.reader.Get(ctx, s.key, configMap); err != nil {
+		if errors.IsNotFound(err) {
+			return map[string]string{}, nil
+		}
+		return nil, fmt.Errorf("failed to get ConfigMap %s: %v", s.key, err)
+	}
+
+	records := make(map[string]string, len(configMap.Data))
+	for key, value := range configMap.Data {
+		records[key] = value
+	}
+	return records, nil
+}
+
+// Put implements Store.
+func (s *configMapStore) Put(ctx context.Context, key string, value string) error {
+	return s.update(ctx, func(data map[string]string) bool {
+		if current, ok := data[key]; ok && current == value {
+			return false
+		}
+		data[key] = value
+		return true
+	})
+}
+
+// Delete implements Store.
+func (s *configMapStore) Delete(ctx context.Context, key string) error {
+	return s.update(ctx, func(data map[string]string) bool {
+		if _, ok := data[key]; !ok {
+			return false
+		}
+		delete(data, key)
+		return true
+	})
+}
+
+// Close implements Store.
+func (s *configMapStore) Close() error {
+	return nil
+}
+
+// update applies the given mutation to the data of the ConfigMap, creating the ConfigMap if it does not exist.
+// The mutation returns whether it changed the data.
+func (s *configMapStore) update(ctx context.Context, mutate func(data map[string]string) bool) error {
+	return retry.RetryOnConflict(retry.DefaultRetry, func() error {
+		configMap := &corev1.ConfigMap{}
+		if err := s.reader.Get(ctx, s.key, configMap); err != nil {
+			if !errors.IsNotFound(err) {
+				return fmt.Errorf("failed to get ConfigMap %s: %v", s.key, err)
+			}
+
+			configMap = &corev1.ConfigMap{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      s.key.Name,
+					Namespace: s.key.Namespace,
+				},
+				Data: map[string]string{},
+			}
+			if !mutate(configMap.Data) {
+				return nil
+			}
+			if err := s.client.Create(ctx, configMap); err != nil {
+				// Another writer created the ConfigMap in the meantime, retry with an update.
+				if errors.IsAlreadyExists(err) {
+					return errors.NewConflict(corev1.Resource("configmaps"), s.key.Name, err)
+				}
+				return fmt.Errorf("failed to create ConfigMap %s: %v", s.key, err)
+			}
+			return nil
+		}
+
+		if configMap.Data == nil {
+			configMap.Data = map[string]string{}
+		}
+		if !mutate(configMap.Data) {
+			return nil
+		}
+		return s.client.Update(ctx, configMap)
+	})
+}
@@ -0,0 +1,67 @@
+/*
+Copyright 2024 The Kubeflow authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package statestore
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"gocloud.dev/blob"
+	// Register the bucket URL schemes supported by the state store.
+	_ "gocloud.dev/blob/fileblob"
+	_ "gocloud.dev/blob/gcsblob"
+	_ "gocloud.dev/blob/memblob"
+	_ "gocloud.dev/blob/s3blob"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// ConfigMapScheme is the URL scheme of state stores backed by a ConfigMap, in the form of
+// `configmap://<namespace>/<name>`.
+const ConfigMapScheme = "configmap://"
+
+// Store is a key-value store of operator bookkeeping records. Keys consist of alphanumeric characters, `-`, `_`
+// and `.`.
+type Store interface {
+	// List returns all the records of the store.
+	List(ctx context.Context) (map[string]string, error)
+	// Put creates or replaces the record with the given key.
+	Put(ctx context.Context, key string, value string) error
+	// Delete removes the record with the given key, if any.
+	Delete(ctx context.Context, key string) error
+	// Close releases the resources held by the store.
+	Close() error
+}
+
+// Open opens the state store located at the given URL, which is either `configmap://<namespace>/<name>` or a bucket
+// URL, e.g. s3://bucket?region=us-west-1&prefix=state/. ConfigMaps are written with the given client and read with
+// the given reader.
+func Open(ctx context.Context, url string, c client.Client, reader client.Reader) (Store, error) {
+	if strings.HasPrefix(url, ConfigMapScheme) {
+		namespace, name, found := strings.Cut(strings.TrimPrefix(url, ConfigMapScheme), "/")
+		if !found || namespace == "" || name == "" || strings.Contains(name, "/") {
+			return nil, fmt.Errorf("state store URL %q must be in the form of %s<namespace>/<name>", url, ConfigMapScheme)
+		}
+		return NewConfigMapStore(c, reader, namespace, name), nil
+	}
+
+	bucket, err := blob.OpenBucket(ctx, url)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open bucket %s: %v", url, err)
+	}
+	return NewBucketStore(bucket), nil
+}
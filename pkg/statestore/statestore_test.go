@@ -0,0 +1,78 @@
+/*
+Copyright 2024 The Kubeflow authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package statestore_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gocloud.dev/blob/memblob"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	"github.com/kubeflow/spark-operator/pkg/statestore"
+)
+
+func testStore(t *testing.T, store statestore.Store) {
+	ctx := context.Background()
+	defer store.Close()
+
+	records, err := store.List(ctx)
+	require.NoError(t, err)
+	assert.Empty(t, records)
+
+	require.NoError(t, store.Put(ctx, "queued_default_spark-pi", "2024-06-03T10:00:00Z"))
+	require.NoError(t, store.Put(ctx, "inflight_default_spark-wordcount", "2024-06-03T10:01:00Z"))
+	require.NoError(t, store.Put(ctx, "queued_default_spark-pi", "2024-06-03T10:02:00Z"))
+	records, err = store.List(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, map[string]string{
+		"queued_default_spark-pi":          "2024-06-03T10:02:00Z",
+		"inflight_default_spark-wordcount": "2024-06-03T10:01:00Z",
+	}, records)
+
+	require.NoError(t, store.Delete(ctx, "queued_default_spark-pi"))
+	require.NoError(t, store.Delete(ctx, "queued_default_spark-pi"))
+	records, err = store.List(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, map[string]string{"inflight_default_spark-wordcount": "2024-06-03T10:01:00Z"}, records)
+}
+
+func TestBucketStore(t *testing.T) {
+	testStore(t, statestore.NewBucketStore(memblob.OpenBucket(nil)))
+}
+
+func TestConfigMapStore(t *testing.T) {
+	c := fake.NewClientBuilder().Build()
+	testStore(t, statestore.NewConfigMapStore(c, c, "spark-operator", "spark-operator-state"))
+}
+
+func TestOpen(t *testing.T) {
+	c := fake.NewClientBuilder().Build()
+
+	store, err := statestore.Open(context.Background(), "configmap://spark-operator/spark-operator-state", c, c)
+	require.NoError(t, err)
+	require.NoError(t, store.Close())
+
+	store, err = statestore.Open(context.Background(), "mem://", c, c)
+	require.NoError(t, err)
+	require.NoError(t, store.Close())
+
+	_, err = statestore.Open(context.Background(), "configmap://spark-operator-state", c, c)
+	assert.Error(t, err)
+}
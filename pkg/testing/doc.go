@@ -0,0 +1,34 @@
+/*
+Copyright 2024 The Kubeflow authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package testing provides an integration test harness for automation built on the Spark operator APIs.
+//
+// The harness starts a local control plane with envtest, installs the CustomResourceDefinitions of the
+// operator and optionally runs a lightweight reconciler stub which moves SparkApplications through their
+// lifecycle without running Spark, so that the automation can be tested without a real cluster:
+//
+//	env, err := testing.NewEnvironment(testing.Options{EnableReconcilerStub: true})
+//	if err != nil {
+//		return err
+//	}
+//	if err := env.Start(ctx); err != nil {
+//		return err
+//	}
+//	defer env.Stop()
+//
+// The envtest binaries are looked up in Options.BinaryAssetsDirectory or the directory given by the
+// KUBEBUILDER_ASSETS environment variable, which can be set up with `setup-envtest use -p path`.
+package testing
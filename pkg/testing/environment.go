@@ -0,0 +1,150 @@
+/*
+Copyright 2024 The Kubeflow authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package testing
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"k8s.io/apimachinery/pkg/runtime"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/rest"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/envtest"
+	metricsserver "sigs.k8s.io/controller-runtime/pkg/metrics/server"
+
+	"github.com/kubeflow/spark-operator/api/v1beta1"
+	"github.com/kubeflow/spark-operator/api/v1beta2"
+)
+
+// Options configures the test environment.
+type Options struct {
+	// BinaryAssetsDirectory is the directory containing the envtest binaries. Defaults to the directory given by
+	// the KUBEBUILDER_ASSETS environment variable.
+	BinaryAssetsDirectory string
+
+	// EnableReconcilerStub enables running a reconciler stub which moves SparkApplications through their lifecycle.
+	EnableReconcilerStub bool
+
+	// Stub configures the reconciler stub.
+	Stub StubOptions
+}
+
+// Environment is a local control plane with the CustomResourceDefinitions of the Spark operator installed.
+type Environment struct {
+	// Config is the configuration to connect to the control plane. It is set once the environment is started.
+	Config *rest.Config
+	// Client is a client of the control plane. It is set once the environment is started.
+	Client client.Client
+	// Scheme is the scheme containing the Kubernetes and Spark operator types.
+	Scheme *runtime.Scheme
+
+	options Options
+	env     *envtest.Environment
+	cancel  context.CancelFunc
+	done    chan error
+}
+
+// NewEnvironment creates a new test environment.
+func NewEnvironment(options Options) (*Environment, error) {
+	scheme := runtime.NewScheme()
+	if err := clientgoscheme.AddToScheme(scheme); err != nil {
+		return nil, err
+	}
+	if err := v1beta1.AddToScheme(scheme); err != nil {
+		return nil, err
+	}
+	if err := v1beta2.AddToScheme(scheme); err != nil {
+		return nil, err
+	}
+
+	crds, err := LoadCRDs()
+	if err != nil {
+		return nil, err
+	}
+
+	return &Environment{
+		Scheme:  scheme,
+		options: options,
+		env: &envtest.Environment{
+			CRDs:                  crds,
+			BinaryAssetsDirectory: options.BinaryAssetsDirectory,
+			Scheme:                scheme,
+		},
+	}, nil
+}
+
+// Start starts the control plane and, if enabled, the reconciler stub.
+func (e *Environment) Start(ctx context.Context) error {
+	cfg, err := e.env.Start()
+	if err != nil {
+		return fmt.Errorf("failed to start test environment: %v", err)
+	}
+	e.Config = cfg
+
+	e.Client, err = client.New(cfg, client.Options{Scheme: e.Scheme})
+	if err != nil {
+		_ = e.env.Stop()
+		return fmt.Errorf("failed to create client: %v", err)
+	}
+
+	if !e.options.EnableReconcilerStub {
+		return nil
+	}
+
+	mgr, err := ctrl.NewManager(cfg, ctrl.Options{
+		Scheme:  e.Scheme,
+		Metrics: metricsserver.Options{BindAddress: "0"},
+	})
+	if err != nil {
+		_ = e.env.Stop()
+		return fmt.Errorf("failed to create manager: %v", err)
+	}
+	if err := NewStubReconciler(mgr.GetClient(), e.options.Stub).SetupWithManager(mgr); err != nil {
+		_ = e.env.Stop()
+		return fmt.Errorf("failed to set up reconciler stub: %v", err)
+	}
+
+	mgrCtx, cancel := context.WithCancel(ctx)
+	e.cancel = cancel
+	e.done = make(chan error, 1)
+	go func() {
+		e.done <- mgr.Start(mgrCtx)
+	}()
+	return nil
+}
+
+// Stop stops the reconciler stub and the control plane.
+func (e *Environment) Stop() error {
+	if e.cancel != nil {
+		e.cancel()
+		select {
+		case err := <-e.done:
+			if err != nil {
+				return fmt.Errorf("failed to run reconciler stub: %v", err)
+			}
+		case <-time.After(30 * time.Second):
+			return fmt.Errorf("timed out waiting for reconciler stub to stop")
+		}
+	}
+	if err := e.env.Stop(); err != nil {
+		return fmt.Errorf("failed to stop test environment: %v", err)
+	}
+	return nil
+}
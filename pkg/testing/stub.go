@@ -0,0 +1,115 @@
+/*
+Copyright 2024 The Kubeflow authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package testing
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/kubeflow/spark-operator/api/v1beta2"
+	"github.com/kubeflow/spark-operator/pkg/util"
+)
+
+const (
+	// AnnotationFinalState is the annotation overriding the final state of a SparkApplication reconciled by the stub,
+	// e.g. FAILED.
+	AnnotationFinalState = "testing.sparkoperator.k8s.io/final-state"
+)
+
+// StubOptions configures the reconciler stub.
+type StubOptions struct {
+	// StepInterval is the time a SparkApplication stays in each state before moving to the next one.
+	StepInterval time.Duration
+
+	// FinalState is the state SparkApplications end in unless overridden by the AnnotationFinalState annotation.
+	// Defaults to COMPLETED.
+	FinalState v1beta2.ApplicationStateType
+}
+
+// StubReconciler moves SparkApplications from their initial state through SUBMITTED and RUNNING to their final state
+// by updating their status, without creating any pods.
+type StubReconciler struct {
+	client  client.Client
+	options StubOptions
+}
+
+// NewStubReconciler creates a new reconciler stub.
+func NewStubReconciler(client client.Client, options StubOptions) *StubReconciler {
+	if options.FinalState == "" {
+		options.FinalState = v1beta2.ApplicationStateCompleted
+	}
+	return &StubReconciler{
+		client:  client,
+		options: options,
+	}
+}
+
+// SetupWithManager sets up the reconciler stub with the manager.
+func (r *StubReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewControllerManagedBy(mgr).
+		Named("spark-application-stub").
+		For(&v1beta2.SparkApplication{}).
+		Complete(r)
+}
+
+// Reconcile implements reconcile.Reconciler.
+func (r *StubReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	app := &v1beta2.SparkApplication{}
+	if err := r.client.Get(ctx, req.NamespacedName, app); err != nil {
+		return ctrl.Result{}, client.IgnoreNotFound(err)
+	}
+	if util.IsTerminated(app) {
+		return ctrl.Result{}, nil
+	}
+
+	now := metav1.Now()
+	switch app.Status.AppState.State {
+	case v1beta2.ApplicationStateNew:
+		app.Status.SubmissionID = uuid.New().String()
+		app.Status.LastSubmissionAttemptTime = now
+		app.Status.SubmissionAttempts++
+		app.Status.ExecutionAttempts++
+		app.Status.DriverInfo.PodName = util.GetDriverPodName(app)
+		app.Status.AppState.State = v1beta2.ApplicationStateSubmitted
+	case v1beta2.ApplicationStateSubmitted:
+		app.Status.AppState.State = v1beta2.ApplicationStateRunning
+	default:
+		app.Status.AppState.State = r.getFinalState(app)
+		app.Status.TerminationTime = now
+	}
+	util.UpdateConditions(app)
+
+	if err := r.client.Status().Update(ctx, app); err != nil {
+		return ctrl.Result{}, err
+	}
+	if util.IsTerminated(app) {
+		return ctrl.Result{}, nil
+	}
+	return ctrl.Result{RequeueAfter: r.options.StepInterval}, nil
+}
+
+func (r *StubReconciler) getFinalState(app *v1beta2.SparkApplication) v1beta2.ApplicationStateType {
+	if state, ok := app.Annotations[AnnotationFinalState]; ok {
+		return v1beta2.ApplicationStateType(state)
+	}
+	return r.options.FinalState
+}
@@ -0,0 +1,102 @@
+/*
+Copyright 2024 The Kubeflow authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package testing_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	"github.com/kubeflow/spark-operator/api/v1beta2"
+	sparktesting "github.com/kubeflow/spark-operator/pkg/testing"
+)
+
+func TestLoadCRDs(t *testing.T) {
+	crds, err := sparktesting.LoadCRDs()
+	require.NoError(t, err)
+
+	var names []string
+	for _, crd := range crds {
+		names = append(names, crd.Name)
+	}
+	assert.Contains(t, names, "sparkapplications.sparkoperator.k8s.io")
+	assert.Contains(t, names, "scheduledsparkapplications.sparkoperator.k8s.io")
+}
+
+func TestStubReconciler(t *testing.T) {
+	scheme := runtime.NewScheme()
+	require.NoError(t, v1beta2.AddToScheme(scheme))
+
+	testCases := []struct {
+		name        string
+		annotations map[string]string
+		finalState  v1beta2.ApplicationStateType
+	}{
+		{
+			name:       "default final state",
+			finalState: v1beta2.ApplicationStateCompleted,
+		},
+		{
+			name:        "final state overridden by annotation",
+			annotations: map[string]string{sparktesting.AnnotationFinalState: string(v1beta2.ApplicationStateFailed)},
+			finalState:  v1beta2.ApplicationStateFailed,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			app := &v1beta2.SparkApplication{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:        "test-app",
+					Namespace:   "default",
+					Annotations: tc.annotations,
+				},
+			}
+			c := fake.NewClientBuilder().
+				WithScheme(scheme).
+				WithObjects(app).
+				WithStatusSubresource(app).
+				Build()
+			r := sparktesting.NewStubReconciler(c, sparktesting.StubOptions{})
+			key := types.NamespacedName{Name: app.Name, Namespace: app.Namespace}
+
+			var states []v1beta2.ApplicationStateType
+			for i := 0; i < 4; i++ {
+				_, err := r.Reconcile(context.Background(), ctrl.Request{NamespacedName: key})
+				require.NoError(t, err)
+				require.NoError(t, c.Get(context.Background(), key, app))
+				states = append(states, app.Status.AppState.State)
+			}
+
+			assert.Equal(t, []v1beta2.ApplicationStateType{
+				v1beta2.ApplicationStateSubmitted,
+				v1beta2.ApplicationStateRunning,
+				tc.finalState,
+				tc.finalState,
+			}, states)
+			assert.NotEmpty(t, app.Status.SubmissionID)
+			assert.False(t, app.Status.TerminationTime.IsZero())
+		})
+	}
+}
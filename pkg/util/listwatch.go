@@ -0,0 +1,107 @@
+/*
+Copyright 2024 The Kubeflow authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package util
+
+import (
+	"time"
+
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/watch"
+	"k8s.io/client-go/tools/cache"
+
+	"github.com/kubeflow/spark-operator/api/v1beta2"
+)
+
+// ListWatch wraps a cache.ListerWatcher to bound the cost of the initial sync of an informer. Lists are
+// requested in pages of PageSize objects from etcd instead of in a single response from the watch cache,
+// and objects rejected by Filter are neither listed nor watched, so they never enter the informer store.
+type ListWatch struct {
+	cache.ListerWatcher
+
+	// PageSize is the maximum number of objects returned by a single list request. Zero disables pagination.
+	PageSize int64
+	// Filter returns whether the given object should be kept. A nil filter keeps all objects.
+	Filter func(obj runtime.Object) bool
+}
+
+// ListWatch implements cache.ListerWatcher.
+var _ cache.ListerWatcher = &ListWatch{}
+
+// List implements cache.Lister.
+func (lw *ListWatch) List(options metav1.ListOptions) (runtime.Object, error) {
+	if lw.PageSize > 0 {
+		// The watch cache ignores the limit for resource version "0", so list from etcd instead.
+		if options.ResourceVersion == "0" && options.Continue == "" {
+			options.ResourceVersion = ""
+		}
+		if options.Limit == 0 || options.Limit > lw.PageSize {
+			options.Limit = lw.PageSize
+		}
+	}
+
+	list, err := lw.ListerWatcher.List(options)
+	if err != nil || lw.Filter == nil {
+		return list, err
+	}
+
+	items, err := meta.ExtractList(list)
+	if err != nil {
+		return nil, err
+	}
+	kept := make([]runtime.Object, 0, len(items))
+	for _, item := range items {
+		if lw.Filter(item) {
+			kept = append(kept, item)
+		}
+	}
+	if len(kept) == len(items) {
+		return list, nil
+	}
+	if err := meta.SetList(list, kept); err != nil {
+		return nil, err
+	}
+	return list, nil
+}
+
+// Watch implements cache.Watcher.
+func (lw *ListWatch) Watch(options metav1.ListOptions) (watch.Interface, error) {
+	w, err := lw.ListerWatcher.Watch(options)
+	if err != nil || lw.Filter == nil {
+		return w, err
+	}
+
+	return watch.Filter(w, func(event watch.Event) (watch.Event, bool) {
+		if event.Type == watch.Added || event.Type == watch.Modified {
+			return event, lw.Filter(event.Object)
+		}
+		return event, true
+	}), nil
+}
+
+// NewTerminatedSparkApplicationFilter returns a filter rejecting the SparkApplications that terminated longer
+// than maxAge ago. Objects of other types are always kept.
+func NewTerminatedSparkApplicationFilter(maxAge time.Duration) func(obj runtime.Object) bool {
+	return func(obj runtime.Object) bool {
+		app, ok := obj.(*v1beta2.SparkApplication)
+		if !ok || !IsTerminated(app) || app.Status.TerminationTime.IsZero() {
+			return true
+		}
+		return time.Since(app.Status.TerminationTime.Time) <= maxAge
+	}
+}
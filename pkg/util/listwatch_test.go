@@ -0,0 +1,102 @@
+/*
+Copyright 2024 The Kubeflow authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package util_test
+
+import (
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/watch"
+	"k8s.io/client-go/tools/cache"
+
+	"github.com/kubeflow/spark-operator/api/v1beta2"
+	"github.com/kubeflow/spark-operator/pkg/util"
+)
+
+var _ = Describe("ListWatch", func() {
+	newApp := func(name string, state v1beta2.ApplicationStateType, terminatedAgo time.Duration) v1beta2.SparkApplication {
+		app := v1beta2.SparkApplication{
+			ObjectMeta: metav1.ObjectMeta{Name: name},
+			Status: v1beta2.SparkApplicationStatus{
+				AppState: v1beta2.ApplicationState{State: state},
+			},
+		}
+		if terminatedAgo > 0 {
+			app.Status.TerminationTime = metav1.NewTime(time.Now().Add(-terminatedAgo))
+		}
+		return app
+	}
+
+	var lastOptions metav1.ListOptions
+	fakeWatcher := watch.NewFake()
+	lw := &util.ListWatch{
+		ListerWatcher: &cache.ListWatch{
+			ListFunc: func(options metav1.ListOptions) (runtime.Object, error) {
+				lastOptions = options
+				return &v1beta2.SparkApplicationList{
+					Items: []v1beta2.SparkApplication{
+						newApp("running", v1beta2.ApplicationStateRunning, 0),
+						newApp("recent", v1beta2.ApplicationStateCompleted, time.Minute),
+						newApp("old", v1beta2.ApplicationStateFailed, 48*time.Hour),
+					},
+				}, nil
+			},
+			WatchFunc: func(_ metav1.ListOptions) (watch.Interface, error) {
+				return fakeWatcher, nil
+			},
+		},
+		PageSize: 100,
+		Filter:   util.NewTerminatedSparkApplicationFilter(24 * time.Hour),
+	}
+
+	It("Should request paginated lists from etcd", func() {
+		_, err := lw.List(metav1.ListOptions{ResourceVersion: "0"})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(lastOptions.ResourceVersion).To(BeEmpty())
+		Expect(lastOptions.Limit).To(Equal(int64(100)))
+	})
+
+	It("Should drop applications terminated before the cutoff from lists", func() {
+		list, err := lw.List(metav1.ListOptions{})
+		Expect(err).NotTo(HaveOccurred())
+		var names []string
+		for _, app := range list.(*v1beta2.SparkApplicationList).Items {
+			names = append(names, app.Name)
+		}
+		Expect(names).To(Equal([]string{"running", "recent"}))
+	})
+
+	It("Should drop applications terminated before the cutoff from watch events", func() {
+		w, err := lw.Watch(metav1.ListOptions{})
+		Expect(err).NotTo(HaveOccurred())
+		defer w.Stop()
+
+		old := newApp("old", v1beta2.ApplicationStateFailed, 48*time.Hour)
+		recent := newApp("recent", v1beta2.ApplicationStateCompleted, time.Minute)
+		go func() {
+			fakeWatcher.Modify(&old)
+			fakeWatcher.Modify(&recent)
+		}()
+
+		event := <-w.ResultChan()
+		Expect(event.Object.(*v1beta2.SparkApplication).Name).To(Equal("recent"))
+	})
+})
@@ -0,0 +1,115 @@
+/*
+Copyright 2024 The Kubeflow authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package util
+
+import (
+	"fmt"
+
+	"github.com/kubeflow/spark-operator/api/v1beta2"
+	"github.com/kubeflow/spark-operator/pkg/common"
+)
+
+// GetLocalDirPolicyConf returns the Spark volume configuration properties expanding the local dir policy
+// of the given SparkApplication into the local dir volumes of the executors, and of the driver if enabled.
+func GetLocalDirPolicyConf(app *v1beta2.SparkApplication) (map[string]string, error) {
+	policy := app.Spec.LocalDirPolicy
+	if policy == nil {
+		return nil, nil
+	}
+
+	if len(GetLocalVolumes(app)) > 0 {
+		return nil, fmt.Errorf("local dir policy cannot be combined with volumes prefixed with %s",
+			common.SparkLocalDirVolumePrefix)
+	}
+
+	count := 1
+	if policy.Count != nil {
+		if *policy.Count < 1 {
+			return nil, fmt.Errorf("local dir count must be positive, got %d", *policy.Count)
+		}
+		count = int(*policy.Count)
+	}
+
+	var volumeType string
+	options := make([]map[string]string, 0, count)
+	switch policy.Type {
+	case v1beta2.LocalDirTypeEmptyDir, v1beta2.LocalDirTypeMemory:
+		volumeType = common.VolumeTypeEmptyDir
+		for i := 0; i < count; i++ {
+			option := map[string]string{}
+			if policy.Type == v1beta2.LocalDirTypeMemory {
+				option["medium"] = "Memory"
+			}
+			if policy.SizeLimit != nil {
+				option["sizeLimit"] = policy.SizeLimit.String()
+			}
+			options = append(options, option)
+		}
+	case v1beta2.LocalDirTypeHostPath:
+		if len(policy.HostPaths) == 0 {
+			return nil, fmt.Errorf("host paths must be specified for local dir type %s", policy.Type)
+		}
+		volumeType = common.VolumeTypeHostPath
+		for _, path := range policy.HostPaths {
+			options = append(options, map[string]string{"path": path})
+		}
+	case v1beta2.LocalDirTypePersistentVolumeClaim:
+		if policy.SizeLimit == nil {
+			return nil, fmt.Errorf("size limit must be specified for local dir type %s", policy.Type)
+		}
+		volumeType = common.VolumeTypePersistentVolumeClaim
+		for i := 0; i < count; i++ {
+			option := map[string]string{
+				"claimName": common.SparkVolumeOnDemandClaimName,
+				"sizeLimit": policy.SizeLimit.String(),
+			}
+			if policy.StorageClassName != nil {
+				option["storageClass"] = *policy.StorageClassName
+			}
+			options = append(options, option)
+		}
+	default:
+		return nil, fmt.Errorf("unsupported local dir type %q", policy.Type)
+	}
+
+	type templates struct {
+		mountPath string
+		options   string
+	}
+	roles := []templates{{
+		mountPath: common.SparkKubernetesExecutorVolumesMountPathTemplate,
+		options:   common.SparkKubernetesExecutorVolumesOptionsTemplate,
+	}}
+	if policy.Driver != nil && *policy.Driver {
+		roles = append(roles, templates{
+			mountPath: common.SparkKubernetesDriverVolumesMountPathTemplate,
+			options:   common.SparkKubernetesDriverVolumesOptionsTemplate,
+		})
+	}
+
+	conf := make(map[string]string)
+	for i, option := range options {
+		name := fmt.Sprintf("%s%d", common.SparkLocalDirVolumePrefix, i+1)
+		for _, role := range roles {
+			conf[fmt.Sprintf(role.mountPath, volumeType, name)] = common.SparkLocalDirMountPathPrefix + name
+			for key, value := range option {
+				conf[fmt.Sprintf(role.options, volumeType, name, key)] = value
+			}
+		}
+	}
+	return conf, nil
+}
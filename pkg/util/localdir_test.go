@@ -0,0 +1,102 @@
+/*
+Copyright 2024 The Kubeflow authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package util_test
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	"k8s.io/utils/ptr"
+
+	"github.com/kubeflow/spark-operator/api/v1beta2"
+	"github.com/kubeflow/spark-operator/pkg/util"
+)
+
+var _ = Describe("GetLocalDirPolicyConf", func() {
+	newApp := func(policy *v1beta2.LocalDirPolicy) *v1beta2.SparkApplication {
+		return &v1beta2.SparkApplication{
+			Spec: v1beta2.SparkApplicationSpec{
+				LocalDirPolicy: policy,
+			},
+		}
+	}
+
+	It("Should return nothing if the local dir policy is not set", func() {
+		conf, err := util.GetLocalDirPolicyConf(newApp(nil))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(conf).To(BeEmpty())
+	})
+
+	It("Should expand memory-backed emptyDir volumes for the executors", func() {
+		conf, err := util.GetLocalDirPolicyConf(newApp(&v1beta2.LocalDirPolicy{
+			Type:      v1beta2.LocalDirTypeMemory,
+			Count:     ptr.To[int32](2),
+			SizeLimit: ptr.To(resource.MustParse("1Gi")),
+		}))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(conf).To(Equal(map[string]string{
+			"spark.kubernetes.executor.volumes.emptyDir.spark-local-dir-1.mount.path":        "/var/data/spark-local-dir-1",
+			"spark.kubernetes.executor.volumes.emptyDir.spark-local-dir-1.options.medium":    "Memory",
+			"spark.kubernetes.executor.volumes.emptyDir.spark-local-dir-1.options.sizeLimit": "1Gi",
+			"spark.kubernetes.executor.volumes.emptyDir.spark-local-dir-2.mount.path":        "/var/data/spark-local-dir-2",
+			"spark.kubernetes.executor.volumes.emptyDir.spark-local-dir-2.options.medium":    "Memory",
+			"spark.kubernetes.executor.volumes.emptyDir.spark-local-dir-2.options.sizeLimit": "1Gi",
+		}))
+	})
+
+	It("Should expand one hostPath volume per host path for the driver and executors", func() {
+		conf, err := util.GetLocalDirPolicyConf(newApp(&v1beta2.LocalDirPolicy{
+			Type:      v1beta2.LocalDirTypeHostPath,
+			HostPaths: []string{"/mnt/disk1", "/mnt/disk2"},
+			Driver:    ptr.To(true),
+		}))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(conf).To(HaveLen(8))
+		Expect(conf).To(HaveKeyWithValue("spark.kubernetes.driver.volumes.hostPath.spark-local-dir-1.options.path", "/mnt/disk1"))
+		Expect(conf).To(HaveKeyWithValue("spark.kubernetes.executor.volumes.hostPath.spark-local-dir-2.options.path", "/mnt/disk2"))
+	})
+
+	It("Should expand on-demand PersistentVolumeClaims", func() {
+		conf, err := util.GetLocalDirPolicyConf(newApp(&v1beta2.LocalDirPolicy{
+			Type:             v1beta2.LocalDirTypePersistentVolumeClaim,
+			SizeLimit:        ptr.To(resource.MustParse("100Gi")),
+			StorageClassName: ptr.To("fast-ssd"),
+		}))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(conf).To(Equal(map[string]string{
+			"spark.kubernetes.executor.volumes.persistentVolumeClaim.spark-local-dir-1.mount.path":           "/var/data/spark-local-dir-1",
+			"spark.kubernetes.executor.volumes.persistentVolumeClaim.spark-local-dir-1.options.claimName":    "OnDemand",
+			"spark.kubernetes.executor.volumes.persistentVolumeClaim.spark-local-dir-1.options.sizeLimit":    "100Gi",
+			"spark.kubernetes.executor.volumes.persistentVolumeClaim.spark-local-dir-1.options.storageClass": "fast-ssd",
+		}))
+	})
+
+	It("Should return an error if the local dir policy is invalid", func() {
+		_, err := util.GetLocalDirPolicyConf(newApp(&v1beta2.LocalDirPolicy{Type: v1beta2.LocalDirTypeHostPath}))
+		Expect(err).To(HaveOccurred())
+
+		_, err = util.GetLocalDirPolicyConf(newApp(&v1beta2.LocalDirPolicy{Type: v1beta2.LocalDirTypePersistentVolumeClaim}))
+		Expect(err).To(HaveOccurred())
+
+		app := newApp(&v1beta2.LocalDirPolicy{Type: v1beta2.LocalDirTypeEmptyDir})
+		app.Spec.Volumes = []corev1.Volume{{Name: "spark-local-dir-1"}}
+		_, err = util.GetLocalDirPolicyConf(app)
+		Expect(err).To(HaveOccurred())
+	})
+})
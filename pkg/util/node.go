@@ -0,0 +1,50 @@
+/*
+Copyright 2024 The Kubeflow authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package util
+
+import (
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+
+	"github.com/kubeflow/spark-operator/pkg/common"
+)
+
+// IsNodeUnreachable returns whether the node has not been ready for at least the given grace period,
+// i.e. its Ready condition has been false or unknown since at least the grace period before now.
+func IsNodeUnreachable(node *corev1.Node, gracePeriod time.Duration, now time.Time) bool {
+	for _, condition := range node.Status.Conditions {
+		if condition.Type != corev1.NodeReady {
+			continue
+		}
+		if condition.Status == corev1.ConditionTrue {
+			return false
+		}
+		return now.Sub(condition.LastTransitionTime.Time) >= gracePeriod
+	}
+	return false
+}
+
+// IsNodeDisruptedByKarpenter returns whether Karpenter has tainted the node for disruption, e.g. consolidation.
+func IsNodeDisruptedByKarpenter(node *corev1.Node) bool {
+	for _, taint := range node.Spec.Taints {
+		if taint.Key == common.KarpenterDisruptedTaintKey {
+			return true
+		}
+	}
+	return false
+}
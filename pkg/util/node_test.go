@@ -0,0 +1,111 @@
+/*
+Copyright 2024 The Kubeflow authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package util_test
+
+import (
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/kubeflow/spark-operator/pkg/common"
+	"github.com/kubeflow/spark-operator/pkg/util"
+)
+
+var _ = Describe("IsNodeUnreachable", func() {
+	now := time.Now()
+	gracePeriod := 5 * time.Minute
+
+	newNode := func(status corev1.ConditionStatus, since time.Duration) *corev1.Node {
+		return &corev1.Node{
+			ObjectMeta: metav1.ObjectMeta{
+				Name: "test-node",
+			},
+			Status: corev1.NodeStatus{
+				Conditions: []corev1.NodeCondition{
+					{
+						Type:               corev1.NodeReady,
+						Status:             status,
+						LastTransitionTime: metav1.NewTime(now.Add(-since)),
+					},
+				},
+			},
+		}
+	}
+
+	Context("Node is ready", func() {
+		node := newNode(corev1.ConditionTrue, time.Hour)
+
+		It("Should return false", func() {
+			Expect(util.IsNodeUnreachable(node, gracePeriod, now)).To(BeFalse())
+		})
+	})
+
+	Context("Node is not ready within the grace period", func() {
+		node := newNode(corev1.ConditionFalse, time.Minute)
+
+		It("Should return false", func() {
+			Expect(util.IsNodeUnreachable(node, gracePeriod, now)).To(BeFalse())
+		})
+	})
+
+	Context("Node is unknown beyond the grace period", func() {
+		node := newNode(corev1.ConditionUnknown, 10*time.Minute)
+
+		It("Should return true", func() {
+			Expect(util.IsNodeUnreachable(node, gracePeriod, now)).To(BeTrue())
+		})
+	})
+
+	Context("Node without ready condition", func() {
+		node := &corev1.Node{}
+
+		It("Should return false", func() {
+			Expect(util.IsNodeUnreachable(node, gracePeriod, now)).To(BeFalse())
+		})
+	})
+})
+
+var _ = Describe("IsNodeDisruptedByKarpenter", func() {
+	Context("Node without taints", func() {
+		node := &corev1.Node{}
+
+		It("Should return false", func() {
+			Expect(util.IsNodeDisruptedByKarpenter(node)).To(BeFalse())
+		})
+	})
+
+	Context("Node tainted for disruption", func() {
+		node := &corev1.Node{
+			Spec: corev1.NodeSpec{
+				Taints: []corev1.Taint{
+					{
+						Key:    common.KarpenterDisruptedTaintKey,
+						Effect: corev1.TaintEffectNoSchedule,
+					},
+				},
+			},
+		}
+
+		It("Should return true", func() {
+			Expect(util.IsNodeDisruptedByKarpenter(node)).To(BeTrue())
+		})
+	})
+})
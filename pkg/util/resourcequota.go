@@ -18,6 +18,10 @@ package util
 
 import (
 	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	"k8s.io/utils/ptr"
+
+	"github.com/kubeflow/spark-operator/api/v1beta2"
 )
 
 // SumResourceList sums the resource list.
@@ -35,3 +39,39 @@ func SumResourceList(lists []corev1.ResourceList) corev1.ResourceList {
 	}
 	return total
 }
+
+// GetResourceReservation returns the resource requests of the executors the app may still scale up to with dynamic
+// allocation given the number of its active executors, i.e. of its executor pods which exist and are accounted for by
+// the ResourceQuotas. It returns nil if the app cannot scale up beyond its initial executors.
+func GetResourceReservation(app *v1beta2.SparkApplication, activeExecutors int32) corev1.ResourceList {
+	dynamicAllocation := app.Spec.DynamicAllocation
+	if dynamicAllocation == nil || !dynamicAllocation.Enabled || dynamicAllocation.MaxExecutors == nil {
+		return nil
+	}
+	if *dynamicAllocation.MaxExecutors <= GetInitialExecutorNumber(app) {
+		return nil
+	}
+	executors := max(*dynamicAllocation.MaxExecutors-activeExecutors, 0)
+
+	executorApp := app.DeepCopy()
+	executorApp.Spec.Executor.Instances = ptr.To[int32](1)
+	requests := GetExecutorRequestResource(executorApp)
+
+	reservation := corev1.ResourceList{}
+	for name, requestName := range map[corev1.ResourceName]corev1.ResourceName{
+		corev1.ResourceCPU:    corev1.ResourceRequestsCPU,
+		corev1.ResourceMemory: corev1.ResourceRequestsMemory,
+	} {
+		quantity, ok := requests[name]
+		if !ok {
+			continue
+		}
+		total := resource.NewQuantity(0, quantity.Format)
+		for i := int32(0); i < executors; i++ {
+			total.Add(quantity)
+		}
+		reservation[name] = total.DeepCopy()
+		reservation[requestName] = total.DeepCopy()
+	}
+	return reservation
+}
@@ -0,0 +1,95 @@
+/*
+Copyright 2024 The Kubeflow authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package util_test
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	"k8s.io/utils/ptr"
+
+	"github.com/kubeflow/spark-operator/api/v1beta2"
+	"github.com/kubeflow/spark-operator/pkg/util"
+)
+
+var _ = Describe("GetResourceReservation", func() {
+	newApp := func(dynamicAllocation *v1beta2.DynamicAllocation) *v1beta2.SparkApplication {
+		return &v1beta2.SparkApplication{
+			Spec: v1beta2.SparkApplicationSpec{
+				Executor: v1beta2.ExecutorSpec{
+					SparkPodSpec: v1beta2.SparkPodSpec{
+						Cores:  ptr.To[int32](2),
+						Memory: ptr.To("4Gi"),
+					},
+					Instances: ptr.To[int32](2),
+				},
+				DynamicAllocation: dynamicAllocation,
+			},
+		}
+	}
+
+	Context("Dynamic allocation is disabled", func() {
+		app := newApp(nil)
+
+		It("Should return nil", func() {
+			Expect(util.GetResourceReservation(app, 0)).To(BeNil())
+		})
+	})
+
+	Context("Max executors do not exceed initial executors", func() {
+		app := newApp(&v1beta2.DynamicAllocation{
+			Enabled:          true,
+			InitialExecutors: ptr.To[int32](4),
+			MaxExecutors:     ptr.To[int32](4),
+		})
+
+		It("Should return nil", func() {
+			Expect(util.GetResourceReservation(app, 0)).To(BeNil())
+		})
+	})
+
+	Context("Max executors exceed initial executors", func() {
+		app := newApp(&v1beta2.DynamicAllocation{
+			Enabled:      true,
+			MinExecutors: ptr.To[int32](1),
+			MaxExecutors: ptr.To[int32](5),
+		})
+
+		It("Should reserve the resources of all executors before any executor is active", func() {
+			reservation := util.GetResourceReservation(app, 0)
+			Expect(reservation.Name(corev1.ResourceRequestsCPU, resource.DecimalSI).String()).To(Equal("10"))
+			Expect(reservation.Name(corev1.ResourceCPU, resource.DecimalSI).String()).To(Equal("10"))
+			Expect(reservation.Name(corev1.ResourceRequestsMemory, resource.BinarySI).String()).To(Equal("20Gi"))
+			Expect(reservation.Name(corev1.ResourceMemory, resource.BinarySI).String()).To(Equal("20Gi"))
+		})
+
+		It("Should reserve the resources of the executors which are not active yet", func() {
+			reservation := util.GetResourceReservation(app, 2)
+			Expect(reservation.Name(corev1.ResourceRequestsCPU, resource.DecimalSI).String()).To(Equal("6"))
+			Expect(reservation.Name(corev1.ResourceRequestsMemory, resource.BinarySI).String()).To(Equal("12Gi"))
+		})
+
+		It("Should reserve nothing once all executors are active", func() {
+			reservation := util.GetResourceReservation(app, 5)
+			Expect(reservation).NotTo(BeNil())
+			Expect(reservation.Name(corev1.ResourceRequestsCPU, resource.DecimalSI).IsZero()).To(BeTrue())
+			Expect(reservation.Name(corev1.ResourceRequestsMemory, resource.BinarySI).IsZero()).To(BeTrue())
+		})
+	})
+})
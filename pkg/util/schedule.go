@@ -0,0 +1,252 @@
+/*
+Copyright 2024 The Kubeflow authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package util
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/robfig/cron/v3"
+
+	"github.com/kubeflow/spark-operator/api/v1beta2"
+)
+
+// maxExcludedRuns bounds the number of consecutive exclusion windows skipped when computing the next run.
+const maxExcludedRuns = 1000
+
+// scheduleMacros maps the calendar macros supported in schedules to the day of the month they run on.
+var scheduleMacros = map[string]func(year int, month time.Month, loc *time.Location) int{
+	"@firstbusinessday": firstBusinessDay,
+	"@lastbusinessday":  lastBusinessDay,
+	"@lastdayofmonth":   lastDayOfMonth,
+}
+
+// ParseSchedule parses the schedule of a ScheduledSparkApplication. The returned schedule is due whenever any of
+// Schedule and AdditionalSchedules is due in the time zone of the spec, except during the exclusion windows listed
+// in the data of the exclusion calendar ConfigMap, which may be nil.
+func ParseSchedule(spec *v1beta2.ScheduledSparkApplicationSpec, calendar map[string]string) (cron.Schedule, error) {
+	loc := time.Local
+	if spec.TimeZone != nil {
+		var err error
+		if loc, err = time.LoadLocation(*spec.TimeZone); err != nil {
+			return nil, fmt.Errorf("invalid time zone %q: %v", *spec.TimeZone, err)
+		}
+	}
+
+	s := &calendarSchedule{}
+	for _, expression := range append([]string{spec.Schedule}, spec.AdditionalSchedules...) {
+		schedule, err := parseScheduleExpression(expression, spec.TimeZone, loc)
+		if err != nil {
+			return nil, fmt.Errorf("invalid schedule %q: %v", expression, err)
+		}
+		s.schedules = append(s.schedules, schedule)
+	}
+
+	windows, err := ParseExclusionCalendar(calendar, loc)
+	if err != nil {
+		return nil, err
+	}
+	s.exclusions = windows
+	return s, nil
+}
+
+// ExclusionWindow is a time window [Start, End) during which no scheduled run is started.
+type ExclusionWindow struct {
+	Start time.Time
+	End   time.Time
+}
+
+// ParseExclusionCalendar parses the data of an exclusion calendar ConfigMap. Every non-empty line not starting with
+// `#` of every value is either a date, e.g. `2024-12-25`, excluding the whole day, or an interval `<start>/<end>`
+// whose ends are dates or RFC 3339 times. An end date is included in the interval. Dates are interpreted in the
+// given location.
+func ParseExclusionCalendar(calendar map[string]string, loc *time.Location) ([]ExclusionWindow, error) {
+	keys := make([]string, 0, len(calendar))
+	for key := range calendar {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	var windows []ExclusionWindow
+	for _, key := range keys {
+		for _, line := range strings.Split(calendar[key], "\n") {
+			line = strings.TrimSpace(line)
+			if line == "" || strings.HasPrefix(line, "#") {
+				continue
+			}
+
+			startValue, endValue, isInterval := strings.Cut(line, "/")
+			if !isInterval {
+				endValue = startValue
+			}
+			start, _, err := parseCalendarTime(strings.TrimSpace(startValue), loc)
+			if err != nil {
+				return nil, fmt.Errorf("invalid exclusion window %q in %q: %v", line, key, err)
+			}
+			end, isDate, err := parseCalendarTime(strings.TrimSpace(endValue), loc)
+			if err != nil {
+				return nil, fmt.Errorf("invalid exclusion window %q in %q: %v", line, key, err)
+			}
+			if isDate {
+				end = end.AddDate(0, 0, 1)
+			}
+			if !end.After(start) {
+				return nil, fmt.Errorf("invalid exclusion window %q in %q: end is not after start", line, key)
+			}
+			windows = append(windows, ExclusionWindow{Start: start, End: end})
+		}
+	}
+	return windows, nil
+}
+
+// IsExcluded returns whether the given time falls into one of the exclusion windows of the given schedule parsed
+// with ParseSchedule.
+func IsExcluded(schedule cron.Schedule, t time.Time) bool {
+	s, ok := schedule.(*calendarSchedule)
+	if !ok {
+		return false
+	}
+	_, excluded := s.exclusionAt(t)
+	return excluded
+}
+
+// parseCalendarTime parses a date or an RFC 3339 time, returning whether a date was parsed.
+func parseCalendarTime(value string, loc *time.Location) (time.Time, bool, error) {
+	if t, err := time.ParseInLocation(time.DateOnly, value, loc); err == nil {
+		return t, true, nil
+	}
+	t, err := time.Parse(time.RFC3339, value)
+	if err != nil {
+		return time.Time{}, false, fmt.Errorf("%q is neither a date nor an RFC 3339 time", value)
+	}
+	return t, false, nil
+}
+
+// parseScheduleExpression parses a standard cron expression or a calendar macro optionally followed by the time of
+// day, e.g. `@lastbusinessday 18:30`.
+func parseScheduleExpression(expression string, timeZone *string, loc *time.Location) (cron.Schedule, error) {
+	fields := strings.Fields(expression)
+	if len(fields) > 0 {
+		if day, ok := scheduleMacros[fields[0]]; ok {
+			s := &monthDaySchedule{day: day, location: loc}
+			switch len(fields) {
+			case 1:
+			case 2:
+				t, err := time.Parse("15:04", fields[1])
+				if err != nil {
+					return nil, fmt.Errorf("invalid time of day %q", fields[1])
+				}
+				s.hour, s.minute = t.Hour(), t.Minute()
+			default:
+				return nil, fmt.Errorf("expected %s [HH:MM]", fields[0])
+			}
+			return s, nil
+		}
+	}
+
+	if timeZone != nil && !strings.HasPrefix(expression, "TZ=") && !strings.HasPrefix(expression, "CRON_TZ=") {
+		expression = fmt.Sprintf("CRON_TZ=%s %s", *timeZone, expression)
+	}
+	return cron.ParseStandard(expression)
+}
+
+// calendarSchedule is due whenever any of its schedules is due outside of its exclusion windows.
+type calendarSchedule struct {
+	schedules  []cron.Schedule
+	exclusions []ExclusionWindow
+}
+
+var _ cron.Schedule = &calendarSchedule{}
+
+// Next implements cron.Schedule.
+func (s *calendarSchedule) Next(t time.Time) time.Time {
+	for i := 0; i < maxExcludedRuns; i++ {
+		var next time.Time
+		for _, schedule := range s.schedules {
+			if candidate := schedule.Next(t); !candidate.IsZero() && (next.IsZero() || candidate.Before(next)) {
+				next = candidate
+			}
+		}
+		window, excluded := s.exclusionAt(next)
+		if next.IsZero() || !excluded {
+			return next
+		}
+		// Cron schedules return the first activation at or after the second following the given time.
+		t = window.End.Add(-time.Nanosecond)
+	}
+	return time.Time{}
+}
+
+// exclusionAt returns the exclusion window the given time falls into, if any.
+func (s *calendarSchedule) exclusionAt(t time.Time) (ExclusionWindow, bool) {
+	for _, window := range s.exclusions {
+		if !t.Before(window.Start) && t.Before(window.End) {
+			return window, true
+		}
+	}
+	return ExclusionWindow{}, false
+}
+
+// monthDaySchedule is due once a month at the given time of the day computed by day.
+type monthDaySchedule struct {
+	day      func(year int, month time.Month, loc *time.Location) int
+	hour     int
+	minute   int
+	location *time.Location
+}
+
+var _ cron.Schedule = &monthDaySchedule{}
+
+// Next implements cron.Schedule.
+func (s *monthDaySchedule) Next(t time.Time) time.Time {
+	t = t.In(s.location)
+	for i := 0; i < 2; i++ {
+		first := time.Date(t.Year(), t.Month()+time.Month(i), 1, 0, 0, 0, 0, s.location)
+		day := s.day(first.Year(), first.Month(), s.location)
+		next := time.Date(first.Year(), first.Month(), day, s.hour, s.minute, 0, 0, s.location)
+		if next.After(t) {
+			return next
+		}
+	}
+	return time.Time{}
+}
+
+func lastDayOfMonth(year int, month time.Month, loc *time.Location) int {
+	return time.Date(year, month+1, 0, 0, 0, 0, 0, loc).Day()
+}
+
+func firstBusinessDay(year int, month time.Month, loc *time.Location) int {
+	day := 1
+	for isWeekend(time.Date(year, month, day, 0, 0, 0, 0, loc)) {
+		day++
+	}
+	return day
+}
+
+func lastBusinessDay(year int, month time.Month, loc *time.Location) int {
+	day := lastDayOfMonth(year, month, loc)
+	for isWeekend(time.Date(year, month, day, 0, 0, 0, 0, loc)) {
+		day--
+	}
+	return day
+}
+
+func isWeekend(t time.Time) bool {
+	return t.Weekday() == time.Saturday || t.Weekday() == time.Sunday
+}
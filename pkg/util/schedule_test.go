@@ -0,0 +1,108 @@
+/*
+Copyright 2024 The Kubeflow authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package util_test
+
+import (
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"k8s.io/utils/ptr"
+
+	"github.com/kubeflow/spark-operator/api/v1beta2"
+	"github.com/kubeflow/spark-operator/pkg/util"
+)
+
+var _ = Describe("ParseSchedule", func() {
+	parse := func(spec *v1beta2.ScheduledSparkApplicationSpec, calendar map[string]string) func(string) time.Time {
+		schedule, err := util.ParseSchedule(spec, calendar)
+		Expect(err).NotTo(HaveOccurred())
+		return func(t string) time.Time {
+			now, err := time.Parse(time.RFC3339, t)
+			Expect(err).NotTo(HaveOccurred())
+			return schedule.Next(now)
+		}
+	}
+
+	It("Should run on the earliest of several schedules in the time zone", func() {
+		next := parse(&v1beta2.ScheduledSparkApplicationSpec{
+			Schedule:            "0 9 * * *",
+			AdditionalSchedules: []string{"0 17 * * *"},
+			TimeZone:            ptr.To("Europe/Berlin"),
+		}, nil)
+		Expect(next("2024-06-03T10:00:00+02:00").UTC()).To(Equal(time.Date(2024, 6, 3, 15, 0, 0, 0, time.UTC)))
+		Expect(next("2024-06-03T18:00:00+02:00").UTC()).To(Equal(time.Date(2024, 6, 4, 7, 0, 0, 0, time.UTC)))
+	})
+
+	It("Should run on the last business day of the month", func() {
+		next := parse(&v1beta2.ScheduledSparkApplicationSpec{
+			Schedule: "@lastbusinessday 18:30",
+			TimeZone: ptr.To("UTC"),
+		}, nil)
+		Expect(next("2024-08-01T00:00:00Z")).To(Equal(time.Date(2024, 8, 30, 18, 30, 0, 0, time.UTC)))
+		Expect(next("2024-08-30T18:30:00Z")).To(Equal(time.Date(2024, 9, 30, 18, 30, 0, 0, time.UTC)))
+	})
+
+	It("Should run on the first business day of the month", func() {
+		next := parse(&v1beta2.ScheduledSparkApplicationSpec{
+			Schedule: "@firstbusinessday",
+			TimeZone: ptr.To("UTC"),
+		}, nil)
+		Expect(next("2024-05-31T12:00:00Z")).To(Equal(time.Date(2024, 6, 3, 0, 0, 0, 0, time.UTC)))
+	})
+
+	It("Should skip runs in exclusion windows", func() {
+		spec := &v1beta2.ScheduledSparkApplicationSpec{
+			Schedule: "0 * * * *",
+			TimeZone: ptr.To("UTC"),
+		}
+		calendar := map[string]string{
+			"holidays":    "# Christmas\n2024-12-25\n2024-12-26\n",
+			"maintenance": "2024-06-03T08:00:00Z/2024-06-03T10:00:00Z",
+		}
+		next := parse(spec, calendar)
+		Expect(next("2024-12-24T23:30:00Z")).To(Equal(time.Date(2024, 12, 27, 0, 0, 0, 0, time.UTC)))
+		Expect(next("2024-06-03T07:30:00Z")).To(Equal(time.Date(2024, 6, 3, 10, 0, 0, 0, time.UTC)))
+
+		schedule, err := util.ParseSchedule(spec, calendar)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(util.IsExcluded(schedule, time.Date(2024, 12, 25, 9, 0, 0, 0, time.UTC))).To(BeTrue())
+		Expect(util.IsExcluded(schedule, time.Date(2024, 12, 27, 9, 0, 0, 0, time.UTC))).To(BeFalse())
+	})
+
+	It("Should return an error for invalid schedules", func() {
+		invalid := []*v1beta2.ScheduledSparkApplicationSpec{
+			{Schedule: "every ten minutes"},
+			{Schedule: "@every 10m", AdditionalSchedules: []string{"* *"}},
+			{Schedule: "@every 10m", TimeZone: ptr.To("Mars/Olympus_Mons")},
+			{Schedule: "@lastbusinessday 25:00"},
+		}
+		for _, spec := range invalid {
+			_, err := util.ParseSchedule(spec, nil)
+			Expect(err).To(HaveOccurred())
+		}
+	})
+
+	It("Should return an error for invalid exclusion windows", func() {
+		spec := &v1beta2.ScheduledSparkApplicationSpec{Schedule: "@every 10m"}
+		for _, window := range []string{"tomorrow", "2024-06-03/2024-06-01"} {
+			_, err := util.ParseSchedule(spec, map[string]string{"windows": window})
+			Expect(err).To(HaveOccurred())
+		}
+	})
+})
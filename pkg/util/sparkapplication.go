@@ -17,34 +17,34 @@ limitations under the License.
 package util
 
 import (
-	"crypto/md5"
+	"crypto/sha256"
+	"encoding/hex"
 	"fmt"
+	"maps"
+	"math"
 	"reflect"
+	"slices"
+	"sort"
+	"strconv"
 	"strings"
 	"time"
 
+	"github.com/robfig/cron/v3"
 	corev1 "k8s.io/api/core/v1"
 	networkingv1 "k8s.io/api/networking/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
 	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/ptr"
 
 	"github.com/kubeflow/spark-operator/api/v1beta2"
 	"github.com/kubeflow/spark-operator/pkg/common"
+	"github.com/kubeflow/spark-operator/pkg/naming"
 )
 
 // GetDriverPodName returns name of the driver pod of the given spark application.
 func GetDriverPodName(app *v1beta2.SparkApplication) string {
-	name := app.Spec.Driver.PodName
-	if name != nil && len(*name) > 0 {
-		return *name
-	}
-
-	sparkConf := app.Spec.SparkConf
-	if sparkConf[common.SparkKubernetesDriverPodName] != "" {
-		return sparkConf[common.SparkKubernetesDriverPodName]
-	}
-
-	return fmt.Sprintf("%s-driver", app.Name)
+	return naming.DriverPodName(app)
 }
 
 // GetApplicationState returns the state of the given SparkApplication.
@@ -52,6 +52,12 @@ func GetApplicationState(app *v1beta2.SparkApplication) v1beta2.ApplicationState
 	return app.Status.AppState.State
 }
 
+// GetCorrelationID returns the ID correlating the log lines of the controller, the submission and the webhook about
+// the current run of the given SparkApplication, in the form of `<namespace>/<name>/<submission ID>`.
+func GetCorrelationID(app *v1beta2.SparkApplication) string {
+	return correlationID(app.Namespace, app.Name, app.Status.SubmissionID)
+}
+
 // IsTerminated returns whether the given SparkApplication is terminated.
 func IsTerminated(app *v1beta2.SparkApplication) bool {
 	return app.Status.AppState.State == v1beta2.ApplicationStateCompleted ||
@@ -74,6 +80,93 @@ func IsExpired(app *v1beta2.SparkApplication) bool {
 	return false
 }
 
+// GetPodGroupExpiry returns the time the batch scheduler PodGroup of the given terminated SparkApplication expires,
+// or false if the application has not terminated or keeps no PodGroup after terminating.
+func GetPodGroupExpiry(app *v1beta2.SparkApplication) (time.Time, bool) {
+	options := app.Spec.BatchSchedulerOptions
+	if options == nil || options.TTLSecondsAfterFinished == nil || app.Status.TerminationTime.IsZero() {
+		return time.Time{}, false
+	}
+	ttl := time.Duration(*options.TTLSecondsAfterFinished) * time.Second
+	return app.Status.TerminationTime.Add(ttl), true
+}
+
+// UpdateConditions derives the conditions of the given SparkApplication from its application state.
+func UpdateConditions(app *v1beta2.SparkApplication) {
+	state := app.Status.AppState.State
+	reason := getConditionReason(state)
+	app.Status.ObservedGeneration = app.Generation
+
+	setCondition := func(conditionType v1beta2.SparkApplicationConditionType, status bool, message string) {
+		condition := metav1.Condition{
+			Type:               string(conditionType),
+			Status:             metav1.ConditionFalse,
+			ObservedGeneration: app.Generation,
+			Reason:             reason,
+			Message:            truncateConditionMessage(message),
+		}
+		if status {
+			condition.Status = metav1.ConditionTrue
+		}
+		meta.SetStatusCondition(&app.Status.Conditions, condition)
+	}
+
+	submitted := state != v1beta2.ApplicationStateNew &&
+		state != v1beta2.ApplicationStateWaitingForDependencies &&
+		state != v1beta2.ApplicationStateFailedSubmission &&
+		state != v1beta2.ApplicationStatePendingRerun &&
+		state != v1beta2.ApplicationStateInvalidating
+	submissionMessage := ""
+	if state == v1beta2.ApplicationStateFailedSubmission {
+		submissionMessage = app.Status.AppState.ErrorMessage
+	}
+	setCondition(v1beta2.SparkApplicationConditionSubmitted, submitted, submissionMessage)
+	setCondition(v1beta2.SparkApplicationConditionRunning, state == v1beta2.ApplicationStateRunning, "")
+	setCondition(v1beta2.SparkApplicationConditionComplete, state == v1beta2.ApplicationStateCompleted, "")
+
+	failureMessage := ""
+	if state == v1beta2.ApplicationStateFailed {
+		failureMessage = app.Status.AppState.ErrorMessage
+	}
+	setCondition(v1beta2.SparkApplicationConditionFailed, state == v1beta2.ApplicationStateFailed, failureMessage)
+}
+
+// maxConditionMessageLength is the maximum length of the message of a condition accepted by the API server.
+const maxConditionMessageLength = 32768
+
+// truncatedConditionMessagePrefix marks condition messages whose beginning was cut off.
+const truncatedConditionMessagePrefix = "...(truncated) "
+
+// truncateConditionMessage truncates the given message to the maximum length of condition messages, so that a long
+// spark-submit output does not get the whole status update rejected. The end of the message is kept, as that is
+// where spark-submit reports the cause of failures.
+func truncateConditionMessage(message string) string {
+	runes := []rune(message)
+	if len(runes) <= maxConditionMessageLength {
+		return message
+	}
+	keep := maxConditionMessageLength - len([]rune(truncatedConditionMessagePrefix))
+	return truncatedConditionMessagePrefix + string(runes[len(runes)-keep:])
+}
+
+// getConditionReason returns a CamelCase condition reason for the given application state, e.g. `SubmissionFailed`.
+func getConditionReason(state v1beta2.ApplicationStateType) string {
+	if state == v1beta2.ApplicationStateNew {
+		return "New"
+	}
+	if state == v1beta2.ApplicationStateFailedSubmission {
+		return "SubmissionFailed"
+	}
+	var reason strings.Builder
+	for _, word := range strings.Split(strings.ToLower(string(state)), "_") {
+		if word == "" {
+			continue
+		}
+		reason.WriteString(strings.ToUpper(word[:1]) + word[1:])
+	}
+	return reason.String()
+}
+
 // IsDriverRunning returns whether the driver pod of the given SparkApplication is running.
 func IsDriverRunning(app *v1beta2.SparkApplication) bool {
 	return app.Status.AppState.State == v1beta2.ApplicationStateRunning
@@ -85,27 +178,93 @@ func ShouldRetry(app *v1beta2.SparkApplication) bool {
 		return app.Spec.RestartPolicy.Type == v1beta2.RestartPolicyAlways
 	case v1beta2.ApplicationStateFailing:
 		if app.Spec.RestartPolicy.Type == v1beta2.RestartPolicyAlways {
-			return true
+			return !hasExhaustedRetryBackoff(app)
 		} else if app.Spec.RestartPolicy.Type == v1beta2.RestartPolicyOnFailure {
 			// We retry if we haven't hit the retry limit.
 			if app.Spec.RestartPolicy.OnFailureRetries != nil && app.Status.ExecutionAttempts <= *app.Spec.RestartPolicy.OnFailureRetries {
-				return true
+				return !hasExhaustedRetryBackoff(app)
 			}
 		}
 	case v1beta2.ApplicationStateFailedSubmission:
 		if app.Spec.RestartPolicy.Type == v1beta2.RestartPolicyAlways {
-			return true
+			return !hasExhaustedRetryBackoff(app)
 		} else if app.Spec.RestartPolicy.Type == v1beta2.RestartPolicyOnFailure {
 			// We retry if we haven't hit the retry limit.
 			if app.Spec.RestartPolicy.OnSubmissionFailureRetries != nil && app.Status.SubmissionAttempts <= *app.Spec.RestartPolicy.OnSubmissionFailureRetries {
-				return true
+				return !hasExhaustedRetryBackoff(app)
 			}
 		}
 	}
 	return false
 }
 
+// GetRetryBackoffInterval returns the interval before the n-th retry according to the given backoff.
+func GetRetryBackoffInterval(backoff *v1beta2.RetryBackoff, n int32) (time.Duration, error) {
+	multiplier := 2.0
+	if backoff.Multiplier != nil {
+		var err error
+		if multiplier, err = strconv.ParseFloat(*backoff.Multiplier, 64); err != nil || multiplier < 1 {
+			return 0, fmt.Errorf("invalid backoff multiplier %q: must be a number not less than 1", *backoff.Multiplier)
+		}
+	}
+
+	interval := float64(backoff.InitialIntervalSeconds)
+	for i := int32(1); i < n; i++ {
+		interval *= multiplier
+		if backoff.MaxIntervalSeconds != nil && interval >= float64(*backoff.MaxIntervalSeconds) {
+			break
+		}
+	}
+	if backoff.MaxIntervalSeconds != nil && interval > float64(*backoff.MaxIntervalSeconds) {
+		interval = float64(*backoff.MaxIntervalSeconds)
+	}
+	return time.Duration(interval * float64(time.Second)), nil
+}
+
+// getRetryAttempts returns the number of attempts made so far that the backoff of the next retry is based on.
+func getRetryAttempts(app *v1beta2.SparkApplication) int32 {
+	if app.Status.AppState.State == v1beta2.ApplicationStateFailing {
+		return app.Status.ExecutionAttempts
+	}
+	return app.Status.SubmissionAttempts
+}
+
+// hasExhaustedRetryBackoff returns whether the next retry of the given SparkApplication would exceed the maximum
+// total duration of its retry backoff.
+func hasExhaustedRetryBackoff(app *v1beta2.SparkApplication) bool {
+	backoff := app.Spec.RestartPolicy.Backoff
+	if backoff == nil || backoff.MaxDurationSeconds == nil {
+		return false
+	}
+
+	var total time.Duration
+	for n := int32(1); n <= getRetryAttempts(app); n++ {
+		interval, err := GetRetryBackoffInterval(backoff, n)
+		if err != nil {
+			return false
+		}
+		total += interval
+		if total > time.Duration(*backoff.MaxDurationSeconds)*time.Second {
+			return true
+		}
+	}
+	return false
+}
+
 func TimeUntilNextRetryDue(app *v1beta2.SparkApplication) (time.Duration, error) {
+	if backoff := app.Spec.RestartPolicy.Backoff; backoff != nil {
+		lastAttemptTime := app.Status.LastSubmissionAttemptTime
+		attemptsDone := getRetryAttempts(app)
+		if lastAttemptTime.IsZero() || attemptsDone <= 0 {
+			return -1, fmt.Errorf("invalid last attempt time (%v) or attemptsDone (%v)", lastAttemptTime, attemptsDone)
+		}
+		interval, err := GetRetryBackoffInterval(backoff, attemptsDone)
+		if err != nil {
+			return -1, err
+		}
+		return interval - time.Since(lastAttemptTime.Time), nil
+	}
+
 	var retryInterval *int64
 	switch app.Status.AppState.State {
 	case v1beta2.ApplicationStateFailedSubmission:
@@ -156,25 +315,256 @@ func GetExecutorLocalVolumeMounts(app *v1beta2.SparkApplication) []corev1.Volume
 	return volumeMounts
 }
 
-func generateName(name, suffix string) string {
-	// Some resource names are used as DNS labels, so must be 63 characters or shorter
-	preferredName := fmt.Sprintf("%s-%s", name, suffix)
-	if len(preferredName) <= 63 {
-		return preferredName
+// GetInjectedDefaults returns the kinds of operator defaults applied to and suppressed from the given driver pod
+// as recorded by the webhook, or nil if the webhook injected no defaults.
+func GetInjectedDefaults(pod *corev1.Pod) *v1beta2.InjectedDefaultsStatus {
+	parse := func(key string) []v1beta2.InjectedDefault {
+		value := pod.Annotations[key]
+		if value == "" {
+			return nil
+		}
+		var kinds []v1beta2.InjectedDefault
+		for _, kind := range strings.Split(value, ",") {
+			kinds = append(kinds, v1beta2.InjectedDefault(kind))
+		}
+		return kinds
+	}
+
+	applied := parse(common.AnnotationAppliedDefaults)
+	suppressed := parse(common.AnnotationSuppressedDefaults)
+	if applied == nil && suppressed == nil {
+		return nil
 	}
+	return &v1beta2.InjectedDefaultsStatus{Applied: applied, Suppressed: suppressed}
+}
 
-	// Truncate the name and append a hash to ensure uniqueness while staying below the limit
-	maxNameLength := 63 - len(suffix) - 10 // 8 for the hash, 2 for the dash
-	hash := fmt.Sprintf("%x", md5.Sum([]byte(preferredName)))
-	return fmt.Sprintf("%s-%s-%s", name[:maxNameLength], hash[:8], suffix)
+// IsMainApplicationSourceInline returns whether the source code of the main application of the given
+// SparkApplication is specified inline.
+func IsMainApplicationSourceInline(app *v1beta2.SparkApplication) bool {
+	return app.Spec.MainApplicationSource != nil && app.Spec.MainApplicationSource.Inline != nil
 }
 
+// GetMainApplicationSourceKey returns the key of the inline main application source in its ConfigMap, which is
+// also the name of the main application file, with an extension matching the type of the application.
+func GetMainApplicationSourceKey(app *v1beta2.SparkApplication) string {
+	if app.Spec.Type == v1beta2.SparkApplicationTypeR {
+		return "main.R"
+	}
+	return "main.py"
+}
+
+// IsMainApplicationFileInConfigMap returns whether the main application file of the given SparkApplication
+// is embedded in a ConfigMap, either referenced by the user or generated from the inline source.
+func IsMainApplicationFileInConfigMap(app *v1beta2.SparkApplication) bool {
+	if IsMainApplicationSourceInline(app) {
+		return true
+	}
+	return app.Spec.MainApplicationFile != nil &&
+		strings.HasPrefix(*app.Spec.MainApplicationFile, common.MainApplicationFileConfigMapScheme)
+}
+
+// ParseMainApplicationFileConfigMap parses a main application file in the form of `configmap://<name>/<key>`
+// and returns the ConfigMap name and key.
+func ParseMainApplicationFileConfigMap(file string) (string, string, error) {
+	if !strings.HasPrefix(file, common.MainApplicationFileConfigMapScheme) {
+		return "", "", fmt.Errorf("main application file %q does not start with %q", file, common.MainApplicationFileConfigMapScheme)
+	}
+
+	name, key, found := strings.Cut(strings.TrimPrefix(file, common.MainApplicationFileConfigMapScheme), "/")
+	if !found || name == "" || key == "" || strings.Contains(key, "/") {
+		return "", "", fmt.Errorf("main application file %q must be in the form of %s<name>/<key>", file, common.MainApplicationFileConfigMapScheme)
+	}
+	return name, key, nil
+}
+
+// GetMainApplicationFileConfigMap returns the name and key of the ConfigMap holding the main application file of
+// the given SparkApplication, which must be embedded in a ConfigMap.
+func GetMainApplicationFileConfigMap(app *v1beta2.SparkApplication) (string, string, error) {
+	if IsMainApplicationSourceInline(app) {
+		return naming.MainApplicationSourceConfigMapName(app), GetMainApplicationSourceKey(app), nil
+	}
+	if app.Spec.MainApplicationFile == nil {
+		return "", "", fmt.Errorf("main application file is not specified")
+	}
+	return ParseMainApplicationFileConfigMap(*app.Spec.MainApplicationFile)
+}
+
+// ShouldMountConfProperties returns whether the SparkConf and HadoopConf properties of the given SparkApplication
+// are mounted on its driver and executor pods from a ConfigMap.
+func ShouldMountConfProperties(app *v1beta2.SparkApplication) bool {
+	return ptr.Deref(app.Spec.MountConfProperties, false)
+}
+
+// GetConfPropertiesConfigMapData returns the data of the ConfigMap holding the SparkConf and HadoopConf properties
+// of the given SparkApplication, each rendered in the Java properties format with keys in sorted order.
+func GetConfPropertiesConfigMapData(app *v1beta2.SparkApplication) map[string]string {
+	return map[string]string{
+		common.SparkConfPropertiesKey:  formatProperties(app.Spec.SparkConf),
+		common.HadoopConfPropertiesKey: formatProperties(app.Spec.HadoopConf),
+	}
+}
+
+// formatProperties renders the given properties in the Java properties format, one property per line.
+func formatProperties(properties map[string]string) string {
+	keyEscaper := strings.NewReplacer(`\`, `\\`, " ", `\ `, "=", `\=`, ":", `\:`, "#", `\#`, "!", `\!`,
+		"\n", `\n`, "\r", `\r`, "\t", `\t`, "\f", `\f`)
+	valueEscaper := strings.NewReplacer(`\`, `\\`, "\n", `\n`, "\r", `\r`, "\t", `\t`, "\f", `\f`)
+
+	var b strings.Builder
+	for _, key := range slices.Sorted(maps.Keys(properties)) {
+		value := valueEscaper.Replace(properties[key])
+		if strings.HasPrefix(value, " ") {
+			value = `\` + value
+		}
+		fmt.Fprintf(&b, "%s=%s\n", keyEscaper.Replace(key), value)
+	}
+	return b.String()
+}
+
+// GetMainApplicationFile returns the main application file passed to spark-submit. Main application files
+// embedded in a ConfigMap, including inline sources, are rewritten to the local path where the ConfigMap is
+// mounted in the driver pod. An empty string is returned if the application has no main application file.
+func GetMainApplicationFile(app *v1beta2.SparkApplication) (string, error) {
+	if app.Spec.MainApplicationFile == nil && !IsMainApplicationSourceInline(app) {
+		return "", nil
+	}
+
+	if !IsMainApplicationSourceInline(app) && IsOCIArtifact(*app.Spec.MainApplicationFile) {
+		return ResolveOCIArtifact(*app.Spec.MainApplicationFile)
+	}
+
+	if !IsMainApplicationFileInConfigMap(app) {
+		return *app.Spec.MainApplicationFile, nil
+	}
+
+	_, key, err := GetMainApplicationFileConfigMap(app)
+	if err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("local://%s/%s", common.DefaultMainApplicationFileMountPath, key), nil
+}
+
+// IsOCIArtifact returns whether the given file is referenced as a file in an OCI artifact.
+func IsOCIArtifact(file string) bool {
+	return strings.HasPrefix(file, common.OCIArtifactScheme)
+}
+
+// ParseOCIArtifact parses a file in the form of `oci://<registry>/<repository>:<tag>/<file>` or
+// `oci://<registry>/<repository>@<digest>/<file>` and returns the artifact reference and the file name.
+func ParseOCIArtifact(file string) (string, string, error) {
+	if !IsOCIArtifact(file) {
+		return "", "", fmt.Errorf("file %q does not start with %q", file, common.OCIArtifactScheme)
+	}
+
+	trimmed := strings.TrimPrefix(file, common.OCIArtifactScheme)
+	index := strings.LastIndex(trimmed, "/")
+	if index < 0 {
+		return "", "", fmt.Errorf("file %q must be in the form of %s<registry>/<repository>:<tag>/<file>", file, common.OCIArtifactScheme)
+	}
+	reference, name := trimmed[:index], trimmed[index+1:]
+	repository := reference[strings.LastIndex(reference, "/")+1:]
+	if name == "" || !strings.Contains(reference, "/") || !strings.ContainsAny(repository, ":@") {
+		return "", "", fmt.Errorf("file %q must be in the form of %s<registry>/<repository>:<tag>/<file>", file, common.OCIArtifactScheme)
+	}
+	return reference, name, nil
+}
+
+// GetOCIArtifactDir returns the directory where the OCI artifact with the given reference is pulled to.
+func GetOCIArtifactDir(reference string) string {
+	sum := sha256.Sum256([]byte(reference))
+	return fmt.Sprintf("%s/%s", common.DefaultOCIArtifactMountPath, hex.EncodeToString(sum[:])[:12])
+}
+
+// ResolveOCIArtifact returns the local path of a file in an OCI artifact as seen by spark-submit. Files not
+// referenced as OCI artifacts are returned as is.
+func ResolveOCIArtifact(file string) (string, error) {
+	if !IsOCIArtifact(file) {
+		return file, nil
+	}
+
+	reference, name, err := ParseOCIArtifact(file)
+	if err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("local://%s/%s", GetOCIArtifactDir(reference), name), nil
+}
+
+// GetOCIArtifacts returns the distinct references of the OCI artifacts the main application file and the
+// dependencies of the given SparkApplication are pulled from, in the order they are first referenced.
+func GetOCIArtifacts(app *v1beta2.SparkApplication) ([]string, error) {
+	var files []string
+	if app.Spec.MainApplicationFile != nil {
+		files = append(files, *app.Spec.MainApplicationFile)
+	}
+	files = append(files, app.Spec.Deps.Jars...)
+	files = append(files, app.Spec.Deps.Files...)
+	files = append(files, app.Spec.Deps.PyFiles...)
+	files = append(files, app.Spec.Deps.Archives...)
+
+	var references []string
+	seen := make(map[string]bool)
+	for _, file := range files {
+		if !IsOCIArtifact(file) {
+			continue
+		}
+		reference, _, err := ParseOCIArtifact(file)
+		if err != nil {
+			return nil, err
+		}
+		if !seen[reference] {
+			seen[reference] = true
+			references = append(references, reference)
+		}
+	}
+	return references, nil
+}
+
+// GetDefaultUIServiceName returns the name of the Service exposing the web UI of the given SparkApplication.
 func GetDefaultUIServiceName(app *v1beta2.SparkApplication) string {
-	return generateName(app.Name, "ui-svc")
+	return naming.UIServiceName(app)
 }
 
+// GetDefaultUIIngressName returns the name of the Ingress exposing the web UI of the given SparkApplication.
 func GetDefaultUIIngressName(app *v1beta2.SparkApplication) string {
-	return generateName(app.Name, "ui-ingress")
+	return naming.UIIngressName(app)
+}
+
+// GetSparkAuthSecretName returns the name of the Secret holding the generated authentication secret of the given SparkApplication.
+func GetSparkAuthSecretName(app *v1beta2.SparkApplication) string {
+	return naming.SparkAuthSecretName(app)
+}
+
+// GetDriverPVCRBACName returns the name of the Role and RoleBinding granting the driver access to persistent volume claims.
+func GetDriverPVCRBACName(app *v1beta2.SparkApplication) string {
+	return naming.DriverPVCRBACName(app)
+}
+
+// GetDriverServiceAccountName returns the name of the service account the driver pod runs as.
+func GetDriverServiceAccountName(app *v1beta2.SparkApplication) string {
+	if app.Spec.Driver.ServiceAccount != nil && *app.Spec.Driver.ServiceAccount != "" {
+		return *app.Spec.Driver.ServiceAccount
+	}
+	if name := app.Spec.SparkConf[common.SparkKubernetesAuthenticateDriverServiceAccountName]; name != "" {
+		return name
+	}
+	return "default"
+}
+
+// IsShuffleTrackingEnabled returns whether dynamic allocation with shuffle tracking is enabled for the given SparkApplication.
+func IsShuffleTrackingEnabled(app *v1beta2.SparkApplication) bool {
+	// Shuffle tracking is always turned on by the operator if dynamic allocation is enabled in the spec.
+	if app.Spec.DynamicAllocation != nil && app.Spec.DynamicAllocation.Enabled {
+		return true
+	}
+	dynamicAllocation, _ := strconv.ParseBool(app.Spec.SparkConf[common.SparkDynamicAllocationEnabled])
+	shuffleTracking, _ := strconv.ParseBool(app.Spec.SparkConf[common.SparkDynamicAllocationShuffleTrackingEnabled])
+	return dynamicAllocation && shuffleTracking
+}
+
+// IsPVCReuseEnabled returns whether the driver of the given SparkApplication reuses the persistent volume claims of deleted executors.
+func IsPVCReuseEnabled(app *v1beta2.SparkApplication) bool {
+	reuse, _ := strconv.ParseBool(app.Spec.SparkConf[common.SparkKubernetesDriverReusePersistentVolumeClaim])
+	return reuse
 }
 
 func GetResourceLabels(app *v1beta2.SparkApplication) map[string]string {
@@ -216,6 +606,15 @@ func GetWebUIServiceType(app *v1beta2.SparkApplication) corev1.ServiceType {
 
 func GetWebUIIngressAnnotations(app *v1beta2.SparkApplication) map[string]string {
 	annotations := map[string]string{}
+	if basicAuth := GetWebUIBasicAuth(app); basicAuth != nil {
+		realm := common.DefaultSparkUIAuthRealm
+		if basicAuth.Realm != nil {
+			realm = *basicAuth.Realm
+		}
+		annotations["nginx.ingress.kubernetes.io/auth-type"] = "basic"
+		annotations["nginx.ingress.kubernetes.io/auth-secret"] = naming.UIAuthSecretName(app)
+		annotations["nginx.ingress.kubernetes.io/auth-realm"] = realm
+	}
 	if app.Spec.SparkUIOptions != nil && app.Spec.SparkUIOptions.IngressAnnotations != nil {
 		for key, value := range app.Spec.SparkUIOptions.IngressAnnotations {
 			annotations[key] = value
@@ -224,6 +623,44 @@ func GetWebUIIngressAnnotations(app *v1beta2.SparkApplication) map[string]string
 	return annotations
 }
 
+// GetWebUIBasicAuth returns the basic authentication configuration of the web UI of the given SparkApplication, if any.
+func GetWebUIBasicAuth(app *v1beta2.SparkApplication) *v1beta2.SparkUIBasicAuth {
+	if app.Spec.SparkUIOptions == nil || app.Spec.SparkUIOptions.Auth == nil {
+		return nil
+	}
+	return app.Spec.SparkUIOptions.Auth.BasicAuth
+}
+
+// GetWebUIOAuth2Proxy returns the oauth2-proxy configuration of the web UI of the given SparkApplication, if any.
+func GetWebUIOAuth2Proxy(app *v1beta2.SparkApplication) *v1beta2.SparkUIOAuth2Proxy {
+	if app.Spec.SparkUIOptions == nil || app.Spec.SparkUIOptions.Auth == nil {
+		return nil
+	}
+	return app.Spec.SparkUIOptions.Auth.OAuth2Proxy
+}
+
+// GetWebUIOAuth2ProxyPort returns the port the oauth2-proxy sidecar in front of the web UI listens on.
+func GetWebUIOAuth2ProxyPort(proxy *v1beta2.SparkUIOAuth2Proxy) int32 {
+	if proxy.Port != nil {
+		return *proxy.Port
+	}
+	return common.DefaultSparkUIOAuth2ProxyPort
+}
+
+// GetWebUIPort returns the port the web UI of the given SparkApplication listens on, taken from Spec.NetworkPorts or
+// the configuration property spark.ui.port in Spec.SparkConf if present, otherwise the default port.
+// Note that the port is not taken from Spec.SparkConfigMap.
+func GetWebUIPort(app *v1beta2.SparkApplication) int32 {
+	if app.Spec.NetworkPorts != nil && app.Spec.NetworkPorts.UIPort != nil {
+		return *app.Spec.NetworkPorts.UIPort
+	}
+	port, err := strconv.Atoi(app.Spec.SparkConf[common.SparkUIPortKey])
+	if err != nil {
+		return common.DefaultSparkWebUIPort
+	}
+	return int32(port)
+}
+
 func GetWebUIIngressTLS(app *v1beta2.SparkApplication) []networkingv1.IngressTLS {
 	ingressTLSs := []networkingv1.IngressTLS{}
 	if app.Spec.SparkUIOptions != nil && app.Spec.SparkUIOptions.IngressTLS != nil {
@@ -234,7 +671,12 @@ func GetWebUIIngressTLS(app *v1beta2.SparkApplication) []networkingv1.IngressTLS
 
 // GetPrometheusConfigMapName returns the name of the ConfigMap for Prometheus configuration.
 func GetPrometheusConfigMapName(app *v1beta2.SparkApplication) string {
-	return fmt.Sprintf("%s-%s", app.Name, common.PrometheusConfigMapNameSuffix)
+	return naming.PrometheusConfigMapName(app)
+}
+
+// DriverServicePublishNotReadyAddresses returns if the driver headless service should publish not-ready addresses.
+func DriverServicePublishNotReadyAddresses(app *v1beta2.SparkApplication) bool {
+	return app.Spec.Driver.ServicePublishNotReadyAddresses != nil && *app.Spec.Driver.ServicePublishNotReadyAddresses
 }
 
 // PrometheusMonitoringEnabled returns if Prometheus monitoring is enabled or not.
@@ -268,6 +710,11 @@ func ExposeDriverMetrics(app *v1beta2.SparkApplication) bool {
 	return app.Spec.Monitoring != nil && app.Spec.Monitoring.ExposeDriverMetrics
 }
 
+// DriverMetricsServiceEnabled returns if a Service exposing the metrics port of the driver should be created.
+func DriverMetricsServiceEnabled(app *v1beta2.SparkApplication) bool {
+	return PrometheusMonitoringEnabled(app) && ExposeDriverMetrics(app) && app.Spec.Monitoring.DriverMetricsService != nil
+}
+
 // ExposeExecutorMetrics returns if executor metrics should be exposed.
 func ExposeExecutorMetrics(app *v1beta2.SparkApplication) bool {
 	return app.Spec.Monitoring != nil && app.Spec.Monitoring.ExposeExecutorMetrics
@@ -291,6 +738,7 @@ func GetDriverState(pod *corev1.Pod) v1beta2.DriverState {
 	case corev1.PodPending:
 		return v1beta2.DriverStatePending
 	case corev1.PodRunning:
+		// Sidecar containers may keep the pod running after the driver container has terminated.
 		state := GetDriverContainerTerminatedState(pod)
 		if state != nil {
 			if state.ExitCode == 0 {
@@ -318,6 +766,14 @@ func GetExecutorState(pod *corev1.Pod) v1beta2.ExecutorState {
 	case corev1.PodPending:
 		return v1beta2.ExecutorStatePending
 	case corev1.PodRunning:
+		// Sidecar containers may keep the pod running after the executor container has terminated.
+		state := GetExecutorContainerTerminatedState(pod)
+		if state != nil {
+			if state.ExitCode == 0 {
+				return v1beta2.ExecutorStateCompleted
+			}
+			return v1beta2.ExecutorStateFailed
+		}
 		return v1beta2.ExecutorStateRunning
 	case corev1.PodSucceeded:
 		return v1beta2.ExecutorStateCompleted
@@ -355,6 +811,28 @@ func GetContainerTerminatedState(pod *corev1.Pod, container string) *corev1.Cont
 	return nil
 }
 
+// ShouldTerminateDriverSidecars returns whether the driver pod is to be deleted once the driver container has
+// terminated while sidecar containers keep it running.
+func ShouldTerminateDriverSidecars(app *v1beta2.SparkApplication) bool {
+	return app.Spec.Driver.TerminateSidecars != nil && *app.Spec.Driver.TerminateSidecars
+}
+
+// GetNativeSidecars returns the sidecars of the given driver or executor spec as native sidecar containers, i.e. init
+// containers with restart policy Always, or nil if native sidecars are not enabled for the spec.
+func GetNativeSidecars(podSpec *v1beta2.SparkPodSpec) []corev1.Container {
+	if podSpec.NativeSidecars == nil || !*podSpec.NativeSidecars {
+		return nil
+	}
+	restartPolicy := corev1.ContainerRestartPolicyAlways
+	sidecars := make([]corev1.Container, 0, len(podSpec.Sidecars))
+	for _, sidecar := range podSpec.Sidecars {
+		container := *sidecar.DeepCopy()
+		container.RestartPolicy = &restartPolicy
+		sidecars = append(sidecars, container)
+	}
+	return sidecars
+}
+
 // IsDriverTerminated returns whether the driver state is a terminated state.
 func IsDriverTerminated(driverState v1beta2.DriverState) bool {
 	return driverState == v1beta2.DriverStateCompleted || driverState == v1beta2.DriverStateFailed
@@ -365,6 +843,23 @@ func IsExecutorTerminated(executorState v1beta2.ExecutorState) bool {
 	return executorState == v1beta2.ExecutorStateCompleted || executorState == v1beta2.ExecutorStateFailed
 }
 
+// GetRunningExecutorCountByZone returns the number of running executors of the application by zone.
+// Executors placed on nodes without zone are counted under "Unknown".
+func GetRunningExecutorCountByZone(app *v1beta2.SparkApplication) map[string]int {
+	counts := make(map[string]int)
+	for name, state := range app.Status.ExecutorState {
+		if state != v1beta2.ExecutorStateRunning {
+			continue
+		}
+		zone := app.Status.ExecutorPlacement[name].Zone
+		if zone == "" {
+			zone = "Unknown"
+		}
+		counts[zone]++
+	}
+	return counts
+}
+
 // DriverStateToApplicationState converts driver state to application state.
 func DriverStateToApplicationState(driverState v1beta2.DriverState) v1beta2.ApplicationStateType {
 	switch driverState {
@@ -416,6 +911,13 @@ func GetDriverRequestResource(app *v1beta2.SparkApplication) corev1.ResourceList
 		}
 	}
 
+	// EphemeralStorage correspond to driver's ephemeral storage request
+	if requests, _, err := GetEphemeralStorageResources(&app.Spec.Driver.SparkPodSpec); err == nil {
+		for name, quantity := range requests {
+			minResource[name] = quantity
+		}
+	}
+
 	return minResource
 }
 
@@ -463,6 +965,13 @@ func GetExecutorRequestResource(app *v1beta2.SparkApplication) corev1.ResourceLi
 		}
 	}
 
+	// EphemeralStorage correspond to executor's ephemeral storage request
+	if requests, _, err := GetEphemeralStorageResources(&app.Spec.Executor.SparkPodSpec); err == nil {
+		for name, quantity := range requests {
+			minResource[name] = quantity
+		}
+	}
+
 	resourceList := []corev1.ResourceList{{}}
 	for i := int32(0); i < *app.Spec.Executor.Instances; i++ {
 		resourceList = append(resourceList, minResource)
@@ -495,3 +1004,397 @@ func GetInitialExecutorNumber(app *v1beta2.SparkApplication) int32 {
 
 	return initialNumExecutors
 }
+
+// GetNetworkPortConf returns the Spark configuration properties of the network ports set in the spec of the
+// SparkApplication.
+func GetNetworkPortConf(app *v1beta2.SparkApplication) map[string]int32 {
+	conf := make(map[string]int32)
+	ports := app.Spec.NetworkPorts
+	if ports == nil {
+		return conf
+	}
+	if ports.DriverPort != nil {
+		conf[common.SparkDriverPort] = *ports.DriverPort
+	}
+	if ports.DriverBlockManagerPort != nil {
+		conf[common.SparkDriverBlockManagerPort] = *ports.DriverBlockManagerPort
+	}
+	if ports.BlockManagerPort != nil {
+		conf[common.SparkBlockManagerPort] = *ports.BlockManagerPort
+	}
+	if ports.UIPort != nil {
+		conf[common.SparkUIPortKey] = *ports.UIPort
+	}
+	return conf
+}
+
+// ValidateNetworkPorts returns an error if the network ports set in the spec of the SparkApplication conflict with
+// the corresponding properties in SparkConf, or if the driver would listen on the same port more than once.
+func ValidateNetworkPorts(app *v1beta2.SparkApplication) error {
+	if app.Spec.NetworkPorts == nil {
+		return nil
+	}
+
+	conf := GetNetworkPortConf(app)
+	for key, port := range conf {
+		if value, ok := app.Spec.SparkConf[key]; ok && value != strconv.Itoa(int(port)) {
+			return fmt.Errorf("port %d conflicts with %s=%s in sparkConf", port, key, value)
+		}
+	}
+
+	getPort := func(defaultPort int32, keys ...string) int32 {
+		for _, key := range keys {
+			if port, ok := conf[key]; ok {
+				return port
+			}
+			if port, err := strconv.ParseInt(app.Spec.SparkConf[key], 10, 32); err == nil {
+				return int32(port)
+			}
+		}
+		return defaultPort
+	}
+	driverPorts := map[string]int32{
+		common.SparkDriverPort:             getPort(common.DefaultSparkDriverPort, common.SparkDriverPort),
+		common.SparkDriverBlockManagerPort: getPort(common.DefaultSparkBlockManagerPort, common.SparkDriverBlockManagerPort, common.SparkBlockManagerPort),
+		common.SparkUIPortKey:              getPort(common.DefaultSparkWebUIPort, common.SparkUIPortKey),
+	}
+	for _, port := range app.Spec.Driver.Ports {
+		driverPorts[fmt.Sprintf("driver port %s", port.Name)] = port.ContainerPort
+	}
+
+	names := make([]string, 0, len(driverPorts))
+	for name := range driverPorts {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	used := make(map[int32]string)
+	for _, name := range names {
+		port := driverPorts[name]
+		if other, ok := used[port]; ok {
+			return fmt.Errorf("%s and %s of the driver use the same port %d", other, name, port)
+		}
+		used[port] = name
+	}
+	return nil
+}
+
+// GetEphemeralStorageResources returns the ephemeral storage requests and limits of the main Spark container of the
+// driver or executor pods with the given spec.
+func GetEphemeralStorageResources(podSpec *v1beta2.SparkPodSpec) (corev1.ResourceList, corev1.ResourceList, error) {
+	requests := corev1.ResourceList{}
+	limits := corev1.ResourceList{}
+	if podSpec.EphemeralStorage != nil {
+		quantity, err := resource.ParseQuantity(*podSpec.EphemeralStorage)
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to parse ephemeral storage %q: %v", *podSpec.EphemeralStorage, err)
+		}
+		requests[corev1.ResourceEphemeralStorage] = quantity
+	}
+	if podSpec.EphemeralStorageLimit != nil {
+		quantity, err := resource.ParseQuantity(*podSpec.EphemeralStorageLimit)
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to parse ephemeral storage limit %q: %v", *podSpec.EphemeralStorageLimit, err)
+		}
+		limits[corev1.ResourceEphemeralStorage] = quantity
+	}
+	if request, ok := requests[corev1.ResourceEphemeralStorage]; ok {
+		if limit, ok := limits[corev1.ResourceEphemeralStorage]; ok && request.Cmp(limit) > 0 {
+			return nil, nil, fmt.Errorf("ephemeral storage %s exceeds its limit %s", request.String(), limit.String())
+		}
+	}
+	return requests, limits, nil
+}
+
+// SLAPolicy holds the parsed objectives of the SLA of a SparkApplication. Unset objectives are zero.
+type SLAPolicy struct {
+	MaxPendingDuration   time.Duration
+	MaxRunDuration       time.Duration
+	ExpectedCompletionBy cron.Schedule
+}
+
+// ParseSLA parses the given SLA of a SparkApplication.
+func ParseSLA(sla *v1beta2.SLASpec) (*SLAPolicy, error) {
+	policy := &SLAPolicy{}
+	if sla.MaxPendingDuration != nil {
+		duration, err := time.ParseDuration(*sla.MaxPendingDuration)
+		if err != nil || duration <= 0 {
+			return nil, fmt.Errorf("invalid max pending duration %q: must be a positive duration", *sla.MaxPendingDuration)
+		}
+		policy.MaxPendingDuration = duration
+	}
+	if sla.MaxRunDuration != nil {
+		duration, err := time.ParseDuration(*sla.MaxRunDuration)
+		if err != nil || duration <= 0 {
+			return nil, fmt.Errorf("invalid max run duration %q: must be a positive duration", *sla.MaxRunDuration)
+		}
+		policy.MaxRunDuration = duration
+	}
+	if sla.ExpectedCompletionBy != nil {
+		schedule, err := cron.ParseStandard(*sla.ExpectedCompletionBy)
+		if err != nil {
+			return nil, fmt.Errorf("invalid expected completion time %q: %v", *sla.ExpectedCompletionBy, err)
+		}
+		policy.ExpectedCompletionBy = schedule
+	}
+	return policy, nil
+}
+
+// GetSLADeadlines returns the deadlines of the SLA objectives of the given SparkApplication that apply to its current
+// state, keyed by the type of the violation missing them causes. The pending time of the application is measured
+// from its creation, or from the end of its previous run or state in case of reruns and invalidation.
+func GetSLADeadlines(app *v1beta2.SparkApplication) (map[v1beta2.SLAViolationType]time.Time, error) {
+	deadlines := make(map[v1beta2.SLAViolationType]time.Time)
+	if app.Spec.SLA == nil {
+		return deadlines, nil
+	}
+	policy, err := ParseSLA(app.Spec.SLA)
+	if err != nil {
+		return nil, err
+	}
+
+	switch app.Status.AppState.State {
+	case v1beta2.ApplicationStateCompleted, v1beta2.ApplicationStateFailed:
+		return deadlines, nil
+	case v1beta2.ApplicationStateNew, v1beta2.ApplicationStateWaitingForDependencies, v1beta2.ApplicationStateSubmitted,
+		v1beta2.ApplicationStateFailedSubmission, v1beta2.ApplicationStatePendingRerun, v1beta2.ApplicationStateInvalidating:
+		if policy.MaxPendingDuration > 0 {
+			deadlines[v1beta2.SLAViolationMaxPendingDuration] = getPendingSince(app).Add(policy.MaxPendingDuration)
+		}
+	case v1beta2.ApplicationStateRunning:
+		condition := meta.FindStatusCondition(app.Status.Conditions, string(v1beta2.SparkApplicationConditionRunning))
+		if policy.MaxRunDuration > 0 && condition != nil && condition.Status == metav1.ConditionTrue {
+			deadlines[v1beta2.SLAViolationMaxRunDuration] = condition.LastTransitionTime.Add(policy.MaxRunDuration)
+		}
+	}
+
+	if policy.ExpectedCompletionBy != nil {
+		deadlines[v1beta2.SLAViolationExpectedCompletionBy] = policy.ExpectedCompletionBy.Next(app.CreationTimestamp.Time)
+	}
+	return deadlines, nil
+}
+
+// getPendingSince returns the time since which the given SparkApplication is pending, which is the latest of its
+// creation and the last transitions of its Running, Complete and Failed conditions.
+func getPendingSince(app *v1beta2.SparkApplication) time.Time {
+	since := app.CreationTimestamp.Time
+	for _, conditionType := range []v1beta2.SparkApplicationConditionType{
+		v1beta2.SparkApplicationConditionRunning,
+		v1beta2.SparkApplicationConditionComplete,
+		v1beta2.SparkApplicationConditionFailed,
+	} {
+		condition := meta.FindStatusCondition(app.Status.Conditions, string(conditionType))
+		if condition != nil && condition.LastTransitionTime.After(since) {
+			since = condition.LastTransitionTime.Time
+		}
+	}
+	return since
+}
+
+// GetExecutorLaunchRateLimitConf returns the Spark configuration properties pacing the creation of executor pods
+// according to the executor launch rate limit of the given SparkApplication. Properties already set in the Spark
+// configuration of the application are not returned.
+func GetExecutorLaunchRateLimitConf(app *v1beta2.SparkApplication) (map[string]string, error) {
+	conf := make(map[string]string)
+	limit := app.Spec.Executor.LaunchRateLimit
+	if limit == nil {
+		return conf, nil
+	}
+	if limit.BatchSize != nil {
+		if *limit.BatchSize < 1 {
+			return nil, fmt.Errorf("invalid executor launch batch size %d: must be positive", *limit.BatchSize)
+		}
+		if _, ok := app.Spec.SparkConf[common.SparkKubernetesAllocationBatchSize]; !ok {
+			conf[common.SparkKubernetesAllocationBatchSize] = strconv.Itoa(int(*limit.BatchSize))
+		}
+	}
+	if limit.BatchDelay != nil {
+		delay, err := time.ParseDuration(*limit.BatchDelay)
+		if err != nil || delay < time.Millisecond {
+			return nil, fmt.Errorf("invalid executor launch batch delay %q: must be a duration of at least 1ms", *limit.BatchDelay)
+		}
+		if _, ok := app.Spec.SparkConf[common.SparkKubernetesAllocationBatchDelay]; !ok {
+			conf[common.SparkKubernetesAllocationBatchDelay] = fmt.Sprintf("%dms", delay.Milliseconds())
+		}
+	}
+	return conf, nil
+}
+
+// GetExecutorQuotaFailurePolicyConf returns the Spark configuration properties making the driver of the given
+// SparkApplication either schedule tasks on the executors it has right away or wait for all requested executors,
+// according to its executor quota failure policy. Properties already set in the Spark configuration of the
+// application are not returned.
+func GetExecutorQuotaFailurePolicyConf(app *v1beta2.SparkApplication) (map[string]string, error) {
+	conf := make(map[string]string)
+	policy := app.Spec.Executor.QuotaFailurePolicy
+	if policy == nil {
+		return conf, nil
+	}
+
+	var ratio, waitingTime string
+	switch policy.Action {
+	case v1beta2.ExecutorQuotaFailureActionContinue, v1beta2.ExecutorQuotaFailureActionFail:
+		ratio = "0.0"
+	case v1beta2.ExecutorQuotaFailureActionWait:
+		ratio = "1.0"
+		// Spark waits for at most 30s by default, so waiting indefinitely is approximated by the maximum duration.
+		waitingTime = fmt.Sprintf("%ds", math.MaxInt32)
+		if policy.WaitTimeout != nil {
+			timeout, err := time.ParseDuration(*policy.WaitTimeout)
+			if err != nil || timeout < time.Millisecond {
+				return nil, fmt.Errorf("invalid executor quota wait timeout %q: must be a duration of at least 1ms", *policy.WaitTimeout)
+			}
+			waitingTime = fmt.Sprintf("%dms", timeout.Milliseconds())
+		}
+	default:
+		return nil, fmt.Errorf("invalid executor quota failure action %q", policy.Action)
+	}
+	if policy.WaitTimeout != nil && policy.Action != v1beta2.ExecutorQuotaFailureActionWait {
+		return nil, fmt.Errorf("executor quota wait timeout is only supported with the %s action", v1beta2.ExecutorQuotaFailureActionWait)
+	}
+
+	if _, ok := app.Spec.SparkConf[common.SparkSchedulerMinRegisteredResourcesRatio]; !ok {
+		conf[common.SparkSchedulerMinRegisteredResourcesRatio] = ratio
+	}
+	if _, ok := app.Spec.SparkConf[common.SparkSchedulerMaxRegisteredResourcesWaitingTime]; !ok && waitingTime != "" {
+		conf[common.SparkSchedulerMaxRegisteredResourcesWaitingTime] = waitingTime
+	}
+	return conf, nil
+}
+
+// GetRequiredExecutorNumber returns the number of executors the given SparkApplication requires to be pending or
+// running, which is the minimum number of executors with dynamic allocation and the number of instances otherwise.
+func GetRequiredExecutorNumber(app *v1beta2.SparkApplication) int32 {
+	if app.Spec.DynamicAllocation != nil && app.Spec.DynamicAllocation.Enabled {
+		if app.Spec.DynamicAllocation.MinExecutors != nil {
+			return *app.Spec.DynamicAllocation.MinExecutors
+		}
+		return 0
+	}
+	return GetInitialExecutorNumber(app)
+}
+
+// GetPodGroupPriorityClassName returns the name of the PriorityClass of the pod group of the given SparkApplication
+// created by batch schedulers, which defaults to the PriorityClass of the driver, as the driver is scheduled first
+// and the executors only exist once it runs.
+func GetPodGroupPriorityClassName(app *v1beta2.SparkApplication) *string {
+	if options := app.Spec.BatchSchedulerOptions; options != nil && options.PriorityClassName != nil && *options.PriorityClassName != "" {
+		return options.PriorityClassName
+	}
+	if app.Spec.Driver.PriorityClassName != nil && *app.Spec.Driver.PriorityClassName != "" {
+		return app.Spec.Driver.PriorityClassName
+	}
+	return nil
+}
+
+// GetReferencedObjects returns the Secrets, ConfigMaps and service accounts in its namespace the given
+// SparkApplication needs to be submitted, i.e. those it references without marking them optional.
+func GetReferencedObjects(app *v1beta2.SparkApplication) []corev1.ObjectReference {
+	var references []corev1.ObjectReference
+	add := func(kind string, name string) {
+		reference := corev1.ObjectReference{Kind: kind, Name: name}
+		if name != "" && !slices.Contains(references, reference) {
+			references = append(references, reference)
+		}
+	}
+
+	if app.Spec.SparkConfigMap != nil {
+		add("ConfigMap", *app.Spec.SparkConfigMap)
+	}
+	if app.Spec.HadoopConfigMap != nil {
+		add("ConfigMap", *app.Spec.HadoopConfigMap)
+	}
+	for _, secret := range app.Spec.ImagePullSecrets {
+		add("Secret", secret)
+	}
+	for _, volume := range app.Spec.Volumes {
+		if secret := volume.Secret; secret != nil && !ptr.Deref(secret.Optional, false) {
+			add("Secret", secret.SecretName)
+		}
+		if configMap := volume.ConfigMap; configMap != nil && !ptr.Deref(configMap.Optional, false) {
+			add("ConfigMap", configMap.Name)
+		}
+	}
+
+	for _, podSpec := range []*v1beta2.SparkPodSpec{&app.Spec.Driver.SparkPodSpec, &app.Spec.Executor.SparkPodSpec} {
+		if podSpec.ServiceAccount != nil {
+			add("ServiceAccount", *podSpec.ServiceAccount)
+		}
+		if podSpec.TemplateConfigMap != nil {
+			add("ConfigMap", podSpec.TemplateConfigMap.Name)
+		}
+		for _, configMap := range podSpec.ConfigMaps {
+			add("ConfigMap", configMap.Name)
+		}
+		for _, secret := range podSpec.Secrets {
+			add("Secret", secret.Name)
+		}
+		for _, name := range slices.Sorted(maps.Keys(podSpec.EnvSecretKeyRefs)) {
+			add("Secret", podSpec.EnvSecretKeyRefs[name].Name)
+		}
+		for _, env := range podSpec.Env {
+			if env.ValueFrom == nil {
+				continue
+			}
+			if ref := env.ValueFrom.SecretKeyRef; ref != nil && !ptr.Deref(ref.Optional, false) {
+				add("Secret", ref.Name)
+			}
+			if ref := env.ValueFrom.ConfigMapKeyRef; ref != nil && !ptr.Deref(ref.Optional, false) {
+				add("ConfigMap", ref.Name)
+			}
+		}
+		for _, envFrom := range podSpec.EnvFrom {
+			if ref := envFrom.SecretRef; ref != nil && !ptr.Deref(ref.Optional, false) {
+				add("Secret", ref.Name)
+			}
+			if ref := envFrom.ConfigMapRef; ref != nil && !ptr.Deref(ref.Optional, false) {
+				add("ConfigMap", ref.Name)
+			}
+		}
+	}
+	return references
+}
+
+// GetDynamicAllocationTimeoutConf returns the Spark configuration properties of the timeouts scaling executors up
+// and down according to the given dynamic allocation spec.
+func GetDynamicAllocationTimeoutConf(dynamicAllocation *v1beta2.DynamicAllocation) (map[string]string, error) {
+	conf := make(map[string]string)
+	if dynamicAllocation == nil {
+		return conf, nil
+	}
+	timeouts := []struct {
+		name  string
+		key   string
+		value *string
+	}{
+		{"executorIdleTimeout", common.SparkDynamicAllocationExecutorIdleTimeout, dynamicAllocation.ExecutorIdleTimeout},
+		{"cachedExecutorIdleTimeout", common.SparkDynamicAllocationCachedExecutorIdleTimeout, dynamicAllocation.CachedExecutorIdleTimeout},
+		{"schedulerBacklogTimeout", common.SparkDynamicAllocationSchedulerBacklogTimeout, dynamicAllocation.SchedulerBacklogTimeout},
+	}
+	for _, timeout := range timeouts {
+		if timeout.value == nil {
+			continue
+		}
+		duration, err := time.ParseDuration(*timeout.value)
+		if err != nil || duration < time.Millisecond {
+			return nil, fmt.Errorf("invalid dynamic allocation %s %q: must be a duration of at least 1ms", timeout.name, *timeout.value)
+		}
+		conf[timeout.key] = fmt.Sprintf("%dms", duration.Milliseconds())
+	}
+	return conf, nil
+}
+
+// GetExecutorAllocationBatchSize returns the number of executor pods the driver of the given SparkApplication creates
+// in each round of executor allocation.
+func GetExecutorAllocationBatchSize(app *v1beta2.SparkApplication) (int, error) {
+	if value, ok := app.Spec.SparkConf[common.SparkKubernetesAllocationBatchSize]; ok {
+		size, err := strconv.Atoi(value)
+		if err != nil {
+			return 0, fmt.Errorf("invalid %s %q: %v", common.SparkKubernetesAllocationBatchSize, value, err)
+		}
+		return size, nil
+	}
+	if limit := app.Spec.Executor.LaunchRateLimit; limit != nil && limit.BatchSize != nil {
+		return int(*limit.BatchSize), nil
+	}
+	return common.DefaultSparkKubernetesAllocationBatchSize, nil
+}
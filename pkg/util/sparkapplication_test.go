@@ -17,13 +17,16 @@ limitations under the License.
 package util_test
 
 import (
+	"strings"
 	"time"
 
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
 
 	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/ptr"
 
 	"github.com/kubeflow/spark-operator/api/v1beta2"
 	"github.com/kubeflow/spark-operator/pkg/common"
@@ -122,6 +125,37 @@ var _ = Describe("GetApplicationState", func() {
 	})
 })
 
+var _ = Describe("GetCorrelationID", func() {
+	Context("SparkApplication not submitted yet", func() {
+		app := &v1beta2.SparkApplication{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "test-app",
+				Namespace: "test-namespace",
+			},
+		}
+
+		It("Should return the namespace and name of the application", func() {
+			Expect(util.GetCorrelationID(app)).To(Equal("test-namespace/test-app"))
+		})
+	})
+
+	Context("Submitted SparkApplication", func() {
+		app := &v1beta2.SparkApplication{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "test-app",
+				Namespace: "test-namespace",
+			},
+			Status: v1beta2.SparkApplicationStatus{
+				SubmissionID: "test-submission-id",
+			},
+		}
+
+		It("Should return the namespace, name and submission ID of the application", func() {
+			Expect(util.GetCorrelationID(app)).To(Equal("test-namespace/test-app/test-submission-id"))
+		})
+	})
+})
+
 var _ = Describe("IsExpired", func() {
 	Context("SparkApplication without TTL", func() {
 		app := &v1beta2.SparkApplication{
@@ -223,6 +257,71 @@ var _ = Describe("IsDriverRunning", func() {
 	})
 })
 
+var _ = Describe("UpdateConditions", func() {
+	It("Should mark a completed application as complete", func() {
+		app := &v1beta2.SparkApplication{
+			ObjectMeta: metav1.ObjectMeta{Generation: 2},
+			Status: v1beta2.SparkApplicationStatus{
+				AppState: v1beta2.ApplicationState{State: v1beta2.ApplicationStateCompleted},
+			},
+		}
+		util.UpdateConditions(app)
+		Expect(app.Status.ObservedGeneration).To(Equal(int64(2)))
+		Expect(meta.IsStatusConditionTrue(app.Status.Conditions, string(v1beta2.SparkApplicationConditionSubmitted))).To(BeTrue())
+		Expect(meta.IsStatusConditionTrue(app.Status.Conditions, string(v1beta2.SparkApplicationConditionComplete))).To(BeTrue())
+		Expect(meta.IsStatusConditionFalse(app.Status.Conditions, string(v1beta2.SparkApplicationConditionFailed))).To(BeTrue())
+	})
+
+	It("Should mark a failed application as failed with the error message", func() {
+		app := &v1beta2.SparkApplication{
+			Status: v1beta2.SparkApplicationStatus{
+				AppState: v1beta2.ApplicationState{
+					State:        v1beta2.ApplicationStateFailed,
+					ErrorMessage: "driver container failed with ExitCode: 1",
+				},
+			},
+		}
+		util.UpdateConditions(app)
+		condition := meta.FindStatusCondition(app.Status.Conditions, string(v1beta2.SparkApplicationConditionFailed))
+		Expect(condition).NotTo(BeNil())
+		Expect(condition.Status).To(Equal(metav1.ConditionTrue))
+		Expect(condition.Reason).To(Equal("Failed"))
+		Expect(condition.Message).To(Equal("driver container failed with ExitCode: 1"))
+	})
+
+	It("Should truncate an oversized error message to the end", func() {
+		message := strings.Repeat("at org.apache.spark.deploy.SparkSubmit\n", 2000) + "Exception in thread main"
+		app := &v1beta2.SparkApplication{
+			Status: v1beta2.SparkApplicationStatus{
+				AppState: v1beta2.ApplicationState{
+					State:        v1beta2.ApplicationStateFailedSubmission,
+					ErrorMessage: message,
+				},
+			},
+		}
+		util.UpdateConditions(app)
+		condition := meta.FindStatusCondition(app.Status.Conditions, string(v1beta2.SparkApplicationConditionSubmitted))
+		Expect(condition).NotTo(BeNil())
+		Expect(len(condition.Message)).To(Equal(32768))
+		Expect(condition.Message).To(HavePrefix("...(truncated) "))
+		Expect(condition.Message).To(HaveSuffix("Exception in thread main"))
+		Expect(app.Status.AppState.ErrorMessage).To(Equal(message))
+	})
+
+	It("Should mark a failed submission as not submitted", func() {
+		app := &v1beta2.SparkApplication{
+			Status: v1beta2.SparkApplicationStatus{
+				AppState: v1beta2.ApplicationState{State: v1beta2.ApplicationStateFailedSubmission},
+			},
+		}
+		util.UpdateConditions(app)
+		condition := meta.FindStatusCondition(app.Status.Conditions, string(v1beta2.SparkApplicationConditionSubmitted))
+		Expect(condition).NotTo(BeNil())
+		Expect(condition.Status).To(Equal(metav1.ConditionFalse))
+		Expect(condition.Reason).To(Equal("SubmissionFailed"))
+	})
+})
+
 var _ = Describe("GetLocalVolumes", func() {
 	Context("SparkApplication with local volumes", func() {
 		volume1 := corev1.Volume{
@@ -273,6 +372,204 @@ var _ = Describe("GetLocalVolumes", func() {
 	})
 })
 
+var _ = Describe("ParseMainApplicationFileConfigMap", func() {
+	It("Should return the ConfigMap name and key", func() {
+		name, key, err := util.ParseMainApplicationFileConfigMap("configmap://my-scripts/pi.py")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(name).To(Equal("my-scripts"))
+		Expect(key).To(Equal("pi.py"))
+	})
+
+	It("Should return an error if the key is missing", func() {
+		_, _, err := util.ParseMainApplicationFileConfigMap("configmap://my-scripts")
+		Expect(err).To(HaveOccurred())
+	})
+
+	It("Should return an error if the scheme is not configmap", func() {
+		_, _, err := util.ParseMainApplicationFileConfigMap("local:///opt/spark/pi.py")
+		Expect(err).To(HaveOccurred())
+	})
+})
+
+var _ = Describe("GetMainApplicationFile", func() {
+	It("Should return the main application file as is", func() {
+		app := &v1beta2.SparkApplication{
+			Spec: v1beta2.SparkApplicationSpec{
+				MainApplicationFile: util.StringPtr("local:///opt/spark/examples/src/main/python/pi.py"),
+			},
+		}
+		file, err := util.GetMainApplicationFile(app)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(file).To(Equal("local:///opt/spark/examples/src/main/python/pi.py"))
+	})
+
+	It("Should rewrite the main application file embedded in a ConfigMap to the mount path", func() {
+		app := &v1beta2.SparkApplication{
+			Spec: v1beta2.SparkApplicationSpec{
+				MainApplicationFile: util.StringPtr("configmap://my-scripts/pi.py"),
+			},
+		}
+		file, err := util.GetMainApplicationFile(app)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(file).To(Equal("local://" + common.DefaultMainApplicationFileMountPath + "/pi.py"))
+	})
+
+	It("Should rewrite the main application file in an OCI artifact to the pull path", func() {
+		app := &v1beta2.SparkApplication{
+			Spec: v1beta2.SparkApplicationSpec{
+				MainApplicationFile: util.StringPtr("oci://ghcr.io/org/app:v1/pi.py"),
+			},
+		}
+		file, err := util.GetMainApplicationFile(app)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(file).To(Equal("local://" + util.GetOCIArtifactDir("ghcr.io/org/app:v1") + "/pi.py"))
+	})
+})
+
+var _ = Describe("GetInjectedDefaults", func() {
+	It("Should return nil if the driver pod records no defaults", func() {
+		Expect(util.GetInjectedDefaults(&corev1.Pod{})).To(BeNil())
+	})
+
+	It("Should return the applied and suppressed defaults recorded on the driver pod", func() {
+		pod := &corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{
+				Annotations: map[string]string{
+					common.AnnotationAppliedDefaults:    "metadata,tolerations",
+					common.AnnotationSuppressedDefaults: "sidecars",
+				},
+			},
+		}
+		Expect(util.GetInjectedDefaults(pod)).To(Equal(&v1beta2.InjectedDefaultsStatus{
+			Applied:    []v1beta2.InjectedDefault{v1beta2.InjectedDefaultMetadata, v1beta2.InjectedDefaultTolerations},
+			Suppressed: []v1beta2.InjectedDefault{v1beta2.InjectedDefaultSidecars},
+		}))
+	})
+})
+
+var _ = Describe("GetMainApplicationFileConfigMap", func() {
+	It("Should return the generated ConfigMap for inline sources", func() {
+		app := &v1beta2.SparkApplication{
+			ObjectMeta: metav1.ObjectMeta{Name: "test-app"},
+			Spec: v1beta2.SparkApplicationSpec{
+				Type:                  v1beta2.SparkApplicationTypeR,
+				MainApplicationSource: &v1beta2.MainApplicationSource{Inline: util.StringPtr("print('hello')")},
+			},
+		}
+		name, key, err := util.GetMainApplicationFileConfigMap(app)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(name).To(Equal("test-app-main-source"))
+		Expect(key).To(Equal("main.R"))
+
+		file, err := util.GetMainApplicationFile(app)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(file).To(Equal("local://" + common.DefaultMainApplicationFileMountPath + "/main.R"))
+	})
+
+	It("Should return the referenced ConfigMap", func() {
+		app := &v1beta2.SparkApplication{
+			Spec: v1beta2.SparkApplicationSpec{
+				MainApplicationFile: util.StringPtr("configmap://my-scripts/pi.py"),
+			},
+		}
+		name, key, err := util.GetMainApplicationFileConfigMap(app)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(name).To(Equal("my-scripts"))
+		Expect(key).To(Equal("pi.py"))
+	})
+})
+
+var _ = Describe("ParseOCIArtifact", func() {
+	It("Should return the reference and the file of a tagged artifact", func() {
+		reference, file, err := util.ParseOCIArtifact("oci://localhost:5000/org/app:v1/app.jar")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(reference).To(Equal("localhost:5000/org/app:v1"))
+		Expect(file).To(Equal("app.jar"))
+	})
+
+	It("Should return the reference and the file of an artifact pinned by digest", func() {
+		reference, file, err := util.ParseOCIArtifact("oci://ghcr.io/org/app@sha256:abc/app.jar")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(reference).To(Equal("ghcr.io/org/app@sha256:abc"))
+		Expect(file).To(Equal("app.jar"))
+	})
+
+	It("Should return an error if the tag is missing", func() {
+		_, _, err := util.ParseOCIArtifact("oci://localhost:5000/org/app/app.jar")
+		Expect(err).To(HaveOccurred())
+	})
+
+	It("Should return an error if the file is missing", func() {
+		_, _, err := util.ParseOCIArtifact("oci://ghcr.io/org/app:v1/")
+		Expect(err).To(HaveOccurred())
+	})
+})
+
+var _ = Describe("GetOCIArtifacts", func() {
+	It("Should return the distinct artifacts referenced by the main application file and the dependencies", func() {
+		app := &v1beta2.SparkApplication{
+			Spec: v1beta2.SparkApplicationSpec{
+				MainApplicationFile: util.StringPtr("oci://ghcr.io/org/app:v1/app.jar"),
+				Deps: v1beta2.Dependencies{
+					Jars:    []string{"oci://ghcr.io/org/app:v1/lib.jar", "local:///opt/spark/jars/other.jar"},
+					PyFiles: []string{"oci://ghcr.io/org/py:v2/deps.zip"},
+				},
+			},
+		}
+		references, err := util.GetOCIArtifacts(app)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(references).To(Equal([]string{"ghcr.io/org/app:v1", "ghcr.io/org/py:v2"}))
+	})
+})
+
+var _ = Describe("GetDriverServiceAccountName", func() {
+	It("Should return the default service account if none is specified", func() {
+		app := &v1beta2.SparkApplication{}
+		Expect(util.GetDriverServiceAccountName(app)).To(Equal("default"))
+	})
+
+	It("Should prefer the driver service account field over the conf", func() {
+		app := &v1beta2.SparkApplication{
+			Spec: v1beta2.SparkApplicationSpec{
+				SparkConf: map[string]string{
+					common.SparkKubernetesAuthenticateDriverServiceAccountName: "conf-sa",
+				},
+				Driver: v1beta2.DriverSpec{
+					SparkPodSpec: v1beta2.SparkPodSpec{
+						ServiceAccount: util.StringPtr("spark"),
+					},
+				},
+			},
+		}
+		Expect(util.GetDriverServiceAccountName(app)).To(Equal("spark"))
+	})
+})
+
+var _ = Describe("IsShuffleTrackingEnabled", func() {
+	It("Should return true if dynamic allocation is enabled in the spec", func() {
+		app := &v1beta2.SparkApplication{
+			Spec: v1beta2.SparkApplicationSpec{
+				DynamicAllocation: &v1beta2.DynamicAllocation{Enabled: true},
+			},
+		}
+		Expect(util.IsShuffleTrackingEnabled(app)).To(BeTrue())
+	})
+
+	It("Should require shuffle tracking to be enabled if dynamic allocation is enabled in the conf", func() {
+		app := &v1beta2.SparkApplication{
+			Spec: v1beta2.SparkApplicationSpec{
+				SparkConf: map[string]string{
+					common.SparkDynamicAllocationEnabled: "true",
+				},
+			},
+		}
+		Expect(util.IsShuffleTrackingEnabled(app)).To(BeFalse())
+
+		app.Spec.SparkConf[common.SparkDynamicAllocationShuffleTrackingEnabled] = "true"
+		Expect(util.IsShuffleTrackingEnabled(app)).To(BeTrue())
+	})
+})
+
 var _ = Describe("GetDefaultUIServiceName", func() {
 	app := &v1beta2.SparkApplication{
 		ObjectMeta: metav1.ObjectMeta{
@@ -327,6 +624,80 @@ var _ = Describe("GetDefaultUIIngressName", func() {
 	})
 })
 
+var _ = Describe("GetDriverState", func() {
+	newDriverPod := func(driverState corev1.ContainerState) *corev1.Pod {
+		return &corev1.Pod{
+			Status: corev1.PodStatus{
+				Phase: corev1.PodRunning,
+				ContainerStatuses: []corev1.ContainerStatus{
+					{Name: common.SparkDriverContainerName, State: driverState},
+					{Name: "istio-proxy", State: corev1.ContainerState{Running: &corev1.ContainerStateRunning{}}},
+				},
+			},
+		}
+	}
+
+	It("Should return running if the driver container is running", func() {
+		pod := newDriverPod(corev1.ContainerState{Running: &corev1.ContainerStateRunning{}})
+		Expect(util.GetDriverState(pod)).To(Equal(v1beta2.DriverStateRunning))
+	})
+
+	It("Should return completed if the driver container has succeeded while sidecars are running", func() {
+		pod := newDriverPod(corev1.ContainerState{Terminated: &corev1.ContainerStateTerminated{ExitCode: 0}})
+		Expect(util.GetDriverState(pod)).To(Equal(v1beta2.DriverStateCompleted))
+	})
+
+	It("Should return failed if the driver container has failed while sidecars are running", func() {
+		pod := newDriverPod(corev1.ContainerState{Terminated: &corev1.ContainerStateTerminated{ExitCode: 1}})
+		Expect(util.GetDriverState(pod)).To(Equal(v1beta2.DriverStateFailed))
+	})
+})
+
+var _ = Describe("GetExecutorState", func() {
+	newExecutorPod := func(executorState corev1.ContainerState) *corev1.Pod {
+		return &corev1.Pod{
+			Status: corev1.PodStatus{
+				Phase: corev1.PodRunning,
+				ContainerStatuses: []corev1.ContainerStatus{
+					{Name: common.SparkExecutorContainerName, State: executorState},
+					{Name: "log-shipper", State: corev1.ContainerState{Running: &corev1.ContainerStateRunning{}}},
+				},
+			},
+		}
+	}
+
+	It("Should return running if the executor container is running", func() {
+		pod := newExecutorPod(corev1.ContainerState{Running: &corev1.ContainerStateRunning{}})
+		Expect(util.GetExecutorState(pod)).To(Equal(v1beta2.ExecutorStateRunning))
+	})
+
+	It("Should return completed if the executor container has succeeded while sidecars are running", func() {
+		pod := newExecutorPod(corev1.ContainerState{Terminated: &corev1.ContainerStateTerminated{ExitCode: 0}})
+		Expect(util.GetExecutorState(pod)).To(Equal(v1beta2.ExecutorStateCompleted))
+	})
+
+	It("Should return failed if the executor container has failed while sidecars are running", func() {
+		pod := newExecutorPod(corev1.ContainerState{Terminated: &corev1.ContainerStateTerminated{ExitCode: 137}})
+		Expect(util.GetExecutorState(pod)).To(Equal(v1beta2.ExecutorStateFailed))
+	})
+})
+
+var _ = Describe("ShouldTerminateDriverSidecars", func() {
+	It("Should return false by default", func() {
+		app := &v1beta2.SparkApplication{}
+		Expect(util.ShouldTerminateDriverSidecars(app)).To(BeFalse())
+	})
+
+	It("Should return true if enabled in the driver spec", func() {
+		app := &v1beta2.SparkApplication{
+			Spec: v1beta2.SparkApplicationSpec{
+				Driver: v1beta2.DriverSpec{TerminateSidecars: ptr.To(true)},
+			},
+		}
+		Expect(util.ShouldTerminateDriverSidecars(app)).To(BeTrue())
+	})
+})
+
 var _ = Describe("IsDriverTerminated", func() {
 	It("Should check whether driver is terminated", func() {
 		Expect(util.IsDriverTerminated(v1beta2.DriverStatePending)).To(BeFalse())
@@ -356,3 +727,424 @@ var _ = Describe("DriverStateToApplicationState", func() {
 		Expect(util.DriverStateToApplicationState(v1beta2.DriverStateUnknown)).To(Equal(v1beta2.ApplicationStateUnknown))
 	})
 })
+
+var _ = Describe("GetRunningExecutorCountByZone", func() {
+	It("Should count running executors by zone", func() {
+		app := &v1beta2.SparkApplication{
+			Status: v1beta2.SparkApplicationStatus{
+				ExecutorState: map[string]v1beta2.ExecutorState{
+					"exec-1": v1beta2.ExecutorStateRunning,
+					"exec-2": v1beta2.ExecutorStateRunning,
+					"exec-3": v1beta2.ExecutorStateRunning,
+					"exec-4": v1beta2.ExecutorStateFailed,
+					"exec-5": v1beta2.ExecutorStatePending,
+				},
+				ExecutorPlacement: map[string]v1beta2.ExecutorPlacement{
+					"exec-1": {NodeName: "node-1", Zone: "zone-a"},
+					"exec-2": {NodeName: "node-2", Zone: "zone-a"},
+					"exec-3": {NodeName: "node-3"},
+					"exec-4": {NodeName: "node-4", Zone: "zone-b"},
+				},
+			},
+		}
+		Expect(util.GetRunningExecutorCountByZone(app)).To(Equal(map[string]int{
+			"zone-a":  2,
+			"Unknown": 1,
+		}))
+	})
+})
+
+var _ = Describe("ValidateNetworkPorts", func() {
+	newApp := func(ports *v1beta2.NetworkPorts, sparkConf map[string]string) *v1beta2.SparkApplication {
+		return &v1beta2.SparkApplication{
+			Spec: v1beta2.SparkApplicationSpec{
+				SparkConf:    sparkConf,
+				NetworkPorts: ports,
+			},
+		}
+	}
+
+	It("Should accept distinct ports", func() {
+		app := newApp(&v1beta2.NetworkPorts{
+			DriverPort:       ptr.To[int32](7078),
+			BlockManagerPort: ptr.To[int32](7079),
+			UIPort:           ptr.To[int32](4040),
+		}, nil)
+		Expect(util.ValidateNetworkPorts(app)).To(Succeed())
+		Expect(util.GetNetworkPortConf(app)).To(Equal(map[string]int32{
+			common.SparkDriverPort:       7078,
+			common.SparkBlockManagerPort: 7079,
+			common.SparkUIPortKey:        4040,
+		}))
+	})
+
+	It("Should reject ports colliding on the driver", func() {
+		app := newApp(&v1beta2.NetworkPorts{
+			DriverPort:             ptr.To[int32](7078),
+			DriverBlockManagerPort: ptr.To[int32](7078),
+		}, nil)
+		Expect(util.ValidateNetworkPorts(app)).To(HaveOccurred())
+
+		app = newApp(&v1beta2.NetworkPorts{UIPort: ptr.To[int32](7079)}, nil)
+		Expect(util.ValidateNetworkPorts(app)).To(HaveOccurred())
+	})
+
+	It("Should reject ports conflicting with sparkConf", func() {
+		app := newApp(&v1beta2.NetworkPorts{UIPort: ptr.To[int32](4041)}, map[string]string{common.SparkUIPortKey: "4040"})
+		Expect(util.ValidateNetworkPorts(app)).To(HaveOccurred())
+
+		app = newApp(&v1beta2.NetworkPorts{UIPort: ptr.To[int32](4041)}, map[string]string{common.SparkUIPortKey: "4041"})
+		Expect(util.ValidateNetworkPorts(app)).To(Succeed())
+	})
+})
+
+var _ = Describe("GetEphemeralStorageResources", func() {
+	It("Should return empty resources if ephemeral storage is not set", func() {
+		requests, limits, err := util.GetEphemeralStorageResources(&v1beta2.SparkPodSpec{})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(requests).To(BeEmpty())
+		Expect(limits).To(BeEmpty())
+	})
+
+	It("Should return the ephemeral storage requests and limits", func() {
+		requests, limits, err := util.GetEphemeralStorageResources(&v1beta2.SparkPodSpec{
+			EphemeralStorage:      ptr.To("10Gi"),
+			EphemeralStorageLimit: ptr.To("20Gi"),
+		})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(requests.StorageEphemeral().String()).To(Equal("10Gi"))
+		Expect(limits.StorageEphemeral().String()).To(Equal("20Gi"))
+	})
+
+	It("Should return an error if the ephemeral storage is invalid or exceeds its limit", func() {
+		_, _, err := util.GetEphemeralStorageResources(&v1beta2.SparkPodSpec{EphemeralStorage: ptr.To("10 GB")})
+		Expect(err).To(HaveOccurred())
+
+		_, _, err = util.GetEphemeralStorageResources(&v1beta2.SparkPodSpec{
+			EphemeralStorage:      ptr.To("20Gi"),
+			EphemeralStorageLimit: ptr.To("10Gi"),
+		})
+		Expect(err).To(HaveOccurred())
+	})
+})
+
+var _ = Describe("GetRetryBackoffInterval", func() {
+	It("Should grow the interval exponentially", func() {
+		backoff := &v1beta2.RetryBackoff{InitialIntervalSeconds: 10}
+		Expect(util.GetRetryBackoffInterval(backoff, 1)).To(Equal(10 * time.Second))
+		Expect(util.GetRetryBackoffInterval(backoff, 3)).To(Equal(40 * time.Second))
+	})
+
+	It("Should cap the interval at the maximum interval", func() {
+		backoff := &v1beta2.RetryBackoff{InitialIntervalSeconds: 10, Multiplier: ptr.To("3"), MaxIntervalSeconds: ptr.To[int64](60)}
+		Expect(util.GetRetryBackoffInterval(backoff, 2)).To(Equal(30 * time.Second))
+		Expect(util.GetRetryBackoffInterval(backoff, 10)).To(Equal(60 * time.Second))
+	})
+
+	It("Should reject a multiplier less than 1", func() {
+		backoff := &v1beta2.RetryBackoff{InitialIntervalSeconds: 10, Multiplier: ptr.To("0.5")}
+		_, err := util.GetRetryBackoffInterval(backoff, 1)
+		Expect(err).To(HaveOccurred())
+	})
+})
+
+var _ = Describe("ShouldRetry", func() {
+	newApp := func(attempts int32) *v1beta2.SparkApplication {
+		return &v1beta2.SparkApplication{
+			Spec: v1beta2.SparkApplicationSpec{
+				RestartPolicy: v1beta2.RestartPolicy{
+					Type: v1beta2.RestartPolicyAlways,
+					Backoff: &v1beta2.RetryBackoff{
+						InitialIntervalSeconds: 10,
+						MaxDurationSeconds:     ptr.To[int64](60),
+					},
+				},
+			},
+			Status: v1beta2.SparkApplicationStatus{
+				AppState:           v1beta2.ApplicationState{State: v1beta2.ApplicationStateFailedSubmission},
+				SubmissionAttempts: attempts,
+			},
+		}
+	}
+
+	It("Should retry within the maximum backoff duration", func() {
+		Expect(util.ShouldRetry(newApp(2))).To(BeTrue())
+	})
+
+	It("Should not retry once the maximum backoff duration is exceeded", func() {
+		Expect(util.ShouldRetry(newApp(3))).To(BeFalse())
+	})
+})
+
+var _ = Describe("TimeUntilNextRetryDue", func() {
+	It("Should use the retry backoff of failed runs", func() {
+		app := &v1beta2.SparkApplication{
+			Spec: v1beta2.SparkApplicationSpec{
+				RestartPolicy: v1beta2.RestartPolicy{
+					Type:    v1beta2.RestartPolicyOnFailure,
+					Backoff: &v1beta2.RetryBackoff{InitialIntervalSeconds: 60},
+				},
+			},
+			Status: v1beta2.SparkApplicationStatus{
+				AppState:                  v1beta2.ApplicationState{State: v1beta2.ApplicationStateFailing},
+				ExecutionAttempts:         3,
+				SubmissionAttempts:        1,
+				LastSubmissionAttemptTime: metav1.Now(),
+			},
+		}
+		duration, err := util.TimeUntilNextRetryDue(app)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(duration).To(BeNumerically("~", 240*time.Second, 5*time.Second))
+	})
+})
+
+var _ = Describe("GetSLADeadlines", func() {
+	created := time.Date(2024, 5, 1, 4, 30, 0, 0, time.UTC)
+	newApp := func(state v1beta2.ApplicationStateType, conditions ...metav1.Condition) *v1beta2.SparkApplication {
+		return &v1beta2.SparkApplication{
+			ObjectMeta: metav1.ObjectMeta{CreationTimestamp: metav1.NewTime(created)},
+			Spec: v1beta2.SparkApplicationSpec{
+				SLA: &v1beta2.SLASpec{
+					MaxPendingDuration:   ptr.To("15m"),
+					MaxRunDuration:       ptr.To("1h"),
+					ExpectedCompletionBy: ptr.To("CRON_TZ=UTC 0 6 * * *"),
+				},
+			},
+			Status: v1beta2.SparkApplicationStatus{
+				AppState:   v1beta2.ApplicationState{State: state},
+				Conditions: conditions,
+			},
+		}
+	}
+
+	It("Should measure the pending time from the end of the previous run", func() {
+		ended := created.Add(time.Hour)
+		app := newApp(v1beta2.ApplicationStatePendingRerun, metav1.Condition{
+			Type:               string(v1beta2.SparkApplicationConditionRunning),
+			Status:             metav1.ConditionFalse,
+			LastTransitionTime: metav1.NewTime(ended),
+		})
+		deadlines, err := util.GetSLADeadlines(app)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(deadlines).To(Equal(map[v1beta2.SLAViolationType]time.Time{
+			v1beta2.SLAViolationMaxPendingDuration:   ended.Add(15 * time.Minute),
+			v1beta2.SLAViolationExpectedCompletionBy: time.Date(2024, 5, 1, 6, 0, 0, 0, time.UTC),
+		}))
+	})
+
+	It("Should measure the run time from the start of the driver", func() {
+		started := created.Add(10 * time.Minute)
+		app := newApp(v1beta2.ApplicationStateRunning, metav1.Condition{
+			Type:               string(v1beta2.SparkApplicationConditionRunning),
+			Status:             metav1.ConditionTrue,
+			LastTransitionTime: metav1.NewTime(started),
+		})
+		deadlines, err := util.GetSLADeadlines(app)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(deadlines).To(HaveKeyWithValue(v1beta2.SLAViolationMaxRunDuration, started.Add(time.Hour)))
+		Expect(deadlines).NotTo(HaveKey(v1beta2.SLAViolationMaxPendingDuration))
+	})
+
+	It("Should not return deadlines for terminated applications", func() {
+		deadlines, err := util.GetSLADeadlines(newApp(v1beta2.ApplicationStateCompleted))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(deadlines).To(BeEmpty())
+	})
+
+	It("Should reject an invalid duration", func() {
+		app := newApp(v1beta2.ApplicationStateNew)
+		app.Spec.SLA.MaxPendingDuration = ptr.To("soon")
+		_, err := util.GetSLADeadlines(app)
+		Expect(err).To(HaveOccurred())
+	})
+})
+
+var _ = Describe("GetPodGroupPriorityClassName", func() {
+	It("Should prefer the PriorityClass of the batch scheduler options", func() {
+		app := &v1beta2.SparkApplication{}
+		app.Spec.BatchSchedulerOptions = &v1beta2.BatchSchedulerConfiguration{PriorityClassName: ptr.To("batch")}
+		app.Spec.Driver.PriorityClassName = ptr.To("high")
+		Expect(util.GetPodGroupPriorityClassName(app)).To(Equal(ptr.To("batch")))
+	})
+
+	It("Should default to the PriorityClass of the driver", func() {
+		app := &v1beta2.SparkApplication{}
+		app.Spec.Driver.PriorityClassName = ptr.To("high")
+		app.Spec.Executor.PriorityClassName = ptr.To("low")
+		Expect(util.GetPodGroupPriorityClassName(app)).To(Equal(ptr.To("high")))
+	})
+})
+
+var _ = Describe("GetReferencedObjects", func() {
+	It("Should return the required Secrets, ConfigMaps and service accounts once each", func() {
+		app := &v1beta2.SparkApplication{}
+		app.Spec.SparkConfigMap = ptr.To("spark-conf")
+		app.Spec.Volumes = []corev1.Volume{
+			{Name: "creds", VolumeSource: corev1.VolumeSource{Secret: &corev1.SecretVolumeSource{SecretName: "creds"}}},
+			{Name: "extra", VolumeSource: corev1.VolumeSource{ConfigMap: &corev1.ConfigMapVolumeSource{
+				LocalObjectReference: corev1.LocalObjectReference{Name: "extra"},
+				Optional:             ptr.To(true),
+			}}},
+		}
+		app.Spec.Driver.ServiceAccount = ptr.To("spark")
+		app.Spec.Driver.Secrets = []v1beta2.SecretInfo{{Name: "creds", Path: "/etc/creds"}}
+		app.Spec.Executor.EnvFrom = []corev1.EnvFromSource{
+			{ConfigMapRef: &corev1.ConfigMapEnvSource{LocalObjectReference: corev1.LocalObjectReference{Name: "env"}}},
+		}
+		Expect(util.GetReferencedObjects(app)).To(Equal([]corev1.ObjectReference{
+			{Kind: "ConfigMap", Name: "spark-conf"},
+			{Kind: "Secret", Name: "creds"},
+			{Kind: "ServiceAccount", Name: "spark"},
+			{Kind: "ConfigMap", Name: "env"},
+		}))
+	})
+})
+
+var _ = Describe("GetDynamicAllocationTimeoutConf", func() {
+	It("Should translate the timeouts into dynamic allocation properties", func() {
+		dynamicAllocation := &v1beta2.DynamicAllocation{
+			Enabled:                 true,
+			ExecutorIdleTimeout:     ptr.To("2m"),
+			SchedulerBacklogTimeout: ptr.To("500ms"),
+		}
+		Expect(util.GetDynamicAllocationTimeoutConf(dynamicAllocation)).To(Equal(map[string]string{
+			common.SparkDynamicAllocationExecutorIdleTimeout:     "120000ms",
+			common.SparkDynamicAllocationSchedulerBacklogTimeout: "500ms",
+		}))
+	})
+
+	It("Should reject an invalid timeout", func() {
+		dynamicAllocation := &v1beta2.DynamicAllocation{Enabled: true, CachedExecutorIdleTimeout: ptr.To("-1s")}
+		_, err := util.GetDynamicAllocationTimeoutConf(dynamicAllocation)
+		Expect(err).To(HaveOccurred())
+	})
+})
+
+var _ = Describe("GetExecutorLaunchRateLimitConf", func() {
+	It("Should translate the launch rate limit into allocation properties", func() {
+		app := &v1beta2.SparkApplication{}
+		app.Spec.Executor.LaunchRateLimit = &v1beta2.ExecutorLaunchRateLimit{BatchSize: ptr.To[int32](20), BatchDelay: ptr.To("1m")}
+		Expect(util.GetExecutorLaunchRateLimitConf(app)).To(Equal(map[string]string{
+			common.SparkKubernetesAllocationBatchSize:  "20",
+			common.SparkKubernetesAllocationBatchDelay: "60000ms",
+		}))
+	})
+
+	It("Should not override properties set in the Spark configuration", func() {
+		app := &v1beta2.SparkApplication{}
+		app.Spec.SparkConf = map[string]string{common.SparkKubernetesAllocationBatchSize: "5"}
+		app.Spec.Executor.LaunchRateLimit = &v1beta2.ExecutorLaunchRateLimit{BatchSize: ptr.To[int32](20)}
+		Expect(util.GetExecutorLaunchRateLimitConf(app)).To(BeEmpty())
+		Expect(util.GetExecutorAllocationBatchSize(app)).To(Equal(5))
+	})
+
+	It("Should reject an invalid batch delay", func() {
+		app := &v1beta2.SparkApplication{}
+		app.Spec.Executor.LaunchRateLimit = &v1beta2.ExecutorLaunchRateLimit{BatchDelay: ptr.To("0s")}
+		_, err := util.GetExecutorLaunchRateLimitConf(app)
+		Expect(err).To(HaveOccurred())
+	})
+})
+
+var _ = Describe("GetConfPropertiesConfigMapData", func() {
+	It("Should render the properties sorted by key", func() {
+		app := &v1beta2.SparkApplication{}
+		app.Spec.SparkConf = map[string]string{"spark.b": "2", "spark.a": "1"}
+		app.Spec.HadoopConf = map[string]string{"fs.defaultFS": "hdfs://namenode:8020"}
+		Expect(util.GetConfPropertiesConfigMapData(app)).To(Equal(map[string]string{
+			common.SparkConfPropertiesKey:  "spark.a=1\nspark.b=2\n",
+			common.HadoopConfPropertiesKey: "fs.defaultFS=hdfs://namenode:8020\n",
+		}))
+	})
+
+	It("Should escape special characters", func() {
+		app := &v1beta2.SparkApplication{}
+		app.Spec.SparkConf = map[string]string{"key with=sep": " value\nwith\\newline"}
+		Expect(util.GetConfPropertiesConfigMapData(app)[common.SparkConfPropertiesKey]).To(
+			Equal(`key\ with\=sep=\ value\nwith\\newline` + "\n"))
+	})
+
+	It("Should render empty files without properties", func() {
+		Expect(util.GetConfPropertiesConfigMapData(&v1beta2.SparkApplication{})).To(Equal(map[string]string{
+			common.SparkConfPropertiesKey:  "",
+			common.HadoopConfPropertiesKey: "",
+		}))
+	})
+})
+
+var _ = Describe("GetPodGroupExpiry", func() {
+	It("Should return false without TTL or termination time", func() {
+		app := &v1beta2.SparkApplication{}
+		_, ok := util.GetPodGroupExpiry(app)
+		Expect(ok).To(BeFalse())
+
+		app.Spec.BatchSchedulerOptions = &v1beta2.BatchSchedulerConfiguration{TTLSecondsAfterFinished: ptr.To[int32](60)}
+		_, ok = util.GetPodGroupExpiry(app)
+		Expect(ok).To(BeFalse())
+	})
+
+	It("Should add the TTL to the termination time", func() {
+		terminationTime := time.Date(2024, time.March, 5, 7, 30, 0, 0, time.UTC)
+		app := &v1beta2.SparkApplication{}
+		app.Spec.BatchSchedulerOptions = &v1beta2.BatchSchedulerConfiguration{TTLSecondsAfterFinished: ptr.To[int32](60)}
+		app.Status.TerminationTime = metav1.NewTime(terminationTime)
+		expiry, ok := util.GetPodGroupExpiry(app)
+		Expect(ok).To(BeTrue())
+		Expect(expiry).To(Equal(terminationTime.Add(time.Minute)))
+	})
+})
+
+var _ = Describe("GetExecutorQuotaFailurePolicyConf", func() {
+	It("Should schedule tasks right away with the Continue action", func() {
+		app := &v1beta2.SparkApplication{}
+		app.Spec.Executor.QuotaFailurePolicy = &v1beta2.ExecutorQuotaFailurePolicy{Action: v1beta2.ExecutorQuotaFailureActionContinue}
+		Expect(util.GetExecutorQuotaFailurePolicyConf(app)).To(Equal(map[string]string{
+			common.SparkSchedulerMinRegisteredResourcesRatio: "0.0",
+		}))
+	})
+
+	It("Should wait for all executors until the timeout with the Wait action", func() {
+		app := &v1beta2.SparkApplication{}
+		app.Spec.Executor.QuotaFailurePolicy = &v1beta2.ExecutorQuotaFailurePolicy{
+			Action:      v1beta2.ExecutorQuotaFailureActionWait,
+			WaitTimeout: ptr.To("10m"),
+		}
+		Expect(util.GetExecutorQuotaFailurePolicyConf(app)).To(Equal(map[string]string{
+			common.SparkSchedulerMinRegisteredResourcesRatio:       "1.0",
+			common.SparkSchedulerMaxRegisteredResourcesWaitingTime: "600000ms",
+		}))
+	})
+
+	It("Should not override properties set in the Spark configuration", func() {
+		app := &v1beta2.SparkApplication{}
+		app.Spec.SparkConf = map[string]string{common.SparkSchedulerMinRegisteredResourcesRatio: "0.5"}
+		app.Spec.Executor.QuotaFailurePolicy = &v1beta2.ExecutorQuotaFailurePolicy{Action: v1beta2.ExecutorQuotaFailureActionWait}
+		Expect(util.GetExecutorQuotaFailurePolicyConf(app)).To(HaveKey(common.SparkSchedulerMaxRegisteredResourcesWaitingTime))
+		Expect(util.GetExecutorQuotaFailurePolicyConf(app)).NotTo(HaveKey(common.SparkSchedulerMinRegisteredResourcesRatio))
+	})
+
+	It("Should reject a wait timeout with actions other than Wait", func() {
+		app := &v1beta2.SparkApplication{}
+		app.Spec.Executor.QuotaFailurePolicy = &v1beta2.ExecutorQuotaFailurePolicy{
+			Action:      v1beta2.ExecutorQuotaFailureActionFail,
+			WaitTimeout: ptr.To("10m"),
+		}
+		_, err := util.GetExecutorQuotaFailurePolicyConf(app)
+		Expect(err).To(HaveOccurred())
+	})
+})
+
+var _ = Describe("GetRequiredExecutorNumber", func() {
+	It("Should require the minimum number of executors with dynamic allocation", func() {
+		app := &v1beta2.SparkApplication{}
+		app.Spec.Executor.Instances = ptr.To[int32](5)
+		app.Spec.DynamicAllocation = &v1beta2.DynamicAllocation{Enabled: true, MinExecutors: ptr.To[int32](2)}
+		Expect(util.GetRequiredExecutorNumber(app)).To(Equal(int32(2)))
+	})
+
+	It("Should require the number of instances without dynamic allocation", func() {
+		app := &v1beta2.SparkApplication{}
+		app.Spec.Executor.Instances = ptr.To[int32](5)
+		Expect(util.GetRequiredExecutorNumber(app)).To(Equal(int32(5)))
+	})
+})
@@ -17,6 +17,8 @@ limitations under the License.
 package util
 
 import (
+	"time"
+
 	corev1 "k8s.io/api/core/v1"
 
 	"github.com/kubeflow/spark-operator/pkg/common"
@@ -51,3 +53,45 @@ func GetAppName(pod *corev1.Pod) string {
 func GetSparkApplicationID(pod *corev1.Pod) string {
 	return pod.Labels[common.LabelSparkApplicationSelector]
 }
+
+// GetPodCorrelationID returns the correlation ID of the run of the SparkApplication the given pod belongs to.
+func GetPodCorrelationID(pod *corev1.Pod) string {
+	return correlationID(pod.Namespace, GetAppName(pod), pod.Labels[common.LabelSubmissionID])
+}
+
+// IsPodReady returns whether the pod has the Ready condition set to true.
+func IsPodReady(pod *corev1.Pod) bool {
+	for _, condition := range pod.Status.Conditions {
+		if condition.Type == corev1.PodReady {
+			return condition.Status == corev1.ConditionTrue
+		}
+	}
+	return false
+}
+
+// GetPodScheduledTime returns the time the pod was scheduled to its node, or its creation time if it is unknown.
+func GetPodScheduledTime(pod *corev1.Pod) time.Time {
+	for _, condition := range pod.Status.Conditions {
+		if condition.Type == corev1.PodScheduled && condition.Status == corev1.ConditionTrue {
+			return condition.LastTransitionTime.Time
+		}
+	}
+	return pod.CreationTimestamp.Time
+}
+
+// SetContainerResources sets the given resource requests and limits on the container, overriding existing ones of
+// the same resource names.
+func SetContainerResources(container *corev1.Container, requests corev1.ResourceList, limits corev1.ResourceList) {
+	if len(requests) > 0 && container.Resources.Requests == nil {
+		container.Resources.Requests = corev1.ResourceList{}
+	}
+	for name, quantity := range requests {
+		container.Resources.Requests[name] = quantity
+	}
+	if len(limits) > 0 && container.Resources.Limits == nil {
+		container.Resources.Limits = corev1.ResourceList{}
+	}
+	for name, quantity := range limits {
+		container.Resources.Limits[name] = quantity
+	}
+}
@@ -17,6 +17,8 @@ limitations under the License.
 package util_test
 
 import (
+	"time"
+
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
 
@@ -75,6 +77,25 @@ var _ = Describe("IsLaunchedBySparkOperator", func() {
 	})
 })
 
+var _ = Describe("GetPodCorrelationID", func() {
+	Context("Pod with app name and submission ID labels", func() {
+		pod := &corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "test-app-driver",
+				Namespace: "test-namespace",
+				Labels: map[string]string{
+					common.LabelSparkAppName: "test-app",
+					common.LabelSubmissionID: "test-submission-id",
+				},
+			},
+		}
+
+		It("Should return the same correlation ID as the application", func() {
+			Expect(util.GetPodCorrelationID(pod)).To(Equal("test-namespace/test-app/test-submission-id"))
+		})
+	})
+})
+
 var _ = Describe("IsDriverPod", func() {
 	Context("Pod without labels", func() {
 		pod := &corev1.Pod{
@@ -347,3 +368,62 @@ var _ = Describe("GetSparkExecutorID", func() {
 		})
 	})
 })
+
+var _ = Describe("IsPodReady", func() {
+	Context("Pod without ready condition", func() {
+		pod := &corev1.Pod{}
+
+		It("Should return false", func() {
+			Expect(util.IsPodReady(pod)).To(BeFalse())
+		})
+	})
+
+	Context("Pod with ready condition set to true", func() {
+		pod := &corev1.Pod{
+			Status: corev1.PodStatus{
+				Conditions: []corev1.PodCondition{
+					{
+						Type:   corev1.PodReady,
+						Status: corev1.ConditionTrue,
+					},
+				},
+			},
+		}
+
+		It("Should return true", func() {
+			Expect(util.IsPodReady(pod)).To(BeTrue())
+		})
+	})
+})
+
+var _ = Describe("GetPodScheduledTime", func() {
+	created := metav1.NewTime(time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC))
+	scheduled := metav1.NewTime(created.Add(time.Minute))
+
+	Context("Pod without scheduled condition", func() {
+		pod := &corev1.Pod{ObjectMeta: metav1.ObjectMeta{CreationTimestamp: created}}
+
+		It("Should return the creation time", func() {
+			Expect(util.GetPodScheduledTime(pod)).To(Equal(created.Time))
+		})
+	})
+
+	Context("Pod with scheduled condition set to true", func() {
+		pod := &corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{CreationTimestamp: created},
+			Status: corev1.PodStatus{
+				Conditions: []corev1.PodCondition{
+					{
+						Type:               corev1.PodScheduled,
+						Status:             corev1.ConditionTrue,
+						LastTransitionTime: scheduled,
+					},
+				},
+			},
+		}
+
+		It("Should return the time the pod was scheduled", func() {
+			Expect(util.GetPodScheduledTime(pod)).To(Equal(scheduled.Time))
+		})
+	})
+})
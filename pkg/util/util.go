@@ -18,6 +18,7 @@ package util
 
 import (
 	"fmt"
+	"net/url"
 	"os"
 	"path/filepath"
 	"strings"
@@ -45,6 +46,15 @@ func GetMasterURL() (string, error) {
 	return fmt.Sprintf("k8s://https://%s:%s", kubernetesServiceHost, kubernetesServicePort), nil
 }
 
+// correlationID joins the namespace and the name of a SparkApplication and the submission ID of its current run, which
+// is omitted if the application has not been submitted yet.
+func correlationID(namespace string, name string, submissionID string) string {
+	if submissionID == "" {
+		return fmt.Sprintf("%s/%s", namespace, name)
+	}
+	return fmt.Sprintf("%s/%s/%s", namespace, name, submissionID)
+}
+
 // Helper functions to check and remove a string from a slice of strings.
 // ContainsString checks if a given string is present in a slice
 func ContainsString(slice []string, s string) bool {
@@ -118,3 +128,27 @@ func WriteObjectToFile(obj interface{}, filePath string) error {
 
 	return nil
 }
+
+// ParseObjectURI splits the URI of an object in an object store, e.g. `s3://bucket/path/to/object?region=us-west-1`,
+// into the URL of its bucket, e.g. `s3://bucket?region=us-west-1`, and the key of the object, e.g. `path/to/object`.
+func ParseObjectURI(uri string) (string, string, error) {
+	u, err := url.Parse(uri)
+	if err != nil {
+		return "", "", err
+	}
+	if u.Scheme == "" {
+		return "", "", fmt.Errorf("missing scheme in %q", uri)
+	}
+	key := strings.TrimPrefix(u.Path, "/")
+	u.Path = ""
+	u.RawPath = ""
+	if u.Scheme == "file" {
+		// Local buckets are directories, so the bucket is the parent directory of the object.
+		u.Path = filepath.Dir("/" + key)
+		key = filepath.Base(key)
+	}
+	if key == "" || key == "." || key == "/" {
+		return "", "", fmt.Errorf("missing object key in %q", uri)
+	}
+	return u.String(), key, nil
+}
@@ -201,3 +201,17 @@ spec:
 		Expect(os.Remove(file)).NotTo(HaveOccurred())
 	})
 })
+
+var _ = Describe("ParseObjectURI", func() {
+	It("Should split the URI into the bucket URL and the object key", func() {
+		bucketURL, key, err := util.ParseObjectURI("s3://bucket/path/to/table?region=us-west-1")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(bucketURL).To(Equal("s3://bucket?region=us-west-1"))
+		Expect(key).To(Equal("path/to/table"))
+	})
+
+	It("Should reject URIs without an object key", func() {
+		_, _, err := util.ParseObjectURI("gs://bucket")
+		Expect(err).To(HaveOccurred())
+	})
+})